@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/supervisor"
+)
+
+var (
+	flagAnswerOption int
+	flagAnswerText   string
+)
+
+var answerCmd = &cobra.Command{
+	Use:   "answer <target>",
+	Short: "Answer a blocked pane's dialog without the supervisor",
+	Long: `Answer the dialog a pane is blocked on, e.g. from a script or over SSH.
+
+The pane is parsed again first, so the answer is checked against what it
+shows now: --option N selects the dialog's Nth option and fails when there
+is no such option. --text types a free-form answer: into the question's
+"Type your own answer" option when it has one, or into the agent's input
+when the pane is waiting at its prompt.
+
+  pane-patrol answer dev:0.1 --option 2
+  pane-patrol answer dev:0.1 --text "use postgres"
+
+Answers are recorded in the action history (history_file) and, when
+audit_file is set, in the audit log as sent by "answer".`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := args[0]
+		if (flagAnswerOption > 0) == (flagAnswerText != "") {
+			return fmt.Errorf("exactly one of --option and --text is required")
+		}
+
+		m, err := getMultiplexer()
+		if err != nil {
+			return err
+		}
+		panes, err := m.ListPanes(cmd.Context(), "")
+		if err != nil {
+			return fmt.Errorf("failed to list panes: %w", err)
+		}
+		var pane model.Pane
+		for _, p := range panes {
+			if p.Target == target {
+				pane = p
+				break
+			}
+		}
+		if pane.Target == "" {
+			return fmt.Errorf("pane %q not found", target)
+		}
+
+		cfg, cfgErr := loadConfig()
+		verdict, err := evaluatePane(cmd.Context(), m, newRegistry(cfg, cfgErr), pane)
+		if err != nil {
+			return fmt.Errorf("%s: %w", target, err)
+		}
+		answer, err := supervisor.ResolveAnswer(*verdict, flagAnswerOption, flagAnswerText)
+		if err != nil {
+			return err
+		}
+
+		key, err := loadStateKey()
+		if err != nil {
+			return err
+		}
+		var history *supervisor.History
+		var audit *supervisor.AuditLog
+		if cfgErr == nil {
+			history = newHistory(cfg, key)
+			if audit, err = openAuditLog(cfg); err != nil {
+				return err
+			}
+		}
+		if err := supervisor.AnswerPane(*verdict, answer, history, audit, "answer"); err != nil {
+			return err
+		}
+		if answer.Action != nil {
+			fmt.Fprintf(os.Stderr, "sent '%s' (%s) to %s\n", answer.Action.Keys, answer.Action.Label, target)
+		}
+		if answer.Text != "" {
+			fmt.Fprintf(os.Stderr, "sent %d chars to %s\n", len([]rune(answer.Text)), target)
+		}
+		return nil
+	},
+}
+
+func init() {
+	answerCmd.Flags().IntVar(&flagAnswerOption, "option", 0, "select the dialog's option with this number (1-based)")
+	answerCmd.Flags().StringVar(&flagAnswerText, "text", "", "type this free-form answer")
+	rootCmd.AddCommand(answerCmd)
+}
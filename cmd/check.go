@@ -8,7 +8,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/timvw/pane-patrol/internal/model"
-	"github.com/timvw/pane-patrol/internal/parser"
 )
 
 var checkCmd = &cobra.Command{
@@ -55,17 +54,20 @@ parsers. Unrecognized panes are reported as unknown.`,
 		content := model.BuildProcessHeader(pane) + capture
 
 		// Try deterministic parsers (instant, free).
-		registry := parser.NewRegistry()
+		registry := newRegistry(loadConfig())
 		var verdict model.Verdict
 		if parsed := registry.Parse(capture, pane.ProcessTree); parsed != nil {
 			verdict = model.BaseVerdict(pane, start)
 			verdict.Agent = parsed.Agent
+			verdict.Model = parsed.Model
+			verdict.Confidence = parsed.Confidence
 			verdict.Blocked = parsed.Blocked
 			verdict.Reason = parsed.Reason
 			verdict.WaitingFor = parsed.WaitingFor
 			verdict.Reasoning = parsed.Reasoning
 			verdict.Actions = parsed.Actions
 			verdict.Recommended = parsed.Recommended
+			verdict.Dialog = parsed.Dialog
 			verdict.EvalSource = model.EvalSourceParser
 		} else {
 			// No parser matched — return unknown verdict.
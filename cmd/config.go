@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Check the configuration",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Report mistakes in the config file",
+	Long: `Check the config file the supervisor would load (with the selected
+profile) and report, with line and column where known:
+
+  - keys pane-patrol does not know, which it would otherwise ignore
+  - patterns that are not valid regular expressions
+  - settings that contradict each other or have no effect
+  - values the supervisor would refuse to start with
+
+Exits non-zero when anything is reported. The supervisor prints the same
+findings as warnings when it starts.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, problems := config.LintFile()
+		if path == "" {
+			fmt.Println("no config file, using the defaults (pane-patrol init writes one)")
+			return nil
+		}
+		var lines []string
+		for _, p := range problems {
+			lines = append(lines, path+":"+p.String())
+		}
+		// Custom parsers and rules are compiled once the file lints clean,
+		// so a bad pattern is not reported twice.
+		if cfg, err := loadConfig(); err != nil {
+			lines = append(lines, path+": "+err.Error())
+		} else if len(problems) == 0 {
+			if _, err := parser.NewCustomParsers(cfg.Parsers); err != nil {
+				lines = append(lines, path+": "+err.Error())
+			}
+			if _, err := parser.NewRiskRules(cfg.RiskRules); err != nil {
+				lines = append(lines, path+": "+err.Error())
+			}
+			if _, err := parser.NewIdleActions(cfg.IdleActions); err != nil {
+				lines = append(lines, path+": "+err.Error())
+			}
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		if len(lines) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d problem(s) in %s", len(lines), path)
+		}
+		fmt.Printf("%s: ok\n", path)
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/supervisor"
+)
+
+var controlCmd = &cobra.Command{
+	Use:   "control <command> [args]",
+	Short: "Send a command to the running supervisor",
+	Long: `Send a command to the supervisor TUI over its control socket
+(control_socket), e.g. from a tmux key binding or a script:
+
+  pane-patrol control rescan
+  pane-patrol control jump dev:0.1
+  pane-patrol control toggle-autonudge
+  pane-patrol control toggle-dnd
+  pane-patrol control reload
+
+The supervisor's answer is printed; a command it rejects exits non-zero.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := ""
+		if cfg, err := loadConfig(); err == nil {
+			path = cfg.ControlSocket
+		}
+		control := supervisor.NewControlSocket(path)
+		if control == nil {
+			return fmt.Errorf("the control socket is off (control_socket)")
+		}
+		answer, err := supervisor.SendControl(control.Path, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		if msg, ok := strings.CutPrefix(answer, "ok: "); ok {
+			fmt.Println(msg)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(controlCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/supervisor"
+)
+
+var decryptCmd = &cobra.Command{
+	Use:   "decrypt <file>",
+	Short: "Print an encrypted history or state file in plain text",
+	Long: `Print a history, labels, remembered answers, or verdict cache file
+encrypted with the state key (PANE_PATROL_STATE_KEY or state_key_command)
+in plain text, e.g. to read or grep the history:
+
+  pane-patrol decrypt ~/.config/pane-patrol/history.jsonl | jq .
+
+Lines written before encryption was turned on are printed as they are.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := loadStateKey()
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return fmt.Errorf("no state key: set PANE_PATROL_STATE_KEY or state_key_command")
+		}
+		data, err := os.ReadFile(expandHome(args[0]))
+		if err != nil {
+			return err
+		}
+		plain, err := key.Open(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		_, err = os.Stdout.Write(plain)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(decryptCmd)
+}
+
+// loadStateKey returns the key encrypting the history and state files,
+// derived from the secret in PANE_PATROL_STATE_KEY or printed by
+// state_key_command and the stored salt. It returns nil when neither is
+// set, so the files are written in plain text; a key that cannot be
+// fetched is an error rather than a reason to do so.
+func loadStateKey() (*supervisor.StateKey, error) {
+	secret := os.Getenv("PANE_PATROL_STATE_KEY")
+	if secret == "" {
+		cfg, err := loadConfig()
+		if err != nil || cfg.StateKeyCommand == "" {
+			// Config errors are reported by the commands that need it.
+			return nil, nil
+		}
+		out, err := exec.Command("sh", "-c", cfg.StateKeyCommand).Output()
+		if err != nil {
+			return nil, fmt.Errorf("state_key_command: %w", err)
+		}
+		secret = strings.TrimSpace(string(out))
+	}
+	salt, err := supervisor.LoadStateSalt(supervisor.DefaultStateSaltPath())
+	if err != nil {
+		return nil, err
+	}
+	return supervisor.NewStateKey(secret, salt)
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/logging"
+	"github.com/timvw/pane-patrol/internal/mux"
+	"github.com/timvw/pane-patrol/internal/supervisor"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the environment is set up for pane-patrol",
+	Long: `Check the environment pane-patrol runs in and print what to fix:
+
+  - tmux is installed, recent enough, and a server is running
+  - the config file parses
+  - the history, answers, labels, cache, and log files are writable, and
+    the ones holding pane content are only readable by you
+  - the terminal supports the supervisor TUI (TERM, colors, UTF-8)
+
+pane-patrol reads agents with deterministic parsers, so there are no LLM
+provider credentials to check.
+
+Exits with status 1 when a check fails; warnings do not fail.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d := &doctor{out: cmd.OutOrStdout()}
+		cfg := d.checkConfig()
+		d.checkTmux(cmd)
+		d.ok("LLM credentials", "none needed: agents are read with deterministic parsers")
+		d.checkFiles(cfg)
+		d.checkTerminal(cfg)
+
+		fmt.Fprintln(d.out)
+		switch {
+		case d.failed > 0:
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d checks failed, %d warnings", d.failed, d.warned)
+		case d.warned > 0:
+			fmt.Fprintf(d.out, "All checks passed, %d warnings.\n", d.warned)
+		default:
+			fmt.Fprintln(d.out, "All checks passed.")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// doctor prints check results, each with a hint on how to fix a problem.
+type doctor struct {
+	out    io.Writer
+	failed int
+	warned int
+}
+
+func (d *doctor) ok(name, detail string) {
+	d.print("ok", name, detail, "")
+}
+
+func (d *doctor) warn(name, detail, hint string) {
+	d.warned++
+	d.print("warn", name, detail, hint)
+}
+
+func (d *doctor) fail(name, detail, hint string) {
+	d.failed++
+	d.print("FAIL", name, detail, hint)
+}
+
+func (d *doctor) print(status, name, detail, hint string) {
+	fmt.Fprintf(d.out, "%-4s  %-16s %s\n", status, name, detail)
+	if hint != "" {
+		fmt.Fprintf(d.out, "      %-16s -> %s\n", "", hint)
+	}
+}
+
+// checkConfig loads the config. A config that fails to load is reported
+// and the defaults are returned, so the other checks still run.
+func (d *doctor) checkConfig() *config.Config {
+	cfg, err := loadConfig()
+	switch {
+	case err != nil:
+		d.fail("config", err.Error(), "fix the file, or replace it with pane-patrol init --force")
+		return &config.Config{}
+	case cfg.ConfigFile == "":
+		d.ok("config", "no config file, using the defaults (pane-patrol init writes one)")
+	case len(cfg.Problems) > 0:
+		d.warn("config", fmt.Sprintf("%s: %d problem(s), first %s", cfg.ConfigFile, len(cfg.Problems), cfg.Problems[0]),
+			"see pane-patrol config validate")
+	default:
+		d.ok("config", cfg.ConfigFile)
+	}
+	return cfg
+}
+
+// checkTmux checks the tmux binary, its version, and the server.
+func (d *doctor) checkTmux(cmd *cobra.Command) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		d.fail("tmux", "not found in PATH", "install tmux 3.2 or newer")
+		return
+	}
+	t := mux.NewTmux()
+	f := t.Features(cmd.Context())
+	switch {
+	case !f.Known:
+		d.warn("tmux", "version unknown (tmux -V failed)", "check that tmux -V runs")
+	case !f.Supported():
+		d.warn("tmux", fmt.Sprintf("%s is older than %d.%d: scanning works, popups do not", f.Version, mux.MinTmuxMajor, mux.MinTmuxMinor),
+			"upgrade tmux")
+	default:
+		d.ok("tmux", f.Version.String())
+	}
+
+	panes, err := t.ListPanes(cmd.Context(), "")
+	switch {
+	case errors.Is(err, mux.ErrNoServer):
+		d.fail("tmux server", "not running", "start a session with: tmux new -s work")
+	case err != nil:
+		d.fail("tmux server", err.Error(), "check that tmux list-panes -a works")
+	default:
+		sessions := make(map[string]bool)
+		for _, p := range panes {
+			sessions[p.Session] = true
+		}
+		d.ok("tmux server", fmt.Sprintf("%d panes in %d sessions", len(panes), len(sessions)))
+	}
+}
+
+// checkFiles checks that the files pane-patrol writes can be written.
+// Files that record pane content or keys sent must not be readable by
+// others.
+func (d *doctor) checkFiles(cfg *config.Config) {
+	files := []struct {
+		name    string
+		path    string
+		private bool
+	}{
+		{"history file", orDefault(cfg.HistoryFile, supervisor.DefaultHistoryPath()), true},
+		{"answers file", orDefault(cfg.AnswersFile, supervisor.DefaultAnswersPath()), true},
+		{"labels file", orDefault(cfg.LabelsFile, supervisor.DefaultLabelsPath()), false},
+		{"cache file", cfg.CacheFile, true},
+		{"audit file", cfg.AuditFile, true},
+		{"log file", orDefault(cfg.LogFile, logging.DefaultPath()), true},
+	}
+	for _, f := range files {
+		switch f.path {
+		case "":
+			continue
+		case "off":
+			d.ok(f.name, "off")
+			continue
+		}
+		d.checkFile(f.name, expandHome(f.path), f.private)
+	}
+}
+
+// checkFile checks that path can be appended to, or created if missing.
+func (d *doctor) checkFile(name, path string, private bool) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		dir := existingParent(filepath.Dir(path))
+		probe, err := os.CreateTemp(dir, ".pane-patrol-doctor-*")
+		if err != nil {
+			d.fail(name, fmt.Sprintf("%s cannot be created: %v", path, err), "make "+dir+" writable, or set another path in the config")
+			return
+		}
+		probe.Close()
+		os.Remove(probe.Name())
+		d.ok(name, path+" (created on first use)")
+		return
+	}
+	if err != nil {
+		d.fail(name, err.Error(), "")
+		return
+	}
+	if info.IsDir() {
+		d.fail(name, path+" is a directory", "set a file path in the config")
+		return
+	}
+	w, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		d.fail(name, fmt.Sprintf("%s is not writable: %v", path, err), "check its owner and mode: ls -l "+path)
+		return
+	}
+	w.Close()
+	if perm := info.Mode().Perm(); private && perm&0o077 != 0 {
+		d.warn(name, fmt.Sprintf("%s is readable by others (mode %04o)", path, perm), "chmod 600 "+path)
+		return
+	}
+	d.ok(name, path)
+}
+
+// checkTerminal checks what the supervisor TUI needs from the terminal.
+func (d *doctor) checkTerminal(cfg *config.Config) {
+	switch term := os.Getenv("TERM"); term {
+	case "", "dumb":
+		d.warn("terminal", fmt.Sprintf("TERM=%q cannot show the TUI", term), "run in a terminal emulator, or use pane-patrol supervisor --plain")
+	default:
+		d.ok("terminal", "TERM="+term)
+	}
+
+	// Detect from the environment even when the output is piped.
+	profile := termenv.NewOutput(os.Stdout, termenv.WithUnsafe()).EnvColorProfile()
+	if p, ok := supervisor.ParseColorProfile(cfg.ColorProfile); ok {
+		profile = p
+	}
+	switch profile {
+	case termenv.TrueColor:
+		d.ok("colors", "truecolor")
+	case termenv.ANSI256:
+		d.ok("colors", "256 colors")
+	case termenv.ANSI:
+		d.ok("colors", "16 colors (themes use the nearest colors)")
+	default:
+		d.warn("colors", "none detected", "set COLORTERM=truecolor, or color_profile in the config")
+	}
+
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	if l := strings.ToLower(locale); strings.Contains(l, "utf-8") || strings.Contains(l, "utf8") {
+		d.ok("UTF-8", locale)
+	} else {
+		d.warn("UTF-8", fmt.Sprintf("locale %q may not show symbols and box drawing", locale),
+			"set LANG to a UTF-8 locale, or use --accessible for ASCII output")
+	}
+}
+
+// orDefault returns path, or def if path is empty.
+func orDefault(path, def string) string {
+	if path == "" {
+		return def
+	}
+	return path
+}
+
+// existingParent returns dir or its nearest ancestor that exists, where
+// the missing directories would be created.
+func existingParent(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
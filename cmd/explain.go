@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+var flagExplainJSON bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <target>",
+	Short: "Show how the parsers reached a pane's verdict",
+	Long: `Capture a pane, run every parser, and print a trace for debugging and
+bug reports: the process tree, the bottom lines the parsers examine, what
+each parser matched (identification evidence and confidence), why the
+state was chosen, and the final actions.
+
+Attach the output (it contains the pane's visible text) when reporting a
+misclassified pane.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := args[0]
+
+		m, err := getMultiplexer()
+		if err != nil {
+			return err
+		}
+
+		panes, err := m.ListPanes(cmd.Context(), "")
+		if err != nil {
+			return fmt.Errorf("failed to list panes: %w", err)
+		}
+		var pane model.Pane
+		for _, p := range panes {
+			if p.Target == target {
+				pane = p
+				break
+			}
+		}
+		if pane.Target == "" {
+			pane.Target = target
+		}
+
+		capture, err := m.CapturePane(cmd.Context(), target)
+		if err != nil {
+			return fmt.Errorf("failed to capture pane %q: %w", target, err)
+		}
+
+		registry := newRegistry(loadConfig())
+		ex := explanation{
+			Target:        pane.Target,
+			Command:       pane.Command,
+			ProcessTree:   pane.ProcessTree,
+			ExaminedLines: parser.ExaminedLines(capture),
+			Selected:      registry.Parse(capture, pane.ProcessTree),
+		}
+		for _, entry := range registry.Trace(capture, pane.ProcessTree) {
+			ex.Parsers = append(ex.Parsers, parserTrace{Parser: entry.Parser, Result: entry.Result})
+		}
+
+		if flagExplainJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(ex)
+		}
+		printExplanation(os.Stdout, ex)
+		return nil
+	},
+}
+
+// explanation is the explain command's report.
+type explanation struct {
+	Target        string         `json:"target"`
+	Command       string         `json:"command"`
+	ProcessTree   []string       `json:"process_tree"`
+	ExaminedLines []string       `json:"examined_lines"`
+	Parsers       []parserTrace  `json:"parsers"`
+	Selected      *parser.Result `json:"selected"`
+}
+
+// parserTrace is one parser's outcome; Result is nil when it did not match.
+type parserTrace struct {
+	Parser string         `json:"parser"`
+	Result *parser.Result `json:"result"`
+}
+
+func printExplanation(w io.Writer, ex explanation) {
+	fmt.Fprintf(w, "target:        %s\n", ex.Target)
+	fmt.Fprintf(w, "command:       %s\n", ex.Command)
+	if len(ex.ProcessTree) == 0 {
+		fmt.Fprintf(w, "process tree:  (none)\n")
+	} else {
+		fmt.Fprintf(w, "process tree:  %s\n", strings.Join(ex.ProcessTree, " → "))
+	}
+
+	fmt.Fprintf(w, "\nexamined lines (bottom %d non-empty):\n", len(ex.ExaminedLines))
+	for _, line := range ex.ExaminedLines {
+		fmt.Fprintf(w, "  │ %s\n", line)
+	}
+
+	fmt.Fprintf(w, "\nparsers (registration order):\n")
+	for _, pt := range ex.Parsers {
+		if pt.Result == nil {
+			fmt.Fprintf(w, "  %-14s no match\n", pt.Parser)
+			continue
+		}
+		r := pt.Result
+		state := "active"
+		if r.Blocked {
+			state = "blocked"
+		}
+		fmt.Fprintf(w, "  %-14s matched, confidence %.1f (%s)\n", pt.Parser, r.Confidence, r.Evidence)
+		fmt.Fprintf(w, "  %-14s state: %s — %s\n", "", state, r.Reason)
+		fmt.Fprintf(w, "  %-14s why: %s\n", "", r.Reasoning)
+	}
+
+	fmt.Fprintln(w)
+	if ex.Selected == nil {
+		fmt.Fprintln(w, "selected: none (pane reported as unknown)")
+		return
+	}
+	s := ex.Selected
+	fmt.Fprintf(w, "selected: %s (highest confidence; ties go to the earlier parser)\n", s.Agent)
+	if s.Model != "" {
+		fmt.Fprintf(w, "  model:       %s\n", s.Model)
+	}
+	if s.WaitingFor != "" {
+		fmt.Fprintf(w, "  waiting for:\n")
+		for _, line := range strings.Split(s.WaitingFor, "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	if len(s.Actions) > 0 {
+		fmt.Fprintf(w, "  actions:\n")
+		for i, a := range s.Actions {
+			marker := " "
+			if i == s.Recommended {
+				marker = "*"
+			}
+			mode := "literal"
+			if a.Raw {
+				mode = "raw"
+			}
+			fmt.Fprintf(w, "   %s %d. [%s] %q %s (%s)\n", marker, i+1, a.Risk, a.Keys, a.Label, mode)
+		}
+	}
+}
+
+func init() {
+	explainCmd.Flags().BoolVar(&flagExplainJSON, "json", false, "output the trace as JSON")
+	rootCmd.AddCommand(explainCmd)
+}
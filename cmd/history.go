@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/config"
+)
+
+var (
+	flagPruneMaxAge  string
+	flagPruneMaxSize string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Maintain the action history",
+	Long: `The supervisor and pane-patrol answer append every action sent and every
+pane state transition to the history (history_file). It is trimmed to
+history_max_size and history_max_age as entries are written.`,
+}
+
+var historyPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Drop old history entries now",
+	Long: `Apply the history retention now: drop entries older than
+history_max_age and, while the file is larger than history_max_size, the
+oldest entries until it is down to three quarters of it. --max-age and
+--max-size override the config, e.g. for a one-off cleanup:
+
+  pane-patrol history prune --max-age 30d`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		key, err := loadStateKey()
+		if err != nil {
+			return err
+		}
+		history := newHistory(cfg, key)
+		if history == nil {
+			return errors.New("the history is off (history_file)")
+		}
+		if cmd.Flags().Changed("max-age") {
+			if history.MaxAge, err = config.ParseAge(flagPruneMaxAge); err != nil {
+				return fmt.Errorf("invalid --max-age %q: %w", flagPruneMaxAge, err)
+			}
+		}
+		if cmd.Flags().Changed("max-size") {
+			if history.MaxSize, err = config.ParseSize(flagPruneMaxSize); err != nil {
+				return fmt.Errorf("invalid --max-size %q: %w", flagPruneMaxSize, err)
+			}
+		}
+		if history.MaxAge == 0 && history.MaxSize == 0 {
+			return errors.New("no retention: set --max-age or --max-size, or history_max_age or history_max_size")
+		}
+		removed, kept, err := history.Prune(time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("removed %d entries, kept %d\n", removed, kept)
+		return nil
+	},
+}
+
+func init() {
+	historyPruneCmd.Flags().StringVar(&flagPruneMaxAge, "max-age", "", `drop entries older than this, e.g. "30d" or "72h"`)
+	historyPruneCmd.Flags().StringVar(&flagPruneMaxSize, "max-size", "", `trim the oldest entries while the file is larger, e.g. "5MB"`)
+	historyCmd.AddCommand(historyPruneCmd)
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+var (
+	flagInitOutput string
+	flagInitForce  bool
+	flagInitYes    bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a starter config file, asking a few questions",
+	Long: `Set up pane-patrol: check that tmux is installed, list the running
+sessions, ask which to exclude, whether to auto-nudge blocked panes, and
+where to send telemetry, and write a commented starter config file.
+
+pane-patrol reads agent screens with deterministic parsers, so it needs no
+LLM API key. The only credentials it uses are optional OTEL headers, e.g.
+for Langfuse.
+
+The file is written to ~/.config/pane-patrol/config.yaml unless --output is
+given; an existing file is only replaced with --force.
+
+Examples:
+  pane-patrol init
+  pane-patrol init --output .pane-patrol.yaml
+  pane-patrol init --yes      # accept all defaults`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := flagInitOutput
+		if path == "" {
+			var err error
+			if path, err = config.UserConfigPath(); err != nil {
+				return fmt.Errorf("cannot find home directory: %w (use --output)", err)
+			}
+		}
+		if _, err := os.Stat(path); err == nil && !flagInitForce {
+			return fmt.Errorf("%s already exists (use --force to replace it)", path)
+		}
+
+		out := cmd.OutOrStdout()
+		p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: out, defaults: flagInitYes}
+		var s config.Starter
+
+		sessions := detectSessions(cmd, out)
+		if len(sessions) > 0 {
+			s.ExcludeSessions = pickSessions(sessions, p.ask("Sessions to exclude (numbers or names, comma-separated; name* for a prefix)", ""))
+		}
+
+		fmt.Fprintln(out, "\nAgents are read with deterministic parsers: no LLM API key needed.")
+		s.OTELEndpoint = p.ask("OTEL endpoint for scan traces, e.g. Langfuse (empty for none)", "")
+		if s.OTELEndpoint != "" {
+			s.OTELHeaders = p.ask("OTEL headers, e.g. Authorization=Basic <credentials> (empty for none)", "")
+		}
+
+		fmt.Fprintln(out)
+		s.AutoNudge = p.confirm("Send the recommended action to blocked panes automatically?", false)
+		if s.AutoNudge {
+			for {
+				s.AutoNudgeMaxRisk = strings.ToLower(p.ask("Highest risk to auto-nudge: low, medium, or high", "low"))
+				if s.AutoNudgeMaxRisk == "low" || s.AutoNudgeMaxRisk == "medium" || s.AutoNudgeMaxRisk == "high" {
+					break
+				}
+				fmt.Fprintln(out, "  answer low, medium, or high")
+			}
+		}
+		s.ShowWaitingFor = p.confirm("Show what blocked panes are waiting for under their rows?", false)
+
+		if err := config.WriteStarter(path, s, flagInitForce); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "\nWrote %s. Run pane-patrol to start the supervisor.\n", path)
+		return nil
+	},
+}
+
+func init() {
+	initCmd.Flags().StringVarP(&flagInitOutput, "output", "o", "", "config file to write (default: ~/.config/pane-patrol/config.yaml)")
+	initCmd.Flags().BoolVar(&flagInitForce, "force", false, "replace an existing config file")
+	initCmd.Flags().BoolVarP(&flagInitYes, "yes", "y", false, "accept all defaults without asking")
+	rootCmd.AddCommand(initCmd)
+}
+
+// initSession is a tmux session found by init, with its pane commands.
+type initSession struct {
+	name     string
+	panes    int
+	commands []string
+}
+
+// detectSessions reports the tmux version and lists the running sessions.
+// Problems are reported, not returned: the config can be written without
+// tmux running.
+func detectSessions(cmd *cobra.Command, out io.Writer) []initSession {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		fmt.Fprintln(out, "tmux: not found in PATH. pane-patrol supervises tmux panes; install tmux to use it.")
+		return nil
+	}
+	t := mux.NewTmux()
+	f := t.Features(cmd.Context())
+	switch {
+	case !f.Known:
+		fmt.Fprintln(out, "tmux: found (version unknown)")
+	case !f.Supported():
+		fmt.Fprintf(out, "tmux: %s (older than %d.%d; scanning works, popups do not)\n", f.Version, mux.MinTmuxMajor, mux.MinTmuxMinor)
+	default:
+		fmt.Fprintf(out, "tmux: %s\n", f.Version)
+	}
+
+	panes, err := t.ListPanes(cmd.Context(), "")
+	if err != nil {
+		fmt.Fprintln(out, "No tmux sessions running; exclusions can be added to the config later.")
+		return nil
+	}
+	index := make(map[string]int)
+	var sessions []initSession
+	for _, pane := range panes {
+		i, ok := index[pane.Session]
+		if !ok {
+			i = len(sessions)
+			index[pane.Session] = i
+			sessions = append(sessions, initSession{name: pane.Session})
+		}
+		s := &sessions[i]
+		s.panes++
+		if !slices.Contains(s.commands, pane.Command) {
+			s.commands = append(s.commands, pane.Command)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].name < sessions[j].name })
+
+	fmt.Fprintln(out, "\nSessions:")
+	for i, s := range sessions {
+		fmt.Fprintf(out, "  %d) %s (%d panes: %s)\n", i+1, s.name, s.panes, strings.Join(s.commands, ", "))
+	}
+	return sessions
+}
+
+// pickSessions turns an answer like "1, 3, scratch-*" into session names:
+// numbers select listed sessions, anything else is taken as a name.
+func pickSessions(sessions []initSession, answer string) []string {
+	var names []string
+	for _, field := range strings.Split(answer, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(field); err == nil && n >= 1 && n <= len(sessions) {
+			field = sessions[n-1].name
+		}
+		if !slices.Contains(names, field) {
+			names = append(names, field)
+		}
+	}
+	return names
+}
+
+// prompter asks questions on a terminal. With defaults set, or once the
+// input ends, every question takes its default answer.
+type prompter struct {
+	in       *bufio.Reader
+	out      io.Writer
+	defaults bool
+}
+
+// ask prints question and returns the trimmed answer, or def if it is empty.
+func (p *prompter) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	if p.defaults {
+		fmt.Fprintln(p.out)
+		return def
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil {
+		p.defaults = true
+		if line == "" {
+			fmt.Fprintln(p.out)
+		}
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// confirm asks a yes/no question.
+func (p *prompter) confirm(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		switch strings.ToLower(p.ask(question+" ("+hint+")", "")) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+		fmt.Fprintln(p.out, "  answer y or n")
+	}
+}
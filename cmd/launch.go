@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/launch"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+var flagLaunchNoSupervise bool
+
+var launchCmd = &cobra.Command{
+	Use:   "launch <template.yaml>",
+	Short: "Create tmux sessions for an agent fleet from a template, then supervise them",
+	Long: `Create the tmux sessions, windows, and panes described in a template file,
+start the agent command in each pane, and then open the supervisor.
+
+Template format:
+
+  sessions:
+    - name: api-refactor
+      dir: ~/src/api            # default working dir for the session
+      windows:
+        - name: agents
+          layout: tiled         # even-horizontal, even-vertical, main-horizontal, main-vertical, tiled
+          panes:
+            - command: claude
+            - command: codex --model gpt-5-codex
+              dir: ../api-worktree
+    - name: docs
+      panes:                    # shorthand for a single window
+        - command: opencode
+
+Relative dirs resolve against the template's directory. launch refuses to
+run if any of the sessions already exists.
+
+Templates in template_dir (default ~/.config/pane-patrol/templates) can also
+be launched from the supervisor with L.
+
+Examples:
+  pane-patrol launch fleet.yaml
+  pane-patrol launch fleet.yaml --no-supervise`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := exec.LookPath("tmux"); err != nil {
+			return fmt.Errorf("tmux not found in PATH: launch requires tmux")
+		}
+		tpl, err := launch.LoadTemplate(args[0])
+		if err != nil {
+			return err
+		}
+
+		launched, err := launch.Launch(cmd.Context(), mux.NewTmux(), tpl)
+		for _, l := range launched {
+			fmt.Fprintf(os.Stderr, "launch: started %q in %s\n", l.Command, l.Target)
+		}
+		if err != nil {
+			return err
+		}
+
+		if cfg, err := loadConfig(); err == nil {
+			for _, s := range tpl.Sessions {
+				if warning := scopeWarning(s.Name, cfg); warning != "" {
+					fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+				}
+			}
+		}
+
+		if flagLaunchNoSupervise {
+			return nil
+		}
+
+		// Hand over to the supervisor in a fresh process: it may re-exec
+		// itself inside tmux, which must not re-run this launch.
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("resolve executable to start supervisor: %w", err)
+		}
+		return syscall.Exec(exe, []string{exe, "supervisor"}, os.Environ())
+	},
+}
+
+func init() {
+	launchCmd.Flags().BoolVar(&flagLaunchNoSupervise, "no-supervise", false, "create the sessions without opening the supervisor")
+	rootCmd.AddCommand(launchCmd)
+}
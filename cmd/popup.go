@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+	"github.com/timvw/pane-patrol/internal/parser"
+	"github.com/timvw/pane-patrol/internal/supervisor"
+)
+
+// popupEnv marks the process running inside the popup, so it shows the
+// quick view instead of opening another popup.
+const popupEnv = "PANE_PATROL_POPUP"
+
+var popupCmd = &cobra.Command{
+	Use:   "popup",
+	Short: "Show blocked panes in a tmux popup to answer or jump to one",
+	Long: `Open a tmux popup listing the blocked panes, scanned once. Pick a pane
+and press Enter to jump to it, y to send its recommended action, or 1-9 to
+send another of its actions; the popup then closes. Bind it to a key to
+supervise without a dedicated pane:
+
+  bind-key b run-shell -b "pane-patrol popup"
+
+Actions sent are recorded in the action history (history_file) and, when
+audit_file is set, in the audit log as sent by "popup". With
+confirm_high_risk, a high-risk action is only sent once "yes" or a reason
+is typed, which is recorded with it. Shell prompts are not listed. Needs
+tmux 3.2 or newer.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if os.Getenv(popupEnv) == "" {
+			return openPopup(cmd)
+		}
+
+		key, err := loadStateKey()
+		if err != nil {
+			return err
+		}
+		cfg, cfgErr := loadConfig()
+		parallel := 10
+		if cfgErr == nil && cfg.Parallel > 0 {
+			parallel = cfg.Parallel
+		}
+		verdicts, err := scanAllPanes(cmd.Context(), "", parallel)
+		if err != nil {
+			return err
+		}
+		var blocked []model.Verdict
+		for _, v := range verdicts {
+			if parser.IsAgentBlock(v) {
+				blocked = append(blocked, v)
+			}
+		}
+
+		popup := &supervisor.Popup{Verdicts: blocked}
+		if cfgErr == nil {
+			popup.History = newHistory(cfg, key)
+			if popup.Audit, err = openAuditLog(cfg); err != nil {
+				return err
+			}
+			popup.ConfirmHighRisk = cfg.ConfirmHighRisk
+			labelsPath := cfg.LabelsFile
+			if labelsPath == "" {
+				labelsPath = supervisor.DefaultLabelsPath()
+			}
+			// Labels only make names friendlier; show targets without them.
+			popup.Labels, _ = supervisor.LoadLabels(labelsPath, key)
+		}
+		return popup.Run()
+	},
+}
+
+// openPopup runs this command again in a tmux popup on the current client.
+func openPopup(cmd *cobra.Command) error {
+	if os.Getenv("TMUX") == "" {
+		return fmt.Errorf("popup needs to run inside tmux, e.g. from a key binding")
+	}
+	t := mux.NewTmux()
+	f := t.Features(cmd.Context())
+	if !f.Popup {
+		return fmt.Errorf("tmux %s has no popups (needs %d.%d or newer)", f.Version, mux.MinTmuxMajor, mux.MinTmuxMinor)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("resolve executable for the popup: %w", err)
+	}
+	command := popupEnv + "=1 " + shellQuote(exe) + " popup"
+	if out, err := exec.Command("tmux", supervisor.PopupArgs(f, command)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux display-popup: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// shellQuote quotes s as one word for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func init() {
+	rootCmd.AddCommand(popupCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+var (
+	flagRecordDir  string
+	flagRecordName string
+	flagRecordNote string
+)
+
+var recordCmd = &cobra.Command{
+	Use:   "record <target>",
+	Short: "Record a pane capture as a parser regression fixture",
+	Long: `Snapshot a pane's raw content and process tree to a fixture file.
+
+The fixture's "expect" block is pre-filled with what the builtin parsers
+currently report. When recording a parser bug, edit "expect" to the
+correct verdict and commit the fixture: the parser tests replay every
+fixture in internal/parser/testdata/fixtures and fail until the parser
+is fixed.
+
+Examples:
+  pane-patrol record work:0.1 --dir internal/parser/testdata/fixtures
+  pane-patrol record work:0.1 --name claude-edit-approval --note "edit dialog misread as idle"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := args[0]
+
+		m, err := getMultiplexer()
+		if err != nil {
+			return err
+		}
+
+		panes, err := m.ListPanes(cmd.Context(), "")
+		if err != nil {
+			return fmt.Errorf("failed to list panes: %w", err)
+		}
+		var pane model.Pane
+		for _, p := range panes {
+			if p.Target == target {
+				pane = p
+				break
+			}
+		}
+		if pane.Target == "" {
+			return fmt.Errorf("pane %q not found", target)
+		}
+
+		content, err := m.CapturePane(cmd.Context(), target)
+		if err != nil {
+			return fmt.Errorf("failed to capture pane %q: %w", target, err)
+		}
+
+		now := time.Now()
+		name := flagRecordName
+		if name == "" {
+			name = sanitizeSessionName(fmt.Sprintf("%s-%d-%d-%s",
+				pane.Session, pane.Window, pane.Pane, now.UTC().Format("20060102T150405")))
+		}
+
+		f := &parser.Fixture{
+			Name:        name,
+			Target:      pane.Target,
+			Command:     pane.Command,
+			ProcessTree: pane.ProcessTree,
+			RecordedAt:  now.UTC(),
+			Note:        flagRecordNote,
+			Expect:      parser.ExpectFromResult(parser.NewRegistry().Parse(content, pane.ProcessTree)),
+			Content:     content,
+		}
+		path, err := parser.WriteFixture(flagRecordDir, f)
+		if err != nil {
+			return fmt.Errorf("failed to write fixture: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "recorded %s (agent=%s blocked=%v reason=%q)\n",
+			path, f.Expect.Agent, f.Expect.Blocked, f.Expect.Reason)
+		fmt.Fprintln(os.Stderr, "review the content for secrets before committing it")
+		return nil
+	},
+}
+
+func init() {
+	recordCmd.Flags().StringVar(&flagRecordDir, "dir", ".", "directory to write the fixture to")
+	recordCmd.Flags().StringVar(&flagRecordName, "name", "", "fixture name (default: <session>-<window>-<pane>-<timestamp>)")
+	recordCmd.Flags().StringVar(&flagRecordNote, "note", "", "free-form note stored in the fixture")
+	rootCmd.AddCommand(recordCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/config"
 	"github.com/timvw/pane-patrol/internal/mux"
 )
 
@@ -11,6 +12,7 @@ var (
 	// Global flags.
 	flagMux     string
 	flagVerbose bool
+	flagProfile string
 )
 
 var rootCmd = &cobra.Command{
@@ -38,12 +40,19 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&flagMux, "mux", envOrDefault("PANE_PATROL_MUX", ""), "terminal multiplexer: tmux, zellij (default: auto-detect)")
 	rootCmd.PersistentFlags().BoolVar(&flagVerbose, "verbose", false, "include raw pane content in output")
+	rootCmd.PersistentFlags().StringVar(&flagProfile, "profile", "", "config profile to apply (default: PANE_PATROL_PROFILE or the profile setting)")
 
 	// Supervisor flags on root (supervisor is the default command).
 	rootCmd.Flags().BoolVar(&flagNoEmbed, "no-embed", false,
 		"Do not auto-embed in a tmux session (navigation will not work outside tmux)")
 	rootCmd.Flags().StringVar(&flagTheme, "theme", "dark",
 		"Color theme: dark, light")
+	rootCmd.Flags().BoolVar(&flagAccessible, "accessible", false,
+		"Screen-reader friendly TUI: states as words, no symbols, box drawing, or colors")
+	rootCmd.Flags().BoolVar(&flagPlain, "plain", false,
+		"Instead of the TUI, print one plain line per pane state change")
+	rootCmd.Flags().IntVar(&flagInline, "inline", 0,
+		"Render the TUI in this many lines below the prompt instead of full screen")
 }
 
 // getMultiplexer returns the configured or auto-detected multiplexer.
@@ -54,6 +63,11 @@ func getMultiplexer() (mux.Multiplexer, error) {
 	return mux.Detect()
 }
 
+// loadConfig loads the configuration with the profile selected by --profile.
+func loadConfig() (*config.Config, error) {
+	return config.LoadProfile(flagProfile)
+}
+
 func envOrDefault(key, defaultValue string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,11 +14,13 @@ import (
 	"github.com/timvw/pane-patrol/internal/model"
 	"github.com/timvw/pane-patrol/internal/mux"
 	"github.com/timvw/pane-patrol/internal/parser"
+	"github.com/timvw/pane-patrol/internal/supervisor"
 )
 
 var (
 	flagScanFilter   string
 	flagScanParallel int
+	flagScanExport   string
 )
 
 var scanCmd = &cobra.Command{
@@ -29,91 +32,185 @@ Known agents (OpenCode, Claude Code, Codex) are evaluated by deterministic
 parsers. Unrecognized panes are reported as unknown.
 
 Outputs a JSON array of verdicts. Use --filter to restrict to sessions
-matching a regex pattern. Use --parallel to evaluate concurrently.`,
+matching a regex pattern. Use --parallel to evaluate concurrently.
+Use --export DIR to also write the verdicts to timestamped JSON and
+Markdown files in DIR, e.g. to hand off supervision or attach to an
+incident.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := cmd.Context()
-
-		m, err := getMultiplexer()
+		verdicts, err := scanAllPanes(cmd.Context(), flagScanFilter, flagScanParallel)
 		if err != nil {
 			return err
 		}
 
-		panes, err := m.ListPanes(ctx, flagScanFilter)
-		if err != nil {
-			return fmt.Errorf("failed to list panes: %w", err)
-		}
-
-		// Apply exclude_sessions from config file
-		cfg, cfgErr := config.Load()
-		if cfgErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to load config: %v\n", cfgErr)
-		}
-		if cfgErr == nil && len(cfg.ExcludeSessions) > 0 {
-			filtered := make([]model.Pane, 0, len(panes))
-			for _, p := range panes {
-				if !config.MatchesExcludeList(p.Session, cfg.ExcludeSessions) {
-					filtered = append(filtered, p)
-				}
+		if flagScanExport != "" {
+			paths, err := supervisor.ExportVerdicts(flagScanExport, verdicts, exportNotes(verdicts), time.Now())
+			if err != nil {
+				return fmt.Errorf("export failed: %w", err)
 			}
-			panes = filtered
+			fmt.Fprintf(os.Stderr, "exported to %s\n", strings.Join(paths, ", "))
 		}
 
-		if len(panes) == 0 {
+		if len(verdicts) == 0 {
 			fmt.Fprintln(os.Stderr, "no panes found")
 			fmt.Println("[]")
 			return nil
 		}
 
-		verdicts := make([]model.Verdict, len(panes))
-		parallel := flagScanParallel
-		if parallel < 1 {
-			parallel = 1
-		}
-		if parallel > len(panes) {
-			parallel = len(panes)
-		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(verdicts)
+	},
+}
 
-		// Evaluate panes with bounded parallelism.
-		registry := parser.NewRegistry()
-		var wg sync.WaitGroup
-		sem := make(chan struct{}, parallel)
-		errCh := make(chan error, len(panes))
-
-		for i, pane := range panes {
-			wg.Add(1)
-			go func(idx int, p model.Pane) {
-				defer wg.Done()
-				sem <- struct{}{}
-				defer func() { <-sem }()
-
-				start := time.Now()
-				v, err := evaluatePane(ctx, m, registry, p)
-				if err != nil {
-					errCh <- fmt.Errorf("pane %s: %w", p.Target, err)
-					// Return a verdict with error info instead of failing the whole scan.
-					v := model.BaseVerdict(p, start)
-					v.Agent = "error"
-					v.Reason = fmt.Sprintf("evaluation failed: %v", err)
-					v.EvalSource = model.EvalSourceError
-					verdicts[idx] = v
-					return
-				}
-				verdicts[idx] = *v
-			}(i, pane)
-		}
+// exportNotes returns the pane notes (set with N in the supervisor) to
+// include in an export. Notes are optional: an unreadable labels file only
+// leaves them out.
+func exportNotes(verdicts []model.Verdict) map[string]string {
+	path := supervisor.DefaultLabelsPath()
+	if cfg, err := loadConfig(); err == nil && cfg.LabelsFile != "" {
+		path = cfg.LabelsFile
+	}
+	key, err := loadStateKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: notes not exported: %v\n", err)
+		return nil
+	}
+	labels, err := supervisor.LoadLabels(path, key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: notes not exported: %v\n", err)
+		return nil
+	}
+	return labels.NotesFor(verdicts)
+}
 
-		wg.Wait()
-		close(errCh)
+// newRegistry builds the parser registry including custom parsers and risk
+// rules from the config file. Invalid definitions are reported as a warning
+// and left out, matching how other config errors are treated by one-shot
+// commands.
+func newRegistry(cfg *config.Config, cfgErr error) *parser.Registry {
+	if cfgErr != nil {
+		return parser.NewRegistry()
+	}
+	custom, err := parser.NewCustomParsers(cfg.Parsers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring custom parsers: %v\n", err)
+		custom = nil
+	}
+	rules, err := parser.NewRiskRules(cfg.RiskRules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring risk rules: %v\n", err)
+		rules = nil
+	}
+	idle, err := parser.NewIdleActions(cfg.IdleActions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring idle actions: %v\n", err)
+		idle = nil
+	}
+	return parser.NewRegistry(custom...).WithIdleActions(idle).WithRiskRules(rules)
+}
+
+// scanAllPanes lists panes matching filter, applies exclude_sessions,
+// include_panes, and exclude_panes from the config file, and evaluates each pane with bounded parallelism. Per-pane
+// evaluation errors are logged to stderr and reported as "error" verdicts
+// rather than failing the whole scan.
+func scanAllPanes(ctx context.Context, filter string, parallel int) ([]model.Verdict, error) {
+	m, err := getMultiplexer()
+	if err != nil {
+		return nil, err
+	}
 
-		// Log errors to stderr but don't fail.
-		for err := range errCh {
-			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	panes, err := m.ListPanes(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list panes: %w", err)
+	}
+
+	// Apply exclude_sessions and the pane filter from config file
+	cfg, cfgErr := loadConfig()
+	if cfgErr != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to load config: %v\n", cfgErr)
+	}
+	if cfgErr == nil && (len(cfg.ExcludeSessions) > 0 || cfg.PaneFilter != nil) {
+		filtered := make([]model.Pane, 0, len(panes))
+		for _, p := range panes {
+			if !config.MatchesExcludeList(p.Session, cfg.ExcludeSessions) && cfg.PaneFilter.Allows(p) {
+				filtered = append(filtered, p)
+			}
 		}
+		panes = filtered
+	}
 
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(verdicts)
-	},
+	if len(panes) == 0 {
+		return nil, nil
+	}
+
+	verdicts := make([]model.Verdict, len(panes))
+	if parallel < 1 {
+		parallel = 1
+	}
+	if parallel > len(panes) {
+		parallel = len(panes)
+	}
+
+	// Evaluate panes with bounded parallelism, and fewer capture-pane
+	// subprocesses at once.
+	registry := newRegistry(cfg, cfgErr)
+	tmuxParallel := config.Defaults().TmuxParallel
+	if cfgErr == nil {
+		tmuxParallel = cfg.TmuxParallel
+	}
+	m = &limitedMux{Multiplexer: m, slots: make(chan struct{}, tmuxParallel)}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+	errCh := make(chan error, len(panes))
+
+	for i, pane := range panes {
+		wg.Add(1)
+		go func(idx int, p model.Pane) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			v, err := evaluatePane(ctx, m, registry, p)
+			if err != nil {
+				errCh <- fmt.Errorf("pane %s: %w", p.Target, err)
+				// Return a verdict with error info instead of failing the whole scan.
+				v := model.BaseVerdict(p, start)
+				v.Agent = "error"
+				v.Reason = fmt.Sprintf("evaluation failed: %v", err)
+				v.EvalSource = model.EvalSourceError
+				verdicts[idx] = v
+				return
+			}
+			verdicts[idx] = *v
+		}(i, pane)
+	}
+
+	wg.Wait()
+	close(errCh)
+
+	// Log errors to stderr but don't fail.
+	for err := range errCh {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	return verdicts, nil
+}
+
+// limitedMux runs at most cap(slots) CapturePane calls at a time.
+type limitedMux struct {
+	mux.Multiplexer
+	slots chan struct{}
+}
+
+func (m *limitedMux) CapturePane(ctx context.Context, target string) (string, error) {
+	select {
+	case m.slots <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { <-m.slots }()
+	return m.Multiplexer.CapturePane(ctx, target)
 }
 
 // evaluatePane captures and evaluates a single pane.
@@ -132,12 +229,17 @@ func evaluatePane(ctx context.Context, m mux.Multiplexer, registry *parser.Regis
 	if parsed := registry.Parse(capture, pane.ProcessTree); parsed != nil {
 		v := model.BaseVerdict(pane, start)
 		v.Agent = parsed.Agent
+		v.Model = parsed.Model
+		v.Confidence = parsed.Confidence
 		v.Blocked = parsed.Blocked
 		v.Reason = parsed.Reason
 		v.WaitingFor = parsed.WaitingFor
+		v.Activity = parsed.Activity
+		v.Idle = parsed.Idle
 		v.Reasoning = parsed.Reasoning
 		v.Actions = parsed.Actions
 		v.Recommended = parsed.Recommended
+		v.Dialog = parsed.Dialog
 		v.Subagents = parsed.Subagents
 		v.EvalSource = model.EvalSourceParser
 		verdict := &v
@@ -163,5 +265,6 @@ func evaluatePane(ctx context.Context, m mux.Multiplexer, registry *parser.Regis
 func init() {
 	scanCmd.Flags().StringVar(&flagScanFilter, "filter", "", "regex pattern to filter by session name")
 	scanCmd.Flags().IntVar(&flagScanParallel, "parallel", 10, "number of panes to evaluate concurrently")
+	scanCmd.Flags().StringVar(&flagScanExport, "export", "", "also write the verdicts to timestamped JSON and Markdown files in this directory")
 	rootCmd.AddCommand(scanCmd)
 }
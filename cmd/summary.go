@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/summary"
+)
+
+var (
+	flagSummaryFilter   string
+	flagSummaryParallel int
+	flagSummaryJSON     bool
+	flagSummaryFailIf   []string
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarize fleet health for automation",
+	Long: `Scan all panes and print aggregate counts by state, agent, and risk,
+plus the longest-blocked panes.
+
+Use --json for machine-readable output. Use --fail-if to exit non-zero
+when a threshold is exceeded, so CI pipelines and cron jobs can act on
+fleet health. --fail-if may be repeated; the command fails if any
+threshold trips.
+
+Threshold metrics:
+  total, blocked, active, unknown, error
+  agent:<name>      panes per agent (e.g. agent:codex)
+  risk:<level>      blocked panes per recommended-action risk
+  oldest_blocked    seconds since the longest-blocked pane's window
+                    last produced output (accepts durations, e.g. 10m)
+
+Operators: >, >=, <, <=, ==, !=
+
+Examples:
+  pane-patrol summary --json
+  pane-patrol summary --fail-if 'blocked>2' --fail-if 'oldest_blocked>15m'`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Parse thresholds before scanning so typos fail fast.
+		thresholds := make([]summary.Threshold, 0, len(flagSummaryFailIf))
+		for _, expr := range flagSummaryFailIf {
+			th, err := summary.ParseThreshold(expr)
+			if err != nil {
+				return err
+			}
+			thresholds = append(thresholds, th)
+		}
+
+		verdicts, err := scanAllPanes(cmd.Context(), flagSummaryFilter, flagSummaryParallel)
+		if err != nil {
+			return err
+		}
+
+		s := summary.Build(verdicts, time.Now())
+
+		if flagSummaryJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(s); err != nil {
+				return err
+			}
+		} else {
+			printSummary(s)
+		}
+
+		var tripped []string
+		for _, th := range thresholds {
+			exceeded, err := th.Exceeded(s)
+			if err != nil {
+				return err
+			}
+			if exceeded {
+				tripped = append(tripped, th.Raw)
+			}
+		}
+		if len(tripped) > 0 {
+			// Threshold failures are expected outcomes, not usage errors.
+			cmd.SilenceUsage = true
+			return fmt.Errorf("threshold exceeded: %s", strings.Join(tripped, ", "))
+		}
+		return nil
+	},
+}
+
+// printSummary writes a human-readable summary to stdout.
+func printSummary(s *summary.Summary) {
+	fmt.Printf("panes:   %d total, %d blocked, %d active, %d unknown, %d error\n",
+		s.Total, s.ByState[summary.StateBlocked], s.ByState[summary.StateActive],
+		s.ByState[summary.StateUnknown], s.ByState[summary.StateError])
+	fmt.Printf("agents:  %s\n", formatCounts(s.ByAgent))
+	fmt.Printf("risk:    %s\n", formatCounts(s.ByRisk))
+	if len(s.OldestBlocked) > 0 {
+		fmt.Println("oldest blocked:")
+		for _, bp := range s.OldestBlocked {
+			age := "unknown"
+			if bp.BlockedForSeconds > 0 {
+				age = (time.Duration(bp.BlockedForSeconds) * time.Second).String()
+			}
+			fmt.Printf("  %-24s %-12s %-10s %s\n", bp.Target, bp.Agent, age, bp.Reason)
+		}
+	}
+	if len(s.Clusters) > 0 {
+		fmt.Println("blocked on:")
+		for _, c := range s.Clusters {
+			fmt.Printf("  %3d  %s\n", c.Count, c.Label)
+		}
+	}
+}
+
+// formatCounts renders a count map as "k=v, k=v" sorted by key.
+func formatCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	summaryCmd.Flags().StringVar(&flagSummaryFilter, "filter", "", "regex pattern to filter by session name")
+	summaryCmd.Flags().IntVar(&flagSummaryParallel, "parallel", 10, "number of panes to evaluate concurrently")
+	summaryCmd.Flags().BoolVar(&flagSummaryJSON, "json", false, "output machine-readable JSON")
+	summaryCmd.Flags().StringArrayVar(&flagSummaryFailIf, "fail-if", nil,
+		"exit non-zero when a threshold is exceeded (e.g. 'blocked>2'); repeatable")
+	rootCmd.AddCommand(summaryCmd)
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
 	"syscall"
 	"time"
@@ -12,14 +14,23 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/timvw/pane-patrol/internal/config"
 	"github.com/timvw/pane-patrol/internal/events"
+	"github.com/timvw/pane-patrol/internal/logging"
+	"github.com/timvw/pane-patrol/internal/mux"
 	telem "github.com/timvw/pane-patrol/internal/otel"
 	"github.com/timvw/pane-patrol/internal/parser"
 	"github.com/timvw/pane-patrol/internal/supervisor"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var flagNoEmbed bool
 var flagTheme string
 var flagEventSocket string
+var flagAccessible bool
+var flagPlain bool
+var flagJSON bool
+var flagInline int
+var flagAutoNudgeViewed bool
+var flagDrain bool
 
 var supervisorCmd = &cobra.Command{
 	Use:   "supervisor",
@@ -44,25 +55,44 @@ func init() {
 	supervisorCmd.Flags().BoolVar(&flagNoEmbed, "no-embed", false,
 		"Do not auto-embed in a tmux session (navigation will not work outside tmux)")
 	supervisorCmd.Flags().StringVar(&flagTheme, "theme", "dark",
-		"Color theme: dark, light")
+		"Color theme: dark, light, or a theme defined in the config file (overrides the theme setting)")
 	supervisorCmd.Flags().StringVar(&flagEventSocket, "event-socket", "",
 		"Unix datagram socket path for hook events")
+	supervisorCmd.Flags().BoolVar(&flagAccessible, "accessible", false,
+		"Screen-reader friendly TUI: states as words, no symbols, box drawing, or colors")
+	supervisorCmd.Flags().BoolVar(&flagPlain, "plain", false,
+		"Instead of the TUI, print one plain line per pane state change")
+	supervisorCmd.Flags().BoolVar(&flagJSON, "json", false,
+		"Like --plain, but print each pane state change as a JSON line (only changes are printed)")
+	supervisorCmd.Flags().IntVar(&flagInline, "inline", 0,
+		"Render the TUI in this many lines below the prompt instead of full screen")
+	supervisorCmd.Flags().BoolVar(&flagDrain, "drain", false,
+		"With --plain, finish the scan in progress on SIGINT/SIGTERM before exiting (a second signal exits at once)")
+	supervisorCmd.Flags().BoolVar(&flagAutoNudgeViewed, "auto-nudge-viewed", false,
+		"Also auto-nudge the pane shown in an attached tmux client (by default it is left to you)")
 	rootCmd.AddCommand(supervisorCmd)
 }
 
 func runSupervisor(cmd *cobra.Command) error {
+	if flagJSON {
+		flagPlain = true
+	}
 	// Auto-embed in tmux if not already inside one.
 	// Navigation (switch-client) requires an active tmux client, so
 	// we re-exec the same command inside a new tmux session.
-	if !flagNoEmbed {
+	// The plain watch mode does not navigate, so it runs anywhere; the
+	// inline mode stays in the current terminal.
+	if !flagNoEmbed && !flagPlain && !inlineRequested(cmd) {
 		autoEmbedInTmux()
 	}
 
+	// Cancelling ctx stops in-flight scans; it is cancelled as soon as the
+	// TUI or watch loop returns, before state is flushed (see shutdown).
 	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel() // cancels in-flight scan goroutines when the TUI exits
+	defer cancel()
 
 	// Load configuration: defaults -> config file -> env vars.
-	cfg, err := config.Load()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("config: %w", err)
 	}
@@ -70,6 +100,18 @@ func runSupervisor(cmd *cobra.Command) error {
 	if cfg.ConfigFile != "" {
 		fmt.Fprintf(os.Stderr, "config: loaded %s\n", cfg.ConfigFile)
 	}
+	for _, p := range cfg.Problems {
+		fmt.Fprintf(os.Stderr, "warning: %s:%s\n", cfg.ConfigFile, p)
+	}
+	stateKey, err := loadStateKey()
+	if err != nil {
+		return err
+	}
+
+	// The debug log is a diagnostic aid; run without it if it cannot be
+	// opened.
+	logger, logFile := openLog(cfg)
+	logger.Info("supervisor started", "version", Version, "config", cfg.ConfigFile, "profile", profileName(cfg))
 
 	// Wire build version into OTEL service metadata
 	telem.Version = Version
@@ -83,7 +125,13 @@ func runSupervisor(cmd *cobra.Command) error {
 		fmt.Fprintf(os.Stderr, "warning: otel init failed: %v\n", err)
 	}
 	if tel != nil {
-		defer tel.Shutdown(ctx)
+		// Flush buffered spans and metrics even after ctx is cancelled,
+		// but do not hang on an unreachable endpoint.
+		defer func() {
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+			defer cancel()
+			tel.Shutdown(flushCtx)
+		}()
 	}
 
 	// Auto-detect multiplexer
@@ -91,6 +139,12 @@ func runSupervisor(cmd *cobra.Command) error {
 	if err != nil {
 		return fmt.Errorf("no supported terminal multiplexer found: %w", err)
 	}
+	if t, ok := m.(*mux.Tmux); ok {
+		if f := t.Features(ctx); !f.Supported() {
+			fmt.Fprintf(os.Stderr, "warning: tmux %s is older than %d.%d; popups and some capture behavior are unavailable\n",
+				f.Version, mux.MinTmuxMajor, mux.MinTmuxMinor)
+		}
+	}
 
 	// Generate a session ID to group all scans from this supervisor run
 	sessionID := fmt.Sprintf("ps-%d-%d", os.Getpid(), time.Now().Unix())
@@ -100,29 +154,65 @@ func runSupervisor(cmd *cobra.Command) error {
 	// the supervisor session (e.g., from split windows) are not useful to scan
 	// and would show as a collapsed session row in the TUI.
 	selfTarget := resolveSelfTarget()
+	var selfSession string
 	if selfTarget != "" {
 		if colonIdx := strings.LastIndex(selfTarget, ":"); colonIdx > 0 {
-			selfSession := selfTarget[:colonIdx]
+			selfSession = selfTarget[:colonIdx]
 			cfg.ExcludeSessions = append(cfg.ExcludeSessions, selfSession)
 			fmt.Fprintf(os.Stderr, "self-session: %s (excluded from scans)\n", selfSession)
 		}
 	}
 
 	var metrics *telem.Metrics
+	var tracer trace.Tracer
 	if tel != nil {
 		metrics = tel.Metrics
+		tracer = tel.Tracer
+	}
+
+	// Custom parsers, risk rules, and idle actions are user config: fail
+	// loudly instead of silently reporting their panes as unrecognized or
+	// keeping the parser's risk levels and actions.
+	customParsers, err := parser.NewCustomParsers(cfg.Parsers)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	riskRules, err := parser.NewRiskRules(cfg.RiskRules)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	idleActions, err := parser.NewIdleActions(cfg.IdleActions)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
 	}
 
 	scanner := &supervisor.Scanner{
 		Mux:             m,
-		Parsers:         parser.NewRegistry(),
+		Parsers:         parser.NewRegistry(customParsers...).WithIdleActions(idleActions).WithRiskRules(riskRules),
 		Filter:          cfg.Filter,
 		ExcludeSessions: cfg.ExcludeSessions,
+		Panes:           cfg.PaneFilter,
 		Parallel:        cfg.Parallel,
+		TmuxParallel:    cfg.TmuxParallel,
 		Metrics:         metrics,
+		Tracer:          tracer,
 		SessionID:       sessionID,
 		SelfTarget:      selfTarget,
-		Cache:           supervisor.NewVerdictCache(cfg.CacheTTLDuration),
+		Log:             logger,
+		Git:             supervisor.NewGitStatus(cfg.GitStatusDuration),
+	}
+
+	// Hook events keep verdicts current, so the verdict cache is only used
+	// when it has a file: it then makes the first scan after a restart
+	// cheap, unless the binary or the parser config changed since. An
+	// unreadable file disables it.
+	if cfg.CacheFile != "" {
+		fingerprint := supervisor.CacheFingerprint(Version, cfg.Parsers, cfg.RiskRules, cfg.IdleActions)
+		if cache, err := supervisor.LoadVerdictCache(cfg.CacheFile, cfg.CacheTTLDuration, stateKey, fingerprint); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: verdict cache disabled: %v\n", err)
+		} else {
+			scanner.Cache = cache
+		}
 	}
 
 	socketPath := flagEventSocket
@@ -138,17 +228,357 @@ func runSupervisor(cmd *cobra.Command) error {
 
 	scanner.EventStore = eventStore
 	scanner.EventOnly = true
-	scanner.Cache = nil
+
+	var announcer *supervisor.Announcer
+	if cfg.Speech {
+		announcer, err = supervisor.NewAnnouncer(cfg.SpeechCommand)
+		if err != nil {
+			// Speech is an accessibility aid; don't refuse to start without it.
+			fmt.Fprintf(os.Stderr, "warning: speech disabled: %v\n", err)
+		}
+	}
+
+	// Labels are a convenience; start without them if the file is unreadable.
+	labelsPath := cfg.LabelsFile
+	if labelsPath == "" {
+		labelsPath = supervisor.DefaultLabelsPath()
+	}
+	labels, err := supervisor.LoadLabels(labelsPath, stateKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: labels disabled: %v\n", err)
+	}
+	if announcer != nil {
+		announcer.Labels = labels
+	}
+
+	if flagPlain {
+		watchCtx, stop, release := watchSignals(ctx, flagDrain)
+		err := supervisor.Watch(watchCtx, stop, scanner, os.Stdout, cfg.RefreshDuration, labels, flagJSON)
+		release()
+		cancel()
+		shutdown(ctx, scanner, nil, logger)
+		return err
+	}
+
+	history := newHistory(cfg, stateKey)
+	if history != nil {
+		history.Log = logger
+	}
+
+	audit, err := openAuditLog(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Remembered answers are a convenience too; an unreadable file only
+	// disables them.
+	var answerMemory *supervisor.AnswerMemory
+	if cfg.AnswersFile != "off" {
+		answersPath := cfg.AnswersFile
+		if answersPath == "" {
+			answersPath = supervisor.DefaultAnswersPath()
+		}
+		if answerMemory, err = supervisor.LoadAnswerMemory(answersPath, stateKey); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: remembered answers disabled: %v\n", err)
+		}
+	}
+
+	instance := registerInstance(ctx, m, selfTarget)
+
+	var badges *supervisor.Badges
+	if len(cfg.TmuxBadges) > 0 {
+		if badges, err = supervisor.NewBadges(cfg.TmuxBadges, mux.NewTmux()); err != nil {
+			return err
+		}
+	}
+
+	profiles, err := supervisorProfiles(cfg, selfSession)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	themeName, err := resolveTheme(cmd, cfg)
+	if err != nil {
+		return err
+	}
 
 	tui := &supervisor.TUI{
 		Scanner:          scanner,
 		RefreshInterval:  cfg.RefreshDuration,
 		AutoNudge:        cfg.AutoNudge,
 		AutoNudgeMaxRisk: cfg.AutoNudgeMaxRisk,
-		ThemeName:        flagTheme,
+		AutoNudgeViewed:  flagAutoNudgeViewed,
+		Instance:         instance,
+		ThemeName:        themeName,
+		Themes:           cfg.Themes,
+		ColorProfile:     cfg.ColorProfile,
+		Announcer:        announcer,
+		ShowWaitingFor:   cfg.ShowWaitingFor,
+		ShowRecommended:  cfg.ShowRecommended,
+		TemplateDir:      cfg.TemplateDir,
+		Layout:           cfg.Layout,
+		ActionOrder:      cfg.ActionOrder,
+		ClickAction:      cfg.ClickAction,
+		GroupBy:          cfg.GroupBy,
+		Labels:           labels,
+		SyncPaneTitles:   cfg.SyncPaneTitles,
+		Snippets:         cfg.Snippets,
+		Continuations:    cfg.Continuations,
+		IdleAfter:        cfg.IdleAfterDuration,
+		Tasks:            supervisor.NewTaskSource(cfg.TaskCommand),
+		ExportDir:        cfg.ExportDir,
+		ConfirmHighRisk:  cfg.ConfirmHighRisk,
+		History:          history,
+		Audit:            audit,
+		Badges:           badges,
+		TransitionHook:   supervisor.NewTransitionHook(cfg.TransitionCommand),
+		Escalator:        supervisor.NewEscalator(cfg.Escalations),
+		QuietHours:       cfg.QuietHours,
+		AnswerMemory:     answerMemory,
+		Profiles:         profiles,
+		Profile:          profileName(cfg),
+		Accessible:       cfg.Accessible || flagAccessible,
+		Inline:           cfg.InlineLines,
+		ConfigFile:       cfg.ConfigFile,
+		Control:          supervisor.NewControlSocket(cfg.ControlSocket),
+		Reload: func(profile string) (supervisor.Settings, error) {
+			return reloadSettings(cmd, profile, selfSession)
+		},
+		Log:     logger,
+		LogFile: logFile,
+	}
+	if cmd.Flags().Changed("inline") {
+		tui.Inline = flagInline
 	}
 
-	return tui.Run(ctx)
+	err = tui.Run(ctx)
+	cancel()
+	if instance != nil {
+		closeCtx, cancelClose := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		if err := instance.Close(closeCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: unregistering supervisor: %v\n", err)
+		}
+		cancelClose()
+	}
+	shutdown(ctx, scanner, badges, logger)
+	return err
+}
+
+// registerInstance marks the supervisor's pane, so other supervisors on
+// the tmux server leave it alone, and reports the ones already running.
+// It returns nil when the multiplexer is not tmux.
+func registerInstance(ctx context.Context, m mux.Multiplexer, selfTarget string) *supervisor.Instance {
+	t, ok := m.(*mux.Tmux)
+	if !ok {
+		return nil
+	}
+	instance := supervisor.NewInstance(t, selfTarget)
+	if err := instance.Register(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: registering supervisor: %v\n", err)
+	}
+	panes, err := t.ListPanes(ctx, "")
+	if err != nil {
+		return instance
+	}
+	for _, p := range panes {
+		if p.Supervisor && p.Target != selfTarget {
+			fmt.Fprintf(os.Stderr, "another supervisor runs in %s (auto-nudge is taken in turns)\n", p.Target)
+		}
+	}
+	return instance
+}
+
+// shutdownTimeout bounds each cleanup step on exit that talks to tmux or
+// the network.
+const shutdownTimeout = 5 * time.Second
+
+// shutdown writes out what the supervisor keeps in memory once it stops:
+// the verdict cache, and the tmux badges, which are removed. History,
+// labels, and remembered answers are written as they change. ctx must be
+// cancelled first, so no scan is still filling the cache.
+func shutdown(ctx context.Context, scanner *supervisor.Scanner, badges *supervisor.Badges, logger *slog.Logger) {
+	if cache := scanner.VerdictCache(); cache != nil {
+		if err := cache.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+	if badges != nil {
+		// Don't leave panes marked blocked when nobody is supervising.
+		clearCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		if err := badges.Clear(clearCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: removing tmux badges: %v\n", err)
+		}
+	}
+	logger.Info("supervisor stopped")
+}
+
+// watchSignals stops the plain watch mode on SIGINT or SIGTERM: the
+// returned context is cancelled, stopping the scan in progress. With
+// drain, the first signal closes stop instead, so the scan in progress
+// finishes and its transitions are written, and a second signal cancels.
+// release stops listening for signals.
+func watchSignals(ctx context.Context, drain bool) (watchCtx context.Context, stop <-chan struct{}, release func()) {
+	watchCtx, cancel := context.WithCancel(ctx)
+	stopCh := make(chan struct{})
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigs:
+		case <-watchCtx.Done():
+			return
+		}
+		if !drain {
+			cancel()
+			return
+		}
+		fmt.Fprintln(os.Stderr, "stopping after the scan in progress (signal again to stop now)")
+		close(stopCh)
+		select {
+		case <-sigs:
+			cancel()
+		case <-watchCtx.Done():
+		}
+	}()
+	return watchCtx, stopCh, func() {
+		signal.Stop(sigs)
+		cancel()
+	}
+}
+
+// inlineRequested reports whether --inline asks for the inline mode, which
+// runs in the current terminal rather than a new tmux session. The config
+// file is read after auto-embedding, so outside tmux inline_lines needs
+// --no-embed.
+func inlineRequested(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("inline") && flagInline > 0
+}
+
+// openLog opens the debug log configured by log_file and returns it with
+// its path, or a discarding logger and "" when it is "off" or cannot be
+// opened. The file stays open until the process exits.
+func openLog(cfg *config.Config) (*slog.Logger, string) {
+	path := cfg.LogFile
+	switch path {
+	case "off":
+		return logging.OrDiscard(nil), ""
+	case "":
+		path = logging.DefaultPath()
+	}
+	level, _ := logging.ParseLevel(cfg.LogLevel) // validated by config.Load
+	logger, _, err := logging.Open(path, level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: log disabled: %v\n", err)
+		return logging.OrDiscard(nil), ""
+	}
+	return logger, path
+}
+
+// newHistory returns the action history configured by history_file, or nil
+// when it is "off".
+func newHistory(cfg *config.Config, key *supervisor.StateKey) *supervisor.History {
+	var history *supervisor.History
+	switch cfg.HistoryFile {
+	case "off":
+		return nil
+	case "":
+		history = supervisor.NewHistory(supervisor.DefaultHistoryPath(), key)
+	default:
+		history = supervisor.NewHistory(cfg.HistoryFile, key)
+	}
+	history.MaxAge, history.MaxSize = cfg.HistoryMaxAgeDuration, cfg.HistoryMaxBytes
+	return history
+}
+
+// resolveTheme returns the theme to start with: --theme overrides the
+// theme setting, which config.Load validated.
+func resolveTheme(cmd *cobra.Command, cfg *config.Config) (string, error) {
+	themeName := cfg.Theme
+	if cmd.Flags().Changed("theme") || themeName == "" {
+		themeName = flagTheme
+	}
+	if _, ok := cfg.Themes[themeName]; !ok && themeName != "dark" && themeName != "light" {
+		return "", fmt.Errorf("unknown theme %q (must be dark, light, or defined under themes in the config file)", themeName)
+	}
+	return themeName, nil
+}
+
+// reloadSettings loads the config again with profile applied, for the
+// supervisor's live reload.
+func reloadSettings(cmd *cobra.Command, profile, selfSession string) (supervisor.Settings, error) {
+	cfg, err := config.LoadProfile(profile)
+	if err != nil {
+		return supervisor.Settings{}, err
+	}
+	themeName, err := resolveTheme(cmd, cfg)
+	if err != nil {
+		return supervisor.Settings{}, err
+	}
+	profiles, err := supervisorProfiles(cfg, selfSession)
+	if err != nil {
+		return supervisor.Settings{}, err
+	}
+	return supervisor.Settings{
+		Profile:         supervisorProfile(profileName(cfg), cfg, selfSession),
+		Profiles:        profiles,
+		ThemeName:       themeName,
+		Themes:          cfg.Themes,
+		ShowWaitingFor:  cfg.ShowWaitingFor,
+		ShowRecommended: cfg.ShowRecommended,
+		Layout:          cfg.Layout,
+		ActionOrder:     cfg.ActionOrder,
+		ClickAction:     cfg.ClickAction,
+		GroupBy:         cfg.GroupBy,
+		Snippets:        cfg.Snippets,
+		Continuations:   cfg.Continuations,
+		ConfirmHighRisk: cfg.ConfirmHighRisk,
+	}, nil
+}
+
+// supervisorProfiles returns the scan settings of every profile in the
+// config file for switching with P, or nil when none are defined. Each
+// profile excludes the supervisor's own session, like the one it started
+// with.
+func supervisorProfiles(cfg *config.Config, selfSession string) ([]supervisor.Profile, error) {
+	if len(cfg.Profiles) == 0 {
+		return nil, nil
+	}
+	var profiles []supervisor.Profile
+	for _, name := range cfg.ProfileNames() {
+		pcfg, err := config.LoadProfile(name)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, supervisorProfile(name, pcfg, selfSession))
+	}
+	return profiles, nil
+}
+
+// supervisorProfile returns the scan settings of cfg, loaded with the named
+// profile, excluding the supervisor's own session.
+func supervisorProfile(name string, cfg *config.Config, selfSession string) supervisor.Profile {
+	exclude := cfg.ExcludeSessions
+	if selfSession != "" {
+		exclude = append(exclude, selfSession)
+	}
+	return supervisor.Profile{
+		Name:             name,
+		Filter:           cfg.Filter,
+		ExcludeSessions:  exclude,
+		Panes:            cfg.PaneFilter,
+		RefreshInterval:  cfg.RefreshDuration,
+		AutoNudge:        cfg.AutoNudge,
+		AutoNudgeMaxRisk: cfg.AutoNudgeMaxRisk,
+	}
+}
+
+// profileName returns the name of the profile cfg was loaded with.
+func profileName(cfg *config.Config) string {
+	if cfg.Profile == "" {
+		return config.DefaultProfile
+	}
+	return cfg.Profile
 }
 
 // autoEmbedInTmux re-launches the current process inside a tmux session
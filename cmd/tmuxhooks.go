@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/supervisor"
+)
+
+var tmuxHooksCmd = &cobra.Command{
+	Use:   "tmux-hooks",
+	Short: "Install tmux hooks that make the supervisor rescan new and focused panes",
+	Long: `Install global tmux hooks (pane-focus-in, after-split-window,
+after-new-window, session-created) that ask the running supervisor, over its
+control socket, to rescan the pane they fired for. New agent panes then show
+up within a second instead of at the next refresh.
+
+The hooks are appended to the hooks you already have; uninstall removes
+only pane-patrol's. Hooks last as long as the tmux server: add
+"run-shell 'pane-patrol tmux-hooks install'" to tmux.conf to keep them.
+pane-focus-in only fires with "set -g focus-events on".`,
+}
+
+var tmuxHooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the rescan hooks, replacing ones installed before",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("resolve executable for the hooks: %w", err)
+		}
+		if err := supervisor.InstallRescanHooks(runTmux, exe); err != nil {
+			return err
+		}
+		fmt.Printf("Installed rescan hooks: %v\n", supervisor.RescanHooks)
+		return nil
+	},
+}
+
+var tmuxHooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the rescan hooks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hooks, err := supervisor.InstalledRescanHooks(runTmux)
+		if err != nil {
+			return err
+		}
+		if err := supervisor.UninstallRescanHooks(runTmux); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %d rescan hooks\n", len(hooks))
+		return nil
+	},
+}
+
+// runTmux runs tmux with args and returns its combined output.
+func runTmux(args ...string) ([]byte, error) {
+	return exec.Command("tmux", args...).CombinedOutput()
+}
+
+func init() {
+	tmuxHooksCmd.AddCommand(tmuxHooksInstallCmd, tmuxHooksUninstallCmd)
+	rootCmd.AddCommand(tmuxHooksCmd)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+var (
+	flagWrapSession string
+	flagWrapDetach  bool
+)
+
+var wrapCmd = &cobra.Command{
+	Use:   "wrap [flags] -- <command> [args...]",
+	Short: "Launch a command in a managed tmux session for supervision",
+	Long: `Launch an agent in a new tmux session so pane-patrol can supervise it,
+even when you work from a terminal that is not tmux (e.g. the VS Code
+integrated terminal).
+
+wrap creates a detached tmux session, starts the command in it, checks
+that the session is within the configured scan scope (filter and
+exclude_sessions), and then attaches the current terminal to it. You keep
+using the agent in the same terminal; the supervisor sees it as a normal
+tmux pane, and assistant hooks can resolve its target via $TMUX_PANE.
+
+The session is named after the command and working directory unless
+--session is given. Use --detach to start the session without attaching.
+
+Examples:
+  pane-patrol wrap -- claude
+  pane-patrol wrap --session api-refactor -- opencode
+  pane-patrol wrap --detach -- codex --model gpt-5-codex`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmuxPath, err := exec.LookPath("tmux")
+		if err != nil {
+			return fmt.Errorf("tmux not found in PATH: wrap requires tmux")
+		}
+		t := mux.NewTmux()
+		ctx := cmd.Context()
+
+		wd, err := os.Getwd()
+		if err != nil {
+			wd = ""
+		}
+
+		name := flagWrapSession
+		if name == "" {
+			name = uniqueSessionName(ctx, t, defaultWrapSessionName(args[0], wd))
+		} else {
+			name = sanitizeSessionName(name)
+			if t.HasSession(ctx, name) {
+				return fmt.Errorf("tmux session %q already exists", name)
+			}
+		}
+
+		target, err := t.NewSession(ctx, name, wd, args)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "wrap: started %q in %s\n", strings.Join(args, " "), target)
+
+		// Registration: the supervisor scans every tmux pane, so the only
+		// way a wrapped session goes unsupervised is by being filtered out.
+		if cfg, err := loadConfig(); err == nil {
+			if warning := scopeWarning(name, cfg); warning != "" {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+			}
+		}
+
+		if flagWrapDetach {
+			fmt.Fprintf(os.Stderr, "wrap: attach with: tmux attach -t %s\n", name)
+			return nil
+		}
+
+		if os.Getenv("TMUX") != "" {
+			// Already inside tmux: switch this client instead of nesting.
+			c := exec.Command(tmuxPath, "switch-client", "-t", target)
+			if out, err := c.CombinedOutput(); err != nil {
+				return fmt.Errorf("switch to %s: %w (%s)", target, err, strings.TrimSpace(string(out)))
+			}
+			return nil
+		}
+
+		// Replace this process with tmux attach so the terminal (e.g. VS Code)
+		// becomes the tmux client for the wrapped session.
+		attachArgs := []string{"tmux", "attach-session", "-t", "=" + name}
+		if err := syscall.Exec(tmuxPath, attachArgs, os.Environ()); err != nil {
+			return fmt.Errorf("attach to %s: %w", name, err)
+		}
+		return nil
+	},
+}
+
+// invalidSessionChars matches characters tmux does not allow (":" and ".")
+// or that would be awkward in a target string.
+var invalidSessionChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// sanitizeSessionName replaces characters that tmux rejects in session
+// names with "-".
+func sanitizeSessionName(name string) string {
+	name = invalidSessionChars.ReplaceAllString(name, "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "wrapped"
+	}
+	return name
+}
+
+// defaultWrapSessionName builds "<command>-<dir>" from the command's base
+// name and the working directory's base name.
+func defaultWrapSessionName(command, wd string) string {
+	name := filepath.Base(command)
+	if wd != "" {
+		if dir := filepath.Base(wd); dir != "/" && dir != "." {
+			name += "-" + dir
+		}
+	}
+	return sanitizeSessionName(name)
+}
+
+// uniqueSessionName appends "-2", "-3", ... to base until no tmux session
+// with that name exists.
+func uniqueSessionName(ctx context.Context, t *mux.Tmux, base string) string {
+	name := base
+	for i := 2; t.HasSession(ctx, name); i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	return name
+}
+
+// scopeWarning returns a warning if the session would be hidden from scans
+// by the configured filter or exclude list, or "" if it is in scope.
+func scopeWarning(session string, cfg *config.Config) string {
+	if config.MatchesExcludeList(session, cfg.ExcludeSessions) {
+		return fmt.Sprintf("session %q matches exclude_sessions and will not be supervised", session)
+	}
+	if cfg.FilterRegexp != nil && !cfg.FilterRegexp.MatchString(session) {
+		return fmt.Sprintf("session %q does not match filter %q and will not be supervised", session, cfg.Filter)
+	}
+	return ""
+}
+
+func init() {
+	wrapCmd.Flags().StringVar(&flagWrapSession, "session", "", "tmux session name (default: <command>-<dir>)")
+	wrapCmd.Flags().BoolVar(&flagWrapDetach, "detach", false, "start the session without attaching to it")
+	rootCmd.AddCommand(wrapCmd)
+}
@@ -2,7 +2,7 @@
 //
 // Precedence (highest to lowest):
 //  1. Environment variables (PANE_PATROL_*)
-//  2. Config file
+//  2. Config file, with the selected profile applied
 //  3. Built-in defaults
 //
 // Config file search order:
@@ -13,66 +13,613 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/timvw/pane-patrol/internal/logging"
+	"github.com/timvw/pane-patrol/internal/model"
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds all pane-supervisor configuration.
 type Config struct {
 	// Scan settings
-	Filter   string `yaml:"filter"`
-	Parallel int    `yaml:"parallel"`
+	Filter       string `yaml:"filter"`
+	Parallel     int    `yaml:"parallel"`      // Panes evaluated at once
+	TmuxParallel int    `yaml:"tmux_parallel"` // Concurrent tmux subprocesses (capture-pane) during a scan
 
 	// Refresh and cache
 	Refresh  string `yaml:"refresh"`   // Go duration string, e.g. "30s"
 	CacheTTL string `yaml:"cache_ttl"` // Go duration string, e.g. "5m"
+	// CacheFile keeps the verdict cache on disk across supervisor restarts
+	// (default: in memory only).
+	CacheFile string `yaml:"cache_file"`
+	// GitStatus is how long the branch and dirty state of an agent pane's
+	// directory is reused before git is run again (default: "30s"; "off"
+	// disables the lookup).
+	GitStatus string `yaml:"git_status"`
 
 	// Session filtering
 	ExcludeSessions []string `yaml:"exclude_sessions"` // Session names to exclude from scanning (exact match)
 
+	// Window and pane filtering (config file only): only panes matching an
+	// include_panes entry (when there are any) and no exclude_panes entry
+	// are scanned.
+	IncludePanes []PaneMatch `yaml:"include_panes"`
+	ExcludePanes []PaneMatch `yaml:"exclude_panes"`
+
 	// Auto-nudge
 	AutoNudge        bool   `yaml:"auto_nudge"`          // Enable automatic nudging of blocked panes
 	AutoNudgeMaxRisk string `yaml:"auto_nudge_max_risk"` // Maximum risk level to auto-nudge: "low" (default), "medium", "high"
 
+	// Audit
+	ConfirmHighRisk bool   `yaml:"confirm_high_risk"` // Require typing "yes" or a reason before sending a high-risk action
+	HistoryFile     string `yaml:"history_file"`      // Log of actions sent to panes (default: ~/.config/pane-patrol/history.jsonl; "off" disables)
+	HistoryMaxAge   string `yaml:"history_max_age"`   // Drop history entries older than this, e.g. "90d" (default: "off")
+	HistoryMaxSize  string `yaml:"history_max_size"`  // Trim the oldest history entries once the file is larger, e.g. "10MB" (default: "10MB"; "off" disables)
+	AnswersFile     string `yaml:"answers_file"`      // Answers to recurring dialogs remembered with R (default: ~/.config/pane-patrol/answers.json; "off" disables)
+	AuditFile       string `yaml:"audit_file"`        // Tamper-evident log of every verdict and action (off unless set; needs an audit key)
+	AuditKeyCommand string `yaml:"audit_key_command"` // Prints the secret that signs the audit log, e.g. from the keychain (PANE_PATROL_AUDIT_KEY takes precedence)
+	StateKeyCommand string `yaml:"state_key_command"` // Prints the secret that encrypts the history and state files, e.g. from the keychain (PANE_PATROL_STATE_KEY takes precedence)
+
+	// Debug log
+	LogFile  string `yaml:"log_file"`  // Supervisor log, rotated at 5 MB (default: ~/.cache/pane-patrol/supervisor.log; "off" disables)
+	LogLevel string `yaml:"log_level"` // "debug", "info" (default), "warn", or "error"
+
+	// Display
+	ShowWaitingFor  bool   `yaml:"show_waiting_for"` // Show the first line of WaitingFor under blocked pane rows
+	ShowRecommended bool   `yaml:"show_recommended"` // Show the recommended action after the reason of blocked pane rows
+	Layout          string `yaml:"layout"`           // Action panel placement: "bottom" (default) or "right"
+	ActionOrder     string `yaml:"action_order"`     // Panel action order: "dialog" (default), "risk" (safest first), or "grouped" (high risk last, below a separator)
+	ClickAction     string `yaml:"click_action"`     // Single click on a pane: "jump" (default) or "select" (double or alt/ctrl-click jumps)
+	GroupBy         string `yaml:"group_by"`         // List grouping: "session" (default), "directory", or "repo"
+	Theme           string `yaml:"theme"`            // Color theme: "dark" (default), "light", or a name from Themes
+	ColorProfile    string `yaml:"color_profile"`    // Terminal colors: "auto" (default), "truecolor", "256", "16", "none"
+	Accessible      bool   `yaml:"accessible"`       // Screen-reader friendly TUI: states as words, no symbols, box drawing, or colors
+	InlineLines     int    `yaml:"inline_lines"`     // Render the TUI in this many lines below the prompt instead of full screen (0: full screen)
+
+	// Speech announcements
+	Speech        bool   `yaml:"speech"`         // Announce newly blocked panes via text-to-speech
+	SpeechCommand string `yaml:"speech_command"` // Custom TTS shell command, reads text on stdin (default: say/espeak-ng/espeak)
+
+	// TransitionCommand is run with sh -c for every pane state transition
+	// (became_blocked, became_active, reason_changed, disappeared), with the
+	// transition as JSON on stdin.
+	TransitionCommand string `yaml:"transition_command"`
+
+	// Unix socket the supervisor takes commands on from scripts and tmux
+	// key bindings (default: control.sock next to the hook socket; "off"
+	// disables it).
+	ControlSocket string `yaml:"control_socket"`
+
+	// Second notifications for panes that stay blocked (config file only)
+	Escalations []Escalation `yaml:"escalations"`
+
+	// When notifications are suppressed (config file only)
+	QuietHours QuietHours `yaml:"quiet_hours"`
+
+	// Pane and session labels
+	LabelsFile     string `yaml:"labels_file"`      // Where labels set with n are stored (default: ~/.config/pane-patrol/labels.json)
+	SyncPaneTitles bool   `yaml:"sync_pane_titles"` // Also set pane labels as tmux pane titles
+
+	// Blocked-pane badges in tmux
+	TmuxBadges []string `yaml:"tmux_badges"` // Mark blocked panes in tmux: "flag", "rename", and/or "border"
+
+	// Fleet templates
+	TemplateDir string `yaml:"template_dir"` // Directory of launch templates for the supervisor (default: ~/.config/pane-patrol/templates)
+
+	// Exports
+	ExportDir string `yaml:"export_dir"` // Directory for verdict exports (e) and pane transcripts (H) (default: current directory)
+
 	// OTEL
 	OTELEndpoint string `yaml:"otel_endpoint"`
 	OTELHeaders  string `yaml:"otel_headers"` // Comma-separated key=value pairs, e.g. "Authorization=Basic abc123"
 
+	// Custom parsers for agents without a builtin parser (config file only)
+	Parsers []CustomParser `yaml:"parsers"`
+
+	// Risk overrides applied to parsed actions (config file only)
+	RiskRules []RiskRule `yaml:"risk_rules"`
+
+	// What to recommend for agents idle at their prompt, by agent name
+	// (config file only)
+	IdleActions map[string]IdleAction `yaml:"idle_actions"`
+
+	// Canned answers offered in the supervisor's text input (config file only)
+	Snippets []Snippet `yaml:"snippets"`
+
+	// Prompts offered to agents idle at their prompt, e.g. to keep them
+	// going (config file only)
+	Continuations []Snippet `yaml:"continuations"`
+
+	// Idle agents
+	IdleAfter   string `yaml:"idle_after"`   // Report agents idle at their prompt this long and list them in the idle filter (default: "10m"; "off" disables)
+	TaskCommand string `yaml:"task_command"` // Prints the next task for an idle agent (O), e.g. from a ticket queue; the pane's verdict is on stdin
+
+	// User-defined color themes, selectable by name (config file only)
+	Themes map[string]ThemeColors `yaml:"themes"`
+
+	// Named profiles overriding the settings above, e.g. for separate
+	// fleets (config file only). Profile selects one by default; the
+	// --profile flag and PANE_PATROL_PROFILE take precedence.
+	Profile  string            `yaml:"profile"`
+	Profiles map[string]Config `yaml:"profiles"`
+
 	// Parsed durations (not from YAML, set after loading)
-	RefreshDuration  time.Duration `yaml:"-"`
-	CacheTTLDuration time.Duration `yaml:"-"`
+	RefreshDuration       time.Duration `yaml:"-"`
+	CacheTTLDuration      time.Duration `yaml:"-"`
+	GitStatusDuration     time.Duration `yaml:"-"`
+	IdleAfterDuration     time.Duration `yaml:"-"`
+	HistoryMaxAgeDuration time.Duration `yaml:"-"`
+	HistoryMaxBytes       int64         `yaml:"-"`
+
+	// FilterRegexp is Filter compiled after loading; nil when it is empty.
+	FilterRegexp *regexp.Regexp `yaml:"-"`
+
+	// PaneFilter is IncludePanes and ExcludePanes compiled after loading;
+	// nil when both are empty.
+	PaneFilter *PaneFilter `yaml:"-"`
 
 	// ConfigFile is the path to the config file that was loaded (empty if none).
 	ConfigFile string `yaml:"-"`
+	// Problems are what Lint found in the config file; Load does not fail
+	// on them.
+	Problems []Problem `yaml:"-"`
+}
+
+// CustomParser defines a config-driven parser for an agent TUI that has no
+// builtin parser. All patterns are Go regular expressions.
+//
+// Example:
+//
+//	parsers:
+//	  - name: acme_agent
+//	    identify:
+//	      process: ["acme-agent"]
+//	      content: ["ACME Agent v\\d+"]
+//	    blocked:
+//	      - pattern: "Approve (\\w+) call\\?"
+//	        reason: "approval for $1"
+//	        actions:
+//	          - {keys: "y", label: "approve", risk: medium, raw: true}
+//	          - {keys: "n", label: "deny", risk: low, raw: true}
+//	    active: ["Thinking…"]
+type CustomParser struct {
+	// Name is the agent identifier reported in verdicts (e.g., "acme_agent").
+	Name string `yaml:"name"`
+	// Identify decides whether a pane belongs to this agent. Any match wins.
+	Identify CustomParserIdentify `yaml:"identify"`
+	// Blocked rules are tried in order against the bottom lines; the first
+	// matching rule produces a blocked verdict.
+	Blocked []CustomParserRule `yaml:"blocked"`
+	// Active patterns mark the agent as working when found in the bottom lines.
+	Active []string `yaml:"active"`
+	// BottomLines is the number of non-empty lines from the bottom of the
+	// pane that rules are matched against. Default: 8.
+	BottomLines int `yaml:"bottom_lines"`
+}
+
+// CustomParserIdentify holds the patterns that identify an agent's pane.
+type CustomParserIdentify struct {
+	// Process patterns are matched against each process tree entry.
+	Process []string `yaml:"process"`
+	// Content patterns are matched against the full pane capture.
+	Content []string `yaml:"content"`
+}
+
+// CustomParserRule maps a pattern to a blocked verdict.
+type CustomParserRule struct {
+	Pattern string `yaml:"pattern"`
+	// Reason is the verdict reason. Supports regex expansion ($1, ${name})
+	// from Pattern's capture groups.
+	Reason string `yaml:"reason"`
+	// Actions are the suggested unblocking actions. Labels support the same
+	// expansion as Reason.
+	Actions []model.Action `yaml:"actions"`
+	// Recommended is the 0-based index into Actions.
+	Recommended int `yaml:"recommended"`
+}
+
+// RiskRule overrides the risk level the parser assigned to matching actions
+// of blocked panes. All set fields must match; patterns are Go regular
+// expressions. Rules are tried in order and the first match sets the risk.
+//
+// Example:
+//
+//	risk_rules:
+//	  - waiting_for: "rm -rf|git push (-f|--force)"
+//	    risk: high
+//	  - agent: claude_code
+//	    action: "(?i)don't ask again|always"
+//	    risk: high
+type RiskRule struct {
+	// Agent is the exact agent name (e.g., "codex"); empty matches any agent.
+	Agent string `yaml:"agent"`
+	// Action is matched against the action's label and its keys; empty
+	// matches every action.
+	Action string `yaml:"action"`
+	// WaitingFor is matched against the pane's WaitingFor text and dialog
+	// question; empty matches any pane.
+	WaitingFor string `yaml:"waiting_for"`
+	// Risk is the new level: low, medium, or high.
+	Risk string `yaml:"risk"`
+}
+
+// IdleAction replaces the action recommended for an agent idle at its
+// prompt, which is Enter (an empty message) by default. The recommended
+// action is also what auto-nudge sends. Set one of Send or None.
+//
+// Example:
+//
+//	idle_actions:
+//	  claude_code:
+//	    send: continue with the plan
+//	  codex:
+//	    send: /compact
+//	  opencode:
+//	    none: true
+type IdleAction struct {
+	// Send is typed at the prompt and submitted: a continuation prompt or
+	// a slash command.
+	Send string `yaml:"send"`
+	// None recommends nothing, so idle panes of the agent are never
+	// auto-nudged; Enter is still offered.
+	None bool `yaml:"none"`
+	// Risk is the level of the Send action: low (default), medium, or high.
+	Risk string `yaml:"risk"`
+}
+
+// Escalation notifies again, typically on a louder channel, about a pane
+// still blocked After it first blocked (when transition_command reported
+// became_blocked). Each escalation runs once per blocked period, for panes
+// whose recommended action is at least Risk.
+//
+// Example:
+//
+//	escalations:
+//	  - after: 10m
+//	    command: ./notify-slack.sh
+//	  - after: 5m
+//	    risk: high
+//	    command: ./page-me.sh
+type Escalation struct {
+	// After is how long the pane has been blocked, e.g. "10m".
+	After string `yaml:"after"`
+	// Command is run with sh -c, like transition_command.
+	Command string `yaml:"command"`
+	// Risk is the lowest risk escalated: low (default, every pane),
+	// medium, or high.
+	Risk string `yaml:"risk"`
+
+	AfterDuration time.Duration `yaml:"-"`
+}
+
+// validate parses After and checks the other fields.
+func (e *Escalation) validate() error {
+	d, err := time.ParseDuration(e.After)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid after %q (want a duration like 10m)", e.After)
+	}
+	e.AfterDuration = d
+	if strings.TrimSpace(e.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	e.Risk = strings.ToLower(e.Risk)
+	switch e.Risk {
+	case "":
+		e.Risk = "low"
+	case "low", "medium", "high":
+	default:
+		return fmt.Errorf("risk must be low, medium, or high (got %q)", e.Risk)
+	}
+	return nil
+}
+
+// QuietHours is a do-not-disturb schedule: while it is quiet, speech,
+// transition_command, and escalations are held back, and auto-nudge pauses
+// or is limited to lower risks.
+//
+// Example:
+//
+//	quiet_hours:
+//	  schedule:
+//	    - from: "23:00"
+//	      to: "08:00"
+//	    - days: [sat, sun]
+//	  auto_nudge: pause
+type QuietHours struct {
+	Schedule []QuietPeriod `yaml:"schedule"`
+	// AutoNudge is what auto-nudge does while quiet: "" (as usual),
+	// "pause", or a maximum risk (low, medium) lower than
+	// auto_nudge_max_risk.
+	AutoNudge string `yaml:"auto_nudge"`
+}
+
+// QuietPeriod is one entry of the schedule: From to To (local "HH:MM",
+// crossing midnight when To is earlier) on Days, or all of Days without
+// times. Without Days it applies every day; a period crossing midnight
+// belongs to the day it starts.
+type QuietPeriod struct {
+	Days []string `yaml:"days"` // mon, tue, wed, thu, fri, sat, sun
+	From string   `yaml:"from"`
+	To   string   `yaml:"to"`
+
+	// Parsed from the above: the days by time.Weekday, and From and To
+	// in minutes after midnight.
+	Weekdays   [7]bool `yaml:"-"`
+	FromMinute int     `yaml:"-"`
+	ToMinute   int     `yaml:"-"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// validate parses the schedule and checks AutoNudge.
+func (q *QuietHours) validate() error {
+	for i := range q.Schedule {
+		if err := q.Schedule[i].parse(); err != nil {
+			return fmt.Errorf("schedule entry %d: %w", i+1, err)
+		}
+	}
+	q.AutoNudge = strings.ToLower(q.AutoNudge)
+	switch q.AutoNudge {
+	case "", "pause", "low", "medium":
+	default:
+		return fmt.Errorf("invalid auto_nudge %q (must be pause, low, or medium)", q.AutoNudge)
+	}
+	return nil
+}
+
+func (p *QuietPeriod) parse() error {
+	if len(p.Days) == 0 {
+		p.Weekdays = [7]bool{true, true, true, true, true, true, true}
+	}
+	for _, d := range p.Days {
+		wd, ok := weekdays[strings.ToLower(d)]
+		if !ok {
+			return fmt.Errorf("invalid day %q (use mon, tue, ..., sun)", d)
+		}
+		p.Weekdays[wd] = true
+	}
+	if p.From == "" && p.To == "" {
+		if len(p.Days) == 0 {
+			return fmt.Errorf("set from and to, or days")
+		}
+		p.FromMinute, p.ToMinute = 0, 24*60
+		return nil
+	}
+	var err error
+	if p.FromMinute, err = parseClock(p.From); err != nil {
+		return err
+	}
+	if p.ToMinute, err = parseClock(p.To); err != nil {
+		return err
+	}
+	if p.FromMinute == p.ToMinute {
+		return fmt.Errorf("from and to are both %s", p.From)
+	}
+	return nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Quiet reports whether t, in its own location, falls in the schedule.
+func (q QuietHours) Quiet(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	day, yesterday := t.Weekday(), (t.Weekday()+6)%7
+	for _, p := range q.Schedule {
+		switch {
+		case p.FromMinute < p.ToMinute:
+			if p.Weekdays[day] && minute >= p.FromMinute && minute < p.ToMinute {
+				return true
+			}
+		case p.Weekdays[day] && minute >= p.FromMinute, p.Weekdays[yesterday] && minute < p.ToMinute:
+			return true
+		}
+	}
+	return false
+}
+
+// Snippet is a canned answer that can be inserted into the supervisor's
+// text input instead of retyping the same guidance.
+//
+// Example:
+//
+//	snippets:
+//	  - name: tests first
+//	    text: Proceed, but write the tests first.
+//	  - text: Skip this and continue with the next step.
+//
+// The continuations section has the same shape.
+type Snippet struct {
+	// Name is shown in the picker. Default: the first line of Text.
+	Name string `yaml:"name"`
+	// Text is inserted into the input. May span several lines.
+	Text string `yaml:"text"`
+}
+
+// Label returns the name shown in the snippet picker.
+func (s Snippet) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(s.Text), "\n")
+	return first
+}
+
+// ThemeColors is a user-defined supervisor color theme. Colors are hex
+// ("#fab283", "#fb8") or ANSI color numbers ("208"); empty fields keep the
+// color of the base theme. Hex colors are approximated on terminals without
+// truecolor support.
+//
+// Example:
+//
+//	themes:
+//	  solarized:
+//	    base: dark
+//	    primary: "#b58900"
+//	    text: "#839496"
+//	    text_muted: "#586e75"
+type ThemeColors struct {
+	// Base is the builtin theme supplying unset colors: dark (default) or light.
+	Base string `yaml:"base"`
+
+	Primary         string `yaml:"primary"`
+	Secondary       string `yaml:"secondary"`
+	Accent          string `yaml:"accent"`
+	Error           string `yaml:"error"`
+	Warning         string `yaml:"warning"`
+	Success         string `yaml:"success"`
+	Info            string `yaml:"info"`
+	Text            string `yaml:"text"`
+	TextMuted       string `yaml:"text_muted"`
+	BackgroundPanel string `yaml:"background_panel"`
+	BackgroundElem  string `yaml:"background_elem"`
+	Border          string `yaml:"border"`
+	ActiveNumber    string `yaml:"active_number"`
+}
+
+// colors returns the color fields by YAML key, for validation.
+func (t ThemeColors) colors() map[string]string {
+	return map[string]string{
+		"primary":          t.Primary,
+		"secondary":        t.Secondary,
+		"accent":           t.Accent,
+		"error":            t.Error,
+		"warning":          t.Warning,
+		"success":          t.Success,
+		"info":             t.Info,
+		"text":             t.Text,
+		"text_muted":       t.TextMuted,
+		"background_panel": t.BackgroundPanel,
+		"background_elem":  t.BackgroundElem,
+		"border":           t.Border,
+		"active_number":    t.ActiveNumber,
+	}
+}
+
+// validColor reports whether s is a hex color (#rgb or #rrggbb) or an ANSI
+// color number (0-255).
+func validColor(s string) bool {
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		for _, c := range hex {
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return false
+			}
+		}
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// validateThemes checks the user-defined themes and the selected theme.
+func validateThemes(cfg *Config) error {
+	for name, t := range cfg.Themes {
+		if name == "dark" || name == "light" {
+			return fmt.Errorf("theme %q: name is reserved for the builtin theme", name)
+		}
+		if t.Base != "" && t.Base != "dark" && t.Base != "light" {
+			return fmt.Errorf("theme %q: invalid base %q (must be dark or light)", name, t.Base)
+		}
+		for key, c := range t.colors() {
+			if c != "" && !validColor(c) {
+				return fmt.Errorf("theme %q: invalid %s color %q (use #rrggbb or 0-255)", name, key, c)
+			}
+		}
+	}
+	if cfg.Theme != "" && cfg.Theme != "dark" && cfg.Theme != "light" {
+		if _, ok := cfg.Themes[cfg.Theme]; !ok {
+			return fmt.Errorf("unknown theme %q (must be dark, light, or defined under themes)", cfg.Theme)
+		}
+	}
+	return nil
 }
 
 // Defaults returns a Config with all default values.
 func Defaults() *Config {
 	return &Config{
-		Parallel: 10,
-		Refresh:  "5s",
-		CacheTTL: "2m",
+		Parallel:       10,
+		TmuxParallel:   4,
+		Refresh:        "5s",
+		CacheTTL:       "2m",
+		GitStatus:      "30s",
+		IdleAfter:      "10m",
+		HistoryMaxSize: "10MB",
 	}
 }
 
 // Load reads configuration from file and environment variables.
 // Environment variables always override file values.
 func Load() (*Config, error) {
+	return LoadProfile("")
+}
+
+// DefaultProfile names the configuration without any profile applied.
+const DefaultProfile = "default"
+
+// LoadProfile is Load with the named profile applied on top of the config
+// file, before environment variables. An empty name selects the profile
+// from PANE_PATROL_PROFILE or the file's profile setting, if any;
+// DefaultProfile selects none.
+func LoadProfile(name string) (*Config, error) {
 	cfg := Defaults()
 
 	// Try to load config file
+	var doc yaml.Node
 	if path, data, err := findConfigFile(); err == nil {
 		var fileCfg Config
 		if err := yaml.Unmarshal(data, &fileCfg); err != nil {
 			return nil, fmt.Errorf("parsing config file %s: %w", path, err)
 		}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
 		cfg.ConfigFile = path
+		cfg.Problems = Lint(data)
 		mergeFile(cfg, &fileCfg)
+		cfg.Profile = fileCfg.Profile
+		cfg.Profiles = fileCfg.Profiles
 	}
 
+	// Apply the profile between the file and the environment.
+	if name == "" {
+		name = os.Getenv("PANE_PATROL_PROFILE")
+	}
+	if name == "" {
+		name = cfg.Profile
+	}
+	if name != "" && name != DefaultProfile {
+		if _, ok := cfg.Profiles[name]; !ok {
+			return nil, fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(cfg.ProfileNames(), ", "))
+		}
+		var top *yaml.Node
+		if len(doc.Content) > 0 {
+			top = doc.Content[0]
+		}
+		if err := applyProfile(cfg, mappingValue(mappingValue(top, "profiles"), name)); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	cfg.Profile = name
+
 	// Environment variables override everything
 	mergeEnv(cfg)
 
@@ -87,8 +634,94 @@ func Load() (*Config, error) {
 		}
 	}
 
-	// Parse durations
+	if cfg.LogLevel != "" {
+		cfg.LogLevel = strings.ToLower(cfg.LogLevel)
+		if _, err := logging.ParseLevel(cfg.LogLevel); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.ClickAction != "" {
+		cfg.ClickAction = strings.ToLower(cfg.ClickAction)
+		if cfg.ClickAction != "jump" && cfg.ClickAction != "select" {
+			return nil, fmt.Errorf("invalid click_action %q (must be jump or select)", cfg.ClickAction)
+		}
+	}
+	if cfg.Layout != "" {
+		cfg.Layout = strings.ToLower(cfg.Layout)
+		if cfg.Layout != "bottom" && cfg.Layout != "right" {
+			return nil, fmt.Errorf("invalid layout %q (must be bottom or right)", cfg.Layout)
+		}
+	}
+
+	if cfg.ActionOrder != "" {
+		cfg.ActionOrder = strings.ToLower(cfg.ActionOrder)
+		switch cfg.ActionOrder {
+		case "dialog", "risk", "grouped":
+			// valid
+		default:
+			return nil, fmt.Errorf("invalid action_order %q (must be dialog, risk, or grouped)", cfg.ActionOrder)
+		}
+	}
+
+	if cfg.GroupBy != "" {
+		cfg.GroupBy = strings.ToLower(cfg.GroupBy)
+		switch cfg.GroupBy {
+		case "session", "directory", "repo":
+			// valid
+		default:
+			return nil, fmt.Errorf("invalid group_by %q (must be session, directory, or repo)", cfg.GroupBy)
+		}
+	}
+
+	if cfg.ColorProfile != "" {
+		cfg.ColorProfile = strings.ToLower(cfg.ColorProfile)
+		switch cfg.ColorProfile {
+		case "auto", "truecolor", "256", "16", "none":
+			// valid
+		default:
+			return nil, fmt.Errorf("invalid color_profile %q (must be auto, truecolor, 256, 16, or none)", cfg.ColorProfile)
+		}
+	}
+	if err := validateThemes(cfg); err != nil {
+		return nil, err
+	}
+
+	if _, ok := cfg.Profiles[DefaultProfile]; ok {
+		return nil, fmt.Errorf("profile name %q is reserved for the configuration without a profile", DefaultProfile)
+	}
+
+	for i, badge := range cfg.TmuxBadges {
+		cfg.TmuxBadges[i] = strings.ToLower(strings.TrimSpace(badge))
+		switch cfg.TmuxBadges[i] {
+		case "flag", "rename", "border":
+			// valid
+		default:
+			return nil, fmt.Errorf("invalid tmux_badges entry %q (must be flag, rename, or border)", badge)
+		}
+	}
+
+	for i, s := range cfg.Snippets {
+		if strings.TrimSpace(s.Text) == "" {
+			return nil, fmt.Errorf("snippet %d (%q): text is required", i+1, s.Name)
+		}
+	}
+	for i, s := range cfg.Continuations {
+		if strings.TrimSpace(s.Text) == "" {
+			return nil, fmt.Errorf("continuation %d (%q): text is required", i+1, s.Name)
+		}
+	}
+
 	var err error
+	if cfg.Filter != "" {
+		if cfg.FilterRegexp, err = regexp.Compile(cfg.Filter); err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %w", cfg.Filter, err)
+		}
+	}
+	if cfg.PaneFilter, err = NewPaneFilter(cfg.IncludePanes, cfg.ExcludePanes); err != nil {
+		return nil, err
+	}
+
+	// Parse durations
 	cfg.RefreshDuration, err = parseDurationOrDisable(cfg.Refresh, 30*time.Second)
 	if err != nil {
 		return nil, fmt.Errorf("invalid refresh interval %q: %w", cfg.Refresh, err)
@@ -97,10 +730,45 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid cache TTL %q: %w", cfg.CacheTTL, err)
 	}
+	cfg.GitStatusDuration, err = parseDurationOrDisable(cfg.GitStatus, 30*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("invalid git_status %q: %w", cfg.GitStatus, err)
+	}
+	cfg.IdleAfterDuration, err = parseDurationOrDisable(cfg.IdleAfter, 10*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("invalid idle_after %q: %w", cfg.IdleAfter, err)
+	}
+	cfg.HistoryMaxAgeDuration, err = ParseAge(cfg.HistoryMaxAge)
+	if err != nil {
+		return nil, fmt.Errorf("invalid history_max_age %q: %w", cfg.HistoryMaxAge, err)
+	}
+	cfg.HistoryMaxBytes, err = ParseSize(cfg.HistoryMaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid history_max_size %q: %w", cfg.HistoryMaxSize, err)
+	}
+	for i := range cfg.Escalations {
+		if err := cfg.Escalations[i].validate(); err != nil {
+			return nil, fmt.Errorf("escalation %d: %w", i+1, err)
+		}
+	}
+	if err := cfg.QuietHours.validate(); err != nil {
+		return nil, fmt.Errorf("quiet_hours: %w", err)
+	}
 
 	return cfg, nil
 }
 
+// ProfileNames returns DefaultProfile followed by the defined profiles in
+// alphabetical order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...)
+}
+
 // findConfigFile searches for a config file and returns its path and contents.
 func findConfigFile() (string, []byte, error) {
 	// 1. Current directory
@@ -109,8 +777,7 @@ func findConfigFile() (string, []byte, error) {
 	}
 
 	// 2. XDG config dir / ~/.config
-	if home, err := os.UserHomeDir(); err == nil {
-		path := filepath.Join(home, ".config", "pane-patrol", "config.yaml")
+	if path, err := UserConfigPath(); err == nil {
 		if data, err := os.ReadFile(path); err == nil {
 			return path, data, nil
 		}
@@ -127,27 +794,202 @@ func mergeFile(cfg *Config, file *Config) {
 	if file.Parallel > 0 {
 		cfg.Parallel = file.Parallel
 	}
+	if file.TmuxParallel > 0 {
+		cfg.TmuxParallel = file.TmuxParallel
+	}
 	if file.Refresh != "" {
 		cfg.Refresh = file.Refresh
 	}
 	if file.CacheTTL != "" {
 		cfg.CacheTTL = file.CacheTTL
 	}
+	if file.CacheFile != "" {
+		cfg.CacheFile = file.CacheFile
+	}
+	if file.GitStatus != "" {
+		cfg.GitStatus = file.GitStatus
+	}
+	if file.IdleAfter != "" {
+		cfg.IdleAfter = file.IdleAfter
+	}
+	if file.TaskCommand != "" {
+		cfg.TaskCommand = file.TaskCommand
+	}
 	if len(file.ExcludeSessions) > 0 {
 		cfg.ExcludeSessions = file.ExcludeSessions
 	}
+	if len(file.IncludePanes) > 0 {
+		cfg.IncludePanes = file.IncludePanes
+	}
+	if len(file.ExcludePanes) > 0 {
+		cfg.ExcludePanes = file.ExcludePanes
+	}
 	if file.AutoNudge {
 		cfg.AutoNudge = file.AutoNudge
 	}
 	if file.AutoNudgeMaxRisk != "" {
 		cfg.AutoNudgeMaxRisk = file.AutoNudgeMaxRisk
 	}
+	if file.ShowWaitingFor {
+		cfg.ShowWaitingFor = file.ShowWaitingFor
+	}
+	if file.ShowRecommended {
+		cfg.ShowRecommended = file.ShowRecommended
+	}
+	if file.Layout != "" {
+		cfg.Layout = file.Layout
+	}
+	if file.ActionOrder != "" {
+		cfg.ActionOrder = file.ActionOrder
+	}
+	if file.ClickAction != "" {
+		cfg.ClickAction = file.ClickAction
+	}
+	if file.GroupBy != "" {
+		cfg.GroupBy = file.GroupBy
+	}
+	if file.Theme != "" {
+		cfg.Theme = file.Theme
+	}
+	if file.ColorProfile != "" {
+		cfg.ColorProfile = file.ColorProfile
+	}
+	if file.Accessible {
+		cfg.Accessible = file.Accessible
+	}
+	if file.InlineLines > 0 {
+		cfg.InlineLines = file.InlineLines
+	}
+	if file.Speech {
+		cfg.Speech = file.Speech
+	}
+	if file.SpeechCommand != "" {
+		cfg.SpeechCommand = file.SpeechCommand
+	}
+	if file.TransitionCommand != "" {
+		cfg.TransitionCommand = file.TransitionCommand
+	}
+	if file.ControlSocket != "" {
+		cfg.ControlSocket = file.ControlSocket
+	}
+	if file.AnswersFile != "" {
+		cfg.AnswersFile = file.AnswersFile
+	}
+	if file.StateKeyCommand != "" {
+		cfg.StateKeyCommand = file.StateKeyCommand
+	}
+	if file.LabelsFile != "" {
+		cfg.LabelsFile = file.LabelsFile
+	}
+	if file.SyncPaneTitles {
+		cfg.SyncPaneTitles = file.SyncPaneTitles
+	}
+	if len(file.TmuxBadges) > 0 {
+		cfg.TmuxBadges = file.TmuxBadges
+	}
+	if file.TemplateDir != "" {
+		cfg.TemplateDir = file.TemplateDir
+	}
+	if file.ExportDir != "" {
+		cfg.ExportDir = file.ExportDir
+	}
+	if file.ConfirmHighRisk {
+		cfg.ConfirmHighRisk = true
+	}
+	if file.HistoryFile != "" {
+		cfg.HistoryFile = file.HistoryFile
+	}
+	if file.HistoryMaxAge != "" {
+		cfg.HistoryMaxAge = file.HistoryMaxAge
+	}
+	if file.HistoryMaxSize != "" {
+		cfg.HistoryMaxSize = file.HistoryMaxSize
+	}
+	if file.AuditFile != "" {
+		cfg.AuditFile = file.AuditFile
+	}
+	if file.AuditKeyCommand != "" {
+		cfg.AuditKeyCommand = file.AuditKeyCommand
+	}
+	if file.LogFile != "" {
+		cfg.LogFile = file.LogFile
+	}
+	if file.LogLevel != "" {
+		cfg.LogLevel = file.LogLevel
+	}
 	if file.OTELEndpoint != "" {
 		cfg.OTELEndpoint = file.OTELEndpoint
 	}
 	if file.OTELHeaders != "" {
 		cfg.OTELHeaders = file.OTELHeaders
 	}
+	if len(file.Parsers) > 0 {
+		cfg.Parsers = file.Parsers
+	}
+	if len(file.RiskRules) > 0 {
+		cfg.RiskRules = file.RiskRules
+	}
+	if len(file.IdleActions) > 0 {
+		cfg.IdleActions = file.IdleActions
+	}
+	if len(file.Escalations) > 0 {
+		cfg.Escalations = file.Escalations
+	}
+	if len(file.QuietHours.Schedule) > 0 {
+		cfg.QuietHours = file.QuietHours
+	}
+	if len(file.Snippets) > 0 {
+		cfg.Snippets = file.Snippets
+	}
+	if len(file.Continuations) > 0 {
+		cfg.Continuations = file.Continuations
+	}
+	if len(file.Themes) > 0 {
+		cfg.Themes = file.Themes
+	}
+}
+
+// applyProfile sets every key of a profile's mapping node onto cfg. Unlike
+// mergeFile it applies zero values too, so a profile can turn a boolean off
+// or clear a list; a key replaces the setting as a whole, maps included.
+// Profiles cannot select or define other profiles.
+func applyProfile(cfg *Config, node *yaml.Node) error {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	settings := &yaml.Node{Kind: yaml.MappingNode, Tag: node.Tag}
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if key == "profile" || key == "profiles" {
+			continue
+		}
+		if f := fieldByKey(v, key); f.IsValid() {
+			f.SetZero()
+		}
+		settings.Content = append(settings.Content, node.Content[i], node.Content[i+1])
+	}
+	return settings.Decode(cfg)
+}
+
+// fieldByKey returns the field of struct v decoded from key (yamlFields),
+// or the zero Value.
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if name == key {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
 }
 
 // mergeEnv applies environment variables onto cfg. Env always wins.
@@ -161,6 +1003,18 @@ func mergeEnv(cfg *Config) {
 	if v := os.Getenv("PANE_PATROL_CACHE_TTL"); v != "" {
 		cfg.CacheTTL = v
 	}
+	if v := os.Getenv("PANE_PATROL_CACHE_FILE"); v != "" {
+		cfg.CacheFile = v
+	}
+	if v := os.Getenv("PANE_PATROL_GIT_STATUS"); v != "" {
+		cfg.GitStatus = v
+	}
+	if v := os.Getenv("PANE_PATROL_IDLE_AFTER"); v != "" {
+		cfg.IdleAfter = v
+	}
+	if v := os.Getenv("PANE_PATROL_TASK_COMMAND"); v != "" {
+		cfg.TaskCommand = v
+	}
 	if v := os.Getenv("PANE_PATROL_EXCLUDE_SESSIONS"); v != "" {
 		cfg.ExcludeSessions = strings.Split(v, ",")
 	}
@@ -170,6 +1024,90 @@ func mergeEnv(cfg *Config) {
 	if v := os.Getenv("PANE_PATROL_AUTO_NUDGE_MAX_RISK"); v != "" {
 		cfg.AutoNudgeMaxRisk = v
 	}
+	if v := os.Getenv("PANE_PATROL_SHOW_WAITING_FOR"); v == "true" || v == "1" {
+		cfg.ShowWaitingFor = true
+	}
+	if v := os.Getenv("PANE_PATROL_SHOW_RECOMMENDED"); v == "true" || v == "1" {
+		cfg.ShowRecommended = true
+	}
+	if v := os.Getenv("PANE_PATROL_LAYOUT"); v != "" {
+		cfg.Layout = v
+	}
+	if v := os.Getenv("PANE_PATROL_ACTION_ORDER"); v != "" {
+		cfg.ActionOrder = v
+	}
+	if v := os.Getenv("PANE_PATROL_CLICK_ACTION"); v != "" {
+		cfg.ClickAction = v
+	}
+	if v := os.Getenv("PANE_PATROL_GROUP_BY"); v != "" {
+		cfg.GroupBy = v
+	}
+	if v := os.Getenv("PANE_PATROL_THEME"); v != "" {
+		cfg.Theme = v
+	}
+	if v := os.Getenv("PANE_PATROL_COLOR_PROFILE"); v != "" {
+		cfg.ColorProfile = v
+	}
+	if v := os.Getenv("PANE_PATROL_ACCESSIBLE"); v == "true" || v == "1" {
+		cfg.Accessible = true
+	}
+	if v := os.Getenv("PANE_PATROL_SPEECH"); v == "true" || v == "1" {
+		cfg.Speech = true
+	}
+	if v := os.Getenv("PANE_PATROL_SPEECH_COMMAND"); v != "" {
+		cfg.SpeechCommand = v
+	}
+	if v := os.Getenv("PANE_PATROL_TRANSITION_COMMAND"); v != "" {
+		cfg.TransitionCommand = v
+	}
+	if v := os.Getenv("PANE_PATROL_CONTROL_SOCKET"); v != "" {
+		cfg.ControlSocket = v
+	}
+	if v := os.Getenv("PANE_PATROL_ANSWERS_FILE"); v != "" {
+		cfg.AnswersFile = v
+	}
+	if v := os.Getenv("PANE_PATROL_STATE_KEY_COMMAND"); v != "" {
+		cfg.StateKeyCommand = v
+	}
+	if v := os.Getenv("PANE_PATROL_LABELS_FILE"); v != "" {
+		cfg.LabelsFile = v
+	}
+	if v := os.Getenv("PANE_PATROL_SYNC_PANE_TITLES"); v == "true" || v == "1" {
+		cfg.SyncPaneTitles = true
+	}
+	if v := os.Getenv("PANE_PATROL_TMUX_BADGES"); v != "" {
+		cfg.TmuxBadges = strings.Split(v, ",")
+	}
+	if v := os.Getenv("PANE_PATROL_TEMPLATE_DIR"); v != "" {
+		cfg.TemplateDir = v
+	}
+	if v := os.Getenv("PANE_PATROL_EXPORT_DIR"); v != "" {
+		cfg.ExportDir = v
+	}
+	if v := os.Getenv("PANE_PATROL_CONFIRM_HIGH_RISK"); v == "true" || v == "1" {
+		cfg.ConfirmHighRisk = true
+	}
+	if v := os.Getenv("PANE_PATROL_HISTORY_FILE"); v != "" {
+		cfg.HistoryFile = v
+	}
+	if v := os.Getenv("PANE_PATROL_HISTORY_MAX_AGE"); v != "" {
+		cfg.HistoryMaxAge = v
+	}
+	if v := os.Getenv("PANE_PATROL_HISTORY_MAX_SIZE"); v != "" {
+		cfg.HistoryMaxSize = v
+	}
+	if v := os.Getenv("PANE_PATROL_AUDIT_FILE"); v != "" {
+		cfg.AuditFile = v
+	}
+	if v := os.Getenv("PANE_PATROL_AUDIT_KEY_COMMAND"); v != "" {
+		cfg.AuditKeyCommand = v
+	}
+	if v := os.Getenv("PANE_PATROL_LOG_FILE"); v != "" {
+		cfg.LogFile = v
+	}
+	if v := os.Getenv("PANE_PATROL_LOG_LEVEL"); v != "" {
+		cfg.LogLevel = v
+	}
 	if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); v != "" {
 		cfg.OTELEndpoint = v
 	}
@@ -190,6 +1128,42 @@ func parseDurationOrDisable(s string, fallback time.Duration) (time.Duration, er
 	return time.ParseDuration(s)
 }
 
+// ParseAge parses a retention age: a duration such as "720h", or a number
+// of days such as "90d". "", "0", "off", and "disable" mean no limit (0).
+func ParseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("not a number of days")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return parseDurationOrDisable(s, 0)
+}
+
+// ParseSize parses a file size such as "10MB", "512KB", "1GB", or a number
+// of bytes. "", "0", "off", and "disable" mean no limit (0).
+func ParseSize(s string) (int64, error) {
+	if s == "" || s == "0" || s == "off" || s == "disable" {
+		return 0, nil
+	}
+	number, unit := strings.ToUpper(s), int64(1)
+	for _, u := range []struct {
+		suffix string
+		size   int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if rest, ok := strings.CutSuffix(number, u.suffix); ok {
+			number, unit = strings.TrimSpace(rest), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("not a size (e.g. 10MB)")
+	}
+	return n * unit, nil
+}
+
 // MatchesExcludeList checks if a name matches any pattern in the exclude list.
 // Patterns ending with * are treated as prefix matches (e.g. "AIGGTM-*").
 // All other patterns are exact matches.
@@ -205,3 +1179,121 @@ func MatchesExcludeList(name string, patterns []string) bool {
 	}
 	return false
 }
+
+// PaneMatch selects panes by window, command, or target. Every field that
+// is set must match; an empty PaneMatch matches nothing.
+type PaneMatch struct {
+	Window  string `yaml:"window"`  // Regex on the window name
+	Command string `yaml:"command"` // Regex on the pane's current command or any process running in it
+	Target  string `yaml:"target"`  // Glob (* and ?) on session:window.pane, with the window as index or name
+}
+
+// PaneFilter decides which panes are scanned, from include_panes and
+// exclude_panes. A nil *PaneFilter allows every pane.
+type PaneFilter struct {
+	include []paneMatcher
+	exclude []paneMatcher
+}
+
+// paneMatcher is a compiled PaneMatch; nil fields are not checked.
+type paneMatcher struct {
+	window  *regexp.Regexp
+	command *regexp.Regexp
+	target  *regexp.Regexp
+}
+
+// NewPaneFilter compiles include and exclude rules. It returns nil when
+// both are empty.
+func NewPaneFilter(include, exclude []PaneMatch) (*PaneFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	f := &PaneFilter{}
+	var err error
+	if f.include, err = compilePaneMatches("include_panes", include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compilePaneMatches("exclude_panes", exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func compilePaneMatches(key string, matches []PaneMatch) ([]paneMatcher, error) {
+	matchers := make([]paneMatcher, len(matches))
+	for i, pm := range matches {
+		if pm.Window == "" && pm.Command == "" && pm.Target == "" {
+			return nil, fmt.Errorf("%s entry %d: set at least one of window, command, or target", key, i+1)
+		}
+		var err error
+		m := &matchers[i]
+		if pm.Window != "" {
+			if m.window, err = regexp.Compile(pm.Window); err != nil {
+				return nil, fmt.Errorf("%s entry %d: invalid window pattern: %w", key, i+1, err)
+			}
+		}
+		if pm.Command != "" {
+			if m.command, err = regexp.Compile(pm.Command); err != nil {
+				return nil, fmt.Errorf("%s entry %d: invalid command pattern: %w", key, i+1, err)
+			}
+		}
+		if pm.Target != "" {
+			m.target = globRegexp(pm.Target)
+		}
+	}
+	return matchers, nil
+}
+
+// globRegexp turns a glob with * (any run of characters) and ? (one
+// character) into an anchored regex.
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Allows reports whether p should be scanned.
+func (f *PaneFilter) Allows(p model.Pane) bool {
+	if f == nil {
+		return true
+	}
+	for _, m := range f.exclude {
+		if m.matches(p) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, m := range f.include {
+		if m.matches(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m paneMatcher) matches(p model.Pane) bool {
+	if m.window != nil && !m.window.MatchString(p.WindowName) {
+		return false
+	}
+	if m.command != nil && !m.command.MatchString(p.Command) && !slices.ContainsFunc(p.ProcessTree, m.command.MatchString) {
+		return false
+	}
+	if m.target != nil && !m.target.MatchString(p.Target) &&
+		!m.target.MatchString(fmt.Sprintf("%s:%s.%d", p.Session, p.WindowName, p.Pane)) {
+		return false
+	}
+	return true
+}
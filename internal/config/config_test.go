@@ -1,9 +1,15 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
 )
 
 func TestDefaults(t *testing.T) {
@@ -229,3 +235,926 @@ refresh: "10s"
 		t.Errorf("Parallel: got %d, want %d (file value should be kept)", cfg.Parallel, 5)
 	}
 }
+
+func TestLoadCustomParsers(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, ".pane-patrol.yaml")
+	content := `parsers:
+  - name: acme_agent
+    identify:
+      process: ["acme-agent"]
+    blocked:
+      - pattern: "Approve (\\w+)\\?"
+        reason: "approval for $1"
+        actions:
+          - {keys: "y", label: "approve", risk: medium, raw: true}
+          - {keys: "n", label: "deny", risk: low, raw: true}
+        recommended: 1
+    active: ["Thinking"]
+`
+	if err := os.WriteFile(cfgPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if len(cfg.Parsers) != 1 {
+		t.Fatalf("Parsers: got %d entries, want 1", len(cfg.Parsers))
+	}
+	p := cfg.Parsers[0]
+	if p.Name != "acme_agent" || len(p.Identify.Process) != 1 || len(p.Active) != 1 {
+		t.Errorf("unexpected parser definition: %+v", p)
+	}
+	if len(p.Blocked) != 1 || p.Blocked[0].Recommended != 1 {
+		t.Fatalf("unexpected blocked rules: %+v", p.Blocked)
+	}
+	a := p.Blocked[0].Actions[0]
+	if a.Keys != "y" || a.Risk != "medium" || !a.Raw {
+		t.Errorf("action decoded as %+v, want keys=y risk=medium raw=true", a)
+	}
+}
+
+func TestLoadSnippets(t *testing.T) {
+	dir := t.TempDir()
+	content := `snippets:
+  - name: tests first
+    text: Proceed, but write the tests first.
+  - text: |
+      Skip this and continue.
+      Note what you skipped in the summary.
+`
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.Snippets) != 2 {
+		t.Fatalf("Snippets: got %d entries, want 2", len(cfg.Snippets))
+	}
+	if got := cfg.Snippets[0].Label(); got != "tests first" {
+		t.Errorf("Label(): got %q, want %q", got, "tests first")
+	}
+	if got := cfg.Snippets[1].Label(); got != "Skip this and continue." {
+		t.Errorf("Label() without name: got %q, want first line", got)
+	}
+}
+
+func TestLoadSnippets_EmptyText(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("snippets:\n  - name: oops\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "text is required") {
+		t.Errorf("expected error for snippet without text, got %v", err)
+	}
+}
+
+func TestLoadContinuations(t *testing.T) {
+	dir := t.TempDir()
+	content := `continuations:
+  - name: keep going
+    text: Continue with the next step of the plan.
+  - text: Run the tests and fix what fails.
+`
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.Continuations) != 2 || cfg.Continuations[0].Label() != "keep going" {
+		t.Fatalf("Continuations: got %+v", cfg.Continuations)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("continuations:\n  - name: oops\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "continuation 1") {
+		t.Errorf("expected error for continuation without text, got %v", err)
+	}
+}
+
+func TestLoadEscalations(t *testing.T) {
+	dir := t.TempDir()
+	content := `escalations:
+  - after: 10m
+    command: ./notify-slack.sh
+  - after: 5m
+    risk: High
+    command: ./page-me.sh
+`
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cfg.Escalations) != 2 {
+		t.Fatalf("Escalations: got %+v", cfg.Escalations)
+	}
+	if e := cfg.Escalations[0]; e.AfterDuration != 10*time.Minute || e.Risk != "low" {
+		t.Errorf("first escalation: got %+v", e)
+	}
+	if e := cfg.Escalations[1]; e.AfterDuration != 5*time.Minute || e.Risk != "high" {
+		t.Errorf("second escalation: got %+v", e)
+	}
+
+	for _, bad := range []string{
+		"escalations:\n  - after: soon\n    command: x\n",
+		"escalations:\n  - after: 5m\n",
+		"escalations:\n  - after: 5m\n    command: x\n    risk: urgent\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "escalation 1") {
+			t.Errorf("%q: expected an escalation error, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadQuietHours(t *testing.T) {
+	dir := t.TempDir()
+	content := `quiet_hours:
+  schedule:
+    - from: "23:00"
+      to: "08:00"
+    - days: [Sat, sun]
+    - days: [wed]
+      from: "12:00"
+      to: "13:00"
+  auto_nudge: Pause
+`
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.QuietHours.AutoNudge != "pause" {
+		t.Errorf("AutoNudge: got %q", cfg.QuietHours.AutoNudge)
+	}
+	// 2026-05-06 is a Wednesday.
+	at := func(day int, clock string) time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", fmt.Sprintf("2026-05-%02d %s", day, clock))
+		return t
+	}
+	for _, tc := range []struct {
+		t     time.Time
+		quiet bool
+	}{
+		{at(6, "22:59"), false},
+		{at(6, "23:00"), true},
+		{at(7, "07:59"), true},
+		{at(7, "08:00"), false},
+		{at(6, "12:30"), true},
+		{at(7, "12:30"), false},
+		{at(9, "15:00"), true},   // Saturday
+		{at(11, "15:00"), false}, // Monday
+	} {
+		if got := cfg.QuietHours.Quiet(tc.t); got != tc.quiet {
+			t.Errorf("Quiet(%s) = %v, want %v", tc.t.Format("Mon 15:04"), got, tc.quiet)
+		}
+	}
+
+	for _, bad := range []string{
+		"quiet_hours:\n  schedule:\n    - from: \"23:00\"\n",
+		"quiet_hours:\n  schedule:\n    - days: [someday]\n",
+		"quiet_hours:\n  schedule:\n    - from: \"25:00\"\n      to: \"08:00\"\n",
+		"quiet_hours:\n  schedule:\n    - days: [sun]\n  auto_nudge: high\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "quiet_hours") {
+			t.Errorf("%q: expected a quiet_hours error, got %v", bad, err)
+		}
+	}
+}
+
+func TestLoadLayout(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("layout: Right\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Layout != "right" {
+		t.Errorf("Layout: got %q, want %q", cfg.Layout, "right")
+	}
+
+	t.Setenv("PANE_PATROL_LAYOUT", "sideways")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid layout") {
+		t.Errorf("expected error for invalid layout, got %v", err)
+	}
+}
+
+func TestLoadActionOrder(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("action_order: Grouped\n"), 0644)
+	if cfg, err := Load(); err != nil || cfg.ActionOrder != "grouped" {
+		t.Errorf("from file: got %q, %v", cfg.ActionOrder, err)
+	}
+	t.Setenv("PANE_PATROL_ACTION_ORDER", "alphabetical")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid action_order") {
+		t.Errorf("expected an invalid action_order error, got %v", err)
+	}
+}
+
+func TestLoadClickAction(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("click_action: Select\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.ClickAction != "select" {
+		t.Errorf("ClickAction: got %q, want %q", cfg.ClickAction, "select")
+	}
+
+	t.Setenv("PANE_PATROL_CLICK_ACTION", "hover")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid click_action") {
+		t.Errorf("expected error for invalid click_action, got %v", err)
+	}
+}
+
+func TestLoadLogLevel(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("PANE_PATROL_LOG_LEVEL", "DEBUG")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("LogLevel: got %q, want %q", cfg.LogLevel, "debug")
+	}
+
+	t.Setenv("PANE_PATROL_LOG_LEVEL", "chatty")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid log level") {
+		t.Errorf("expected error for invalid log level, got %v", err)
+	}
+}
+
+func TestStarterLoads(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".pane-patrol.yaml")
+	s := Starter{
+		ExcludeSessions:  []string{"private", "scratch-*"},
+		AutoNudge:        true,
+		AutoNudgeMaxRisk: "medium",
+		ShowWaitingFor:   true,
+		OTELEndpoint:     "http://localhost:3000/api/public/otel",
+		OTELHeaders:      "Authorization=Basic abc",
+	}
+	if err := WriteStarter(path, s, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteStarter(path, s, false); err == nil {
+		t.Error("WriteStarter replaced an existing file without force")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("file with OTEL headers should be 0600, got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !slices.Equal(cfg.ExcludeSessions, s.ExcludeSessions) {
+		t.Errorf("ExcludeSessions: got %v, want %v", cfg.ExcludeSessions, s.ExcludeSessions)
+	}
+	if !cfg.AutoNudge || cfg.AutoNudgeMaxRisk != "medium" || !cfg.ShowWaitingFor {
+		t.Errorf("auto-nudge/display settings not loaded: %+v", cfg)
+	}
+	if cfg.OTELEndpoint != s.OTELEndpoint || cfg.OTELHeaders != s.OTELHeaders {
+		t.Errorf("OTEL settings: got %q %q", cfg.OTELEndpoint, cfg.OTELHeaders)
+	}
+	if cfg.Refresh != Defaults().Refresh {
+		t.Errorf("Refresh: got %q, want the default", cfg.Refresh)
+	}
+
+	// The defaults-only starter is all comments and loads as the defaults.
+	if err := WriteStarter(path, Starter{}, true); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.AutoNudge || len(cfg.ExcludeSessions) != 0 || cfg.OTELEndpoint != "" {
+		t.Errorf("empty starter should load the defaults, got %+v", cfg)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	dir := t.TempDir()
+	content := `theme: solarized
+color_profile: "256"
+themes:
+  solarized:
+    base: light
+    primary: "#b58900"
+    text_muted: "245"
+`
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Theme != "solarized" || cfg.ColorProfile != "256" {
+		t.Errorf("Theme/ColorProfile: got %q/%q", cfg.Theme, cfg.ColorProfile)
+	}
+	if got := cfg.Themes["solarized"]; got.Base != "light" || got.Primary != "#b58900" || got.TextMuted != "245" {
+		t.Errorf("Themes[solarized]: got %+v", got)
+	}
+}
+
+func TestLoadThemes_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"bad color", "themes:\n  x:\n    primary: orange\n", `invalid primary color "orange"`},
+		{"bad hex", "themes:\n  x:\n    text: \"#12345\"\n", `invalid text color`},
+		{"ansi out of range", "themes:\n  x:\n    border: \"256\"\n", `invalid border color`},
+		{"bad base", "themes:\n  x:\n    base: blue\n", `invalid base "blue"`},
+		{"reserved name", "themes:\n  dark:\n    primary: \"#fff\"\n", "reserved"},
+		{"unknown theme", "theme: nope\n", `unknown theme "nope"`},
+		{"bad profile", "color_profile: rgb\n", `invalid color_profile "rgb"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			origDir, _ := os.Getwd()
+			defer os.Chdir(origDir)
+			os.Chdir(dir)
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadGroupBy(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	t.Setenv("PANE_PATROL_GROUP_BY", "Repo")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GroupBy != "repo" {
+		t.Errorf("GroupBy: got %q, want %q", cfg.GroupBy, "repo")
+	}
+
+	t.Setenv("PANE_PATROL_GROUP_BY", "window")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid group_by") {
+		t.Errorf("expected error for invalid group_by, got %v", err)
+	}
+}
+
+func TestLoadConfirmHighRiskAndHistoryFile(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("confirm_high_risk: true\nhistory_file: /tmp/actions.jsonl\n"), 0644)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !cfg.ConfirmHighRisk || cfg.HistoryFile != "/tmp/actions.jsonl" {
+		t.Errorf("got ConfirmHighRisk=%v HistoryFile=%q", cfg.ConfirmHighRisk, cfg.HistoryFile)
+	}
+
+	t.Setenv("PANE_PATROL_HISTORY_FILE", "off")
+	if cfg, _ := Load(); cfg.HistoryFile != "off" {
+		t.Errorf("HistoryFile: got %q, want off", cfg.HistoryFile)
+	}
+}
+
+func TestLoadAuditFile(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	if cfg, _ := Load(); cfg.AuditFile != "" {
+		t.Errorf("the audit log should be off by default, got %q", cfg.AuditFile)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("audit_file: /tmp/audit.jsonl\n"), 0644)
+	if cfg, _ := Load(); cfg.AuditFile != "/tmp/audit.jsonl" {
+		t.Errorf("AuditFile: got %q", cfg.AuditFile)
+	}
+	t.Setenv("PANE_PATROL_AUDIT_FILE", "/var/log/pp.jsonl")
+	if cfg, _ := Load(); cfg.AuditFile != "/var/log/pp.jsonl" {
+		t.Errorf("AuditFile from env: got %q", cfg.AuditFile)
+	}
+	t.Setenv("PANE_PATROL_AUDIT_KEY_COMMAND", "pass show pane-patrol-audit")
+	if cfg, _ := Load(); cfg.AuditKeyCommand != "pass show pane-patrol-audit" {
+		t.Errorf("AuditKeyCommand from env: got %q", cfg.AuditKeyCommand)
+	}
+}
+
+func TestLoadGitStatus(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	if cfg, _ := Load(); cfg.GitStatusDuration != 30*time.Second {
+		t.Errorf("default: got %v, want 30s", cfg.GitStatusDuration)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("git_status: 1m\n"), 0644)
+	if cfg, _ := Load(); cfg.GitStatusDuration != time.Minute {
+		t.Errorf("from file: got %v, want 1m", cfg.GitStatusDuration)
+	}
+	t.Setenv("PANE_PATROL_GIT_STATUS", "off")
+	if cfg, _ := Load(); cfg.GitStatusDuration != 0 {
+		t.Errorf("off: got %v, want 0", cfg.GitStatusDuration)
+	}
+	t.Setenv("PANE_PATROL_GIT_STATUS", "soon")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "git_status") {
+		t.Errorf("expected an invalid git_status error, got %v", err)
+	}
+}
+
+func TestLoadIdleAfterAndTaskCommand(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, _ := Load()
+	if cfg.IdleAfterDuration != 10*time.Minute || cfg.TaskCommand != "" {
+		t.Errorf("defaults: got %v, %q", cfg.IdleAfterDuration, cfg.TaskCommand)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("idle_after: 30m\ntask_command: ./next-ticket.sh\n"), 0644)
+	if cfg, _ := Load(); cfg.IdleAfterDuration != 30*time.Minute || cfg.TaskCommand != "./next-ticket.sh" {
+		t.Errorf("from file: got %v, %q", cfg.IdleAfterDuration, cfg.TaskCommand)
+	}
+	t.Setenv("PANE_PATROL_IDLE_AFTER", "off")
+	t.Setenv("PANE_PATROL_TASK_COMMAND", "jira next")
+	if cfg, _ := Load(); cfg.IdleAfterDuration != 0 || cfg.TaskCommand != "jira next" {
+		t.Errorf("from env: got %v, %q", cfg.IdleAfterDuration, cfg.TaskCommand)
+	}
+}
+
+func TestLoadControlSocket(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	if cfg, _ := Load(); cfg.ControlSocket != "" {
+		t.Errorf("default: got %q, want the default path", cfg.ControlSocket)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("control_socket: off\n"), 0644)
+	if cfg, _ := Load(); cfg.ControlSocket != "off" {
+		t.Errorf("from file: got %q", cfg.ControlSocket)
+	}
+	t.Setenv("PANE_PATROL_CONTROL_SOCKET", "~/.cache/pane-patrol.sock")
+	if cfg, _ := Load(); cfg.ControlSocket != "~/.cache/pane-patrol.sock" {
+		t.Errorf("from env: got %q", cfg.ControlSocket)
+	}
+}
+
+func TestLoadStateKeyCommand(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("state_key_command: pass show pane-patrol\n"), 0644)
+	if cfg, _ := Load(); cfg.StateKeyCommand != "pass show pane-patrol" {
+		t.Errorf("from file: got %q", cfg.StateKeyCommand)
+	}
+	t.Setenv("PANE_PATROL_STATE_KEY_COMMAND", "secret-tool lookup app pane-patrol")
+	if cfg, _ := Load(); cfg.StateKeyCommand != "secret-tool lookup app pane-patrol" {
+		t.Errorf("from env: got %q", cfg.StateKeyCommand)
+	}
+}
+
+func TestLoadHistoryRetention(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, _ := Load()
+	if cfg.HistoryMaxAgeDuration != 0 || cfg.HistoryMaxBytes != 10<<20 {
+		t.Errorf("defaults: got %v, %d", cfg.HistoryMaxAgeDuration, cfg.HistoryMaxBytes)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("history_max_age: 90d\nhistory_max_size: 512KB\n"), 0644)
+	if cfg, _ := Load(); cfg.HistoryMaxAgeDuration != 90*24*time.Hour || cfg.HistoryMaxBytes != 512<<10 {
+		t.Errorf("from file: got %v, %d", cfg.HistoryMaxAgeDuration, cfg.HistoryMaxBytes)
+	}
+	t.Setenv("PANE_PATROL_HISTORY_MAX_AGE", "72h")
+	t.Setenv("PANE_PATROL_HISTORY_MAX_SIZE", "off")
+	if cfg, _ := Load(); cfg.HistoryMaxAgeDuration != 72*time.Hour || cfg.HistoryMaxBytes != 0 {
+		t.Errorf("from env: got %v, %d", cfg.HistoryMaxAgeDuration, cfg.HistoryMaxBytes)
+	}
+	t.Setenv("PANE_PATROL_HISTORY_MAX_SIZE", "lots")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "history_max_size") {
+		t.Errorf("expected an invalid history_max_size error, got %v", err)
+	}
+}
+
+func TestLoadShowRecommended(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	if cfg, _ := Load(); cfg.ShowRecommended {
+		t.Error("ShowRecommended should be off by default")
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("show_recommended: true\n"), 0644)
+	if cfg, _ := Load(); !cfg.ShowRecommended {
+		t.Error("ShowRecommended not loaded from the file")
+	}
+
+	os.Remove(filepath.Join(dir, ".pane-patrol.yaml"))
+	t.Setenv("PANE_PATROL_SHOW_RECOMMENDED", "1")
+	if cfg, _ := Load(); !cfg.ShowRecommended {
+		t.Error("ShowRecommended not set from PANE_PATROL_SHOW_RECOMMENDED")
+	}
+}
+
+func TestLoadTmuxBadges(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("tmux_badges: [flag, Border]\n"), 0644)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if strings.Join(cfg.TmuxBadges, ",") != "flag,border" {
+		t.Errorf("TmuxBadges: got %v", cfg.TmuxBadges)
+	}
+
+	t.Setenv("PANE_PATROL_TMUX_BADGES", "rename,blink")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "invalid tmux_badges") {
+		t.Errorf("expected error for invalid tmux_badges, got %v", err)
+	}
+}
+
+func TestLoadRiskRules(t *testing.T) {
+	dir := t.TempDir()
+	content := `risk_rules:
+  - waiting_for: "rm -rf"
+    risk: high
+  - agent: claude_code
+    action: "(?i)always"
+    risk: high
+`
+	if err := os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := []RiskRule{
+		{WaitingFor: "rm -rf", Risk: "high"},
+		{Agent: "claude_code", Action: "(?i)always", Risk: "high"},
+	}
+	if len(cfg.RiskRules) != len(want) || cfg.RiskRules[0] != want[0] || cfg.RiskRules[1] != want[1] {
+		t.Errorf("RiskRules: got %+v, want %+v", cfg.RiskRules, want)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	content := `refresh: 5s
+exclude_sessions: [scratch]
+profile: work
+profiles:
+  work:
+    filter: "^work-"
+    auto_nudge: true
+  ops:
+    exclude_sessions: [dev]
+    refresh: 30s
+`
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644)
+	t.Setenv("PANE_PATROL_PROFILE", "")
+
+	// The file's profile applies by default, on top of the base settings.
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Profile != "work" || cfg.Filter != "^work-" || !cfg.AutoNudge || cfg.Refresh != "5s" {
+		t.Errorf("work profile: got profile=%q filter=%q auto_nudge=%v refresh=%q", cfg.Profile, cfg.Filter, cfg.AutoNudge, cfg.Refresh)
+	}
+	if strings.Join(cfg.ProfileNames(), ",") != "default,ops,work" {
+		t.Errorf("ProfileNames: got %v", cfg.ProfileNames())
+	}
+
+	// PANE_PATROL_PROFILE overrides the file; an explicit name overrides both.
+	t.Setenv("PANE_PATROL_PROFILE", "ops")
+	cfg, err = LoadProfile("")
+	if err != nil {
+		t.Fatalf("LoadProfile() error: %v", err)
+	}
+	if cfg.Profile != "ops" || cfg.Refresh != "30s" || strings.Join(cfg.ExcludeSessions, ",") != "dev" || cfg.AutoNudge {
+		t.Errorf("ops profile: got %+v", cfg)
+	}
+	cfg, err = LoadProfile(DefaultProfile)
+	if err != nil {
+		t.Fatalf("LoadProfile(default) error: %v", err)
+	}
+	if cfg.Filter != "" || strings.Join(cfg.ExcludeSessions, ",") != "scratch" {
+		t.Errorf("default profile: got filter=%q exclude=%v", cfg.Filter, cfg.ExcludeSessions)
+	}
+
+	if _, err := LoadProfile("home"); err == nil || !strings.Contains(err.Error(), `unknown profile "home" (defined: default, ops, work)`) {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestLoadProfile_ExplicitZeroValues(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	content := `auto_nudge: true
+confirm_high_risk: true
+exclude_sessions: [scratch]
+themes:
+  mine: {primary: "#ff0000"}
+profiles:
+  quiet:
+    auto_nudge: false
+    confirm_high_risk: false
+    exclude_sessions: []
+    themes:
+      other: {primary: "#0000ff"}
+    profile: quiet
+`
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644)
+	t.Setenv("PANE_PATROL_PROFILE", "")
+
+	cfg, err := LoadProfile("quiet")
+	if err != nil {
+		t.Fatalf("LoadProfile(quiet) error: %v", err)
+	}
+	if cfg.AutoNudge || cfg.ConfirmHighRisk || len(cfg.ExcludeSessions) != 0 {
+		t.Errorf("quiet profile: got auto_nudge=%v confirm_high_risk=%v exclude=%v", cfg.AutoNudge, cfg.ConfirmHighRisk, cfg.ExcludeSessions)
+	}
+	if _, ok := cfg.Themes["mine"]; ok || len(cfg.Themes) != 1 {
+		t.Errorf("themes: a profile's map should replace the file's, got %v", cfg.Themes)
+	}
+	if cfg.Profile != "quiet" || len(cfg.Profiles) != 1 {
+		t.Errorf("profile: got %q with %d profiles", cfg.Profile, len(cfg.Profiles))
+	}
+
+	cfg, err = LoadProfile(DefaultProfile)
+	if err != nil {
+		t.Fatalf("LoadProfile(default) error: %v", err)
+	}
+	if !cfg.AutoNudge || !cfg.ConfirmHighRisk || strings.Join(cfg.ExcludeSessions, ",") != "scratch" {
+		t.Errorf("default profile: got auto_nudge=%v confirm_high_risk=%v exclude=%v", cfg.AutoNudge, cfg.ConfirmHighRisk, cfg.ExcludeSessions)
+	}
+}
+
+func TestLoadProfile_ReservedName(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("profiles:\n  default:\n    filter: x\n"), 0644)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected reserved name error, got %v", err)
+	}
+}
+
+func TestPaneFilter(t *testing.T) {
+	f, err := NewPaneFilter(
+		[]PaneMatch{{Command: "^(opencode|codex|claude)$"}, {Window: "^agent"}},
+		[]PaneMatch{{Target: "*:logs.*"}, {Target: "scratch:?.1"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		pane model.Pane
+		want bool
+	}{
+		{"included command", model.Pane{Target: "dev:0.0", Session: "dev", WindowName: "main", Command: "codex"}, true},
+		{"command in process tree", model.Pane{Target: "dev:0.1", Session: "dev", Pane: 1, WindowName: "main", Command: "node", ProcessTree: []string{"claude"}}, true},
+		{"included window", model.Pane{Target: "dev:1.0", Session: "dev", WindowName: "agents-2", Command: "bash"}, true},
+		{"neither included", model.Pane{Target: "dev:2.0", Session: "dev", WindowName: "main", Command: "bash"}, false},
+		{"excluded by window name in target", model.Pane{Target: "dev:3.0", Session: "dev", WindowName: "logs", Command: "codex"}, false},
+		{"excluded by index target", model.Pane{Target: "scratch:4.1", Session: "scratch", Pane: 1, WindowName: "main", Command: "codex"}, false},
+		{"index glob needs one character", model.Pane{Target: "scratch:14.1", Session: "scratch", Pane: 1, WindowName: "main", Command: "codex"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Allows(tt.pane); got != tt.want {
+				t.Errorf("Allows(%+v) = %v, want %v", tt.pane, got, tt.want)
+			}
+		})
+	}
+
+	var none *PaneFilter
+	if !none.Allows(model.Pane{Target: "dev:0.0"}) {
+		t.Error("a nil filter should allow every pane")
+	}
+}
+
+func TestLoadPaneFilter(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	content := `exclude_panes:
+  - target: "*:logs.*"
+  - window: "^scratch$"
+    command: bash
+`
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte(content), 0644)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.PaneFilter == nil || cfg.PaneFilter.Allows(model.Pane{Target: "dev:1.0", Session: "dev", WindowName: "logs"}) {
+		t.Error("expected the logs window to be excluded")
+	}
+	if !cfg.PaneFilter.Allows(model.Pane{Target: "dev:2.0", Session: "dev", WindowName: "scratch", Command: "vim"}) {
+		t.Error("expected both fields of a rule to have to match")
+	}
+
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("include_panes:\n  - command: \"(codex\"\n"), 0644)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "include_panes entry 1: invalid command pattern") {
+		t.Errorf("expected invalid pattern error, got %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("filter: \"(ops\"\n"), 0644)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), `invalid filter "(ops"`) {
+		t.Errorf("expected invalid filter error, got %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("filter: \"^ops-\"\n"), 0644)
+	if cfg, err := Load(); err != nil || !cfg.FilterRegexp.MatchString("ops-api") || cfg.FilterRegexp.MatchString("dev") {
+		t.Errorf("compiled filter: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("exclude_panes:\n  - {}\n"), 0644)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "set at least one of") {
+		t.Errorf("expected empty rule error, got %v", err)
+	}
+}
+
+func TestLint(t *testing.T) {
+	data := `refersh: 10s
+auto_nudge_max_risk: high
+confirm_high_risk: true
+speech_command: espeak
+parsers:
+  - name: acme
+    identify:
+      process: ["acme("]
+    blocked:
+      - pattern: "Approve (\\w+)\\?"
+        actions:
+          - {keys: y, label: approve, risk: low, follow_up: true}
+include_panes:
+  - window: "[logs"
+profiles:
+  night:
+    layot: right
+    speech: true
+    speech_command: say
+    filter: "(ops"
+`
+	var got []string
+	for _, p := range Lint([]byte(data)) {
+		got = append(got, p.String())
+	}
+	want := []string{
+		`1:1: unknown key "refersh" (did you mean "refresh"?)`,
+		`2:22: auto_nudge_max_risk: high lets auto-nudge send high-risk actions without the confirmation confirm_high_risk asks for`,
+		`4:17: speech_command has no effect without speech: true`,
+		"8:17: process: invalid regular expression \"acme(\": error parsing regexp: missing closing ): `acme(`",
+		`12:50: unknown key "follow_up" (did you mean "followup"?)`,
+		"14:13: window: invalid regular expression \"[logs\": error parsing regexp: missing closing ]: `[logs`",
+		`17:5: unknown key "layot" (did you mean "layout"?)`,
+		"20:13: filter: invalid regular expression \"(ops\": error parsing regexp: missing closing ): `(ops`",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if problems := Lint([]byte("refresh: 10s\nprofiles:\n  quiet:\n    speech: false\n")); len(problems) != 0 {
+		t.Errorf("a clean file: got %v", problems)
+	}
+	if problems := Lint([]byte("refresh: [10s\n")); len(problems) != 1 || problems[0].Line != 0 {
+		t.Errorf("invalid YAML: got %v", problems)
+	}
+}
+
+func TestLoadReportsLintProblems(t *testing.T) {
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(dir)
+
+	os.WriteFile(filepath.Join(dir, ".pane-patrol.yaml"), []byte("refresh: 10s\ncache_tl: 1m\n"), 0644)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unknown keys should not fail Load: %v", err)
+	}
+	if len(cfg.Problems) != 1 || cfg.Problems[0].Line != 2 || !strings.Contains(cfg.Problems[0].Message, `"cache_ttl"`) {
+		t.Errorf("problems: %v", cfg.Problems)
+	}
+}
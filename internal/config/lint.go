@@ -0,0 +1,250 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is something Lint found in a config file: a key Load would
+// ignore, a pattern that does not compile, or settings that contradict
+// each other. Line and Column are 1-based, 0 when unknown.
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+// regexpKeys are the settings holding Go regular expressions, as paths of
+// keys ("[]" for a list entry), relative to the top level or a profile.
+var regexpKeys = map[string]bool{
+	"filter":                       true,
+	"parsers[].identify.process[]": true,
+	"parsers[].identify.content[]": true,
+	"parsers[].blocked[].pattern":  true,
+	"parsers[].active[]":           true,
+	"risk_rules[].action":          true,
+	"risk_rules[].waiting_for":     true,
+	"include_panes[].window":       true,
+	"include_panes[].command":      true,
+	"exclude_panes[].window":       true,
+	"exclude_panes[].command":      true,
+}
+
+// Lint checks the contents of a config file for what Load accepts without
+// complaint but is most likely a mistake: unknown keys (typos, or settings
+// from another version), patterns that are not valid regular expressions,
+// and settings that contradict each other or have no effect. Values Load
+// rejects (an unknown layout, a bad duration) are left to Load.
+func Lint(data []byte) []Problem {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Problem{{Message: err.Error()}}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	var problems []Problem
+	lintNode(doc.Content[0], reflect.TypeOf(Config{}), "", &problems)
+	top := doc.Content[0]
+	lintConflicts(top, nil, &problems)
+	for _, profile := range mappingValues(mappingValue(top, "profiles")) {
+		lintConflicts(profile, top, &problems)
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+// lintNode checks node against t, the type it is decoded into; path is the
+// node's key path relative to the top level or a profile.
+func lintNode(node *yaml.Node, t reflect.Type, path string, problems *[]Problem) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				*problems = append(*problems, Problem{key.Line, key.Column, unknownKey(key.Value, fields)})
+				continue
+			}
+			childPath := joinPath(path, key.Value)
+			if key.Value == "profiles" && path == "" {
+				// Profiles hold the same settings as the top level.
+				childPath = ""
+			}
+			lintNode(value, field, childPath, problems)
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 1; i < len(node.Content); i += 2 {
+			lintNode(node.Content[i], t.Elem(), path, problems)
+		}
+	case node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for _, item := range node.Content {
+			lintNode(item, t.Elem(), path+"[]", problems)
+		}
+	case node.Kind == yaml.ScalarNode && regexpKeys[path]:
+		if _, err := regexp.Compile(node.Value); err != nil {
+			*problems = append(*problems, Problem{node.Line, node.Column,
+				fmt.Sprintf("%s: invalid regular expression %q: %v", lastKey(path), node.Value, err)})
+		}
+	}
+}
+
+// yamlFields maps the keys a struct is decoded from to their field types.
+// As in yaml.v3, a field without a yaml tag is decoded from its lowercased
+// name.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// unknownKey describes an unknown key, suggesting a known key it is
+// probably a typo of.
+func unknownKey(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown key %q (did you mean %q?)", key, best)
+	}
+	return fmt.Sprintf("unknown key %q", key)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// lintConflicts reports settings of one level, the top level or a profile
+// (on top of base, the top level), that contradict each other or have no
+// effect. Only settings set on this level are reported.
+func lintConflicts(node, base *yaml.Node, problems *[]Problem) {
+	setting := func(key string) *yaml.Node {
+		if v := mappingValue(node, key); v != nil {
+			return v
+		}
+		return mappingValue(base, key)
+	}
+	risk, confirm := setting("auto_nudge_max_risk"), setting("confirm_high_risk")
+	if risk != nil && strings.EqualFold(risk.Value, "high") && confirm != nil && confirm.Value == "true" {
+		at := mappingValue(node, "auto_nudge_max_risk")
+		if at == nil {
+			at = mappingValue(node, "confirm_high_risk")
+		}
+		if at != nil {
+			*problems = append(*problems, Problem{at.Line, at.Column,
+				"auto_nudge_max_risk: high lets auto-nudge send high-risk actions without the confirmation confirm_high_risk asks for"})
+		}
+	}
+	if command := mappingValue(node, "speech_command"); command != nil && command.Value != "" {
+		if speech := setting("speech"); speech == nil || speech.Value != "true" {
+			*problems = append(*problems, Problem{command.Line, command.Column,
+				"speech_command has no effect without speech: true"})
+		}
+	}
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingValues returns the values of a mapping node.
+func mappingValues(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var values []*yaml.Node
+	for i := 1; i < len(node.Content); i += 2 {
+		values = append(values, node.Content[i])
+	}
+	return values
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lastKey returns the last key of path, e.g. "pattern" for
+// "parsers[].blocked[].pattern".
+func lastKey(path string) string {
+	path = strings.TrimSuffix(path, "[]")
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// LintFile lints the config file Load would read. The path is empty when
+// there is none.
+func LintFile() (string, []Problem) {
+	path, data, err := findConfigFile()
+	if err != nil {
+		return "", nil
+	}
+	return path, Lint(data)
+}
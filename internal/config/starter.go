@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Starter holds the answers of the setup wizard (pane-patrol init).
+type Starter struct {
+	ExcludeSessions  []string
+	AutoNudge        bool
+	AutoNudgeMaxRisk string // "low", "medium", or "high"; empty for low
+	Refresh          string // empty for the default
+	ShowWaitingFor   bool
+	OTELEndpoint     string // empty for no telemetry
+	OTELHeaders      string // e.g. Langfuse credentials, "Authorization=Basic ..."
+}
+
+// UserConfigPath returns the config file in the user's config directory,
+// ~/.config/pane-patrol/config.yaml, the second place Load looks.
+func UserConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "pane-patrol", "config.yaml"), nil
+}
+
+// YAML renders s as a commented config file. Settings left at their
+// defaults are written commented out, so the file doubles as a reference.
+func (s Starter) YAML() []byte {
+	var b strings.Builder
+	b.WriteString("# pane-patrol configuration, written by pane-patrol init.\n")
+	b.WriteString("# Environment variables (PANE_PATROL_*) override these settings.\n\n")
+
+	b.WriteString("# Sessions to skip when scanning (exact names, or a prefix ending in *).\n")
+	if len(s.ExcludeSessions) == 0 {
+		b.WriteString("# exclude_sessions: [\"private\"]\n")
+	} else {
+		b.WriteString("exclude_sessions:\n")
+		for _, name := range s.ExcludeSessions {
+			fmt.Fprintf(&b, "  - %s\n", strconv.Quote(name))
+		}
+	}
+
+	b.WriteString("\n# Send the recommended action to blocked panes automatically, up to a\n")
+	b.WriteString("# risk level: low, medium, or high. Toggle at runtime with a.\n")
+	risk := s.AutoNudgeMaxRisk
+	if risk == "" {
+		risk = "low"
+	}
+	if s.AutoNudge {
+		b.WriteString("auto_nudge: true\n")
+		fmt.Fprintf(&b, "auto_nudge_max_risk: %s\n", risk)
+	} else {
+		b.WriteString("# auto_nudge: true\n")
+		fmt.Fprintf(&b, "# auto_nudge_max_risk: %s\n", risk)
+	}
+
+	b.WriteString("\n# Auto-refresh interval (\"0\" or \"off\" to disable).\n")
+	if s.Refresh == "" {
+		fmt.Fprintf(&b, "# refresh: %s\n", Defaults().Refresh)
+	} else {
+		fmt.Fprintf(&b, "refresh: %s\n", strconv.Quote(s.Refresh))
+	}
+
+	b.WriteString("\n# Show what a blocked pane is waiting for under its row.\n")
+	if s.ShowWaitingFor {
+		b.WriteString("show_waiting_for: true\n")
+	} else {
+		b.WriteString("# show_waiting_for: true\n")
+	}
+
+	b.WriteString("\n# OTEL/Langfuse observability: scan traces and metrics.\n")
+	if s.OTELEndpoint == "" {
+		b.WriteString("# otel_endpoint: http://localhost:3000/api/public/otel\n")
+		b.WriteString("# otel_headers: \"Authorization=Basic <base64-encoded-credentials>\"\n")
+	} else {
+		fmt.Fprintf(&b, "otel_endpoint: %s\n", strconv.Quote(s.OTELEndpoint))
+		if s.OTELHeaders != "" {
+			fmt.Fprintf(&b, "otel_headers: %s\n", strconv.Quote(s.OTELHeaders))
+		}
+	}
+	return []byte(b.String())
+}
+
+// WriteStarter writes s to path, creating its directory. It refuses to
+// replace an existing file unless force is set. A file with OTEL headers,
+// which usually hold credentials, is only readable by the user.
+func WriteStarter(path string, s Starter, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists (use --force to replace it)", path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	perm := os.FileMode(0644)
+	if s.OTELHeaders != "" {
+		perm = 0600
+	}
+	return os.WriteFile(path, s.YAML(), perm)
+}
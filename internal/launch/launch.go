@@ -0,0 +1,91 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Multiplexer is the subset of tmux operations needed to build a fleet.
+// *mux.Tmux implements it; tests substitute a fake.
+type Multiplexer interface {
+	HasSession(ctx context.Context, name string) bool
+	NewSession(ctx context.Context, name, dir string, command []string) (string, error)
+	NewWindow(ctx context.Context, session, name, dir string, command []string) (string, error)
+	SplitWindow(ctx context.Context, target, dir string, command []string) (string, error)
+	SelectLayout(ctx context.Context, target, layout string) error
+	RenameWindow(ctx context.Context, target, name string) error
+}
+
+// Launched is a pane created by Launch.
+type Launched struct {
+	Target  string
+	Command string
+}
+
+// Launch creates every session in the template and returns the created
+// panes in template order. It refuses to start if any session already
+// exists, so a template is never half-applied on top of a running fleet.
+// If creation fails midway, the panes created so far are returned with
+// the error.
+func Launch(ctx context.Context, m Multiplexer, t *Template) ([]Launched, error) {
+	var existing []string
+	for _, s := range t.Sessions {
+		if m.HasSession(ctx, s.Name) {
+			existing = append(existing, s.Name)
+		}
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("tmux session(s) already exist: %s", strings.Join(existing, ", "))
+	}
+
+	var launched []Launched
+	for _, s := range t.Sessions {
+		for wi, w := range s.Windows {
+			for pi, p := range w.Panes {
+				dir := t.resolveDir(s, w, p)
+				var target string
+				var err error
+				switch {
+				case wi == 0 && pi == 0:
+					target, err = m.NewSession(ctx, s.Name, dir, commandArgs(p.Command))
+					if err == nil && w.Name != "" {
+						err = m.RenameWindow(ctx, windowTarget(target), w.Name)
+					}
+				case pi == 0:
+					target, err = m.NewWindow(ctx, s.Name, w.Name, dir, commandArgs(p.Command))
+				default:
+					target, err = m.SplitWindow(ctx, windowTarget(launched[len(launched)-1].Target), dir, commandArgs(p.Command))
+				}
+				if err != nil {
+					return launched, fmt.Errorf("session %q: %w", s.Name, err)
+				}
+				launched = append(launched, Launched{Target: target, Command: p.Command})
+			}
+			if w.Layout != "" {
+				last := launched[len(launched)-1].Target
+				if err := m.SelectLayout(ctx, windowTarget(last), w.Layout); err != nil {
+					return launched, fmt.Errorf("session %q: %w", s.Name, err)
+				}
+			}
+		}
+	}
+	return launched, nil
+}
+
+// commandArgs passes a command line to tmux as a single argument, which tmux
+// runs through the shell. An empty command starts the default shell.
+func commandArgs(command string) []string {
+	if command == "" {
+		return nil
+	}
+	return []string{command}
+}
+
+// windowTarget strips the pane index from a "session:window.pane" target.
+func windowTarget(target string) string {
+	if i := strings.LastIndex(target, "."); i > strings.LastIndex(target, ":") {
+		return target[:i]
+	}
+	return target
+}
@@ -0,0 +1,156 @@
+package launch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeTmux records calls and hands out targets like tmux would.
+type fakeTmux struct {
+	existing map[string]bool
+	windows  map[string]int // session -> next window index
+	panes    map[string]int // window target -> next pane index
+	calls    []string
+	failOn   string
+}
+
+func newFakeTmux() *fakeTmux {
+	return &fakeTmux{existing: map[string]bool{}, windows: map[string]int{}, panes: map[string]int{}}
+}
+
+func (f *fakeTmux) record(call string) error {
+	f.calls = append(f.calls, call)
+	if f.failOn != "" && strings.HasPrefix(call, f.failOn) {
+		return fmt.Errorf("boom")
+	}
+	return nil
+}
+
+func (f *fakeTmux) HasSession(_ context.Context, name string) bool { return f.existing[name] }
+
+func (f *fakeTmux) NewSession(_ context.Context, name, dir string, command []string) (string, error) {
+	if err := f.record(fmt.Sprintf("new-session %s %s %q", name, dir, command)); err != nil {
+		return "", err
+	}
+	f.windows[name] = 1
+	f.panes[name+":0"] = 1
+	return name + ":0.0", nil
+}
+
+func (f *fakeTmux) NewWindow(_ context.Context, session, name, dir string, command []string) (string, error) {
+	if err := f.record(fmt.Sprintf("new-window %s %s %s %q", session, name, dir, command)); err != nil {
+		return "", err
+	}
+	w := f.windows[session]
+	f.windows[session]++
+	win := fmt.Sprintf("%s:%d", session, w)
+	f.panes[win] = 1
+	return win + ".0", nil
+}
+
+func (f *fakeTmux) SplitWindow(_ context.Context, target, dir string, command []string) (string, error) {
+	if err := f.record(fmt.Sprintf("split-window %s %s %q", target, dir, command)); err != nil {
+		return "", err
+	}
+	p := f.panes[target]
+	f.panes[target]++
+	return fmt.Sprintf("%s.%d", target, p), nil
+}
+
+func (f *fakeTmux) SelectLayout(_ context.Context, target, layout string) error {
+	return f.record("select-layout " + target + " " + layout)
+}
+
+func (f *fakeTmux) RenameWindow(_ context.Context, target, name string) error {
+	return f.record("rename-window " + target + " " + name)
+}
+
+const fleetYAML = `
+sessions:
+  - name: api
+    dir: /src/api
+    windows:
+      - name: agents
+        panes:
+          - command: claude
+          - command: codex
+            dir: /src/api-2
+      - name: shell
+        panes:
+          - {}
+  - name: docs
+    panes:
+      - command: opencode
+`
+
+func TestLaunch_CreatesSessionsWindowsPanes(t *testing.T) {
+	tpl, err := ParseTemplate([]byte(fleetYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newFakeTmux()
+	launched, err := Launch(context.Background(), f, tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTargets := []string{"api:0.0", "api:0.1", "api:1.0", "docs:0.0"}
+	if len(launched) != len(wantTargets) {
+		t.Fatalf("launched: got %+v", launched)
+	}
+	for i, want := range wantTargets {
+		if launched[i].Target != want {
+			t.Errorf("launched[%d]: got %q, want %q", i, launched[i].Target, want)
+		}
+	}
+	wantCalls := []string{
+		`new-session api /src/api ["claude"]`,
+		`rename-window api:0 agents`,
+		`split-window api:0 /src/api-2 ["codex"]`,
+		`select-layout api:0 tiled`,
+		`new-window api shell /src/api []`,
+		`new-session docs  ["opencode"]`,
+	}
+	if strings.Join(f.calls, "\n") != strings.Join(wantCalls, "\n") {
+		t.Errorf("calls:\n%s\nwant:\n%s", strings.Join(f.calls, "\n"), strings.Join(wantCalls, "\n"))
+	}
+}
+
+func TestLaunch_RefusesExistingSessions(t *testing.T) {
+	tpl, _ := ParseTemplate([]byte(fleetYAML))
+	f := newFakeTmux()
+	f.existing["docs"] = true
+	_, err := Launch(context.Background(), f, tpl)
+	if err == nil || !strings.Contains(err.Error(), "docs") {
+		t.Fatalf("expected existing-session error, got %v", err)
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("nothing should be created, got calls %v", f.calls)
+	}
+}
+
+func TestLaunch_ReturnsPartialOnFailure(t *testing.T) {
+	tpl, _ := ParseTemplate([]byte(fleetYAML))
+	f := newFakeTmux()
+	f.failOn = "new-window"
+	launched, err := Launch(context.Background(), f, tpl)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(launched) != 2 {
+		t.Errorf("expected the 2 panes created before the failure, got %+v", launched)
+	}
+}
+
+func TestWindowTarget(t *testing.T) {
+	for in, want := range map[string]string{
+		"api:0.1":     "api:0",
+		"my.proj:2.0": "my.proj:2",
+		"api:3":       "api:3",
+	} {
+		if got := windowTarget(in); got != want {
+			t.Errorf("windowTarget(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
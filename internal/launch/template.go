@@ -0,0 +1,227 @@
+// Package launch creates tmux sessions for a fleet of agents from a
+// template file, so pane-patrol can spawn the agents it then supervises.
+//
+// Example template:
+//
+//	sessions:
+//	  - name: api-refactor
+//	    dir: ~/src/api
+//	    windows:
+//	      - name: agents
+//	        layout: tiled
+//	        panes:
+//	          - command: claude
+//	          - command: codex --model gpt-5-codex
+//	            dir: ../api-worktree
+//	  - name: docs
+//	    panes:                # shorthand for a single window
+//	      - command: opencode
+package launch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template describes the tmux sessions to create.
+type Template struct {
+	Sessions []Session `yaml:"sessions"`
+
+	// Path is the file the template was loaded from (empty if parsed
+	// from bytes). Relative directories resolve against its directory.
+	Path string `yaml:"-"`
+}
+
+// Session is one tmux session.
+type Session struct {
+	Name string `yaml:"name"`
+	// Dir is the default working directory for the session's panes.
+	Dir     string   `yaml:"dir"`
+	Windows []Window `yaml:"windows"`
+	// Panes is shorthand for a single unnamed window holding these panes.
+	Panes []Pane `yaml:"panes"`
+}
+
+// Window is one window of a session.
+type Window struct {
+	Name string `yaml:"name"`
+	// Dir overrides the session directory for this window's panes.
+	Dir string `yaml:"dir"`
+	// Layout is a tmux preset layout applied after all panes are created.
+	// Default: "tiled" when the window has more than one pane.
+	Layout string `yaml:"layout"`
+	Panes  []Pane `yaml:"panes"`
+}
+
+// Pane is one pane and the agent started in it.
+type Pane struct {
+	// Command is a shell command line (e.g. "claude --resume"). Empty
+	// starts the default shell.
+	Command string `yaml:"command"`
+	// Dir overrides the window directory for this pane.
+	Dir string `yaml:"dir"`
+}
+
+// layouts are tmux's preset layouts (select-layout).
+var layouts = map[string]bool{
+	"even-horizontal": true,
+	"even-vertical":   true,
+	"main-horizontal": true,
+	"main-vertical":   true,
+	"tiled":           true,
+}
+
+// validSessionName matches names usable in a tmux target without quoting.
+var validSessionName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// LoadTemplate reads and validates a template file.
+func LoadTemplate(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := ParseTemplate(data)
+	if err != nil {
+		return nil, fmt.Errorf("template %s: %w", path, err)
+	}
+	tpl.Path = path
+	return tpl, nil
+}
+
+// ParseTemplate parses and validates a template. The session-level Panes
+// shorthand is normalized into a single window.
+func ParseTemplate(data []byte) (*Template, error) {
+	var tpl Template
+	if err := yaml.Unmarshal(data, &tpl); err != nil {
+		return nil, err
+	}
+	if err := tpl.normalize(); err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+func (t *Template) normalize() error {
+	if len(t.Sessions) == 0 {
+		return fmt.Errorf("no sessions defined")
+	}
+	seen := map[string]bool{}
+	for i := range t.Sessions {
+		s := &t.Sessions[i]
+		if !validSessionName.MatchString(s.Name) {
+			return fmt.Errorf("session %d: name %q must be non-empty and contain only letters, digits, '-' and '_'", i+1, s.Name)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("session %q defined twice", s.Name)
+		}
+		seen[s.Name] = true
+
+		if len(s.Panes) > 0 {
+			if len(s.Windows) > 0 {
+				return fmt.Errorf("session %q: use either panes or windows, not both", s.Name)
+			}
+			s.Windows = []Window{{Panes: s.Panes}}
+			s.Panes = nil
+		}
+		if len(s.Windows) == 0 {
+			return fmt.Errorf("session %q: no panes defined", s.Name)
+		}
+		for j := range s.Windows {
+			w := &s.Windows[j]
+			if len(w.Panes) == 0 {
+				return fmt.Errorf("session %q window %d: no panes defined", s.Name, j+1)
+			}
+			if w.Layout == "" && len(w.Panes) > 1 {
+				w.Layout = "tiled"
+			}
+			if w.Layout != "" && !layouts[w.Layout] {
+				return fmt.Errorf("session %q window %d: unknown layout %q (use %s)",
+					s.Name, j+1, w.Layout, strings.Join(layoutNames(), ", "))
+			}
+		}
+	}
+	return nil
+}
+
+func layoutNames() []string {
+	return []string{"even-horizontal", "even-vertical", "main-horizontal", "main-vertical", "tiled"}
+}
+
+// PaneCount returns the total number of panes in the template.
+func (t *Template) PaneCount() int {
+	n := 0
+	for _, s := range t.Sessions {
+		for _, w := range s.Windows {
+			n += len(w.Panes)
+		}
+	}
+	return n
+}
+
+// resolveDir returns the working directory for a pane: the most specific of
+// pane, window, and session Dir. "~" expands to the home directory and
+// relative paths resolve against the template file's directory.
+func (t *Template) resolveDir(s Session, w Window, p Pane) string {
+	dir := s.Dir
+	if w.Dir != "" {
+		dir = w.Dir
+	}
+	if p.Dir != "" {
+		dir = p.Dir
+	}
+	if dir == "" {
+		return ""
+	}
+	dir = expandHome(dir)
+	if !filepath.IsAbs(dir) && t.Path != "" {
+		dir = filepath.Join(filepath.Dir(t.Path), dir)
+	}
+	return dir
+}
+
+// expandHome replaces a leading "~" with the home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
+
+// DefaultDir is where the supervisor looks for templates when no
+// template_dir is configured: ~/.config/pane-patrol/templates.
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "pane-patrol", "templates")
+}
+
+// ListTemplates returns the .yaml and .yml files in dir, sorted by name.
+// A leading "~" in dir is expanded. A missing directory yields no
+// templates and no error.
+func ListTemplates(dir string) ([]string, error) {
+	dir = expandHome(dir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,96 @@
+package launch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTemplate_PanesShorthand(t *testing.T) {
+	tpl, err := ParseTemplate([]byte(`
+sessions:
+  - name: docs
+    panes:
+      - command: opencode
+      - command: claude
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := tpl.Sessions[0]
+	if len(s.Windows) != 1 || len(s.Windows[0].Panes) != 2 || s.Panes != nil {
+		t.Fatalf("expected one window with 2 panes, got %+v", s)
+	}
+	if s.Windows[0].Layout != "tiled" {
+		t.Errorf("default layout: got %q, want tiled", s.Windows[0].Layout)
+	}
+	if tpl.PaneCount() != 2 {
+		t.Errorf("PaneCount: got %d, want 2", tpl.PaneCount())
+	}
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name, yaml, want string
+	}{
+		{"empty", `sessions: []`, "no sessions"},
+		{"bad name", "sessions:\n  - name: a.b\n    panes: [{command: x}]", "name"},
+		{"duplicate", "sessions:\n  - name: a\n    panes: [{command: x}]\n  - name: a\n    panes: [{command: y}]", "defined twice"},
+		{"no panes", "sessions:\n  - name: a", "no panes"},
+		{"both", "sessions:\n  - name: a\n    panes: [{command: x}]\n    windows: [{panes: [{command: y}]}]", "not both"},
+		{"empty window", "sessions:\n  - name: a\n    windows: [{name: w}]", "window 1: no panes"},
+		{"layout", "sessions:\n  - name: a\n    windows: [{layout: grid, panes: [{command: x}]}]", "unknown layout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDir(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	tpl := &Template{Path: "/etc/fleet/api.yaml"}
+	s := Session{Dir: "~/src"}
+	w := Window{}
+	tests := []struct {
+		pane Pane
+		w    Window
+		want string
+	}{
+		{Pane{}, w, filepath.Join(home, "src")},
+		{Pane{}, Window{Dir: "/srv"}, "/srv"},
+		{Pane{Dir: "worktree"}, w, "/etc/fleet/worktree"},
+	}
+	for _, tt := range tests {
+		if got := tpl.resolveDir(s, tt.w, tt.pane); got != tt.want {
+			t.Errorf("resolveDir(%+v, %+v): got %q, want %q", tt.w, tt.pane, got, tt.want)
+		}
+	}
+	if got := tpl.resolveDir(Session{}, w, Pane{}); got != "" {
+		t.Errorf("no dir: got %q, want empty (tmux default)", got)
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.yaml", "a.yml", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := ListTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || filepath.Base(got[0]) != "a.yml" || filepath.Base(got[1]) != "b.yaml" {
+		t.Errorf("got %v", got)
+	}
+	if got, err := ListTemplates(filepath.Join(dir, "missing")); err != nil || got != nil {
+		t.Errorf("missing dir: got %v, %v", got, err)
+	}
+}
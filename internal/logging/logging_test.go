@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]slog.Level{
+		"": slog.LevelInfo, "debug": slog.LevelDebug, "INFO": slog.LevelInfo,
+		"warn": slog.LevelWarn, "error": slog.LevelError,
+	} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestOpen_LevelsAndTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "supervisor.log")
+	log, f, err := Open(path, slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Debug("scan done", "panes", 3)
+	log.Info("nudge sent", "target", "api:0.1", "keys", "1")
+	log.Error("scan failed", "err", "no server")
+	f.Close()
+
+	lines, err := Tail(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected the info and error records only, got %q", lines)
+	}
+	if !strings.Contains(lines[0], "level=INFO") || !strings.Contains(lines[0], "target=api:0.1") ||
+		!strings.Contains(lines[1], "level=ERROR") {
+		t.Errorf("unexpected records: %q", lines)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("log should be 0600, got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	if lines, err := Tail(path, 1); err != nil || len(lines) != 1 || !strings.Contains(lines[0], "scan failed") {
+		t.Errorf("Tail(1) = %q, %v", lines, err)
+	}
+	if lines, err := Tail(filepath.Join(t.TempDir(), "missing.log"), 10); err != nil || lines != nil {
+		t.Errorf("missing log: %q, %v", lines, err)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+	r, err := NewRotatingFile(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5 {
+		fmt.Fprintf(r, "line %d %s\n", i, strings.Repeat("x", 30))
+	}
+	r.Close()
+
+	current, _ := os.ReadFile(path)
+	previous, _ := os.ReadFile(path + ".1")
+	if len(current) > 100 || len(previous) > 100 {
+		t.Errorf("files exceed the max size: %d and %d bytes", len(current), len(previous))
+	}
+	if !strings.HasPrefix(string(current), "line 4") || !strings.HasPrefix(string(previous), "line 2") {
+		t.Errorf("unexpected rotation:\ncurrent: %q\nprevious: %q", current, previous)
+	}
+}
+
+func TestOrDiscard(t *testing.T) {
+	OrDiscard(nil).Info("dropped") // must not panic
+	l := slog.Default()
+	if OrDiscard(l) != l {
+		t.Error("expected the logger itself")
+	}
+}
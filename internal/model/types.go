@@ -22,6 +22,8 @@ type Pane struct {
 	Session string `json:"session"`
 	// Window is the window index.
 	Window int `json:"window"`
+	// WindowName is the window's name.
+	WindowName string `json:"window_name,omitempty"`
 	// Pane is the pane index.
 	Pane int `json:"pane"`
 	// PID is the pane's shell process ID.
@@ -30,6 +32,14 @@ type Pane struct {
 	Command string `json:"command"`
 	// ProcessTree is the list of child processes (command lines) running in the pane.
 	ProcessTree []string `json:"process_tree,omitempty"`
+	// LastActivity is the time of the last output in the pane's window, as
+	// reported by the multiplexer. Zero when the multiplexer does not report it.
+	LastActivity time.Time `json:"last_activity,omitzero"`
+	// Path is the working directory of the pane's foreground process.
+	Path string `json:"path,omitempty"`
+	// Supervisor is set for panes running a pane-patrol supervisor, which
+	// other supervisors leave out of their scans.
+	Supervisor bool `json:"supervisor,omitempty"`
 }
 
 // Verdict is the result of evaluating a pane's content.
@@ -40,14 +50,36 @@ type Verdict struct {
 	Session string `json:"session"`
 	// Window is the window index.
 	Window int `json:"window"`
+	// WindowName is the window's name.
+	WindowName string `json:"window_name,omitempty"`
 	// Pane is the pane index.
 	Pane int `json:"pane"`
 	// Command is the current command running in the pane.
 	Command string `json:"command"`
+	// LastActivity is the time of the last output in the pane's window.
+	// For a blocked pane this approximates when it became blocked.
+	LastActivity time.Time `json:"last_activity,omitzero"`
+	// Path is the working directory of the pane's foreground process.
+	Path string `json:"path,omitempty"`
+	// GitBranch is the branch checked out in Path (a short commit hash when
+	// detached), empty outside a git repository or when not looked up.
+	GitBranch string `json:"git_branch,omitempty"`
+	// GitDirty is true when Path's repository has uncommitted or untracked
+	// changes.
+	GitDirty bool `json:"git_dirty,omitempty"`
+	// PID is the pane's shell process ID. It changes when the pane is
+	// respawned, e.g. when its agent is restarted.
+	PID int `json:"pid,omitempty"`
+	// ProcessTree is the pane's child processes when it was evaluated, kept
+	// to parse the pane again the same way (not reported).
+	ProcessTree []string `json:"-"`
 
 	// Agent is the detected agent name (e.g., "claude_code", "opencode", "codex", "not_an_agent").
 	// Set by deterministic parsers for known agents.
 	Agent string `json:"agent"`
+	// Model is the LLM model the agent is using (e.g., "claude-sonnet-4-5"),
+	// when visible in the agent's TUI. Empty if not shown.
+	Model string `json:"model,omitempty"`
 	// Blocked indicates whether the pane is waiting for human input.
 	Blocked bool `json:"blocked"`
 	// Reason is a one-line summary of the verdict.
@@ -55,21 +87,39 @@ type Verdict struct {
 	// WaitingFor is a verbatim extract of the dialog, prompt, or question the
 	// agent is blocked on. Only populated when blocked is true.
 	WaitingFor string `json:"waiting_for"`
+	// Activity is the last few lines of output above the dialog or prompt,
+	// i.e. what the agent did before it blocked. Only populated when blocked
+	// is true.
+	Activity []string `json:"activity,omitempty"`
 	// Reasoning is the detailed step-by-step analysis.
 	Reasoning string `json:"reasoning"`
+	// Confidence is the parser's evidence level for the agent attribution
+	// (see parser.Confidence*). Zero when no parser produced the verdict.
+	Confidence float64 `json:"confidence,omitempty"`
 
 	// Actions is a list of possible actions to unblock the pane.
 	// Set by deterministic parsers for known agents.
 	// Only populated when the pane is blocked.
 	Actions []Action `json:"actions,omitempty"`
-	// Recommended is the 0-based index into Actions for the recommended action.
+	// Recommended is the 0-based index into Actions for the recommended action,
+	// or -1 when nothing is recommended.
 	Recommended int `json:"recommended"`
+	// Idle is what an agent idle at its prompt last did (one of the Idle*
+	// constants), or empty when it is not idle or cannot be told.
+	Idle string `json:"idle,omitempty"`
+	// Dialog is the structured form of the dialog the agent is blocked on
+	// (kind, question, options, tabs). Nil when no dialog is visible, e.g.
+	// idle at prompt. Only populated when blocked is true.
+	Dialog *Dialog `json:"dialog,omitempty"`
 	// Subagents lists detected subagent tasks parsed from TUI content.
 	// Populated by deterministic parsers when a running Task block is visible.
 	Subagents []SubagentInfo `json:"subagents,omitempty"`
 
 	// Content is the raw pane capture. Only populated when verbose mode is enabled.
 	Content string `json:"content,omitempty"`
+	// ContentHash is the hex SHA-256 of the pane capture (with its process
+	// header) the verdict was made from. Empty for hook events.
+	ContentHash string `json:"content_hash,omitempty"`
 
 	// EvalSource records how this verdict was produced.
 	// Use the EvalSource* constants.
@@ -93,6 +143,73 @@ type Action struct {
 	// appended). Use this for TUIs that run in raw mode and process each
 	// keypress individually (e.g., Claude Code, OpenCode, Codex).
 	Raw bool `json:"raw,omitempty"`
+	// FollowUp, when true, means the agent may answer the action with a
+	// follow-up dialog that needs its own answer (e.g., OpenCode's "Allow
+	// always" asking for a scope) instead of unblocking.
+	FollowUp bool `json:"follow_up,omitempty"`
+	// Text, when true, means Keys open a text field (e.g., the notes of a
+	// Codex question): the text to answer with is typed after the keys and
+	// submitted with Enter.
+	Text bool `json:"text,omitempty"`
+}
+
+// Idle states for Verdict.Idle, told apart by the parsers from what is on
+// screen above the prompt.
+const (
+	IdleFresh    = "fresh"    // just started: the splash screen, no task run yet
+	IdleFinished = "finished" // finished a task ("✻ Worked for 2m 15s")
+)
+
+// Dialog kinds for Dialog.Kind.
+const (
+	DialogPermission = "permission" // approve or deny a command, edit, or tool call
+	DialogQuestion   = "question"   // the agent asks a question with numbered options
+	DialogConfirm    = "confirm"    // review-and-submit tab of a multi-question form
+	DialogAuth       = "auth"       // the agent needs the user to sign in
+)
+
+// Dialog is a structured description of an agent dialog, populated by the
+// deterministic parsers from the same exact strings they match on.
+// Consumers should prefer it over parsing Reason or WaitingFor text.
+type Dialog struct {
+	// Kind is one of the Dialog* constants.
+	Kind string `json:"kind"`
+	// Question is the dialog's prompt text (e.g., "Do you want to proceed?").
+	Question string `json:"question,omitempty"`
+	// Options are the visible choices, in display order. Empty for dialogs
+	// without numbered options (e.g., OpenCode permission, Confirm tab).
+	Options []DialogOption `json:"options,omitempty"`
+	// MultiSelect is true when options are checkboxes: number keys toggle
+	// them and Enter submits the selection.
+	MultiSelect bool `json:"multi_select,omitempty"`
+	// Tabs are the tab headers of a multi-question form, in display order.
+	Tabs []string `json:"tabs,omitempty"`
+	// ActiveTab is the index into Tabs of the visible tab, or -1 when it is
+	// unknown (the agent marks the active tab by color only) or there are
+	// no tabs.
+	ActiveTab int `json:"active_tab"`
+	// Command is the shell command a permission dialog asks to run, taken
+	// from its "$ " line. Empty for other dialogs.
+	Command string `json:"command,omitempty"`
+	// Warnings name the dangerous patterns found in Command (e.g.
+	// "rm -rf: recursive forced delete").
+	Warnings []string `json:"warnings,omitempty"`
+	// Code is the one-time code an auth dialog asks to enter in a browser
+	// (e.g., Codex device-code sign-in). Empty for other dialogs.
+	Code string `json:"code,omitempty"`
+}
+
+// DialogOption is one choice in a Dialog.
+type DialogOption struct {
+	// Label is the option text without its number or checkbox
+	// (e.g., "Yes, and don't ask again").
+	Label string `json:"label"`
+	// Description is the option's help text rendered below it, if any.
+	Description string `json:"description,omitempty"`
+	// Checked is the checkbox state in a multi-select question.
+	Checked bool `json:"checked,omitempty"`
+	// Selected is true for the option under the dialog cursor (❯ or ›).
+	Selected bool `json:"selected,omitempty"`
 }
 
 // SubagentInfo describes a detected subagent task parsed from TUI content.
@@ -118,13 +235,18 @@ type SubagentInfo struct {
 // Blocked, Reason, EvalSource, etc.) directly.
 func BaseVerdict(pane Pane, start time.Time) Verdict {
 	return Verdict{
-		Target:      pane.Target,
-		Session:     pane.Session,
-		Window:      pane.Window,
-		Pane:        pane.Pane,
-		Command:     pane.Command,
-		EvaluatedAt: time.Now().UTC(),
-		DurationMs:  time.Since(start).Milliseconds(),
+		Target:       pane.Target,
+		Session:      pane.Session,
+		Window:       pane.Window,
+		WindowName:   pane.WindowName,
+		Pane:         pane.Pane,
+		Command:      pane.Command,
+		LastActivity: pane.LastActivity,
+		Path:         pane.Path,
+		PID:          pane.PID,
+		ProcessTree:  pane.ProcessTree,
+		EvaluatedAt:  time.Now().UTC(),
+		DurationMs:   time.Since(start).Milliseconds(),
 	}
 }
 
@@ -6,10 +6,22 @@ package mux
 
 import (
 	"context"
+	"errors"
 
 	"github.com/timvw/pane-patrol/internal/model"
 )
 
+// Errors multiplexer operations wrap, to tell a vanished pane or server
+// apart from other failures (check with errors.Is).
+var (
+	// ErrPaneNotFound means the target pane, window, or session no longer
+	// exists, e.g. it was closed after panes were listed.
+	ErrPaneNotFound = errors.New("pane not found")
+	// ErrNoServer means no multiplexer server is running, e.g. it was
+	// restarted or exited.
+	ErrNoServer = errors.New("multiplexer server not running")
+)
+
 // Multiplexer abstracts terminal multiplexer operations.
 // Implementations exist for tmux and (future) zellij.
 type Multiplexer interface {
@@ -24,3 +36,12 @@ type Multiplexer interface {
 	// The target format depends on the multiplexer (e.g., "session:window.pane" for tmux).
 	CapturePane(ctx context.Context, target string) (string, error)
 }
+
+// BatchCapturer is implemented by multiplexers that can capture several
+// panes in one call, which is much cheaper than one subprocess per pane.
+type BatchCapturer interface {
+	// CapturePanes captures the visible content of each target, keyed by
+	// target. If any target cannot be captured the whole call fails; callers
+	// then fall back to CapturePane per target.
+	CapturePanes(ctx context.Context, targets []string) (map[string]string, error)
+}
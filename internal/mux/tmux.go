@@ -2,17 +2,24 @@ package mux
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/timvw/pane-patrol/internal/model"
 )
 
 // Tmux implements the Multiplexer interface for tmux.
-type Tmux struct{}
+type Tmux struct {
+	featuresOnce sync.Once
+	features     TmuxFeatures
+}
 
 // NewTmux creates a new tmux multiplexer.
 func NewTmux() *Tmux {
@@ -24,10 +31,31 @@ func (t *Tmux) Name() string {
 	return "tmux"
 }
 
+// Features probes the tmux version once and returns the version-dependent
+// behaviors to use. If probing fails, the minimum supported release is
+// assumed.
+func (t *Tmux) Features(ctx context.Context) TmuxFeatures {
+	t.featuresOnce.Do(func() {
+		v, err := probeTmuxVersion(ctx)
+		if err != nil {
+			t.features = baselineFeatures()
+			return
+		}
+		t.features = FeaturesFor(v)
+	})
+	return t.features
+}
+
+// SupervisorOption is the pane option a pane-patrol supervisor sets on its
+// own pane, so other supervisors on the same server can tell it apart.
+const SupervisorOption = "@pane-patrol-supervisor"
+
 // ListPanes returns all tmux panes, optionally filtered by session name pattern.
 func (t *Tmux) ListPanes(ctx context.Context, filter string) ([]model.Pane, error) {
-	// Format: session_name:window_index.pane_index\tpane_pid\tcurrent_command
-	format := "#{session_name}:#{window_index}.#{pane_index}\t#{pane_pid}\t#{pane_current_command}"
+	// Format: session_name:window_index.pane_index\tpane_pid\twindow_activity\tsupervisor_option\tcurrent_command\twindow_name\tcurrent_path
+	// The path comes last so a tab in a directory name cannot shift fields.
+	format := "#{session_name}:#{window_index}.#{pane_index}\t#{pane_pid}\t#{window_activity}\t#{" + SupervisorOption +
+		"}\t#{pane_current_command}\t#{window_name}\t#{pane_current_path}"
 	out, err := t.run(ctx, "list-panes", "-a", "-F", format)
 	if err != nil {
 		return nil, fmt.Errorf("tmux list-panes: %w", err)
@@ -41,19 +69,25 @@ func (t *Tmux) ListPanes(ctx context.Context, filter string) ([]model.Pane, erro
 		}
 	}
 
+	// One process snapshot serves every pane of this listing.
+	var procs processTable
 	var panes []model.Pane
 	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
 		if line == "" {
 			continue
 		}
-		parts := strings.SplitN(line, "\t", 3)
-		if len(parts) != 3 {
+		parts := strings.SplitN(line, "\t", 7)
+		if len(parts) != 7 {
 			continue
 		}
 
 		target := parts[0]
 		pid, _ := strconv.Atoi(parts[1])
-		command := parts[2]
+		activity, _ := strconv.ParseInt(parts[2], 10, 64)
+		supervisor := parts[3] != ""
+		command := parts[4]
+		windowName := parts[5]
+		path := parts[6]
 
 		pane, err := parseTarget(target)
 		if err != nil {
@@ -61,13 +95,22 @@ func (t *Tmux) ListPanes(ctx context.Context, filter string) ([]model.Pane, erro
 		}
 		pane.PID = pid
 		pane.Command = command
-		pane.ProcessTree = getProcessTree(pid)
+		pane.WindowName = windowName
+		pane.Path = path
+		pane.Supervisor = supervisor
+		if activity > 0 {
+			pane.LastActivity = time.Unix(activity, 0).UTC()
+		}
 
 		// Apply session name filter if provided.
 		if re != nil && !re.MatchString(pane.Session) {
 			continue
 		}
 
+		if procs == nil {
+			procs = snapshotProcesses()
+		}
+		pane.ProcessTree = procs.tree(pid)
 		panes = append(panes, pane)
 	}
 
@@ -75,100 +118,405 @@ func (t *Tmux) ListPanes(ctx context.Context, filter string) ([]model.Pane, erro
 }
 
 // CapturePane captures the visible content of a tmux pane.
-// Uses -p (stdout) and -J (joined, unwraps lines).
+// Uses -p (stdout) and -J (joined, unwraps lines). Trailing spaces are
+// stripped on every tmux version so parsers see identical content.
 func (t *Tmux) CapturePane(ctx context.Context, target string) (string, error) {
-	out, err := t.run(ctx, "capture-pane", "-t", target, "-p", "-J")
+	f := t.Features(ctx)
+	out, err := t.run(ctx, captureArgs(target, f)...)
 	if err != nil {
 		return "", fmt.Errorf("tmux capture-pane -t %s: %w", target, err)
 	}
+	if !f.CaptureTrimTrailing {
+		out = trimTrailingSpace(out)
+	}
 	return out, nil
 }
 
+// CapturePanes captures several panes with one tmux invocation: the
+// capture-pane commands are chained with ";", separated by a random marker
+// line printed with display-message.
+func (t *Tmux) CapturePanes(ctx context.Context, targets []string) (map[string]string, error) {
+	if len(targets) == 0 {
+		return map[string]string{}, nil
+	}
+	f := t.Features(ctx)
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return nil, err
+	}
+	marker := "pane-patrol-capture-" + hex.EncodeToString(buf[:])
+	out, err := t.run(ctx, batchCaptureArgs(targets, f, marker)...)
+	if err != nil {
+		return nil, fmt.Errorf("tmux capture-pane (%d panes): %w", len(targets), err)
+	}
+	parts, err := splitBatchCapture(out, marker, len(targets))
+	if err != nil {
+		return nil, err
+	}
+	captures := make(map[string]string, len(targets))
+	for i, target := range targets {
+		if !f.CaptureTrimTrailing {
+			parts[i] = trimTrailingSpace(parts[i])
+		}
+		captures[target] = parts[i]
+	}
+	return captures, nil
+}
+
+// batchCaptureArgs chains a capture of each target, each followed by a line
+// holding marker.
+func batchCaptureArgs(targets []string, f TmuxFeatures, marker string) []string {
+	var args []string
+	for i, target := range targets {
+		if i > 0 {
+			args = append(args, ";")
+		}
+		args = append(args, captureArgs(target, f)...)
+		args = append(args, ";", "display-message", "-p", marker)
+	}
+	return args
+}
+
+// splitBatchCapture splits the output of batchCaptureArgs into n captures.
+func splitBatchCapture(out, marker string, n int) ([]string, error) {
+	parts := strings.Split(out, marker+"\n")
+	if len(parts) != n+1 || parts[n] != "" {
+		return nil, fmt.Errorf("tmux capture-pane: expected %d captures, got %d", n, len(parts)-1)
+	}
+	return parts[:n], nil
+}
+
+// CapturePaneHistory captures the visible content of a tmux pane plus up to
+// lines lines of scrollback above it, for content that has scrolled out of
+// view (e.g. the top of a long diff).
+func (t *Tmux) CapturePaneHistory(ctx context.Context, target string, lines int) (string, error) {
+	f := t.Features(ctx)
+	args := append(captureArgs(target, f), "-S", strconv.Itoa(-lines))
+	out, err := t.run(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("tmux capture-pane -t %s -S -%d: %w", target, lines, err)
+	}
+	if !f.CaptureTrimTrailing {
+		out = trimTrailingSpace(out)
+	}
+	return out, nil
+}
+
+// CapturePaneTranscript captures a tmux pane's entire scrollback history
+// followed by its visible content.
+func (t *Tmux) CapturePaneTranscript(ctx context.Context, target string) (string, error) {
+	f := t.Features(ctx)
+	out, err := t.run(ctx, append(captureArgs(target, f), "-S", "-")...)
+	if err != nil {
+		return "", fmt.Errorf("tmux capture-pane -t %s -S -: %w", target, err)
+	}
+	if !f.CaptureTrimTrailing {
+		out = trimTrailingSpace(out)
+	}
+	return out, nil
+}
+
+// NewSession creates a detached tmux session named name, with its first pane
+// running command in dir. Returns the target of the new pane
+// (session:window.pane).
+func (t *Tmux) NewSession(ctx context.Context, name, dir string, command []string) (string, error) {
+	args := []string{"new-session", "-d", "-P", "-F", "#{session_name}:#{window_index}.#{pane_index}",
+		"-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	args = append(args, command...)
+	out, err := t.run(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("tmux new-session -s %s: %w", name, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// NewWindow creates a window in session with its first pane running command
+// in dir, named name unless empty. Returns the target of the new pane.
+func (t *Tmux) NewWindow(ctx context.Context, session, name, dir string, command []string) (string, error) {
+	// "=" forces an exact session match; the trailing ":" selects the next
+	// free window index in that session.
+	args := []string{"new-window", "-d", "-P", "-F", "#{session_name}:#{window_index}.#{pane_index}",
+		"-t", "=" + session + ":"}
+	if name != "" {
+		args = append(args, "-n", name)
+	}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	args = append(args, command...)
+	out, err := t.run(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("tmux new-window -t %s: %w", session, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// SplitWindow splits the window containing target, running command in dir
+// in the new pane. Returns the target of the new pane.
+func (t *Tmux) SplitWindow(ctx context.Context, target, dir string, command []string) (string, error) {
+	args := []string{"split-window", "-d", "-P", "-F", "#{session_name}:#{window_index}.#{pane_index}",
+		"-t", target}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	args = append(args, command...)
+	out, err := t.run(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("tmux split-window -t %s: %w", target, err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// SelectLayout applies a preset layout (e.g. "tiled") to target's window.
+func (t *Tmux) SelectLayout(ctx context.Context, target, layout string) error {
+	if _, err := t.run(ctx, "select-layout", "-t", target, layout); err != nil {
+		return fmt.Errorf("tmux select-layout -t %s %s: %w", target, layout, err)
+	}
+	return nil
+}
+
+// RenameWindow renames target's window.
+func (t *Tmux) RenameWindow(ctx context.Context, target, name string) error {
+	if _, err := t.run(ctx, "rename-window", "-t", target, name); err != nil {
+		return fmt.Errorf("tmux rename-window -t %s: %w", target, err)
+	}
+	return nil
+}
+
+// SetPaneTitle sets target's pane title (#{pane_title}), which tmux shows
+// in pane borders and window lists when configured to.
+func (t *Tmux) SetPaneTitle(ctx context.Context, target, title string) error {
+	if _, err := t.run(ctx, "select-pane", "-t", target, "-T", title); err != nil {
+		return fmt.Errorf("tmux select-pane -t %s -T: %w", target, err)
+	}
+	return nil
+}
+
+// WindowName returns the name of target's window and whether tmux renames
+// it automatically (the automatic-rename option).
+func (t *Tmux) WindowName(ctx context.Context, target string) (name string, autoRename bool, err error) {
+	out, err := t.run(ctx, "display-message", "-p", "-t", target, "#{window_name}\t#{automatic-rename}")
+	if err != nil {
+		return "", false, fmt.Errorf("tmux display-message -t %s: %w", target, err)
+	}
+	name, auto, _ := strings.Cut(strings.TrimRight(out, "\n"), "\t")
+	return name, auto == "1", nil
+}
+
+// ViewedPanes returns the active pane of each attached client's current
+// window: the panes someone may be looking at or typing into.
+func (t *Tmux) ViewedPanes(ctx context.Context) ([]string, error) {
+	out, err := t.run(ctx, "list-clients", "-F", "#{session_name}:#{window_index}.#{pane_index}")
+	if err != nil {
+		return nil, fmt.Errorf("tmux list-clients: %w", err)
+	}
+	return strings.Fields(out), nil
+}
+
+// GlobalOption returns the value of the global option name, or "" when
+// it is not set.
+func (t *Tmux) GlobalOption(ctx context.Context, name string) (string, error) {
+	out, err := t.run(ctx, "show-options", "-gqv", name)
+	if err != nil {
+		return "", fmt.Errorf("tmux show-options -g %s: %w", name, err)
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// SetGlobalOption sets the global option name to value.
+func (t *Tmux) SetGlobalOption(ctx context.Context, name, value string) error {
+	if _, err := t.run(ctx, "set-option", "-g", name, value); err != nil {
+		return fmt.Errorf("tmux set-option -g %s: %w", name, err)
+	}
+	return nil
+}
+
+// UnsetGlobalOption removes the global option name.
+func (t *Tmux) UnsetGlobalOption(ctx context.Context, name string) error {
+	if _, err := t.run(ctx, "set-option", "-gu", name); err != nil {
+		return fmt.Errorf("tmux set-option -gu %s: %w", name, err)
+	}
+	return nil
+}
+
+// LockChannel locks the wait-for channel name (tmux wait-for -L), waiting
+// while another client holds it. The lock is held until UnlockChannel, by
+// any client.
+func (t *Tmux) LockChannel(ctx context.Context, name string) error {
+	if _, err := t.run(ctx, "wait-for", "-L", name); err != nil {
+		return fmt.Errorf("tmux wait-for -L %s: %w", name, err)
+	}
+	return nil
+}
+
+// UnlockChannel unlocks the wait-for channel name (tmux wait-for -U).
+func (t *Tmux) UnlockChannel(ctx context.Context, name string) error {
+	if _, err := t.run(ctx, "wait-for", "-U", name); err != nil {
+		return fmt.Errorf("tmux wait-for -U %s: %w", name, err)
+	}
+	return nil
+}
+
+// SetOption sets option name to value on target. scope is "-p" for a pane
+// option or "-w" for a window option.
+func (t *Tmux) SetOption(ctx context.Context, scope, target, name, value string) error {
+	if _, err := t.run(ctx, "set-option", scope, "-t", target, name, value); err != nil {
+		return fmt.Errorf("tmux set-option %s -t %s %s: %w", scope, target, name, err)
+	}
+	return nil
+}
+
+// UnsetOption removes option name from target so it inherits the value of
+// the enclosing scope again. scope is "-p" or "-w" as for SetOption.
+func (t *Tmux) UnsetOption(ctx context.Context, scope, target, name string) error {
+	if _, err := t.run(ctx, "set-option", scope, "-u", "-t", target, name); err != nil {
+		return fmt.Errorf("tmux set-option %s -u -t %s %s: %w", scope, target, name, err)
+	}
+	return nil
+}
+
+// PaneStart returns the command the pane was created with (empty when it
+// started the default shell) and the working directory of its foreground
+// process.
+func (t *Tmux) PaneStart(ctx context.Context, target string) (command, path string, err error) {
+	out, err := t.run(ctx, "display-message", "-p", "-t", target, "#{pane_start_command}\t#{pane_current_path}")
+	if err != nil {
+		return "", "", fmt.Errorf("tmux display-message -t %s: %w", target, err)
+	}
+	command, path, _ = strings.Cut(strings.TrimRight(out, "\n"), "\t")
+	return command, path, nil
+}
+
+// KillPane closes target, terminating the processes running in it.
+func (t *Tmux) KillPane(ctx context.Context, target string) error {
+	if _, err := t.run(ctx, "kill-pane", "-t", target); err != nil {
+		return fmt.Errorf("tmux kill-pane -t %s: %w", target, err)
+	}
+	return nil
+}
+
+// RespawnPane kills the processes in target and reruns the pane's start
+// command (or the default shell) in dir, keeping the pane in place.
+func (t *Tmux) RespawnPane(ctx context.Context, target, dir string) error {
+	args := []string{"respawn-pane", "-k", "-t", target}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	if _, err := t.run(ctx, args...); err != nil {
+		return fmt.Errorf("tmux respawn-pane -t %s: %w", target, err)
+	}
+	return nil
+}
+
+// HasSession reports whether a tmux session with the exact given name exists.
+func (t *Tmux) HasSession(ctx context.Context, name string) bool {
+	// "=" prefix forces an exact match instead of tmux's prefix matching.
+	_, err := t.run(ctx, "has-session", "-t", "="+name)
+	return err == nil
+}
+
 // run executes a tmux command and returns its stdout.
 func (t *Tmux) run(ctx context.Context, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "tmux", args...)
 	out, err := cmd.Output()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("%w: %s", err, string(exitErr.Stderr))
+			return "", &tmuxError{err: err, stderr: string(exitErr.Stderr)}
 		}
 		return "", err
 	}
 	return string(out), nil
 }
 
-// getProcessTree returns the command lines of all descendant processes of the given PID.
-// Uses a single "ps -eo pid,ppid,args" call to snapshot all processes, then builds
-// the tree in Go — O(1) subprocess invocations instead of O(N) per scan.
-//
-// Walks up to maxProcessTreeDepth levels deep to capture subprocesses spawned by
-// agents (covers wrapper scripts, version manager shims, and agent binaries).
-// The result is capped at maxProcessTreeEntries to keep the verdict compact
-// by excluding LSP servers and other long-running child processes that don't help classification.
-// Returns nil on any error — process info is best-effort, never fatal.
-const maxProcessTreeDepth = 5
-const maxProcessTreeEntries = 15
+// tmuxError is a failed tmux command with its stderr. It wraps
+// ErrPaneNotFound or ErrNoServer when stderr carries tmux's message for
+// those.
+type tmuxError struct {
+	err    error
+	stderr string
+}
 
-func getProcessTree(pid int) []string {
-	if pid <= 0 {
-		return nil
+func (e *tmuxError) Error() string {
+	return fmt.Sprintf("%v: %s", e.err, e.stderr)
+}
+
+func (e *tmuxError) Unwrap() []error {
+	errs := []error{e.err}
+	switch {
+	case strings.Contains(e.stderr, "can't find pane"),
+		strings.Contains(e.stderr, "can't find window"),
+		strings.Contains(e.stderr, "can't find session"):
+		errs = append(errs, ErrPaneNotFound)
+	case strings.Contains(e.stderr, "no server running"),
+		strings.Contains(e.stderr, "error connecting to"),
+		strings.Contains(e.stderr, "server exited unexpectedly"),
+		strings.Contains(e.stderr, "lost server"):
+		errs = append(errs, ErrNoServer)
 	}
+	return errs
+}
+
+// processTable maps parent PIDs to their child processes, from one
+// "ps -eo pid,ppid,args" snapshot; trees are then built in Go, so a scan
+// runs one ps instead of one per pane.
+type processTable map[int][]process
+
+type process struct {
+	pid  int
+	args string
+}
+
+// Process trees are walked up to maxProcessTreeDepth levels deep to capture
+// subprocesses spawned by agents (covers wrapper scripts, version manager
+// shims, and agent binaries). The result is capped at maxProcessTreeEntries
+// to keep the verdict compact by excluding LSP servers and other
+// long-running child processes that don't help classification.
+const maxProcessTreeDepth = 5
+const maxProcessTreeEntries = 15
 
-	// Single subprocess call: snapshot all processes with their parent PID.
+// snapshotProcesses lists all processes with their parent PID. It returns
+// an empty table on any error — process info is best-effort, never fatal.
+func snapshotProcesses() processTable {
 	// "pid=" and "ppid=" suppress the header; "args=" gives full command line.
-	cmd := exec.Command("ps", "-eo", "pid=,ppid=,args=")
-	out, err := cmd.Output()
+	out, err := exec.Command("ps", "-eo", "pid=,ppid=,args=").Output()
 	if err != nil {
-		return nil
+		return processTable{}
 	}
+	return parseProcessTable(string(out))
+}
 
-	// Build parent -> children map and pid -> args map.
-	type proc struct {
-		pid  int
-		ppid int
-		args string
-	}
-	children := map[int][]proc{}
-	for _, line := range strings.Split(string(out), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		// Format: "  PID  PPID ARGS..." — fields are space-separated,
-		// but ARGS can contain spaces. Split into at most 3 fields.
-		fields := strings.SplitN(line, " ", 3)
+// parseProcessTable parses "PID PPID ARGS..." lines as printed by ps.
+func parseProcessTable(out string) processTable {
+	procs := processTable{}
+	for _, line := range strings.Split(out, "\n") {
+		// Fields are separated by variable whitespace, and ARGS can
+		// contain spaces: split off the two numbers, keep the rest.
+		fields := strings.Fields(line)
 		if len(fields) < 3 {
-			// Try harder: ps output may have variable whitespace between PID and PPID.
-			fields = strings.Fields(line)
-			if len(fields) < 3 {
-				continue
-			}
-			// Rejoin everything after the second field as args.
-			// Find the position after the second number in the original line.
-			rest := line
-			for i := 0; i < 2; i++ {
-				rest = strings.TrimSpace(rest)
-				idx := strings.IndexByte(rest, ' ')
-				if idx < 0 {
-					rest = ""
-					break
-				}
-				rest = rest[idx:]
-			}
-			rest = strings.TrimSpace(rest)
-			fields = []string{fields[0], fields[1], rest}
+			continue
 		}
-
-		p, err1 := strconv.Atoi(strings.TrimSpace(fields[0]))
-		pp, err2 := strconv.Atoi(strings.TrimSpace(fields[1]))
+		p, err1 := strconv.Atoi(fields[0])
+		pp, err2 := strconv.Atoi(fields[1])
 		if err1 != nil || err2 != nil {
 			continue
 		}
-		args := strings.TrimSpace(fields[2])
-		if args == "" {
-			continue
+		rest := strings.TrimSpace(line)
+		for range 2 {
+			rest = strings.TrimLeft(rest[strings.IndexAny(rest, " \t"):], " \t")
 		}
-		children[pp] = append(children[pp], proc{pid: p, ppid: pp, args: args})
+		procs[pp] = append(procs[pp], process{pid: p, args: rest})
+	}
+	return procs
+}
+
+// tree returns the command lines of all descendant processes of pid,
+// indented two spaces per level below the first.
+func (procs processTable) tree(pid int) []string {
+	if pid <= 0 {
+		return nil
 	}
 
 	// Walk the tree from the root PID using BFS with depth tracking.
@@ -185,7 +533,7 @@ func getProcessTree(pid int) []string {
 			continue
 		}
 		indent := strings.Repeat("  ", e.depth)
-		for _, child := range children[e.pid] {
+		for _, child := range procs[e.pid] {
 			if len(tree) >= maxProcessTreeEntries {
 				break
 			}
@@ -0,0 +1,72 @@
+package mux
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestProcessTable_Tree(t *testing.T) {
+	procs := parseProcessTable(`    1     0 /sbin/init
+  100     1 -zsh
+  200   100 node /usr/local/bin/claude --resume
+  300   200 bash -c  go test ./...
+  400     1 tmux
+garbage line
+`)
+	want := []string{"node /usr/local/bin/claude --resume", "  bash -c  go test ./..."}
+	if got := procs.tree(100); !slices.Equal(got, want) {
+		t.Errorf("tree(100) = %q, want %q", got, want)
+	}
+	if got := procs.tree(400); got != nil {
+		t.Errorf("tree(400) = %q, want none", got)
+	}
+	if got := procs.tree(0); got != nil {
+		t.Errorf("tree(0) = %q, want none", got)
+	}
+}
+
+func TestBatchCapture(t *testing.T) {
+	args := batchCaptureArgs([]string{"dev:0.0", "dev:0.1"}, TmuxFeatures{CaptureTrimTrailing: true}, "M")
+	want := []string{
+		"capture-pane", "-t", "dev:0.0", "-p", "-J", "-T", ";", "display-message", "-p", "M", ";",
+		"capture-pane", "-t", "dev:0.1", "-p", "-J", "-T", ";", "display-message", "-p", "M",
+	}
+	if !slices.Equal(args, want) {
+		t.Errorf("batchCaptureArgs:\n got %q\nwant %q", args, want)
+	}
+
+	parts, err := splitBatchCapture("$ ls\nfoo\nM\n\n\nM\n", "M", 2)
+	if err != nil || !slices.Equal(parts, []string{"$ ls\nfoo\n", "\n\n"}) {
+		t.Errorf("splitBatchCapture: got %q, %v", parts, err)
+	}
+	// Output cut short, e.g. by a pane closing mid-chain, is an error.
+	if _, err := splitBatchCapture("$ ls\nM\n", "M", 2); err == nil {
+		t.Error("expected an error for a missing capture")
+	}
+}
+
+func TestTmuxError_Classification(t *testing.T) {
+	exit := errors.New("exit status 1")
+	tests := []struct {
+		stderr string
+		want   error
+	}{
+		{"can't find pane: %42\n", ErrPaneNotFound},
+		{"can't find session: dev\n", ErrPaneNotFound},
+		{"no server running on /tmp/tmux-1000/default\n", ErrNoServer},
+		{"error connecting to /tmp/tmux-1000/default (No such file or directory)\n", ErrNoServer},
+		{"unknown command: foo\n", nil},
+	}
+	for _, tt := range tests {
+		err := &tmuxError{err: exit, stderr: tt.stderr}
+		if !errors.Is(err, exit) {
+			t.Errorf("%q: expected the exit error to stay wrapped", tt.stderr)
+		}
+		for _, sentinel := range []error{ErrPaneNotFound, ErrNoServer} {
+			if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+				t.Errorf("%q: errors.Is(%v) = %v", tt.stderr, sentinel, got)
+			}
+		}
+	}
+}
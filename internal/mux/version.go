@@ -0,0 +1,171 @@
+package mux
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// MinTmuxMajor and MinTmuxMinor are the oldest tmux release pane-patrol is
+// tested against (3.2). Older servers still work for scanning, but features
+// such as popups are unavailable.
+const (
+	MinTmuxMajor = 3
+	MinTmuxMinor = 2
+)
+
+// TmuxVersion is a parsed `tmux -V` version. Development builds
+// ("next-3.6", "master") are treated as newer than any release.
+type TmuxVersion struct {
+	Major int
+	Minor int
+	// Suffix is the patch letter of a release ("a" in 3.3a).
+	Suffix string
+	// Dev is true for unreleased builds.
+	Dev bool
+	// Raw is the original version string.
+	Raw string
+}
+
+// String returns the version as printed by tmux (without the "tmux " prefix).
+func (v TmuxVersion) String() string {
+	if v.Raw != "" {
+		return v.Raw
+	}
+	return fmt.Sprintf("%d.%d%s", v.Major, v.Minor, v.Suffix)
+}
+
+// AtLeast reports whether v is major.minor or newer.
+func (v TmuxVersion) AtLeast(major, minor int) bool {
+	if v.Dev && v.Major == 0 {
+		return true
+	}
+	if v.Major != major {
+		return v.Major > major
+	}
+	return v.Minor >= minor
+}
+
+// ParseTmuxVersion parses the output of `tmux -V`, e.g. "tmux 3.3a",
+// "tmux 3.5", "tmux next-3.6", or "tmux master".
+func ParseTmuxVersion(s string) (TmuxVersion, error) {
+	raw := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "tmux"))
+	v := TmuxVersion{Raw: raw}
+	switch {
+	case raw == "master":
+		v.Dev = true
+		return v, nil
+	case strings.HasPrefix(raw, "next-"):
+		v.Dev = true
+		raw = strings.TrimPrefix(raw, "next-")
+	case strings.HasPrefix(raw, "openbsd-"):
+		// OpenBSD base tracks tmux master.
+		v.Dev = true
+		return v, nil
+	}
+
+	major, rest, ok := strings.Cut(raw, ".")
+	if !ok {
+		return TmuxVersion{}, fmt.Errorf("unrecognized tmux version %q", s)
+	}
+	var err error
+	if v.Major, err = strconv.Atoi(major); err != nil {
+		return TmuxVersion{}, fmt.Errorf("unrecognized tmux version %q", s)
+	}
+	digits := 0
+	for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+		digits++
+	}
+	if v.Minor, err = strconv.Atoi(rest[:digits]); err != nil {
+		return TmuxVersion{}, fmt.Errorf("unrecognized tmux version %q", s)
+	}
+	// "3.3a" → suffix "a"; "3.0-rc" → suffix "-rc".
+	v.Suffix = rest[digits:]
+	return v, nil
+}
+
+// TmuxFeatures are the version-dependent tmux behaviors pane-patrol gates
+// on. Each flag names the release that introduced it.
+type TmuxFeatures struct {
+	Version TmuxVersion
+	// Known is false when the version could not be probed; the flags then
+	// describe the minimum supported release.
+	Known bool
+
+	// Popup: display-popup exists (3.2).
+	Popup bool
+	// PopupTitle: display-popup accepts -T title and -b border lines (3.3).
+	PopupTitle bool
+	// CaptureTrimTrailing: capture-pane accepts -T, which stops each line at
+	// the last used cell instead of padding to the pane width (3.4). Older
+	// servers pad joined lines with trailing spaces, so capture output is
+	// trimmed in Go instead.
+	CaptureTrimTrailing bool
+}
+
+// FeaturesFor returns the feature set of a tmux version.
+func FeaturesFor(v TmuxVersion) TmuxFeatures {
+	return TmuxFeatures{
+		Version:             v,
+		Known:               true,
+		Popup:               v.AtLeast(3, 2),
+		PopupTitle:          v.AtLeast(3, 3),
+		CaptureTrimTrailing: v.AtLeast(3, 4),
+	}
+}
+
+// Supported reports whether the tmux version is at least the minimum
+// pane-patrol is tested against. Unknown versions are assumed supported.
+func (f TmuxFeatures) Supported() bool {
+	return !f.Known || f.Version.AtLeast(MinTmuxMajor, MinTmuxMinor)
+}
+
+// baselineFeatures is used when `tmux -V` fails: assume the minimum
+// supported release so no newer-only flag is passed.
+func baselineFeatures() TmuxFeatures {
+	f := FeaturesFor(TmuxVersion{Major: MinTmuxMajor, Minor: MinTmuxMinor})
+	f.Known = false
+	return f
+}
+
+// probeTmuxVersion runs `tmux -V`. It does not need a running server.
+func probeTmuxVersion(ctx context.Context) (TmuxVersion, error) {
+	out, err := exec.CommandContext(ctx, "tmux", "-V").Output()
+	if err != nil {
+		return TmuxVersion{}, fmt.Errorf("tmux -V: %w", err)
+	}
+	return ParseTmuxVersion(string(out))
+}
+
+// captureArgs builds the capture-pane arguments for a feature set.
+// -p prints to stdout; -J joins wrapped lines.
+func captureArgs(target string, f TmuxFeatures) []string {
+	args := []string{"capture-pane", "-t", target, "-p", "-J"}
+	if f.CaptureTrimTrailing {
+		args = append(args, "-T")
+	}
+	return args
+}
+
+// trimTrailingSpace strips trailing spaces from every line, matching what
+// capture-pane -T produces on tmux 3.4+.
+func trimTrailingSpace(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SendKeysArgs builds tmux send-keys arguments. Literal text is sent with
+// -l and preceded by "--", so text starting with "-" (e.g. answering "-y")
+// is typed instead of being parsed as a send-keys flag.
+func SendKeysArgs(target string, literal bool, keys string) []string {
+	args := []string{"send-keys", "-t", target}
+	if literal {
+		args = append(args, "-l", "--")
+	}
+	return append(args, keys)
+}
@@ -0,0 +1,121 @@
+package mux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTmuxVersion(t *testing.T) {
+	tests := []struct {
+		in           string
+		major, minor int
+		suffix       string
+		dev          bool
+	}{
+		{"tmux 3.2", 3, 2, "", false},
+		{"tmux 3.2a\n", 3, 2, "a", false},
+		{"tmux 3.3a", 3, 3, "a", false},
+		{"tmux 3.4", 3, 4, "", false},
+		{"tmux 3.5a", 3, 5, "a", false},
+		{"tmux 3.0-rc5", 3, 0, "-rc5", false},
+		{"tmux next-3.6", 3, 6, "", true},
+		{"tmux master", 0, 0, "", true},
+	}
+	for _, tt := range tests {
+		v, err := ParseTmuxVersion(tt.in)
+		if err != nil {
+			t.Errorf("ParseTmuxVersion(%q): %v", tt.in, err)
+			continue
+		}
+		if v.Major != tt.major || v.Minor != tt.minor || v.Suffix != tt.suffix || v.Dev != tt.dev {
+			t.Errorf("ParseTmuxVersion(%q) = %+v", tt.in, v)
+		}
+	}
+}
+
+func TestParseTmuxVersion_Invalid(t *testing.T) {
+	for _, in := range []string{"", "tmux", "tmux three", "tmux 3.x"} {
+		if _, err := ParseTmuxVersion(in); err == nil {
+			t.Errorf("ParseTmuxVersion(%q): expected error", in)
+		}
+	}
+}
+
+func TestFeaturesFor_Releases(t *testing.T) {
+	tests := []struct {
+		version                      string
+		popup, popupTitle, trimTrail bool
+		supported                    bool
+	}{
+		{"tmux 3.1c", false, false, false, false},
+		{"tmux 3.2a", true, false, false, true},
+		{"tmux 3.3a", true, true, false, true},
+		{"tmux 3.4", true, true, true, true},
+		{"tmux 3.5a", true, true, true, true},
+		{"tmux next-3.6", true, true, true, true},
+		{"tmux master", true, true, true, true},
+	}
+	for _, tt := range tests {
+		v, err := ParseTmuxVersion(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := FeaturesFor(v)
+		if f.Popup != tt.popup || f.PopupTitle != tt.popupTitle || f.CaptureTrimTrailing != tt.trimTrail {
+			t.Errorf("%s: got %+v", tt.version, f)
+		}
+		if f.Supported() != tt.supported {
+			t.Errorf("%s: Supported() = %v, want %v", tt.version, f.Supported(), tt.supported)
+		}
+	}
+}
+
+func TestBaselineFeatures(t *testing.T) {
+	f := baselineFeatures()
+	if f.Known {
+		t.Error("baseline features should be marked unknown")
+	}
+	if !f.Supported() {
+		t.Error("unknown versions should be assumed supported")
+	}
+	if f.CaptureTrimTrailing {
+		t.Error("baseline must not pass flags newer than the minimum release")
+	}
+}
+
+func TestCaptureArgs(t *testing.T) {
+	v33, _ := ParseTmuxVersion("tmux 3.3a")
+	v34, _ := ParseTmuxVersion("tmux 3.4")
+
+	got := captureArgs("s:0.1", FeaturesFor(v33))
+	want := []string{"capture-pane", "-t", "s:0.1", "-p", "-J"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("3.3a: got %q, want %q", got, want)
+	}
+	got = captureArgs("s:0.1", FeaturesFor(v34))
+	want = []string{"capture-pane", "-t", "s:0.1", "-p", "-J", "-T"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("3.4: got %q, want %q", got, want)
+	}
+}
+
+func TestTrimTrailingSpace(t *testing.T) {
+	in := "  ❯ 1. Yes   \n\n? for shortcuts      \n"
+	want := "  ❯ 1. Yes\n\n? for shortcuts\n"
+	if got := trimTrailingSpace(in); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSendKeysArgs(t *testing.T) {
+	got := SendKeysArgs("s:0.1", true, "-y")
+	want := []string{"send-keys", "-t", "s:0.1", "-l", "--", "-y"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("literal: got %q, want %q", got, want)
+	}
+	got = SendKeysArgs("s:0.1", false, "Enter")
+	want = []string{"send-keys", "-t", "s:0.1", "Enter"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("key: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"strings"
+)
+
+// activityLines is the number of output lines kept above a blocked pane's
+// dialog or prompt as Result.Activity.
+const activityLines = 5
+
+// activityWindow is how far above the bottom of the capture the dialog's
+// text is looked for.
+const activityWindow = 40
+
+// frameLines is how far above the dialog's text its frame (a box's top
+// border or a rule) may start.
+const frameLines = 6
+
+// annotateActivity sets a blocked result's Activity: the last lines of
+// output above the dialog or prompt, i.e. what the agent did before it
+// blocked. The dialog is found by its text (WaitingFor and the question)
+// near the bottom of the capture, extended up to its frame; a pane idle at
+// its prompt has no dialog text, so the bottom block of lines (the prompt
+// and its footer) is taken instead.
+func annotateActivity(r *Result, content string) {
+	if r == nil || !r.Blocked {
+		return
+	}
+	lines := strings.Split(content, "\n")
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	lines = lines[:end]
+	r.Activity = activityAbove(lines, dialogTop(lines, r), activityLines)
+}
+
+// dialogTop returns the index of the first line of the dialog or prompt r
+// is blocked on.
+func dialogTop(lines []string, r *Result) int {
+	top := -1
+	for _, want := range dialogAnchors(r) {
+		for i := len(lines) - 1; i >= max(len(lines)-activityWindow, 0); i-- {
+			if strings.Contains(lines[i], want) {
+				if top < 0 || i < top {
+					top = i
+				}
+				break
+			}
+		}
+	}
+	if top < 0 {
+		top = len(lines) - 1
+		for top > 0 && strings.TrimSpace(lines[top-1]) != "" {
+			top--
+		}
+		return max(top, 0)
+	}
+	for top > 0 && isBarLine(lines[top-1]) {
+		top--
+	}
+	for i := top - 1; i >= 0 && i >= top-frameLines; i-- {
+		if isRuleLine(lines[i]) {
+			return i
+		}
+	}
+	return top
+}
+
+// dialogAnchors returns the texts that identify r's dialog on screen: the
+// lines of WaitingFor (Claude Code's "Tool — description" split in two)
+// and the dialog's question.
+func dialogAnchors(r *Result) []string {
+	var anchors []string
+	for _, line := range strings.Split(r.WaitingFor, "\n") {
+		for _, part := range strings.Split(line, " — ") {
+			if part = strings.TrimSpace(part); len([]rune(part)) >= 4 {
+				anchors = append(anchors, part)
+			}
+		}
+	}
+	if r.Dialog != nil && r.Dialog.Question != "" {
+		anchors = append(anchors, r.Dialog.Question)
+	}
+	return anchors
+}
+
+// activityAbove returns up to n non-empty lines above lines[top], in screen
+// order, trimmed and without frame lines.
+func activityAbove(lines []string, top, n int) []string {
+	var activity []string
+	for i := top - 1; i >= 0 && len(activity) < n; i-- {
+		if isRuleLine(lines[i]) {
+			continue
+		}
+		line := trimRightPanel(stripDialogPrefix(strings.TrimSpace(strings.Trim(lines[i], " │"))))
+		if line == "" {
+			continue
+		}
+		activity = append(activity, line)
+	}
+	for i, j := 0, len(activity)-1; i < j; i, j = i+1, j-1 {
+		activity[i], activity[j] = activity[j], activity[i]
+	}
+	return activity
+}
+
+// isBarLine reports whether line is inside a box or OpenCode's ┃ bar.
+func isBarLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "│") || strings.HasPrefix(trimmed, "┃")
+}
+
+// isRuleLine reports whether line is a box border or a horizontal rule.
+func isRuleLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "╭") || strings.HasPrefix(trimmed, "╰") ||
+		strings.HasPrefix(trimmed, "┌") || strings.HasPrefix(trimmed, "└") {
+		return true
+	}
+	return len([]rune(trimmed)) >= 3 && strings.Trim(trimmed, "─━═") == ""
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"strings"
 	"unicode"
 
@@ -44,6 +45,10 @@ import (
 // Footer: "Esc to cancel · Tab to amend"
 // Active: tool-specific progress messages
 // Auto-resolve: "Auto-selecting in {N}s…"
+// Startup: "Do you trust the files in this folder?" (1. Yes, proceed /
+// 2. No, exit), then onboarding screens on first run: the theme picker
+// ("Choose the text style that looks best with your terminal"), "Use
+// Claude Code's terminal setup?", and notices with "Press Enter to continue"
 //
 // Input handling: Permission dialogs use the Select component (UA) which
 // renders numbered options (1. Yes, 2. Yes and don't ask again, 3. No).
@@ -150,10 +155,18 @@ type ClaudeCodeParser struct{}
 func (p *ClaudeCodeParser) Name() string { return "claude_code" }
 
 func (p *ClaudeCodeParser) Parse(content string, processTree []string) *Result {
-	if !p.isClaudeCode(content, processTree) {
+	confidence, evidence := p.identify(content, processTree)
+	if confidence == 0 {
 		return nil
 	}
+	r := p.parseState(content)
+	r.Confidence = confidence
+	r.Evidence = evidence
+	return r
+}
 
+// parseState classifies the Claude Code pane state (idle, dialog, active).
+func (p *ClaudeCodeParser) parseState(content string) *Result {
 	// Check idle at bottom FIRST: if the bottom of the screen shows a clear
 	// idle prompt, any dialog text or active indicators above it are stale
 	// (from a prior turn or the agent's own output) and should be ignored.
@@ -163,7 +176,7 @@ func (p *ClaudeCodeParser) Parse(content string, processTree []string) *Result {
 		return &Result{
 			Agent:      "claude_code",
 			Blocked:    true,
-			Reason:     "idle at prompt",
+			Reason:     IdleReason,
 			WaitingFor: "idle at prompt",
 			Actions: []model.Action{
 				{Keys: "Enter", Label: "send empty message / continue", Risk: "low", Raw: true},
@@ -173,7 +186,14 @@ func (p *ClaudeCodeParser) Parse(content string, processTree []string) *Result {
 		}
 	}
 
-	// Not idle — check for dialog states (permission, edit, auto-resolve).
+	// Not idle — check for dialog states (startup, permission, edit,
+	// auto-resolve).
+	if r := p.parseTrustDialog(content); r != nil {
+		return r
+	}
+	if r := p.parseOnboarding(content); r != nil {
+		return r
+	}
 	if r := p.parsePermissionDialog(content); r != nil {
 		return r
 	}
@@ -197,7 +217,7 @@ func (p *ClaudeCodeParser) Parse(content string, processTree []string) *Result {
 	return &Result{
 		Agent:      "claude_code",
 		Blocked:    true,
-		Reason:     "idle at prompt",
+		Reason:     IdleReason,
 		WaitingFor: "idle at prompt",
 		Actions: []model.Action{
 			{Keys: "Enter", Label: "send empty message / continue", Risk: "low", Raw: true},
@@ -269,34 +289,41 @@ func (p *ClaudeCodeParser) isIdleAtBottom(content string) bool {
 	return hasPrompt
 }
 
-func (p *ClaudeCodeParser) isClaudeCode(content string, processTree []string) bool {
+// identify returns the confidence level and a description of the strongest
+// evidence that this pane is running Claude Code, or 0 if it is not. The "? for shortcuts"
+// footer and spinner glyphs are also rendered by other agents (Codex shows
+// the same footer), so they only count as shared evidence.
+func (p *ClaudeCodeParser) identify(content string, processTree []string) (float64, string) {
 	for _, proc := range processTree {
 		lower := strings.ToLower(proc)
 		// Match "claude" process but not "claude-code-supervisor" etc.
 		if strings.Contains(lower, "claude") && !strings.Contains(lower, "pane-patrol") &&
 			!strings.Contains(lower, "pane-supervisor") {
-			return true
+			return ConfidenceProcess, fmt.Sprintf("process %q", proc)
 		}
 	}
 	// Fallback: look for Claude Code-specific TUI markers
 	if strings.Contains(content, "Claude needs your permission") {
-		return true
+		return ConfidenceMarker, `marker "Claude needs your permission"`
+	}
+	if strings.Contains(content, claudeTrustMarker) {
+		return ConfidenceMarker, `marker "` + claudeTrustMarker + `"`
 	}
 	if strings.Contains(content, "Esc to cancel") && strings.Contains(content, "Tab to amend") {
-		return true
+		return ConfidenceMarker, `marker "Esc to cancel" + "Tab to amend"`
 	}
 	if strings.Contains(content, "Do you want to proceed?") && p.hasNumberedOptions(content) {
-		return true
+		return ConfidenceMarker, `marker "Do you want to proceed?" + numbered options`
 	}
 	// "? for shortcuts" is the persistent footer in Claude Code's TUI
 	if strings.Contains(content, "? for shortcuts") {
-		return true
+		return ConfidenceShared, `shared marker "? for shortcuts"`
 	}
 	// Claude Code's unique thinking/working indicator characters
 	if containsSpinnerIndicator(content) {
-		return true
+		return ConfidenceShared, "shared marker spinner glyph"
 	}
-	return false
+	return 0, ""
 }
 
 // parsePermissionDialog detects "Claude needs your permission to use" or
@@ -334,11 +361,26 @@ func (p *ClaudeCodeParser) parsePermissionDialog(content string) *Result {
 		})
 	}
 
+	question := "Do you want to proceed?"
+	if !hasProceed {
+		question = markerLine(content, "Claude needs your permission")
+	}
+	labels := []string{"Yes", "No"}
+	if hasDontAsk {
+		labels = []string{"Yes", "Yes, and don't ask again", "No"}
+	}
+
 	return &Result{
-		Agent:       "claude_code",
-		Blocked:     true,
-		Reason:      "permission dialog waiting for approval",
-		WaitingFor:  waitingFor,
+		Agent:      "claude_code",
+		Blocked:    true,
+		Reason:     "permission dialog waiting for approval",
+		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:      model.DialogPermission,
+			Question:  question,
+			Options:   fixedOptions(selectedOption(content), labels...),
+			ActiveTab: -1,
+		},
 		Actions:     actions,
 		Recommended: 0,
 		Reasoning:   "deterministic parser: Claude Code permission dialog detected",
@@ -360,6 +402,12 @@ func (p *ClaudeCodeParser) parseEditApproval(content string) *Result {
 		Blocked:    true,
 		Reason:     "edit approval dialog",
 		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:      model.DialogPermission,
+			Question:  markerLine(content, "Do you want to make this edit to"),
+			Options:   fixedOptions(selectedOption(content), "Yes", "No"),
+			ActiveTab: -1,
+		},
 		Actions: []model.Action{
 			{Keys: "1", Label: "approve edit", Risk: "medium", Raw: true},
 			{Keys: "2", Label: "reject edit", Risk: "low", Raw: true},
@@ -369,6 +417,135 @@ func (p *ClaudeCodeParser) parseEditApproval(content string) *Result {
 	}
 }
 
+// claudeTrustMarker is the question of the dialog a fresh session shows in a
+// folder it has not been trusted in.
+const claudeTrustMarker = "Do you trust the files in this folder?"
+
+// parseTrustDialog detects the trust-folder dialog shown before the first
+// prompt in a new folder. Trusting lets the agent read and run the folder's
+// files, so it is medium risk; "No, exit" quits Claude Code.
+func (p *ClaudeCodeParser) parseTrustDialog(content string) *Result {
+	if !strings.Contains(content, claudeTrustMarker) {
+		return nil
+	}
+	lines := linesFrom(content, claudeTrustMarker)
+	options, _ := extractDialogOptions(lines)
+	if len(options) == 0 {
+		options = fixedOptions(selectedOption(content), "Yes, proceed", "No, exit")
+	}
+
+	actions := make([]model.Action, 0, len(options))
+	for i, opt := range options {
+		label, risk := "trust folder ("+opt.Label+")", "medium"
+		if strings.HasPrefix(opt.Label, "No") {
+			label, risk = "don't trust ("+opt.Label+")", "low"
+		}
+		actions = append(actions, model.Action{Keys: fmt.Sprint(i + 1), Label: label, Risk: risk, Raw: true})
+	}
+
+	// The folder is shown on the first line below the question.
+	waitingFor := claudeTrustMarker
+	for _, line := range lines[1:] {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			if !isNumberedOption(stripDialogPrefix(strings.TrimLeft(trimmed, "❯ "))) {
+				waitingFor += "\n" + trimmed
+			}
+			break
+		}
+	}
+
+	return &Result{
+		Agent:      "claude_code",
+		Blocked:    true,
+		Reason:     "trust folder dialog at startup",
+		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:      model.DialogPermission,
+			Question:  claudeTrustMarker,
+			Options:   options,
+			ActiveTab: -1,
+		},
+		Actions:     actions,
+		Recommended: 0,
+		Reasoning:   "deterministic parser: Claude Code trust-folder dialog detected",
+	}
+}
+
+// claudeOnboarding are the first-run screens with numbered choices, by the
+// question they show. Any choice is harmless and can be changed later with
+// /config, so the highlighted one is recommended.
+var claudeOnboarding = []string{
+	"Choose the text style that looks best with your terminal",
+	"Use Claude Code's terminal setup?",
+}
+
+// parseOnboarding detects the first-run onboarding screens: pickers with
+// numbered choices and notices that wait for Enter.
+func (p *ClaudeCodeParser) parseOnboarding(content string) *Result {
+	for _, marker := range claudeOnboarding {
+		if !strings.Contains(content, marker) {
+			continue
+		}
+		options, _ := extractDialogOptions(linesFrom(content, marker))
+		if len(options) == 0 {
+			continue
+		}
+		actions := make([]model.Action, 0, len(options))
+		recommended := 0
+		for i, opt := range options {
+			actions = append(actions, model.Action{Keys: fmt.Sprint(i + 1), Label: opt.Label, Risk: "low", Raw: true})
+			if opt.Selected {
+				recommended = i
+			}
+		}
+		return &Result{
+			Agent:      "claude_code",
+			Blocked:    true,
+			Reason:     "onboarding at startup",
+			WaitingFor: markerLine(content, marker),
+			Dialog: &model.Dialog{
+				Kind:      model.DialogQuestion,
+				Question:  markerLine(content, marker),
+				Options:   options,
+				ActiveTab: -1,
+			},
+			Actions:     actions,
+			Recommended: recommended,
+			Reasoning:   fmt.Sprintf("deterministic parser: Claude Code onboarding screen detected (%s)", marker),
+		}
+	}
+
+	bottom := bottomNonEmpty(strings.Split(content, "\n"), bottomLines)
+	for _, line := range bottom {
+		if strings.Contains(line, "Press Enter to continue") {
+			return &Result{
+				Agent:      "claude_code",
+				Blocked:    true,
+				Reason:     "notice waiting for Enter",
+				WaitingFor: strings.TrimSpace(bottom[0]),
+				Actions: []model.Action{
+					{Keys: "Enter", Label: "continue", Risk: "low", Raw: true},
+				},
+				Recommended: 0,
+				Reasoning:   `deterministic parser: Claude Code onboarding notice detected ("Press Enter to continue")`,
+			}
+		}
+	}
+	return nil
+}
+
+// linesFrom returns the lines of content from the last line containing
+// marker, so a dialog's options are read below its question.
+func linesFrom(content, marker string) []string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(lines[i], marker) {
+			return lines[i:]
+		}
+	}
+	return lines
+}
+
 // parseAutoResolve detects "Auto-selecting in {N}s…" — the agent will
 // auto-resolve soon, so it's technically not blocked.
 func (p *ClaudeCodeParser) parseAutoResolve(content string) *Result {
@@ -535,6 +712,20 @@ func (p *ClaudeCodeParser) hasNumberedOptions(content string) bool {
 		(strings.Contains(content, "Yes") || strings.Contains(content, "No"))
 }
 
+// selectedOption returns the 1-based number of the option under the Select
+// component cursor ("❯ 2. No" → 2), or 0 if no cursor line is visible.
+// Searches bottom-up so a stale dialog in scrollback is ignored.
+func selectedOption(content string) int {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if isDialogSelector(trimmed) {
+			return int(trimmed[len("❯ ")] - '0')
+		}
+	}
+	return 0
+}
+
 // isDialogSelector returns true if the line looks like a Claude Code Select
 // component cursor line: "❯ 1. Yes ..." or "❯ 2. No". These have the "❯ "
 // prefix followed by a digit and period. This distinguishes them from idle
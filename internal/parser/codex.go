@@ -2,6 +2,9 @@ package parser
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/timvw/pane-patrol/internal/model"
@@ -21,7 +24,7 @@ import (
 //	Network: "Do you want to approve access to \"{host}\"?"
 //	  Options: "Yes, just this once" / "Yes, and allow this host for this session" / "No, and tell Codex what to do differently"
 //	MCP: "{server_name} needs your approval."
-//	User input: "Yes, provide the requested info" / "No, but continue without it" / "Cancel this request"
+//	  Options: "Yes, provide the requested info" / "No, but continue without it" / "Cancel this request"
 //
 // Source reference: codex-rs/tui/src/bottom_pane/request_user_input/mod.rs
 // Question dialog (RequestUserInputOverlay): agent asks user questions with numbered options.
@@ -29,6 +32,16 @@ import (
 //   - "None of the above" option when is_other is true
 //   - Footer tips: "enter to submit answer" (single), "enter to submit all" (multi-question)
 //   - "esc to interrupt", "tab to add notes", "←/→ to navigate questions"
+//   - "Question 2/3" above the question of a multi-question overlay
+//
+// Source reference: codex-rs/tui/src/onboarding/auth.rs, codex-rs/login
+// Sign-in: "Sign in with ChatGPT" / "Provide your own API key" picker
+// ("> 1." marks the selected option); device-code sign-in shows a link and
+// "Enter this one-time code" followed by the code (e.g. "ABCD-1234").
+//
+// Source reference: codex-rs/tui/src/update_prompt.rs
+// Update prompt: "✨ Update available! {current} -> {latest}" with
+// "1. Update now" / "2. Skip" / "3. Skip until next version"
 //
 // Active state: "Working" header with elapsed time, "({elapsed} · {key} to interrupt)"
 // Footer: "Plan mode" / "Pair Programming mode" / "Execute mode"
@@ -39,9 +52,19 @@ type CodexParser struct{}
 func (p *CodexParser) Name() string { return "codex" }
 
 func (p *CodexParser) Parse(content string, processTree []string) *Result {
-	if !p.isCodex(content, processTree) {
+	confidence, evidence := p.identify(content, processTree)
+	if confidence == 0 {
 		return nil
 	}
+	r := p.parseState(content)
+	r.Model = p.extractModel(content)
+	r.Confidence = confidence
+	r.Evidence = evidence
+	return r
+}
+
+// parseState classifies the Codex pane state (idle, dialog, active).
+func (p *CodexParser) parseState(content string) *Result {
 
 	// Check idle at bottom FIRST: if the bottom of the screen shows a clear
 	// idle prompt, any dialog text or active indicators above it are stale
@@ -50,7 +73,7 @@ func (p *CodexParser) Parse(content string, processTree []string) *Result {
 		return &Result{
 			Agent:      "codex",
 			Blocked:    true,
-			Reason:     "idle at prompt",
+			Reason:     IdleReason,
 			WaitingFor: "idle at prompt",
 			Actions: []model.Action{
 				{Keys: "Enter", Label: "submit / continue", Risk: "low", Raw: true},
@@ -61,6 +84,12 @@ func (p *CodexParser) Parse(content string, processTree []string) *Result {
 	}
 
 	// Not idle — check for dialog states.
+	if r := p.parseAuth(content); r != nil {
+		return r
+	}
+	if r := p.parseUpdatePrompt(content); r != nil {
+		return r
+	}
 	if r := p.parseExecApproval(content); r != nil {
 		return r
 	}
@@ -93,7 +122,7 @@ func (p *CodexParser) Parse(content string, processTree []string) *Result {
 	return &Result{
 		Agent:      "codex",
 		Blocked:    true,
-		Reason:     "idle at prompt",
+		Reason:     IdleReason,
 		WaitingFor: "idle at prompt",
 		Actions: []model.Action{
 			{Keys: "Enter", Label: "submit / continue", Risk: "low", Raw: true},
@@ -103,6 +132,28 @@ func (p *CodexParser) Parse(content string, processTree []string) *Result {
 	}
 }
 
+// extractModel returns the model name from the session header, or "" if the
+// header is not visible.
+//
+// Source: codex-rs/tui/src/history_cell.rs (SessionHeaderHistoryCell)
+// The header box renders "│ model:     {model} {effort}   /model to change │".
+// Only the first token after "model:" is the model name.
+func (p *CodexParser) extractModel(content string) string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "│"))
+		rest, ok := strings.CutPrefix(trimmed, "model:")
+		if !ok {
+			continue
+		}
+		if fields := strings.Fields(rest); len(fields) > 0 && fields[0] != "/model" {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
 // isIdleAtBottom checks if the bottom of the screen shows a clear idle
 // prompt. Codex's idle state has ">" prompt and/or "Plan mode  shift+tab to cycle".
 //
@@ -136,8 +187,9 @@ func (p *CodexParser) isIdleAtBottom(content string) bool {
 			return false
 		}
 
-		// Idle signals
-		if trimmed == ">" || strings.HasPrefix(trimmed, "> ") {
+		// Idle signals. "> 1. ..." is the cursor of an onboarding
+		// picker, not the prompt.
+		if trimmed == ">" || strings.HasPrefix(trimmed, "> ") && !isNumberedOption(trimmed[2:]) {
 			hasIdle = true
 		}
 		if strings.Contains(trimmed, "Plan mode") && strings.Contains(trimmed, "shift+tab") {
@@ -147,37 +199,39 @@ func (p *CodexParser) isIdleAtBottom(content string) bool {
 	return hasIdle
 }
 
-func (p *CodexParser) isCodex(content string, processTree []string) bool {
+// identify returns the confidence level and a description of the strongest
+// evidence that this pane is running Codex, or 0 if it is not.
+func (p *CodexParser) identify(content string, processTree []string) (float64, string) {
 	for _, proc := range processTree {
 		lower := strings.ToLower(proc)
 		if strings.Contains(lower, "codex") {
-			return true
+			return ConfidenceProcess, fmt.Sprintf("process %q", proc)
 		}
 	}
 	// Fallback: look for Codex-specific TUI markers
 	if strings.Contains(content, "Would you like to run the following command?") {
-		return true
+		return ConfidenceMarker, `marker "Would you like to run the following command?"`
 	}
 	if strings.Contains(content, "Would you like to make the following edits?") {
-		return true
+		return ConfidenceMarker, `marker "Would you like to make the following edits?"`
 	}
 	if strings.Contains(content, "approved codex to run") {
-		return true
+		return ConfidenceMarker, `marker "approved codex to run"`
 	}
 	// Mode indicators unique to Codex
 	if (strings.Contains(content, "Plan mode") || strings.Contains(content, "Pair Programming mode") ||
 		strings.Contains(content, "Execute mode")) && strings.Contains(content, "shift+tab to cycle") {
-		return true
+		return ConfidenceMarker, `marker mode indicator + "shift+tab to cycle"`
 	}
 	// Codex splash banner: ">_ OpenAI Codex"
 	if strings.Contains(content, "OpenAI Codex") {
-		return true
+		return ConfidenceMarker, `marker "OpenAI Codex"`
 	}
 	// "? for shortcuts" with "context left" is Codex, not Claude
 	if strings.Contains(content, "? for shortcuts") && strings.Contains(content, "context left") {
-		return true
+		return ConfidenceMarker, `marker "? for shortcuts" + "context left"`
 	}
-	return false
+	return 0, ""
 }
 
 // parseExecApproval detects "Would you like to run the following command?"
@@ -193,6 +247,14 @@ func (p *CodexParser) parseExecApproval(content string) *Result {
 		Blocked:    true,
 		Reason:     "command approval dialog",
 		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:     model.DialogPermission,
+			Question: markerLine(content, "Would you like to run the following command?"),
+			Options: approvalOptions(content, "Would you like to run the following command?",
+				"Yes, proceed", "Yes, and don't ask again for commands that start with this prefix",
+				"No, and tell Codex what to do differently"),
+			ActiveTab: -1,
+		},
 		Actions: []model.Action{
 			{Keys: "Enter", Label: "yes, proceed (approve command)", Risk: "medium", Raw: true},
 			{Keys: "Down Enter", Label: "yes, and don't ask again for this prefix", Risk: "medium", Raw: true},
@@ -217,6 +279,13 @@ func (p *CodexParser) parseEditApproval(content string) *Result {
 		Blocked:    true,
 		Reason:     "edit approval dialog",
 		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:     model.DialogPermission,
+			Question: markerLine(content, "Would you like to make the following edits?"),
+			Options: approvalOptions(content, "Would you like to make the following edits?",
+				"Yes, proceed", "Yes, and don't ask again for these files", "No, and tell Codex what to do differently"),
+			ActiveTab: -1,
+		},
 		Actions: []model.Action{
 			{Keys: "Enter", Label: "yes, proceed (approve edits)", Risk: "medium", Raw: true},
 			{Keys: "Down Enter", Label: "yes, and don't ask again for these files", Risk: "medium", Raw: true},
@@ -241,6 +310,13 @@ func (p *CodexParser) parseNetworkApproval(content string) *Result {
 		Blocked:    true,
 		Reason:     "network access approval dialog",
 		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:     model.DialogPermission,
+			Question: markerLine(content, "Do you want to approve access to"),
+			Options: approvalOptions(content, "Do you want to approve access to",
+				"Yes, just this once", "Yes, and allow this host for this session", "No, and tell Codex what to do differently"),
+			ActiveTab: -1,
+		},
 		Actions: []model.Action{
 			{Keys: "Enter", Label: "yes, just this once", Risk: "medium", Raw: true},
 			{Keys: "Down Enter", Label: "yes, allow this host for session", Risk: "medium", Raw: true},
@@ -265,8 +341,17 @@ func (p *CodexParser) parseMCPApproval(content string) *Result {
 		Blocked:    true,
 		Reason:     "MCP server approval dialog",
 		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:     model.DialogPermission,
+			Question: markerLine(content, "needs your approval"),
+			Options: approvalOptions(content, "needs your approval",
+				"Yes, provide the requested info", "No, but continue without it", "Cancel this request"),
+			ActiveTab: -1,
+		},
 		Actions: []model.Action{
-			{Keys: "Enter", Label: "approve", Risk: "medium", Raw: true},
+			{Keys: "Enter", Label: "yes, provide the requested info", Risk: "medium", Raw: true},
+			{Keys: "Down Enter", Label: "no, but continue without it", Risk: "low", Raw: true},
+			{Keys: "Down Down Enter", Label: "cancel this request", Risk: "low", Raw: true},
 			{Keys: "Escape", Label: "cancel", Risk: "low", Raw: true},
 		},
 		Recommended: 0,
@@ -274,6 +359,24 @@ func (p *CodexParser) parseMCPApproval(content string) *Result {
 	}
 }
 
+// approvalOptions returns an approval dialog's options, which its actions
+// follow: the ones on screen when they are the options approval_overlay.rs
+// lists (labels), otherwise labels, with the one under the cursor ("›")
+// marked.
+func approvalOptions(content, marker string, labels ...string) []model.DialogOption {
+	visible, _ := extractDialogOptions(linesFrom(content, marker))
+	if len(visible) == len(labels) {
+		return visible
+	}
+	selected := 0
+	for i, o := range visible {
+		if o.Selected {
+			selected = i + 1
+		}
+	}
+	return fixedOptions(selected, labels...)
+}
+
 // parseQuestionDialog detects the Codex RequestUserInputOverlay question dialog.
 //
 // Source: codex-rs/tui/src/bottom_pane/request_user_input/mod.rs
@@ -300,6 +403,15 @@ func (p *CodexParser) parseQuestionDialog(content string) *Result {
 		return nil
 	}
 
+	// The position of a multi-question overlay ("Question 2/3") becomes
+	// its tabs, and is not part of the question.
+	tabs, activeTab := codexQuestionTabs(bottom)
+	if tabs != nil {
+		lines = slices.DeleteFunc(lines, func(line string) bool {
+			return codexQuestionProgress.MatchString(strings.TrimSpace(line))
+		})
+	}
+
 	// Extract question text and options for WaitingFor.
 	waitingFor := extractQuestionSummary(lines)
 
@@ -309,6 +421,7 @@ func (p *CodexParser) parseQuestionDialog(content string) *Result {
 	optionCount := countNumberedOptions(lines)
 
 	optionLabels := extractOptionLabels(lines)
+	options, _ := extractDialogOptions(lines)
 
 	actions := make([]model.Action, 0, optionCount+2)
 	for i := 1; i <= optionCount && i <= 9; i++ {
@@ -323,12 +436,39 @@ func (p *CodexParser) parseQuestionDialog(content string) *Result {
 			Raw:   true,
 		})
 	}
+	// "tab to add notes" moves focus to a notes field under the options;
+	// Enter there submits the highlighted option with the notes.
+	if hasFooterTip(bottom, "tab to add notes") {
+		actions = append(actions, model.Action{
+			Keys:  "Tab",
+			Label: "add notes",
+			Risk:  "low",
+			Raw:   true,
+			Text:  true,
+		})
+	}
 	actions = append(actions, model.Action{
 		Keys:  "Enter",
 		Label: "submit answer",
 		Risk:  "low",
 		Raw:   true,
 	})
+	// Question navigation for multi-question overlays, the counterpart of
+	// OpenCode's tabs.
+	if hasFooterTip(bottom, "navigate questions") {
+		actions = append(actions, model.Action{
+			Keys:  "Right",
+			Label: "next question",
+			Risk:  "low",
+			Raw:   true,
+		})
+		actions = append(actions, model.Action{
+			Keys:  "Left",
+			Label: "prev question",
+			Risk:  "low",
+			Raw:   true,
+		})
+	}
 	actions = append(actions, model.Action{
 		Keys:  "Escape",
 		Label: "interrupt / dismiss",
@@ -337,16 +477,175 @@ func (p *CodexParser) parseQuestionDialog(content string) *Result {
 	})
 
 	return &Result{
-		Agent:       "codex",
-		Blocked:     true,
-		Reason:      "question dialog waiting for answer",
-		WaitingFor:  waitingFor,
+		Agent:      "codex",
+		Blocked:    true,
+		Reason:     "question dialog waiting for answer",
+		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:      model.DialogQuestion,
+			Question:  extractQuestionText(lines),
+			Options:   options,
+			Tabs:      tabs,
+			ActiveTab: activeTab,
+		},
 		Actions:     actions,
 		Recommended: 0,
 		Reasoning:   "deterministic parser: Codex question dialog detected (enter to submit footer)",
 	}
 }
 
+// hasFooterTip reports whether the question dialog's footer shows tip, e.g.
+// "tab to add notes".
+func hasFooterTip(bottom []string, tip string) bool {
+	for _, line := range bottom {
+		if strings.Contains(line, tip) {
+			return true
+		}
+	}
+	return false
+}
+
+// codexQuestionProgress matches the position shown above a question of a
+// multi-question overlay, e.g. "Question 2/3".
+var codexQuestionProgress = regexp.MustCompile(`^Question (\d+)/(\d+)\b`)
+
+// codexQuestionTabs returns the questions of a multi-question overlay as
+// tabs, "Q1" to "Qn" since Codex does not name them, and the index of the
+// visible one; nil and -1 for a single question.
+func codexQuestionTabs(bottom []string) ([]string, int) {
+	for _, line := range bottom {
+		m := codexQuestionProgress.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		current, _ := strconv.Atoi(m[1])
+		total, _ := strconv.Atoi(m[2])
+		if total < 2 || current < 1 || current > total {
+			continue
+		}
+		tabs := make([]string, total)
+		for i := range tabs {
+			tabs[i] = fmt.Sprintf("Q%d", i+1)
+		}
+		return tabs, current - 1
+	}
+	return nil, -1
+}
+
+// codexDeviceCode matches the one-time code of device-code sign-in.
+var codexDeviceCode = regexp.MustCompile(`\b[A-Z0-9]{4,}-[A-Z0-9]{4,}\b`)
+
+// parseAuth detects sign-in screens: the sign-in method picker and
+// device-code sign-in. Signing in opens a browser or needs a key, so
+// nothing is recommended; y in the supervisor copies the device code.
+func (p *CodexParser) parseAuth(content string) *Result {
+	lines := strings.Split(content, "\n")
+	if strings.Contains(content, "one-time code") {
+		var code, link string
+		for _, line := range linesFrom(content, "one-time code")[1:] {
+			if code = codexDeviceCode.FindString(line); code != "" {
+				break
+			}
+		}
+		for _, line := range lines {
+			if i := strings.Index(line, "https://"); i >= 0 && link == "" {
+				link = strings.Fields(line[i:])[0]
+			}
+		}
+		waitingFor := "sign in with ChatGPT: enter code " + code
+		if link != "" {
+			waitingFor += " at " + link
+		}
+		return &Result{
+			Agent:      "codex",
+			Blocked:    true,
+			Reason:     "auth required",
+			WaitingFor: waitingFor,
+			Dialog: &model.Dialog{
+				Kind:      model.DialogAuth,
+				Question:  "Enter this one-time code",
+				ActiveTab: -1,
+				Code:      code,
+			},
+			Actions: []model.Action{
+				{Keys: "Escape", Label: "cancel sign-in", Risk: "low", Raw: true},
+			},
+			Recommended: -1,
+			Reasoning:   "deterministic parser: Codex device-code sign-in detected (one-time code)",
+		}
+	}
+
+	if !strings.Contains(content, "Sign in with ChatGPT") {
+		return nil
+	}
+	options, _ := extractDialogOptions(linesFrom(content, "Sign in with ChatGPT"))
+	if len(options) < 2 {
+		return nil
+	}
+	row := dialogRow(options)
+	return &Result{
+		Agent:      "codex",
+		Blocked:    true,
+		Reason:     "auth required",
+		WaitingFor: "sign in to Codex",
+		Dialog: &model.Dialog{
+			Kind:      model.DialogAuth,
+			Question:  "Sign in",
+			Options:   options,
+			ActiveTab: -1,
+		},
+		// Either choice opens the next sign-in step.
+		Actions:     row.actions(func(string) (string, bool) { return "low", true }),
+		Recommended: -1,
+		Reasoning:   "deterministic parser: Codex sign-in picker detected; " + row.describe(),
+	}
+}
+
+// parseUpdatePrompt detects the prompt to update Codex shown at startup.
+// Updating runs the package manager, so skipping is recommended.
+func (p *CodexParser) parseUpdatePrompt(content string) *Result {
+	marker := "Update available!"
+	if !strings.Contains(content, marker) {
+		marker = "A new version is available"
+		if !strings.Contains(content, marker) {
+			return nil
+		}
+	}
+	options, _ := extractDialogOptions(linesFrom(content, marker))
+	if len(options) == 0 {
+		options = fixedOptions(0, "Update now", "Skip", "Skip until next version")
+	}
+	row := dialogRow(options)
+	actions := row.actions(func(label string) (string, bool) {
+		if strings.HasPrefix(label, "Update") {
+			return "medium", false
+		}
+		return "low", false
+	})
+	recommended := -1
+	for i, label := range row.labels {
+		if strings.HasPrefix(label, "Skip") {
+			recommended = i
+			break
+		}
+	}
+	return &Result{
+		Agent:      "codex",
+		Blocked:    true,
+		Reason:     "update prompt",
+		WaitingFor: markerLine(content, marker),
+		Dialog: &model.Dialog{
+			Kind:      model.DialogQuestion,
+			Question:  markerLine(content, marker),
+			Options:   options,
+			ActiveTab: -1,
+		},
+		Actions:     actions,
+		Recommended: recommended,
+		Reasoning:   "deterministic parser: Codex update prompt detected; " + row.describe(),
+	}
+}
+
 // parseUserInputRequest detects user input request dialogs.
 func (p *CodexParser) parseUserInputRequest(content string) *Result {
 	if !strings.Contains(content, "Yes, provide the requested info") {
@@ -360,6 +659,11 @@ func (p *CodexParser) parseUserInputRequest(content string) *Result {
 		Blocked:    true,
 		Reason:     "requesting user input",
 		WaitingFor: waitingFor,
+		Dialog: &model.Dialog{
+			Kind:      model.DialogQuestion,
+			Options:   fixedOptions(0, "Yes, provide the requested info", "No, continue without it", "Cancel this request"),
+			ActiveTab: -1,
+		},
 		Actions: []model.Action{
 			{Keys: "Enter", Label: "yes, provide the requested info", Risk: "low", Raw: true},
 			{Keys: "Down Enter", Label: "no, continue without it", Risk: "low", Raw: true},
@@ -0,0 +1,226 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// CustomParser is a config-driven parser for an agent without a builtin
+// parser. Its rules are user-supplied regular expressions, so it follows the
+// same exact-match discipline as the builtin parsers: the user states the
+// literal strings their agent renders, and nothing is inferred.
+//
+// Evaluation order:
+//  1. Identify: any process pattern matches a process tree entry
+//     (ConfidenceProcess), or any content pattern matches the capture
+//     (ConfidenceMarker). Otherwise Parse returns nil.
+//  2. Blocked rules, in order, against the bottom lines. First match wins.
+//  3. Active patterns against the bottom lines.
+//  4. Otherwise the agent is reported as not blocked with "no rule matched".
+type CustomParser struct {
+	name        string
+	process     []*regexp.Regexp
+	content     []*regexp.Regexp
+	blocked     []customRule
+	active      []*regexp.Regexp
+	bottomLines int
+}
+
+// customRule is a compiled config.CustomParserRule.
+type customRule struct {
+	re          *regexp.Regexp
+	reason      string
+	actions     []model.Action
+	recommended int
+}
+
+// builtinParserNames are reserved so a custom parser cannot impersonate a
+// builtin agent (the supervisor keys behaviour off these names).
+var builtinParserNames = []string{"opencode", "claude_code", "codex", AgentGenericPrompt, "unknown", "error", "not_an_agent"}
+
+// NewCustomParser compiles a config definition. It returns an error that
+// names the parser and the offending field when a definition is invalid.
+func NewCustomParser(def config.CustomParser) (*CustomParser, error) {
+	name := strings.TrimSpace(def.Name)
+	if name == "" {
+		return nil, fmt.Errorf("custom parser: name is required")
+	}
+	for _, reserved := range builtinParserNames {
+		if name == reserved {
+			return nil, fmt.Errorf("custom parser %q: name is reserved for a builtin parser", name)
+		}
+	}
+	if len(def.Identify.Process) == 0 && len(def.Identify.Content) == 0 {
+		return nil, fmt.Errorf("custom parser %q: identify needs at least one process or content pattern", name)
+	}
+
+	p := &CustomParser{name: name, bottomLines: def.BottomLines}
+	if p.bottomLines <= 0 {
+		p.bottomLines = bottomLines
+	}
+
+	var err error
+	if p.process, err = compileAll(name, "identify.process", def.Identify.Process); err != nil {
+		return nil, err
+	}
+	if p.content, err = compileAll(name, "identify.content", def.Identify.Content); err != nil {
+		return nil, err
+	}
+	if p.active, err = compileAll(name, "active", def.Active); err != nil {
+		return nil, err
+	}
+
+	for i, rule := range def.Blocked {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("custom parser %q: blocked[%d].pattern: %w", name, i, err)
+		}
+		if rule.Reason == "" {
+			return nil, fmt.Errorf("custom parser %q: blocked[%d].reason is required", name, i)
+		}
+		for j, a := range rule.Actions {
+			if a.Keys == "" {
+				return nil, fmt.Errorf("custom parser %q: blocked[%d].actions[%d].keys is required", name, i, j)
+			}
+			switch a.Risk {
+			case "low", "medium", "high":
+			default:
+				return nil, fmt.Errorf("custom parser %q: blocked[%d].actions[%d].risk must be low, medium, or high (got %q)", name, i, j, a.Risk)
+			}
+		}
+		if rule.Recommended < 0 || (len(rule.Actions) > 0 && rule.Recommended >= len(rule.Actions)) {
+			return nil, fmt.Errorf("custom parser %q: blocked[%d].recommended %d is out of range", name, i, rule.Recommended)
+		}
+		p.blocked = append(p.blocked, customRule{
+			re:          re,
+			reason:      rule.Reason,
+			actions:     rule.Actions,
+			recommended: rule.Recommended,
+		})
+	}
+	return p, nil
+}
+
+// NewCustomParsers compiles all config definitions, rejecting duplicate names.
+func NewCustomParsers(defs []config.CustomParser) ([]AgentParser, error) {
+	parsers := make([]AgentParser, 0, len(defs))
+	seen := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		p, err := NewCustomParser(def)
+		if err != nil {
+			return nil, err
+		}
+		if seen[p.name] {
+			return nil, fmt.Errorf("custom parser %q: defined more than once", p.name)
+		}
+		seen[p.name] = true
+		parsers = append(parsers, p)
+	}
+	return parsers, nil
+}
+
+func compileAll(name, field string, patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for i, pat := range patterns {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return nil, fmt.Errorf("custom parser %q: %s[%d]: %w", name, field, i, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func (p *CustomParser) Name() string { return p.name }
+
+func (p *CustomParser) Parse(content string, processTree []string) *Result {
+	confidence, evidence := p.identify(content, processTree)
+	if confidence == 0 {
+		return nil
+	}
+	r := p.parseState(content)
+	r.Confidence = confidence
+	r.Evidence = evidence
+	return r
+}
+
+// parseState applies the blocked and active rules to an identified pane.
+func (p *CustomParser) parseState(content string) *Result {
+	bottom := bottomNonEmpty(strings.Split(content, "\n"), p.bottomLines)
+
+	for _, rule := range p.blocked {
+		for _, line := range bottom {
+			trimmed := strings.TrimSpace(line)
+			match := rule.re.FindStringSubmatchIndex(trimmed)
+			if match == nil {
+				continue
+			}
+			actions := make([]model.Action, len(rule.actions))
+			for i, a := range rule.actions {
+				a.Label = expandMatch(rule.re, a.Label, trimmed, match)
+				actions[i] = a
+			}
+			return &Result{
+				Agent:       p.name,
+				Blocked:     true,
+				Reason:      expandMatch(rule.re, rule.reason, trimmed, match),
+				WaitingFor:  trimmed,
+				Actions:     actions,
+				Recommended: rule.recommended,
+				Reasoning:   fmt.Sprintf("custom parser %q: blocked rule /%s/ matched", p.name, rule.re.String()),
+			}
+		}
+	}
+
+	for _, re := range p.active {
+		for _, line := range bottom {
+			if re.MatchString(line) {
+				return &Result{
+					Agent:     p.name,
+					Blocked:   false,
+					Reason:    "actively working",
+					Reasoning: fmt.Sprintf("custom parser %q: active pattern /%s/ matched", p.name, re.String()),
+				}
+			}
+		}
+	}
+
+	return &Result{
+		Agent:     p.name,
+		Blocked:   false,
+		Reason:    "no rule matched",
+		Reasoning: fmt.Sprintf("custom parser %q: identified, but no blocked or active rule matched", p.name),
+	}
+}
+
+// identify returns ConfidenceProcess for a process match, ConfidenceMarker
+// for a content match, or 0 if the pane does not belong to this agent,
+// along with a description of the matching pattern.
+func (p *CustomParser) identify(content string, processTree []string) (float64, string) {
+	for _, re := range p.process {
+		for _, proc := range processTree {
+			if re.MatchString(proc) {
+				return ConfidenceProcess, fmt.Sprintf("process %q matches /%s/", proc, re)
+			}
+		}
+	}
+	for _, re := range p.content {
+		if re.MatchString(content) {
+			return ConfidenceMarker, fmt.Sprintf("content matches /%s/", re)
+		}
+	}
+	return 0, ""
+}
+
+// expandMatch expands $1 / ${name} references in template using the
+// submatches of re in src. Templates without "$" are returned unchanged.
+func expandMatch(re *regexp.Regexp, template, src string, match []int) string {
+	if !strings.Contains(template, "$") {
+		return template
+	}
+	return string(re.ExpandString(nil, template, src, match))
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FixtureExt is the file extension of recorded pane fixtures.
+const FixtureExt = ".json"
+
+// Fixture is a recorded pane capture plus the verdict the parsers are
+// expected to produce for it. Fixtures are written by `pane-patrol record`
+// and replayed by the parser regression tests, so a real-world parser bug
+// becomes a permanent test by recording the pane and correcting Expect.
+type Fixture struct {
+	// Name is the file name without extension. Not stored in the file.
+	Name string `json:"-"`
+
+	Target      string    `json:"target"`
+	Command     string    `json:"command,omitempty"`
+	ProcessTree []string  `json:"process_tree"`
+	RecordedAt  time.Time `json:"recorded_at,omitzero"`
+	// Note is free-form context for reviewers (what the pane showed, which
+	// bug it reproduces).
+	Note string `json:"note,omitempty"`
+
+	Expect FixtureExpect `json:"expect"`
+
+	// Content is the raw pane capture, exactly as passed to the parsers.
+	Content string `json:"content"`
+}
+
+// FixtureExpect is the subset of a parser Result asserted by a fixture.
+// Agent "unknown" means no parser is expected to recognize the pane.
+type FixtureExpect struct {
+	Agent   string `json:"agent"`
+	Blocked bool   `json:"blocked"`
+	Reason  string `json:"reason"`
+	// RecommendedKeys is the Keys of the recommended action, "" if none.
+	RecommendedKeys string `json:"recommended_keys,omitempty"`
+	Model           string `json:"model,omitempty"`
+}
+
+// ExpectFromResult builds the expectation matching a parser result. A nil
+// result (no parser matched) expects agent "unknown".
+func ExpectFromResult(r *Result) FixtureExpect {
+	if r == nil {
+		return FixtureExpect{Agent: "unknown"}
+	}
+	e := FixtureExpect{
+		Agent:   r.Agent,
+		Blocked: r.Blocked,
+		Reason:  r.Reason,
+		Model:   r.Model,
+	}
+	if r.Recommended >= 0 && r.Recommended < len(r.Actions) {
+		e.RecommendedKeys = r.Actions[r.Recommended].Keys
+	}
+	return e
+}
+
+// Replay runs the registry over the fixture and returns an error describing
+// every field that differs from the expectation, or nil if all match.
+func (f *Fixture) Replay(r *Registry) error {
+	got := ExpectFromResult(r.Parse(f.Content, f.ProcessTree))
+	var diffs []string
+	if got.Agent != f.Expect.Agent {
+		diffs = append(diffs, fmt.Sprintf("agent: got %q, want %q", got.Agent, f.Expect.Agent))
+	}
+	if got.Blocked != f.Expect.Blocked {
+		diffs = append(diffs, fmt.Sprintf("blocked: got %v, want %v", got.Blocked, f.Expect.Blocked))
+	}
+	if got.Reason != f.Expect.Reason {
+		diffs = append(diffs, fmt.Sprintf("reason: got %q, want %q", got.Reason, f.Expect.Reason))
+	}
+	if got.RecommendedKeys != f.Expect.RecommendedKeys {
+		diffs = append(diffs, fmt.Sprintf("recommended_keys: got %q, want %q", got.RecommendedKeys, f.Expect.RecommendedKeys))
+	}
+	if got.Model != f.Expect.Model {
+		diffs = append(diffs, fmt.Sprintf("model: got %q, want %q", got.Model, f.Expect.Model))
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("fixture %s: %s", f.Name, strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+// LoadFixture reads a single fixture file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	f.Name = strings.TrimSuffix(filepath.Base(path), FixtureExt)
+	return &f, nil
+}
+
+// LoadFixtures reads every fixture file in dir, sorted by name.
+func LoadFixtures(dir string) ([]*Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+FixtureExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	fixtures := make([]*Fixture, 0, len(paths))
+	for _, path := range paths {
+		f, err := LoadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// WriteFixture writes f to dir as <f.Name>.json and returns the path.
+// It refuses to overwrite an existing fixture.
+func WriteFixture(dir string, f *Fixture) (string, error) {
+	if f.Name == "" {
+		return "", fmt.Errorf("fixture name is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, f.Name+FixtureExt)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
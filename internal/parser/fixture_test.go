@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// fixturesDir holds recorded pane captures (see `pane-patrol record`).
+const fixturesDir = "testdata/fixtures"
+
+// TestFixtures replays every recorded pane capture through the default
+// registry and asserts the expected verdict.
+func TestFixtures(t *testing.T) {
+	fixtures, err := LoadFixtures(fixturesDir)
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("no fixtures found in %s", fixturesDir)
+	}
+	r := NewRegistry()
+	for _, f := range fixtures {
+		t.Run(f.Name, func(t *testing.T) {
+			if err := f.Replay(r); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestWriteFixture_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	content := "\n  Do you want to continue? [Y/n] "
+	f := &Fixture{
+		Name:        "apt",
+		Target:      "ops:0.0",
+		ProcessTree: []string{"bash", "apt"},
+		Content:     content,
+		Expect:      ExpectFromResult(NewRegistry().Parse(content, []string{"bash", "apt"})),
+	}
+	path, err := WriteFixture(dir, f)
+	if err != nil {
+		t.Fatalf("WriteFixture: %v", err)
+	}
+	if path != filepath.Join(dir, "apt.json") {
+		t.Errorf("path: got %q", path)
+	}
+	if _, err := WriteFixture(dir, f); err == nil {
+		t.Error("expected error when overwriting an existing fixture")
+	}
+
+	loaded, err := LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture: %v", err)
+	}
+	if loaded.Name != "apt" || loaded.Content != content {
+		t.Errorf("round trip mismatch: %+v", loaded)
+	}
+	if err := loaded.Replay(NewRegistry()); err != nil {
+		t.Errorf("Replay: %v", err)
+	}
+}
+
+func TestFixture_ReplayReportsDiffs(t *testing.T) {
+	f := &Fixture{
+		Name:        "wrong",
+		ProcessTree: []string{"claude"},
+		Content:     "\n ❯ \n ? for shortcuts\n",
+		Expect:      FixtureExpect{Agent: "codex", Blocked: false, Reason: "actively working"},
+	}
+	if err := f.Replay(NewRegistry()); err == nil {
+		t.Error("expected replay mismatch error")
+	}
+}
@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// AgentGenericPrompt is the Agent value for verdicts produced by
+// GenericPromptParser. The supervisor only shows these panes in the "all"
+// display filter and never auto-nudges them.
+const AgentGenericPrompt = "shell_prompt"
+
+// IsAgentBlock reports whether v is an agent waiting on its user: blocked,
+// and not a generic shell prompt (AgentGenericPrompt), a pane that is not
+// an agent, or a failed evaluation. Counts, notifications, and anything
+// that answers panes by itself only consider these.
+func IsAgentBlock(v model.Verdict) bool {
+	if !v.Blocked {
+		return false
+	}
+	switch v.Agent {
+	case AgentGenericPrompt, "not_an_agent", "error":
+		return false
+	}
+	return true
+}
+
+// GenericPromptParser recognizes common interactive prompts in arbitrary
+// (non-agent) panes: yes/no confirmations, password prompts, and "press
+// ENTER to continue" pauses.
+//
+// Unlike the agent parsers, these prompts come from many different programs
+// (apt, rm -i, ssh, npm init, installers), so the match is restricted to the
+// exact prompt shapes below on the LAST non-empty line — the line holding
+// the cursor. A prompt that has scrolled up is no longer waiting for input.
+//
+// Verdicts are low confidence (ConfidenceGeneric): the pane is reported as
+// blocked, but actions are suggestions for a human to pick. Any agent parser
+// that recognizes the pane outranks it.
+type GenericPromptParser struct{}
+
+func (p *GenericPromptParser) Name() string { return AgentGenericPrompt }
+
+// genericPromptRule maps a last-line pattern to a verdict.
+type genericPromptRule struct {
+	re          *regexp.Regexp
+	reason      string
+	actions     []model.Action
+	recommended int
+}
+
+// yesNoActions answer a yes/no prompt. Keys are sent raw ("y" as a literal
+// keystroke, then Enter) so no Escape is injected into cooked-mode reads.
+var yesNoActions = []model.Action{
+	{Keys: "n Enter", Label: "answer no", Risk: "low", Raw: true},
+	{Keys: "y Enter", Label: "answer yes", Risk: "high", Raw: true},
+	{Keys: "C-c", Label: "interrupt (Ctrl-C)", Risk: "medium", Raw: true},
+}
+
+var genericPromptRules = []genericPromptRule{
+	{
+		// "[y/N]", "[Y/n]", "(y/n)", "[yes/no]", "(yes/no)?"
+		re:      regexp.MustCompile(`(?i)[\[(](y|yes)/(n|no)[\])]\s*[?:]?\s*$`),
+		reason:  "yes/no confirmation prompt",
+		actions: yesNoActions,
+	},
+	{
+		// "Password:", "[sudo] password for tim:", "Enter passphrase for key '/x':"
+		re:     regexp.MustCompile(`(?i)(password|passphrase)( for [^:]+)?:\s*$`),
+		reason: "password prompt",
+		actions: []model.Action{
+			{Keys: "C-c", Label: "cancel (Ctrl-C) — enter secrets in the pane itself", Risk: "medium", Raw: true},
+		},
+	},
+	{
+		// "Press ENTER to continue", "Press Enter to continue...", "Press RETURN to continue"
+		re:     regexp.MustCompile(`(?i)press (enter|return)( key)? to continue\W*$`),
+		reason: "press ENTER to continue",
+		actions: []model.Action{
+			{Keys: "Enter", Label: "continue", Risk: "low", Raw: true},
+			{Keys: "C-c", Label: "interrupt (Ctrl-C)", Risk: "medium", Raw: true},
+		},
+	},
+	{
+		// "Press any key to continue"
+		re:     regexp.MustCompile(`(?i)press any key to continue\W*$`),
+		reason: "press any key to continue",
+		actions: []model.Action{
+			{Keys: "Enter", Label: "continue", Risk: "low", Raw: true},
+			{Keys: "C-c", Label: "interrupt (Ctrl-C)", Risk: "medium", Raw: true},
+		},
+	},
+}
+
+func (p *GenericPromptParser) Parse(content string, processTree []string) *Result {
+	lines := bottomNonEmpty(strings.Split(content, "\n"), 1)
+	if len(lines) == 0 {
+		return nil
+	}
+	last := strings.TrimSpace(lines[len(lines)-1])
+	for _, rule := range genericPromptRules {
+		if !rule.re.MatchString(last) {
+			continue
+		}
+		actions := make([]model.Action, len(rule.actions))
+		copy(actions, rule.actions)
+		return &Result{
+			Agent:       AgentGenericPrompt,
+			Blocked:     true,
+			Reason:      rule.reason,
+			WaitingFor:  last,
+			Actions:     actions,
+			Recommended: rule.recommended,
+			Reasoning:   "deterministic parser (low confidence): interactive prompt on the last line of a non-agent pane",
+			Confidence:  ConfidenceGeneric,
+			Evidence:    fmt.Sprintf("last line matches /%s/", rule.re),
+		}
+	}
+	return nil
+}
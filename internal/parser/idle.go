@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// IdleReason is the reason of a blocked result for an agent idle at its
+// prompt, whose only action is Enter.
+const IdleReason = "idle at prompt"
+
+// IdleAction is a compiled config.IdleAction: the action recommended for an
+// agent idle at its prompt, or none.
+type IdleAction struct {
+	action *model.Action // nil recommends nothing
+}
+
+// NewIdleActions checks the idle_actions config section. It returns an
+// error naming the offending agent when a definition is invalid.
+func NewIdleActions(defs map[string]config.IdleAction) (map[string]IdleAction, error) {
+	agents := make([]string, 0, len(defs))
+	for agent := range defs {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+
+	actions := make(map[string]IdleAction, len(defs))
+	for _, agent := range agents {
+		def := defs[agent]
+		send := strings.TrimSpace(def.Send)
+		switch {
+		case send != "" && def.None:
+			return nil, fmt.Errorf("idle_actions.%s: set send or none, not both", agent)
+		case send == "" && !def.None:
+			return nil, fmt.Errorf("idle_actions.%s: needs send or none", agent)
+		}
+		risk := def.Risk
+		switch risk {
+		case "":
+			risk = "low"
+		case "low", "medium", "high":
+		default:
+			return nil, fmt.Errorf("idle_actions.%s: risk must be low, medium, or high (got %q)", agent, def.Risk)
+		}
+		if def.None {
+			actions[agent] = IdleAction{}
+			continue
+		}
+		actions[agent] = IdleAction{action: &model.Action{Keys: send, Label: "send " + send, Risk: risk}}
+	}
+	return actions, nil
+}
+
+// WithIdleActions makes Parse apply actions (see NewIdleActions) to results
+// of agents idle at their prompt, and returns r.
+func (r *Registry) WithIdleActions(actions map[string]IdleAction) *Registry {
+	r.idleActions = actions
+	return r
+}
+
+// applyIdleAction puts the configured idle action of the result's agent in
+// front of the parser's actions and recommends it, or recommends nothing
+// (Recommended -1) for none. The parser's actions stay available.
+func applyIdleAction(r *Result, actions map[string]IdleAction) {
+	if r == nil || !r.Blocked || r.Reason != IdleReason {
+		return
+	}
+	idle, ok := actions[r.Agent]
+	if !ok {
+		return
+	}
+	if idle.action == nil {
+		r.Recommended = -1
+		r.Reasoning = strings.TrimSpace(r.Reasoning + "\nnothing recommended by idle_actions." + r.Agent)
+		return
+	}
+	r.Actions = append([]model.Action{*idle.action}, r.Actions...)
+	r.Recommended = 0
+	r.Reasoning = strings.TrimSpace(r.Reasoning + fmt.Sprintf("\n%q recommended by idle_actions.%s", idle.action.Keys, r.Agent))
+}
+
+// completedRe matches the line an agent prints when it finishes a turn:
+//
+//   - Claude Code: "✻ Worked for 2m 15s", any spinner indicator and one of
+//     the 8 completed verbs (source: NLT array, see ClaudeCodeParser).
+//   - Codex: "─ Worked for 1m 02s ─────" (source:
+//     codex-rs/tui/src/history_cell.rs, FinalMessageSeparator, with the
+//     duration from fmt_elapsed_compact in status_indicator_widget.rs).
+var completedRe = regexp.MustCompile(`^(?:[·✢✳✶✻✽*] (?:Baked|Brewed|Churned|Cogitated|Cooked|Crunched|Sautéed|Worked)|─ Worked) for (?:\d+h )?(?:\d+m )?\d+s\b`)
+
+// splashMarkers are shown on an agent's start screen until its first task
+// scrolls them away:
+//
+//   - "Welcome to Claude Code": Claude Code's welcome box (binary analysis,
+//     see ClaudeCodeParser).
+//   - "OpenAI Codex": the Codex session header, ">_ OpenAI Codex (v0.104.0)"
+//     (source: codex-rs/tui/src/history_cell.rs, SessionHeaderHistoryCell).
+//   - "Welcome to Codex": the Codex onboarding welcome (source:
+//     codex-rs/tui/src/onboarding/welcome.rs).
+var splashMarkers = []string{"Welcome to Claude Code", "OpenAI Codex", "Welcome to Codex"}
+
+// annotateIdle sets the Idle state of a result idle at its prompt from the
+// screen above the prompt: it finished a task (a completion line) or it
+// shows its start screen and nothing else (fresh). It stays empty
+// otherwise, e.g. when the output scrolled away.
+func annotateIdle(r *Result, content string) {
+	if r == nil || !r.Blocked || r.Reason != IdleReason {
+		return
+	}
+	lines := strings.Split(content, "\n")
+	lines = lines[max(len(lines)-activityWindow, 0):]
+	for _, line := range lines {
+		if completedRe.MatchString(strings.TrimSpace(strings.Trim(line, " │"))) {
+			r.Idle = model.IdleFinished
+			return
+		}
+	}
+	for _, marker := range splashMarkers {
+		if strings.Contains(content, marker) {
+			r.Idle = model.IdleFresh
+			return
+		}
+	}
+}
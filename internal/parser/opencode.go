@@ -14,6 +14,8 @@ import (
 // Source reference: packages/opencode/src/cli/cmd/tui/routes/session/permission.tsx
 // Permission dialog title: "△ Permission required"
 // Options: "Allow once", "Allow always", "Reject"
+// Always stage: "△ Always allow" + scope options "This project", "Global"
+// (opened by "Allow always" for some permissions)
 // Reject stage: "△ Reject permission" + "Tell OpenCode what to do differently"
 // Footer: "⇆ select  enter confirm"
 // Active: Knight Rider scanner (■/⬝ or ⬥◆⬩⬪·), Build/Plan indicators
@@ -32,9 +34,19 @@ type OpenCodeParser struct{}
 func (p *OpenCodeParser) Name() string { return "opencode" }
 
 func (p *OpenCodeParser) Parse(content string, processTree []string) *Result {
-	if !p.isOpenCode(content, processTree) {
+	confidence, evidence := p.identify(content, processTree)
+	if confidence == 0 {
 		return nil
 	}
+	r := p.parseState(content)
+	r.Model = p.extractModel(content)
+	r.Confidence = confidence
+	r.Evidence = evidence
+	return r
+}
+
+// parseState classifies the OpenCode pane state (idle, dialog, active).
+func (p *OpenCodeParser) parseState(content string) *Result {
 
 	// Check idle at bottom FIRST: if the bottom of the screen shows a clear
 	// idle prompt, any dialog text or active indicators above it are stale
@@ -43,7 +55,7 @@ func (p *OpenCodeParser) Parse(content string, processTree []string) *Result {
 		return &Result{
 			Agent:      "opencode",
 			Blocked:    true,
-			Reason:     "idle at prompt",
+			Reason:     IdleReason,
 			WaitingFor: "idle at prompt",
 			Actions: []model.Action{
 				{Keys: "Enter", Label: "send empty message / continue", Risk: "low", Raw: true},
@@ -53,7 +65,11 @@ func (p *OpenCodeParser) Parse(content string, processTree []string) *Result {
 		}
 	}
 
-	// Not idle — check for dialog states.
+	// Not idle — check for dialog states. The scope selector first: the
+	// permission dialog's text can stay above it.
+	if r := p.parseAlwaysAllowDialog(content); r != nil {
+		return r
+	}
 	if r := p.parsePermissionDialog(content); r != nil {
 		return r
 	}
@@ -78,7 +94,7 @@ func (p *OpenCodeParser) Parse(content string, processTree []string) *Result {
 	return &Result{
 		Agent:      "opencode",
 		Blocked:    true,
-		Reason:     "idle at prompt",
+		Reason:     IdleReason,
 		WaitingFor: "idle at prompt",
 		Actions: []model.Action{
 			{Keys: "Enter", Label: "send empty message / continue", Risk: "low", Raw: true},
@@ -88,6 +104,30 @@ func (p *OpenCodeParser) Parse(content string, processTree []string) *Result {
 	}
 }
 
+// extractModel returns the model ID from the most recent assistant message
+// footer, or "" if none is visible.
+//
+// Source: packages/opencode/src/cli/cmd/tui/routes/session/index.tsx
+// The assistant message footer renders "▣ {Mode} · {modelID} · {duration}",
+// e.g. "▣ Build · claude-sonnet-4-5 · 12s".
+func (p *OpenCodeParser) extractModel(content string) string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		trimmed := stripDialogPrefix(strings.TrimSpace(lines[i]))
+		if !strings.HasPrefix(trimmed, "▣ ") && !strings.HasPrefix(trimmed, "■ ") {
+			continue
+		}
+		parts := strings.Split(trimmed, " · ")
+		if len(parts) < 2 {
+			continue
+		}
+		if model := strings.TrimSpace(parts[1]); model != "" && !strings.Contains(model, " ") {
+			return model
+		}
+	}
+	return ""
+}
+
 // isIdleAtBottom checks if the bottom of the screen shows a clear idle
 // prompt. OpenCode's idle state has "> " prompt line.
 //
@@ -158,59 +198,181 @@ func (p *OpenCodeParser) isIdleAtBottom(content string) bool {
 	return hasPrompt
 }
 
-// isOpenCode checks if this pane is running OpenCode based on the process tree
-// and characteristic TUI elements.
-func (p *OpenCodeParser) isOpenCode(content string, processTree []string) bool {
+// identify checks if this pane is running OpenCode based on the process tree
+// and characteristic TUI elements. Returns the confidence level and a
+// description of the strongest evidence found, or 0 if the pane is not OpenCode.
+func (p *OpenCodeParser) identify(content string, processTree []string) (float64, string) {
 	for _, proc := range processTree {
 		lower := strings.ToLower(proc)
 		if strings.Contains(lower, "opencode") {
-			return true
+			return ConfidenceProcess, fmt.Sprintf("process %q", proc)
 		}
 	}
 	// Fallback: look for OpenCode-specific TUI markers in content.
 	// These are unique to OpenCode and won't appear in other agents.
 	if strings.Contains(content, "△ Permission required") {
-		return true
+		return ConfidenceMarker, `marker "△ Permission required"`
 	}
 	if strings.Contains(content, "△ Reject permission") {
-		return true
+		return ConfidenceMarker, `marker "△ Reject permission"`
+	}
+	if strings.Contains(content, "△ Always allow") {
+		return ConfidenceMarker, `marker "△ Always allow"`
 	}
 	// OpenCode footer pattern: "⇆ select  enter confirm"
 	if strings.Contains(content, "⇆ select") {
-		return true
+		return ConfidenceMarker, `marker "⇆ select"`
 	}
 	// Question dialog footer: "↑↓ select" + "esc dismiss"
 	if strings.Contains(content, "↑↓") && strings.Contains(content, "select") &&
 		strings.Contains(content, "esc dismiss") {
-		return true
+		return ConfidenceMarker, `marker "↑↓" + "select" + "esc dismiss"`
 	}
-	return false
+	return 0, ""
 }
 
-// parsePermissionDialog detects "△ Permission required" dialogs.
+// parsePermissionDialog detects "△ Permission required" dialogs. The
+// actions are the visible options, labeled verbatim, with the keys that
+// move the cursor from the highlighted option to each of them.
 func (p *OpenCodeParser) parsePermissionDialog(content string) *Result {
-	if !strings.Contains(content, "△ Permission required") {
+	const marker = "△ Permission required"
+	if !strings.Contains(content, marker) {
 		return nil
 	}
 
-	waitingFor := extractBlock(content, "△ Permission required")
+	row := parseOptionRow(content, marker, func(label string) bool {
+		return strings.HasPrefix(label, "Allow") || label == "Reject"
+	})
+	if row.labels == nil {
+		row = optionRow{labels: []string{"Allow once", "Allow always", "Reject"}}
+	}
+	actions := row.actions(func(label string) (string, bool) {
+		if label == "Reject" {
+			return "low", false
+		}
+		return "medium", label == "Allow always"
+	})
+	recommended := 0
+	for i, label := range row.labels {
+		if label == "Allow once" {
+			recommended = i
+			break
+		}
+	}
 
 	return &Result{
 		Agent:      "opencode",
 		Blocked:    true,
 		Reason:     "permission dialog waiting for approval",
-		WaitingFor: waitingFor,
-		Actions: []model.Action{
-			{Keys: "Enter", Label: "allow once (confirm selected option)", Risk: "medium", Raw: true},
-			{Keys: "Down Enter", Label: "allow always", Risk: "medium", Raw: true},
-			{Keys: "Down Down Enter", Label: "reject", Risk: "low", Raw: true},
-			{Keys: "Escape", Label: "dismiss dialog", Risk: "low", Raw: true},
+		WaitingFor: extractBlock(content, marker),
+		Dialog: &model.Dialog{
+			Kind:      model.DialogPermission,
+			Question:  "Permission required",
+			Options:   row.options(),
+			ActiveTab: -1,
 		},
-		Recommended: 0,
-		Reasoning:   "deterministic parser: OpenCode permission dialog detected (△ Permission required)",
+		Actions:     append(actions, model.Action{Keys: "Escape", Label: "dismiss dialog", Risk: "low", Raw: true}),
+		Recommended: recommended,
+		Reasoning:   "deterministic parser: OpenCode permission dialog detected (△ Permission required); " + row.describe(),
+	}
+}
+
+// parseAlwaysAllowDialog detects the "△ Always allow" follow-up, where
+// "Allow always" asks for the scope of the rule. Allowing everywhere is
+// riskier than allowing in this project.
+func (p *OpenCodeParser) parseAlwaysAllowDialog(content string) *Result {
+	const marker = "△ Always allow"
+	if !strings.Contains(content, marker) {
+		return nil
+	}
+
+	global := func(label string) bool { return strings.Contains(strings.ToLower(label), "global") }
+	row := parseOptionRow(content, marker, func(label string) bool {
+		return global(label) || strings.Contains(strings.ToLower(label), "project")
+	})
+	if row.labels == nil {
+		row = optionRow{labels: []string{"This project", "Global"}}
+	}
+	actions := row.actions(func(label string) (string, bool) {
+		if global(label) {
+			return "high", false
+		}
+		return "medium", false
+	})
+	recommended := 0
+	for i, label := range row.labels {
+		if !global(label) {
+			recommended = i
+			break
+		}
+	}
+
+	return &Result{
+		Agent:      "opencode",
+		Blocked:    true,
+		Reason:     "allow always — waiting for a scope",
+		WaitingFor: extractBlock(content, marker),
+		Dialog: &model.Dialog{
+			Kind:      model.DialogPermission,
+			Question:  "Always allow",
+			Options:   row.options(),
+			ActiveTab: -1,
+		},
+		Actions:     append(actions, model.Action{Keys: "Escape", Label: "cancel, return to permission dialog", Risk: "low", Raw: true}),
+		Recommended: recommended,
+		Reasoning:   "deterministic parser: OpenCode allow-always scope selector detected (△ Always allow); " + row.describe(),
 	}
 }
 
+// optionGap separates the options of an OpenCode dialog's option row:
+// "Allow once  Allow always  Reject".
+var optionGap = regexp.MustCompile(`\s{2,}`)
+
+// optionSelectors mark the highlighted option when the capture shows it as
+// text; usually it is only highlighted by color.
+var optionSelectors = []string{"❯ ", "› ", "▸ ", "> "}
+
+// parseOptionRow finds the option row below marker: the first line that
+// splits into two or more options of which one satisfies isOption. Stops at
+// the "⇆ select" footer. Returns a zero optionRow if there is none.
+func parseOptionRow(content, marker string, isOption func(label string) bool) optionRow {
+	lines := strings.Split(content, "\n")
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(lines[i], marker) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return optionRow{}
+	}
+	for _, line := range lines[start+1:] {
+		if strings.Contains(line, "⇆ select") {
+			break
+		}
+		labels := optionGap.Split(strings.TrimSpace(line), -1)
+		if len(labels) < 2 {
+			continue
+		}
+		row := optionRow{labels: labels}
+		matched := false
+		for i, label := range labels {
+			for _, sel := range optionSelectors {
+				if rest, ok := strings.CutPrefix(label, sel); ok {
+					labels[i], label = rest, rest
+					row.selected, row.visible = i, true
+				}
+			}
+			matched = matched || isOption(label)
+		}
+		if matched {
+			return row
+		}
+	}
+	return optionRow{}
+}
+
 // parseRejectDialog detects the "△ Reject permission" follow-up.
 func (p *OpenCodeParser) parseRejectDialog(content string) *Result {
 	if !strings.Contains(content, "△ Reject permission") {
@@ -222,6 +384,11 @@ func (p *OpenCodeParser) parseRejectDialog(content string) *Result {
 		Blocked:    true,
 		Reason:     "reject dialog — waiting for alternative instructions",
 		WaitingFor: "△ Reject permission\nTell OpenCode what to do differently",
+		Dialog: &model.Dialog{
+			Kind:      model.DialogPermission,
+			Question:  "Tell OpenCode what to do differently",
+			ActiveTab: -1,
+		},
 		Actions: []model.Action{
 			{Keys: "Escape", Label: "cancel rejection, return to permission dialog", Risk: "low", Raw: true},
 		},
@@ -321,10 +488,6 @@ func (p *OpenCodeParser) parseQuestionDialog(content string) *Result {
 
 	// Extract question text and options for WaitingFor.
 	waitingFor := extractQuestionSummary(lines)
-	// Prefix with tab header info if present.
-	if len(tabHeaders) > 0 {
-		waitingFor = "[tabs] " + strings.Join(tabHeaders, " | ") + "\n" + waitingFor
-	}
 
 	// Build actions. Number keys directly select options in OpenCode's question dialog.
 	// Count visible numbered options in the bottom portion to avoid stale matches.
@@ -333,13 +496,17 @@ func (p *OpenCodeParser) parseQuestionDialog(content string) *Result {
 	// Detect multi-select: options have [✓]/[ ] checkbox prefixes.
 	// In multi-select, number keys toggle checkboxes (not select-and-submit),
 	// and Enter submits the selection.
-	isMultiSelect := false
-	for _, line := range lines {
-		stripped := stripDialogPrefix(strings.TrimSpace(line))
-		if isNumberedOption(stripped) && (strings.Contains(stripped, "[ ]") || strings.Contains(stripped, "[✓]")) {
-			isMultiSelect = true
-			break
-		}
+	options, isMultiSelect := extractDialogOptions(lines)
+
+	// The active tab is highlighted by background color only, which a
+	// plain-text capture does not preserve.
+	dialog := &model.Dialog{
+		Kind:        model.DialogQuestion,
+		Question:    extractQuestionText(lines),
+		Options:     options,
+		MultiSelect: isMultiSelect,
+		Tabs:        tabHeaders,
+		ActiveTab:   -1,
 	}
 
 	optionLabels := extractOptionLabels(lines)
@@ -403,6 +570,7 @@ func (p *OpenCodeParser) parseQuestionDialog(content string) *Result {
 		Blocked:     true,
 		Reason:      "question dialog waiting for answer",
 		WaitingFor:  waitingFor,
+		Dialog:      dialog,
 		Actions:     actions,
 		Recommended: recommended,
 		Reasoning:   "deterministic parser: OpenCode question dialog detected (↑↓ select footer)",
@@ -432,12 +600,16 @@ func (p *OpenCodeParser) parseConfirmTab(lines []string, content string) *Result
 
 	tabHeaders := parseTabHeaders(lines)
 
-	// Build WaitingFor: include tab headers and review content.
-	var waitParts []string
-	if len(tabHeaders) > 0 {
-		waitParts = append(waitParts, "[tabs] "+strings.Join(tabHeaders, " | "))
+	// The Confirm tab is always the last tab.
+	dialog := &model.Dialog{
+		Kind:      model.DialogConfirm,
+		Question:  "Review",
+		Tabs:      tabHeaders,
+		ActiveTab: len(tabHeaders) - 1,
 	}
-	waitParts = append(waitParts, "[confirm tab]")
+
+	// Build WaitingFor from the review content.
+	waitParts := []string{"Review"}
 
 	// Collect review lines (after "Review", before footer).
 	// Apply trimRightPanel before stripDialogPrefix to catch right-panel
@@ -474,6 +646,7 @@ func (p *OpenCodeParser) parseConfirmTab(lines []string, content string) *Result
 		Blocked:     true,
 		Reason:      "question dialog confirm tab",
 		WaitingFor:  waitingFor,
+		Dialog:      dialog,
 		Actions:     actions,
 		Recommended: 0, // recommend Enter (submit)
 		Reasoning:   "deterministic parser: OpenCode question Confirm tab detected (⇆ tab footer + Review)",
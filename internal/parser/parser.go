@@ -5,11 +5,13 @@
 // calling an LLM. This is protocol parsing — we know exactly what strings
 // these agents render because we read their source code.
 //
-// The Registry tries each registered parser in order. If none matches, the
-// pane is reported as unrecognized (no fallback).
+// The Registry runs every registered parser and keeps the match with the
+// highest confidence level (ties go to the earlier parser). If none matches,
+// the pane is reported as unrecognized (no fallback).
 package parser
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/timvw/pane-patrol/internal/model"
@@ -26,8 +28,25 @@ type Result struct {
 	Recommended int
 	Reasoning   string
 	Subagents   []model.SubagentInfo
+	Dialog      *model.Dialog // structured dialog, nil when none is visible
+	Model       string        // LLM model shown in the agent TUI, empty if not visible
+	Confidence  float64       // one of the Confidence* levels
+	Evidence    string        // what identified the agent, e.g. `process "claude"`
+	Activity    []string      // output lines above the dialog or prompt, set by Registry.Parse for blocked results
+	Idle        string        // one of the model.Idle* states for results idle at the prompt, set by Registry.Parse
 }
 
+// Confidence levels for Result.Confidence. These are not probabilities: each
+// level names the kind of evidence that identified the agent, so the Registry
+// can prefer the parser with the strongest evidence when several recognize
+// the same pane (e.g. Codex and Claude Code both render "? for shortcuts").
+const (
+	ConfidenceProcess = 1.0 // agent binary found in the pane's process tree
+	ConfidenceMarker  = 0.8 // TUI string unique to the agent (dialog title, banner)
+	ConfidenceShared  = 0.5 // TUI string other agents also render (footer hints, spinners)
+	ConfidenceGeneric = 0.2 // generic prompt shape, not specific to any agent
+)
+
 // AgentParser recognizes a specific agent's TUI output and produces a
 // deterministic verdict. Parse returns nil if the content does not belong
 // to this agent.
@@ -40,32 +59,91 @@ type AgentParser interface {
 	Parse(content string, processTree []string) *Result
 }
 
-// Registry holds an ordered list of parsers and tries each one.
+// Registry holds an ordered list of parsers and runs each one.
 type Registry struct {
-	parsers []AgentParser
+	parsers     []AgentParser
+	riskRules   []RiskRule
+	idleActions map[string]IdleAction
 }
 
 // NewRegistry creates a registry with the default set of parsers for
-// the three supported agents: OpenCode, Claude Code, and Codex.
-func NewRegistry() *Registry {
-	return &Registry{
-		parsers: []AgentParser{
-			&OpenCodeParser{},
-			&CodexParser{},
-			&ClaudeCodeParser{},
-		},
+// the three supported agents: OpenCode, Claude Code, and Codex, followed by
+// any custom parsers (see NewCustomParsers) and finally the generic
+// interactive-prompt parser for non-agent panes. Registration order breaks
+// confidence ties, so builtin parsers win over custom ones on equal evidence.
+func NewRegistry(custom ...AgentParser) *Registry {
+	parsers := []AgentParser{
+		&OpenCodeParser{},
+		&CodexParser{},
+		&ClaudeCodeParser{},
 	}
+	parsers = append(parsers, custom...)
+	parsers = append(parsers, &GenericPromptParser{})
+	return &Registry{parsers: parsers}
 }
 
-// Parse tries each registered parser in order. Returns the first match,
-// or nil if no parser recognizes the content.
+// WithRiskRules makes Parse apply rules (see NewRiskRules) to the selected
+// result's actions, and returns r.
+func (r *Registry) WithRiskRules(rules []RiskRule) *Registry {
+	r.riskRules = rules
+	return r
+}
+
+// Parse runs every registered parser and returns the match with the highest
+// confidence. On equal confidence the earlier-registered parser wins, so the
+// builtin agents take precedence over custom and generic parsers. The
+// selected result's shell command is checked (see annotateCommand), the
+// configured idle action is applied, then risk rules are applied, so user
+// rules have the last word. Blocked results get the output above their
+// dialog as Activity, and results idle at the prompt their Idle state.
+// Returns nil if no parser recognizes the content.
 func (r *Registry) Parse(content string, processTree []string) *Result {
-	for _, p := range r.parsers {
-		if result := p.Parse(content, processTree); result != nil {
-			return result
+	var best *Result
+	for _, result := range r.ParseAll(content, processTree) {
+		if best == nil || result.Confidence > best.Confidence {
+			best = result
 		}
 	}
-	return nil
+	annotateCommand(best)
+	applyIdleAction(best, r.idleActions)
+	applyRiskRules(best, r.riskRules)
+	annotateActivity(best, content)
+	annotateIdle(best, content)
+	return best
+}
+
+// ParseAll returns the result of every parser that recognizes the content,
+// in registration order. Useful for debugging conflicting matches.
+func (r *Registry) ParseAll(content string, processTree []string) []*Result {
+	var results []*Result
+	for _, entry := range r.Trace(content, processTree) {
+		if entry.Result != nil {
+			results = append(results, entry.Result)
+		}
+	}
+	return results
+}
+
+// TraceEntry is one parser's outcome for a pane, as returned by Trace.
+type TraceEntry struct {
+	Parser string
+	Result *Result // nil if the parser did not recognize the pane
+}
+
+// Trace runs every parser and reports each outcome in registration order,
+// including parsers that did not recognize the pane.
+func (r *Registry) Trace(content string, processTree []string) []TraceEntry {
+	entries := make([]TraceEntry, 0, len(r.parsers))
+	for _, p := range r.parsers {
+		entries = append(entries, TraceEntry{Parser: p.Name(), Result: p.Parse(content, processTree)})
+	}
+	return entries
+}
+
+// ExaminedLines returns the bottom lines the builtin parsers use to decide
+// between idle, active, and dialog states.
+func ExaminedLines(content string) []string {
+	return bottomNonEmpty(strings.Split(content, "\n"), bottomLines)
 }
 
 // bottomLines is the number of non-empty lines from the bottom of the
@@ -185,27 +263,28 @@ func trimRightPanel(s string) string {
 	return s
 }
 
-// extractQuestionSummary extracts question text and visible options from
-// a question dialog. Looks for the question text above the first numbered
-// option, then collects option labels with their description lines.
-func extractQuestionSummary(lines []string) string {
-	// Find the first numbered option line. Strip known border/cursor
-	// prefixes (┃ from OpenCode, › from Codex) before checking.
-	firstOptIdx := -1
+// firstNumberedOption returns the index of the first numbered option line,
+// or -1. Known border/cursor prefixes (┃ from OpenCode, › from Codex, ❯
+// from Claude Code) are stripped before checking.
+func firstNumberedOption(lines []string) int {
 	for i, line := range lines {
 		trimmed := trimRightPanel(strings.TrimSpace(line))
-		stripped := stripDialogPrefix(trimmed)
+		stripped, _ := splitCursorPrefix(stripDialogPrefix(trimmed))
 		if isNumberedOption(stripped) {
-			firstOptIdx = i
-			break
+			return i
 		}
 	}
+	return -1
+}
+
+// extractQuestionText returns the question text above the first numbered
+// option (up to 4 lines, stopping at a blank line), with border prefixes
+// stripped. Returns "" if there is no numbered option.
+func extractQuestionText(lines []string) string {
+	firstOptIdx := firstNumberedOption(lines)
 	if firstOptIdx < 0 {
-		return "question dialog"
+		return ""
 	}
-
-	// Collect question text from lines above the first option.
-	// Strip border prefixes so the output is clean.
 	var questionLines []string
 	for i := firstOptIdx - 1; i >= 0 && len(questionLines) < 4; i-- {
 		trimmed := trimRightPanel(strings.TrimSpace(lines[i]))
@@ -221,6 +300,156 @@ func extractQuestionSummary(lines []string) string {
 	for i, j := 0, len(questionLines)-1; i < j; i, j = i+1, j-1 {
 		questionLines[i], questionLines[j] = questionLines[j], questionLines[i]
 	}
+	return strings.Join(questionLines, "\n")
+}
+
+// extractDialogOptions returns the numbered options starting at the first
+// numbered option line, up to 9, each with up to 2 description lines.
+// The option under the cursor (› in Codex/OpenCode, ❯ in Claude Code) is
+// marked Selected; "[✓]"/"[ ]" checkbox prefixes set Checked and are
+// removed from the label. The second result reports whether any option
+// has a checkbox (multi-select).
+func extractDialogOptions(lines []string) ([]model.DialogOption, bool) {
+	firstOptIdx := firstNumberedOption(lines)
+	if firstOptIdx < 0 {
+		return nil, false
+	}
+	var options []model.DialogOption
+	multiSelect := false
+	for i := firstOptIdx; i < len(lines) && len(options) < 9; i++ {
+		rest, selected := splitCursorPrefix(trimRightPanel(strings.TrimSpace(lines[i])))
+		if !isNumberedOption(rest) {
+			if isFooterLine(rest) {
+				break
+			}
+			continue
+		}
+		opt := model.DialogOption{
+			Label:    trimRightPanel(strings.TrimSpace(rest[3:])),
+			Selected: selected,
+		}
+		if label, ok := strings.CutPrefix(opt.Label, "[✓]"); ok {
+			opt.Label, opt.Checked, multiSelect = strings.TrimSpace(label), true, true
+		} else if label, ok := strings.CutPrefix(opt.Label, "[ ]"); ok {
+			opt.Label, multiSelect = strings.TrimSpace(label), true
+		}
+		var desc []string
+		for j := i + 1; j < len(lines) && len(desc) < 2; j++ {
+			ds := stripDialogPrefix(trimRightPanel(strings.TrimSpace(lines[j])))
+			if ds == "" || isNumberedOption(ds) || isFooterLine(ds) || strings.HasPrefix(ds, "❯") {
+				break
+			}
+			desc = append(desc, ds)
+			i = j
+		}
+		opt.Description = strings.Join(desc, " ")
+		options = append(options, opt)
+	}
+	return options, multiSelect
+}
+
+// splitCursorPrefix strips dialog border and cursor prefixes from a trimmed
+// line and reports whether a selection cursor (› or ❯) was present.
+func splitCursorPrefix(trimmed string) (string, bool) {
+	s := strings.TrimLeft(strings.TrimPrefix(trimmed, "┃"), " ")
+	selected := false
+	for _, cursor := range []string{"›", "❯", "> "} {
+		if rest, ok := strings.CutPrefix(s, cursor); ok {
+			s, selected = strings.TrimLeft(rest, " "), true
+		}
+	}
+	return s, selected
+}
+
+// markerLine returns the first line containing marker, trimmed and with
+// dialog border/cursor prefixes stripped, or marker itself if not found.
+func markerLine(content, marker string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, marker) {
+			return stripDialogPrefix(trimRightPanel(strings.TrimSpace(line)))
+		}
+	}
+	return marker
+}
+
+// optionRow is the options of a dialog navigated with the arrow keys, such
+// as OpenCode's option row or Codex's onboarding screens.
+type optionRow struct {
+	labels   []string
+	selected int  // index of the highlighted option
+	visible  bool // whether the highlight was visible; if not, the first option is assumed
+}
+
+// actions returns an action per option, labeled verbatim, whose keys move
+// the cursor from the highlighted option (Down/Up) and confirm. risk gives
+// an option's risk and whether it may open a follow-up dialog.
+func (r optionRow) actions(risk func(label string) (string, bool)) []model.Action {
+	actions := make([]model.Action, 0, len(r.labels)+1)
+	for i, label := range r.labels {
+		keys := "Enter"
+		switch {
+		case i > r.selected:
+			keys = strings.Repeat("Down ", i-r.selected) + keys
+		case i < r.selected:
+			keys = strings.Repeat("Up ", r.selected-i) + keys
+		}
+		level, followUp := risk(label)
+		actions = append(actions, model.Action{Keys: keys, Label: label, Risk: level, Raw: true, FollowUp: followUp})
+	}
+	return actions
+}
+
+// options returns the row as dialog options.
+func (r optionRow) options() []model.DialogOption {
+	selected := 0
+	if r.visible {
+		selected = r.selected + 1
+	}
+	return fixedOptions(selected, r.labels...)
+}
+
+// describe explains the row's cursor for the reasoning.
+func (r optionRow) describe() string {
+	if r.visible {
+		return fmt.Sprintf("cursor on %q", r.labels[r.selected])
+	}
+	return "cursor not visible, assuming the first option"
+}
+
+// dialogRow returns options as an optionRow, with the cursor on the
+// selected option.
+func dialogRow(options []model.DialogOption) optionRow {
+	var row optionRow
+	for i, opt := range options {
+		row.labels = append(row.labels, opt.Label)
+		if opt.Selected {
+			row.selected, row.visible = i, true
+		}
+	}
+	return row
+}
+
+// fixedOptions builds dialog options from labels known from the agent's
+// source code, marking option selected (1-based) as under the cursor.
+// selected 0 marks none.
+func fixedOptions(selected int, labels ...string) []model.DialogOption {
+	options := make([]model.DialogOption, len(labels))
+	for i, label := range labels {
+		options[i] = model.DialogOption{Label: label, Selected: i+1 == selected}
+	}
+	return options
+}
+
+// extractQuestionSummary extracts question text and visible options from
+// a question dialog. Looks for the question text above the first numbered
+// option, then collects option labels with their description lines.
+func extractQuestionSummary(lines []string) string {
+	firstOptIdx := firstNumberedOption(lines)
+	if firstOptIdx < 0 {
+		return "question dialog"
+	}
+
+	question := extractQuestionText(lines)
 
 	// Collect option labels with their description lines.
 	// Each numbered option ("N. label") may be followed by indented description
@@ -259,7 +488,6 @@ func extractQuestionSummary(lines []string) string {
 		}
 	}
 
-	question := strings.Join(questionLines, "\n")
 	options := strings.Join(optionLines, "\n")
 	if question != "" && options != "" {
 		return question + "\n" + options
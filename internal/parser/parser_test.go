@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
 )
 
 // --- OpenCode Parser Tests ---
@@ -66,6 +70,87 @@ func TestOpenCode_RejectDialog(t *testing.T) {
 	}
 }
 
+func TestOpenCode_PermissionOptionsFromCursor(t *testing.T) {
+	content := `
+  △ Permission required
+
+  # Edit src/main.go
+
+  Allow once  › Allow always  Reject
+
+  ⇆ select  enter confirm
+`
+	result := (&OpenCodeParser{}).Parse(content, []string{"opencode"})
+	if result == nil || result.Dialog == nil {
+		t.Fatalf("expected permission dialog, got %+v", result)
+	}
+	want := []model.Action{
+		{Keys: "Up Enter", Label: "Allow once", Risk: "medium", Raw: true},
+		{Keys: "Enter", Label: "Allow always", Risk: "medium", Raw: true, FollowUp: true},
+		{Keys: "Down Enter", Label: "Reject", Risk: "low", Raw: true},
+		{Keys: "Escape", Label: "dismiss dialog", Risk: "low", Raw: true},
+	}
+	if len(result.Actions) != len(want) {
+		t.Fatalf("actions: got %+v", result.Actions)
+	}
+	for i := range want {
+		if result.Actions[i] != want[i] {
+			t.Errorf("action %d: got %+v, want %+v", i, result.Actions[i], want[i])
+		}
+	}
+	if result.Recommended != 0 || !result.Dialog.Options[1].Selected || result.Dialog.Options[0].Selected {
+		t.Errorf("recommended %d, options %+v", result.Recommended, result.Dialog.Options)
+	}
+
+	// Only the options shown are offered; without a visible cursor the
+	// first option is assumed.
+	content = strings.Replace(content, "Allow once  › Allow always  Reject", "Allow once  Reject", 1)
+	result = (&OpenCodeParser{}).Parse(content, []string{"opencode"})
+	if len(result.Actions) != 3 || result.Actions[1].Keys != "Down Enter" || result.Actions[1].Label != "Reject" {
+		t.Errorf("two options: got %+v", result.Actions)
+	}
+	if !strings.Contains(result.Reasoning, "assuming the first option") {
+		t.Errorf("reasoning: got %q", result.Reasoning)
+	}
+}
+
+func TestOpenCode_AlwaysAllowScopeDialog(t *testing.T) {
+	content := `
+  △ Permission required
+
+  # Bash command
+  $ git diff HEAD~3
+
+  △ Always allow
+
+  This will allow git diff * from now on
+
+  This project  Global
+
+  ⇆ select  enter confirm  esc cancel
+`
+	p := &OpenCodeParser{}
+	result := p.Parse(content, []string{"opencode"})
+	if result == nil || result.Dialog == nil {
+		t.Fatalf("expected scope dialog, got %+v", result)
+	}
+	if result.Reason != "allow always — waiting for a scope" {
+		t.Errorf("reason: got %q", result.Reason)
+	}
+	if len(result.Dialog.Options) != 2 || result.Dialog.Options[1].Label != "Global" {
+		t.Errorf("options: got %+v", result.Dialog.Options)
+	}
+	if len(result.Actions) != 3 || result.Actions[0].Keys != "Enter" || result.Actions[1].Risk != "high" {
+		t.Errorf("actions: got %+v", result.Actions)
+	}
+
+	// The permission dialog marks "allow always" as opening a follow-up.
+	permission := p.Parse(strings.SplitN(content, "  △ Always allow", 2)[0], []string{"opencode"})
+	if permission == nil || len(permission.Actions) < 2 || !permission.Actions[1].FollowUp || permission.Actions[0].FollowUp {
+		t.Errorf("expected only allow always to be a follow-up: %+v", permission)
+	}
+}
+
 func TestOpenCode_ActiveExecution_Spinner(t *testing.T) {
 	content := `
   ▣ Build · claude-sonnet-4-5 · 12s
@@ -232,6 +317,76 @@ func TestClaude_EditApproval(t *testing.T) {
 	}
 }
 
+func TestClaude_TrustFolderDialog(t *testing.T) {
+	content := `
+╭──────────────────────────────────────────────────────────────╮
+│                                                              │
+ Do you trust the files in this folder?
+
+ /home/user/src/api
+
+ Claude Code may read files in this folder. Reading untrusted files may
+ lead Claude Code to behave in unexpected ways.
+
+ ❯ 1. Yes, proceed
+   2. No, exit
+
+ Enter to confirm · Esc to exit
+`
+	result := NewRegistry().Parse(content, []string{"claude"})
+	if result == nil || result.Agent != "claude_code" || !result.Blocked {
+		t.Fatalf("expected blocked claude_code, got %+v", result)
+	}
+	if result.Reason != "trust folder dialog at startup" || result.WaitingFor != "Do you trust the files in this folder?\n/home/user/src/api" {
+		t.Errorf("reason %q, waiting for %q", result.Reason, result.WaitingFor)
+	}
+	if len(result.Actions) != 2 || result.Actions[0].Keys != "1" || result.Actions[0].Risk != "medium" ||
+		result.Actions[1].Label != "don't trust (No, exit)" || result.Actions[1].Risk != "low" {
+		t.Errorf("actions: got %+v", result.Actions)
+	}
+	if result.Dialog == nil || len(result.Dialog.Options) != 2 || !result.Dialog.Options[0].Selected {
+		t.Errorf("dialog: got %+v", result.Dialog)
+	}
+
+	// Recognized without the process tree, e.g. when launched through a wrapper.
+	if result := NewRegistry().Parse(content, nil); result == nil || result.Agent != "claude_code" {
+		t.Errorf("expected the trust marker to identify claude_code, got %+v", result)
+	}
+}
+
+func TestClaude_Onboarding(t *testing.T) {
+	theme := `
+ Let's get started.
+
+ Choose the text style that looks best with your terminal:
+ To change this later, run /config
+
+   1. Dark mode
+ ❯ 2. Light mode
+   3. Dark mode (colorblind-friendly)
+`
+	result := NewRegistry().Parse(theme, []string{"claude"})
+	if result == nil || result.Reason != "onboarding at startup" || result.Dialog == nil {
+		t.Fatalf("theme picker: got %+v", result)
+	}
+	if len(result.Actions) != 3 || result.Recommended != 1 || result.Actions[1].Label != "Light mode" || result.Actions[1].Keys != "2" {
+		t.Errorf("theme picker actions: %+v (recommended %d)", result.Actions, result.Recommended)
+	}
+
+	notice := `
+ Security notes:
+
+ 1. Claude can make mistakes
+ 2. Due to prompt injection risks, only use it with code you trust
+
+ Press Enter to continue…
+`
+	result = NewRegistry().Parse(notice, []string{"claude"})
+	if result == nil || result.Reason != "notice waiting for Enter" || len(result.Actions) != 1 || result.Actions[0].Keys != "Enter" {
+		t.Errorf("notice: got %+v", result)
+	}
+}
+
 func TestClaude_AutoResolve(t *testing.T) {
 	content := `
   Auto-selecting in 3s… Press any key to intervene.
@@ -510,6 +665,74 @@ func TestClaude_IdentifiedByAmbiguousThinkingIndicators(t *testing.T) {
 
 // --- Codex Parser Tests ---
 
+func TestCodex_SignIn(t *testing.T) {
+	picker := `
+  >_ Welcome to Codex, OpenAI's command-line coding agent
+
+  Sign in with ChatGPT to use Codex as part of your paid plan
+  or connect an API key for usage-based billing
+
+  > 1. Sign in with ChatGPT
+       Usage included with Plus, Pro, and Team plans
+    2. Provide your own API key
+       Pay for what you use
+
+  Press Enter to continue
+`
+	result := NewRegistry().Parse(picker, []string{"codex"})
+	if result == nil || result.Reason != "auth required" || result.Dialog == nil || result.Dialog.Kind != model.DialogAuth {
+		t.Fatalf("picker: got %+v", result)
+	}
+	if result.Recommended != -1 || len(result.Actions) != 2 || result.Actions[0].Keys != "Enter" ||
+		result.Actions[1].Keys != "Down Enter" || result.Actions[1].Label != "Provide your own API key" {
+		t.Errorf("picker actions: %+v (recommended %d)", result.Actions, result.Recommended)
+	}
+
+	device := `
+Follow these steps to sign in with ChatGPT using device code authorization:
+
+1. Open this link in your browser and sign in to your account
+   https://auth.openai.com/codex/device
+
+2. Enter this one-time code (expires in 15 minutes)
+   QX7R-2K9M
+`
+	result = NewRegistry().Parse(device, []string{"codex"})
+	if result == nil || result.Reason != "auth required" || result.Dialog == nil || result.Dialog.Code != "QX7R-2K9M" {
+		t.Fatalf("device code: got %+v", result)
+	}
+	if result.WaitingFor != "sign in with ChatGPT: enter code QX7R-2K9M at https://auth.openai.com/codex/device" || result.Recommended != -1 {
+		t.Errorf("device code: waiting for %q, recommended %d", result.WaitingFor, result.Recommended)
+	}
+}
+
+func TestCodex_UpdatePrompt(t *testing.T) {
+	content := `
+  ✨ Update available! 0.40.0 -> 0.41.0
+
+  Release notes: https://github.com/openai/codex/releases/latest
+
+› 1. Update now (runs ` + "`npm install -g @openai/codex`" + `)
+  2. Skip
+  3. Skip until next version
+
+  Press enter to continue
+`
+	result := NewRegistry().Parse(content, []string{"codex"})
+	if result == nil || result.Reason != "update prompt" || !result.Blocked {
+		t.Fatalf("got %+v", result)
+	}
+	want := []string{"Enter medium", "Down Enter low", "Down Down Enter low"}
+	for i, a := range result.Actions {
+		if got := a.Keys + " " + a.Risk; i >= len(want) || got != want[i] {
+			t.Errorf("action %d: got %q (%s)", i, got, a.Label)
+		}
+	}
+	if result.Recommended != 1 {
+		t.Errorf("skipping should be recommended, got %d", result.Recommended)
+	}
+}
+
 func TestCodex_ExecApproval(t *testing.T) {
 	content := `
   Would you like to run the following command?
@@ -599,6 +822,11 @@ func TestCodex_MCPApproval(t *testing.T) {
 	if !result.Blocked {
 		t.Error("expected blocked=true for MCP approval")
 	}
+	// The options follow approval_overlay.rs, one action each, then Escape.
+	if result.Dialog == nil || len(result.Dialog.Options) != 3 || len(result.Actions) != 4 ||
+		result.Dialog.Options[1].Label != "No, but continue without it" || result.Actions[1].Keys != "Down Enter" {
+		t.Errorf("options %+v, actions %+v", result.Dialog, result.Actions)
+	}
 }
 
 func TestCodex_ActiveWorking(t *testing.T) {
@@ -1920,12 +2148,32 @@ func TestOpenCode_QuestionDialogMultiTab(t *testing.T) {
 	if !result.Blocked {
 		t.Error("expected Blocked=true")
 	}
-	// WaitingFor should contain tab header info
-	if !strings.Contains(result.WaitingFor, "[tabs]") {
-		t.Errorf("WaitingFor should contain [tabs], got: %q", result.WaitingFor)
+	// Tab headers are structured, not embedded in WaitingFor.
+	d := result.Dialog
+	if d == nil || d.Kind != model.DialogQuestion {
+		t.Fatalf("expected question dialog, got %+v", d)
+	}
+	wantTabs := []string{"Next steps", "Aspire wt config", "Skill overlap", "Confirm"}
+	if strings.Join(d.Tabs, "|") != strings.Join(wantTabs, "|") {
+		t.Errorf("Tabs: got %q, want %q", d.Tabs, wantTabs)
+	}
+	if d.ActiveTab != -1 {
+		t.Errorf("ActiveTab: got %d, want -1 (color-only highlight)", d.ActiveTab)
+	}
+	if !d.MultiSelect || len(d.Options) != 3 {
+		t.Fatalf("expected 3 multi-select options, got %+v", d.Options)
+	}
+	if d.Options[0].Label != "Configure wt for multi-repo" || d.Options[0].Checked {
+		t.Errorf("option 1: got %+v", d.Options[0])
+	}
+	if d.Options[0].Description != "Set up custom pattern on both machines" {
+		t.Errorf("option 1 description: got %q", d.Options[0].Description)
 	}
-	if !strings.Contains(result.WaitingFor, "Next steps") {
-		t.Errorf("WaitingFor should contain tab name, got: %q", result.WaitingFor)
+	if !strings.HasPrefix(d.Question, "Now that superpowers is installed") {
+		t.Errorf("Question: got %q", d.Question)
+	}
+	if strings.Contains(result.WaitingFor, "Next steps") {
+		t.Errorf("WaitingFor should not contain tab headers, got: %q", result.WaitingFor)
 	}
 	// Should have Tab and BTab actions
 	hasTab := false
@@ -1977,10 +2225,14 @@ func TestOpenCode_ConfirmTab(t *testing.T) {
 	if !strings.Contains(result.Reason, "confirm") {
 		t.Errorf("reason should mention confirm, got: %q", result.Reason)
 	}
-	// WaitingFor should contain tab headers and review content
-	if !strings.Contains(result.WaitingFor, "[confirm tab]") {
-		t.Errorf("WaitingFor should contain [confirm tab], got: %q", result.WaitingFor)
+	d := result.Dialog
+	if d == nil || d.Kind != model.DialogConfirm {
+		t.Fatalf("expected confirm dialog, got %+v", d)
+	}
+	if len(d.Tabs) != 4 || d.ActiveTab != 3 {
+		t.Errorf("expected Confirm (index 3) active among 4 tabs, got tabs=%q active=%d", d.Tabs, d.ActiveTab)
 	}
+	// WaitingFor should contain the review content
 	if !strings.Contains(result.WaitingFor, "Configure wt") {
 		t.Errorf("WaitingFor should contain review answers, got: %q", result.WaitingFor)
 	}
@@ -2217,7 +2469,883 @@ func TestOpenCode_SingleQuestionNoTabs(t *testing.T) {
 			t.Errorf("single question should not have Tab/BTab actions, found: %+v", a)
 		}
 	}
-	if strings.Contains(result.WaitingFor, "[tabs]") {
-		t.Errorf("single question should not have [tabs] in WaitingFor, got: %q", result.WaitingFor)
+	if result.Dialog == nil || len(result.Dialog.Tabs) != 0 {
+		t.Errorf("single question should have no tabs, got: %+v", result.Dialog)
+	}
+}
+
+// --- Model extraction ---
+
+func TestOpenCode_ExtractsModelFromBuildLine(t *testing.T) {
+	content := `
+  ▣ Build · claude-sonnet-4-5 · 12s
+
+  ■■■⬝⬝⬝⬝⬝
+
+  esc interrupt
+`
+	p := &OpenCodeParser{}
+	result := p.Parse(content, []string{"opencode"})
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+	if result.Model != "claude-sonnet-4-5" {
+		t.Errorf("model: got %q, want %q", result.Model, "claude-sonnet-4-5")
+	}
+}
+
+func TestOpenCode_ExtractsMostRecentModel(t *testing.T) {
+	content := `
+  ▣ Build · gpt-4o · 3s
+
+  some output
+
+  ▣ Plan · claude-opus-4-1 · 20s
+
+  >
+`
+	p := &OpenCodeParser{}
+	result := p.Parse(content, []string{"opencode"})
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+	if result.Model != "claude-opus-4-1" {
+		t.Errorf("model: got %q, want %q (bottom-most Build/Plan line wins)", result.Model, "claude-opus-4-1")
+	}
+}
+
+func TestOpenCode_NoModelWhenNoBuildLine(t *testing.T) {
+	content := `
+  >
+`
+	p := &OpenCodeParser{}
+	result := p.Parse(content, []string{"opencode"})
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+	if result.Model != "" {
+		t.Errorf("model: got %q, want empty", result.Model)
+	}
+}
+
+func TestCodex_ExtractsModelFromSplash(t *testing.T) {
+	content := `
+│ >_ OpenAI Codex (v0.104.0)                  │
+│                                             │
+│ model:     gpt-5.3-codex   /model to change │
+│ directory: /tmp                             │
+╰─────────────────────────────────────────────╯
+› Run /review on my current changes
+  ? for shortcuts                                                                                     100% context left
+`
+	r := NewRegistry()
+	result := r.Parse(content, []string{"codex"})
+	if result == nil {
+		t.Fatal("expected non-nil result")
+	}
+	if result.Model != "gpt-5.3-codex" {
+		t.Errorf("model: got %q, want %q", result.Model, "gpt-5.3-codex")
+	}
+}
+
+// --- Generic prompt parser ---
+
+func TestGenericPrompt_YesNo(t *testing.T) {
+	tests := []string{
+		"Do you want to continue? [Y/n]",
+		"Remove file 'foo.txt'? (y/n)",
+		"Proceed ([y]/n)? [y/N] ",
+		"Are you sure you want to continue connecting (yes/no)?",
+	}
+	p := &GenericPromptParser{}
+	for _, last := range tests {
+		result := p.Parse("some output\n"+last+"\n\n", nil)
+		if result == nil {
+			t.Errorf("%q: expected match", last)
+			continue
+		}
+		if result.Agent != AgentGenericPrompt {
+			t.Errorf("%q: agent: got %q, want %q", last, result.Agent, AgentGenericPrompt)
+		}
+		if !result.Blocked {
+			t.Errorf("%q: expected blocked=true", last)
+		}
+		if result.Reason != "yes/no confirmation prompt" {
+			t.Errorf("%q: reason: got %q", last, result.Reason)
+		}
+		rec := result.Actions[result.Recommended]
+		if rec.Risk != "low" || rec.Keys != "n Enter" {
+			t.Errorf("%q: recommended action should be the safe 'no', got %+v", last, rec)
+		}
+	}
+}
+
+func TestGenericPrompt_Password(t *testing.T) {
+	p := &GenericPromptParser{}
+	for _, last := range []string{"Password:", "[sudo] password for tim: ", "Enter passphrase for key '/home/tim/.ssh/id_ed25519':"} {
+		result := p.Parse("$ sudo apt upgrade\n"+last, nil)
+		if result == nil {
+			t.Errorf("%q: expected match", last)
+			continue
+		}
+		if result.Reason != "password prompt" {
+			t.Errorf("%q: reason: got %q", last, result.Reason)
+		}
+		for _, a := range result.Actions {
+			if a.Keys != "C-c" {
+				t.Errorf("%q: password prompt must not suggest typed input, got %q", last, a.Keys)
+			}
+		}
+	}
+}
+
+func TestGenericPrompt_PressEnter(t *testing.T) {
+	p := &GenericPromptParser{}
+	result := p.Parse("Installation complete.\nPress ENTER to continue...", nil)
+	if result == nil {
+		t.Fatal("expected match")
+	}
+	if result.Actions[result.Recommended].Keys != "Enter" {
+		t.Errorf("expected Enter recommended, got %q", result.Actions[result.Recommended].Keys)
+	}
+}
+
+func TestGenericPrompt_OnlyLastLine(t *testing.T) {
+	// A prompt that has scrolled up (answered) is not waiting for input.
+	content := "Do you want to continue? [Y/n] y\nSetting up foo...\n$ "
+	p := &GenericPromptParser{}
+	if result := p.Parse(content, nil); result != nil {
+		t.Errorf("expected nil for answered prompt above shell, got %+v", result)
+	}
+}
+
+func TestRegistry_AgentParsersWinOverGenericPrompt(t *testing.T) {
+	// Claude Code content that happens to end in a y/n-looking line must
+	// still be attributed to Claude Code.
+	content := `
+ Do you want to proceed?
+ ❯ 1. Yes
+   2. No
+ Esc to cancel · Tab to amend (y/n)
+`
+	r := NewRegistry()
+	result := r.Parse(content, []string{"claude"})
+	if result == nil || result.Agent != "claude_code" {
+		t.Fatalf("expected claude_code, got %+v", result)
+	}
+}
+
+// --- Custom Parser Tests ---
+
+func acmeParserDef() config.CustomParser {
+	return config.CustomParser{
+		Name:     "acme_agent",
+		Identify: config.CustomParserIdentify{Process: []string{`^acme-agent$`}},
+		Blocked: []config.CustomParserRule{
+			{
+				Pattern: `^Approve (\w+) call\?`,
+				Reason:  "approval for $1",
+				Actions: []model.Action{
+					{Keys: "y", Label: "approve $1", Risk: "medium", Raw: true},
+					{Keys: "n", Label: "deny", Risk: "low", Raw: true},
+				},
+				Recommended: 1,
+			},
+		},
+		Active: []string{`Thinking…`},
+	}
+}
+
+func TestCustomParser_Blocked(t *testing.T) {
+	p, err := NewCustomParser(acmeParserDef())
+	if err != nil {
+		t.Fatalf("NewCustomParser: %v", err)
+	}
+	content := "ACME Agent\n\n  Approve shell call? [y/n]\n"
+	result := p.Parse(content, []string{"acme-agent"})
+	if result == nil {
+		t.Fatal("expected a result")
+	}
+	if result.Agent != "acme_agent" || !result.Blocked {
+		t.Fatalf("expected blocked acme_agent, got %+v", result)
+	}
+	if result.Reason != "approval for shell" {
+		t.Errorf("Reason: got %q, want %q", result.Reason, "approval for shell")
+	}
+	if result.Actions[0].Label != "approve shell" {
+		t.Errorf("Label: got %q, want %q", result.Actions[0].Label, "approve shell")
+	}
+	if result.Recommended != 1 {
+		t.Errorf("Recommended: got %d, want 1", result.Recommended)
+	}
+}
+
+func TestCustomParser_ActiveAndFallthrough(t *testing.T) {
+	p, err := NewCustomParser(acmeParserDef())
+	if err != nil {
+		t.Fatalf("NewCustomParser: %v", err)
+	}
+	result := p.Parse("ACME Agent\n  Thinking…\n", []string{"acme-agent"})
+	if result == nil || result.Blocked || result.Reason != "actively working" {
+		t.Errorf("expected active, got %+v", result)
+	}
+	result = p.Parse("ACME Agent\n> \n", []string{"acme-agent"})
+	if result == nil || result.Blocked || result.Reason != "no rule matched" {
+		t.Errorf("expected no rule matched, got %+v", result)
+	}
+}
+
+func TestCustomParser_NotIdentified(t *testing.T) {
+	p, err := NewCustomParser(acmeParserDef())
+	if err != nil {
+		t.Fatalf("NewCustomParser: %v", err)
+	}
+	if result := p.Parse("Approve shell call?\n", []string{"bash"}); result != nil {
+		t.Errorf("expected nil for unidentified pane, got %+v", result)
+	}
+}
+
+func TestNewCustomParser_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*config.CustomParser)
+	}{
+		{"missing name", func(d *config.CustomParser) { d.Name = "" }},
+		{"reserved name", func(d *config.CustomParser) { d.Name = "codex" }},
+		{"no identify", func(d *config.CustomParser) { d.Identify = config.CustomParserIdentify{} }},
+		{"bad regex", func(d *config.CustomParser) { d.Active = []string{"("} }},
+		{"bad risk", func(d *config.CustomParser) { d.Blocked[0].Actions[0].Risk = "critical" }},
+		{"recommended out of range", func(d *config.CustomParser) { d.Blocked[0].Recommended = 5 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := acmeParserDef()
+			tt.modify(&def)
+			if _, err := NewCustomParser(def); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNewCustomParsers_DuplicateName(t *testing.T) {
+	if _, err := NewCustomParsers([]config.CustomParser{acmeParserDef(), acmeParserDef()}); err == nil {
+		t.Error("expected error for duplicate parser names")
+	}
+}
+
+func TestRegistry_CustomParserAfterBuiltins(t *testing.T) {
+	custom, err := NewCustomParsers([]config.CustomParser{{
+		Name:     "greedy",
+		Identify: config.CustomParserIdentify{Content: []string{`.`}},
+	}})
+	if err != nil {
+		t.Fatalf("NewCustomParsers: %v", err)
+	}
+	r := NewRegistry(custom...)
+
+	// Builtin agents still win.
+	content := `
+ Do you want to proceed?
+ ❯ 1. Yes
+   2. No
+ Esc to cancel · Tab to amend
+`
+	if result := r.Parse(content, []string{"claude"}); result == nil || result.Agent != "claude_code" {
+		t.Fatalf("expected claude_code, got %+v", result)
+	}
+	// Custom parsers run before the generic prompt parser.
+	if result := r.Parse("Continue? [y/N]", nil); result == nil || result.Agent != "greedy" {
+		t.Fatalf("expected greedy, got %+v", result)
+	}
+}
+
+// --- Confidence / Conflict Resolution Tests ---
+
+func TestRegistry_ProcessTreeOutranksContentMarker(t *testing.T) {
+	// Claude Code discussing Codex: the "OpenAI Codex" banner text is a Codex
+	// content marker, but the process tree says this is Claude Code. Codex is
+	// registered first, so first-match would misattribute the pane.
+	content := `
+ ⏺ The OpenAI Codex CLI supports sandboxing via --sandbox.
+
+ ❯
+ ? for shortcuts
+`
+	r := NewRegistry()
+	result := r.Parse(content, []string{"claude"})
+	if result == nil || result.Agent != "claude_code" {
+		t.Fatalf("expected claude_code, got %+v", result)
+	}
+	if result.Confidence != ConfidenceProcess {
+		t.Errorf("Confidence: got %v, want %v", result.Confidence, ConfidenceProcess)
+	}
+}
+
+func TestRegistry_SharedFooterPrefersUniqueMarker(t *testing.T) {
+	// "? for shortcuts" + "context left" is Codex; Claude only sees the
+	// shared footer.
+	content := `
+ › Summarize recent commits
+
+ ? for shortcuts                                   100% context left
+`
+	r := NewRegistry()
+	all := r.ParseAll(content, nil)
+	if len(all) < 2 {
+		t.Fatalf("expected both Codex and Claude Code to match, got %d results", len(all))
+	}
+	result := r.Parse(content, nil)
+	if result == nil || result.Agent != "codex" {
+		t.Fatalf("expected codex, got %+v", result)
+	}
+	if result.Confidence != ConfidenceMarker {
+		t.Errorf("Confidence: got %v, want %v", result.Confidence, ConfidenceMarker)
+	}
+}
+
+func TestRegistry_SharedEvidenceConfidence(t *testing.T) {
+	content := "\n ❯ \n ? for shortcuts\n"
+	result := NewRegistry().Parse(content, nil)
+	if result == nil || result.Agent != "claude_code" {
+		t.Fatalf("expected claude_code, got %+v", result)
+	}
+	if result.Confidence != ConfidenceShared {
+		t.Errorf("Confidence: got %v, want %v", result.Confidence, ConfidenceShared)
+	}
+}
+
+func TestGenericPrompt_LowestConfidence(t *testing.T) {
+	result := (&GenericPromptParser{}).Parse("Continue? [y/N]", nil)
+	if result == nil || result.Confidence != ConfidenceGeneric {
+		t.Fatalf("expected ConfidenceGeneric, got %+v", result)
+	}
+}
+
+func TestRegistry_TraceIncludesNonMatching(t *testing.T) {
+	content := "\n ❯ \n ? for shortcuts\n"
+	trace := NewRegistry().Trace(content, []string{"claude"})
+	want := []string{"opencode", "codex", "claude_code", "shell_prompt"}
+	if len(trace) != len(want) {
+		t.Fatalf("trace: got %d entries, want %d", len(trace), len(want))
+	}
+	for i, name := range want {
+		if trace[i].Parser != name {
+			t.Errorf("trace[%d].Parser: got %q, want %q", i, trace[i].Parser, name)
+		}
+	}
+	if trace[0].Result != nil {
+		t.Errorf("opencode should not match, got %+v", trace[0].Result)
+	}
+	claude := trace[2].Result
+	if claude == nil {
+		t.Fatal("expected claude_code to match")
+	}
+	if claude.Evidence != `process "claude"` {
+		t.Errorf("Evidence: got %q", claude.Evidence)
+	}
+}
+
+func TestResult_EvidenceDescribesMarker(t *testing.T) {
+	content := `△ Permission required
+  $ rm -rf /tmp/test
+  Allow once  Allow always  Reject`
+	result := NewRegistry().Parse(content, nil)
+	if result == nil || result.Agent != "opencode" {
+		t.Fatalf("expected opencode, got %+v", result)
+	}
+	if !strings.Contains(result.Evidence, "Permission required") {
+		t.Errorf("Evidence: got %q, want the permission marker", result.Evidence)
+	}
+}
+
+func TestExaminedLines(t *testing.T) {
+	content := "a\nb\n\n" + strings.Repeat("x\n", bottomLines) + "\n\n"
+	lines := ExaminedLines(content)
+	if len(lines) != bottomLines {
+		t.Fatalf("got %d lines, want %d", len(lines), bottomLines)
+	}
+	for _, l := range lines {
+		if l != "x" {
+			t.Errorf("unexpected examined line %q", l)
+		}
+	}
+}
+
+func TestClaude_PermissionDialogStructured(t *testing.T) {
+	content := `
+  Claude needs your permission to use Bash
+
+  $ npm test
+
+  Do you want to proceed?
+    1. Yes
+  ❯ 2. Yes, and don't ask again for npm test commands
+    3. No
+`
+	result := (&ClaudeCodeParser{}).Parse(content, []string{"claude"})
+	if result == nil || result.Dialog == nil {
+		t.Fatalf("expected a dialog, got %+v", result)
+	}
+	d := result.Dialog
+	if d.Kind != model.DialogPermission || d.Question != "Do you want to proceed?" {
+		t.Errorf("got kind=%q question=%q", d.Kind, d.Question)
+	}
+	if len(d.Options) != 3 || d.Options[2].Label != "No" {
+		t.Fatalf("options: got %+v", d.Options)
+	}
+	if d.Options[0].Selected || !d.Options[1].Selected {
+		t.Errorf("expected option 2 under the cursor, got %+v", d.Options)
+	}
+}
+
+func TestCodex_QuestionDialogStructured(t *testing.T) {
+	content := `
+  Which database should we use?
+
+  › 1. PostgreSQL
+       Relational, production ready
+    2. SQLite
+
+  enter to submit answer  esc to interrupt
+`
+	result := (&CodexParser{}).Parse(content, []string{"codex"})
+	if result == nil || result.Dialog == nil {
+		t.Fatalf("expected a dialog, got %+v", result)
+	}
+	d := result.Dialog
+	if d.Kind != model.DialogQuestion || d.Question != "Which database should we use?" {
+		t.Errorf("got kind=%q question=%q", d.Kind, d.Question)
+	}
+	if len(d.Options) != 2 {
+		t.Fatalf("options: got %+v", d.Options)
+	}
+	if d.Options[0].Label != "PostgreSQL" || !d.Options[0].Selected || d.Options[0].Description != "Relational, production ready" {
+		t.Errorf("option 1: got %+v", d.Options[0])
+	}
+	if d.Options[1].Selected || d.MultiSelect {
+		t.Errorf("option 2: got %+v (multi=%v)", d.Options[1], d.MultiSelect)
+	}
+}
+
+func TestCodex_QuestionNotesAction(t *testing.T) {
+	content := `
+  Which database should we use?
+
+  › 1. PostgreSQL
+    2. SQLite
+
+  tab to add notes | enter to submit answer | esc to interrupt
+`
+	result := (&CodexParser{}).Parse(content, []string{"codex"})
+	if result == nil || len(result.Actions) != 5 {
+		t.Fatalf("expected 2 options, notes, submit, and dismiss, got %+v", result)
+	}
+	if a := result.Actions[2]; a.Keys != "Tab" || a.Label != "add notes" || !a.Text || !a.Raw {
+		t.Errorf("notes action: got %+v", a)
+	}
+
+	// Without the footer tip there is no notes field to tab into.
+	result = (&CodexParser{}).Parse(strings.Replace(content, "tab to add notes | ", "", 1), []string{"codex"})
+	for _, a := range result.Actions {
+		if a.Text {
+			t.Errorf("unexpected notes action: %+v", a)
+		}
+	}
+}
+
+func TestCodex_QuestionNavigation(t *testing.T) {
+	content := `
+  Question 2/3
+  Which test runner?
+
+  › 1. Jest
+    2. Vitest
+
+  enter to submit all | ←/→ to navigate questions | esc to interrupt
+`
+	result := (&CodexParser{}).Parse(content, []string{"codex"})
+	if result == nil || result.Dialog == nil {
+		t.Fatalf("expected a dialog, got %+v", result)
+	}
+	d := result.Dialog
+	if strings.Join(d.Tabs, ",") != "Q1,Q2,Q3" || d.ActiveTab != 1 {
+		t.Errorf("tabs: got %v active %d", d.Tabs, d.ActiveTab)
+	}
+	if d.Question != "Which test runner?" || strings.Contains(result.WaitingFor, "Question 2/3") {
+		t.Errorf("the position should not be part of the question: %q / %q", d.Question, result.WaitingFor)
+	}
+	var keys []string
+	for _, a := range result.Actions {
+		keys = append(keys, a.Keys)
+	}
+	if got := strings.Join(keys, " "); got != "1 2 Enter Right Left Escape" {
+		t.Errorf("action keys: got %q", got)
+	}
+
+	// A single question has no tabs and nothing to navigate to.
+	result = (&CodexParser{}).Parse(`
+  Which test runner?
+
+  › 1. Jest
+    2. Vitest
+
+  enter to submit answer | esc to interrupt
+`, []string{"codex"})
+	if result.Dialog.Tabs != nil || result.Dialog.ActiveTab != -1 || len(result.Actions) != 4 {
+		t.Errorf("single question: tabs %v active %d actions %d", result.Dialog.Tabs, result.Dialog.ActiveTab, len(result.Actions))
+	}
+}
+
+func TestIdle_NoDialog(t *testing.T) {
+	result := (&ClaudeCodeParser{}).Parse("\n ❯ \n ? for shortcuts\n", []string{"claude"})
+	if result == nil || result.Dialog != nil {
+		t.Errorf("idle prompt should have no dialog, got %+v", result)
+	}
+}
+
+// --- Risk Rule Tests ---
+
+func TestRiskRules_OverrideMatchingActions(t *testing.T) {
+	def := acmeParserDef()
+	custom, err := NewCustomParsers([]config.CustomParser{def})
+	if err != nil {
+		t.Fatalf("NewCustomParsers: %v", err)
+	}
+	rules, err := NewRiskRules([]config.RiskRule{
+		{WaitingFor: `rm -rf`, Action: `^approve`, Risk: "high"},
+		{Agent: "acme_agent", Action: `^approve`, Risk: "low"},
+		{Agent: "codex", Risk: "high"},
+	})
+	if err != nil {
+		t.Fatalf("NewRiskRules: %v", err)
+	}
+	r := NewRegistry(custom...).WithRiskRules(rules)
+
+	result := r.Parse("ACME Agent\n  Approve shell call? rm -rf build [y/n]\n", []string{"acme-agent"})
+	if result == nil || !result.Blocked {
+		t.Fatalf("expected blocked acme_agent, got %+v", result)
+	}
+	if result.Actions[0].Risk != "high" || result.Actions[1].Risk != "low" {
+		t.Errorf("risks: got %q, %q; want high, low", result.Actions[0].Risk, result.Actions[1].Risk)
+	}
+	if !strings.Contains(result.Reasoning, `risk of "approve shell" set from medium to high by risk_rules[0]`) {
+		t.Errorf("expected override in Reasoning, got %q", result.Reasoning)
+	}
+
+	// Without rm -rf the second rule matches first.
+	result = r.Parse("ACME Agent\n  Approve shell call? [y/n]\n", []string{"acme-agent"})
+	if result.Actions[0].Risk != "low" {
+		t.Errorf("risk: got %q, want low", result.Actions[0].Risk)
+	}
+
+	// The parser's shared action definitions are left untouched.
+	if def.Blocked[0].Actions[0].Risk != "medium" {
+		t.Errorf("config action mutated: %+v", def.Blocked[0].Actions[0])
+	}
+}
+
+func TestRiskRules_OnlyBlockedPanes(t *testing.T) {
+	rules, _ := NewRiskRules([]config.RiskRule{{Agent: "claude_code", Risk: "high"}})
+	result := &Result{Agent: "claude_code", Actions: []model.Action{{Keys: "1", Label: "yes", Risk: "low"}}}
+	applyRiskRules(result, rules)
+	if result.Actions[0].Risk != "low" {
+		t.Errorf("expected active pane to keep its risk, got %q", result.Actions[0].Risk)
+	}
+}
+
+func TestNewRiskRules_Invalid(t *testing.T) {
+	tests := map[string]config.RiskRule{
+		"bad risk":     {Agent: "codex", Risk: "critical"},
+		"no matcher":   {Risk: "high"},
+		"bad action":   {Action: "(", Risk: "high"},
+		"bad question": {WaitingFor: "[", Risk: "high"},
+	}
+	for name, def := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewRiskRules([]config.RiskRule{def}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// --- Idle Action Tests ---
+
+func TestIdleActions_ReplaceRecommendation(t *testing.T) {
+	idle, err := NewIdleActions(map[string]config.IdleAction{
+		"claude_code": {Send: "continue with the plan"},
+		"codex":       {None: true},
+	})
+	if err != nil {
+		t.Fatalf("NewIdleActions: %v", err)
+	}
+	r := NewRegistry().WithIdleActions(idle)
+
+	result := r.Parse("\n ❯ \n ? for shortcuts\n", []string{"claude"})
+	if result == nil || result.Reason != IdleReason {
+		t.Fatalf("expected idle claude_code, got %+v", result)
+	}
+	if len(result.Actions) != 2 || result.Recommended != 0 {
+		t.Fatalf("actions: got %+v (recommended %d)", result.Actions, result.Recommended)
+	}
+	want := model.Action{Keys: "continue with the plan", Label: "send continue with the plan", Risk: "low"}
+	if result.Actions[0] != want || result.Actions[1].Keys != "Enter" {
+		t.Errorf("actions: got %+v, want %+v then Enter", result.Actions, want)
+	}
+	if !strings.Contains(result.Reasoning, `"continue with the plan" recommended by idle_actions.claude_code`) {
+		t.Errorf("expected the idle action in Reasoning, got %q", result.Reasoning)
+	}
+
+	result = &Result{Agent: "codex", Blocked: true, Reason: IdleReason,
+		Actions: []model.Action{{Keys: "Enter", Label: "continue", Risk: "low", Raw: true}}}
+	applyIdleAction(result, idle)
+	if result.Recommended != -1 || len(result.Actions) != 1 {
+		t.Errorf("none: got %+v (recommended %d)", result.Actions, result.Recommended)
+	}
+
+	// Dialogs keep the parser's recommendation.
+	dialog := &Result{Agent: "claude_code", Blocked: true, Reason: "permission dialog",
+		Actions: []model.Action{{Keys: "1", Label: "yes", Risk: "low"}}}
+	applyIdleAction(dialog, idle)
+	if len(dialog.Actions) != 1 || dialog.Recommended != 0 {
+		t.Errorf("dialog changed: %+v", dialog.Actions)
+	}
+}
+
+func TestIdleStates(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		process []string
+		want    string
+	}{
+		{"claude finished", `
+⏺ Updated the parser and its tests.
+
+✻ Worked for 2m 15s
+
+────────────────────────────
+❯
+────────────────────────────
+  ? for shortcuts
+`, []string{"claude"}, model.IdleFinished},
+		{"claude question then finished", `
+⏺ The tests pass. Should I also update the README?
+
+✻ Sautéed for 45s
+
+────────────────────────────
+❯
+────────────────────────────
+  ? for shortcuts
+`, []string{"claude"}, model.IdleFinished},
+		{"claude fresh", `
+╭───────────────────────────────╮
+│ ✻ Welcome to Claude Code!     │
+│   cwd: /home/dev/api          │
+╰───────────────────────────────╯
+
+────────────────────────────
+❯
+────────────────────────────
+  ? for shortcuts
+`, []string{"claude"}, model.IdleFresh},
+		{"codex fresh", `
+╭─────────────────────────────────────────────╮
+│ >_ OpenAI Codex (v0.104.0)                  │
+│ model:     gpt-5.3-codex   /model to change │
+╰─────────────────────────────────────────────╯
+
+› Run /review on my current changes
+  ? for shortcuts                                                                                     100% context left
+`, []string{"codex"}, model.IdleFresh},
+		{"codex finished", `
+╭─────────────────────────────────────────────╮
+│ >_ OpenAI Codex (v0.104.0)                  │
+╰─────────────────────────────────────────────╯
+
+• Renamed the flag and updated the docs.
+
+─ Worked for 1m 02s ─────────────────────────────
+
+› Run /review on my current changes
+  ? for shortcuts                                                                                     100% context left
+`, []string{"codex"}, model.IdleFinished},
+		{"output scrolled away", "\n ❯ \n ? for shortcuts\n", []string{"claude"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewRegistry().Parse(tt.content, tt.process)
+			if result == nil || result.Reason != IdleReason {
+				t.Fatalf("expected idle at prompt, got %+v", result)
+			}
+			if result.Idle != tt.want {
+				t.Errorf("idle state: got %q, want %q (activity %q)", result.Idle, tt.want, result.Activity)
+			}
+		})
+	}
+
+	// Only panes idle at their prompt get a state.
+	r := &Result{Agent: "claude_code", Blocked: true, Reason: "permission dialog"}
+	annotateIdle(r, "✻ Worked for 2m 15s\n")
+	if r.Idle != "" {
+		t.Errorf("dialog got idle state %q", r.Idle)
+	}
+}
+
+func TestNewIdleActions_Invalid(t *testing.T) {
+	tests := map[string]config.IdleAction{
+		"empty":    {},
+		"both":     {Send: "/compact", None: true},
+		"bad risk": {Send: "/compact", Risk: "critical"},
+	}
+	for name, def := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewIdleActions(map[string]config.IdleAction{"codex": def}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+// --- Command Safety Tests ---
+
+func TestAnalyzeCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"git log --oneline -10", nil},
+		{"rm -rf build", []string{"rm -rf: recursive forced delete"}},
+		{"sudo /bin/rm -r -f /tmp/x", []string{"rm -rf: recursive forced delete"}},
+		{"rm -Rf --one-file-system dist", []string{"rm -rf: recursive forced delete"}},
+		{"rm -r build", nil},
+		{"dd if=image.iso of=/dev/sda bs=4M", []string{"dd of=: raw write to a file or device"}},
+		{"curl -fsSL https://example.com/install.sh | sh", []string{"curl | sh: runs a downloaded script"}},
+		{"wget -qO- https://example.com/x | sudo bash -s", []string{"curl | sh: runs a downloaded script"}},
+		{"curl -o install.sh https://example.com/install.sh", nil},
+		{"git push --force origin main", []string{"git push --force: rewrites remote history"}},
+		{"git -C repo push -f", []string{"git push --force: rewrites remote history"}},
+		{"git push origin +main", []string{"git push --force: rewrites remote history"}},
+		{"git push --force-with-lease", []string{"git push --force: rewrites remote history"}},
+		{"git push origin main", nil},
+		{"git fetch -f && git push", nil},
+		{"chmod -R 777 public", []string{"chmod 777: makes files writable by everyone"}},
+		{"chmod 755 bin/run", nil},
+		{"make clean && rm -rf dist; chmod 777 dist", []string{"rm -rf: recursive forced delete", "chmod 777: makes files writable by everyone"}},
+		{"FOO=1 env rm -fr x", []string{"rm -rf: recursive forced delete"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got := AnalyzeCommand(tt.command)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("AnalyzeCommand(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_CommandSafetyRaisesRisk(t *testing.T) {
+	content := `
+  Would you like to run the following command?
+
+  Reason: Clean up build output
+  $ rm -rf build
+
+  Yes, proceed
+  Yes, and don't ask again for commands that start with ` + "`rm`" + `
+  No, and tell Codex what to do differently
+`
+	result := NewRegistry().Parse(content, []string{"codex"})
+	if result == nil || result.Dialog == nil {
+		t.Fatalf("expected codex dialog, got %+v", result)
+	}
+	if result.Dialog.Command != "rm -rf build" {
+		t.Errorf("Command: got %q", result.Dialog.Command)
+	}
+	if len(result.Dialog.Warnings) != 1 || result.Dialog.Warnings[0] != "rm -rf: recursive forced delete" {
+		t.Errorf("Warnings: got %q", result.Dialog.Warnings)
+	}
+	for _, a := range result.Actions {
+		want := "high"
+		if a.Keys == "Down Down Enter" || a.Keys == "Escape" {
+			want = "low"
+		}
+		if a.Risk != want {
+			t.Errorf("%s: risk %q, want %q", a.Label, a.Risk, want)
+		}
+	}
+	if !strings.Contains(result.Reasoning, "command safety: rm -rf") {
+		t.Errorf("expected safety note in Reasoning, got %q", result.Reasoning)
+	}
+	if result.Recommended != -1 {
+		t.Errorf("approving a dangerous command should not be recommended, got %d", result.Recommended)
+	}
+}
+
+func TestRegistry_CommandExtractedWithoutWarnings(t *testing.T) {
+	content := `
+  Claude needs your permission to use Bash
+
+  $ git -C /home/user/project log --oneline -10
+
+  Do you want to proceed?
+  ❯ 1. Yes  2. Yes, and don't ask again  3. No
+`
+	result := NewRegistry().Parse(content, []string{"claude"})
+	if result == nil || result.Dialog == nil {
+		t.Fatalf("expected claude dialog, got %+v", result)
+	}
+	if result.Dialog.Command != "git -C /home/user/project log --oneline -10" || len(result.Dialog.Warnings) != 0 {
+		t.Errorf("got command %q warnings %q", result.Dialog.Command, result.Dialog.Warnings)
+	}
+	if result.Actions[0].Risk != "medium" || result.Recommended != 0 {
+		t.Errorf("risk: got %q recommended %d, want medium 0", result.Actions[0].Risk, result.Recommended)
+	}
+}
+
+func TestRegistry_ActivityAboveDialog(t *testing.T) {
+	r := NewRegistry()
+
+	claude := strings.Join([]string{
+		"⏺ Update(internal/auth/token.go)",
+		"  ⎿  Updated internal/auth/token.go with 3 additions",
+		"",
+		"⏺ Now let me clean the build directory.",
+		"",
+		"╭──────────────────────────────────────────╮",
+		"│ Bash command                             │",
+		"│                                          │",
+		"│   rm -rf build                           │",
+		"│                                          │",
+		"│ Do you want to proceed?                  │",
+		"│ ❯ 1. Yes                                 │",
+		"│   2. No                                  │",
+		"╰──────────────────────────────────────────╯",
+	}, "\n")
+	got := r.Parse(claude, []string{"claude"})
+	want := []string{
+		"⏺ Update(internal/auth/token.go)",
+		"⎿  Updated internal/auth/token.go with 3 additions",
+		"⏺ Now let me clean the build directory.",
+	}
+	if got == nil || !got.Blocked || !reflect.DeepEqual(got.Activity, want) {
+		t.Errorf("claude permission: got %+v", got)
+	}
+
+	opencode := "some previous output...\n\n  ┃  △ Permission required\n  ┃  # Bash command\n  ┃  $ git diff HEAD~3\n  ┃\n  ┃  Allow once  Allow always  Reject\n"
+	if got := r.Parse(opencode, []string{"opencode"}); got == nil || !reflect.DeepEqual(got.Activity, []string{"some previous output..."}) {
+		t.Errorf("opencode permission: got %+v", got)
+	}
+
+	idle := " ⏺ Done: the tests pass.\n\n ❯\n ? for shortcuts\n"
+	if got := r.Parse(idle, []string{"claude"}); got == nil || got.Reason != IdleReason || !reflect.DeepEqual(got.Activity, []string{"⏺ Done: the tests pass."}) {
+		t.Errorf("idle at prompt: got %+v", got)
+	}
+
+	active := "⏺ Reading files\n\n✻ Thinking… (esc to interrupt)\n"
+	if got := r.Parse(active, []string{"claude"}); got == nil || got.Blocked || got.Activity != nil {
+		t.Errorf("active panes have no activity: got %+v", got)
 	}
 }
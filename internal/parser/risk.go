@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/timvw/pane-patrol/internal/config"
+)
+
+// RiskRule is a compiled config.RiskRule. Like custom parsers, the patterns
+// are user-supplied exact strings: a rule never guesses whether a command is
+// dangerous, it only applies the level the user chose for what they matched.
+type RiskRule struct {
+	index      int
+	agent      string
+	action     *regexp.Regexp // nil matches every action
+	waitingFor *regexp.Regexp // nil matches any pane
+	risk       string
+}
+
+// NewRiskRules compiles the risk_rules config section. It returns an error
+// naming the offending rule when a definition is invalid.
+func NewRiskRules(defs []config.RiskRule) ([]RiskRule, error) {
+	rules := make([]RiskRule, 0, len(defs))
+	for i, def := range defs {
+		switch def.Risk {
+		case "low", "medium", "high":
+		default:
+			return nil, fmt.Errorf("risk_rules[%d]: risk must be low, medium, or high (got %q)", i, def.Risk)
+		}
+		if def.Agent == "" && def.Action == "" && def.WaitingFor == "" {
+			return nil, fmt.Errorf("risk_rules[%d]: needs at least one of agent, action, or waiting_for", i)
+		}
+		rule := RiskRule{index: i, agent: def.Agent, risk: def.Risk}
+		var err error
+		if def.Action != "" {
+			if rule.action, err = regexp.Compile(def.Action); err != nil {
+				return nil, fmt.Errorf("risk_rules[%d].action: %w", i, err)
+			}
+		}
+		if def.WaitingFor != "" {
+			if rule.waitingFor, err = regexp.Compile(def.WaitingFor); err != nil {
+				return nil, fmt.Errorf("risk_rules[%d].waiting_for: %w", i, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// applyRiskRules sets the risk of each action of a blocked result to that of
+// the first matching rule, noting every change in Reasoning. The actions
+// slice is copied because parsers may share it between results.
+func applyRiskRules(r *Result, rules []RiskRule) {
+	if r == nil || !r.Blocked || len(r.Actions) == 0 || len(rules) == 0 {
+		return
+	}
+	question := r.WaitingFor
+	if r.Dialog != nil {
+		question += "\n" + r.Dialog.Question
+	}
+	r.Actions = append(r.Actions[:0:0], r.Actions...)
+	var notes []string
+	for i, a := range r.Actions {
+		for _, rule := range rules {
+			if rule.agent != "" && rule.agent != r.Agent {
+				continue
+			}
+			if rule.waitingFor != nil && !rule.waitingFor.MatchString(question) {
+				continue
+			}
+			if rule.action != nil && !rule.action.MatchString(a.Label) && !rule.action.MatchString(a.Keys) {
+				continue
+			}
+			if a.Risk != rule.risk {
+				notes = append(notes, fmt.Sprintf("risk of %q set from %s to %s by risk_rules[%d]", a.Label, a.Risk, rule.risk, rule.index))
+				r.Actions[i].Risk = rule.risk
+			}
+			break
+		}
+	}
+	if len(notes) > 0 {
+		r.Reasoning = strings.TrimSpace(r.Reasoning + "\n" + strings.Join(notes, "\n"))
+	}
+}
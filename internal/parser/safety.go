@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// commandCheck is one dangerous shell command pattern. match receives the
+// words of one pipeline element (after sudo/env prefixes) and the words of
+// the element it pipes into, if any.
+type commandCheck struct {
+	warning string
+	match   func(words, next []string) bool
+}
+
+// commandChecks are the builtin safety checks for shell-approval dialogs.
+// Like the parsers, they match exact command names and flags; they do not
+// try to judge what a command will do beyond that.
+var commandChecks = []commandCheck{
+	{"rm -rf: recursive forced delete", func(w, _ []string) bool {
+		return w[0] == "rm" && (hasFlag(w, 'r', "--recursive") || hasFlag(w, 'R', "")) && hasFlag(w, 'f', "--force")
+	}},
+	{"dd of=: raw write to a file or device", func(w, _ []string) bool {
+		return w[0] == "dd" && hasArgPrefix(w, "of=")
+	}},
+	{"curl | sh: runs a downloaded script", func(w, next []string) bool {
+		return (w[0] == "curl" || w[0] == "wget") && len(next) > 0 && isShell(next[0])
+	}},
+	{"git push --force: rewrites remote history", func(w, _ []string) bool {
+		if w[0] != "git" {
+			return false
+		}
+		for i, word := range w {
+			if word == "push" {
+				return hasFlag(w[i:], 'f', "--force") || hasArgPrefix(w[i:], "--force-with-lease") || hasArgPrefix(w[i:], "+")
+			}
+		}
+		return false
+	}},
+	{"chmod 777: makes files writable by everyone", func(w, _ []string) bool {
+		return w[0] == "chmod" && (hasArg(w, "777") || hasArg(w, "0777") || hasArg(w, "a+rwx") || hasArg(w, "ugo+rwx"))
+	}},
+}
+
+// AnalyzeCommand returns the warning of each commandCheck that matches part
+// of a shell command, in check order. Commands are split on ;, &&, ||,
+// newlines, and pipes; quoting is not interpreted.
+func AnalyzeCommand(command string) []string {
+	var warnings []string
+	seen := map[string]bool{}
+	for _, pipeline := range splitCommand(command) {
+		for i, words := range pipeline {
+			var next []string
+			if i+1 < len(pipeline) {
+				next = pipeline[i+1]
+			}
+			for _, c := range commandChecks {
+				if !seen[c.warning] && c.match(words, next) {
+					seen[c.warning] = true
+					warnings = append(warnings, c.warning)
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// splitCommand splits a command line into pipelines of word lists. Leading
+// sudo, env, nohup, time, and VAR=value words are dropped, and command
+// paths are reduced to their base name (/bin/rm → rm).
+func splitCommand(command string) [][][]string {
+	command = strings.NewReplacer("&&", ";", "||", ";", "\n", ";").Replace(command)
+	var pipelines [][][]string
+	for _, part := range strings.Split(command, ";") {
+		var pipeline [][]string
+		for _, element := range strings.Split(part, "|") {
+			words := strings.Fields(element)
+			for len(words) > 0 && isCommandPrefix(words[0]) {
+				words = words[1:]
+			}
+			if len(words) == 0 {
+				continue
+			}
+			words[0] = filepath.Base(words[0])
+			pipeline = append(pipeline, words)
+		}
+		if len(pipeline) > 0 {
+			pipelines = append(pipelines, pipeline)
+		}
+	}
+	return pipelines
+}
+
+func isCommandPrefix(word string) bool {
+	switch word {
+	case "sudo", "env", "nohup", "time", "command", "exec":
+		return true
+	}
+	name, _, ok := strings.Cut(word, "=")
+	return ok && name != "" && !strings.HasPrefix(name, "-")
+}
+
+func isShell(name string) bool {
+	switch filepath.Base(name) {
+	case "sh", "bash", "zsh", "dash", "ksh", "fish":
+		return true
+	}
+	return false
+}
+
+// hasFlag reports whether words contain the long flag (if any) or the short
+// flag, alone or in a cluster such as -rf.
+func hasFlag(words []string, short rune, long string) bool {
+	for _, w := range words[1:] {
+		if long != "" && w == long {
+			return true
+		}
+		if len(w) > 1 && w[0] == '-' && w[1] != '-' && strings.ContainsRune(w[1:], short) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasArg(words []string, arg string) bool {
+	for _, w := range words[1:] {
+		if w == arg {
+			return true
+		}
+	}
+	return false
+}
+
+func hasArgPrefix(words []string, prefix string) bool {
+	for _, w := range words[1:] {
+		if strings.HasPrefix(w, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractCommand returns the shell command of a permission dialog's
+// WaitingFor: the text after "$ " on the first line that starts with it
+// (Claude Code prefixes the line with "Bash — "). Empty if there is none.
+func extractCommand(waitingFor string) string {
+	for _, line := range strings.Split(waitingFor, "\n") {
+		line = strings.TrimSpace(line)
+		if _, rest, ok := strings.Cut(line, " — "); ok {
+			line = rest
+		}
+		if cmd, ok := strings.CutPrefix(line, "$ "); ok {
+			return strings.TrimSpace(cmd)
+		}
+	}
+	return ""
+}
+
+// annotateCommand fills Dialog.Command for shell permission dialogs. When
+// AnalyzeCommand finds dangerous patterns, they are listed in
+// Dialog.Warnings and every approving action (risk above low) is raised to
+// high, so auto-nudge below high risk skips the pane and confirm_high_risk
+// asks before approving. An approving action is no longer recommended
+// (Recommended -1), so not even auto_nudge_max_risk: high approves it and
+// the panel leaves the choice to the user.
+func annotateCommand(r *Result) {
+	if r == nil || !r.Blocked || r.Dialog == nil || r.Dialog.Kind != model.DialogPermission {
+		return
+	}
+	command := extractCommand(r.WaitingFor)
+	if command == "" {
+		return
+	}
+	r.Dialog.Command = command
+	warnings := AnalyzeCommand(command)
+	if len(warnings) == 0 {
+		return
+	}
+	r.Dialog.Warnings = warnings
+	r.Actions = append(r.Actions[:0:0], r.Actions...)
+	for i := range r.Actions {
+		if r.Actions[i].Risk == "medium" {
+			r.Actions[i].Risk = "high"
+		}
+	}
+	note := "; approving actions raised to high risk"
+	if r.Recommended >= 0 && r.Recommended < len(r.Actions) && r.Actions[r.Recommended].Risk == "high" {
+		r.Recommended = -1
+		note += ", nothing recommended"
+	}
+	r.Reasoning = strings.TrimSpace(r.Reasoning + "\ncommand safety: " + strings.Join(warnings, "; ") + note)
+}
@@ -0,0 +1,85 @@
+package summary
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// Cluster groups blocked panes that are waiting on the same thing, e.g.
+// twelve panes all showing "permission dialog — $ npm install <arg>".
+//
+// Grouping is an exact match on a normalized label, not a similarity score:
+// two panes share a cluster only if their reason and the first line of their
+// WaitingFor are identical after masking numbers and file paths.
+type Cluster struct {
+	Label   string   `json:"label"`
+	Count   int      `json:"count"`
+	Targets []string `json:"targets"`
+}
+
+// ClusterLabel returns the normalized cluster label for a blocked verdict:
+// the reason, followed by the first WaitingFor line when it adds detail.
+func ClusterLabel(v model.Verdict) string {
+	reason := normalizeForCluster(v.Reason)
+	detail := ""
+	for _, line := range strings.Split(v.WaitingFor, "\n") {
+		if line = normalizeForCluster(line); line != "" {
+			detail = line
+			break
+		}
+	}
+	if detail == "" || detail == reason {
+		return reason
+	}
+	if reason == "" {
+		return detail
+	}
+	return reason + " — " + detail
+}
+
+// BuildClusters groups blocked verdicts by ClusterLabel. Clusters are
+// ordered by size (largest first), then label.
+func BuildClusters(verdicts []model.Verdict) []Cluster {
+	index := map[string]int{}
+	clusters := []Cluster{}
+	for _, v := range verdicts {
+		if StateOf(v) != StateBlocked {
+			continue
+		}
+		label := ClusterLabel(v)
+		i, ok := index[label]
+		if !ok {
+			i = len(clusters)
+			index[label] = i
+			clusters = append(clusters, Cluster{Label: label})
+		}
+		clusters[i].Count++
+		clusters[i].Targets = append(clusters[i].Targets, v.Target)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].Label < clusters[j].Label
+	})
+	return clusters
+}
+
+// normalizeForCluster collapses whitespace and masks the parts of a line
+// that differ between otherwise identical prompts: tokens containing a
+// digit become "<n>" and tokens containing a path separator become "<path>".
+func normalizeForCluster(s string) string {
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		switch {
+		case strings.Contains(f, "/"):
+			fields[i] = "<path>"
+		case strings.IndexFunc(f, unicode.IsDigit) >= 0:
+			fields[i] = "<n>"
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,48 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestClusterLabel_MasksNumbersAndPaths(t *testing.T) {
+	a := model.Verdict{Blocked: true, Agent: "codex", Reason: "exec approval",
+		WaitingFor: "$ npm install --prefix ./web 2>&1"}
+	b := model.Verdict{Blocked: true, Agent: "codex", Reason: "exec approval",
+		WaitingFor: "$ npm   install --prefix ./api 2>&1\nReason: deps"}
+
+	if ClusterLabel(a) != ClusterLabel(b) {
+		t.Errorf("expected same label, got %q and %q", ClusterLabel(a), ClusterLabel(b))
+	}
+	if want := "exec approval — $ npm install --prefix <path> <n>"; ClusterLabel(a) != want {
+		t.Errorf("ClusterLabel: got %q, want %q", ClusterLabel(a), want)
+	}
+}
+
+func TestClusterLabel_OmitsDetailEqualToReason(t *testing.T) {
+	v := model.Verdict{Blocked: true, Agent: "claude_code", Reason: "idle at prompt", WaitingFor: "idle at prompt"}
+	if got := ClusterLabel(v); got != "idle at prompt" {
+		t.Errorf("ClusterLabel: got %q, want %q", got, "idle at prompt")
+	}
+}
+
+func TestBuildClusters_GroupsBlockedOnly(t *testing.T) {
+	verdicts := []model.Verdict{
+		{Target: "a:0.0", Agent: "claude_code", Blocked: true, Reason: "idle at prompt"},
+		{Target: "b:0.0", Agent: "opencode", Blocked: true, Reason: "permission dialog", WaitingFor: "Edit src/a.go"},
+		{Target: "c:0.0", Agent: "claude_code", Blocked: true, Reason: "idle at prompt"},
+		{Target: "d:0.0", Agent: "codex", Blocked: false, Reason: "actively working"},
+		{Target: "e:0.0", Agent: "error", Blocked: true, Reason: "evaluation failed"},
+	}
+	clusters := BuildClusters(verdicts)
+	if len(clusters) != 2 {
+		t.Fatalf("got %d clusters, want 2: %+v", len(clusters), clusters)
+	}
+	if clusters[0].Label != "idle at prompt" || clusters[0].Count != 2 {
+		t.Errorf("largest cluster: got %+v", clusters[0])
+	}
+	if len(clusters[0].Targets) != 2 || clusters[0].Targets[1] != "c:0.0" {
+		t.Errorf("targets: got %v", clusters[0].Targets)
+	}
+}
@@ -0,0 +1,214 @@
+// Package summary aggregates scan verdicts into fleet-level counts for
+// automated consumers (CI pipelines, cron jobs, status bars).
+//
+// The summary is a pure function of the verdicts: it counts what the parsers
+// reported and never re-classifies a pane.
+package summary
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// State constants classify a verdict for aggregation.
+const (
+	StateBlocked = "blocked"
+	StateActive  = "active"
+	StateUnknown = "unknown"
+	StateError   = "error"
+)
+
+// maxOldestBlocked caps the number of entries in Summary.OldestBlocked.
+const maxOldestBlocked = 5
+
+// Summary is the machine-readable fleet health report.
+type Summary struct {
+	// Total is the number of panes scanned.
+	Total int `json:"total"`
+	// ByState counts panes per State* constant.
+	ByState map[string]int `json:"by_state"`
+	// ByAgent counts panes per detected agent name.
+	ByAgent map[string]int `json:"by_agent"`
+	// ByRisk counts blocked panes by the risk of their recommended action.
+	// Blocked panes without actions are counted as "none".
+	ByRisk map[string]int `json:"by_risk"`
+	// OldestBlocked lists the longest-blocked panes, oldest first.
+	OldestBlocked []BlockedPane `json:"oldest_blocked"`
+	// Clusters groups blocked panes waiting on the same thing, largest first.
+	Clusters []Cluster `json:"clusters"`
+	// GeneratedAt is the time the summary was built.
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// BlockedPane describes a blocked pane in Summary.OldestBlocked.
+type BlockedPane struct {
+	Target string `json:"target"`
+	Agent  string `json:"agent"`
+	Reason string `json:"reason"`
+	// BlockedForSeconds is the time since the pane's window last produced
+	// output. Zero when the multiplexer does not report activity.
+	BlockedForSeconds int64 `json:"blocked_for_seconds"`
+}
+
+// StateOf returns the aggregation state for a verdict.
+func StateOf(v model.Verdict) string {
+	switch {
+	case v.Agent == "error":
+		return StateError
+	case parser.IsAgentBlock(v):
+		return StateBlocked
+	case v.Agent == "unknown" || v.Agent == "not_an_agent" || v.Agent == parser.AgentGenericPrompt:
+		// A shell waiting on a password or [y/N] is not an agent.
+		return StateUnknown
+	default:
+		return StateActive
+	}
+}
+
+// Build aggregates verdicts into a Summary. now is used to compute
+// blocked durations.
+func Build(verdicts []model.Verdict, now time.Time) *Summary {
+	s := &Summary{
+		Total: len(verdicts),
+		ByState: map[string]int{
+			StateBlocked: 0,
+			StateActive:  0,
+			StateUnknown: 0,
+			StateError:   0,
+		},
+		ByAgent:       map[string]int{},
+		ByRisk:        map[string]int{},
+		OldestBlocked: []BlockedPane{},
+		GeneratedAt:   now.UTC(),
+	}
+
+	var blocked []BlockedPane
+	for _, v := range verdicts {
+		state := StateOf(v)
+		s.ByState[state]++
+		s.ByAgent[v.Agent]++
+		if state != StateBlocked {
+			continue
+		}
+
+		risk := "none"
+		if v.Recommended >= 0 && v.Recommended < len(v.Actions) && v.Actions[v.Recommended].Risk != "" {
+			risk = v.Actions[v.Recommended].Risk
+		}
+		s.ByRisk[risk]++
+
+		bp := BlockedPane{Target: v.Target, Agent: v.Agent, Reason: v.Reason}
+		if !v.LastActivity.IsZero() && now.After(v.LastActivity) {
+			bp.BlockedForSeconds = int64(now.Sub(v.LastActivity).Seconds())
+		}
+		blocked = append(blocked, bp)
+	}
+
+	sort.SliceStable(blocked, func(i, j int) bool {
+		if blocked[i].BlockedForSeconds == blocked[j].BlockedForSeconds {
+			return blocked[i].Target < blocked[j].Target
+		}
+		return blocked[i].BlockedForSeconds > blocked[j].BlockedForSeconds
+	})
+	if len(blocked) > maxOldestBlocked {
+		blocked = blocked[:maxOldestBlocked]
+	}
+	s.OldestBlocked = append(s.OldestBlocked, blocked...)
+	s.Clusters = BuildClusters(verdicts)
+
+	return s
+}
+
+// Metric returns the value of a named metric used by thresholds.
+// Supported names: "total", the State* constants, "agent:<name>",
+// "risk:<level>", and "oldest_blocked" (seconds).
+func (s *Summary) Metric(name string) (float64, bool) {
+	switch {
+	case name == "total":
+		return float64(s.Total), true
+	case name == "oldest_blocked":
+		if len(s.OldestBlocked) == 0 {
+			return 0, true
+		}
+		return float64(s.OldestBlocked[0].BlockedForSeconds), true
+	case strings.HasPrefix(name, "agent:"):
+		return float64(s.ByAgent[strings.TrimPrefix(name, "agent:")]), true
+	case strings.HasPrefix(name, "risk:"):
+		return float64(s.ByRisk[strings.TrimPrefix(name, "risk:")]), true
+	}
+	if n, ok := s.ByState[name]; ok {
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// Threshold is a parsed "--fail-if" expression such as "blocked>2" or
+// "oldest_blocked>=10m".
+type Threshold struct {
+	Raw    string
+	Metric string
+	Op     string
+	Value  float64
+}
+
+// thresholdOps lists supported comparison operators. Two-character
+// operators come first so "blocked>=2" is not parsed as ">" + "=2".
+var thresholdOps = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// ParseThreshold parses a "<metric><op><value>" expression. For the
+// "oldest_blocked" metric the value may be a Go duration ("10m"); otherwise
+// it must be a number.
+func ParseThreshold(expr string) (Threshold, error) {
+	raw := strings.TrimSpace(expr)
+	for _, op := range thresholdOps {
+		idx := strings.Index(raw, op)
+		if idx <= 0 {
+			continue
+		}
+		metric := strings.TrimSpace(raw[:idx])
+		valueStr := strings.TrimSpace(raw[idx+len(op):])
+		if valueStr == "" {
+			return Threshold{}, fmt.Errorf("invalid threshold %q: missing value", expr)
+		}
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			d, derr := time.ParseDuration(valueStr)
+			if metric != "oldest_blocked" || derr != nil {
+				return Threshold{}, fmt.Errorf("invalid threshold %q: value %q is not a number", expr, valueStr)
+			}
+			value = d.Seconds()
+		}
+		return Threshold{Raw: raw, Metric: metric, Op: op, Value: value}, nil
+	}
+	return Threshold{}, fmt.Errorf("invalid threshold %q (expected e.g. blocked>2)", expr)
+}
+
+// Exceeded reports whether the summary trips the threshold. An unknown
+// metric name is an error so typos do not silently pass a pipeline.
+func (t Threshold) Exceeded(s *Summary) (bool, error) {
+	got, ok := s.Metric(t.Metric)
+	if !ok {
+		return false, fmt.Errorf("unknown metric %q in threshold %q", t.Metric, t.Raw)
+	}
+	switch t.Op {
+	case ">":
+		return got > t.Value, nil
+	case ">=":
+		return got >= t.Value, nil
+	case "<":
+		return got < t.Value, nil
+	case "<=":
+		return got <= t.Value, nil
+	case "==":
+		return got == t.Value, nil
+	case "!=":
+		return got != t.Value, nil
+	}
+	return false, fmt.Errorf("unknown operator %q in threshold %q", t.Op, t.Raw)
+}
@@ -0,0 +1,147 @@
+package summary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func testVerdicts(now time.Time) []model.Verdict {
+	return []model.Verdict{
+		{Target: "a:0.0", Agent: "opencode", Blocked: true, Reason: "permission dialog",
+			LastActivity: now.Add(-10 * time.Minute),
+			Actions:      []model.Action{{Keys: "Enter", Risk: "medium"}}},
+		{Target: "a:0.1", Agent: "claude_code", Blocked: true, Reason: "idle at prompt",
+			LastActivity: now.Add(-2 * time.Minute),
+			Actions:      []model.Action{{Keys: "Enter", Risk: "low"}}},
+		{Target: "b:0.0", Agent: "codex", Blocked: false, Reason: "actively working"},
+		{Target: "c:0.0", Agent: "unknown"},
+		{Target: "d:0.0", Agent: "error", Reason: "evaluation failed"},
+		{Target: "e:0.0", Agent: "shell_prompt", Blocked: true, Reason: "password prompt",
+			LastActivity: now.Add(-time.Hour)},
+	}
+}
+
+func TestBuild_Counts(t *testing.T) {
+	now := time.Now()
+	s := Build(testVerdicts(now), now)
+
+	if s.Total != 6 {
+		t.Errorf("Total: got %d, want 6", s.Total)
+	}
+	// A shell prompt is not an agent waiting, however long it has been.
+	wantStates := map[string]int{StateBlocked: 2, StateActive: 1, StateUnknown: 2, StateError: 1}
+	for state, want := range wantStates {
+		if got := s.ByState[state]; got != want {
+			t.Errorf("ByState[%s]: got %d, want %d", state, got, want)
+		}
+	}
+	if s.ByAgent["opencode"] != 1 || s.ByAgent["codex"] != 1 {
+		t.Errorf("ByAgent: unexpected counts %v", s.ByAgent)
+	}
+	if s.ByRisk["medium"] != 1 || s.ByRisk["low"] != 1 {
+		t.Errorf("ByRisk: unexpected counts %v", s.ByRisk)
+	}
+}
+
+func TestBuild_OldestBlockedSortedDescending(t *testing.T) {
+	now := time.Now()
+	s := Build(testVerdicts(now), now)
+
+	if len(s.OldestBlocked) != 2 {
+		t.Fatalf("OldestBlocked: got %d entries, want 2", len(s.OldestBlocked))
+	}
+	if s.OldestBlocked[0].Target != "a:0.0" {
+		t.Errorf("OldestBlocked[0]: got %q, want %q", s.OldestBlocked[0].Target, "a:0.0")
+	}
+	if s.OldestBlocked[0].BlockedForSeconds != 600 {
+		t.Errorf("BlockedForSeconds: got %d, want 600", s.OldestBlocked[0].BlockedForSeconds)
+	}
+}
+
+func TestBuild_EmptyIsNotNil(t *testing.T) {
+	s := Build(nil, time.Now())
+	if s.OldestBlocked == nil {
+		t.Error("OldestBlocked should be an empty slice, not nil (JSON [] vs null)")
+	}
+	if s.ByState[StateBlocked] != 0 {
+		t.Errorf("ByState[blocked]: got %d, want 0", s.ByState[StateBlocked])
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		expr    string
+		metric  string
+		op      string
+		value   float64
+		wantErr bool
+	}{
+		{"blocked>2", "blocked", ">", 2, false},
+		{"blocked>=2", "blocked", ">=", 2, false},
+		{"error!=0", "error", "!=", 0, false},
+		{"oldest_blocked>10m", "oldest_blocked", ">", 600, false},
+		{"agent:codex<1", "agent:codex", "<", 1, false},
+		{"blocked>many", "", "", 0, true},
+		{"blocked>5m", "", "", 0, true},
+		{"blocked", "", "", 0, true},
+		{">2", "", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseThreshold(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseThreshold(%q): error = %v, wantErr = %v", tt.expr, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Metric != tt.metric || got.Op != tt.op || got.Value != tt.value {
+				t.Errorf("ParseThreshold(%q) = %+v, want metric=%q op=%q value=%v",
+					tt.expr, got, tt.metric, tt.op, tt.value)
+			}
+		})
+	}
+}
+
+func TestThreshold_Exceeded(t *testing.T) {
+	now := time.Now()
+	s := Build(testVerdicts(now), now)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"blocked>1", true},
+		{"blocked>2", false},
+		{"error>0", true},
+		{"oldest_blocked>5m", true},
+		{"oldest_blocked>15m", false},
+		{"risk:high>0", false},
+	}
+	for _, tt := range tests {
+		th, err := ParseThreshold(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseThreshold(%q): %v", tt.expr, err)
+		}
+		got, err := th.Exceeded(s)
+		if err != nil {
+			t.Fatalf("Exceeded(%q): %v", tt.expr, err)
+		}
+		if got != tt.want {
+			t.Errorf("Exceeded(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestThreshold_UnknownMetric(t *testing.T) {
+	th, err := ParseThreshold("blokced>0")
+	if err != nil {
+		t.Fatalf("ParseThreshold: %v", err)
+	}
+	if _, err := th.Exceeded(Build(nil, time.Now())); err == nil {
+		t.Error("expected error for unknown metric")
+	}
+}
@@ -0,0 +1,162 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// customAnswerOption is the question dialog option that opens a text field
+// for a free-form answer (OpenCode's question tool).
+const customAnswerOption = "Type your own answer"
+
+// Answer is how to answer a blocked pane without the TUI (pane-patrol
+// answer): the action selecting an option, the text typed after it, or
+// both for a free-form answer to a question dialog.
+type Answer struct {
+	Action *model.Action
+	Text   string
+	// Reason is what was typed to confirm a high-risk action (see
+	// confirm_high_risk); it is recorded in the history.
+	Reason string
+}
+
+// ResolveAnswer validates an answer against the pane's current verdict.
+// option is 1-based and selects the dialog's option of that number; the
+// parsers build the actions of a dialog in option order, so it is answered
+// by the action at the same position. text is typed into the question's
+// free-form option or notes field when it has one, or into the agent's
+// input otherwise.
+// Exactly one of option (> 0) and text must be given.
+func ResolveAnswer(v model.Verdict, option int, text string) (Answer, error) {
+	if (option > 0) == (text != "") {
+		return Answer{}, fmt.Errorf("give either an option or a text answer")
+	}
+	if !v.Blocked {
+		return Answer{}, fmt.Errorf("%s is not waiting for input (%s)", v.Target, v.Reason)
+	}
+	d := v.Dialog
+
+	if option > 0 {
+		if d == nil || len(d.Options) == 0 {
+			return Answer{}, fmt.Errorf("%s has no dialog with options (%s)", v.Target, v.Reason)
+		}
+		if option > len(d.Options) || option > len(v.Actions) {
+			return Answer{}, fmt.Errorf("%s has no option %d (options: %s)", v.Target, option, formatOptions(d.Options))
+		}
+		return Answer{Action: &v.Actions[option-1]}, nil
+	}
+
+	if d == nil || d.Kind != model.DialogQuestion {
+		if d != nil {
+			return Answer{}, fmt.Errorf("%s is showing a %s dialog, not a question; answer it with an option (options: %s)",
+				v.Target, d.Kind, formatOptions(d.Options))
+		}
+		return Answer{Text: text}, nil
+	}
+	for i, o := range d.Options {
+		if o.Label == customAnswerOption && !d.MultiSelect && i < len(v.Actions) {
+			return Answer{Action: &v.Actions[i], Text: text}, nil
+		}
+	}
+	for i, a := range v.Actions {
+		if a.Text {
+			return Answer{Action: &v.Actions[i], Text: text}, nil
+		}
+	}
+	return Answer{}, fmt.Errorf("%s's question has no free-form option (options: %s)", v.Target, formatOptions(d.Options))
+}
+
+// formatOptions lists options as "1. Yes, 2. No".
+func formatOptions(options []model.DialogOption) string {
+	parts := make([]string, len(options))
+	for i, o := range options {
+		parts[i] = fmt.Sprintf("%d. %s", i+1, o.Label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SendAnswer sends a resolved answer to target. A free-form answer after a
+// selected option is typed into the text field the option opened, without
+// the Escape NudgePane sends first, which would dismiss the dialog.
+func (n *Nudger) SendAnswer(target string, a Answer) error {
+	if a.Action == nil {
+		return n.NudgePane(target, a.Text, false)
+	}
+	if err := n.NudgePane(target, a.Action.Keys, a.Action.Raw); err != nil {
+		return err
+	}
+	if a.Text == "" {
+		return nil
+	}
+
+	sendKeys, paste, sleep := n.SendKeys, n.Paste, n.Sleep
+	if sendKeys == nil {
+		sendKeys = defaultSendKeys
+	}
+	if paste == nil {
+		paste = defaultPaste
+	}
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	// Let the agent open the text field.
+	sleep(300 * time.Millisecond)
+	if strings.Contains(a.Text, "\n") {
+		if err := paste(target, a.Text); err != nil {
+			return fmt.Errorf("paste text: %w", err)
+		}
+	} else if err := sendKeys(target, "-l", a.Text); err != nil {
+		return fmt.Errorf("send literal keys: %w", err)
+	}
+	sleep(500 * time.Millisecond)
+	if err := sendKeys(target, "", "Enter"); err != nil {
+		return fmt.Errorf("send Enter: %w", err)
+	}
+	return nil
+}
+
+// historyEntry describes the answer for the action history.
+func (a Answer) historyEntry(now time.Time, v model.Verdict) HistoryEntry {
+	e := HistoryEntry{Time: now, Target: v.Target, Agent: v.Agent, Reason: a.Reason}
+	switch {
+	case a.Action == nil:
+		e.Action, e.Keys = "typed answer", a.Text
+	case a.Text == "":
+		e.Action, e.Keys, e.Risk = a.Action.Label, a.Action.Keys, a.Action.Risk
+	default:
+		e.Action, e.Keys, e.Risk = a.Action.Label+" (typed answer)", a.Action.Keys+" "+a.Text, a.Action.Risk
+	}
+	return e
+}
+
+// auditRecord describes the answer for the audit log, sent by actor.
+func (a Answer) auditRecord(now time.Time, v model.Verdict, actor string) AuditRecord {
+	sent := model.Action{Keys: a.Text, Label: "text answer"}
+	if a.Action != nil {
+		sent = *a.Action
+		if a.Text != "" {
+			sent.Keys += " " + a.Text
+		}
+	}
+	return AuditRecord{Time: now, Kind: "action", Target: v.Target, Parser: v.Agent, Action: &sent, Actor: actor}
+}
+
+// AnswerPane sends a resolved answer to the verdict's pane and records it in
+// history and the audit log as sent by actor ("popup" or "answer"); nil
+// records nothing.
+func AnswerPane(v model.Verdict, a Answer, history *History, audit *AuditLog, actor string) error {
+	if err := DefaultNudger().SendAnswer(v.Target, a); err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := audit.Record(a.auditRecord(now, v, actor)); err != nil {
+		return fmt.Errorf("answer sent, but not audited: %w", err)
+	}
+	if err := history.Record(a.historyEntry(now, v)); err != nil {
+		return fmt.Errorf("answer sent, but not recorded: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+func questionVerdict() model.Verdict {
+	return model.Verdict{
+		Target:  "dev:0.1",
+		Agent:   "opencode",
+		Blocked: true,
+		Reason:  "question dialog waiting for answer",
+		Dialog: &model.Dialog{
+			Kind:    model.DialogQuestion,
+			Options: []model.DialogOption{{Label: "PostgreSQL"}, {Label: "SQLite"}, {Label: "Type your own answer"}},
+		},
+		Actions: []model.Action{
+			{Keys: "1", Label: "PostgreSQL", Risk: "low", Raw: true},
+			{Keys: "2", Label: "SQLite", Risk: "low", Raw: true},
+			{Keys: "3", Label: "Type your own answer", Risk: "low", Raw: true},
+			{Keys: "Escape", Label: "dismiss", Risk: "low", Raw: true},
+		},
+	}
+}
+
+func TestResolveAnswer_Option(t *testing.T) {
+	a, err := ResolveAnswer(questionVerdict(), 2, "")
+	if err != nil || a.Action == nil || a.Action.Keys != "2" || a.Text != "" {
+		t.Fatalf("got %+v, %v", a, err)
+	}
+
+	_, err = ResolveAnswer(questionVerdict(), 4, "")
+	if err == nil || !strings.Contains(err.Error(), "no option 4 (options: 1. PostgreSQL, 2. SQLite, 3. Type your own answer)") {
+		t.Errorf("expected missing option error, got %v", err)
+	}
+}
+
+func TestResolveAnswer_CodexApprovals(t *testing.T) {
+	for _, tc := range []struct {
+		content string
+		option  int
+		keys    string
+	}{
+		{"  Would you like to run the following command?\n\n  $ git log --oneline -10\n\n" +
+			"› 1. Yes, proceed\n  2. Yes, and don't ask again for commands that start with `git`\n  3. No, and tell Codex what to do differently\n", 2, "Down Enter"},
+		{"  Would you like to make the following edits?\n\n  Yes, proceed\n", 1, "Enter"},
+		{"  Do you want to approve access to \"api.github.com\"?\n", 3, "Down Down Enter"},
+		{"  filesystem-server needs your approval.\n", 2, "Down Enter"},
+	} {
+		r := (&parser.CodexParser{}).Parse(tc.content, []string{"codex"})
+		v := model.Verdict{Target: "dev:0.1", Agent: r.Agent, Blocked: r.Blocked, Reason: r.Reason, Dialog: r.Dialog, Actions: r.Actions}
+		a, err := ResolveAnswer(v, tc.option, "")
+		if err != nil || a.Action.Keys != tc.keys {
+			t.Errorf("%s: option %d: got %+v, %v, want %s", r.Reason, tc.option, a.Action, err, tc.keys)
+		}
+		if _, err := ResolveAnswer(v, 4, ""); err == nil {
+			t.Errorf("%s: Escape is not an option", r.Reason)
+		}
+	}
+}
+
+func TestResolveAnswer_Text(t *testing.T) {
+	a, err := ResolveAnswer(questionVerdict(), 0, "use postgres")
+	if err != nil || a.Action == nil || a.Action.Keys != "3" || a.Text != "use postgres" {
+		t.Fatalf("expected the free-form option then the text, got %+v, %v", a, err)
+	}
+
+	// Without a free-form option the text has nowhere to go.
+	v := questionVerdict()
+	v.Dialog.Options = v.Dialog.Options[:2]
+	if _, err := ResolveAnswer(v, 0, "use postgres"); err == nil {
+		t.Error("expected an error for a question without a free-form option")
+	}
+
+	// A Codex question takes the text as notes on the highlighted option.
+	v.Actions = append(v.Actions[:2], model.Action{Keys: "Tab", Label: "add notes", Risk: "low", Raw: true, Text: true})
+	a, err = ResolveAnswer(v, 0, "with coverage")
+	if err != nil || a.Action == nil || a.Action.Keys != "Tab" || a.Text != "with coverage" {
+		t.Errorf("expected the notes field then the text, got %+v, %v", a, err)
+	}
+
+	// A permission dialog must be answered with an option.
+	if _, err := ResolveAnswer(model.Verdict{Blocked: true, Dialog: &model.Dialog{Kind: model.DialogPermission}}, 0, "yes"); err == nil {
+		t.Error("expected an error for text on a permission dialog")
+	}
+
+	// At the prompt the text goes to the agent's input.
+	a, err = ResolveAnswer(model.Verdict{Target: "dev:0.2", Blocked: true, Reason: "idle at prompt"}, 0, "continue")
+	if err != nil || a.Action != nil || a.Text != "continue" {
+		t.Errorf("got %+v, %v", a, err)
+	}
+}
+
+func TestResolveAnswer_Invalid(t *testing.T) {
+	if _, err := ResolveAnswer(questionVerdict(), 1, "both"); err == nil {
+		t.Error("expected an error for both an option and text")
+	}
+	v := questionVerdict()
+	v.Blocked = false
+	if _, err := ResolveAnswer(v, 1, ""); err == nil {
+		t.Error("expected an error for a pane that is not blocked")
+	}
+}
+
+func TestNudger_SendAnswerTypesIntoOptionField(t *testing.T) {
+	var calls []sendKeysCall
+	nudger := &Nudger{
+		SendKeys: func(paneID, flag, keys string) error {
+			calls = append(calls, sendKeysCall{paneID, flag, keys})
+			return nil
+		},
+		Sleep: func(time.Duration) {},
+	}
+	a, _ := ResolveAnswer(questionVerdict(), 0, "use postgres")
+	if err := nudger.SendAnswer("dev:0.1", a); err != nil {
+		t.Fatal(err)
+	}
+	want := []sendKeysCall{
+		{"dev:0.1", "-l", "3"},
+		{"dev:0.1", "-l", "use postgres"},
+		{"dev:0.1", "", "Enter"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("got %+v, want %+v (no Escape)", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d: got %+v, want %+v", i+1, calls[i], want[i])
+		}
+	}
+}
+
+func TestAnswer_HistoryEntry(t *testing.T) {
+	a, _ := ResolveAnswer(questionVerdict(), 0, "use postgres")
+	e := a.historyEntry(time.Time{}, questionVerdict())
+	if e.Action != "Type your own answer (typed answer)" || e.Keys != "3 use postgres" || e.Agent != "opencode" {
+		t.Errorf("got %+v", e)
+	}
+	r := a.auditRecord(time.Time{}, questionVerdict(), "answer")
+	if r.Kind != "action" || r.Actor != "answer" || r.Action.Keys != "3 use postgres" || r.Parser != "opencode" {
+		t.Errorf("audit record: %+v", r)
+	}
+}
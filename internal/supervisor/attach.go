@@ -0,0 +1,210 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// attachRefresh is how often the attach view captures the pane again.
+const attachRefresh = 300 * time.Millisecond
+
+// attachView is the interactive attach mode (opened with i): keys are
+// forwarded to the pane while a live capture of it is shown, for a quick
+// back-and-forth with one agent without switching the tmux client away.
+// ctrl+] detaches.
+type attachView struct {
+	target string
+	seq    int // tells captures of this attach apart from earlier ones
+	lines  []string
+	err    error
+
+	// Keys are sent one batch at a time so they arrive in typing order;
+	// keys typed while a batch is in flight wait in pending.
+	pending []attachKey
+	sending bool
+}
+
+// attachKey is one key to send: literal text (send-keys -l) or a tmux key
+// name.
+type attachKey struct {
+	keys    string
+	literal bool
+}
+
+// attachCaptureMsg delivers a capture for the attach view.
+type attachCaptureMsg struct {
+	target  string
+	seq     int
+	content string
+	err     error
+}
+
+// attachTickMsg triggers the next capture of the attach view.
+type attachTickMsg struct{ seq int }
+
+// attachSentMsg reports that a batch of keys was sent.
+type attachSentMsg struct {
+	seq int
+	err error
+}
+
+func captureAttachCmd(ctx context.Context, target string, seq int) tea.Cmd {
+	return func() tea.Msg {
+		content, err := mux.NewTmux().CapturePane(ctx, target)
+		return attachCaptureMsg{target: target, seq: seq, content: content, err: err}
+	}
+}
+
+// tmuxKey maps a key press to what send-keys needs to reproduce it in the
+// pane. ok is false for keys with no tmux equivalent.
+func tmuxKey(msg tea.KeyMsg) (k attachKey, ok bool) {
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt && len(msg.Runes) == 1 {
+			return attachKey{keys: "M-" + string(msg.Runes)}, true
+		}
+		return attachKey{keys: string(msg.Runes), literal: true}, true
+	case tea.KeySpace:
+		return attachKey{keys: " ", literal: true}, true
+	}
+	names := map[tea.KeyType]string{
+		tea.KeyEnter: "Enter", tea.KeyEsc: "Escape", tea.KeyBackspace: "BSpace",
+		tea.KeyTab: "Tab", tea.KeyShiftTab: "BTab", tea.KeyDelete: "DC",
+		tea.KeyUp: "Up", tea.KeyDown: "Down", tea.KeyLeft: "Left", tea.KeyRight: "Right",
+		tea.KeyHome: "Home", tea.KeyEnd: "End", tea.KeyPgUp: "PPage", tea.KeyPgDown: "NPage",
+	}
+	if name, ok := names[msg.Type]; ok {
+		return attachKey{keys: name}, true
+	}
+	if rest, ok := strings.CutPrefix(msg.String(), "ctrl+"); ok && len(rest) == 1 {
+		return attachKey{keys: "C-" + rest}, true
+	}
+	return attachKey{}, false
+}
+
+// handleAttachKey forwards keys to the attached pane; ctrl+] detaches.
+func (m *tuiModel) handleAttachKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a := m.attach
+	if msg.Type == tea.KeyCtrlCloseBracket {
+		m.attach = nil
+		m.message = fmt.Sprintf("Detached from %s", a.target)
+		// The conversation most likely changed the pane's state.
+		m.invalidateCache(a.target)
+		if m.scanning {
+			return m, nil
+		}
+		m.scanning = true
+		return m, m.doScan()
+	}
+	k, ok := tmuxKey(msg)
+	if !ok {
+		return m, nil
+	}
+	// Merge typed text so a burst of characters is one send-keys call.
+	if n := len(a.pending); k.literal && n > 0 && a.pending[n-1].literal {
+		a.pending[n-1].keys += k.keys
+	} else {
+		a.pending = append(a.pending, k)
+	}
+	return m, m.flushAttachKeys()
+}
+
+// flushAttachKeys sends the pending keys unless a batch is in flight.
+func (m *tuiModel) flushAttachKeys() tea.Cmd {
+	a := m.attach
+	if a == nil || a.sending || len(a.pending) == 0 {
+		return nil
+	}
+	keys, target, seq := a.pending, a.target, a.seq
+	a.pending, a.sending = nil, true
+	sendKeys := m.nudger.sendKeysFunc()
+	return func() tea.Msg {
+		for _, k := range keys {
+			flag := ""
+			if k.literal {
+				flag = "-l"
+			}
+			if err := sendKeys(target, flag, k.keys); err != nil {
+				return attachSentMsg{seq: seq, err: err}
+			}
+		}
+		return attachSentMsg{seq: seq}
+	}
+}
+
+// updateAttach handles the attach view's messages. Messages of an earlier
+// attach are dropped, which also stops its capture loop.
+func (m *tuiModel) updateAttach(msg tea.Msg) tea.Cmd {
+	a := m.attach
+	switch msg := msg.(type) {
+	case attachCaptureMsg:
+		if a == nil || a.seq != msg.seq {
+			return nil
+		}
+		a.err = msg.err
+		if msg.err == nil {
+			a.lines = strings.Split(strings.TrimRight(msg.content, "\n"), "\n")
+		}
+		seq := a.seq
+		return tea.Tick(attachRefresh, func(time.Time) tea.Msg { return attachTickMsg{seq: seq} })
+	case attachTickMsg:
+		if a == nil || a.seq != msg.seq {
+			return nil
+		}
+		return captureAttachCmd(m.ctx, a.target, a.seq)
+	case attachSentMsg:
+		if a == nil || a.seq != msg.seq {
+			return nil
+		}
+		a.sending = false
+		if msg.err != nil {
+			m.message = fmt.Sprintf("send to %s failed: %v", a.target, msg.err)
+		}
+		// The capture loop shows the echo within attachRefresh.
+		return m.flushAttachKeys()
+	}
+	return nil
+}
+
+// viewAttach renders the bottom of the live capture, as much as fits.
+func (m *tuiModel) viewAttach() string {
+	a := m.attach
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Attached"))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(a.target))
+	b.WriteString("\n")
+
+	rows := max(m.height-3, 1)
+	switch {
+	case a.err != nil:
+		b.WriteString(m.s.err.Render(fmt.Sprintf("  capture failed: %v", a.err)))
+		b.WriteString("\n")
+		rows--
+	case a.lines == nil:
+		b.WriteString(m.s.dim.Render("  capturing..."))
+		b.WriteString("\n")
+		rows--
+	}
+	lines := a.lines[max(len(a.lines)-rows, 0):]
+	for _, line := range lines {
+		b.WriteString(truncate(line, m.width-1))
+		b.WriteString("\n")
+	}
+	for i := len(lines); i < rows; i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.styleHints("  keys go to the pane  ctrl+] detach"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + truncate(m.message, m.width-3)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTmuxKey(t *testing.T) {
+	tests := []struct {
+		msg     tea.KeyMsg
+		keys    string
+		literal bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("yes")}, "yes", true},
+		{tea.KeyMsg{Type: tea.KeySpace}, " ", true},
+		{tea.KeyMsg{Type: tea.KeyEnter}, "Enter", false},
+		{tea.KeyMsg{Type: tea.KeyEsc}, "Escape", false},
+		{tea.KeyMsg{Type: tea.KeyBackspace}, "BSpace", false},
+		{tea.KeyMsg{Type: tea.KeyShiftTab}, "BTab", false},
+		{tea.KeyMsg{Type: tea.KeyPgUp}, "PPage", false},
+		{tea.KeyMsg{Type: tea.KeyCtrlC}, "C-c", false},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b"), Alt: true}, "M-b", false},
+	}
+	for _, tt := range tests {
+		k, ok := tmuxKey(tt.msg)
+		if !ok || k.keys != tt.keys || k.literal != tt.literal {
+			t.Errorf("%s: got %+v (ok=%v), want %q literal=%v", tt.msg, k, ok, tt.keys, tt.literal)
+		}
+	}
+}
+
+func TestAttach_SendsKeysInOrder(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if m.attach == nil || cmd == nil {
+		t.Fatal("expected i to attach and start capturing")
+	}
+	a := m.attach
+
+	// The first key is sent right away; later keys wait for it.
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil || !a.sending {
+		t.Fatal("expected the first key to be sent")
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("it")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(a.pending) != 2 || a.pending[0] != (attachKey{"uit", true}) || a.pending[1] != (attachKey{"Enter", false}) {
+		t.Fatalf("pending: got %+v", a.pending)
+	}
+
+	// A stale confirmation from an earlier attach changes nothing.
+	m.Update(attachSentMsg{seq: a.seq - 1})
+	if !a.sending || len(a.pending) != 2 {
+		t.Error("expected a stale send result to be ignored")
+	}
+	if _, cmd := m.Update(attachSentMsg{seq: a.seq}); cmd == nil || !a.sending || a.pending != nil {
+		t.Errorf("expected the pending keys to be sent next, got %+v", a)
+	}
+}
+
+func TestAttach_ViewAndDetach(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m.Update(attachCaptureMsg{target: "test:0.0", seq: m.attachSeq, content: "> what next?\n"})
+	view := m.View()
+	if !strings.Contains(view, "Attached") || !strings.Contains(view, "> what next?") {
+		t.Errorf("expected the live capture:\n%s", view)
+	}
+
+	// q is forwarded, not quitting; ctrl+] detaches and rescans.
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if m.attach == nil {
+		t.Fatal("expected q to be forwarded to the pane")
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	if m.attach != nil || cmd == nil || !m.scanning {
+		t.Error("expected ctrl+] to detach and rescan")
+	}
+
+	// Captures of the closed attach stop its refresh loop.
+	if _, cmd := m.Update(attachCaptureMsg{seq: m.attachSeq}); cmd != nil {
+		t.Error("expected no further capture after detaching")
+	}
+}
@@ -0,0 +1,324 @@
+package supervisor
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// AuditLog is a tamper-evident, append-only JSON Lines record of the
+// supervisor's decisions (audit_file): the verdict of every agent pane in
+// every scan, with the hash of the capture it was made from, and every
+// action sent, with who sent it. Each record holds the hash of the record
+// before it and its own, an HMAC-SHA256 keyed by the audit key, so editing,
+// removing, or reordering records breaks the chain and only the key's
+// holder can seal a new one (see VerifyAudit). The number and hash of the
+// last record are kept in a head file next to the log (path + ".head"), so
+// records removed from the end are noticed too, unless the head file is
+// rolled back with them. A nil *AuditLog records nothing; Record is safe
+// for concurrent use, also by other processes appending to the same log
+// (several supervisors, popup, answer).
+type AuditLog struct {
+	path string
+	key  []byte
+}
+
+// AuditRecord is one record of the audit log: a "scan", the "verdict" of
+// one pane in it, or an "action" sent to a pane.
+type AuditRecord struct {
+	Seq    int       `json:"seq"`
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Target string    `json:"target,omitempty"`
+
+	// Scan records: the number of panes evaluated.
+	Panes int `json:"panes,omitempty"`
+
+	// Verdict records: what was evaluated, by which parser (the agent it
+	// recognized) and how (parser, cache, event), and the decision.
+	ContentHash string         `json:"content_hash,omitempty"`
+	Parser      string         `json:"parser,omitempty"`
+	Source      string         `json:"source,omitempty"`
+	Confidence  float64        `json:"confidence,omitempty"`
+	Blocked     bool           `json:"blocked,omitempty"`
+	Reason      string         `json:"reason,omitempty"`
+	Actions     []model.Action `json:"actions,omitempty"`
+	Recommended *int           `json:"recommended,omitempty"`
+
+	// Action records: what was sent and by whom: "user", "auto"
+	// (auto-nudge), "remembered" (an answer remembered with R), "popup"
+	// (pane-patrol popup), or "answer" (pane-patrol answer).
+	Action *model.Action `json:"action,omitempty"`
+	Actor  string        `json:"actor,omitempty"`
+
+	Prev string `json:"prev"`
+	Hash string `json:"hash"`
+}
+
+// auditHead is the contents of the head file: the last record written.
+type auditHead struct {
+	Seq  int    `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// seal sets r's hash: the HMAC-SHA256 under key of r encoded without it,
+// which includes the previous record's hash.
+func (r *AuditRecord) seal(key []byte) error {
+	r.Hash = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	r.Hash = hex.EncodeToString(mac.Sum(nil))
+	return nil
+}
+
+// OpenAuditLog returns the audit log at path (a leading "~" is expanded),
+// signed with key, continuing the chain of its existing records. A log
+// that does not verify (VerifyAudit) is an error rather than continued, so
+// the break stays visible. The file is created on the first Record.
+func OpenAuditLog(path string, key []byte) (*AuditLog, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("audit: no key; set PANE_PATROL_AUDIT_KEY or audit_key_command")
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	if _, _, err := VerifyAudit(path, key); err != nil {
+		return nil, fmt.Errorf("audit %s: %w", path, err)
+	}
+	return &AuditLog{path: path, key: key}, nil
+}
+
+// Record appends records to the log, chaining and sealing each one, then
+// moves the head file to the last of them. It holds an exclusive lock on
+// the log throughout and continues the chain from its last record, so
+// processes sharing the log do not interleave their chains. The log can
+// contain pane commands, so it is only readable by the owner.
+func (a *AuditLog) Record(records ...AuditRecord) error {
+	if a == nil || len(records) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o755); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	defer f.Close() // also releases the lock
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("audit: lock: %w", err)
+	}
+	seq, last, err := auditTail(f)
+	if err != nil {
+		return fmt.Errorf("audit %s: %w", a.path, err)
+	}
+	var buf []byte
+	for _, r := range records {
+		seq++
+		r.Seq, r.Prev = seq, last
+		if err := r.seal(a.key); err != nil {
+			return fmt.Errorf("audit: %w", err)
+		}
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("audit: %w", err)
+		}
+		buf = append(append(buf, data...), '\n')
+		last = r.Hash
+	}
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	if err := writeAuditHead(a.path, auditHead{Seq: seq, Hash: last}); err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	return nil
+}
+
+// auditTail returns the number and hash of the last record in the log f,
+// reading back from its end, or 0 and "" when it is empty.
+func auditTail(f *os.File) (int, string, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, "", err
+	}
+	size := info.Size()
+	if size == 0 {
+		return 0, "", nil
+	}
+	for chunk := int64(4096); ; chunk *= 2 {
+		off := max(size-chunk, 0)
+		buf := make([]byte, size-off)
+		if _, err := f.ReadAt(buf, off); err != nil {
+			return 0, "", err
+		}
+		buf = bytes.TrimSuffix(buf, []byte("\n"))
+		i := bytes.LastIndexByte(buf, '\n')
+		if i < 0 && off > 0 {
+			continue
+		}
+		var rec AuditRecord
+		if err := json.Unmarshal(buf[i+1:], &rec); err != nil {
+			return 0, "", fmt.Errorf("last record: %w", err)
+		}
+		return rec.Seq, rec.Hash, nil
+	}
+}
+
+// writeAuditHead replaces the head file of the log at path.
+func writeAuditHead(path string, head auditHead) error {
+	data, err := json.Marshal(head)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".head.tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path+".head")
+}
+
+// VerifyAudit checks the audit log at path against key and its head file,
+// and returns the number of records and the hash of the last one. The
+// error names the first record that was altered, removed, or moved, or
+// says that records are missing from the end. A log that does not exist
+// yet has no records.
+func VerifyAudit(path string, key []byte) (n int, last string, err error) {
+	var head *auditHead
+	switch data, err := os.ReadFile(path + ".head"); {
+	case err == nil:
+		head = &auditHead{}
+		if err := json.Unmarshal(data, head); err != nil {
+			return 0, "", fmt.Errorf("head file: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return 0, "", err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		if head != nil {
+			return 0, "", fmt.Errorf("log removed (head file names record %d)", head.Seq)
+		}
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	last, n, err = verifyAudit(f, key, head)
+	return n, last, err
+}
+
+// verifyAudit checks the chain read from r. The head, when known, must be
+// one of its records: a later one means records were removed from the
+// end. Records after the head are accepted, as a crash between appending
+// and moving the head leaves them.
+func verifyAudit(r io.Reader, key []byte, head *auditHead) (last string, n int, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := n + 1
+		var rec AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return "", n, fmt.Errorf("line %d: %w", line, err)
+		}
+		if rec.Seq != line {
+			return "", n, fmt.Errorf("line %d: record %d is out of sequence", line, rec.Seq)
+		}
+		if rec.Prev != last {
+			return "", n, fmt.Errorf("line %d: chain broken (previous record removed or altered)", line)
+		}
+		want := rec.Hash
+		if err := rec.seal(key); err != nil {
+			return "", n, err
+		}
+		if !hmac.Equal([]byte(rec.Hash), []byte(want)) {
+			return "", n, fmt.Errorf("line %d: record altered or wrong audit key (hash mismatch)", line)
+		}
+		if head != nil && head.Seq == line && head.Hash != want {
+			return "", n, fmt.Errorf("line %d: record differs from the head file", line)
+		}
+		last, n = want, line
+	}
+	if err := scanner.Err(); err != nil {
+		return "", n, err
+	}
+	if head != nil && head.Seq > n {
+		return "", n, fmt.Errorf("records %d to %d removed from the end (head file)", n+1, head.Seq)
+	}
+	if head == nil && n > 0 {
+		return "", n, fmt.Errorf("head file missing")
+	}
+	return last, n, nil
+}
+
+// scanAuditRecords describes a scan for the audit log: one scan record and
+// a verdict record per agent pane.
+func scanAuditRecords(verdicts []model.Verdict, now time.Time) []AuditRecord {
+	records := []AuditRecord{{Time: now, Kind: "scan", Panes: len(verdicts)}}
+	for _, v := range verdicts {
+		if !tracked(v) {
+			continue
+		}
+		rec := AuditRecord{Time: now, Kind: "verdict", Target: v.Target, ContentHash: v.ContentHash,
+			Parser: v.Agent, Source: v.EvalSource, Confidence: v.Confidence, Blocked: v.Blocked, Reason: v.Reason}
+		if v.Blocked {
+			recommended := v.Recommended
+			rec.Actions, rec.Recommended = v.Actions, &recommended
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+// actionAuditRecords describes the actions sent to panes for the audit
+// log. auto is true when auto-nudge sent them.
+func actionAuditRecords(tasks []nudgeTask, auto bool, now time.Time) []AuditRecord {
+	records := make([]AuditRecord, 0, len(tasks))
+	for _, t := range tasks {
+		actor := "user"
+		switch {
+		case t.remembered:
+			actor = "remembered"
+		case auto:
+			actor = "auto"
+		}
+		records = append(records, AuditRecord{Time: now, Kind: "action", Target: t.target, Parser: t.agent,
+			Action: &model.Action{Keys: t.keys, Label: t.label, Risk: t.risk, Raw: t.raw}, Actor: actor})
+	}
+	return records
+}
+
+// auditCmd returns a tea.Cmd that appends records to the audit log in the
+// background.
+func (m *tuiModel) auditCmd(records []AuditRecord) tea.Cmd {
+	if m.audit == nil || len(records) == 0 {
+		return nil
+	}
+	audit := m.audit
+	return func() tea.Msg {
+		if err := audit.Record(records...); err != nil {
+			return nudgeResultMsg{messages: []string{err.Error()}}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,145 @@
+package supervisor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestAuditLog_ChainsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	v := simpleVerdict()
+	v.ContentHash = hashContent("Allow? (y/n)")
+
+	key := []byte("audit secret")
+	a, err := OpenAuditLog(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Record(scanAuditRecords([]model.Verdict{v}, now)...); err != nil {
+		t.Fatal(err)
+	}
+	a, err = OpenAuditLog(path, key)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	tasks := []nudgeTask{{target: v.Target, agent: v.Agent, keys: "y", label: "allow", risk: "low"}}
+	if err := a.Record(actionAuditRecords(tasks, true, now)...); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _, err := VerifyAudit(path, key); err != nil || n != 3 {
+		t.Fatalf("VerifyAudit: %d records, %v", n, err)
+	}
+	if _, _, err := VerifyAudit(path, []byte("another secret")); err == nil || !strings.Contains(err.Error(), "wrong audit key") {
+		t.Errorf("another key should not verify, got %v", err)
+	}
+	for _, want := range []string{`"kind":"scan"`, `"content_hash":"` + v.ContentHash, `"parser":"opencode"`, `"actor":"auto"`, `"seq":3`} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("audit log lacks %s:\n%s", want, data)
+		}
+	}
+	for _, p := range []string{path, path + ".head"} {
+		if info, err := os.Stat(p); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("%s should be private, got %v", p, err)
+		}
+	}
+	if _, err := OpenAuditLog(path, nil); err == nil {
+		t.Error("an audit log without a key should not open")
+	}
+}
+
+func TestVerifyAudit_DetectsTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	key := []byte("audit secret")
+	a, _ := OpenAuditLog(path, key)
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []nudgeTask{{target: "a:0.0", keys: "y"}, {target: "b:0.0", keys: "n"}, {target: "c:0.0", keys: "y"}}
+	if err := a.Record(actionAuditRecords(tasks, false, now)...); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	head, _ := os.ReadFile(path + ".head")
+	lines := strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	// Without the key, a rewritten chain has the right shape but the wrong
+	// hashes.
+	forged := filepath.Join(t.TempDir(), "audit.jsonl")
+	f, _ := OpenAuditLog(forged, []byte("forger's secret"))
+	f.Record(actionAuditRecords(tasks, false, now)...)
+	forgedData, _ := os.ReadFile(forged)
+	forgedHead, _ := os.ReadFile(forged + ".head")
+
+	for name, tc := range map[string]struct {
+		log, head string
+		want      string
+	}{
+		"edited":    {strings.Replace(string(data), `"keys":"n"`, `"keys":"y"`, 1), string(head), "line 2: record altered"},
+		"removed":   {lines[0] + lines[2], string(head), "line 2: record 3 is out of sequence"},
+		"reordered": {lines[1] + lines[0] + lines[2], string(head), "line 1: record 2 is out of sequence"},
+		"truncated": {lines[0] + lines[1], string(head), "records 3 to 3 removed from the end"},
+		"rewritten": {string(forgedData), string(forgedHead), "line 1: record altered or wrong audit key"},
+		"no head":   {string(data), "", "head file missing"},
+	} {
+		os.WriteFile(path, []byte(tc.log), 0o600)
+		os.Remove(path + ".head")
+		if tc.head != "" {
+			os.WriteFile(path+".head", []byte(tc.head), 0o600)
+		}
+		if _, _, err := VerifyAudit(path, key); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got %v, want %q", name, err, tc.want)
+		}
+		if _, err := OpenAuditLog(path, key); err == nil {
+			t.Errorf("%s: a broken chain should not be continued", name)
+		}
+	}
+}
+
+func TestAuditLog_SharedBetweenProcesses(t *testing.T) {
+	// Two supervisors (or a supervisor and pane-patrol answer) opened the
+	// log before either wrote to it; each continues the other's chain.
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	key := []byte("audit secret")
+	a, _ := OpenAuditLog(path, key)
+	b, _ := OpenAuditLog(path, key)
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i, log := range []*AuditLog{a, b, a, b} {
+		// One record longer than the chunk the tail is read back in.
+		tasks := []nudgeTask{{target: fmt.Sprintf("p:0.%d", i), keys: "y", label: strings.Repeat("x", i*3000)}}
+		if err := log.Record(actionAuditRecords(tasks, false, now)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, _, err := VerifyAudit(path, key); err != nil || n != 4 {
+		t.Errorf("VerifyAudit: %d records, %v", n, err)
+	}
+}
+
+func TestAuditRecords(t *testing.T) {
+	now := time.Now()
+	blocked := simpleVerdict()
+	blocked.Actions = []model.Action{{Keys: "y", Label: "allow"}}
+	blocked.Recommended = 0
+	idle := model.Verdict{Target: "idle:0.0", Agent: "not_an_agent"}
+	records := scanAuditRecords([]model.Verdict{blocked, idle}, now)
+	if len(records) != 2 || records[0].Panes != 2 || records[1].Target != "test:0.0" ||
+		len(records[1].Actions) != 1 || records[1].Recommended == nil {
+		t.Errorf("scan records: %+v", records)
+	}
+
+	actors := actionAuditRecords([]nudgeTask{{target: "a"}, {target: "b", remembered: true}}, false, now)
+	if actors[0].Actor != "user" || actors[1].Actor != "remembered" {
+		t.Errorf("actors: %s %s", actors[0].Actor, actors[1].Actor)
+	}
+}
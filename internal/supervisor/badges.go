@@ -0,0 +1,200 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// Badge modes (tmux_badges config values).
+const (
+	BadgeFlag   = "flag"   // set the @pane-patrol-blocked window option
+	BadgeRename = "rename" // prefix the window name with badgePrefix
+	BadgeBorder = "border" // color the pane's borders with badgeBorderStyle
+)
+
+// BadgeModes lists the valid badge modes.
+var BadgeModes = []string{BadgeFlag, BadgeRename, BadgeBorder}
+
+const (
+	// badgeOption is the window option set by the flag mode, for use in
+	// window-status-format, e.g. #{?@pane-patrol-blocked,⚠ ,}.
+	badgeOption = "@pane-patrol-blocked"
+	// badgePrefix is prepended to window names by the rename mode.
+	badgePrefix = "⚠ "
+	// badgeBorderStyle is the pane border style set by the border mode.
+	badgeBorderStyle = "fg=red"
+)
+
+// badgeTmux is the part of mux.Tmux used by Badges, replaced in tests.
+type badgeTmux interface {
+	WindowName(ctx context.Context, target string) (string, bool, error)
+	RenameWindow(ctx context.Context, target, name string) error
+	SetOption(ctx context.Context, scope, target, name, value string) error
+	UnsetOption(ctx context.Context, scope, target, name string) error
+}
+
+// windowBadge remembers how a window looked before it was renamed.
+type windowBadge struct {
+	name       string
+	autoRename bool
+}
+
+// Badges marks blocked panes in tmux itself, so a blocked agent is visible
+// from any tmux window and not only in the supervisor. Each mode is applied
+// when a pane becomes blocked and undone when it unblocks; flag and rename
+// mark the pane's window, border the pane. Like speech, only agent blocks
+// are marked, not errors or generic prompts.
+type Badges struct {
+	modes map[string]bool
+	tmux  badgeTmux
+
+	mu      sync.Mutex
+	panes   map[string]bool        // badged pane targets
+	windows map[string]windowBadge // badged windows (session:window)
+	closed  bool
+}
+
+// NewBadges returns Badges applying modes (see BadgeModes) through tmux.
+func NewBadges(modes []string, tmux badgeTmux) (*Badges, error) {
+	b := &Badges{modes: map[string]bool{}, tmux: tmux, panes: map[string]bool{}, windows: map[string]windowBadge{}}
+	for _, mode := range modes {
+		switch mode {
+		case BadgeFlag, BadgeRename, BadgeBorder:
+			b.modes[mode] = true
+		default:
+			return nil, fmt.Errorf("unknown tmux badge %q (must be %s)", mode, strings.Join(BadgeModes, ", "))
+		}
+	}
+	return b, nil
+}
+
+// Update badges the panes blocked in verdicts and removes the badges of
+// panes (and windows) that are no longer blocked. Errors, e.g. for panes
+// that were closed, are joined; the remaining panes are still updated.
+func (b *Badges) Update(ctx context.Context, verdicts []model.Verdict) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	return b.update(ctx, verdicts)
+}
+
+// Clear removes all badges and stops further updates. The supervisor calls
+// it on exit.
+func (b *Badges) Clear(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return b.update(ctx, nil)
+}
+
+func (b *Badges) update(ctx context.Context, verdicts []model.Verdict) error {
+	panes := map[string]bool{}
+	windows := map[string]bool{}
+	for _, v := range verdicts {
+		if announceable(v) {
+			panes[v.Target] = true
+			windows[fmt.Sprintf("%s:%d", v.Session, v.Window)] = true
+		}
+	}
+
+	var errs []error
+	for _, target := range sortedKeys(panes) {
+		if !b.panes[target] {
+			errs = append(errs, b.addPane(ctx, target))
+		}
+	}
+	for _, target := range sortedKeys(b.panes) {
+		if !panes[target] {
+			errs = append(errs, b.removePane(ctx, target))
+		}
+	}
+	for _, window := range sortedKeys(windows) {
+		if _, ok := b.windows[window]; !ok {
+			errs = append(errs, b.addWindow(ctx, window))
+		}
+	}
+	for _, window := range sortedKeys(b.windows) {
+		if !windows[window] {
+			errs = append(errs, b.removeWindow(ctx, window))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Badges) addPane(ctx context.Context, target string) error {
+	b.panes[target] = true
+	if !b.modes[BadgeBorder] {
+		return nil
+	}
+	return errors.Join(
+		b.tmux.SetOption(ctx, "-p", target, "pane-border-style", badgeBorderStyle),
+		b.tmux.SetOption(ctx, "-p", target, "pane-active-border-style", badgeBorderStyle))
+}
+
+func (b *Badges) removePane(ctx context.Context, target string) error {
+	delete(b.panes, target)
+	if !b.modes[BadgeBorder] {
+		return nil
+	}
+	return errors.Join(
+		b.tmux.UnsetOption(ctx, "-p", target, "pane-border-style"),
+		b.tmux.UnsetOption(ctx, "-p", target, "pane-active-border-style"))
+}
+
+func (b *Badges) addWindow(ctx context.Context, window string) error {
+	var errs []error
+	var badge windowBadge
+	if b.modes[BadgeFlag] {
+		errs = append(errs, b.tmux.SetOption(ctx, "-w", window, badgeOption, "1"))
+	}
+	if b.modes[BadgeRename] {
+		name, autoRename, err := b.tmux.WindowName(ctx, window)
+		if err == nil && !strings.HasPrefix(name, badgePrefix) {
+			badge = windowBadge{name: name, autoRename: autoRename}
+			err = b.tmux.RenameWindow(ctx, window, badgePrefix+name)
+		}
+		errs = append(errs, err)
+	}
+	b.windows[window] = badge
+	return errors.Join(errs...)
+}
+
+// removeWindow undoes addWindow. A window renamed since it was badged keeps
+// its new name.
+func (b *Badges) removeWindow(ctx context.Context, window string) error {
+	badge := b.windows[window]
+	delete(b.windows, window)
+	var errs []error
+	if b.modes[BadgeFlag] {
+		errs = append(errs, b.tmux.UnsetOption(ctx, "-w", window, badgeOption))
+	}
+	if b.modes[BadgeRename] && badge.name != "" {
+		name, _, err := b.tmux.WindowName(ctx, window)
+		if err == nil && name == badgePrefix+badge.name {
+			err = b.tmux.RenameWindow(ctx, window, badge.name)
+			if err == nil && badge.autoRename {
+				// rename-window turned automatic-rename off for the window.
+				err = b.tmux.UnsetOption(ctx, "-w", window, "automatic-rename")
+			}
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
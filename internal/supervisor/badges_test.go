@@ -0,0 +1,139 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// fakeBadgeTmux records badge commands and keeps window names.
+type fakeBadgeTmux struct {
+	calls []string
+	names map[string]string
+}
+
+func (f *fakeBadgeTmux) WindowName(_ context.Context, target string) (string, bool, error) {
+	name, ok := f.names[target]
+	if !ok {
+		return "", false, fmt.Errorf("can't find window: %s", target)
+	}
+	return name, true, nil
+}
+
+func (f *fakeBadgeTmux) RenameWindow(_ context.Context, target, name string) error {
+	f.calls = append(f.calls, fmt.Sprintf("rename %s %s", target, name))
+	f.names[target] = name
+	return nil
+}
+
+func (f *fakeBadgeTmux) SetOption(_ context.Context, scope, target, name, value string) error {
+	f.calls = append(f.calls, fmt.Sprintf("set %s %s %s %s", scope, target, name, value))
+	return nil
+}
+
+func (f *fakeBadgeTmux) UnsetOption(_ context.Context, scope, target, name string) error {
+	f.calls = append(f.calls, fmt.Sprintf("unset %s %s %s", scope, target, name))
+	return nil
+}
+
+func (f *fakeBadgeTmux) take() string {
+	calls := strings.Join(f.calls, "\n")
+	f.calls = nil
+	return calls
+}
+
+func badgeVerdict(target string, window int, blocked bool) model.Verdict {
+	return model.Verdict{Target: target, Session: "dev", Window: window, Agent: "claude_code", Blocked: blocked, Reason: "permission"}
+}
+
+func TestBadges_MarkAndClear(t *testing.T) {
+	tmux := &fakeBadgeTmux{names: map[string]string{"dev:0": "api", "dev:1": "web"}}
+	b, err := NewBadges([]string{BadgeFlag, BadgeRename, BadgeBorder}, tmux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	verdicts := []model.Verdict{badgeVerdict("dev:0.0", 0, true), badgeVerdict("dev:0.1", 0, true), badgeVerdict("dev:1.0", 1, false)}
+	if err := b.Update(ctx, verdicts); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"set -p dev:0.0 pane-border-style fg=red",
+		"set -p dev:0.0 pane-active-border-style fg=red",
+		"set -p dev:0.1 pane-border-style fg=red",
+		"set -p dev:0.1 pane-active-border-style fg=red",
+		"set -w dev:0 @pane-patrol-blocked 1",
+		"rename dev:0 ⚠ api",
+	}, "\n")
+	if got := tmux.take(); got != want {
+		t.Errorf("first update:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Unchanged verdicts don't touch tmux again.
+	if err := b.Update(ctx, verdicts); err != nil || tmux.take() != "" {
+		t.Errorf("expected no commands for an unchanged scan, err=%v", err)
+	}
+
+	// The window stays badged while one of its panes is blocked.
+	verdicts[0].Blocked = false
+	_ = b.Update(ctx, verdicts)
+	want = "unset -p dev:0.0 pane-border-style\nunset -p dev:0.0 pane-active-border-style"
+	if got := tmux.take(); got != want {
+		t.Errorf("partial unblock:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := b.Clear(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want = strings.Join([]string{
+		"unset -p dev:0.1 pane-border-style",
+		"unset -p dev:0.1 pane-active-border-style",
+		"unset -w dev:0 @pane-patrol-blocked",
+		"rename dev:0 api",
+		"unset -w dev:0 automatic-rename",
+	}, "\n")
+	if got := tmux.take(); got != want {
+		t.Errorf("clear:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Scans finishing after Clear don't badge panes again.
+	_ = b.Update(ctx, verdicts)
+	if got := tmux.take(); got != "" {
+		t.Errorf("expected no commands after Clear, got:\n%s", got)
+	}
+}
+
+func TestBadges_KeepsUserRename(t *testing.T) {
+	tmux := &fakeBadgeTmux{names: map[string]string{"dev:0": "api"}}
+	b, _ := NewBadges([]string{BadgeRename}, tmux)
+	ctx := context.Background()
+	_ = b.Update(ctx, []model.Verdict{badgeVerdict("dev:0.0", 0, true)})
+	tmux.names["dev:0"] = "renamed by hand"
+	tmux.take()
+
+	_ = b.Update(ctx, nil)
+	if got := tmux.take(); got != "" {
+		t.Errorf("expected the user's window name to be kept, got:\n%s", got)
+	}
+}
+
+func TestBadges_SkipsErrorsAndPrompts(t *testing.T) {
+	tmux := &fakeBadgeTmux{names: map[string]string{}}
+	b, _ := NewBadges([]string{BadgeBorder}, tmux)
+	errVerdict := badgeVerdict("dev:0.0", 0, true)
+	errVerdict.Agent = "error"
+	_ = b.Update(context.Background(), []model.Verdict{errVerdict})
+	if got := tmux.take(); got != "" {
+		t.Errorf("expected errors not to be badged, got:\n%s", got)
+	}
+}
+
+func TestNewBadges_UnknownMode(t *testing.T) {
+	if _, err := NewBadges([]string{"blink"}, &fakeBadgeTmux{}); err == nil {
+		t.Error("expected an error for an unknown badge mode")
+	}
+}
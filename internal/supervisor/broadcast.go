@@ -0,0 +1,114 @@
+package supervisor
+
+import (
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/summary"
+)
+
+// answerGroup is a set of blocked panes showing the same dialog, which can
+// be answered with one action. Membership is exact: panes must show the
+// same WaitingFor text and dialog command, unmasked, and offer identical
+// actions with identical risks, so an action index means the same
+// keystrokes in every member. label (see summary.ClusterLabel) is only
+// shown.
+type answerGroup struct {
+	label      string
+	waitingFor string
+	command    string
+	actions    []model.Action
+	targets    []string
+}
+
+// answerGroupFor returns the answer group of the blocked pane target among
+// verdicts. The group always contains target itself.
+func answerGroupFor(verdicts []model.Verdict, target string) (answerGroup, bool) {
+	var anchor *model.Verdict
+	for i := range verdicts {
+		if verdicts[i].Target == target {
+			anchor = &verdicts[i]
+			break
+		}
+	}
+	if anchor == nil || summary.StateOf(*anchor) != summary.StateBlocked || len(anchor.Actions) == 0 {
+		return answerGroup{}, false
+	}
+	g := answerGroup{label: summary.ClusterLabel(*anchor), waitingFor: anchor.WaitingFor,
+		command: dialogCommand(*anchor), actions: anchor.Actions}
+	for _, v := range verdicts {
+		if g.includes(v) {
+			g.targets = append(g.targets, v.Target)
+		}
+	}
+	return g, true
+}
+
+// includes reports whether v currently belongs to the group. Used both to
+// build the group and to re-check members right before sending, since
+// scans keep running while the user decides.
+func (g answerGroup) includes(v model.Verdict) bool {
+	return summary.StateOf(v) == summary.StateBlocked &&
+		v.WaitingFor == g.waitingFor &&
+		dialogCommand(v) == g.command &&
+		sameActions(v.Actions, g.actions)
+}
+
+// dialogCommand returns the command v's dialog asks to run, if any.
+func dialogCommand(v model.Verdict) string {
+	if v.Dialog == nil {
+		return ""
+	}
+	return v.Dialog.Command
+}
+
+// sameActions reports whether two action lists send the same keystrokes at
+// the same risk.
+func sameActions(a, b []model.Action) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Keys != b[i].Keys || a[i].Raw != b[i].Raw || a[i].Risk != b[i].Risk {
+			return false
+		}
+	}
+	return true
+}
+
+// broadcastTasks returns a nudge task sending each member's own action
+// choice for every group member that is still in the group in verdicts,
+// plus the targets that dropped out (answered by hand, moved on, or now
+// showing a different dialog).
+func (g answerGroup) broadcastTasks(verdicts []model.Verdict, choice int) ([]nudgeTask, []string) {
+	current := make(map[string]model.Verdict, len(verdicts))
+	for _, v := range verdicts {
+		current[v.Target] = v
+	}
+	var tasks []nudgeTask
+	var skipped []string
+	for _, target := range g.targets {
+		v, ok := current[target]
+		if !ok || !g.includes(v) {
+			skipped = append(skipped, target)
+			continue
+		}
+		action := v.Actions[choice]
+		tasks = append(tasks, nudgeTask{target: target, agent: v.Agent, keys: action.Keys, raw: action.Raw, label: action.Label, risk: action.Risk,
+			state: blockedState(v), followUp: action.FollowUp})
+		if optionKey(v, action) {
+			tasks[len(tasks)-1].check = &v
+		}
+	}
+	return tasks, skipped
+}
+
+// highestRisk returns the highest risk among tasks, which decides whether
+// sending them needs confirming.
+func highestRisk(tasks []nudgeTask) string {
+	risk := ""
+	for _, t := range tasks {
+		if riskOrdinal(t.risk) > riskOrdinal(risk) {
+			risk = t.risk
+		}
+	}
+	return risk
+}
@@ -0,0 +1,118 @@
+package supervisor
+
+import (
+	"testing"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func approvalVerdict(target, command string) model.Verdict {
+	return model.Verdict{
+		Target:     target,
+		Session:    target[:1],
+		Agent:      "codex",
+		Blocked:    true,
+		Reason:     "command approval dialog",
+		WaitingFor: "$ " + command,
+		Actions: []model.Action{
+			{Keys: "Enter", Label: "yes, proceed", Risk: "medium", Raw: true},
+			{Keys: "Escape", Label: "cancel", Risk: "low", Raw: true},
+		},
+	}
+}
+
+func TestAnswerGroupFor_ExactDialogMatch(t *testing.T) {
+	other := approvalVerdict("d:0.0", "rm -rf build")
+	verdicts := []model.Verdict{
+		approvalVerdict("a:0.0", "npm install"),
+		approvalVerdict("b:0.0", "npm install"),
+		approvalVerdict("c:0.0", "npm install"),
+		other,
+		{Target: "e:0.0", Agent: "codex", Reason: "actively working"},
+	}
+	g, ok := answerGroupFor(verdicts, "b:0.0")
+	if !ok {
+		t.Fatal("expected a group")
+	}
+	want := []string{"a:0.0", "b:0.0", "c:0.0"}
+	if len(g.targets) != len(want) {
+		t.Fatalf("targets: got %v, want %v", g.targets, want)
+	}
+	for i := range want {
+		if g.targets[i] != want[i] {
+			t.Errorf("targets[%d]: got %q, want %q", i, g.targets[i], want[i])
+		}
+	}
+}
+
+func TestAnswerGroupFor_DifferentActionsExcluded(t *testing.T) {
+	a := approvalVerdict("a:0.0", "npm install")
+	b := approvalVerdict("b:0.0", "npm install")
+	b.Actions = b.Actions[:1] // same text, different dialog options
+	g, ok := answerGroupFor([]model.Verdict{a, b}, "a:0.0")
+	if !ok || len(g.targets) != 1 {
+		t.Errorf("expected only a:0.0, got %v", g.targets)
+	}
+}
+
+func TestAnswerGroupFor_UnmaskedDialogMatch(t *testing.T) {
+	// ClusterLabel masks numbers and paths; a group must not.
+	a := approvalVerdict("a:0.0", "chmod 644 ./notes.txt")
+	b := approvalVerdict("b:0.0", "chmod 777 /")
+	// Same first line (a box border), different commands.
+	c := approvalVerdict("c:0.0", "ls")
+	d := approvalVerdict("d:0.0", "rm -rf /")
+	c.WaitingFor, d.WaitingFor = "╭───╮", "╭───╮"
+	c.Dialog = &model.Dialog{Kind: model.DialogPermission, Command: "ls"}
+	d.Dialog = &model.Dialog{Kind: model.DialogPermission, Command: "rm -rf /"}
+	// Same keystrokes at a different risk.
+	e := approvalVerdict("e:0.0", "chmod 644 ./notes.txt")
+	e.Actions = []model.Action{e.Actions[0], e.Actions[1]}
+	e.Actions[0].Risk = "high"
+	verdicts := []model.Verdict{a, b, c, d, e}
+	for _, target := range []string{"a:0.0", "c:0.0"} {
+		g, ok := answerGroupFor(verdicts, target)
+		if !ok || len(g.targets) != 1 || g.targets[0] != target {
+			t.Errorf("%s: expected a group of itself, got %v", target, g.targets)
+		}
+	}
+}
+
+func TestHighestRisk(t *testing.T) {
+	tasks := []nudgeTask{{risk: "low"}, {risk: "high"}, {risk: "medium"}}
+	if got := highestRisk(tasks); got != "high" {
+		t.Errorf("got %q, want high", got)
+	}
+}
+
+func TestAnswerGroupFor_NotBlocked(t *testing.T) {
+	v := model.Verdict{Target: "a:0.0", Agent: "codex", Reason: "actively working"}
+	if _, ok := answerGroupFor([]model.Verdict{v}, "a:0.0"); ok {
+		t.Error("active pane should not form an answer group")
+	}
+	if _, ok := answerGroupFor(nil, "missing:0.0"); ok {
+		t.Error("unknown target should not form an answer group")
+	}
+}
+
+func TestBroadcastTasks_SkipsChangedMembers(t *testing.T) {
+	verdicts := []model.Verdict{
+		approvalVerdict("a:0.0", "npm install"),
+		approvalVerdict("b:0.0", "npm install"),
+		approvalVerdict("c:0.0", "npm install"),
+	}
+	g, _ := answerGroupFor(verdicts, "a:0.0")
+
+	// Meanwhile b was answered by hand and c disappeared.
+	now := []model.Verdict{
+		verdicts[0],
+		{Target: "b:0.0", Agent: "codex", Reason: "actively working"},
+	}
+	tasks, skipped := g.broadcastTasks(now, 0)
+	if len(tasks) != 1 || tasks[0].target != "a:0.0" || tasks[0].keys != "Enter" || !tasks[0].raw {
+		t.Errorf("tasks: got %+v", tasks)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("skipped: got %v, want [b:0.0 c:0.0]", skipped)
+	}
+}
@@ -2,13 +2,33 @@ package supervisor
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/timvw/pane-patrol/internal/model"
 )
 
+// Cache reuses the verdicts of panes whose content has not changed since
+// they were evaluated. VerdictCache is the supervisor's implementation;
+// library users can plug in their own (see pkg/patrol).
+type Cache interface {
+	// Lookup returns the verdict stored for target if it was made from
+	// content.
+	Lookup(target, content string) (*model.Verdict, bool)
+	// Store records the verdict made from target's content.
+	Store(target, content string, verdict model.Verdict)
+	// Invalidate drops target's verdict.
+	Invalidate(target string)
+	// Retain drops the verdicts of panes not in panes.
+	Retain(panes []model.Pane)
+}
+
 // VerdictCache caches LLM verdicts keyed by pane content hash.
 // When pane content hasn't changed since the last scan, the cached verdict
 // is reused — saving an expensive LLM API call (~10-15s per pane).
@@ -16,12 +36,27 @@ import (
 // Cache entries have a TTL. After expiry, the pane is re-evaluated even if
 // content is identical. This ensures we don't miss frozen/stuck agents where
 // the content hasn't changed because the agent is truly stuck.
+//
+// The cache holds at most maxCacheEntries panes, evicting the oldest entry.
+// A cache loaded with LoadVerdictCache is also kept on disk by Save, so the
+// first scan after a restart reuses the verdicts of unchanged panes.
 type VerdictCache struct {
 	mu      sync.RWMutex
 	entries map[string]*cacheEntry // keyed by pane target
 	ttl     time.Duration
+	path    string // file written by Save; "" keeps the cache in memory only
+	key     *StateKey
+	// fingerprint identifies what the verdicts were made with (see
+	// CacheFingerprint); it is saved with them.
+	fingerprint string
+
+	// counters since the cache was created, for the cache screen
+	hits, misses, invalidations, evictions int
 }
 
+// maxCacheEntries bounds the cache size.
+const maxCacheEntries = 1000
+
 type cacheEntry struct {
 	contentHash string
 	verdict     model.Verdict
@@ -54,21 +89,25 @@ func (c *VerdictCache) Lookup(target, content string) (*model.Verdict, bool) {
 
 	entry, ok := c.entries[target]
 	if !ok {
+		c.misses++
 		return nil, false
 	}
 
 	// Content changed — cache miss
 	if entry.contentHash != hash {
+		c.misses++
 		return nil, false
 	}
 
 	// TTL expired — delete stale entry and return miss
 	if time.Since(entry.cachedAt) > c.ttl {
 		delete(c.entries, target)
+		c.misses++
 		return nil, false
 	}
 
 	// Cache hit — increment counter and return a copy
+	c.hits++
 	entry.hitCount++
 	v := entry.verdict
 	return &v, true
@@ -85,6 +124,9 @@ func (c *VerdictCache) Store(target, content string, verdict model.Verdict) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if _, ok := c.entries[target]; !ok && len(c.entries) >= maxCacheEntries {
+		c.evictOldest()
+	}
 	c.entries[target] = &cacheEntry{
 		contentHash: hash,
 		verdict:     verdict,
@@ -92,16 +134,203 @@ func (c *VerdictCache) Store(target, content string, verdict model.Verdict) {
 	}
 }
 
+// evictOldest removes the entry cached longest ago. The caller holds mu.
+func (c *VerdictCache) evictOldest() {
+	var oldest string
+	for target, e := range c.entries {
+		if oldest == "" || e.cachedAt.Before(c.entries[oldest].cachedAt) {
+			oldest = target
+		}
+	}
+	delete(c.entries, oldest)
+	c.evictions++
+}
+
 // Invalidate removes the cache entry for the given target, forcing
 // re-evaluation on the next scan regardless of content.
 func (c *VerdictCache) Invalidate(target string) {
 	c.mu.Lock()
-	delete(c.entries, target)
+	if _, ok := c.entries[target]; ok {
+		delete(c.entries, target)
+		c.invalidations++
+	}
 	c.mu.Unlock()
 }
 
+// Retain drops the entries of panes not in panes, e.g. closed since the
+// last scan; each counts as an invalidation. A nil cache is a no-op.
+func (c *VerdictCache) Retain(panes []model.Pane) {
+	if c == nil {
+		return
+	}
+	keep := make(map[string]bool, len(panes))
+	for _, p := range panes {
+		keep[p.Target] = true
+	}
+	c.mu.Lock()
+	for target := range c.entries {
+		if !keep[target] {
+			delete(c.entries, target)
+			c.invalidations++
+		}
+	}
+	c.mu.Unlock()
+}
+
+// Clear removes every entry; each counts as an invalidation.
+func (c *VerdictCache) Clear() {
+	c.mu.Lock()
+	c.invalidations += len(c.entries)
+	clear(c.entries)
+	c.mu.Unlock()
+}
+
+// CacheStats summarizes a VerdictCache.
+type CacheStats struct {
+	Entries       int
+	Hits          int
+	Misses        int
+	Invalidations int // entries dropped by Invalidate, Retain, or Clear
+	Evictions     int // entries dropped to stay within the size bound
+	TTL           time.Duration
+	File          string // "" when the cache is in memory only
+}
+
+// Stats returns the cache's size and counters.
+func (c *VerdictCache) Stats() CacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return CacheStats{
+		Entries: len(c.entries), Hits: c.hits, Misses: c.misses,
+		Invalidations: c.invalidations, Evictions: c.evictions,
+		TTL: c.ttl, File: c.path,
+	}
+}
+
+// CachedVerdict describes one cache entry.
+type CachedVerdict struct {
+	Target   string
+	Verdict  model.Verdict
+	CachedAt time.Time
+	Hits     int
+}
+
+// List returns the entries sorted by target.
+func (c *VerdictCache) List() []CachedVerdict {
+	c.mu.RLock()
+	list := make([]CachedVerdict, 0, len(c.entries))
+	for target, e := range c.entries {
+		list = append(list, CachedVerdict{Target: target, Verdict: e.verdict, CachedAt: e.cachedAt, Hits: e.hitCount})
+	}
+	c.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Target < list[j].Target })
+	return list
+}
+
 // hashContent returns a hex-encoded SHA256 hash of the content.
 func hashContent(content string) string {
 	h := sha256.Sum256([]byte(content))
 	return fmt.Sprintf("%x", h)
 }
+
+// cacheFile is the on-disk form of a VerdictCache.
+type cacheFile struct {
+	Fingerprint string           `json:"fingerprint"`
+	Entries     []cacheFileEntry `json:"entries"`
+}
+
+// CacheFingerprint identifies what verdicts are made with: the binary's
+// version and the configuration the parsers read (custom parsers, risk
+// rules, idle actions), each given as it is configured. A persisted cache
+// made with another fingerprint is not reused, since the same content may
+// now get another verdict.
+func CacheFingerprint(version string, config ...any) string {
+	data, err := json.Marshal(append([]any{version}, config...))
+	if err != nil {
+		// Never matches a saved cache, so nothing stale is reused.
+		return ""
+	}
+	return hashContent(string(data))
+}
+
+type cacheFileEntry struct {
+	Target      string        `json:"target"`
+	ContentHash string        `json:"content_hash"`
+	CachedAt    time.Time     `json:"cached_at"`
+	Verdict     model.Verdict `json:"verdict"`
+}
+
+// LoadVerdictCache creates a cache with the given TTL that is persisted to
+// path (a leading "~" is expanded), encrypted with key unless it is nil.
+// Entries already expired, and all entries when the file was saved with
+// another fingerprint (see CacheFingerprint), are dropped; a missing file
+// yields an empty cache.
+func LoadVerdictCache(path string, ttl time.Duration, key *StateKey, fingerprint string) (*VerdictCache, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	c := NewVerdictCache(ttl)
+	c.path, c.key, c.fingerprint = path, key, fingerprint
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading verdict cache: %w", err)
+	}
+	if data, err = key.open(path, data); err != nil {
+		return nil, fmt.Errorf("reading verdict cache: %w", err)
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing verdict cache %s: %w", path, err)
+	}
+	if fingerprint == "" || file.Fingerprint != fingerprint {
+		return c, nil
+	}
+	for _, e := range file.Entries {
+		if time.Since(e.CachedAt) > ttl || len(c.entries) >= maxCacheEntries {
+			continue
+		}
+		c.entries[e.Target] = &cacheEntry{contentHash: e.ContentHash, verdict: e.Verdict, cachedAt: e.CachedAt}
+	}
+	return c, nil
+}
+
+// Save writes the unexpired entries to the cache's file, replacing it
+// atomically. It does nothing for a cache that is not persisted.
+func (c *VerdictCache) Save() error {
+	if c.path == "" {
+		return nil
+	}
+	file := cacheFile{Fingerprint: c.fingerprint}
+	c.mu.RLock()
+	for target, e := range c.entries {
+		if time.Since(e.cachedAt) > c.ttl {
+			continue
+		}
+		file.Entries = append(file.Entries, cacheFileEntry{
+			Target: target, ContentHash: e.contentHash, CachedAt: e.cachedAt, Verdict: e.verdict,
+		})
+	}
+	c.mu.RUnlock()
+	sort.Slice(file.Entries, func(i, j int) bool { return file.Entries[i].Target < file.Entries[j].Target })
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("saving verdict cache: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(c.key.seal(data), '\n'), 0o600); err != nil {
+		return fmt.Errorf("saving verdict cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("saving verdict cache: %w", err)
+	}
+	return nil
+}
@@ -1,10 +1,13 @@
 package supervisor
 
 import (
+	"fmt"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/timvw/pane-patrol/internal/config"
 	"github.com/timvw/pane-patrol/internal/model"
 )
 
@@ -241,3 +244,101 @@ func TestVerdictCache_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 	// If we get here without -race complaints, the locking is correct
 }
+
+func TestVerdictCache_Persisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := LoadVerdictCache(path, 5*time.Minute, nil, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Store("dev:0.0", "content", model.Verdict{Target: "dev:0.0", Agent: "codex", Blocked: true})
+	cache.Store("dev:0.1", "other", model.Verdict{Target: "dev:0.1", Agent: "opencode"})
+	cache.mu.Lock()
+	cache.entries["dev:0.1"].cachedAt = time.Now().Add(-10 * time.Minute)
+	cache.mu.Unlock()
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := LoadVerdictCache(path, 5*time.Minute, nil, "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := reloaded.Lookup("dev:0.0", "content"); !ok || v.Agent != "codex" || !v.Blocked {
+		t.Errorf("expected the verdict to survive a reload, got %+v, %v", v, ok)
+	}
+	if _, ok := reloaded.Lookup("dev:0.0", "changed"); ok {
+		t.Error("expected a miss for changed content")
+	}
+	if len(reloaded.entries) != 1 {
+		t.Errorf("expected the expired entry to be dropped, got %d entries", len(reloaded.entries))
+	}
+
+	if err := NewVerdictCache(time.Minute).Save(); err != nil {
+		t.Errorf("an in-memory cache has nothing to save: %v", err)
+	}
+
+	// Another binary or parser config may judge the same content otherwise.
+	other, err := LoadVerdictCache(path, 5*time.Minute, nil, "v2")
+	if err != nil || len(other.entries) != 0 {
+		t.Errorf("expected no entries under another fingerprint, got %d, %v", len(other.entries), err)
+	}
+}
+
+func TestCacheFingerprint(t *testing.T) {
+	rules := []config.RiskRule{{WaitingFor: "terraform apply", Risk: "high"}}
+	base := CacheFingerprint("1.0.0", []config.CustomParser(nil), rules)
+	if base == "" || base != CacheFingerprint("1.0.0", []config.CustomParser(nil), rules) {
+		t.Fatalf("fingerprint should be stable: %q", base)
+	}
+	if base == CacheFingerprint("1.1.0", []config.CustomParser(nil), rules) {
+		t.Error("a new version should change the fingerprint")
+	}
+	if base == CacheFingerprint("1.0.0", []config.CustomParser(nil), []config.RiskRule(nil)) {
+		t.Error("new risk rules should change the fingerprint")
+	}
+}
+
+func TestVerdictCache_Bounded(t *testing.T) {
+	cache := NewVerdictCache(5 * time.Minute)
+	for i := range maxCacheEntries + 5 {
+		target := fmt.Sprintf("dev:0.%d", i)
+		cache.Store(target, "content", model.Verdict{Target: target})
+		cache.mu.Lock()
+		cache.entries[target].cachedAt = time.Now().Add(time.Duration(i) * time.Millisecond)
+		cache.mu.Unlock()
+	}
+	if len(cache.entries) != maxCacheEntries {
+		t.Fatalf("expected %d entries, got %d", maxCacheEntries, len(cache.entries))
+	}
+	if _, ok := cache.Lookup("dev:0.0", "content"); ok {
+		t.Error("expected the oldest entry to be evicted")
+	}
+	if _, ok := cache.Lookup(fmt.Sprintf("dev:0.%d", maxCacheEntries+4), "content"); !ok {
+		t.Error("expected the newest entry to be kept")
+	}
+}
+
+func TestVerdictCache_Stats(t *testing.T) {
+	cache := NewVerdictCache(5 * time.Minute)
+	cache.Store("dev:0.0", "content", model.Verdict{Agent: "codex"})
+	cache.Store("dev:0.1", "content", model.Verdict{Agent: "opencode"})
+	cache.Lookup("dev:0.0", "content")
+	cache.Lookup("dev:0.0", "changed")
+	cache.Lookup("dev:0.2", "content")
+	cache.Invalidate("dev:0.0")
+	cache.Invalidate("dev:0.0") // already gone: not counted again
+
+	st := cache.Stats()
+	if st.Entries != 1 || st.Hits != 1 || st.Misses != 2 || st.Invalidations != 1 {
+		t.Errorf("got %+v", st)
+	}
+	if list := cache.List(); len(list) != 1 || list[0].Target != "dev:0.1" || list[0].Verdict.Agent != "opencode" {
+		t.Errorf("List: got %+v", list)
+	}
+
+	cache.Clear()
+	if st := cache.Stats(); st.Entries != 0 || st.Invalidations != 2 {
+		t.Errorf("after Clear: got %+v", st)
+	}
+}
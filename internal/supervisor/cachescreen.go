@@ -0,0 +1,108 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cacheScreen is the verdict cache screen (opened with C): the cache's
+// counters and entries, to flush a bad cached verdict without restarting.
+type cacheScreen struct {
+	cursor int
+}
+
+// handleCacheScreenKey navigates the cache entries; x invalidates one and
+// c clears the cache.
+func (m *tuiModel) handleCacheScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.cacheScreen
+	cache := m.scanner.VerdictCache()
+	entries := cache.List()
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "C":
+		m.cacheScreen = nil
+	case "up", "k":
+		s.cursor = max(s.cursor-1, 0)
+	case "down", "j":
+		s.cursor = min(s.cursor+1, max(len(entries)-1, 0))
+	case "x", "delete":
+		if s.cursor >= len(entries) {
+			return m, nil
+		}
+		m.invalidateCache(entries[s.cursor].Target)
+		m.message = fmt.Sprintf("Invalidated %s; it is re-evaluated on the next scan", entries[s.cursor].Target)
+		s.cursor = min(s.cursor, max(len(entries)-2, 0))
+	case "c":
+		cache.Clear()
+		m.logger().Info("cache cleared", "entries", len(entries))
+		s.cursor = 0
+		m.message = fmt.Sprintf("Cleared %d cached verdicts", len(entries))
+	case "r":
+		if !m.scanning {
+			m.scanning = true
+			return m, m.doScan()
+		}
+	}
+	return m, nil
+}
+
+// viewCacheScreen renders the cache counters and entries, as many as fit.
+func (m *tuiModel) viewCacheScreen() string {
+	s := m.cacheScreen
+	cache := m.scanner.VerdictCache()
+	st := cache.Stats()
+	entries := cache.List()
+	now := time.Now()
+
+	var b strings.Builder
+	b.WriteString(m.s.title.Render(fmt.Sprintf("Verdict cache (%d)", st.Entries)))
+	b.WriteString("  ")
+	where := "in memory"
+	if st.File != "" {
+		where = st.File
+	}
+	b.WriteString(m.s.dim.Render(fmt.Sprintf("ttl %s · %s", st.TTL, where)))
+	b.WriteString("\n")
+	lookups := st.Hits + st.Misses
+	ratio := 0.0
+	if lookups > 0 {
+		ratio = 100 * float64(st.Hits) / float64(lookups)
+	}
+	b.WriteString(fmt.Sprintf("  %d hits, %d misses (%.0f%% hit ratio), %d invalidated, %d evicted\n\n",
+		st.Hits, st.Misses, ratio, st.Invalidations, st.Evictions))
+
+	if len(entries) == 0 {
+		b.WriteString(m.s.dim.Render("  no cached verdicts"))
+		b.WriteString("\n")
+	}
+	rows := max(m.height-8, 1)
+	first := max(min(s.cursor-rows/2, len(entries)-rows), 0)
+	for i := first; i < len(entries) && i < first+rows; i++ {
+		e := entries[i]
+		marker := "  "
+		if i == s.cursor {
+			marker = m.s.selected.Render(m.glyphs().cursor)
+		}
+		state := "active"
+		if e.Verdict.Blocked {
+			state = "blocked"
+		}
+		age := formatDuration(now.Sub(e.CachedAt))
+		line := fmt.Sprintf("%s %s %-8s %6s %4d hits  %s",
+			padRight(truncate(e.Target, 24), 24), padRight(truncate(e.Verdict.Agent, 12), 12), state, age, e.Hits, e.Verdict.Reason)
+		b.WriteString(marker + truncate(line, m.width-3))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styleHints("  ↑↓ select  x invalidate  c clear all  r rescan  esc close"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + truncate(m.message, m.width-3)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestCacheScreen_InvalidateAndClear(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.scanner = &Scanner{Cache: NewVerdictCache(time.Minute)}
+	m.scanner.Cache.Store("a:0.0", "content", model.Verdict{Agent: "codex", Reason: "idle"})
+	m.scanner.Cache.Store("test:0.0", "content", model.Verdict{Agent: "opencode", Blocked: true, Reason: "permission"})
+	m.scanner.Cache.Store("z:0.0", "content", model.Verdict{Agent: "codex"})
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if m.cacheScreen == nil || m.cacheScreen.cursor != 1 {
+		t.Fatalf("expected C to open the cache screen at the selected pane, got %+v", m.cacheScreen)
+	}
+	if view := m.View(); !strings.Contains(view, "Verdict cache (3)") || !strings.Contains(view, "permission") {
+		t.Errorf("expected the entries listed:\n%s", view)
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if _, ok := m.scanner.Cache.Lookup("test:0.0", "content"); ok {
+		t.Error("expected x to invalidate the selected entry")
+	}
+	if !strings.Contains(m.View(), "1 invalidated") {
+		t.Errorf("expected the invalidation counted:\n%s", m.View())
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if st := m.scanner.VerdictCache().Stats(); st.Entries != 0 {
+		t.Errorf("expected c to clear the cache, got %+v", st)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.cacheScreen != nil {
+		t.Error("expected esc to close the screen")
+	}
+}
+
+func TestCacheScreen_Disabled(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+	if m.cacheScreen != nil || !strings.Contains(m.message, "cache is off") {
+		t.Errorf("expected a hint instead of the screen, got %q", m.message)
+	}
+}
@@ -0,0 +1,121 @@
+package supervisor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// clipboardTool is a program pair that copies stdin to the system clipboard
+// and writes the clipboard to stdout.
+type clipboardTool struct {
+	copy  []string
+	paste []string
+}
+
+// clipboardTools lists the clipboard programs in preference order. A tool
+// that is installed but cannot reach a display (xclip over ssh) fails and
+// the next one is tried. The last entry uses tmux's paste buffer; with -w
+// tmux also forwards the text to the outer terminal's clipboard via OSC 52
+// (requires set-clipboard on, the tmux default for most terminals).
+var clipboardTools = []clipboardTool{
+	{copy: []string{"pbcopy"}, paste: []string{"pbpaste"}},
+	{copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}},
+	{copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}},
+	{copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}},
+	{copy: []string{"tmux", "load-buffer", "-w", "-"}, paste: []string{"tmux", "save-buffer", "-"}},
+}
+
+// copyToClipboard copies text with the first clipboard tool that works and
+// returns the tool's name.
+func copyToClipboard(text string) (string, error) {
+	var errs []string
+	for _, tool := range clipboardTools {
+		if _, err := exec.LookPath(tool.copy[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(tool.copy[0], tool.copy[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v %s", tool.copy[0], err, strings.TrimSpace(string(out))))
+			continue
+		}
+		return tool.copy[0], nil
+	}
+	return "", clipboardError(errs)
+}
+
+// pasteFromClipboard returns the clipboard text from the first clipboard
+// tool that works.
+func pasteFromClipboard() (string, error) {
+	var errs []string
+	for _, tool := range clipboardTools {
+		if _, err := exec.LookPath(tool.paste[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(tool.paste[0], tool.paste[1:]...).Output()
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", tool.paste[0], err))
+			continue
+		}
+		return string(out), nil
+	}
+	return "", clipboardError(errs)
+}
+
+func clipboardError(errs []string) error {
+	if len(errs) == 0 {
+		return fmt.Errorf("no clipboard program found (tried pbcopy, wl-copy, xclip, xsel, tmux)")
+	}
+	return fmt.Errorf("clipboard failed: %s", strings.Join(errs, "; "))
+}
+
+// copyText returns the text to copy for a pane: the one-time code of a
+// sign-in dialog, what it is waiting for, or the dialog question when the
+// parser extracted no WaitingFor.
+func copyText(v model.Verdict) string {
+	if v.Dialog != nil && v.Dialog.Code != "" {
+		return v.Dialog.Code
+	}
+	if v.WaitingFor != "" {
+		return v.WaitingFor
+	}
+	if v.Dialog != nil {
+		return v.Dialog.Question
+	}
+	return ""
+}
+
+// copyCmd copies the pane's question text to the clipboard in the
+// background.
+func copyCmd(v model.Verdict) tea.Cmd {
+	text := copyText(v)
+	if text == "" {
+		return func() tea.Msg {
+			return nudgeResultMsg{messages: []string{v.Target + " has no question text to copy"}}
+		}
+	}
+	return func() tea.Msg {
+		tool, err := copyToClipboard(text)
+		if err != nil {
+			return nudgeResultMsg{messages: []string{err.Error()}}
+		}
+		return nudgeResultMsg{messages: []string{fmt.Sprintf("copied %d chars from %s (%s)", len([]rune(text)), v.Target, tool)}}
+	}
+}
+
+// clipboardPasteMsg delivers clipboard text to the text input.
+type clipboardPasteMsg struct {
+	text string
+	err  error
+}
+
+func pasteCmd() tea.Cmd {
+	return func() tea.Msg {
+		text, err := pasteFromClipboard()
+		return clipboardPasteMsg{text: text, err: err}
+	}
+}
@@ -0,0 +1,66 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestCopyToClipboard_FallsThroughFailingTools(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "clip")
+	orig := clipboardTools
+	t.Cleanup(func() { clipboardTools = orig })
+	clipboardTools = []clipboardTool{
+		{copy: []string{"pane-patrol-no-such-tool"}, paste: []string{"pane-patrol-no-such-tool"}},
+		{copy: []string{"false"}, paste: []string{"false"}},
+		{copy: []string{"sh", "-c", "cat > " + file}, paste: []string{"cat", file}},
+	}
+
+	tool, err := copyToClipboard("Allow bash: rm -rf build?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tool != "sh" {
+		t.Errorf("tool: got %q, want sh", tool)
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != "Allow bash: rm -rf build?" {
+		t.Errorf("clipboard: got %q", data)
+	}
+
+	got, err := pasteFromClipboard()
+	if err != nil || got != "Allow bash: rm -rf build?" {
+		t.Errorf("paste: got %q, %v", got, err)
+	}
+}
+
+func TestCopyToClipboard_NoTool(t *testing.T) {
+	orig := clipboardTools
+	t.Cleanup(func() { clipboardTools = orig })
+	clipboardTools = []clipboardTool{{copy: []string{"pane-patrol-no-such-tool"}, paste: []string{"pane-patrol-no-such-tool"}}}
+
+	if _, err := copyToClipboard("x"); err == nil || !strings.Contains(err.Error(), "no clipboard program") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestCopyText(t *testing.T) {
+	v := model.Verdict{WaitingFor: "Which checks?\n[ ] lint", Dialog: &model.Dialog{Question: "Which checks?"}}
+	if got := copyText(v); got != v.WaitingFor {
+		t.Errorf("WaitingFor preferred: got %q", got)
+	}
+	v.WaitingFor = ""
+	if got := copyText(v); got != "Which checks?" {
+		t.Errorf("dialog question fallback: got %q", got)
+	}
+	if got := copyText(model.Verdict{}); got != "" {
+		t.Errorf("nothing to copy: got %q", got)
+	}
+	signIn := model.Verdict{WaitingFor: "sign in with ChatGPT: enter code ABCD-1234", Dialog: &model.Dialog{Kind: model.DialogAuth, Code: "ABCD-1234"}}
+	if got := copyText(signIn); got != "ABCD-1234" {
+		t.Errorf("device code preferred: got %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// maxConfirmReason caps the reason typed to confirm a high-risk action.
+const maxConfirmReason = 200
+
+// confirmInput asks for "yes" or a reason before a high-risk action is sent
+// (confirm_high_risk). send is called with the typed text, which ends up in
+// the history log.
+type confirmInput struct {
+	action  model.Action
+	targets []string
+	input   []rune
+	send    func(reason string) tea.Cmd
+}
+
+// needsConfirm reports whether sending a requires a typed confirmation.
+// Low- and medium-risk actions stay one keystroke.
+func (m *tuiModel) needsConfirm(a model.Action) bool {
+	return m.confirmHighRisk && a.Risk == "high"
+}
+
+// handleConfirmKey handles keys while confirming a high-risk action: enter
+// sends once something is typed, esc cancels.
+func (m *tuiModel) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.confirm = nil
+		m.message = "Cancelled"
+	case tea.KeyEnter:
+		reason := strings.TrimSpace(string(c.input))
+		if reason == "" {
+			m.message = `Type "yes" or a reason to send this action`
+			return m, nil
+		}
+		m.confirm = nil
+		m.message = ""
+		return m, c.send(reason)
+	default:
+		c.edit(msg)
+	}
+	return m, nil
+}
+
+// edit applies an editing key to the typed reason.
+func (c *confirmInput) edit(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if len(c.input) > 0 {
+			c.input = c.input[:len(c.input)-1]
+		}
+	case tea.KeyCtrlU:
+		c.input = nil
+	case tea.KeySpace:
+		c.input = append(c.input, ' ')
+	case tea.KeyRunes:
+		if len(c.input)+len(msg.Runes) <= maxConfirmReason {
+			c.input = append(c.input, msg.Runes...)
+		}
+	}
+}
+
+// viewConfirm shows the high-risk action and the pane(s) it goes to above
+// the reason input.
+func (m *tuiModel) viewConfirm() string {
+	c := m.confirm
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Confirm high-risk action"))
+	b.WriteString("\n\n  ")
+	b.WriteString(m.riskLabel(c.action.Risk))
+	b.WriteString(" ")
+	b.WriteString(truncate(fmt.Sprintf("%s (%s)", c.action.Label, c.action.Keys), m.width-10))
+	b.WriteString("\n\n")
+	for _, target := range c.targets {
+		b.WriteString("  → ")
+		b.WriteString(target)
+		if label := m.labels.Pane(target); label != "" {
+			b.WriteString(m.s.dim.Render(" " + label))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n  yes or reason: ")
+	b.WriteString(string(c.input))
+	b.WriteString("█\n\n")
+	b.WriteString(m.styleHints("  enter send  ctrl+u clear  esc cancel"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func highRiskVerdict() model.Verdict {
+	v := simpleVerdict()
+	v.Actions = append(v.Actions, model.Action{Keys: "a", Label: "allow always", Risk: "high", Raw: true})
+	return v
+}
+
+func TestConfirm_HighRiskActionAsksForReason(t *testing.T) {
+	m := newTestModel(highRiskVerdict())
+	m.confirmHighRisk = true
+
+	if _, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}); cmd != nil {
+		t.Fatal("expected no command before confirmation")
+	}
+	if m.confirm == nil || m.confirm.action.Label != "allow always" || m.confirm.targets[0] != "test:0.0" {
+		t.Fatalf("expected confirmation for allow always, got %+v", m.confirm)
+	}
+	var reason string
+	m.confirm.send = func(r string) tea.Cmd {
+		reason = r
+		return func() tea.Msg { return nil }
+	}
+
+	// Enter without a reason keeps the confirmation open.
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.confirm == nil {
+		t.Fatal("expected empty confirmation to be refused")
+	}
+	for _, r := range "reviewed the plan" {
+		_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Fatal("expected confirmation to send the action")
+	}
+	if m.confirm != nil || reason != "reviewed the plan" {
+		t.Errorf("got confirm=%v reason=%q", m.confirm, reason)
+	}
+}
+
+func TestConfirm_EscCancels(t *testing.T) {
+	m := newTestModel(highRiskVerdict())
+	m.confirmHighRisk = true
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || m.confirm != nil {
+		t.Error("expected esc to cancel without sending")
+	}
+}
+
+func TestConfirm_LowAndMediumRiskStayOneKeystroke(t *testing.T) {
+	m := newTestModel(highRiskVerdict())
+	m.confirmHighRisk = true
+	for _, key := range []string{"1", "2"} {
+		if _, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}); cmd == nil || m.confirm != nil {
+			t.Errorf("key %s: expected the action to be sent directly", key)
+		}
+	}
+}
+
+func TestConfirm_DisabledSendsHighRiskDirectly(t *testing.T) {
+	m := newTestModel(highRiskVerdict())
+	if _, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}); cmd == nil || m.confirm != nil {
+		t.Error("expected high-risk action to be sent without confirm_high_risk")
+	}
+}
+
+func TestConfirm_ViewShowsActionAndTargets(t *testing.T) {
+	m := newTestModel(highRiskVerdict())
+	m.confirmHighRisk = true
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	view := m.View()
+	for _, want := range []string{"Confirm high-risk action", "allow always (a)", "→ test:0.0", "yes or reason"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestConfirm_AnswerAllHighRisk(t *testing.T) {
+	a := approvalVerdict("a:0.0", "rm -rf build")
+	b := approvalVerdict("b:0.0", "rm -rf build")
+	a.Actions[0].Risk, b.Actions[0].Risk = "high", "high"
+	m := newTestModel(a)
+	m.verdicts = append(m.verdicts, b)
+	m.confirmHighRisk = true
+	g, _ := answerGroupFor(m.verdicts, "a:0.0")
+	m.answering = &g
+	m.answerChoice = 0
+
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd != nil {
+		t.Fatal("expected no command before confirmation")
+	}
+	if m.confirm == nil || len(m.confirm.targets) != 2 {
+		t.Fatalf("expected confirmation for both panes, got %+v", m.confirm)
+	}
+}
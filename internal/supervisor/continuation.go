@@ -0,0 +1,91 @@
+package supervisor
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// LastContinuation returns the continuation prompt last sent to the pane at
+// target with o, or "".
+func (l *Labels) LastContinuation(target string) string {
+	if l == nil {
+		return ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Continuations[target]
+}
+
+// SetLastContinuation remembers text as the continuation prompt last sent
+// to the pane at target.
+func (l *Labels) SetLastContinuation(target, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Continuations[target] = text
+}
+
+// idleAtPrompt reports whether v is an agent waiting at its prompt for the
+// next instruction rather than for an answer to a dialog.
+func idleAtPrompt(v *model.Verdict) bool {
+	return v != nil && v.Blocked && v.Reason == parser.IdleReason
+}
+
+// openContinuation opens the text input for the selected idle pane with the
+// configured continuations to pick from (o). What is sent is remembered
+// for the pane so "." can resend it.
+func (m *tuiModel) openContinuation() {
+	v := m.selectedVerdict()
+	if v == nil {
+		return
+	}
+	if !idleAtPrompt(v) {
+		m.message = fmt.Sprintf("%s is not idle at its prompt", v.Target)
+		return
+	}
+	ti := &textInput{target: v.Target, continuation: true, choices: m.continuations}
+	ti.picking = len(ti.choices) > 0
+	m.textInput = ti
+	m.message = ""
+	if !ti.picking {
+		m.message = "No continuations configured (add continuations: to the config file)"
+	}
+}
+
+// rememberContinuation records text as the last continuation sent to
+// target and persists it. Without labels it is not remembered.
+func (m *tuiModel) rememberContinuation(target, text string) {
+	if m.labels == nil {
+		return
+	}
+	m.labels.SetLastContinuation(target, text)
+	if err := m.labels.Save(); err != nil {
+		m.logger().Warn("saving last continuation", "target", target, "err", err)
+	}
+}
+
+// continuationAction is the action that resends text to an idle agent.
+func continuationAction(text string) model.Action {
+	return model.Action{Keys: text, Label: "resend continuation", Risk: "low"}
+}
+
+// resendContinuation sends the selected idle pane the continuation it was
+// last sent (.), so an agent can be kept going with one key.
+func (m *tuiModel) resendContinuation() tea.Cmd {
+	v := m.selectedVerdict()
+	if v == nil {
+		return nil
+	}
+	if !idleAtPrompt(v) {
+		m.message = fmt.Sprintf("%s is not idle at its prompt", v.Target)
+		return nil
+	}
+	text := m.labels.LastContinuation(v.Target)
+	if text == "" {
+		m.message = fmt.Sprintf("No continuation sent to %s yet (o picks one)", v.Target)
+		return nil
+	}
+	return m.sendActionCmd(v.Target, continuationAction(text))
+}
@@ -0,0 +1,105 @@
+package supervisor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+func idleVerdict() model.Verdict {
+	return model.Verdict{
+		Target:  "dev:0.0",
+		Session: "dev",
+		Blocked: true,
+		Agent:   "claude",
+		Reason:  parser.IdleReason,
+		Actions: []model.Action{{Keys: "Enter", Label: "press Enter", Risk: "low", Raw: true}},
+	}
+}
+
+func TestContinuation_PickSendAndResend(t *testing.T) {
+	var typed []string
+	m := newTestModel(idleVerdict())
+	m.labels, _ = LoadLabels(filepath.Join(t.TempDir(), "labels.json"), nil)
+	m.continuations = []config.Snippet{
+		{Name: "keep going", Text: "Continue with the next step."},
+		{Text: "Run the tests and fix what fails."},
+	}
+	m.nudger = &Nudger{SendKeys: func(_, _, keys string) error {
+		typed = append(typed, keys)
+		return nil
+	}, Sleep: func(time.Duration) {}}
+	key := func(k string) tea.Cmd {
+		_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		return cmd
+	}
+
+	// Nothing to resend yet.
+	if cmd := key("."); cmd != nil || !strings.Contains(m.message, "No continuation sent") {
+		t.Fatalf("message = %q", m.message)
+	}
+
+	// o opens the picker over the continuations; 2 inserts one, enter sends.
+	key("o")
+	if m.textInput == nil || !m.textInput.picking || !strings.Contains(m.View(), "1. keep going") {
+		t.Fatalf("expected the continuation picker, got %+v", m.textInput)
+	}
+	key("2")
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected the continuation sent")
+	}
+	cmd()
+	if got := m.labels.LastContinuation("dev:0.0"); got != "Run the tests and fix what fails." {
+		t.Errorf("last continuation = %q", got)
+	}
+	reloaded, _ := LoadLabels(m.labels.path, nil)
+	if reloaded.LastContinuation("dev:0.0") == "" {
+		t.Error("expected the last continuation persisted")
+	}
+	if lines, _ := m.renderActionPanel(&m.verdicts[0], 80, 10); !strings.Contains(strings.Join(lines, "\n"), `resend "Run the tests`) {
+		t.Errorf("panel does not offer the resend:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// . resends it with one key.
+	typed = nil
+	cmd = key(".")
+	if cmd == nil {
+		t.Fatal("expected the continuation resent")
+	}
+	cmd()
+	if !strings.Contains(strings.Join(typed, "|"), "Run the tests and fix what fails.") {
+		t.Errorf("typed %q", typed)
+	}
+
+	// Panes busy or waiting on a dialog are not sent continuations.
+	m.verdicts[0].Reason = "permission dialog"
+	if cmd := key("."); cmd != nil || !strings.Contains(m.message, "not idle") {
+		t.Errorf("message = %q", m.message)
+	}
+	key("o")
+	if m.textInput != nil {
+		t.Error("o should not open for a pane that is not idle")
+	}
+}
+
+func TestContinuation_NoneConfigured(t *testing.T) {
+	m := newTestModel(idleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if m.textInput == nil || m.textInput.picking || !m.textInput.continuation {
+		t.Fatalf("expected a continuation input without picker, got %+v", m.textInput)
+	}
+	if !strings.Contains(m.message, "No continuations configured") {
+		t.Errorf("message = %q", m.message)
+	}
+	var nilLabels *Labels
+	if nilLabels.LastContinuation("dev:0.0") != "" {
+		t.Error("nil labels should have no continuations")
+	}
+}
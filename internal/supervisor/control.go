@@ -0,0 +1,261 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// paneAction is a process-level action on a pane, for crashed or wedged
+// agents that no dialog answer can unblock.
+type paneAction int
+
+const (
+	paneInterrupt paneAction = iota // send C-c to the foreground process
+	paneKill                        // close the pane (kill-pane)
+	paneRestart                     // kill and rerun the agent command in place
+)
+
+// destructive reports whether the action needs a y/n confirmation.
+func (a paneAction) destructive() bool {
+	return a != paneInterrupt
+}
+
+// restartPlan is how an agent is rerun in its pane.
+//
+// Panes created with a command (new-session/split-window with a command,
+// e.g. by pane-patrol launch) are respawned and tmux reruns that start
+// command. Panes that started a shell are respawned with a fresh shell and
+// the agent's command line, taken from the first process under the shell,
+// is typed back in.
+type restartPlan struct {
+	startCommand string // pane_start_command rerun by tmux, "" for shell panes
+	retype       string // command typed into the respawned shell
+	dir          string // working directory of the agent when it was killed
+}
+
+// command returns the agent command line the restart runs.
+func (p restartPlan) command() string {
+	if p.startCommand != "" {
+		return p.startCommand
+	}
+	return p.retype
+}
+
+// planRestart recovers the agent command from the pane's start command or,
+// for shell panes, the top level of its process tree (entries nested under
+// another process are indented by mux.ListPanes).
+func planRestart(startCommand, dir string, processTree []string) (restartPlan, error) {
+	if startCommand != "" {
+		return restartPlan{startCommand: unquoteStartCommand(startCommand), dir: dir}, nil
+	}
+	for _, proc := range processTree {
+		if proc != "" && !strings.HasPrefix(proc, " ") {
+			return restartPlan{retype: proc, dir: dir}, nil
+		}
+	}
+	return restartPlan{}, fmt.Errorf("no process running in the pane's shell")
+}
+
+// unquoteStartCommand strips the quotes tmux puts around a start command
+// given as a single string ("claude --resume" is shown as "\"claude --resume\"").
+func unquoteStartCommand(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' && !strings.Contains(s[1:len(s)-1], `"`) {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// paneControl is the kill/restart view for one pane (opened with X).
+type paneControl struct {
+	target     string
+	restart    restartPlan
+	restartErr error // why the pane cannot be restarted, nil if it can
+	choice     int   // picked paneAction awaiting confirmation, -1 while choosing
+}
+
+// paneControlMsg delivers the looked-up pane control view.
+type paneControlMsg struct {
+	control paneControl
+}
+
+// loadPaneControlCmd looks up how the selected pane's agent was started so
+// the control view can show the exact command a restart would run.
+func loadPaneControlCmd(ctx context.Context, v model.Verdict) tea.Cmd {
+	return func() tea.Msg {
+		c := paneControl{target: v.Target, choice: -1}
+		t := mux.NewTmux()
+		start, dir, err := t.PaneStart(ctx, v.Target)
+		if err != nil {
+			c.restartErr = err
+			return paneControlMsg{control: c}
+		}
+		var tree []string
+		if start == "" {
+			panes, err := t.ListPanes(ctx, "^"+regexp.QuoteMeta(v.Session)+"$")
+			if err != nil {
+				c.restartErr = err
+				return paneControlMsg{control: c}
+			}
+			for _, p := range panes {
+				if p.Target == v.Target {
+					tree = p.ProcessTree
+				}
+			}
+		}
+		c.restart, c.restartErr = planRestart(start, dir, tree)
+		return paneControlMsg{control: c}
+	}
+}
+
+// handlePaneControlKey handles keys in the pane control view: 1 interrupts
+// right away, 2 (kill) and 3 (restart) ask for y/n confirmation first.
+func (m *tuiModel) handlePaneControlKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.control = nil
+		return m, nil
+	}
+
+	c := m.control
+	if c.choice < 0 {
+		var action paneAction
+		switch key {
+		case "1":
+			action = paneInterrupt
+		case "2":
+			action = paneKill
+		case "3":
+			if c.restartErr != nil {
+				m.message = fmt.Sprintf("Cannot restart %s: %v", c.target, c.restartErr)
+				return m, nil
+			}
+			action = paneRestart
+		default:
+			return m, nil
+		}
+		if action.destructive() {
+			c.choice = int(action)
+			return m, nil
+		}
+		m.control = nil
+		return m, paneActionCmd(m.ctx, *c, action)
+	}
+
+	switch key {
+	case "y", "enter":
+		action := paneAction(c.choice)
+		m.control = nil
+		m.invalidateCache(c.target)
+		return m, paneActionCmd(m.ctx, *c, action)
+	case "n":
+		c.choice = -1
+	}
+	return m, nil
+}
+
+// paneActionCmd runs a pane action in the background and reports the result.
+func paneActionCmd(ctx context.Context, c paneControl, action paneAction) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		var done string
+		switch action {
+		case paneInterrupt:
+			err = NudgePane(c.target, "C-c", true)
+			done = "sent C-c to " + c.target
+		case paneKill:
+			err = mux.NewTmux().KillPane(ctx, c.target)
+			done = "killed " + c.target
+		case paneRestart:
+			err = restartPane(ctx, c.target, c.restart)
+			done = fmt.Sprintf("restarted %s: %s", c.target, c.restart.command())
+		}
+		if err != nil {
+			return nudgeResultMsg{messages: []string{fmt.Sprintf("%s: %v", c.target, err)}}
+		}
+		if action == paneInterrupt {
+			return nudgeResultMsg{messages: []string{done},
+				nudges: []nudgeTask{{target: c.target, keys: "C-c", raw: true, label: "interrupt", typed: true}}}
+		}
+		return nudgeResultMsg{messages: []string{done}}
+	}
+}
+
+// restartPane respawns the pane and, for shell panes, types the agent
+// command back into the new shell.
+func restartPane(ctx context.Context, target string, plan restartPlan) error {
+	if err := mux.NewTmux().RespawnPane(ctx, target, plan.dir); err != nil {
+		return err
+	}
+	if plan.retype == "" {
+		return nil
+	}
+	if err := defaultSendKeys(target, "-l", plan.retype); err != nil {
+		return err
+	}
+	return defaultSendKeys(target, "", "Enter")
+}
+
+// viewPaneControl renders the interrupt/kill/restart choices for a pane.
+func (m *tuiModel) viewPaneControl() string {
+	c := m.control
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Pane control"))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(c.target))
+	b.WriteString("\n\n")
+
+	restart := "restart: " + c.restart.command()
+	if c.restartErr != nil {
+		restart = "restart unavailable: " + c.restartErr.Error()
+	}
+	options := []string{
+		"interrupt (send C-c)",
+		"kill pane",
+		restart,
+	}
+	for i, opt := range options {
+		line := fmt.Sprintf("  %d. %s", i+1, truncate(opt, m.width-6))
+		switch {
+		case i == c.choice:
+			b.WriteString(m.s.selected.Render(line))
+		case i == int(paneRestart) && c.restartErr != nil:
+			b.WriteString(m.s.dim.Render(line))
+		default:
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	if c.restartErr == nil && c.restart.dir != "" {
+		b.WriteString(m.s.dim.Render("     in " + truncate(c.restart.dir, m.width-9)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	switch paneAction(c.choice) {
+	case paneKill:
+		b.WriteString(m.s.blocked.Render(fmt.Sprintf("  Kill %s and everything running in it?", c.target)))
+		b.WriteString("  ")
+		b.WriteString(m.styleHints("y confirm  n back  esc cancel"))
+	case paneRestart:
+		b.WriteString(m.s.blocked.Render(fmt.Sprintf("  Kill %s and rerun '%s'?", c.target, c.restart.command())))
+		b.WriteString("  ")
+		b.WriteString(m.styleHints("y confirm  n back  esc cancel"))
+	default:
+		b.WriteString(m.styleHints("  1-3 choose  esc cancel"))
+	}
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package supervisor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPlanRestart(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   string
+		tree    []string
+		want    restartPlan
+		wantErr bool
+	}{
+		{
+			name:  "start command is rerun by tmux",
+			start: `"claude --resume"`,
+			tree:  []string{"npx mcp-server"},
+			want:  restartPlan{startCommand: "claude --resume", dir: "/src"},
+		},
+		{
+			name:  "argv start command kept as shown",
+			start: `sh -c "sleep 100"`,
+			want:  restartPlan{startCommand: `sh -c "sleep 100"`, dir: "/src"},
+		},
+		{
+			name: "shell pane retypes the top-level process",
+			tree: []string{"node /usr/bin/codex --model o3", "  rg --files", "  node mcp.js"},
+			want: restartPlan{retype: "node /usr/bin/codex --model o3", dir: "/src"},
+		},
+		{
+			name:    "idle shell has nothing to restart",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := planRestart(tt.start, "/src", tt.tree)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err: got %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaneControl_KillNeedsConfirmation(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.Update(paneControlMsg{control: paneControl{
+		target:  "test:0.0",
+		restart: restartPlan{startCommand: "claude", dir: "/src"},
+		choice:  -1,
+	}})
+	view := m.View()
+	if !strings.Contains(view, "restart: claude") || !strings.Contains(view, "in /src") {
+		t.Errorf("expected restart command in view, got:\n%s", view)
+	}
+
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")}); cmd != nil {
+		t.Fatal("kill must not run before confirmation")
+	}
+	if !strings.Contains(m.View(), "Kill test:0.0") {
+		t.Errorf("expected kill confirmation, got:\n%s", m.View())
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.control == nil || m.control.choice != -1 {
+		t.Fatal("n should return to the choices")
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil || m.control != nil {
+		t.Error("y should run the restart and close the view")
+	}
+}
+
+func TestPaneControl_InterruptIsImmediate(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.control = &paneControl{target: "test:0.0", choice: -1}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if cmd == nil || m.control != nil {
+		t.Error("interrupt should run without confirmation")
+	}
+}
+
+func TestPaneControl_RestartUnavailable(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.control = &paneControl{target: "test:0.0", restartErr: errors.New("no process running in the pane's shell"), choice: -1}
+	if !strings.Contains(m.View(), "restart unavailable") {
+		t.Errorf("view:\n%s", m.View())
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	if cmd != nil || m.control.choice != -1 || !strings.Contains(m.message, "Cannot restart") {
+		t.Errorf("restart should be refused: choice=%d message=%q", m.control.choice, m.message)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.control != nil {
+		t.Error("esc should close the view")
+	}
+}
@@ -0,0 +1,221 @@
+package supervisor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/events"
+)
+
+// controlReplyTimeout bounds how long a control command waits for the TUI,
+// e.g. while it is suspended for the pager.
+const controlReplyTimeout = 5 * time.Second
+
+// controlHelp answers the help command.
+const controlHelp = "commands: rescan [target], jump <target>, toggle-autonudge, toggle-dnd, reload, help"
+
+// ControlSocket is a Unix socket the supervisor takes commands on, one per
+// line, so tmux key bindings and scripts can drive the running TUI
+// (control_socket). Each command is answered with one line: "ok", "ok: "
+// and the status message, or "error: " and why. Like the hook socket, it
+// trusts every process of the same user: the socket is 0600, and its
+// directory 0700 when Listen creates it. An existing directory (e.g.
+// ~/.cache for ~/.cache/pane-patrol.sock) keeps its mode.
+type ControlSocket struct {
+	Path string
+}
+
+// DefaultControlSocketPath returns the control socket next to the hook
+// event socket.
+func DefaultControlSocketPath() string {
+	return filepath.Join(filepath.Dir(events.DefaultSocketPath()), "control.sock")
+}
+
+// NewControlSocket returns the control socket at path (a leading "~" is
+// expanded), "" for the default path, or nil when path is "off".
+func NewControlSocket(path string) *ControlSocket {
+	switch path {
+	case "off":
+		return nil
+	case "":
+		path = DefaultControlSocketPath()
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return &ControlSocket{Path: path}
+}
+
+// controlMsg is a command read from the control socket; the TUI answers it
+// on reply.
+type controlMsg struct {
+	command string
+	reply   chan<- string
+}
+
+// Listen takes commands on the socket until the returned stop function is
+// called, handing each to send as a controlMsg. A socket another
+// supervisor still listens on is left alone; a stale one is replaced.
+func (c *ControlSocket) Listen(send func(tea.Msg)) (stop func(), err error) {
+	dir := filepath.Dir(c.Path)
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("control socket: %w", err)
+		}
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("control socket: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("control socket: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", c.Path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("control socket: another supervisor is listening on %s", c.Path)
+	}
+	if err := os.Remove(c.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("control socket: remove stale socket: %w", err)
+	}
+	ln, err := net.Listen("unix", c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("control socket: %w", err)
+	}
+	if err := os.Chmod(c.Path, 0o600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("control socket: %w", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveControl(conn, send, done)
+		}
+	}()
+	return func() {
+		close(done)
+		ln.Close()
+	}, nil
+}
+
+// serveControl answers the commands read from conn until it is closed.
+func serveControl(conn net.Conn, send func(tea.Msg), done <-chan struct{}) {
+	defer conn.Close()
+	lines := bufio.NewScanner(conn)
+	for lines.Scan() {
+		command := strings.TrimSpace(lines.Text())
+		if command == "" {
+			continue
+		}
+		reply := make(chan string, 1)
+		send(controlMsg{command: command, reply: reply})
+		var answer string
+		select {
+		case answer = <-reply:
+		case <-time.After(controlReplyTimeout):
+			answer = "error: the supervisor did not answer"
+		case <-done:
+			return
+		}
+		if _, err := fmt.Fprintln(conn, answer); err != nil {
+			return
+		}
+	}
+}
+
+// SendControl sends command to the supervisor listening on the control
+// socket at path and returns its answer. An "error: " answer is returned
+// as an error.
+func SendControl(path, command string) (string, error) {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return "", fmt.Errorf("no supervisor listening on %s: %w", path, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(controlReplyTimeout + time.Second))
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return "", err
+	}
+	answer, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && answer == "" {
+		return "", fmt.Errorf("reading the answer: %w", err)
+	}
+	answer = strings.TrimRight(answer, "\n")
+	if rest, ok := strings.CutPrefix(answer, "error: "); ok {
+		return "", errors.New(rest)
+	}
+	return answer, nil
+}
+
+// runControl runs a command from the control socket as the matching key
+// would and returns its answer.
+func (m *tuiModel) runControl(command string) (string, tea.Cmd) {
+	name, arg, _ := strings.Cut(command, " ")
+	arg = strings.TrimSpace(arg)
+	m.logger().Info("control command", "command", command)
+	var cmd tea.Cmd
+	switch name {
+	case "rescan":
+		// With a target (from the tmux hooks) only that pane is evaluated,
+		// afresh instead of from the cache, whether or not a scan is in
+		// flight. A full rescan requested during a scan follows it, once its
+		// result was applied; further requests are coalesced into that one.
+		if arg != "" {
+			return "ok", m.scanPaneCmd(arg)
+		}
+		if m.scanning {
+			m.scanAgain = true
+			return "ok: queued after the scan in flight", nil
+		}
+		m.scanning = true
+		m.message = ""
+		return "ok", m.doScan()
+	case "jump":
+		if arg == "" {
+			return "error: jump needs a pane target, e.g. jump dev:0.1", nil
+		}
+		if errMsg := jumpToPane(arg); errMsg != "" {
+			m.message = errMsg
+			return "error: " + errMsg, nil
+		}
+		m.selectTarget(arg)
+		return "ok", nil
+	case "toggle-autonudge":
+		m.toggleAutoNudge()
+	case "toggle-dnd":
+		m.toggleDND(time.Now())
+	case "reload":
+		if m.reload == nil {
+			return "error: reloading is not available", nil
+		}
+		cmd = m.reloadConfig()
+		if strings.HasPrefix(m.message, "Config reload failed") {
+			return "error: " + m.message, cmd
+		}
+	case "help":
+		return "ok: " + controlHelp, nil
+	default:
+		return fmt.Sprintf("error: unknown command %q (try help)", name), nil
+	}
+	return "ok: " + m.message, cmd
+}
+
+// selectTarget moves the cursor to the pane with target when it is listed.
+func (m *tuiModel) selectTarget(target string) {
+	for i, item := range m.items {
+		if item.kind == itemPane && m.verdicts[item.paneIdx].Target == target {
+			m.cursor = i
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestControlSocket_AnswersCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "control.sock")
+	c := NewControlSocket(path)
+	var got []string
+	stop, err := c.Listen(func(msg tea.Msg) {
+		cm := msg.(controlMsg)
+		got = append(got, cm.command)
+		if cm.command == "nope" {
+			cm.reply <- "error: unknown command"
+			return
+		}
+		cm.reply <- "ok: done"
+	})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer stop()
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("socket mode: %v (%v)", info.Mode(), err)
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || info.Mode().Perm() != 0o700 {
+		t.Errorf("created directory mode: %v (%v)", info.Mode(), err)
+	}
+	if answer, err := SendControl(path, "rescan"); err != nil || answer != "ok: done" {
+		t.Errorf("rescan: %q, %v", answer, err)
+	}
+	if _, err := SendControl(path, "nope"); err == nil || err.Error() != "unknown command" {
+		t.Errorf("expected the rejection as an error, got %v", err)
+	}
+	if strings.Join(got, ",") != "rescan,nope" {
+		t.Errorf("commands: %v", got)
+	}
+
+	if _, err := c.Listen(func(tea.Msg) {}); err == nil || !strings.Contains(err.Error(), "another supervisor") {
+		t.Errorf("a socket in use should be left alone, got %v", err)
+	}
+	// A directory Listen did not create keeps its mode.
+	shared := t.TempDir()
+	if err := os.Chmod(shared, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stopShared, err := NewControlSocket(filepath.Join(shared, "pane-patrol.sock")).Listen(func(tea.Msg) {})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	stopShared()
+	if info, err := os.Stat(shared); err != nil || info.Mode().Perm() != 0o755 {
+		t.Errorf("existing directory mode changed: %v (%v)", info.Mode(), err)
+	}
+
+	if NewControlSocket("off") != nil {
+		t.Error("off should disable the control socket")
+	}
+}
+
+func TestRunControl(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.autoNudgeMaxRisk = "low"
+
+	if answer, _ := m.runControl("toggle-autonudge"); !m.autoNudge || answer != "ok: Auto-nudge ON (max risk: low)" {
+		t.Errorf("toggle-autonudge: %q (on=%v)", answer, m.autoNudge)
+	}
+	if answer, cmd := m.runControl("rescan"); answer != "ok" || cmd == nil || !m.scanning {
+		t.Errorf("rescan: %q", answer)
+	}
+	if answer, _ := m.runControl("toggle-dnd"); !strings.HasPrefix(answer, "ok: Do not disturb ON") {
+		t.Errorf("toggle-dnd: %q", answer)
+	}
+	for command, want := range map[string]string{
+		"jump":    "error: jump needs a pane target",
+		"reload":  "error: reloading is not available",
+		"explode": `error: unknown command "explode"`,
+		"help":    "ok: commands: rescan",
+	} {
+		if answer, _ := m.runControl(command); !strings.HasPrefix(answer, want) {
+			t.Errorf("%s: got %q, want %q...", command, answer, want)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// Scrollback fetched for the diff viewer: diffHistoryStep lines at first,
+// and another step each time the top is reached, up to maxDiffHistory.
+const (
+	diffHistoryStep = 200
+	maxDiffHistory  = 2000
+)
+
+// diffView is the scrollable viewer for an edit approval (opened with d).
+// It shows the pane's capture including scrollback, so diffs taller than
+// the pane can be reviewed before approving.
+type diffView struct {
+	target    string
+	lines     []string
+	offset    int  // index of the first visible line
+	history   int  // scrollback lines requested so far
+	exhausted bool // the last fetch found no older lines
+	loading   bool
+}
+
+// diffCaptureMsg delivers a capture for the diff viewer.
+type diffCaptureMsg struct {
+	target  string
+	content string
+	history int
+	err     error
+}
+
+// isEditApproval reports whether v is blocked on an edit approval, the
+// dialogs whose content is a diff (Claude Code and Codex).
+func isEditApproval(v model.Verdict) bool {
+	return v.Blocked && v.Reason == "edit approval dialog"
+}
+
+func captureDiffCmd(ctx context.Context, target string, history int) tea.Cmd {
+	return func() tea.Msg {
+		content, err := mux.NewTmux().CapturePaneHistory(ctx, target, history)
+		return diffCaptureMsg{target: target, content: content, history: history, err: err}
+	}
+}
+
+// applyCapture replaces the viewer content. The first capture scrolls to
+// the bottom, where the dialog is; later (longer) captures keep the same
+// lines on screen.
+func (d *diffView) applyCapture(msg diffCaptureMsg, rows int) {
+	lines := strings.Split(strings.TrimRight(msg.content, "\n"), "\n")
+	first := d.lines == nil
+	added := len(lines) - len(d.lines)
+	d.exhausted = !first && added <= 0
+	d.lines = lines
+	d.history = msg.history
+	d.loading = false
+	if first {
+		d.offset = max(len(lines)-rows, 0)
+	} else {
+		d.offset = max(d.offset+added, 0)
+	}
+}
+
+// diffLineKind classifies a line for coloring.
+type diffLineKind int
+
+const (
+	diffContext diffLineKind = iota
+	diffAdded
+	diffRemoved
+	diffHunk
+	diffFile
+)
+
+// classifyDiffLine looks at a line the way the agents render diffs: after
+// any dialog border ("│") and line-number gutter, a leading "+" or "-"
+// marks an added or removed line.
+func classifyDiffLine(line string) diffLineKind {
+	s := strings.TrimLeft(line, " │┃|")
+	if rest := strings.TrimLeft(s, "0123456789"); len(rest) < len(s) && strings.HasPrefix(rest, " ") {
+		s = rest[1:]
+	}
+	switch {
+	case strings.HasPrefix(s, "+++ "), strings.HasPrefix(s, "--- "):
+		return diffFile
+	case strings.HasPrefix(s, "@@"):
+		return diffHunk
+	case strings.HasPrefix(s, "+"):
+		return diffAdded
+	case strings.HasPrefix(s, "-"):
+		return diffRemoved
+	}
+	return diffContext
+}
+
+// diffRows is the number of content lines the viewer shows for actions.
+func (m *tuiModel) diffRows(actions int) int {
+	return max(m.height-actions-6, 3)
+}
+
+// handleDiffViewKey scrolls the viewer, fetches more scrollback at the
+// top, and sends the pane's actions with 1-9.
+func (m *tuiModel) handleDiffViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.diff
+	v := m.verdictByTarget(d.target)
+	var actions []model.Action
+	if v != nil {
+		actions = v.Actions
+	}
+	rows := m.diffRows(len(actions))
+	maxOffset := max(len(d.lines)-rows, 0)
+
+	key := msg.String()
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.diff = nil
+		return m, nil
+	case "up", "k":
+		d.offset--
+	case "down", "j":
+		d.offset++
+	case "pgup", "b":
+		d.offset -= rows
+	case "pgdown", "f", " ":
+		d.offset += rows
+	case "home", "g":
+		d.offset = 0
+	case "end", "G":
+		d.offset = maxOffset
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if i := int(key[0] - '1'); i < len(actions) {
+				m.diff = nil
+				return m, m.sendActionCmd(d.target, actions[i])
+			}
+		}
+		return m, nil
+	}
+
+	if d.offset < 0 && !d.loading && !d.exhausted && d.history < maxDiffHistory {
+		d.offset = 0
+		d.loading = true
+		return m, captureDiffCmd(m.ctx, d.target, min(d.history+diffHistoryStep, maxDiffHistory))
+	}
+	d.offset = min(max(d.offset, 0), maxOffset)
+	return m, nil
+}
+
+// verdictByTarget returns the current verdict for target, or nil.
+func (m *tuiModel) verdictByTarget(target string) *model.Verdict {
+	for i := range m.verdicts {
+		if m.verdicts[i].Target == target {
+			return &m.verdicts[i]
+		}
+	}
+	return nil
+}
+
+// viewDiff renders the visible part of the capture with added lines in
+// the success color, removed lines in the error color, and hunk headers in
+// the info color, followed by the pane's actions.
+func (m *tuiModel) viewDiff() string {
+	d := m.diff
+	v := m.verdictByTarget(d.target)
+	var actions []model.Action
+	if v != nil {
+		actions = v.Actions
+	}
+	rows := m.diffRows(len(actions))
+
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Review edit"))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(d.target))
+	if v != nil && v.WaitingFor != "" {
+		b.WriteString("  ")
+		b.WriteString(m.s.dim.Render(truncate(strings.SplitN(v.WaitingFor, "\n", 2)[0], max(m.width-len(d.target)-16, 10))))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case d.loading && d.lines == nil:
+		b.WriteString(m.s.dim.Render("  capturing..."))
+		b.WriteString("\n")
+	case d.loading:
+		b.WriteString(m.s.dim.Render("  loading more scrollback..."))
+		b.WriteString("\n")
+	case d.offset == 0 && (d.exhausted || d.history >= maxDiffHistory):
+		b.WriteString(m.s.dim.Render("  (start of scrollback)"))
+		b.WriteString("\n")
+	default:
+		b.WriteString(m.s.dim.Render(fmt.Sprintf("  line %d of %d", d.offset+1, len(d.lines))))
+		b.WriteString("\n")
+	}
+
+	end := min(d.offset+rows, len(d.lines))
+	for _, line := range d.lines[min(d.offset, end):end] {
+		line = truncate(line, m.width-1)
+		switch classifyDiffLine(line) {
+		case diffAdded:
+			line = m.s.active.Render(line)
+		case diffRemoved:
+			line = m.s.err.Render(line)
+		case diffHunk:
+			line = m.s.info.Render(line)
+		case diffFile:
+			line = m.s.title.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	for i := end - d.offset; i < rows; i++ {
+		b.WriteString("\n")
+	}
+
+	for i, a := range actions {
+		b.WriteString(fmt.Sprintf("  %d. [%s] %s\n", i+1, a.Risk, a.Label))
+	}
+	b.WriteString(m.styleHints("  ↑↓/pgup/pgdn scroll  g/G top/bottom  1-9 answer  esc close"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func editVerdict() model.Verdict {
+	return model.Verdict{
+		Target:     "api:0.0",
+		Session:    "api",
+		Blocked:    true,
+		Agent:      "claude_code",
+		Reason:     "edit approval dialog",
+		WaitingFor: "Edit — src/main.go",
+		Actions: []model.Action{
+			{Keys: "1", Label: "approve edit", Risk: "medium", Raw: true},
+			{Keys: "2", Label: "reject edit", Risk: "low", Raw: true},
+		},
+	}
+}
+
+func TestClassifyDiffLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want diffLineKind
+	}{
+		{`  +import "os"`, diffAdded},
+		{`   import "fmt"`, diffContext},
+		{`  -	return nil`, diffRemoved},
+		{"  @@ -10,3 +10,4 @@", diffHunk},
+		{"  --- a/src/main.go", diffFile},
+		{"  +++ b/src/main.go", diffFile},
+		{"│ 12 +	os.Exit(1)", diffAdded},
+		{"│ 13 -	return", diffRemoved},
+		{"│ 14  	}", diffContext},
+		{"  2024 was a good year", diffContext},
+	}
+	for _, tt := range tests {
+		if got := classifyDiffLine(tt.line); got != tt.want {
+			t.Errorf("classifyDiffLine(%q) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
+
+func numberedLines(from, to int) string {
+	var lines []string
+	for i := from; i <= to; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestDiffView_ScrollFetchesMoreScrollback(t *testing.T) {
+	m := newTestModel(editVerdict())
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if m.diff == nil || cmd == nil {
+		t.Fatal("d on an edit approval should open the viewer and capture the pane")
+	}
+
+	_, _ = m.Update(diffCaptureMsg{target: "api:0.0", content: numberedLines(101, 200), history: diffHistoryStep})
+	rows := m.diffRows(2)
+	if m.diff.offset != 100-rows {
+		t.Fatalf("first capture should scroll to the bottom: offset %d, want %d", m.diff.offset, 100-rows)
+	}
+	if !strings.Contains(m.View(), "line 200") {
+		t.Errorf("expected last line visible:\n%s", m.View())
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	_, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyUp})
+	if cmd == nil || !m.diff.loading {
+		t.Fatal("scrolling above the top should fetch more scrollback")
+	}
+
+	// Older lines arrive; "line 101" stays at the top of the screen.
+	_, _ = m.Update(diffCaptureMsg{target: "api:0.0", content: numberedLines(1, 200), history: 2 * diffHistoryStep})
+	if got := m.diff.lines[m.diff.offset]; got != "line 101" {
+		t.Errorf("top line after fetch: got %q, want line 101", got)
+	}
+
+	// Nothing older: the viewer stops fetching.
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyUp})
+	_, _ = m.Update(diffCaptureMsg{target: "api:0.0", content: numberedLines(1, 200), history: 3 * diffHistoryStep})
+	if !m.diff.exhausted {
+		t.Fatal("expected exhausted after a fetch with no new lines")
+	}
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyUp}); cmd != nil {
+		t.Error("no fetch once scrollback is exhausted")
+	}
+	if !strings.Contains(m.View(), "start of scrollback") {
+		t.Errorf("expected start marker:\n%s", m.View())
+	}
+}
+
+func TestDiffView_AnswerAndClose(t *testing.T) {
+	m := newTestModel(editVerdict())
+	m.diff = &diffView{target: "api:0.0"}
+	_, _ = m.Update(diffCaptureMsg{target: "api:0.0", content: "  +import \"os\"\n", history: diffHistoryStep})
+	if !strings.Contains(m.View(), "1. [medium] approve edit") {
+		t.Errorf("expected actions in viewer:\n%s", m.View())
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if cmd == nil || m.diff != nil {
+		t.Error("1 should send the first action and close the viewer")
+	}
+}
+
+func TestDiffView_OnlyForEditApprovals(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")}); cmd != nil || m.diff != nil {
+		t.Error("d should not open the viewer for a permission dialog")
+	}
+	if !strings.Contains(m.message, "no edit approval") {
+		t.Errorf("message: got %q", m.message)
+	}
+}
@@ -0,0 +1,234 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// End-to-end flows: keys pressed in the TUI reach a fake pane, which
+// changes its screen, and the next scan (r) shows the new state.
+
+const opencodeWorking = `
+  ▣ Build · claude-sonnet-4-5 · 12s
+
+  ■■■⬝⬝⬝⬝⬝
+
+  esc interrupt
+`
+
+func TestE2E_MultiSelectSubmit(t *testing.T) {
+	screen := func(auth, db string) string {
+		return `
+  ┃  Which features do you need? (select all that apply)
+  ┃
+  ┃  1. [` + auth + `] Authentication
+  ┃  2. [` + db + `] Database
+  ┃  3. [ ] API routes
+  ┃  4. Type your own answer
+  ┃
+  ┃  ↑↓ select  enter toggle  esc dismiss
+  ┃
+`
+	}
+	tmux := &fakeTmux{}
+	tmux.addPane("app:0.0", "opencode", "auth", map[string]string{
+		"auth":    screen("✓", " "),
+		"both":    screen("✓", "✓"),
+		"working": opencodeWorking,
+	}).onKey("auth", "2", "both").onKey("both", "Enter", "working")
+
+	e := newE2E(t, tmux)
+	e.selectPane("app:0.0")
+	v := e.verdict("app:0.0")
+	if !v.Blocked || v.Dialog == nil || !v.Dialog.MultiSelect {
+		t.Fatalf("expected a blocked multi-select dialog, got %+v", v)
+	}
+
+	e.press("2", "r")
+	v = e.verdict("app:0.0")
+	if d := v.Dialog; d == nil || !d.Options[0].Checked || !d.Options[1].Checked {
+		t.Fatalf("option 2 not toggled: %+v", v.Dialog)
+	}
+	if v.Actions[4].Label != "submit selection" {
+		t.Fatalf("action 5 = %q, want submit selection", v.Actions[4].Label)
+	}
+
+	e.selectPane("app:0.0")
+	e.press("5", "r")
+	if v := e.verdict("app:0.0"); v.Blocked {
+		t.Errorf("pane still blocked after submit: %s", v.Reason)
+	}
+	if got := strings.Join(tmux.panes[0].keys, " "); got != "2 Enter" {
+		t.Errorf("keys sent: %q, want %q", got, "2 Enter")
+	}
+	if !strings.Contains(e.m.message, "app:0.0 became active") {
+		t.Errorf("message: %q", e.m.message)
+	}
+}
+
+func TestE2E_TabNavigationAndConfirm(t *testing.T) {
+	tabs := "  ┃   Database      Config      Confirm\n  ┃\n"
+	question := func(q, a, b string) string {
+		return "\n" + tabs + "  ┃  " + q + "\n  ┃\n  ┃  1. " + a + "\n  ┃  2. " + b +
+			"\n  ┃\n  ┃  ⇆ tab  ↑↓ select  enter confirm  esc dismiss\n  ┃\n"
+	}
+	tmux := &fakeTmux{}
+	tmux.addPane("api:0.0", "opencode", "database", map[string]string{
+		"database": question("Which database should I use?", "PostgreSQL", "SQLite"),
+		"config":   question("Which config format?", "YAML", "TOML"),
+		"confirm": "\n" + tabs + "  ┃  Review\n  ┃  Database: PostgreSQL\n  ┃  Config: YAML\n  ┃\n" +
+			"  ┃  ⇆ tab  enter submit  esc dismiss\n  ┃\n",
+		"working": opencodeWorking,
+	}).
+		onKey("database", "Tab", "config").
+		onKey("config", "BTab", "database").
+		onKey("database", "1", "config").
+		onKey("config", "1", "confirm").
+		onKey("confirm", "Enter", "working")
+
+	e := newE2E(t, tmux)
+	e.selectPane("api:0.0")
+	if v := e.verdict("api:0.0"); v.Actions[2].Keys != "Tab" || v.Actions[3].Keys != "BTab" {
+		t.Fatalf("expected tab actions 3 and 4, got %+v", v.Actions)
+	}
+
+	// Tab to the second question and back.
+	e.press("3", "r")
+	if v := e.verdict("api:0.0"); !strings.Contains(v.Dialog.Question, "config format") {
+		t.Fatalf("after Tab: question %q", v.Dialog.Question)
+	}
+	e.selectPane("api:0.0")
+	e.press("4", "r")
+	if v := e.verdict("api:0.0"); !strings.Contains(v.Dialog.Question, "database") {
+		t.Fatalf("after BTab: question %q", v.Dialog.Question)
+	}
+
+	// Answer both questions, then submit on the Confirm tab.
+	e.selectPane("api:0.0")
+	e.press("1", "r")
+	e.selectPane("api:0.0")
+	e.press("1", "r")
+	v := e.verdict("api:0.0")
+	if v.Reason != "question dialog confirm tab" || v.Actions[0].Label != "submit all answers" {
+		t.Fatalf("expected the Confirm tab, got %q %+v", v.Reason, v.Actions)
+	}
+	e.selectPane("api:0.0")
+	e.press("1", "r")
+	if v := e.verdict("api:0.0"); v.Blocked {
+		t.Errorf("pane still blocked after submitting: %s", v.Reason)
+	}
+}
+
+func TestE2E_TextAnswerIsTypedAndSubmitted(t *testing.T) {
+	tmux := &fakeTmux{}
+	p := tmux.addPane("docs:0.0", "opencode", "idle", map[string]string{
+		"idle":    "\n  Previous conversation output...\n\n  > \n",
+		"working": opencodeWorking,
+	}).onKey("idle", "Enter", "working")
+
+	e := newE2E(t, tmux)
+	e.selectPane("docs:0.0")
+	e.press("t")
+	for _, r := range "continue" {
+		e.press(string(r))
+	}
+	e.press("enter", "r")
+	if got := strings.Join(p.keys, "|"); got != "continue|Escape|Enter" {
+		t.Errorf("keys sent: %q", got)
+	}
+	if v := e.verdict("docs:0.0"); v.Blocked {
+		t.Errorf("pane still blocked: %s", v.Reason)
+	}
+}
+
+func TestE2E_OptionCheckedAgainstRerenderedDialog(t *testing.T) {
+	question := func(options ...string) string {
+		s := "\n  ┃  Which package manager?\n  ┃\n"
+		for i, o := range options {
+			s += fmt.Sprintf("  ┃  %d. %s\n", i+1, o)
+		}
+		return s + "  ┃\n  ┃  ↑↓ select  enter confirm  esc dismiss\n  ┃\n"
+	}
+	tmux := &fakeTmux{}
+	p := tmux.addPane("web:0.0", "opencode", "four", map[string]string{
+		"four": question("npm", "pnpm", "yarn", "bun"),
+		"two":  question("npm", "pnpm"),
+	})
+
+	e := newE2E(t, tmux)
+	// The agent re-renders its dialog after the scan.
+	p.state = "two"
+	e.selectPane("web:0.0")
+	e.press("4")
+	if len(p.keys) != 0 {
+		t.Fatalf("keys sent into the changed dialog: %v", p.keys)
+	}
+	if !strings.Contains(e.m.message, "dialog changed, review again") {
+		t.Errorf("message: %q", e.m.message)
+	}
+	if v := e.verdict("web:0.0"); len(v.Dialog.Options) != 2 {
+		t.Errorf("not rescanned: %+v", v.Dialog.Options)
+	}
+}
+
+func TestE2E_CodexQuestionNotes(t *testing.T) {
+	tmux := &fakeTmux{}
+	p := tmux.addPane("svc:0.0", "codex", "question", map[string]string{
+		"question": "\n  Which test runner?\n\n  › 1. Jest\n    2. Vitest\n\n" +
+			"  tab to add notes | enter to submit answer | esc to interrupt\n",
+	})
+
+	e := newE2E(t, tmux)
+	e.selectPane("svc:0.0")
+	if a := e.verdict("svc:0.0").Actions[2]; a.Label != "add notes" {
+		t.Fatalf("action 3 = %q, want add notes", a.Label)
+	}
+	e.press("3")
+	if e.m.textInput == nil || e.m.textInput.after == nil {
+		t.Fatal("add notes should open the text input")
+	}
+	if len(p.keys) != 0 {
+		t.Fatalf("keys sent before the notes were typed: %v", p.keys)
+	}
+	e.press("with", " ", "coverage", "enter")
+	if got := strings.Join(p.keys, "|"); got != "Tab|with coverage|Enter" {
+		t.Errorf("keys sent: %q", got)
+	}
+}
+
+func TestE2E_AutoNudgeCheckedAgainstChangedDialog(t *testing.T) {
+	question := func(q, a, b string) string {
+		return "\n  ┃  " + q + "\n  ┃\n  ┃  1. " + a + "\n  ┃  2. " + b +
+			"\n  ┃\n  ┃  ↑↓ select  enter confirm  esc dismiss\n  ┃\n"
+	}
+	tmux := &fakeTmux{}
+	p := tmux.addPane("web:0.0", "opencode", "asked", map[string]string{
+		"asked":     question("Which package manager?", "npm", "pnpm"),
+		"replaced":  question("Delete the lockfile?", "yes", "no"),
+		"relabeled": question("Which package manager?", "yarn", "pnpm"),
+	})
+
+	e := newE2E(t, tmux)
+	// Another dialog with as many options took the place of the scanned one.
+	p.state = "replaced"
+	e.m.autoNudge = true
+	msg := e.m.autoNudgeCmd()().(nudgeResultMsg)
+	if len(p.keys) != 0 {
+		t.Fatalf("auto-nudge answered a dialog it did not see: %v", p.keys)
+	}
+	if !msg.changed || !strings.Contains(strings.Join(msg.messages, " "), "auto-nudge skipped web:0.0: dialog changed") {
+		t.Errorf("result: %+v", msg)
+	}
+
+	// The same question with another first option.
+	e.m.autoNudge = false
+	p.state = "asked"
+	e.press("r")
+	p.state = "relabeled"
+	e.selectPane("web:0.0")
+	e.press("1")
+	if len(p.keys) != 0 || !strings.Contains(e.m.message, `option 1 is now "yarn"`) {
+		t.Errorf("keys %v, message %q", p.keys, e.m.message)
+	}
+}
@@ -0,0 +1,217 @@
+package supervisor
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// sealedPrefix starts each line encrypted with a StateKey. The salt the
+// key was derived with follows it, so a line can be read with the secret
+// alone.
+const sealedPrefix = "pane-patrol:sealed:v2:"
+
+// Argon2id parameters (RFC 9106's second recommended option) and the salt
+// size.
+const (
+	argonTime    = 3
+	argonMemory  = 64 * 1024 // KiB
+	argonThreads = 4
+	saltSize     = 16
+)
+
+// StateKey encrypts the history and the state files (labels, remembered
+// answers, the verdict cache) at rest with AES-256-GCM, keyed by Argon2id
+// of a secret (PANE_PATROL_STATE_KEY or state_key_command) and a random
+// salt. Each line is sealed on its own, so the history stays append-only,
+// and files written before encryption was turned on are still read. A nil
+// *StateKey writes plain text.
+type StateKey struct {
+	secret string
+	salt   []byte
+	aead   cipher.AEAD
+
+	mu    sync.Mutex
+	salts map[string]cipher.AEAD // keys derived for lines sealed with other salts
+}
+
+// NewStateKey returns the key derived from secret and salt (see
+// LoadStateSalt). Lines sealed with another salt are read with a key
+// derived from theirs.
+func NewStateKey(secret string, salt []byte) (*StateKey, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("state key: the secret is empty")
+	}
+	if len(salt) < saltSize {
+		return nil, fmt.Errorf("state key: the salt is shorter than %d bytes", saltSize)
+	}
+	aead, err := deriveAEAD(secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	return &StateKey{secret: secret, salt: salt, aead: aead, salts: map[string]cipher.AEAD{string(salt): aead}}, nil
+}
+
+func deriveAEAD(secret string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(secret), salt, argonTime, argonMemory, argonThreads, 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("state key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("state key: %w", err)
+	}
+	return aead, nil
+}
+
+// DefaultStateSaltPath returns ~/.config/pane-patrol/state.salt.
+func DefaultStateSaltPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "pane-patrol", "state.salt")
+}
+
+// LoadStateSalt returns the salt stored at path, storing a random one
+// there first if there is none. Sealed lines carry their salt, so losing
+// the file only means new lines are sealed with a new one.
+func LoadStateSalt(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		salt, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+		if err != nil || len(salt) < saltSize {
+			return nil, fmt.Errorf("state salt %s: not a salt", path)
+		}
+		return salt, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("state salt: %w", err)
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("state salt: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("state salt: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(salt)+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("state salt: %w", err)
+	}
+	return salt, nil
+}
+
+// Seal encrypts one line (without its newline).
+func (k *StateKey) Seal(line []byte) []byte {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(fmt.Sprintf("state key: reading random nonce: %v", err))
+	}
+	sealed := k.aead.Seal(nonce, nonce, line, nil)
+	return []byte(sealedPrefix + base64.StdEncoding.EncodeToString(k.salt) + ":" + base64.StdEncoding.EncodeToString(sealed))
+}
+
+// Open decrypts data line by line. Lines that are not sealed are returned
+// as they are. Without a key (nil), sealed data is an error.
+func (k *StateKey) Open(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte(sealedPrefix)) {
+		return data, nil
+	}
+	if k == nil {
+		return nil, fmt.Errorf("encrypted; set PANE_PATROL_STATE_KEY or state_key_command")
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	var out []byte
+	for i, line := range lines {
+		text := bytes.TrimRight(line, "\n")
+		encoded, ok := bytes.CutPrefix(text, []byte(sealedPrefix))
+		if !ok {
+			out = append(out, line...)
+			continue
+		}
+		plain, err := k.openLine(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		out = append(out, plain...)
+		if len(text) < len(line) {
+			out = append(out, '\n')
+		}
+	}
+	return out, nil
+}
+
+// openLine decrypts a sealed line after its prefix: the salt and the
+// nonce and ciphertext, base64-encoded and separated by a colon.
+func (k *StateKey) openLine(encoded []byte) ([]byte, error) {
+	encodedSalt, encodedSealed, ok := bytes.Cut(encoded, []byte(":"))
+	if !ok {
+		return nil, fmt.Errorf("not a sealed line")
+	}
+	salt, err := base64.StdEncoding.DecodeString(string(encodedSalt))
+	if err != nil {
+		return nil, fmt.Errorf("not a sealed line")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(encodedSealed))
+	if err != nil {
+		return nil, fmt.Errorf("not a sealed line")
+	}
+	aead, err := k.saltAEAD(salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("not a sealed line")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("wrong state key or altered data")
+	}
+	return plain, nil
+}
+
+// saltAEAD returns the cipher for lines sealed with salt, deriving it once.
+func (k *StateKey) saltAEAD(salt []byte) (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if aead, ok := k.salts[string(salt)]; ok {
+		return aead, nil
+	}
+	if len(salt) < saltSize {
+		return nil, fmt.Errorf("not a sealed line")
+	}
+	aead, err := deriveAEAD(k.secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	k.salts[string(salt)] = aead
+	return aead, nil
+}
+
+// seal encrypts data, one JSON document or line, for a state file; with
+// no key (nil) it is written as it is.
+func (k *StateKey) seal(data []byte) []byte {
+	if k == nil {
+		return data
+	}
+	return k.Seal(data)
+}
+
+// open decrypts the contents of the state file at path.
+func (k *StateKey) open(path string, data []byte) ([]byte, error) {
+	plain, err := k.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,109 @@
+package supervisor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStateKey_SealsLines(t *testing.T) {
+	salt, err := LoadStateSalt(filepath.Join(t.TempDir(), "state.salt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := NewStateKey("correct horse battery staple", salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := append(append(k.Seal([]byte(`{"a":1}`)), '\n'), []byte("{\"plain\":true}\n")...)
+	data = append(append(data, k.Seal([]byte("{\n  \"b\": 2\n}"))...), '\n')
+	if strings.Contains(string(data), `"a"`) {
+		t.Fatalf("sealed line is readable: %s", data)
+	}
+	plain, err := k.Open(data)
+	if err != nil || string(plain) != "{\"a\":1}\n{\"plain\":true}\n{\n  \"b\": 2\n}\n" {
+		t.Errorf("Open: %q, %v", plain, err)
+	}
+
+	// Lines carry their salt: the secret alone reads them.
+	otherSalt, _ := LoadStateSalt(filepath.Join(t.TempDir(), "state.salt"))
+	if bytes.Equal(salt, otherSalt) {
+		t.Fatal("salts should be random")
+	}
+	same, _ := NewStateKey("correct horse battery staple", otherSalt)
+	if plain, err := same.Open(data); err != nil || !strings.Contains(string(plain), `{"a":1}`) {
+		t.Errorf("another salt: %q, %v", plain, err)
+	}
+	other, _ := NewStateKey("another secret", salt)
+	if _, err := other.Open(data); err == nil || !strings.Contains(err.Error(), "line 1: wrong state key") {
+		t.Errorf("wrong key: %v", err)
+	}
+	var none *StateKey
+	if _, err := none.Open(data); err == nil || !strings.Contains(err.Error(), "PANE_PATROL_STATE_KEY") {
+		t.Errorf("no key: %v", err)
+	}
+	if plain, err := none.Open([]byte("{}\n")); err != nil || string(plain) != "{}\n" {
+		t.Errorf("plain text without a key: %q, %v", plain, err)
+	}
+	if _, err := NewStateKey("", salt); err == nil {
+		t.Error("an empty secret should be refused")
+	}
+}
+
+func TestLoadStateSalt_Stored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pane-patrol", "state.salt")
+	salt, err := LoadStateSalt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := LoadStateSalt(path)
+	if err != nil || !bytes.Equal(salt, again) {
+		t.Errorf("the salt should be stored: %x, then %x (%v)", salt, again, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("the salt file should be private, got %v", err)
+	}
+}
+
+func TestStateKey_HistoryAndLabels(t *testing.T) {
+	dir := t.TempDir()
+	salt, _ := LoadStateSalt(filepath.Join(dir, "state.salt"))
+	k, _ := NewStateKey("secret", salt)
+
+	h := NewHistory(filepath.Join(dir, "history.jsonl"), k)
+	for _, target := range []string{"api:0.0", "web:0.0"} {
+		if err := h.Record(HistoryEntry{Time: time.Now(), Target: target, Action: "rm -rf build"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "history.jsonl"))
+	if strings.Contains(string(data), "rm -rf") || strings.Count(string(data), "\n") != 2 {
+		t.Errorf("history should hold one sealed line per entry:\n%s", data)
+	}
+	if plain, err := k.Open(data); err != nil || strings.Count(string(plain), "rm -rf build") != 2 {
+		t.Errorf("history: %q, %v", plain, err)
+	}
+
+	path := filepath.Join(dir, "labels.json")
+	l, _ := LoadLabels(path, k)
+	l.SetPane("api:0.0", "payments")
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "payments") {
+		t.Errorf("labels are readable:\n%s", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("labels should be private, got %v", err)
+	}
+	if l, err := LoadLabels(path, k); err != nil || l.Pane("api:0.0") != "payments" {
+		t.Errorf("reloaded labels: %v", err)
+	}
+
+	if _, err := LoadLabels(path, nil); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("encrypted labels without a key: %v", err)
+	}
+}
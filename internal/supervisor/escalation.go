@@ -0,0 +1,181 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// Escalation is a second (or later) notification about a pane that stayed
+// blocked: Step is the number of the escalations entry that is due.
+type Escalation struct {
+	Step       int           `json:"step"`
+	Time       time.Time     `json:"time"`
+	Target     string        `json:"target"`
+	BlockedFor time.Duration `json:"-"`
+	// BlockedSeconds is BlockedFor for the command's JSON.
+	BlockedSeconds int           `json:"blocked_seconds"`
+	Verdict        model.Verdict `json:"verdict"`
+}
+
+// String describes e for the status line, e.g. "escalated api:0.1 (blocked
+// 10m)".
+func (e Escalation) String() string {
+	return fmt.Sprintf("escalated %s (blocked %s)", e.Target, formatDuration(e.BlockedFor))
+}
+
+// historyEntry describes e for the history log.
+func (e Escalation) historyEntry() HistoryEntry {
+	return HistoryEntry{
+		Time:    e.Time,
+		Target:  e.Target,
+		Agent:   e.Verdict.Agent,
+		Event:   "escalated",
+		Summary: fmt.Sprintf("step %d after %s: %s", e.Step, formatDuration(e.BlockedFor), e.Verdict.Reason),
+	}
+}
+
+// escalationCmd returns a tea.Cmd that logs escs to the history and runs
+// their commands in the background.
+func (m *tuiModel) escalationCmd(escs []Escalation) tea.Cmd {
+	if len(escs) == 0 {
+		return nil
+	}
+	for _, e := range escs {
+		m.logger().Info("escalation", "target", e.Target, "step", e.Step, "blocked_for", e.BlockedFor.Round(time.Second))
+	}
+	history, escalator, ctx := m.history, m.escalator, m.ctx
+	return func() tea.Msg {
+		var logErr error
+		for _, e := range escs {
+			if err := history.Record(e.historyEntry()); err != nil && logErr == nil {
+				logErr = err
+			}
+		}
+		if err := escalator.Run(ctx, escs); err != nil {
+			return transitionResultMsg{err: err}
+		}
+		return transitionResultMsg{err: logErr}
+	}
+}
+
+// escalationState is a blocked pane's escalation state in the VerdictStore:
+// when it blocked on its current question, the steps already sent, and
+// whether it was reported idle too long (see IdleTooLong).
+type escalationState struct {
+	since        time.Time
+	sent         map[int]bool
+	idleReported bool
+}
+
+// trackBlocked starts a blocked period for panes that blocked or moved on
+// to another question, and ends it for panes that are no longer blocked.
+// s.mu must be held.
+func (s *VerdictStore) trackBlocked(ts []Transition, now time.Time) {
+	if s.escalations == nil {
+		s.escalations = make(map[string]*escalationState)
+	}
+	for _, t := range ts {
+		switch t.Kind {
+		case BecameBlocked, ReasonChanged:
+			s.escalations[t.Target] = &escalationState{since: now, sent: map[int]bool{}}
+		case BecameActive, Disappeared:
+			delete(s.escalations, t.Target)
+		}
+	}
+}
+
+// Escalate returns the escalations due at now, sorted by target, and marks
+// them sent: one per step of e and blocked pane that has been blocked on
+// the same question for at least the step's After and whose recommended
+// action is at least the step's Risk. Each step is sent once per blocked
+// period.
+func (s *VerdictStore) Escalate(e *Escalator, now time.Time) []Escalation {
+	if e == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []Escalation
+	for target, st := range s.escalations {
+		v := s.panes[target]
+		blocked := now.Sub(st.since)
+		for i, step := range e.Steps {
+			if st.sent[i] || blocked < step.AfterDuration || riskOrdinal(paneRisk(v)) < riskOrdinal(step.Risk) {
+				continue
+			}
+			st.sent[i] = true
+			due = append(due, Escalation{Step: i + 1, Time: now, Target: target, BlockedFor: blocked,
+				BlockedSeconds: int(blocked.Seconds()), Verdict: v})
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].Target != due[j].Target {
+			return due[i].Target < due[j].Target
+		}
+		return due[i].Step < due[j].Step
+	})
+	return due
+}
+
+// paneRisk is the risk of v's recommended action, or low when nothing is
+// recommended.
+func paneRisk(v model.Verdict) string {
+	if v.Recommended >= 0 && v.Recommended < len(v.Actions) && v.Actions[v.Recommended].Risk != "" {
+		return v.Actions[v.Recommended].Risk
+	}
+	return "low"
+}
+
+// Escalator runs the escalations commands. A nil *Escalator escalates
+// nothing.
+type Escalator struct {
+	Steps []config.Escalation
+}
+
+// NewEscalator returns an escalator for steps, or nil if there are none.
+func NewEscalator(steps []config.Escalation) *Escalator {
+	if len(steps) == 0 {
+		return nil
+	}
+	return &Escalator{Steps: steps}
+}
+
+// Run runs each escalation's command with the escalation as JSON on stdin,
+// PANE_PATROL_EVENT=escalated, PANE_PATROL_TARGET, PANE_PATROL_ESCALATION
+// (the step number), and PANE_PATROL_BLOCKED_FOR (e.g. "10m"), each for at
+// most hookTimeout. All escalations are attempted; the first error is
+// returned.
+func (e *Escalator) Run(ctx context.Context, escs []Escalation) error {
+	if e == nil {
+		return nil
+	}
+	var first error
+	for _, esc := range escs {
+		data, err := json.Marshal(esc)
+		if err != nil {
+			return err
+		}
+		cmdCtx, cancel := context.WithTimeout(ctx, hookTimeout)
+		cmd := exec.CommandContext(cmdCtx, "sh", "-c", e.Steps[esc.Step-1].Command)
+		cmd.Stdin = strings.NewReader(string(data) + "\n")
+		cmd.Env = append(os.Environ(), "PANE_PATROL_EVENT=escalated", "PANE_PATROL_TARGET="+esc.Target,
+			"PANE_PATROL_ESCALATION="+strconv.Itoa(esc.Step), "PANE_PATROL_BLOCKED_FOR="+formatDuration(esc.BlockedFor))
+		out, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil && first == nil {
+			first = fmt.Errorf("escalation command failed for %s: %w (output: %s)", esc.Target, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return first
+}
@@ -0,0 +1,100 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestVerdictStore_EscalatesOncePerBlockedPeriod(t *testing.T) {
+	e := NewEscalator([]config.Escalation{
+		{AfterDuration: 10 * time.Minute, Command: "slack", Risk: "low"},
+		{AfterDuration: 5 * time.Minute, Command: "sms", Risk: "high"},
+	})
+	risky := blockedVerdict("api:0.0", "api", "permission required")
+	risky.Actions = []model.Action{{Keys: "y", Label: "run rm -rf", Risk: "high"}}
+	calm := blockedVerdict("web:0.0", "web", "idle at prompt")
+
+	var s VerdictStore
+	t0 := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	scan := func(at time.Duration, vs ...model.Verdict) []Escalation {
+		s.Apply(vs, t0.Add(at))
+		return s.Escalate(e, t0.Add(at))
+	}
+	steps := func(escs []Escalation) []string {
+		var out []string
+		for _, esc := range escs {
+			out = append(out, esc.Target+"#"+string(rune('0'+esc.Step)))
+		}
+		return out
+	}
+
+	if got := scan(0, risky, calm); len(got) != 0 {
+		t.Fatalf("nothing is due right away, got %v", steps(got))
+	}
+	// Only the high-risk pane escalates after 5m.
+	if got := steps(scan(6*time.Minute, risky, calm)); strings.Join(got, " ") != "api:0.0#2" {
+		t.Errorf("after 6m: got %v", got)
+	}
+	got := scan(11*time.Minute, risky, calm)
+	if strings.Join(steps(got), " ") != "api:0.0#1 web:0.0#1" || got[0].BlockedFor != 11*time.Minute {
+		t.Errorf("after 11m: got %v", steps(got))
+	}
+	if got := scan(30*time.Minute, risky, calm); len(got) != 0 {
+		t.Errorf("each step is sent once, got %v", steps(got))
+	}
+
+	// Moving on to another question starts a new blocked period; so does
+	// blocking again after working.
+	risky.Reason = "edit approval"
+	calm.Blocked = false
+	scan(31*time.Minute, risky, calm)
+	calm.Blocked = true
+	scan(32*time.Minute, risky, calm)
+	if got := steps(scan(42*time.Minute, risky, calm)); strings.Join(got, " ") != "api:0.0#1 api:0.0#2 web:0.0#1" {
+		t.Errorf("after a new blocked period: got %v", got)
+	}
+
+	if s.Escalate(nil, t0.Add(time.Hour)) != nil {
+		t.Error("a nil escalator escalates nothing")
+	}
+}
+
+func TestEscalator_RunsStepCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "escalations")
+	e := NewEscalator([]config.Escalation{
+		{Command: "exit 1"},
+		{Command: `{ printf '%s %s %s %s ' "$PANE_PATROL_EVENT" "$PANE_PATROL_TARGET" "$PANE_PATROL_ESCALATION" "$PANE_PATROL_BLOCKED_FOR"; cat; } >> ` + out},
+	})
+	esc := Escalation{Step: 2, Target: "api:0.0", BlockedFor: 12 * time.Minute, BlockedSeconds: 720,
+		Verdict: blockedVerdict("api:0.0", "api", "permission required")}
+	if err := e.Run(context.Background(), []Escalation{esc}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix, payload, _ := strings.Cut(string(data), " {")
+	if prefix != "escalated api:0.0 2 12m" {
+		t.Errorf("env: got %q", prefix)
+	}
+	var got Escalation
+	if err := json.Unmarshal([]byte("{"+payload), &got); err != nil || got.BlockedSeconds != 720 || got.Verdict.Reason != "permission required" {
+		t.Errorf("stdin: %q (%v)", payload, err)
+	}
+
+	if err := e.Run(context.Background(), []Escalation{{Step: 1, Target: "api:0.0"}}); err == nil || !strings.Contains(err.Error(), "escalation command failed") {
+		t.Errorf("expected the failing command reported, got %v", err)
+	}
+	if NewEscalator(nil) != nil {
+		t.Error("no escalations should disable the escalator")
+	}
+}
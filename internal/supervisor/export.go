@@ -0,0 +1,143 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// exportFile is the JSON export format.
+type exportFile struct {
+	ExportedAt time.Time         `json:"exported_at"`
+	Verdicts   []model.Verdict   `json:"verdicts"`
+	Notes      map[string]string `json:"notes,omitempty"` // pane notes by target
+}
+
+// ExportVerdicts writes verdicts to dir as pane-patrol-<timestamp>.json (all
+// verdicts, as scan prints them) and pane-patrol-<timestamp>.md (blocked
+// panes with their questions and actions, for handing off supervision or
+// attaching to an incident), including the pane notes (by target) given.
+// dir is created if needed; "" is the current
+// directory and a leading "~" is expanded. The files may contain pane
+// content, so they are only readable by the owner. It returns the paths
+// written.
+func ExportVerdicts(dir string, verdicts []model.Verdict, notes map[string]string, now time.Time) ([]string, error) {
+	dir, err := makeExportDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Join(dir, "pane-patrol-"+now.Format("20060102-150405"))
+
+	if verdicts == nil {
+		verdicts = []model.Verdict{}
+	}
+	data, err := json.MarshalIndent(exportFile{ExportedAt: now, Verdicts: verdicts, Notes: notes}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{base + ".json", base + ".md"}
+	if err := os.WriteFile(paths[0], append(data, '\n'), 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(paths[1], []byte(exportMarkdown(verdicts, notes, now)), 0o600); err != nil {
+		return paths[:1], err
+	}
+	return paths, nil
+}
+
+// makeExportDir creates the export directory dir ("" is the current
+// directory, a leading "~" is expanded) and returns its path.
+func makeExportDir(dir string) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, rest)
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create export directory: %w", err)
+	}
+	return dir, nil
+}
+
+// exportMarkdown renders blocked panes in detail followed by a table of the
+// other agent panes. Non-agent panes are left out.
+func exportMarkdown(verdicts []model.Verdict, notes map[string]string, now time.Time) string {
+	var blocked, other []model.Verdict
+	for _, v := range verdicts {
+		switch {
+		case v.Agent == "not_an_agent" || v.Agent == "":
+		case v.Blocked:
+			blocked = append(blocked, v)
+		default:
+			other = append(other, v)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# pane-patrol export %s\n\n", now.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(&b, "%d agent panes, %d blocked.\n", len(blocked)+len(other), len(blocked))
+
+	if len(blocked) > 0 {
+		b.WriteString("\n## Blocked\n")
+	}
+	for _, v := range blocked {
+		fmt.Fprintf(&b, "\n### %s (%s)\n\n", v.Target, v.Agent)
+		if v.Path != "" {
+			fmt.Fprintf(&b, "Directory: `%s`\n\n", v.Path)
+		}
+		if !v.LastActivity.IsZero() {
+			fmt.Fprintf(&b, "Blocked for: %s\n\n", formatDuration(now.Sub(v.LastActivity)))
+		}
+		if note := notes[v.Target]; note != "" {
+			fmt.Fprintf(&b, "Note: %s\n\n", note)
+		}
+		fmt.Fprintf(&b, "%s\n", v.Reason)
+		if text := copyText(v); text != "" {
+			fmt.Fprintf(&b, "\n```\n%s\n```\n", strings.TrimRight(text, "\n"))
+		}
+		if len(v.Actions) > 0 {
+			b.WriteString("\nActions:\n\n")
+		}
+		for i, a := range v.Actions {
+			fmt.Fprintf(&b, "%d. %s (`%s`, %s risk)", i+1, a.Label, a.Keys, a.Risk)
+			if i == v.Recommended {
+				b.WriteString(" (recommended)")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(other) > 0 {
+		b.WriteString("\n## Other agent panes\n\n")
+		b.WriteString("| Pane | Agent | Status | Note |\n|------|-------|--------|------|\n")
+		for _, v := range other {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", v.Target, v.Agent,
+				strings.ReplaceAll(v.Reason, "|", `\|`), strings.ReplaceAll(notes[v.Target], "|", `\|`))
+		}
+	}
+	return b.String()
+}
+
+// exportCmd writes the current verdicts to the export directory in the
+// background.
+func (m *tuiModel) exportCmd() tea.Cmd {
+	verdicts := append([]model.Verdict(nil), m.verdicts...)
+	notes := m.labels.NotesFor(verdicts)
+	dir := m.exportDir
+	return func() tea.Msg {
+		paths, err := ExportVerdicts(dir, verdicts, notes, time.Now())
+		if err != nil {
+			return nudgeResultMsg{messages: []string{fmt.Sprintf("export failed: %v", err)}}
+		}
+		return nudgeResultMsg{messages: []string{fmt.Sprintf("exported %d panes to %s", len(verdicts), strings.Join(paths, ", "))}}
+	}
+}
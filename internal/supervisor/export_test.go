@@ -0,0 +1,87 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestExportVerdicts(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "exports")
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	blocked := simpleVerdict()
+	blocked.WaitingFor = "Allow bash: rm -rf build?"
+	verdicts := []model.Verdict{
+		blocked,
+		{Target: "test:0.1", Agent: "codex", Reason: "working"},
+		{Target: "test:0.2", Agent: "not_an_agent", Reason: "shell"},
+	}
+
+	paths, err := ExportVerdicts(dir, verdicts, map[string]string{"test:0.0": "deny anything touching prod"}, now)
+	if err != nil {
+		t.Fatalf("ExportVerdicts: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "pane-patrol-20260304-050607.json"),
+		filepath.Join(dir, "pane-patrol-20260304-050607.md"),
+	}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("paths: got %v, want %v", paths, want)
+	}
+
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got exportFile
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Verdicts) != 3 || !got.ExportedAt.Equal(now) || got.Verdicts[0].WaitingFor != blocked.WaitingFor ||
+		got.Notes["test:0.0"] != "deny anything touching prod" {
+		t.Errorf("unexpected JSON export: %+v", got)
+	}
+	if info, _ := os.Stat(paths[0]); info.Mode().Perm() != 0o600 {
+		t.Errorf("export mode: got %v, want 0600", info.Mode().Perm())
+	}
+
+	md, err := os.ReadFile(paths[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"2 agent panes, 1 blocked.",
+		"### test:0.0 (opencode)",
+		"Allow bash: rm -rf build?",
+		"1. allow once (`Enter`, medium risk) (recommended)",
+		"2. dismiss (`Escape`, low risk)\n",
+		"Note: deny anything touching prod",
+		"| test:0.1 | codex | working |  |",
+	} {
+		if !strings.Contains(string(md), s) {
+			t.Errorf("markdown missing %q:\n%s", s, md)
+		}
+	}
+	if strings.Contains(string(md), "test:0.2") {
+		t.Errorf("markdown should leave out non-agent panes:\n%s", md)
+	}
+}
+
+func TestExportKey(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.exportDir = t.TempDir()
+	_, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if cmd == nil {
+		t.Fatal("expected e to export")
+	}
+	msg, ok := cmd().(nudgeResultMsg)
+	if !ok || len(msg.messages) != 1 || !strings.HasPrefix(msg.messages[0], "exported 1 panes to "+m.exportDir) {
+		t.Errorf("unexpected result: %+v", msg)
+	}
+}
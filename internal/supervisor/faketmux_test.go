@@ -0,0 +1,215 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// fakeTmux is an in-memory tmux for end-to-end TUI tests: a multiplexer
+// whose panes switch screens in response to the keys a Nudger sends them,
+// so flows can be driven from keypress to rescan without a tmux server.
+type fakeTmux struct {
+	mu    sync.Mutex
+	panes []*fakePane
+}
+
+// fakePane is a simulated pane: a state machine over captured screens.
+type fakePane struct {
+	model.Pane
+	state   string
+	screens map[string]string            // state -> captured content
+	on      map[string]map[string]string // state -> key -> next state
+	keys    []string                     // every key received, in order
+}
+
+// addPane adds a pane running command in session, showing screens[state].
+func (f *fakeTmux) addPane(target, command, state string, screens map[string]string) *fakePane {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	session, _, _ := strings.Cut(target, ":")
+	p := &fakePane{
+		Pane:    model.Pane{Target: target, Session: session, PID: 1000 + len(f.panes), Command: command, ProcessTree: []string{command}},
+		state:   state,
+		screens: screens,
+		on:      make(map[string]map[string]string),
+	}
+	f.panes = append(f.panes, p)
+	return p
+}
+
+// onKey makes key switch the pane from state from to state to.
+func (p *fakePane) onKey(from, key, to string) *fakePane {
+	if p.on[from] == nil {
+		p.on[from] = make(map[string]string)
+	}
+	p.on[from][key] = to
+	return p
+}
+
+func (f *fakeTmux) pane(target string) (*fakePane, error) {
+	for _, p := range f.panes {
+		if p.Target == target {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("can't find pane: %s", target)
+}
+
+func (f *fakeTmux) Name() string { return "fake" }
+
+func (f *fakeTmux) ListPanes(context.Context, string) ([]model.Pane, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	panes := make([]model.Pane, len(f.panes))
+	for i, p := range f.panes {
+		panes[i] = p.Pane
+	}
+	return panes, nil
+}
+
+func (f *fakeTmux) CapturePane(_ context.Context, target string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, err := f.pane(target)
+	if err != nil {
+		return "", err
+	}
+	return p.screens[p.state], nil
+}
+
+// sendKeys is the fake's SendKeysFunc. Literal text (-l) is one key.
+func (f *fakeTmux) sendKeys(target, _, keys string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, err := f.pane(target)
+	if err != nil {
+		return err
+	}
+	p.keys = append(p.keys, keys)
+	if next, ok := p.on[p.state][keys]; ok {
+		p.state = next
+	}
+	return nil
+}
+
+func (f *fakeTmux) paste(target, text string) error {
+	return f.sendKeys(target, "", text)
+}
+
+// nudger returns a Nudger that sends keys to the fake without delays.
+func (f *fakeTmux) nudger() *Nudger {
+	return &Nudger{SendKeys: f.sendKeys, Paste: f.paste, Sleep: func(time.Duration) {}}
+}
+
+// e2e drives a TUI model over a fakeTmux, running the model's commands
+// synchronously the way the bubbletea runtime would.
+type e2e struct {
+	t    *testing.T
+	m    *tuiModel
+	tmux *fakeTmux
+}
+
+func newE2E(t *testing.T, tmux *fakeTmux) *e2e {
+	t.Helper()
+	e := &e2e{t: t, tmux: tmux, m: &tuiModel{
+		s:                newStyles(DarkTheme()),
+		scanner:          &Scanner{Mux: tmux, Parsers: parser.NewRegistry(), Parallel: 1},
+		ctx:              context.Background(),
+		expanded:         make(map[string]bool),
+		manualCollapsed:  make(map[string]bool),
+		autoNudgeMaxRisk: "low",
+		nudger:           tmux.nudger(),
+		width:            120,
+		height:           40,
+	}}
+	e.run(e.m.Init())
+	return e
+}
+
+// run executes cmd and feeds its messages back into the model until no
+// commands are left.
+func (e *e2e) run(cmd tea.Cmd) {
+	e.t.Helper()
+	for depth := 0; cmd != nil; depth++ {
+		if depth > 20 {
+			e.t.Fatal("command chain did not settle")
+		}
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			// Like the runtime, run batched commands concurrently (a scan's
+			// progress waiter blocks until the scan sends), but update the
+			// model from this goroutine only.
+			msgs := make(chan tea.Cmd, len(batch))
+			for _, c := range batch {
+				go func() {
+					if c == nil {
+						msgs <- nil
+						return
+					}
+					msg := c()
+					msgs <- func() tea.Msg { return msg }
+				}()
+			}
+			for range batch {
+				e.run(<-msgs)
+			}
+			return
+		}
+		if msg == nil {
+			return
+		}
+		_, cmd = e.m.Update(msg)
+	}
+}
+
+// press sends keys as typed, e.g. "j", "enter", "5".
+func (e *e2e) press(keys ...string) {
+	e.t.Helper()
+	for _, k := range keys {
+		var msg tea.KeyMsg
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEscape}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		_, cmd := e.m.handleKey(msg)
+		e.run(cmd)
+	}
+}
+
+// selectPane moves the cursor to target's row.
+func (e *e2e) selectPane(target string) {
+	e.t.Helper()
+	for i, item := range e.m.items {
+		if item.kind == itemPane && e.m.verdicts[item.paneIdx].Target == target {
+			e.m.cursor = i
+			return
+		}
+	}
+	e.t.Fatalf("pane %s not listed", target)
+}
+
+// verdict returns target's verdict from the latest scan.
+func (e *e2e) verdict(target string) model.Verdict {
+	e.t.Helper()
+	for _, v := range e.m.verdicts {
+		if v.Target == target {
+			return v
+		}
+	}
+	e.t.Fatalf("no verdict for %s", target)
+	return model.Verdict{}
+}
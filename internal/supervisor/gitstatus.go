@@ -0,0 +1,135 @@
+package supervisor
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// gitTimeout bounds one git status call, so a huge or hung repository
+// cannot stall a scan.
+const gitTimeout = 2 * time.Second
+
+// maxBranchLen caps the branch shown after a pane's name in the list.
+const maxBranchLen = 24
+
+// GitStatus looks up the branch and dirty state of panes' working
+// directories (git_status). Each directory's result is cached for TTL, so a
+// scan runs git at most once per directory and interval. A nil *GitStatus
+// looks up nothing; it is safe for concurrent use.
+type GitStatus struct {
+	TTL time.Duration
+	// Status runs git status in dir and returns its output; nil runs git.
+	Status func(ctx context.Context, dir string) (string, error)
+
+	mu      sync.Mutex
+	entries map[string]gitEntry // by directory
+}
+
+type gitEntry struct {
+	branch string // "" when dir is not in a repository
+	dirty  bool
+	at     time.Time
+}
+
+// NewGitStatus returns a GitStatus caching results for ttl, or nil when ttl
+// is 0 (git_status: off).
+func NewGitStatus(ttl time.Duration) *GitStatus {
+	if ttl <= 0 {
+		return nil
+	}
+	return &GitStatus{TTL: ttl}
+}
+
+// Enrich sets v's GitBranch and GitDirty from its working directory. Panes
+// without an agent are left alone.
+func (g *GitStatus) Enrich(ctx context.Context, v *model.Verdict) {
+	if g == nil || v.Path == "" || !tracked(*v) {
+		return
+	}
+	v.GitBranch, v.GitDirty = g.Lookup(ctx, v.Path, time.Now())
+}
+
+// Lookup returns the branch checked out in dir ("" outside a repository, a
+// short commit hash when detached) and whether it has uncommitted or
+// untracked changes, from the cache when the result is younger than TTL.
+func (g *GitStatus) Lookup(ctx context.Context, dir string, now time.Time) (string, bool) {
+	g.mu.Lock()
+	e, ok := g.entries[dir]
+	g.mu.Unlock()
+	if ok && now.Sub(e.at) < g.TTL {
+		return e.branch, e.dirty
+	}
+
+	status := g.Status
+	if status == nil {
+		status = runGitStatus
+	}
+	e = gitEntry{at: now}
+	if out, err := status(ctx, dir); err == nil {
+		e.branch, e.dirty = parseGitStatus(out)
+	}
+	g.mu.Lock()
+	if g.entries == nil {
+		g.entries = make(map[string]gitEntry)
+	}
+	g.entries[dir] = e
+	g.mu.Unlock()
+	return e.branch, e.dirty
+}
+
+// runGitStatus runs git status in porcelain v2 format with the branch
+// header. GIT_OPTIONAL_LOCKS=0 keeps it from taking the index lock, which
+// would make an agent's own git commands fail.
+func runGitStatus(ctx context.Context, dir string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "status", "--porcelain=v2", "--branch")
+	cmd.Env = append(os.Environ(), "GIT_OPTIONAL_LOCKS=0")
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// parseGitStatus reads the branch and dirty state from git status
+// --porcelain=v2 --branch output: "# branch.head" names the branch
+// ("(detached)" when detached, then "# branch.oid" is used), and any line
+// that is not a header is a change.
+func parseGitStatus(out string) (branch string, dirty bool) {
+	var oid string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			branch = strings.TrimPrefix(line, "# branch.head ")
+		case strings.HasPrefix(line, "# branch.oid "):
+			oid = strings.TrimPrefix(line, "# branch.oid ")
+		case strings.HasPrefix(line, "#"), line == "":
+		default:
+			dirty = true
+		}
+	}
+	if branch == "(detached)" && len(oid) >= 7 {
+		branch = oid[:7]
+	}
+	return branch, dirty
+}
+
+// gitLabel describes v's branch for the list, e.g. "feature/auth ✗" when
+// it has uncommitted changes, or "" outside a repository.
+func (m *tuiModel) gitLabel(v model.Verdict) string {
+	if v.GitBranch == "" {
+		return ""
+	}
+	label := truncate(v.GitBranch, maxBranchLen)
+	if v.GitDirty {
+		label += " " + m.glyphs().dirty
+	}
+	return label
+}
@@ -0,0 +1,109 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestParseGitStatus(t *testing.T) {
+	for _, tc := range []struct {
+		name, out, branch string
+		dirty             bool
+	}{
+		{"clean", "# branch.oid 1234567890abcdef\n# branch.head main\n# branch.upstream origin/main\n", "main", false},
+		{"modified", "# branch.oid 1234567890abcdef\n# branch.head feature/auth\n1 .M N... 100644 100644 100644 abc abc go.mod\n", "feature/auth", true},
+		{"untracked", "# branch.head main\n? notes.txt\n", "main", true},
+		{"detached", "# branch.oid 1234567890abcdef\n# branch.head (detached)\n", "1234567", false},
+	} {
+		branch, dirty := parseGitStatus(tc.out)
+		if branch != tc.branch || dirty != tc.dirty {
+			t.Errorf("%s: got %q/%v, want %q/%v", tc.name, branch, dirty, tc.branch, tc.dirty)
+		}
+	}
+}
+
+func TestGitStatus_CachesPerDirectory(t *testing.T) {
+	calls := map[string]int{}
+	g := &GitStatus{TTL: 30 * time.Second, Status: func(_ context.Context, dir string) (string, error) {
+		calls[dir]++
+		if dir == "/tmp" {
+			return "", errors.New("not a git repository")
+		}
+		return "# branch.head main\n? new.go\n", nil
+	}}
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	for range 3 {
+		if branch, dirty := g.Lookup(ctx, "/src/app", now); branch != "main" || !dirty {
+			t.Fatalf("got %q/%v", branch, dirty)
+		}
+	}
+	if branch, _ := g.Lookup(ctx, "/tmp", now); branch != "" {
+		t.Errorf("outside a repository: got %q", branch)
+	}
+	g.Lookup(ctx, "/tmp", now.Add(time.Second))
+	if calls["/src/app"] != 1 || calls["/tmp"] != 1 {
+		t.Errorf("each directory should be looked up once per TTL: %v", calls)
+	}
+	g.Lookup(ctx, "/src/app", now.Add(31*time.Second))
+	if calls["/src/app"] != 2 {
+		t.Errorf("an expired entry should be looked up again: %v", calls)
+	}
+
+	var off *GitStatus
+	v := model.Verdict{Agent: "claude_code", Path: "/src/app"}
+	off.Enrich(ctx, &v)
+	if v.GitBranch != "" || NewGitStatus(0) != nil {
+		t.Error("git_status: off should look up nothing")
+	}
+	shell := model.Verdict{Path: "/src/app"}
+	g.Enrich(ctx, &shell)
+	if shell.GitBranch != "" {
+		t.Error("panes without an agent should not be looked up")
+	}
+}
+
+func TestGitStatus_RunsGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "-b", "feature/auth", dir).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v (%s)", err, out)
+	}
+	g := NewGitStatus(time.Minute)
+	if branch, dirty := g.Lookup(context.Background(), dir, time.Now()); branch != "feature/auth" || dirty {
+		t.Errorf("empty repository: got %q/%v", branch, dirty)
+	}
+	if branch, _ := g.Lookup(context.Background(), t.TempDir(), time.Now()); branch != "" {
+		t.Errorf("outside a repository: got %q", branch)
+	}
+}
+
+func TestGitLabel_ShownAfterPaneName(t *testing.T) {
+	v := simpleVerdict()
+	v.GitBranch, v.GitDirty = "feature/auth", true
+	m := newTestModel(v)
+	if name := m.paneDisplayName(v); !strings.HasSuffix(name, " (feature/auth ✗)") {
+		t.Errorf("got %q", name)
+	}
+	m.accessible = true
+	if name := m.paneDisplayName(v); !strings.HasSuffix(name, " (feature/auth modified)") {
+		t.Errorf("accessible: got %q", name)
+	}
+	v.GitBranch = strings.Repeat("x", 40)
+	if name := m.paneDisplayName(v); !strings.Contains(name, strings.Repeat("x", maxBranchLen-3)+"...") {
+		t.Errorf("long branches should be truncated: %q", name)
+	}
+	tr := Transition{Kind: BecameBlocked, Target: "dev:0.1", Verdict: model.Verdict{GitBranch: "feature/auth", Reason: "permission required"}}
+	if got := tr.String(); got != "dev:0.1 (feature/auth) became blocked: permission required" {
+		t.Errorf("transition: got %q", got)
+	}
+}
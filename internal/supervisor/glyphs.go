@@ -0,0 +1,63 @@
+package supervisor
+
+// glyphs are the symbols the TUI draws for states, markers, and rules.
+// The accessible set spells states out in ASCII words and avoids box
+// drawing: screen readers skip or mispronounce symbols, and dumb terminals
+// cannot draw them. It also marks the selected row with text, since the
+// highlight alone is a color-only signal.
+type glyphs struct {
+	blocked, active, failed, idle string // pane and session state
+	expanded, collapsed           string // session rows
+	pointer                       string // before the selected list row; "" when the highlight shows it
+	cursor                        string // before the selected entry of pickers and screens
+	rule                          string // repeated into horizontal separators
+	border                        string // between the list and the sidebar
+	warning                       string // before warnings
+	recommended                   string // marks the recommended action
+	note                          string // before pane notes
+	dirty                         string // after the branch of a pane with uncommitted changes
+	activity                      string // before the output above a blocked pane's dialog
+}
+
+var unicodeGlyphs = glyphs{
+	blocked:     "⚠",
+	active:      "✓",
+	failed:      "✗",
+	idle:        "·",
+	expanded:    "▼",
+	collapsed:   "▶",
+	cursor:      "▸ ",
+	rule:        "─",
+	border:      "│ ",
+	warning:     "⚠",
+	recommended: "★",
+	note:        "✎",
+	dirty:       "✗",
+	activity:    "↳",
+}
+
+var accessibleGlyphs = glyphs{
+	blocked:     "BLOCKED",
+	active:      "active",
+	failed:      "ERROR",
+	idle:        "-",
+	expanded:    "[-]",
+	collapsed:   "[+]",
+	pointer:     ">",
+	cursor:      "> ",
+	rule:        "-",
+	border:      "| ",
+	warning:     "WARNING:",
+	recommended: "*",
+	note:        "Note:",
+	dirty:       "modified",
+	activity:    "Before:",
+}
+
+// glyphs returns the glyphs for the TUI's mode.
+func (m *tuiModel) glyphs() *glyphs {
+	if m.accessible {
+		return &accessibleGlyphs
+	}
+	return &unicodeGlyphs
+}
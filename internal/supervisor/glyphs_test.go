@@ -0,0 +1,31 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAccessible_ListUsesWordsAndASCII(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.accessible = true
+	view := m.View()
+	for _, symbol := range []string{"⚠", "✓", "▼", "▶", "─", "★"} {
+		if strings.Contains(view, symbol) {
+			t.Errorf("accessible view contains %q:\n%s", symbol, view)
+		}
+	}
+	if !strings.Contains(view, "> BLOCKED") {
+		t.Errorf("selected pane should read \"> BLOCKED\":\n%s", view)
+	}
+	if !strings.Contains(view, "[-]") {
+		t.Errorf("expanded session should read \"[-]\":\n%s", view)
+	}
+}
+
+func TestAccessible_DefaultKeepsSymbols(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	view := m.View()
+	if !strings.Contains(view, "⚠") || strings.Contains(view, "BLOCKED") {
+		t.Errorf("default view should use symbols:\n%s", view)
+	}
+}
@@ -0,0 +1,124 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// groupMode controls how panes are grouped in the list.
+type groupMode int
+
+const (
+	groupBySession   groupMode = iota // tmux session (default)
+	groupByDirectory                  // working directory of the pane
+	groupByRepo                       // git repository or worktree root of the working directory
+)
+
+// parseGroupMode maps the group_by setting to a groupMode; anything
+// unrecognized groups by session.
+func parseGroupMode(name string) groupMode {
+	switch name {
+	case "directory":
+		return groupByDirectory
+	case "repo":
+		return groupByRepo
+	}
+	return groupBySession
+}
+
+func (g groupMode) String() string {
+	switch g {
+	case groupByDirectory:
+		return "directory"
+	case groupByRepo:
+		return "repo"
+	}
+	return "session"
+}
+
+func (g groupMode) next() groupMode {
+	return (g + 1) % 3
+}
+
+// noPathGroup collects panes whose working directory is unknown (e.g.
+// verdicts from hook events).
+const noPathGroup = "(unknown directory)"
+
+// maxGroupNameLen caps directory group names in the list; longer paths keep
+// their last components.
+const maxGroupNameLen = 40
+
+// groupKey returns the name of the group v belongs to.
+func (m *tuiModel) groupKey(v model.Verdict) string {
+	switch m.groupBy {
+	case groupByDirectory:
+		if v.Path == "" {
+			return noPathGroup
+		}
+		return v.Path
+	case groupByRepo:
+		if v.Path == "" {
+			return noPathGroup
+		}
+		return m.repoRoot(v.Path)
+	}
+	return v.Session
+}
+
+// repoRoot returns the closest directory at or above dir that contains a
+// .git entry (a directory for a repository, a file for a worktree), or dir
+// itself when it is not inside one. Results are memoized per directory.
+func (m *tuiModel) repoRoot(dir string) string {
+	if root, ok := m.repoRoots[dir]; ok {
+		return root
+	}
+	root := dir
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			root = d
+			break
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+	if m.repoRoots == nil {
+		m.repoRoots = map[string]string{}
+	}
+	m.repoRoots[dir] = root
+	return root
+}
+
+// groupDisplayName is a group's name in the list: the session (with its
+// label), or the directory with the home directory shortened to "~".
+func (m *tuiModel) groupDisplayName(name string) string {
+	if name == pinnedGroup {
+		return "pinned"
+	}
+	if m.groupBy == groupBySession {
+		return m.sessionDisplayName(name)
+	}
+	return shortenPath(name, maxGroupNameLen)
+}
+
+// shortenPath replaces the home directory with "~" and, when still longer
+// than maxLen columns, keeps the last maxLen-1 columns after "…".
+func shortenPath(path string, maxLen int) string {
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if path == home {
+			path = "~"
+		} else if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+			path = "~/" + rest
+		}
+	}
+	if width := runewidth.StringWidth(path); width > maxLen {
+		path = runewidth.TruncateLeft(path, width-maxLen+1, "…")
+	}
+	return path
+}
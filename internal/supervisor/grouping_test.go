@@ -0,0 +1,117 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestRepoRoot(t *testing.T) {
+	base := t.TempDir()
+	repo := filepath.Join(base, "repo")
+	worktree := filepath.Join(base, "repo-feature")
+	plain := filepath.Join(base, "plain", "sub")
+	for _, dir := range []string{filepath.Join(repo, ".git"), filepath.Join(repo, "cmd", "app"), filepath.Join(worktree, "internal"), plain} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A worktree has a .git file pointing at the main repository.
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: ../repo/.git/worktrees/feature\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &tuiModel{}
+	tests := map[string]string{
+		repo:                                repo,
+		filepath.Join(repo, "cmd", "app"):   repo,
+		filepath.Join(worktree, "internal"): worktree,
+		plain:                               plain,
+	}
+	for dir, want := range tests {
+		if got := m.repoRoot(dir); got != want {
+			t.Errorf("repoRoot(%s) = %s, want %s", dir, got, want)
+		}
+	}
+}
+
+func TestShortenPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	if got := shortenPath(filepath.Join(home, "src", "app"), 40); got != "~/src/app" {
+		t.Errorf("home prefix: got %q", got)
+	}
+	long := "/srv/" + strings.Repeat("x", 50) + "/project"
+	got := shortenPath(long, 20)
+	if len([]rune(got)) != 20 || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "/project") {
+		t.Errorf("long path: got %q", got)
+	}
+}
+
+func groupingTestModel() *tuiModel {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "a:0.0", Session: "a", Agent: "claude_code", Blocked: true, Reason: "idle", Path: "/work/api"},
+			{Target: "b:0.0", Session: "b", Agent: "codex", Blocked: true, Reason: "idle", Path: "/work/api"},
+			{Target: "c:0.0", Session: "c", Agent: "codex", Blocked: true, Reason: "idle"},
+		},
+		expanded:        map[string]bool{},
+		manualCollapsed: map[string]bool{},
+		width:           120,
+		height:          40,
+	}
+	m.rebuildGroups()
+	return m
+}
+
+func TestGroupBy_CycleWithG(t *testing.T) {
+	m := groupingTestModel()
+	if len(m.groups) != 3 {
+		t.Fatalf("by session: got %d groups, want 3", len(m.groups))
+	}
+
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m.groupBy != groupByDirectory || m.message != "Group: directory" {
+		t.Fatalf("expected directory grouping, got %v (%q)", m.groupBy, m.message)
+	}
+	if len(m.groups) != 2 {
+		t.Fatalf("by directory: got %d groups, want 2", len(m.groups))
+	}
+	for _, g := range m.groups {
+		if g.name == "/work/api" && len(g.verdicts) != 2 {
+			t.Errorf("/work/api: got %d panes, want 2", len(g.verdicts))
+		}
+	}
+	view := m.View()
+	if !strings.Contains(view, "/work/api") || !strings.Contains(view, noPathGroup) || !strings.Contains(view, "b:0.0") {
+		t.Errorf("expected directory groups with full pane targets:\n%s", view)
+	}
+
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m.groupBy != groupByRepo {
+		t.Errorf("expected repo grouping, got %v", m.groupBy)
+	}
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m.groupBy != groupBySession || len(m.groups) != 3 {
+		t.Errorf("expected session grouping again, got %v with %d groups", m.groupBy, len(m.groups))
+	}
+}
+
+func TestGroupBy_KeepsSelectedPane(t *testing.T) {
+	m := groupingTestModel()
+	for i, item := range m.items {
+		if item.kind == itemPane && m.verdicts[item.paneIdx].Target == "b:0.0" {
+			m.cursor = i
+		}
+	}
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if v := m.selectedVerdict(); v == nil || v.Target != "b:0.0" {
+		t.Errorf("expected b:0.0 to stay selected, got %+v", v)
+	}
+}
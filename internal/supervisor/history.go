@@ -0,0 +1,203 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/logging"
+)
+
+// History is an append-only JSON Lines log of the actions the supervisor
+// sent to panes and of pane state transitions, one HistoryEntry per line.
+// A nil *History records nothing. Record is safe for concurrent use; nudges
+// are sent from tea.Cmd goroutines.
+type History struct {
+	path string
+	key  *StateKey
+
+	// MaxAge and MaxSize are the retention of the log (history_max_age,
+	// history_max_size); 0 keeps entries regardless. See Prune.
+	MaxAge  time.Duration
+	MaxSize int64
+
+	// Log receives the errors of the pruning Record does after appending,
+	// which do not fail the Record; nil discards them.
+	Log *slog.Logger
+
+	mu     sync.Mutex
+	pruned time.Time // when MaxAge was last applied
+}
+
+// HistoryEntry is one action sent to one pane, its outcome, or one pane's
+// transition.
+type HistoryEntry struct {
+	Time   time.Time `json:"time"`
+	Target string    `json:"target"`
+	Agent  string    `json:"agent,omitempty"`
+	Action string    `json:"action,omitempty"`
+	Keys   string    `json:"keys,omitempty"`
+	Risk   string    `json:"risk,omitempty"`
+	// Reason is what was typed to confirm a high-risk action (see
+	// confirm_high_risk).
+	Reason string `json:"reason,omitempty"`
+	// Auto is true when auto-nudge sent the action.
+	Auto bool `json:"auto,omitempty"`
+	// Event is set instead of Action for a pane state transition (see
+	// TransitionKind); Summary is then the pane's verdict reason.
+	Event   string `json:"event,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	// Outcome is set on a second entry for an action once its pane's next
+	// scans show whether it unblocked the pane (see NudgeOutcome).
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// DefaultHistoryPath returns ~/.config/pane-patrol/history.jsonl.
+func DefaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "pane-patrol", "history.jsonl")
+}
+
+// NewHistory returns a history log appending to path (a leading "~" is
+// expanded), encrypted with key unless it is nil. The file is created on
+// the first Record.
+func NewHistory(path string, key *StateKey) *History {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return &History{path: path, key: key}
+}
+
+// Record appends e to the log. The log can contain pane commands, so it is
+// only readable by the owner, and encrypted with the state key when there
+// is one.
+func (h *History) Record(e HistoryEntry) error {
+	if h == nil || h.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if _, err := f.Write(append(h.key.seal(data), '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if h.pruneDue(time.Now()) {
+		// The entry is recorded; a failed prune is retried with the next
+		// Record that finds the log due.
+		if _, _, err := h.prune(time.Now()); err != nil {
+			logging.OrDiscard(h.Log).Warn("history prune failed", "path", h.path, "err", err)
+		}
+	}
+	return nil
+}
+
+// pruneInterval is how often Record applies MaxAge.
+const pruneInterval = 24 * time.Hour
+
+// pruneDue reports whether the log has outgrown MaxSize or MaxAge was not
+// applied for a day. h.mu must be held.
+func (h *History) pruneDue(now time.Time) bool {
+	if h.MaxAge > 0 && now.Sub(h.pruned) >= pruneInterval {
+		return true
+	}
+	if h.MaxSize <= 0 {
+		return false
+	}
+	info, err := os.Stat(h.path)
+	return err == nil && info.Size() > h.MaxSize
+}
+
+// Prune applies the retention: it drops entries older than MaxAge and,
+// while the log is larger than MaxSize, the oldest entries until it is
+// down to three quarters of it, so it is not trimmed again on every
+// Record. Record prunes by itself; Prune is for pane-patrol history prune.
+// It returns the number of entries removed and kept.
+func (h *History) Prune(now time.Time) (removed, kept int, err error) {
+	if h == nil || h.path == "" {
+		return 0, 0, nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.prune(now)
+}
+
+// prune implements Prune; h.mu must be held. Encrypted entries are read
+// with the state key and written back as they were. Entries it cannot
+// decrypt (sealed with another key, or read without one) have no known
+// time and are dropped only with an older entry after them.
+func (h *History) prune(now time.Time) (removed, kept int, err error) {
+	h.pruned = now
+	data, err := os.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("history: %w", err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	start := 0
+	if h.MaxAge > 0 {
+		cutoff := now.Add(-h.MaxAge)
+		for i := start; i < len(lines); i++ {
+			plain, err := h.key.open(h.path, []byte(lines[i]))
+			if err != nil {
+				continue
+			}
+			// Entries are in time order; keep from the first one that is
+			// recent or has no time.
+			var e HistoryEntry
+			if json.Unmarshal(plain, &e) != nil || e.Time.IsZero() || !e.Time.Before(cutoff) {
+				break
+			}
+			start = i + 1
+		}
+	}
+	size := int64(0)
+	for _, line := range lines[start:] {
+		size += int64(len(line))
+	}
+	if h.MaxSize > 0 && size > h.MaxSize {
+		for start < len(lines) && size > h.MaxSize*3/4 {
+			size -= int64(len(lines[start]))
+			start++
+		}
+	}
+	if start == 0 {
+		return 0, len(lines), nil
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines[start:], "")), 0o600); err != nil {
+		return 0, 0, fmt.Errorf("history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return 0, 0, fmt.Errorf("history: %w", err)
+	}
+	return start, len(lines) - start, nil
+}
@@ -0,0 +1,115 @@
+package supervisor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistory_RecordAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "history.jsonl")
+	h := NewHistory(path, nil)
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	entries := []HistoryEntry{
+		{Time: now, Target: "api:0.0", Agent: "claude_code", Action: "allow once", Keys: "1", Risk: "medium"},
+		{Time: now, Target: "api:0.1", Agent: "codex", Action: "run command", Keys: "y", Risk: "high", Reason: "migration approved in standup"},
+	}
+	for _, e := range entries {
+		if err := h.Record(e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 || got[1] != entries[1] || !got[0].Time.Equal(now) {
+		t.Errorf("got %+v", got)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("history mode: got %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestHistory_NilRecordsNothing(t *testing.T) {
+	var h *History
+	if err := h.Record(HistoryEntry{Target: "a:0.0"}); err != nil {
+		t.Errorf("nil history: %v", err)
+	}
+}
+
+func TestHistory_PruneAppliesRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	h := NewHistory(path, nil)
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	for days := 10; days >= 0; days-- {
+		if err := h.Record(HistoryEntry{Time: now.Add(-time.Duration(days) * 24 * time.Hour), Target: "api:0.0", Action: "allow once"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h.MaxAge = 3 * 24 * time.Hour
+	if removed, kept, err := h.Prune(now); err != nil || removed != 7 || kept != 4 {
+		t.Fatalf("max age: removed %d, kept %d, %v", removed, kept, err)
+	}
+	data, _ := os.ReadFile(path)
+	var first HistoryEntry
+	if err := json.Unmarshal(data[:bytes.IndexByte(data, '\n')], &first); err != nil || !first.Time.Equal(now.Add(-3*24*time.Hour)) {
+		t.Errorf("oldest entry kept: %v (%v)", first.Time, err)
+	}
+
+	// Over MaxSize, the oldest entries go until the log is at 3/4 of it.
+	h.MaxAge, h.MaxSize = 0, int64(len(data))-1
+	if removed, kept, err := h.Prune(now); err != nil || removed != 2 || kept != 2 {
+		t.Errorf("max size: removed %d, kept %d, %v", removed, kept, err)
+	}
+	if removed, _, _ := h.Prune(now); removed != 0 {
+		t.Errorf("a log within its limits is left alone, removed %d", removed)
+	}
+
+	// Record trims by itself once the log outgrows MaxSize.
+	h.MaxSize = 400
+	for range 10 {
+		h.Record(HistoryEntry{Time: now, Target: "web:0.0", Action: "deny"})
+	}
+	if info, _ := os.Stat(path); info.Size() > 400 {
+		t.Errorf("history grew to %d bytes past history_max_size", info.Size())
+	}
+}
+
+func TestHistory_PruneSkipsSealedEntriesWithoutKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.jsonl")
+	salt, _ := LoadStateSalt(filepath.Join(dir, "state.salt"))
+	k, _ := NewStateKey("secret", salt)
+	now := time.Now()
+	sealed := NewHistory(path, k)
+	sealed.Record(HistoryEntry{Time: now.Add(-5 * 24 * time.Hour), Target: "api:0.0", Action: "allow once"})
+
+	// Without the key the sealed entry's time is unknown: it neither fails
+	// Record nor stops an older plain entry after it from being dropped.
+	h := NewHistory(path, nil)
+	h.Record(HistoryEntry{Time: now.Add(-4 * 24 * time.Hour), Target: "api:0.0", Action: "deny"})
+	h.MaxAge = 3 * 24 * time.Hour
+	if err := h.Record(HistoryEntry{Time: now, Target: "web:0.0", Action: "allow once"}); err != nil {
+		t.Fatalf("Record with sealed entries and no key: %v", err)
+	}
+	if removed, kept, err := h.Prune(now); err != nil || removed != 0 || kept != 1 {
+		t.Errorf("after Record pruned: removed %d, kept %d, %v", removed, kept, err)
+	}
+}
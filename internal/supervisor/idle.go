@@ -0,0 +1,196 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// taskTimeout bounds one run of task_command.
+const taskTimeout = 30 * time.Second
+
+// IdleFor returns how long the pane at target has been idle at its prompt
+// at now, or 0 when it is not.
+func (s *VerdictStore) IdleFor(target string, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.panes[target]
+	st := s.escalations[target]
+	if !ok || st == nil || !v.Blocked || v.Reason != parser.IdleReason {
+		return 0
+	}
+	return now.Sub(st.since)
+}
+
+// IdleTooLong returns the panes that have been idle at their prompt for
+// after at now and were not reported before, sorted, and marks them
+// reported. A pane is reported again once it idles again after working.
+func (s *VerdictStore) IdleTooLong(after time.Duration, now time.Time) []string {
+	if after <= 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var targets []string
+	for target, st := range s.escalations {
+		v := s.panes[target]
+		if st.idleReported || v.Reason != parser.IdleReason || now.Sub(st.since) < after {
+			continue
+		}
+		st.idleReported = true
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// idleTooLong reports whether v has been idle at its prompt for idle_after.
+func (m *tuiModel) idleTooLong(v model.Verdict) bool {
+	return m.idleAfter > 0 && idleAtPrompt(&v) && m.store.IdleFor(v.Target, time.Now()) >= m.idleAfter
+}
+
+// idleReason is the list reason of a pane idle at its prompt for
+// idle_after, e.g. "idle at prompt for 25m", or "" for other panes.
+func (m *tuiModel) idleReason(v model.Verdict) string {
+	if !m.idleTooLong(v) {
+		return ""
+	}
+	return fmt.Sprintf("%s for %s", v.Reason, formatDuration(m.store.IdleFor(v.Target, time.Now())))
+}
+
+// idleStates describes the Idle states of agents idle at their prompt in
+// the list and the panel.
+var idleStates = map[string]string{
+	model.IdleFresh:    "fresh session",
+	model.IdleFinished: "task finished",
+}
+
+// withIdleState adds what an agent idle at its prompt last did to its
+// reason, e.g. "idle at prompt · task finished".
+func withIdleState(reason string, v model.Verdict) string {
+	if state := idleStates[v.Idle]; state != "" && idleAtPrompt(&v) {
+		return reason + " · " + state
+	}
+	return reason
+}
+
+// reportIdle adds the panes that just passed idle_after to the status line,
+// pointing at O when task_command can supply their next task.
+func (m *tuiModel) reportIdle(now time.Time) {
+	targets := m.store.IdleTooLong(m.idleAfter, now)
+	for _, target := range targets {
+		m.logger().Info("idle too long", "target", target, "idle_after", m.idleAfter)
+	}
+	if len(targets) == 0 {
+		return
+	}
+	text := fmt.Sprintf("%s idle for %s", strings.Join(targets, ", "), formatDuration(m.idleAfter))
+	if m.tasks != nil {
+		text += " (O for the next task)"
+	}
+	m.message = strings.TrimPrefix(m.message+" · "+text, " · ")
+}
+
+// TaskSource runs task_command to get the next task for an idle agent,
+// e.g. from a ticket queue. The command receives the pane's verdict as JSON
+// on stdin, PANE_PATROL_EVENT=next_task, PANE_PATROL_TARGET,
+// PANE_PATROL_PATH (the pane's directory), and PANE_PATROL_IDLE_FOR (e.g.
+// "25m"), and prints the task; nothing printed means no task. A nil
+// *TaskSource supplies nothing.
+type TaskSource struct {
+	Command string
+}
+
+// NewTaskSource returns a task source running command, or nil if it is
+// empty.
+func NewTaskSource(command string) *TaskSource {
+	if command == "" {
+		return nil
+	}
+	return &TaskSource{Command: command}
+}
+
+// Next runs the command for v and returns the task it printed, trimmed.
+func (t *TaskSource) Next(ctx context.Context, v model.Verdict, idleFor time.Duration) (string, error) {
+	if t == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, taskTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", t.Command)
+	cmd.Stdin = strings.NewReader(string(data) + "\n")
+	cmd.Env = append(os.Environ(), "PANE_PATROL_EVENT=next_task", "PANE_PATROL_TARGET="+v.Target,
+		"PANE_PATROL_PATH="+v.Path, "PANE_PATROL_IDLE_FOR="+formatDuration(idleFor))
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("task command failed for %s: %w (output: %s)", v.Target, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// nextTaskMsg is task_command's answer for an idle pane.
+type nextTaskMsg struct {
+	target string
+	task   string
+	err    error
+}
+
+// nextTaskCmd asks task_command for the selected idle pane's next task (O)
+// in the background. The task is then put in the text input, so it is only
+// sent once confirmed with Enter, and can be edited first.
+func (m *tuiModel) nextTaskCmd() tea.Cmd {
+	v := m.selectedVerdict()
+	if v == nil {
+		return nil
+	}
+	if !idleAtPrompt(v) {
+		m.message = fmt.Sprintf("%s is not idle at its prompt", v.Target)
+		return nil
+	}
+	if m.tasks == nil {
+		m.message = "No task_command configured"
+		return nil
+	}
+	m.message = fmt.Sprintf("Asking task_command for the next task of %s...", v.Target)
+	tasks, ctx, verdict, idleFor := m.tasks, m.ctx, *v, m.store.IdleFor(v.Target, time.Now())
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return func() tea.Msg {
+		task, err := tasks.Next(ctx, verdict, idleFor)
+		return nextTaskMsg{target: verdict.Target, task: task, err: err}
+	}
+}
+
+// handleNextTask opens the text input with the task for confirmation.
+func (m *tuiModel) handleNextTask(msg nextTaskMsg) {
+	switch {
+	case msg.err != nil:
+		m.logger().Warn("task command failed", "target", msg.target, "err", msg.err)
+		m.message = msg.err.Error()
+	case msg.task == "":
+		m.message = fmt.Sprintf("task_command has no task for %s", msg.target)
+	case m.textInput != nil:
+		m.message = fmt.Sprintf("Next task for %s ignored: already typing an answer", msg.target)
+	default:
+		ti := &textInput{target: msg.target}
+		ti.insert(msg.task)
+		m.textInput = ti
+		m.message = fmt.Sprintf("Next task for %s: enter sends it, esc cancels", msg.target)
+	}
+}
@@ -0,0 +1,140 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+func idlePane(target, session string) model.Verdict {
+	return blockedVerdict(target, session, parser.IdleReason)
+}
+
+func TestVerdictStore_IdleTooLong(t *testing.T) {
+	var s VerdictStore
+	t0 := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	idle, busy := idlePane("api:0.0", "api"), blockedVerdict("web:0.0", "web", "permission required")
+	working := idle
+	working.Blocked, working.Reason = false, "working"
+
+	s.Apply([]model.Verdict{idle, busy}, t0)
+	if got := s.IdleTooLong(10*time.Minute, t0.Add(5*time.Minute)); len(got) != 0 {
+		t.Errorf("too early: got %v", got)
+	}
+	if got := s.IdleTooLong(10*time.Minute, t0.Add(11*time.Minute)); strings.Join(got, " ") != "api:0.0" {
+		t.Errorf("after 11m: got %v (dialogs are not idle)", got)
+	}
+	if s.IdleFor("api:0.0", t0.Add(11*time.Minute)) != 11*time.Minute || s.IdleFor("web:0.0", t0) != 0 {
+		t.Error("IdleFor should only measure panes idle at their prompt")
+	}
+	if got := s.IdleTooLong(10*time.Minute, t0.Add(20*time.Minute)); len(got) != 0 {
+		t.Errorf("each idle period is reported once, got %v", got)
+	}
+
+	s.Apply([]model.Verdict{working, busy}, t0.Add(21*time.Minute))
+	s.Apply([]model.Verdict{idle, busy}, t0.Add(22*time.Minute))
+	if got := s.IdleTooLong(10*time.Minute, t0.Add(33*time.Minute)); strings.Join(got, " ") != "api:0.0" {
+		t.Errorf("idling again after working: got %v", got)
+	}
+	if s.IdleTooLong(0, t0.Add(time.Hour)) != nil {
+		t.Error("idle_after: off reports nothing")
+	}
+}
+
+func TestIdleFilter(t *testing.T) {
+	m := newTestModel(idlePane("api:0.0", "api"))
+	m.verdicts = append(m.verdicts, idlePane("web:0.0", "web"), blockedVerdict("db:0.0", "db", "permission required"))
+	m.store.Apply(m.verdicts[:1], time.Now().Add(-time.Hour))
+	m.store.Apply(m.verdicts, time.Now())
+
+	f := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}}
+	for range 3 {
+		m.handleVerdictListKey(f)
+	}
+	if m.filter != filterBlocked {
+		t.Fatalf("without idle_after the idle filter is skipped, got %v", m.filter)
+	}
+
+	m.idleAfter = 10 * time.Minute
+	for range 3 {
+		m.handleVerdictListKey(f)
+	}
+	if m.filter != filterIdle {
+		t.Fatalf("expected the idle filter after all, got %v", m.filter)
+	}
+	var shown []string
+	for _, item := range m.items {
+		if item.kind == itemPane {
+			shown = append(shown, m.verdicts[item.paneIdx].Target)
+		}
+	}
+	if strings.Join(shown, " ") != "api:0.0" {
+		t.Errorf("idle filter: got %v", shown)
+	}
+	if view := m.View(); !strings.Contains(view, "idle at prompt for 1h") {
+		t.Errorf("expected how long the pane idled in its row:\n%s", view)
+	}
+	m.handleVerdictListKey(f)
+	if m.filter != filterBlocked {
+		t.Errorf("expected blocked after idle, got %v", m.filter)
+	}
+}
+
+func TestIdleState_ShownInList(t *testing.T) {
+	finished, fresh := idlePane("api:0.0", "api"), idlePane("web:0.0", "web")
+	finished.Idle, fresh.Idle = model.IdleFinished, model.IdleFresh
+	dialog := blockedVerdict("db:0.0", "db", "permission required")
+	dialog.Idle = model.IdleFinished // only idle panes show their state
+	m := newTestModel(finished)
+	m.verdicts = append(m.verdicts, fresh, dialog)
+	m.expanded["web"], m.expanded["db"] = true, true
+	m.rebuildGroups()
+
+	view := m.View()
+	for _, want := range []string{"idle at prompt · task finished", "idle at prompt · fresh session"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the list:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "permission required · task finished") {
+		t.Errorf("a dialog should not show an idle state:\n%s", view)
+	}
+}
+
+func TestNextTask_ConfirmedInTextInput(t *testing.T) {
+	m := newTestModel(idlePane("api:0.0", "api"))
+	m.store.Apply(m.verdicts, time.Now().Add(-20*time.Minute))
+
+	O := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}}
+	if _, cmd := m.handleVerdictListKey(O); cmd != nil || !strings.Contains(m.message, "No task_command") {
+		t.Errorf("without task_command: %q", m.message)
+	}
+
+	m.tasks = NewTaskSource(`grep -q '"target":"api:0.0"' && echo "fix #42 in $PANE_PATROL_TARGET (idle $PANE_PATROL_IDLE_FOR)"`)
+	_, cmd := m.handleVerdictListKey(O)
+	if cmd == nil {
+		t.Fatalf("expected the task command to run: %q", m.message)
+	}
+	m.Update(cmd())
+	if m.textInput == nil || string(m.textInput.text) != "fix #42 in api:0.0 (idle 20m)" {
+		t.Fatalf("expected the task in the text input, got %+v (%q)", m.textInput, m.message)
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+
+	m.tasks = NewTaskSource("true")
+	_, cmd = m.handleVerdictListKey(O)
+	m.Update(cmd())
+	if m.textInput != nil || !strings.Contains(m.message, "no task for api:0.0") {
+		t.Errorf("empty output: %q", m.message)
+	}
+	m.tasks = NewTaskSource("echo queue down >&2; exit 3")
+	_, cmd = m.handleVerdictListKey(O)
+	m.Update(cmd())
+	if !strings.Contains(m.message, "task command failed for api:0.0") || !strings.Contains(m.message, "queue down") {
+		t.Errorf("failing command: %q", m.message)
+	}
+}
@@ -0,0 +1,64 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// viewInline renders the list for the inline mode (--inline N): a status
+// line and as many list rows as fit in the remaining lines, without the
+// action panel and hints, so the supervisor can run as a small ticker at
+// the bottom of a terminal. Screens opened from the list (t, X, D, ...)
+// render as usual.
+func (m *tuiModel) viewInline() string {
+	var b strings.Builder
+	blocked, active := 0, 0
+	for _, g := range m.groups {
+		blocked += g.blocked
+		active += g.active
+	}
+	b.WriteString(m.s.title.Render("pane-patrol"))
+	b.WriteString("  ")
+	counts := fmt.Sprintf("%d blocked", blocked)
+	if blocked > 0 {
+		counts = m.s.blocked.Render(counts)
+	}
+	b.WriteString(counts)
+	b.WriteString(m.s.dim.Render(fmt.Sprintf(" · %d active", active)))
+	if m.scanning {
+		b.WriteString(m.s.dim.Render(" · " + m.scanStatus()))
+	}
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + truncate(m.message, max(m.width-40, 10))))
+	}
+
+	rows := max(m.inline-1, 1)
+	switch {
+	case m.serverDown > 0:
+		b.WriteString("\n")
+		b.WriteString(m.s.err.Render(fmt.Sprintf("  %s tmux server not running — retrying in %s",
+			m.glyphs().warning, serverRetryDelay(m.serverDown))))
+		rows--
+	case len(m.items) == 0 && !m.scanning:
+		b.WriteString("\n")
+		b.WriteString(m.s.dim.Render("  no panes"))
+		rows--
+	case len(m.items) > 0:
+		nameWidth := min(m.nameColumnWidth(), max(m.width/2, 10))
+		reasonWidth := max(m.width-nameWidth-len(listSeparator), 15)
+		start, end := m.scrollWindow(rows)
+		m.listStart = start
+		lines := m.renderListRows(start, end, nameWidth, reasonWidth)
+		if len(lines) > rows {
+			lines = lines[:rows]
+		}
+		for _, line := range lines {
+			b.WriteString("\n")
+			b.WriteString(line)
+		}
+		rows -= len(lines)
+	}
+	// Keep the height constant so the terminal above does not jump.
+	b.WriteString(strings.Repeat("\n", max(rows, 0)))
+	return b.String()
+}
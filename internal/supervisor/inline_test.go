@@ -0,0 +1,21 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInline_RendersFixedHeight(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.inline = 4
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines != 4 {
+		t.Errorf("inline view has %d lines, want 4:\n%s", lines, view)
+	}
+	if !strings.Contains(view, "1 blocked") || !strings.Contains(view, ":0.0") {
+		t.Errorf("inline view should show counts and the pane:\n%s", view)
+	}
+	if strings.Contains(view, "allow once") {
+		t.Errorf("inline view should not show the action panel:\n%s", view)
+	}
+}
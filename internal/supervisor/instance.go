@@ -0,0 +1,163 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// nudgeLeaseOption is the global tmux option holding the auto-nudge lease
+// as "<expiry unix time> <pid> <pane>": the supervisor holding it is the
+// only one on the server that auto-nudges until it expires.
+const nudgeLeaseOption = "@pane-patrol-nudge-lease"
+
+// nudgeLeaseChannel is the tmux wait-for channel locked around every read
+// and write of the lease, so taking it is atomic across supervisors.
+const nudgeLeaseChannel = "pane-patrol-nudge-lease"
+
+// nudgeLeaseLockWait is how long to wait for nudgeLeaseChannel. Holding it
+// takes a few tmux commands, so a lock held longer was left by a
+// supervisor that died holding it, and is broken. Shortened in tests.
+var nudgeLeaseLockWait = 5 * time.Second
+
+// minNudgeLease is the shortest auto-nudge lease; it is longer with a
+// longer refresh interval so the holder can renew it before it expires.
+const minNudgeLease = 30 * time.Second
+
+// instanceTmux is the part of mux.Tmux used by Instance, replaced in tests.
+type instanceTmux interface {
+	SetOption(ctx context.Context, scope, target, name, value string) error
+	UnsetOption(ctx context.Context, scope, target, name string) error
+	GlobalOption(ctx context.Context, name string) (string, error)
+	SetGlobalOption(ctx context.Context, name, value string) error
+	UnsetGlobalOption(ctx context.Context, name string) error
+	LockChannel(ctx context.Context, name string) error
+	UnlockChannel(ctx context.Context, name string) error
+}
+
+// Instance is this supervisor's presence on the tmux server, so several
+// supervisors (e.g. of two people sharing a server) do not supervise each
+// other or answer the same dialog twice. Its pane is marked with
+// mux.SupervisorOption, which makes other supervisors leave it out of
+// their scans, and it takes turns with them for the auto-nudge lease.
+type Instance struct {
+	tmux   instanceTmux
+	target string // the supervisor's pane; "" when not running in tmux
+	pid    int
+}
+
+// NewInstance returns the instance of the supervisor running in pane
+// target ("" outside tmux).
+func NewInstance(tmux instanceTmux, target string) *Instance {
+	return &Instance{tmux: tmux, target: target, pid: os.Getpid()}
+}
+
+// Register marks the supervisor's pane.
+func (i *Instance) Register(ctx context.Context) error {
+	if i.target == "" {
+		return nil
+	}
+	return i.tmux.SetOption(ctx, "-p", i.target, mux.SupervisorOption, strconv.Itoa(i.pid))
+}
+
+// Close removes the pane mark and gives up the auto-nudge lease if this
+// supervisor holds it, so another one can take over at once.
+func (i *Instance) Close(ctx context.Context) error {
+	var errs []error
+	if i.target != "" {
+		errs = append(errs, i.tmux.UnsetOption(ctx, "-p", i.target, mux.SupervisorOption))
+	}
+	unlock, err := i.lockLease(ctx)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	defer unlock()
+	value, err := i.tmux.GlobalOption(ctx, nudgeLeaseOption)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	if l, ok := parseNudgeLease(value); ok && l.pid == i.pid {
+		errs = append(errs, i.tmux.UnsetGlobalOption(ctx, nudgeLeaseOption))
+	}
+	return errors.Join(errs...)
+}
+
+// nudgeLease is a parsed nudgeLeaseOption value.
+type nudgeLease struct {
+	expires time.Time
+	pid     int
+	target  string
+}
+
+func parseNudgeLease(value string) (nudgeLease, bool) {
+	fields := strings.Fields(value)
+	if len(fields) < 2 {
+		return nudgeLease{}, false
+	}
+	expires, err1 := strconv.ParseInt(fields[0], 10, 64)
+	pid, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil {
+		return nudgeLease{}, false
+	}
+	l := nudgeLease{expires: time.Unix(expires, 0), pid: pid}
+	if len(fields) > 2 {
+		l.target = fields[2]
+	}
+	return l, true
+}
+
+// holder describes the supervisor holding l for status messages.
+func (l nudgeLease) holder() string {
+	if l.target != "" {
+		return "the supervisor in " + l.target
+	}
+	return fmt.Sprintf("the supervisor with pid %d", l.pid)
+}
+
+// lockLease locks nudgeLeaseChannel and returns the function unlocking it.
+// A lock not taken within nudgeLeaseLockWait is broken and an error
+// returned, so the lease is taken on the next try.
+func (i *Instance) lockLease(ctx context.Context) (unlock func(), err error) {
+	lockCtx, cancel := context.WithTimeout(ctx, nudgeLeaseLockWait)
+	defer cancel()
+	if err := i.tmux.LockChannel(lockCtx, nudgeLeaseChannel); err != nil {
+		if lockCtx.Err() != nil && ctx.Err() == nil {
+			_ = i.tmux.UnlockChannel(ctx, nudgeLeaseChannel)
+			return nil, fmt.Errorf("auto-nudge lease: lock held for over %s, broken", nudgeLeaseLockWait)
+		}
+		return nil, err
+	}
+	return func() { _ = i.tmux.UnlockChannel(context.WithoutCancel(ctx), nudgeLeaseChannel) }, nil
+}
+
+// AcquireNudgeLease takes or renews the auto-nudge lease for ttl (at least
+// minNudgeLease). It fails while another supervisor holds an unexpired
+// lease, returning a description of that supervisor. The lease is read
+// and written with nudgeLeaseChannel locked, so of two supervisors taking
+// an expired lease at once only the first gets it.
+func (i *Instance) AcquireNudgeLease(ctx context.Context, now time.Time, ttl time.Duration) (ok bool, holder string, err error) {
+	unlock, err := i.lockLease(ctx)
+	if err != nil {
+		return false, "", err
+	}
+	defer unlock()
+	value, err := i.tmux.GlobalOption(ctx, nudgeLeaseOption)
+	if err != nil {
+		return false, "", err
+	}
+	if l, ok := parseNudgeLease(value); ok && l.pid != i.pid && now.Before(l.expires) {
+		return false, l.holder(), nil
+	}
+	expires := now.Add(max(ttl, minNudgeLease)).Unix()
+	mine := strings.TrimSpace(fmt.Sprintf("%d %d %s", expires, i.pid, i.target))
+	if err := i.tmux.SetGlobalOption(ctx, nudgeLeaseOption, mine); err != nil {
+		return false, "", err
+	}
+	return true, "", nil
+}
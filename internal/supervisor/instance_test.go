@@ -0,0 +1,171 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// fakeInstanceTmux keeps pane and global options and wait-for locks like a
+// tmux server shared by several supervisors.
+type fakeInstanceTmux struct {
+	pane   map[string]string
+	global map[string]string
+	locked map[string]bool
+}
+
+func newFakeInstanceTmux() *fakeInstanceTmux {
+	return &fakeInstanceTmux{pane: make(map[string]string), global: make(map[string]string), locked: make(map[string]bool)}
+}
+
+// LockChannel waits until ctx is done while the channel is locked; these
+// tests run one supervisor at a time, so nobody unlocks it meanwhile.
+func (f *fakeInstanceTmux) LockChannel(ctx context.Context, name string) error {
+	if f.locked[name] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	f.locked[name] = true
+	return nil
+}
+
+func (f *fakeInstanceTmux) UnlockChannel(_ context.Context, name string) error {
+	delete(f.locked, name)
+	return nil
+}
+
+func (f *fakeInstanceTmux) SetOption(_ context.Context, _, target, name, value string) error {
+	f.pane[target+" "+name] = value
+	return nil
+}
+
+func (f *fakeInstanceTmux) UnsetOption(_ context.Context, _, target, name string) error {
+	delete(f.pane, target+" "+name)
+	return nil
+}
+
+func (f *fakeInstanceTmux) GlobalOption(_ context.Context, name string) (string, error) {
+	return f.global[name], nil
+}
+
+func (f *fakeInstanceTmux) SetGlobalOption(_ context.Context, name, value string) error {
+	f.global[name] = value
+	return nil
+}
+
+func (f *fakeInstanceTmux) UnsetGlobalOption(_ context.Context, name string) error {
+	delete(f.global, name)
+	return nil
+}
+
+func TestInstance_RegisterAndClose(t *testing.T) {
+	tmux := newFakeInstanceTmux()
+	i := NewInstance(tmux, "me:0.0")
+	ctx := context.Background()
+
+	if err := i.Register(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := tmux.pane["me:0.0 "+mux.SupervisorOption]; got != fmt.Sprint(os.Getpid()) {
+		t.Errorf("pane option = %q, want the pid", got)
+	}
+	if ok, _, err := i.AcquireNudgeLease(ctx, time.Now(), time.Minute); !ok || err != nil {
+		t.Fatalf("lease not taken: %v", err)
+	}
+
+	if err := i.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(tmux.pane) != 0 || len(tmux.global) != 0 {
+		t.Errorf("left behind pane=%v global=%v", tmux.pane, tmux.global)
+	}
+}
+
+func TestInstance_NudgeLease(t *testing.T) {
+	tmux := newFakeInstanceTmux()
+	mine := NewInstance(tmux, "me:0.0")
+	other := &Instance{tmux: tmux, target: "alice:0.0", pid: mine.pid + 1}
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+
+	if ok, _, _ := other.AcquireNudgeLease(ctx, now, 15*time.Second); !ok {
+		t.Fatal("expected the first supervisor to take the lease")
+	}
+	ok, holder, err := mine.AcquireNudgeLease(ctx, now.Add(10*time.Second), time.Minute)
+	if ok || err != nil || holder != "the supervisor in alice:0.0" {
+		t.Errorf("ok=%v holder=%q err=%v, want the lease held by alice:0.0", ok, holder, err)
+	}
+	// The holder renews its own lease.
+	if ok, _, _ := other.AcquireNudgeLease(ctx, now.Add(20*time.Second), 15*time.Second); !ok {
+		t.Error("expected the holder to renew the lease")
+	}
+	// The lease lasts at least minNudgeLease, then anyone may take it.
+	if ok, _, _ := mine.AcquireNudgeLease(ctx, now.Add(20*time.Second+minNudgeLease), time.Minute); !ok {
+		t.Error("expected an expired lease to be taken over")
+	}
+	// Closing the other supervisor leaves a lease it doesn't hold alone.
+	if err := other.Close(ctx); err != nil || !strings.Contains(tmux.global[nudgeLeaseOption], "me:0.0") {
+		t.Errorf("lease = %q, %v", tmux.global[nudgeLeaseOption], err)
+	}
+	if tmux.locked[nudgeLeaseChannel] {
+		t.Error("the lease lock should be released")
+	}
+}
+
+func TestInstance_NudgeLeaseLocked(t *testing.T) {
+	defer func(wait time.Duration) { nudgeLeaseLockWait = wait }(nudgeLeaseLockWait)
+	nudgeLeaseLockWait = 10 * time.Millisecond
+	tmux := newFakeInstanceTmux()
+	mine := NewInstance(tmux, "me:0.0")
+	ctx := context.Background()
+	now := time.Unix(1_700_000_000, 0)
+
+	// Another supervisor is between reading and writing the lease.
+	tmux.locked[nudgeLeaseChannel] = true
+	if ok, _, err := mine.AcquireNudgeLease(ctx, now, time.Minute); ok || err == nil {
+		t.Fatalf("ok=%v err=%v, want no lease while the lock is held", ok, err)
+	}
+	if tmux.global[nudgeLeaseOption] != "" {
+		t.Errorf("lease written without the lock: %q", tmux.global[nudgeLeaseOption])
+	}
+	// A lock held that long is stale (its holder died) and was broken.
+	if ok, _, err := mine.AcquireNudgeLease(ctx, now, time.Minute); !ok || err != nil {
+		t.Errorf("ok=%v err=%v, want the lease once the stale lock is broken", ok, err)
+	}
+}
+
+func TestAutoNudge_LeftToLeaseHolder(t *testing.T) {
+	tmux := newFakeInstanceTmux()
+	other := &Instance{tmux: tmux, target: "alice:0.0", pid: os.Getpid() + 1}
+	if ok, _, _ := other.AcquireNudgeLease(context.Background(), time.Now(), time.Minute); !ok {
+		t.Fatal("lease not taken")
+	}
+
+	var sent []string
+	m := &tuiModel{
+		verdicts: []model.Verdict{{Target: "a:0.0", Session: "a", Agent: "opencode", Blocked: true,
+			Actions: []model.Action{{Keys: "Enter", Label: "allow once", Risk: "low", Raw: true}}}},
+		scanner:          &Scanner{},
+		autoNudge:        true,
+		autoNudgeMaxRisk: "low",
+		nudger: &Nudger{SendKeys: func(target, _, _ string) error {
+			sent = append(sent, target)
+			return nil
+		}, Sleep: func(time.Duration) {}},
+		instance: NewInstance(tmux, "me:0.0"),
+		ctx:      context.Background(),
+	}
+	msg := m.autoNudgeCmd()().(nudgeResultMsg)
+	if len(sent) != 0 || msg.sent != 0 {
+		t.Errorf("sent to %v while another supervisor holds the lease", sent)
+	}
+	if len(msg.messages) != 1 || msg.messages[0] != "auto-nudge left to the supervisor in alice:0.0" {
+		t.Errorf("messages = %q", msg.messages)
+	}
+}
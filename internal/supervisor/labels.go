@@ -0,0 +1,272 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// maxLabelLen caps labels so they fit the list's name column.
+const maxLabelLen = 32
+
+// Labels are friendly names for panes (by target, e.g. "dev:0.3") and
+// sessions, assigned in the supervisor with n, pane notes (N), pinned
+// panes (p), and the last continuation sent to each pane (o). They are persisted as JSON so they survive restarts. A nil
+// *Labels has no labels.
+type Labels struct {
+	path string
+	key  *StateKey
+
+	mu       sync.Mutex
+	Panes    map[string]string   `json:"panes,omitempty"`
+	Sessions map[string]string   `json:"sessions,omitempty"`
+	Notes    map[string]PaneNote `json:"notes,omitempty"`
+	Pins     map[string]bool     `json:"pins,omitempty"`
+
+	Continuations map[string]string `json:"continuations,omitempty"`
+}
+
+// DefaultLabelsPath is where labels are stored when no labels_file is
+// configured: ~/.config/pane-patrol/labels.json.
+func DefaultLabelsPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "pane-patrol", "labels.json")
+}
+
+// LoadLabels reads labels from path (a leading "~" is expanded), encrypted
+// with key unless it is nil. A missing file yields empty labels that are
+// created on the first Save.
+func LoadLabels(path string, key *StateKey) (*Labels, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	l := &Labels{path: path, key: key, Panes: map[string]string{}, Sessions: map[string]string{}, Notes: map[string]PaneNote{},
+		Pins: map[string]bool{}, Continuations: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading labels: %w", err)
+	}
+	if data, err = key.open(path, data); err != nil {
+		return nil, fmt.Errorf("reading labels: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("parsing labels %s: %w", path, err)
+	}
+	if l.Panes == nil {
+		l.Panes = map[string]string{}
+	}
+	if l.Sessions == nil {
+		l.Sessions = map[string]string{}
+	}
+	if l.Notes == nil {
+		l.Notes = map[string]PaneNote{}
+	}
+	if l.Pins == nil {
+		l.Pins = map[string]bool{}
+	}
+	if l.Continuations == nil {
+		l.Continuations = map[string]string{}
+	}
+	return l, nil
+}
+
+// Save writes the labels to their file, replacing it atomically. Notes can
+// hold anything, so the file is only readable by the owner.
+func (l *Labels) Save() error {
+	l.mu.Lock()
+	data, err := json.MarshalIndent(l, "", "  ")
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("saving labels: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, append(l.key.seal(data), '\n'), 0o600); err != nil {
+		return fmt.Errorf("saving labels: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("saving labels: %w", err)
+	}
+	return nil
+}
+
+// Pane returns the label of the pane at target, or "".
+func (l *Labels) Pane(target string) string {
+	if l == nil {
+		return ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Panes[target]
+}
+
+// Session returns the label of a session, or "".
+func (l *Labels) Session(name string) string {
+	if l == nil {
+		return ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Sessions[name]
+}
+
+// SetPane labels the pane at target; an empty label removes it.
+func (l *Labels) SetPane(target, label string) {
+	l.set(l.Panes, target, label)
+}
+
+// SetSession labels a session; an empty label removes it.
+func (l *Labels) SetSession(name, label string) {
+	l.set(l.Sessions, name, label)
+}
+
+func (l *Labels) set(m map[string]string, key, label string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if label = cleanLabel(label); label == "" {
+		delete(m, key)
+	} else {
+		m[key] = label
+	}
+}
+
+// cleanLabel collapses whitespace and caps the length.
+func cleanLabel(label string) string {
+	label = strings.Join(strings.Fields(label), " ")
+	if runes := []rune(label); len(runes) > maxLabelLen {
+		label = string(runes[:maxLabelLen])
+	}
+	return label
+}
+
+// renameInput is the label being typed for a pane or session (opened with
+// n). An empty label removes the existing one.
+type renameInput struct {
+	key     string // pane target or session name
+	session bool
+	input   []rune
+}
+
+// handleRenameKey handles keys while typing a label: enter saves, esc
+// cancels.
+func (m *tuiModel) handleRenameKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.rename
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.rename = nil
+	case tea.KeyEnter:
+		m.rename = nil
+		return m, m.saveLabel(r.key, r.session, string(r.input))
+	case tea.KeyBackspace:
+		if len(r.input) > 0 {
+			r.input = r.input[:len(r.input)-1]
+		}
+	case tea.KeyCtrlU:
+		r.input = nil
+	case tea.KeySpace:
+		r.input = append(r.input, ' ')
+	case tea.KeyRunes:
+		if len(r.input)+len(msg.Runes) <= maxLabelLen {
+			r.input = append(r.input, msg.Runes...)
+		}
+	}
+	return m, nil
+}
+
+// saveLabel stores and persists a label, and mirrors pane labels to the
+// tmux pane title when sync_pane_titles is on.
+func (m *tuiModel) saveLabel(key string, session bool, label string) tea.Cmd {
+	label = cleanLabel(label)
+	if session {
+		m.labels.SetSession(key, label)
+	} else {
+		m.labels.SetPane(key, label)
+	}
+	m.rebuildGroups()
+	if err := m.labels.Save(); err != nil {
+		m.message = err.Error()
+		return nil
+	}
+	if label == "" {
+		m.message = fmt.Sprintf("Removed label of %s", key)
+	} else {
+		m.message = fmt.Sprintf("Labeled %s %q", key, label)
+	}
+	if session || !m.syncPaneTitles {
+		return nil
+	}
+	ctx := m.ctx
+	return func() tea.Msg {
+		if err := mux.NewTmux().SetPaneTitle(ctx, key, label); err != nil {
+			return nudgeResultMsg{messages: []string{err.Error()}}
+		}
+		return nil
+	}
+}
+
+// sessionDisplayName is the session's name in the list: "label (name)"
+// when labeled.
+func (m *tuiModel) sessionDisplayName(session string) string {
+	if label := m.labels.Session(session); label != "" {
+		return fmt.Sprintf("%s (%s)", label, session)
+	}
+	return session
+}
+
+// paneDisplayName is the pane's name in the list: ":window.pane" (the full
+// target when not grouped by session, or pinned), followed by its label.
+func (m *tuiModel) paneDisplayName(v model.Verdict) string {
+	name := fmt.Sprintf(":%d.%d", v.Window, v.Pane)
+	if m.groupBy != groupBySession || m.labels.Pinned(v.Target) {
+		name = v.Target
+	}
+	if label := m.labels.Pane(v.Target); label != "" {
+		name += " " + label
+	}
+	if git := m.gitLabel(v); git != "" {
+		name += " (" + git + ")"
+	}
+	return name
+}
+
+// viewRename renders the label input.
+func (m *tuiModel) viewRename() string {
+	r := m.rename
+	var b strings.Builder
+	what := "pane"
+	if r.session {
+		what = "session"
+	}
+	b.WriteString(m.s.title.Render("Label " + what))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(r.key))
+	b.WriteString("\n\n  label: ")
+	b.WriteString(string(r.input))
+	b.WriteString("█\n\n")
+	b.WriteString(m.styleHints("  enter save (empty removes)  ctrl+u clear  esc cancel"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package supervisor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLabels_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "labels.json")
+	l, err := LoadLabels(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLabels on missing file: %v", err)
+	}
+	l.SetPane("dev:0.3", "  api   refactor ")
+	l.SetSession("dev", "backend")
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	l, err = LoadLabels(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLabels: %v", err)
+	}
+	if got := l.Pane("dev:0.3"); got != "api refactor" {
+		t.Errorf("Pane: got %q", got)
+	}
+	if got := l.Session("dev"); got != "backend" {
+		t.Errorf("Session: got %q", got)
+	}
+
+	l.SetPane("dev:0.3", " ")
+	if _, ok := l.Panes["dev:0.3"]; ok {
+		t.Error("empty label should remove the pane label")
+	}
+}
+
+func TestLabels_Nil(t *testing.T) {
+	var l *Labels
+	if l.Pane("x:0.0") != "" || l.Session("x") != "" {
+		t.Error("nil labels should have no labels")
+	}
+}
+
+func TestCleanLabel_Caps(t *testing.T) {
+	if got := cleanLabel(strings.Repeat("x", 50)); len(got) != maxLabelLen {
+		t.Errorf("cleanLabel: got %d chars, want %d", len(got), maxLabelLen)
+	}
+}
+
+func TestRename_PaneAndSession(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	labels, _ := LoadLabels(filepath.Join(t.TempDir(), "labels.json"), nil)
+	m.labels = labels
+
+	typeLabel := func(label string) {
+		_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		if m.rename == nil {
+			t.Fatal("expected n to open the label input")
+		}
+		_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlU})
+		_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(label)})
+		_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	typeLabel("api")
+	if got := labels.Pane("test:0.0"); got != "api" {
+		t.Fatalf("pane label: got %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, ":0.0 api") {
+		t.Errorf("expected pane label in list:\n%s", view)
+	}
+
+	// Reopening pre-fills the current label.
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if string(m.rename.input) != "api" {
+		t.Errorf("expected pre-filled label, got %q", string(m.rename.input))
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+
+	m.cursor = 0 // session header
+	typeLabel("backend")
+	if got := labels.Session("test"); got != "backend" {
+		t.Fatalf("session label: got %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "backend (test)") {
+		t.Errorf("expected session label in list:\n%s", view)
+	}
+
+	// Persisted on every change.
+	reloaded, err := LoadLabels(labels.path, nil)
+	if err != nil || reloaded.Session("test") != "backend" || reloaded.Pane("test:0.0") != "api" {
+		t.Errorf("labels not persisted: %+v, %v", reloaded, err)
+	}
+}
+
+func TestRename_WithoutLabels(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.rename != nil || m.message == "" {
+		t.Error("expected a message instead of the label input when labels are unavailable")
+	}
+}
+
+func TestAnnouncementText_Labels(t *testing.T) {
+	labels, _ := LoadLabels(filepath.Join(t.TempDir(), "labels.json"), nil)
+	v := simpleVerdict()
+	v.Reason = "permission required"
+	if got := announcementText(v, labels); got != "Session test blocked: permission required" {
+		t.Errorf("unlabeled: got %q", got)
+	}
+	labels.SetSession("test", "backend")
+	if got := announcementText(v, labels); got != "Session backend blocked: permission required" {
+		t.Errorf("session label: got %q", got)
+	}
+	labels.SetPane("test:0.0", "api refactor")
+	if got := announcementText(v, labels); got != "api refactor blocked: permission required" {
+		t.Errorf("pane label: got %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package supervisor
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/logging"
+)
+
+// logViewLines is how many lines of the log the log view reads.
+const logViewLines = 1000
+
+// logView is the tail of the supervisor log (opened with E), to debug odd
+// behavior without leaving the supervisor. It follows the log, re-reading
+// it after every scan, unless scrolled up.
+type logView struct {
+	lines  []string
+	offset int // index of the first visible line
+	follow bool
+	err    error
+}
+
+// logger returns the supervisor log; it discards when logging is off.
+func (m *tuiModel) logger() *slog.Logger {
+	return logging.OrDiscard(m.log)
+}
+
+// openLogView opens the log view scrolled to the end.
+func (m *tuiModel) openLogView() {
+	m.logView = &logView{follow: true}
+	m.readLog()
+	m.message = ""
+}
+
+// readLog re-reads the log's tail; when following, it scrolls to the end.
+func (m *tuiModel) readLog() {
+	l := m.logView
+	l.lines, l.err = logging.Tail(m.logFile, logViewLines)
+	maxOffset := max(len(l.lines)-m.logRows(), 0)
+	if l.follow {
+		l.offset = maxOffset
+	}
+	l.offset = min(l.offset, maxOffset)
+}
+
+// logRows is the number of log lines the view shows.
+func (m *tuiModel) logRows() int {
+	return max(m.height-4, 3)
+}
+
+// handleLogViewKey scrolls the log view.
+func (m *tuiModel) handleLogViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	l := m.logView
+	rows := m.logRows()
+	maxOffset := max(len(l.lines)-rows, 0)
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "E":
+		m.logView = nil
+		return m, nil
+	case "up", "k":
+		l.offset = max(l.offset-1, 0)
+	case "down", "j":
+		l.offset = min(l.offset+1, maxOffset)
+	case "pgup", "b":
+		l.offset = max(l.offset-rows, 0)
+	case "pgdown", " ":
+		l.offset = min(l.offset+rows, maxOffset)
+	case "home", "g":
+		l.offset = 0
+	case "end", "G":
+		l.offset = maxOffset
+	case "r":
+		m.readLog()
+	}
+	l.follow = l.offset == maxOffset
+	return m, nil
+}
+
+// viewLogView renders the visible part of the log, with warnings and
+// errors highlighted.
+func (m *tuiModel) viewLogView() string {
+	l := m.logView
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Supervisor log"))
+	b.WriteString("  ")
+	b.WriteString(m.s.dim.Render(m.logFile))
+	b.WriteString("\n")
+
+	rows := m.logRows()
+	switch {
+	case l.err != nil:
+		b.WriteString(m.s.err.Render(fmt.Sprintf("  cannot read the log: %v", l.err)))
+		b.WriteString("\n")
+		rows--
+	case len(l.lines) == 0:
+		b.WriteString(m.s.dim.Render("  the log is empty"))
+		b.WriteString("\n")
+		rows--
+	}
+	end := min(l.offset+rows, len(l.lines))
+	for _, line := range l.lines[l.offset:end] {
+		line = truncate(line, m.width-2)
+		switch {
+		case strings.Contains(line, "level=ERROR"):
+			line = m.s.err.Render(line)
+		case strings.Contains(line, "level=WARN"):
+			line = m.s.blocked.Render(line)
+		case strings.Contains(line, "level=DEBUG"):
+			line = m.s.dim.Render(line)
+		}
+		b.WriteString("  " + line + "\n")
+		rows--
+	}
+	b.WriteString(strings.Repeat("\n", max(rows, 0)))
+
+	position := fmt.Sprintf("%d-%d of %d", min(l.offset+1, len(l.lines)), end, len(l.lines))
+	if l.follow {
+		position += " · following"
+	}
+	b.WriteString(m.styleHints("  ↑↓ pgup pgdn scroll  g/G top/end  r reload  esc close  "))
+	b.WriteString(m.s.dim.Render(position))
+	b.WriteString("\n")
+	return b.String()
+}
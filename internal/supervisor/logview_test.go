@@ -0,0 +1,84 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func writeTestLog(t *testing.T, path string, n int) {
+	t.Helper()
+	var b strings.Builder
+	for i := range n {
+		level := "DEBUG"
+		if i == n-1 {
+			level = "ERROR"
+		}
+		fmt.Fprintf(&b, "time=2026-10-16T10:00:00Z level=%s msg=\"record %d\"\n", level, i)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogView_OpenScrollFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supervisor.log")
+	writeTestLog(t, path, 100)
+	m := newTestModel(simpleVerdict())
+	m.logFile = path
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("E")})
+	if m.logView == nil {
+		t.Fatal("expected E to open the log view")
+	}
+	view := m.View()
+	if !strings.Contains(view, "record 99") || strings.Contains(view, "record 0\"") {
+		t.Errorf("expected the view to start at the end of the log:\n%s", view)
+	}
+	if !strings.Contains(view, "following") {
+		t.Error("expected the view to follow the log")
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if m.logView.offset != 0 || m.logView.follow {
+		t.Errorf("g: offset=%d follow=%v", m.logView.offset, m.logView.follow)
+	}
+	// Scrolled up, new records do not move the view.
+	writeTestLog(t, path, 120)
+	_, _ = m.Update(scanResultMsg{result: &ScanResult{}})
+	if m.logView.offset != 0 {
+		t.Errorf("expected the view to stay at the top, offset=%d", m.logView.offset)
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	_, _ = m.Update(scanResultMsg{result: &ScanResult{}})
+	if !m.logView.follow || !strings.Contains(m.View(), "record 119") {
+		t.Errorf("expected G to follow the log again:\n%s", m.View())
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.logView != nil {
+		t.Error("expected esc to close the log view")
+	}
+}
+
+func TestLogView_Off(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("E")})
+	if m.logView != nil || !strings.Contains(m.message, "log is off") {
+		t.Errorf("expected a hint instead of the view, got %q", m.message)
+	}
+}
+
+func TestLogView_MissingFile(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.logFile = filepath.Join(t.TempDir(), "missing.log")
+	m.openLogView()
+	if !strings.Contains(m.View(), "the log is empty") {
+		t.Errorf("expected an empty log:\n%s", m.View())
+	}
+}
@@ -0,0 +1,154 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// Macro is a named sequence of raw keys recorded for one dialog (ctrl+r in
+// the raw key mode), for dialogs that need the same few keystrokes every
+// time (Tab Tab 2 Enter). Macros are stored with the remembered answers and
+// keyed the same way, the agent plus a hash of the dialog's WaitingFor
+// text; matching dialogs list them in the action panel after their own
+// actions. Unlike remembered answers they are only sent by hand.
+type Macro struct {
+	Name  string `json:"name"`
+	Agent string `json:"agent"`
+	Hash  string `json:"hash"` // dialogHash of the WaitingFor text
+	// Question is the first line of the dialog, shown on the management
+	// screen.
+	Question string    `json:"question"`
+	Keys     string    `json:"keys"` // space-separated tmux key names, sent raw
+	Created  time.Time `json:"created"`
+}
+
+// macroLabelPrefix marks macros among a dialog's actions.
+const macroLabelPrefix = "macro: "
+
+// Action returns the macro as an action sending its keys one by one.
+func (mac Macro) Action() model.Action {
+	return model.Action{Keys: mac.Keys, Label: macroLabelPrefix + mac.Name, Raw: true}
+}
+
+// RecordMacro stores keys as the macro name for v's dialog, replacing an
+// earlier macro of that name for the same dialog.
+func (mem *AnswerMemory) RecordMacro(v model.Verdict, name, keys string, now time.Time) {
+	mac := Macro{
+		Name:     name,
+		Agent:    v.Agent,
+		Hash:     dialogHash(v.WaitingFor),
+		Question: strings.TrimSpace(strings.SplitN(strings.TrimSpace(v.WaitingFor), "\n", 2)[0]),
+		Keys:     keys,
+		Created:  now,
+	}
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	for i, old := range mem.Macros {
+		if old.Agent == mac.Agent && old.Hash == mac.Hash && old.Name == mac.Name {
+			mem.Macros[i] = mac
+			return
+		}
+	}
+	mem.Macros = append(mem.Macros, mac)
+}
+
+// MacrosFor returns the macros recorded for v's dialog, oldest first.
+func (mem *AnswerMemory) MacrosFor(v model.Verdict) []Macro {
+	if mem == nil || !rememberable(v) {
+		return nil
+	}
+	hash := dialogHash(v.WaitingFor)
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	var out []Macro
+	for _, mac := range mem.Macros {
+		if mac.Agent == v.Agent && mac.Hash == hash {
+			out = append(out, mac)
+		}
+	}
+	return out
+}
+
+// ListMacros returns a copy of all recorded macros.
+func (mem *AnswerMemory) ListMacros() []Macro {
+	if mem == nil {
+		return nil
+	}
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	return append([]Macro(nil), mem.Macros...)
+}
+
+// ForgetMacro deletes the ith macro (as returned by ListMacros).
+func (mem *AnswerMemory) ForgetMacro(i int) {
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	if i >= 0 && i < len(mem.Macros) {
+		mem.Macros = append(mem.Macros[:i], mem.Macros[i+1:]...)
+	}
+}
+
+// withMacros returns v with the macros recorded for its dialog appended to
+// its actions, so the panel lists them and 1-9 and clicks send them. The
+// verdict itself is left alone: auto-nudge and remembered answers only
+// consider the dialog's own actions.
+func (m *tuiModel) withMacros(v model.Verdict) model.Verdict {
+	macros := m.answerMemory.MacrosFor(v)
+	if len(macros) == 0 {
+		return v
+	}
+	actions := append([]model.Action(nil), v.Actions...)
+	for _, mac := range macros {
+		actions = append(actions, mac.Action())
+	}
+	v.Actions = actions
+	return v
+}
+
+// toggleMacroRecording starts recording the keys sent in raw key mode as a
+// macro for the pane's current dialog, or stops and asks for its name.
+func (m *tuiModel) toggleMacroRecording() {
+	r := m.rawKeys
+	if r.recording {
+		r.recording = false
+		if len(r.recorded) == 0 {
+			m.message = "Recording stopped: no keys were sent"
+			return
+		}
+		r.naming, r.input = true, ""
+		m.message = ""
+		return
+	}
+	if m.answerMemory == nil {
+		m.message = "Macros are unavailable (see the startup warning)"
+		return
+	}
+	for _, v := range m.verdicts {
+		if v.Target != r.target {
+			continue
+		}
+		if !rememberable(v) {
+			m.message = fmt.Sprintf("%s shows no agent dialog to record a macro for", r.target)
+			return
+		}
+		r.recording, r.recorded, r.dialog = true, nil, v
+		m.message = "Recording: send the keys, then ctrl+r to stop"
+		return
+	}
+}
+
+// saveMacro stores the recorded keys under name.
+func (m *tuiModel) saveMacro(name string) {
+	r := m.rawKeys
+	keys := strings.Join(r.recorded, " ")
+	r.naming, r.recorded, r.input = false, nil, ""
+	m.answerMemory.RecordMacro(r.dialog, name, keys, time.Now())
+	if err := m.answerMemory.Save(); err != nil {
+		m.message = err.Error()
+		return
+	}
+	m.message = fmt.Sprintf("Saved macro '%s' (%s); the panel offers it for this dialog", name, keys)
+}
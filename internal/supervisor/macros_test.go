@@ -0,0 +1,87 @@
+package supervisor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMacro_RecordAndReplay(t *testing.T) {
+	v := permissionVerdict("dev")
+	m := newTestModel(v)
+	m.answerMemory, _ = LoadAnswerMemory(filepath.Join(t.TempDir(), "answers.json"), nil)
+	typeKeys := func(keys string) {
+		for _, r := range keys {
+			if r == ' ' {
+				_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeySpace})
+				continue
+			}
+			_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if !m.rawKeys.recording || !strings.Contains(m.View(), "recording") {
+		t.Fatalf("expected ctrl+r to start recording, message %q", m.message)
+	}
+	typeKeys("Tab Tab 2")
+	if cmd, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Fatal("expected the typed keys to be sent")
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}) // palette: Enter
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if !m.rawKeys.naming || !strings.Contains(m.View(), "recorded: Tab Tab 2 Enter") {
+		t.Fatalf("expected the name prompt, got:\n%s", m.View())
+	}
+	typeKeys("pick two")
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	reloaded, _ := LoadAnswerMemory(m.answerMemory.path, nil)
+	macros := reloaded.ListMacros()
+	if len(macros) != 1 || macros[0].Name != "pick two" || macros[0].Keys != "Tab Tab 2 Enter" {
+		t.Fatalf("saved macros: %+v", macros)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+
+	// The panel lists the macro after the dialog's own actions, and its
+	// number sends its keys raw.
+	lines, _ := m.renderActionPanel(&m.verdicts[0], 80, 20)
+	if panel := strings.Join(lines, "\n"); !strings.Contains(panel, "3. ") || !strings.Contains(panel, "macro: pick two") {
+		t.Errorf("expected the macro as action 3:\n%s", panel)
+	}
+	if len(m.verdicts[0].Actions) != 2 {
+		t.Error("the verdict's own actions should be left alone")
+	}
+	if cmd, _ := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}); cmd == nil {
+		t.Error("expected 3 to replay the macro")
+	}
+
+	// Another dialog does not offer it.
+	other := permissionVerdict("dev")
+	other.WaitingFor = "Bash — $ rm -rf build\nDo you want to proceed?"
+	if got := m.withMacros(other); len(got.Actions) != 2 {
+		t.Errorf("expected no macro for a different dialog, got %+v", got.Actions)
+	}
+
+	// Macros are listed and deleted on the management screen.
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")})
+	if view := m.View(); !strings.Contains(view, "Macros (1)") || !strings.Contains(view, "pick two") {
+		t.Errorf("expected the macro listed:\n%s", view)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if len(m.answerMemory.ListMacros()) != 0 {
+		t.Error("expected x to delete the macro")
+	}
+}
+
+func TestMacro_NeedsADialog(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.answerMemory, _ = LoadAnswerMemory(filepath.Join(t.TempDir(), "answers.json"), nil)
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m.rawKeys.recording || !strings.Contains(m.message, "no agent dialog") {
+		t.Errorf("expected recording to be refused, message %q", m.message)
+	}
+}
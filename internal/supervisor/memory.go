@@ -0,0 +1,336 @@
+package supervisor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// AnswerMemory holds the answers to recurring dialogs the user asked to
+// remember (R after answering). A remembered answer is keyed to one exact
+// dialog, the agent plus a hash of its WaitingFor text, and is sent again
+// whenever that dialog reappears, in one session or in all of them. Unlike
+// auto-nudge it does not depend on risk levels: the user chose the answer
+// for this very dialog. A nil *AnswerMemory remembers nothing.
+type AnswerMemory struct {
+	path string
+	key  *StateKey
+
+	mu      sync.Mutex
+	Answers []RememberedAnswer `json:"answers"`
+	Macros  []Macro            `json:"macros,omitempty"` // recorded key sequences, see Macro
+}
+
+// RememberedAnswer is one remembered answer.
+type RememberedAnswer struct {
+	Agent string `json:"agent"`
+	Hash  string `json:"hash"` // dialogHash of the WaitingFor text
+	// Session limits the answer to one session; empty applies it in every
+	// session.
+	Session string `json:"session,omitempty"`
+	// Question is the first line of the dialog, shown on the management
+	// screen.
+	Question string    `json:"question"`
+	Keys     string    `json:"keys"`
+	Label    string    `json:"label"`
+	Created  time.Time `json:"created"`
+}
+
+// DefaultAnswersPath is where remembered answers are stored when no
+// answers_file is configured: ~/.config/pane-patrol/answers.json.
+func DefaultAnswersPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "pane-patrol", "answers.json")
+}
+
+// LoadAnswerMemory reads remembered answers from path (a leading "~" is
+// expanded), encrypted with key unless it is nil. A missing file yields an
+// empty memory that is created on the first Save.
+func LoadAnswerMemory(path string, key *StateKey) (*AnswerMemory, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	mem := &AnswerMemory{path: path, key: key}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return mem, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading remembered answers: %w", err)
+	}
+	if data, err = key.open(path, data); err != nil {
+		return nil, fmt.Errorf("reading remembered answers: %w", err)
+	}
+	if err := json.Unmarshal(data, mem); err != nil {
+		return nil, fmt.Errorf("parsing remembered answers %s: %w", path, err)
+	}
+	return mem, nil
+}
+
+// Save writes the answers to their file, replacing it atomically. Answers
+// are text typed into panes, so the file is only readable by the owner.
+func (mem *AnswerMemory) Save() error {
+	mem.mu.Lock()
+	data, err := json.MarshalIndent(mem, "", "  ")
+	mem.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(mem.path), 0o755); err != nil {
+		return fmt.Errorf("saving remembered answers: %w", err)
+	}
+	tmp := mem.path + ".tmp"
+	if err := os.WriteFile(tmp, append(mem.key.seal(data), '\n'), 0o600); err != nil {
+		return fmt.Errorf("saving remembered answers: %w", err)
+	}
+	if err := os.Rename(tmp, mem.path); err != nil {
+		return fmt.Errorf("saving remembered answers: %w", err)
+	}
+	return nil
+}
+
+// dialogHash identifies a dialog by its exact text.
+func dialogHash(waitingFor string) string {
+	sum := sha256.Sum256([]byte(waitingFor))
+	return hex.EncodeToString(sum[:8])
+}
+
+// rememberable reports whether v's dialog can be remembered: an agent's
+// block with dialog text to key on. Generic prompts (passwords, [y/N]) are
+// never remembered.
+func rememberable(v model.Verdict) bool {
+	return announceable(v) && strings.TrimSpace(v.WaitingFor) != ""
+}
+
+// Remember stores a as the answer to v's dialog, in v's session only or in
+// every session, replacing an earlier answer to the same dialog and scope.
+func (mem *AnswerMemory) Remember(v model.Verdict, a model.Action, everySession bool, now time.Time) {
+	r := RememberedAnswer{
+		Agent:    v.Agent,
+		Hash:     dialogHash(v.WaitingFor),
+		Question: strings.TrimSpace(strings.SplitN(strings.TrimSpace(v.WaitingFor), "\n", 2)[0]),
+		Keys:     a.Keys,
+		Label:    a.Label,
+		Created:  now,
+	}
+	if !everySession {
+		r.Session = v.Session
+	}
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	for i, old := range mem.Answers {
+		if old.Agent == r.Agent && old.Hash == r.Hash && old.Session == r.Session {
+			mem.Answers[i] = r
+			return
+		}
+	}
+	mem.Answers = append(mem.Answers, r)
+}
+
+// Lookup returns the remembered answer to v's dialog as one of v's current
+// actions; an answer for v's session wins over one for every session. The
+// action must still be offered (same keys and label), so a dialog that
+// changed shape is not answered blindly.
+func (mem *AnswerMemory) Lookup(v model.Verdict) (model.Action, bool) {
+	if mem == nil || !rememberable(v) {
+		return model.Action{}, false
+	}
+	hash := dialogHash(v.WaitingFor)
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	var match *RememberedAnswer
+	for i, r := range mem.Answers {
+		if r.Agent != v.Agent || r.Hash != hash || (r.Session != "" && r.Session != v.Session) {
+			continue
+		}
+		if match == nil || r.Session != "" {
+			match = &mem.Answers[i]
+		}
+	}
+	if match == nil {
+		return model.Action{}, false
+	}
+	for _, a := range v.Actions {
+		if a.Keys == match.Keys && a.Label == match.Label {
+			return a, true
+		}
+	}
+	return model.Action{}, false
+}
+
+// List returns a copy of the remembered answers.
+func (mem *AnswerMemory) List() []RememberedAnswer {
+	if mem == nil {
+		return nil
+	}
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	return append([]RememberedAnswer(nil), mem.Answers...)
+}
+
+// Forget deletes the ith remembered answer (as returned by List).
+func (mem *AnswerMemory) Forget(i int) {
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+	if i >= 0 && i < len(mem.Answers) {
+		mem.Answers = append(mem.Answers[:i], mem.Answers[i+1:]...)
+	}
+}
+
+// lastAnswer is the most recent action sent by hand to a rememberable
+// dialog, offered for remembering with R.
+type lastAnswer struct {
+	verdict model.Verdict
+	action  model.Action
+}
+
+// handleRememberKey handles the scope prompt opened with R: s remembers the
+// answer for the pane's session, g for every session.
+func (m *tuiModel) handleRememberKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	last := m.remember
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.remember = nil
+	case "s", "g":
+		m.remember = nil
+		m.lastAnswer = nil
+		everySession := msg.String() == "g"
+		m.answerMemory.Remember(last.verdict, last.action, everySession, time.Now())
+		if err := m.answerMemory.Save(); err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		scope := "in session " + last.verdict.Session
+		if everySession {
+			scope = "in every session"
+		}
+		m.message = fmt.Sprintf("Will answer '%s' to this dialog %s (M to review)", last.action.Label, scope)
+	}
+	return m, nil
+}
+
+// viewRemember renders the scope prompt.
+func (m *tuiModel) viewRemember() string {
+	last := m.remember
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Remember answer"))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(last.verdict.Target))
+	b.WriteString("\n\n")
+	for _, line := range strings.Split(strings.TrimSpace(last.verdict.WaitingFor), "\n") {
+		b.WriteString(m.s.dim.Render("  " + truncate(strings.TrimSpace(line), m.width-4)))
+		b.WriteString("\n")
+	}
+	b.WriteString(fmt.Sprintf("\n  Answer '%s' automatically whenever %s shows this dialog again?\n\n",
+		last.action.Label, last.verdict.Agent))
+	b.WriteString(m.styleHints(fmt.Sprintf("  s only in session %s  g in every session  esc cancel", last.verdict.Session)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// answersScreen is the management screen of remembered answers and
+// macros (M). The cursor runs over the answers, then the macros.
+type answersScreen struct {
+	cursor int
+}
+
+// handleAnswersScreenKey navigates the remembered answers and macros; x
+// deletes one.
+func (m *tuiModel) handleAnswersScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.answersScreen
+	answers, macros := m.answerMemory.List(), m.answerMemory.ListMacros()
+	total := len(answers) + len(macros)
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "M":
+		m.answersScreen = nil
+	case "up", "k":
+		s.cursor = max(s.cursor-1, 0)
+	case "down", "j":
+		s.cursor = min(s.cursor+1, max(total-1, 0))
+	case "x", "delete":
+		if s.cursor >= total {
+			return m, nil
+		}
+		if s.cursor < len(answers) {
+			m.answerMemory.Forget(s.cursor)
+		} else {
+			m.answerMemory.ForgetMacro(s.cursor - len(answers))
+		}
+		if err := m.answerMemory.Save(); err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		if s.cursor < len(answers) {
+			m.message = fmt.Sprintf("Forgot '%s' for %q", answers[s.cursor].Label, answers[s.cursor].Question)
+		} else {
+			mac := macros[s.cursor-len(answers)]
+			m.message = fmt.Sprintf("Deleted macro '%s' for %q", mac.Name, mac.Question)
+		}
+		s.cursor = min(s.cursor, max(total-2, 0))
+	}
+	return m, nil
+}
+
+// viewAnswersScreen lists the remembered answers and macros.
+func (m *tuiModel) viewAnswersScreen() string {
+	s := m.answersScreen
+	answers, macros := m.answerMemory.List(), m.answerMemory.ListMacros()
+	var b strings.Builder
+	b.WriteString(m.s.title.Render(fmt.Sprintf("Remembered answers (%d)", len(answers))))
+	b.WriteString("\n\n")
+	if len(answers) == 0 {
+		b.WriteString(m.s.dim.Render("  none yet: answer a dialog, then press R to remember the answer"))
+		b.WriteString("\n")
+	}
+	marker := func(i int) string {
+		if i == s.cursor {
+			return m.s.selected.Render(m.glyphs().cursor)
+		}
+		return "  "
+	}
+	for i, r := range answers {
+		scope := "all sessions"
+		if r.Session != "" {
+			scope = "session " + r.Session
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s\n", marker(i), m.s.header.Render(r.Label), m.s.dim.Render("· "+r.Agent+" · "+scope)))
+		b.WriteString(m.s.dim.Render("    " + truncate(r.Question, m.width-6)))
+		b.WriteString("\n")
+	}
+	if len(macros) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.s.title.Render(fmt.Sprintf("Macros (%d)", len(macros))))
+		b.WriteString("\n\n")
+	}
+	for i, mac := range macros {
+		b.WriteString(fmt.Sprintf("%s%s %s\n", marker(len(answers)+i), m.s.header.Render(mac.Name), m.s.dim.Render("· "+mac.Agent+" · "+mac.Keys)))
+		b.WriteString(m.s.dim.Render("    " + truncate(mac.Question, m.width-6)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styleHints("  ↑↓ select  x forget  esc close"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
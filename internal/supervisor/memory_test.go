@@ -0,0 +1,127 @@
+package supervisor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func permissionVerdict(session string) model.Verdict {
+	return model.Verdict{
+		Target:     session + ":0.0",
+		Session:    session,
+		Agent:      "claude_code",
+		Blocked:    true,
+		Reason:     "permission dialog",
+		WaitingFor: "Bash — $ go test ./...\nDo you want to proceed?",
+		Actions: []model.Action{
+			{Keys: "1", Label: "approve (yes)", Risk: "medium", Raw: true},
+			{Keys: "3", Label: "deny (no)", Risk: "low", Raw: true},
+		},
+	}
+}
+
+func TestAnswerMemory_Lookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.json")
+	mem, err := LoadAnswerMemory(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev, ops := permissionVerdict("dev"), permissionVerdict("ops")
+	mem.Remember(dev, dev.Actions[0], true, time.Now())
+	mem.Remember(dev, dev.Actions[1], false, time.Now())
+	if err := mem.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	mem, err = LoadAnswerMemory(path, nil)
+	if err != nil || len(mem.List()) != 2 {
+		t.Fatalf("expected 2 persisted answers, got %+v, %v", mem.List(), err)
+	}
+	// The session's own answer wins over the one for every session.
+	if a, ok := mem.Lookup(dev); !ok || a.Label != "deny (no)" {
+		t.Errorf("dev: got %+v, %v", a, ok)
+	}
+	if a, ok := mem.Lookup(ops); !ok || a.Label != "approve (yes)" {
+		t.Errorf("ops: got %+v, %v", a, ok)
+	}
+
+	// A different dialog, or one no longer offering the action, is not answered.
+	other := permissionVerdict("ops")
+	other.WaitingFor = "Bash — $ rm -rf build\nDo you want to proceed?"
+	if _, ok := mem.Lookup(other); ok {
+		t.Error("expected no answer for a different dialog")
+	}
+	ops.Actions = ops.Actions[1:]
+	if _, ok := mem.Lookup(ops); ok {
+		t.Error("expected no answer when the remembered action is gone")
+	}
+
+	mem.Forget(0)
+	if _, ok := mem.Lookup(permissionVerdict("ops")); ok {
+		t.Error("expected the forgotten answer not to apply")
+	}
+	var nilMem *AnswerMemory
+	if _, ok := nilMem.Lookup(dev); ok {
+		t.Error("nil memory should remember nothing")
+	}
+}
+
+func TestRemember_OfferedAfterAnswer(t *testing.T) {
+	v := permissionVerdict("dev")
+	m := newTestModel(v)
+	m.answerMemory, _ = LoadAnswerMemory(filepath.Join(t.TempDir(), "answers.json"), nil)
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.remember != nil {
+		t.Fatal("expected R to do nothing before an answer was sent")
+	}
+
+	m.Update(nudgeResultMsg{messages: []string{"sent '1' (approve (yes)) to dev:0.0"}, sent: 1,
+		answer: &lastAnswer{verdict: v, action: v.Actions[0]}})
+	if !strings.Contains(m.message, "R to always answer this") {
+		t.Errorf("expected the offer in the status line, got %q", m.message)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if m.remember == nil || !strings.Contains(m.View(), "Do you want to proceed?") {
+		t.Fatal("expected R to open the remember prompt")
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	answers := m.answerMemory.List()
+	if len(answers) != 1 || answers[0].Session != "dev" || answers[0].Keys != "1" {
+		t.Fatalf("remembered: got %+v", answers)
+	}
+
+	// The next scan answers the dialog although auto-nudge is off.
+	if cmd := m.autoNudgeCmd(); cmd == nil {
+		t.Error("expected the remembered answer to be sent")
+	}
+}
+
+func TestAnswersScreen_Forget(t *testing.T) {
+	v := permissionVerdict("dev")
+	m := newTestModel(v)
+	m.answerMemory, _ = LoadAnswerMemory(filepath.Join(t.TempDir(), "answers.json"), nil)
+	m.answerMemory.Remember(v, v.Actions[1], true, time.Now())
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("M")})
+	if view := m.View(); !strings.Contains(view, "deny (no)") || !strings.Contains(view, "all sessions") {
+		t.Errorf("expected the remembered answer listed:\n%s", view)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if len(m.answerMemory.List()) != 0 {
+		t.Error("expected x to forget the answer")
+	}
+	reloaded, _ := LoadAnswerMemory(m.answerMemory.path, nil)
+	if len(reloaded.List()) != 0 {
+		t.Error("expected the deletion to be saved")
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.answersScreen != nil {
+		t.Error("expected esc to close the screen")
+	}
+}
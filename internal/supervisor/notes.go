@@ -0,0 +1,137 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// maxNoteLen caps pane notes; they are shown on one line of the action
+// panel.
+const maxNoteLen = 200
+
+// PaneNote is a free-text note on a pane ("waiting on infra ticket"),
+// attached with N. PID is the pane's process when the note was written: a
+// respawned pane (e.g. a restarted agent) gets a new PID, so a note about
+// the previous agent is not shown for the new one.
+type PaneNote struct {
+	Text string `json:"text"`
+	PID  int    `json:"pid,omitempty"`
+}
+
+// Note returns the note of the pane at target when it was written for the
+// process pid (or either PID is unknown), or "".
+func (l *Labels) Note(target string, pid int) string {
+	if l == nil {
+		return ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n, ok := l.Notes[target]
+	if !ok || (n.PID != 0 && pid != 0 && n.PID != pid) {
+		return ""
+	}
+	return n.Text
+}
+
+// SetNote attaches a note to the pane at target running process pid; an
+// empty note removes it.
+func (l *Labels) SetNote(target string, pid int, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if text = cleanNote(text); text == "" {
+		delete(l.Notes, target)
+	} else {
+		l.Notes[target] = PaneNote{Text: text, PID: pid}
+	}
+}
+
+// NotesFor returns the notes of the given verdicts' panes by target.
+func (l *Labels) NotesFor(verdicts []model.Verdict) map[string]string {
+	notes := map[string]string{}
+	for _, v := range verdicts {
+		if note := l.Note(v.Target, v.PID); note != "" {
+			notes[v.Target] = note
+		}
+	}
+	return notes
+}
+
+// cleanNote collapses whitespace and caps the length.
+func cleanNote(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxNoteLen {
+		text = string(runes[:maxNoteLen])
+	}
+	return text
+}
+
+// noteInput is the note being typed for a pane (opened with N), prefilled
+// with the current note. An empty note removes it.
+type noteInput struct {
+	target string
+	pid    int
+	input  []rune
+}
+
+// handleNoteKey handles keys while typing a note: enter saves, esc cancels.
+func (m *tuiModel) handleNoteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	n := m.note
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.note = nil
+	case tea.KeyEnter:
+		m.note = nil
+		m.saveNote(n.target, n.pid, string(n.input))
+	case tea.KeyBackspace:
+		if len(n.input) > 0 {
+			n.input = n.input[:len(n.input)-1]
+		}
+	case tea.KeyCtrlU:
+		n.input = nil
+	case tea.KeySpace:
+		n.input = append(n.input, ' ')
+	case tea.KeyRunes:
+		if len(n.input)+len(msg.Runes) <= maxNoteLen {
+			n.input = append(n.input, msg.Runes...)
+		}
+	}
+	return m, nil
+}
+
+// saveNote stores and persists a note.
+func (m *tuiModel) saveNote(target string, pid int, text string) {
+	m.labels.SetNote(target, pid, text)
+	if err := m.labels.Save(); err != nil {
+		m.message = err.Error()
+		return
+	}
+	if cleanNote(text) == "" {
+		m.message = fmt.Sprintf("Removed note of %s", target)
+	} else {
+		m.message = fmt.Sprintf("Noted on %s", target)
+	}
+}
+
+// viewNote renders the note input.
+func (m *tuiModel) viewNote() string {
+	n := m.note
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Note"))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(n.target))
+	b.WriteString("\n\n  note: ")
+	b.WriteString(string(n.input))
+	b.WriteString("█\n\n")
+	b.WriteString(m.styleHints("  enter save (empty removes)  ctrl+u clear  esc cancel"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package supervisor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLabels_NoteFollowsPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.json")
+	l, _ := LoadLabels(path, nil)
+	l.SetNote("dev:0.3", 4242, "  waiting on   infra ticket ")
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	l, err := LoadLabels(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLabels: %v", err)
+	}
+	if got := l.Note("dev:0.3", 4242); got != "waiting on infra ticket" {
+		t.Errorf("Note: got %q", got)
+	}
+	if got := l.Note("dev:0.3", 0); got != "waiting on infra ticket" {
+		t.Errorf("Note with unknown PID: got %q", got)
+	}
+	if got := l.Note("dev:0.3", 999); got != "" {
+		t.Errorf("a respawned pane should not inherit the note, got %q", got)
+	}
+
+	l.SetNote("dev:0.3", 4242, " ")
+	if _, ok := l.Notes["dev:0.3"]; ok {
+		t.Error("empty note should remove the note")
+	}
+	var nilLabels *Labels
+	if nilLabels.Note("dev:0.3", 4242) != "" || len(nilLabels.NotesFor(nil)) != 0 {
+		t.Error("nil labels should have no notes")
+	}
+}
+
+func TestNote_KeyOpensPrefilledInput(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	labels, _ := LoadLabels(filepath.Join(t.TempDir(), "labels.json"), nil)
+	m.labels = labels
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if m.note == nil {
+		t.Fatal("expected N to open the note input")
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("deny prod")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := labels.Note("test:0.0", 0); got != "deny prod" {
+		t.Fatalf("note: got %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "✎ deny prod") {
+		t.Errorf("expected the note in the action panel:\n%s", view)
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if string(m.note.input) != "deny prod" {
+		t.Errorf("expected pre-filled note, got %q", string(m.note.input))
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+
+	reloaded, err := LoadLabels(labels.path, nil)
+	if err != nil || reloaded.Note("test:0.0", 0) != "deny prod" {
+		t.Errorf("note not persisted: %+v, %v", reloaded, err)
+	}
+}
+
+func TestNote_WithoutLabels(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if m.note != nil || m.message == "" {
+		t.Error("expected a message instead of the note input when labels are unavailable")
+	}
+}
+
+func TestAnnouncementText_Note(t *testing.T) {
+	labels, _ := LoadLabels(filepath.Join(t.TempDir(), "labels.json"), nil)
+	v := simpleVerdict()
+	v.Reason = "permission required"
+	labels.SetNote(v.Target, 0, "deny prod")
+	if got := announcementText(v, labels); got != "Session test blocked: permission required. Note: deny prod" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -7,9 +7,14 @@ package supervisor
 
 import (
 	"fmt"
+	"log/slog"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/timvw/pane-patrol/internal/logging"
+	"github.com/timvw/pane-patrol/internal/mux"
 )
 
 // SendKeysFunc sends keys to a pane with an optional flag (e.g. "-l" for literal mode).
@@ -20,11 +25,15 @@ type SendKeysFunc func(paneID, flag, keys string) error
 // defaultSendKeys runs tmux send-keys with optional flags.
 func defaultSendKeys(paneID, flag, keys string) error {
 	var args []string
-	args = append(args, "send-keys", "-t", paneID)
-	if flag != "" {
-		args = append(args, flag)
+	if flag == "-l" {
+		args = mux.SendKeysArgs(paneID, true, keys)
+	} else {
+		args = []string{"send-keys", "-t", paneID}
+		if flag != "" {
+			args = append(args, flag)
+		}
+		args = append(args, keys)
 	}
-	args = append(args, keys)
 
 	cmd := exec.Command("tmux", args...)
 	if out, err := cmd.CombinedOutput(); err != nil {
@@ -33,18 +42,47 @@ func defaultSendKeys(paneID, flag, keys string) error {
 	return nil
 }
 
+// PasteFunc pastes multi-line text into a pane. The default implementation
+// uses a tmux paste buffer. Tests can replace this to avoid exec.Command.
+type PasteFunc func(paneID, text string) error
+
+// pasteBuffer is the tmux buffer used for multi-line text; paste-buffer -d
+// deletes it again after pasting.
+const pasteBuffer = "pane-patrol"
+
+// defaultPaste loads text into a tmux buffer and pastes it with bracketed
+// paste (-p) when the application requested it, so agents insert the
+// newlines into their input instead of submitting each line.
+func defaultPaste(paneID, text string) error {
+	load := exec.Command("tmux", "load-buffer", "-b", pasteBuffer, "-")
+	load.Stdin = strings.NewReader(text)
+	if out, err := load.CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux load-buffer failed: %w (output: %s)", err, string(out))
+	}
+	paste := exec.Command("tmux", "paste-buffer", "-p", "-d", "-b", pasteBuffer, "-t", paneID)
+	if out, err := paste.CombinedOutput(); err != nil {
+		return fmt.Errorf("tmux paste-buffer failed: %w (output: %s)", err, string(out))
+	}
+	return nil
+}
+
 // Nudger sends keystroke sequences to tmux panes using the Gastown-reliable
 // nudge pattern. Inject a custom SendKeys function for testing.
 type Nudger struct {
 	SendKeys SendKeysFunc
+	// Paste pastes multi-line text. Defaults to a tmux paste buffer.
+	Paste PasteFunc
 	// Sleep is an injectable delay function. Defaults to time.Sleep.
 	Sleep func(time.Duration)
+	// Log records every send; nil discards.
+	Log *slog.Logger
 }
 
 // DefaultNudger returns a Nudger that shells out to tmux.
 func DefaultNudger() *Nudger {
 	return &Nudger{
 		SendKeys: defaultSendKeys,
+		Paste:    defaultPaste,
 		Sleep:    time.Sleep,
 	}
 }
@@ -56,12 +94,27 @@ func DefaultNudger() *Nudger {
 //
 // When raw is false (default), literal text (e.g., "yes", "continue") is sent
 // with -l flag using the Gastown pattern: literal → debounce → Escape → Enter.
+// Multi-line text is pasted instead of typed (see PasteFunc) so its newlines
+// do not submit the agent's input early; Enter is sent once at the end.
 // Control sequences (e.g., "C-c", "Enter") are always sent raw regardless.
+// A nil *Nudger shells out to tmux.
 func (n *Nudger) NudgePane(paneID, keys string, raw bool) error {
+	if n == nil {
+		n = DefaultNudger()
+	}
+	var err error
 	if raw || isControlSequence(keys) {
-		return n.nudgeRaw(paneID, keys)
+		err = n.nudgeRaw(paneID, keys)
+	} else {
+		err = n.nudgeLiteral(paneID, keys)
 	}
-	return n.nudgeLiteral(paneID, keys)
+	log := logging.OrDiscard(n.Log)
+	if err != nil {
+		log.Warn("send failed", "target", paneID, "keys", keys, "raw", raw, "err", err)
+	} else {
+		log.Info("keys sent", "target", paneID, "keys", keys, "raw", raw)
+	}
+	return err
 }
 
 // nudgeLiteral sends literal text followed by Enter (Gastown-reliable pattern).
@@ -70,13 +123,18 @@ func (n *Nudger) nudgeLiteral(paneID, keys string) error {
 	if sleep == nil {
 		sleep = time.Sleep
 	}
-	sendKeys := n.SendKeys
-	if sendKeys == nil {
-		sendKeys = defaultSendKeys
-	}
+	sendKeys := n.sendKeysFunc()
 
-	// 1. Send text in literal mode
-	if err := sendKeys(paneID, "-l", keys); err != nil {
+	// 1. Send text in literal mode, or paste it if it spans lines
+	if strings.Contains(keys, "\n") {
+		paste := n.Paste
+		if paste == nil {
+			paste = defaultPaste
+		}
+		if err := paste(paneID, keys); err != nil {
+			return fmt.Errorf("paste text: %w", err)
+		}
+	} else if err := sendKeys(paneID, "-l", keys); err != nil {
 		return fmt.Errorf("send literal keys: %w", err)
 	}
 
@@ -109,10 +167,7 @@ func (n *Nudger) nudgeLiteral(paneID, keys string) error {
 // literal characters (y, n, etc.) are sent with the -l flag so tmux
 // delivers the actual character to the TUI's stdin.
 func (n *Nudger) nudgeRaw(paneID, keys string) error {
-	sendKeys := n.SendKeys
-	if sendKeys == nil {
-		sendKeys = defaultSendKeys
-	}
+	sendKeys := n.sendKeysFunc()
 	sleep := n.Sleep
 	if sleep == nil {
 		sleep = time.Sleep
@@ -134,6 +189,15 @@ func (n *Nudger) nudgeRaw(paneID, keys string) error {
 	return nil
 }
 
+// sendKeysFunc returns n.SendKeys, or tmux send-keys when n or its
+// SendKeys is nil.
+func (n *Nudger) sendKeysFunc() SendKeysFunc {
+	if n == nil || n.SendKeys == nil {
+		return defaultSendKeys
+	}
+	return n.SendKeys
+}
+
 // splitKeySequence splits a key string by spaces into individual tokens.
 // Each token is either a tmux control sequence name (Enter, Down, C-c, etc.)
 // or a literal character/string (y, n, etc.). The caller is responsible for
@@ -163,9 +227,7 @@ func NudgePane(paneID, keys string, raw bool) error {
 // isControlSequence returns true if the keys string is a tmux control sequence
 // rather than literal text to type.
 func isControlSequence(keys string) bool {
-	switch keys {
-	case "Enter", "Escape", "Up", "Down", "Left", "Right",
-		"Tab", "BTab", "Space", "BSpace", "DC":
+	if isNamedKey(keys) {
 		return true
 	}
 	// C-x patterns (Ctrl+key)
@@ -176,5 +238,25 @@ func isControlSequence(keys string) bool {
 	if len(keys) == 3 && keys[0] == 'M' && keys[1] == '-' {
 		return true
 	}
+	// Modifiers on named keys, e.g. "C-Up", "M-Left", "S-Tab"
+	if len(keys) > 2 && keys[1] == '-' && strings.ContainsRune("CMS", rune(keys[0])) {
+		return isNamedKey(keys[2:])
+	}
+	return false
+}
+
+// isNamedKey reports whether keys is a tmux key name (as opposed to a
+// character), e.g. "Enter", "PPage", "F5".
+func isNamedKey(keys string) bool {
+	switch keys {
+	case "Enter", "Escape", "Up", "Down", "Left", "Right",
+		"Tab", "BTab", "Space", "BSpace", "DC",
+		"Home", "End", "IC", "PPage", "NPage", "PageUp", "PageDown", "PgUp", "PgDn":
+		return true
+	}
+	// Function keys F1-F12
+	if n, err := strconv.Atoi(strings.TrimPrefix(keys, "F")); err == nil && keys[0] == 'F' && n >= 1 && n <= 12 {
+		return true
+	}
 	return false
 }
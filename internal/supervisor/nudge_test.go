@@ -2,6 +2,7 @@ package supervisor
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"testing"
 	"time"
@@ -57,6 +58,34 @@ func TestNudger_LiteralText(t *testing.T) {
 	}
 }
 
+func TestNudger_MultilineTextIsPasted(t *testing.T) {
+	var calls []sendKeysCall
+	var pasted []string
+	nudger := &Nudger{
+		SendKeys: func(paneID, flag, keys string) error {
+			calls = append(calls, sendKeysCall{paneID, flag, keys})
+			return nil
+		},
+		Paste: func(paneID, text string) error {
+			pasted = append(pasted, text)
+			return nil
+		},
+		Sleep: func(d time.Duration) {},
+	}
+
+	text := "Stop editing the lockfile.\nRun the tests first."
+	if err := nudger.NudgePane("session:0.0", text, false); err != nil {
+		t.Fatalf("NudgePane() error: %v", err)
+	}
+	if len(pasted) != 1 || pasted[0] != text {
+		t.Fatalf("expected the whole text pasted once, got %q", pasted)
+	}
+	// No literal send-keys: only Escape and a single Enter follow the paste.
+	if len(calls) != 2 || calls[0].keys != "Escape" || calls[1].keys != "Enter" {
+		t.Errorf("expected Escape, Enter after paste, got %+v", calls)
+	}
+}
+
 func TestNudger_ControlSequence(t *testing.T) {
 	var calls []sendKeysCall
 	nudger := &Nudger{
@@ -345,6 +374,17 @@ func TestIsControlSequence(t *testing.T) {
 		{"Space", true},
 		{"BSpace", true},
 		{"DC", true},
+		{"PPage", true},
+		{"NPage", true},
+		{"PageUp", true},
+		{"Home", true},
+		{"F5", true},
+		{"F12", true},
+
+		// Modifiers on named keys
+		{"C-Up", true},
+		{"M-Left", true},
+		{"S-Tab", true},
 
 		// Ctrl+key patterns
 		{"C-c", true},
@@ -371,6 +411,10 @@ func TestIsControlSequence(t *testing.T) {
 		{"C-cc", false},  // too long for Ctrl pattern
 		{"CC-c", false},  // wrong prefix position
 		{"enter", false}, // lowercase (tmux keys are case-sensitive)
+		{"F13", false},   // no such function key
+		{"F", false},     // literal capital F
+		{"S-x", false},   // Shift only applies to named keys
+		{"C-Upp", false}, // modifier on an unknown name
 	}
 
 	for _, tt := range tests {
@@ -382,3 +426,27 @@ func TestIsControlSequence(t *testing.T) {
 		})
 	}
 }
+
+func TestNudger_LogsSends(t *testing.T) {
+	var buf strings.Builder
+	nudger := &Nudger{
+		SendKeys: func(paneID, flag, keys string) error {
+			if paneID == "gone:0.0" {
+				return fmt.Errorf("can't find pane")
+			}
+			return nil
+		},
+		Sleep: func(d time.Duration) {},
+		Log:   slog.New(slog.NewTextHandler(&buf, nil)),
+	}
+	_ = nudger.NudgePane("api:0.1", "1", true)
+	_ = nudger.NudgePane("gone:0.0", "1", true)
+
+	out := buf.String()
+	if !strings.Contains(out, `level=INFO msg="keys sent" target=api:0.1 keys=1 raw=true`) {
+		t.Errorf("expected the send to be logged:\n%s", out)
+	}
+	if !strings.Contains(out, `level=WARN msg="send failed" target=gone:0.0`) {
+		t.Errorf("expected the failure to be logged:\n%s", out)
+	}
+}
@@ -0,0 +1,72 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// optionKey reports whether a is a number key picking an option of v's
+// question dialog. Agents may re-render such a dialog with other options
+// between the scan and the keypress, so these keys are checked against the
+// pane before they are sent.
+func optionKey(v model.Verdict, a model.Action) bool {
+	if v.Dialog == nil || v.Dialog.Kind != model.DialogQuestion || !a.Raw {
+		return false
+	}
+	return len(a.Keys) == 1 && a.Keys[0] >= '1' && a.Keys[0] <= '9'
+}
+
+// checkDialog captures v's pane afresh, parses it with the process tree of
+// the scan, and returns an error describing how it changed unless it still
+// shows v's state: the same reason and, for a dialog, the same kind and
+// question. For an option key (optionKey) the option must still exist with
+// the same label; labels are compared without checkbox state, which
+// toggling changes. Verdicts from hook events have no parsed screen to
+// compare with and are not checked.
+func (s *Scanner) checkDialog(ctx context.Context, v model.Verdict, a model.Action) error {
+	if s == nil || s.Mux == nil || s.Parsers == nil || v.EvalSource == model.EvalSourceEvent {
+		return nil
+	}
+	capture, err := s.Mux.CapturePane(ctx, v.Target)
+	if err != nil {
+		return fmt.Errorf("capture failed: %w", err)
+	}
+	r := s.Parsers.Parse(capture, v.ProcessTree)
+	if r == nil || !r.Blocked || r.Agent != v.Agent || r.Reason != v.Reason {
+		now := "nothing to answer"
+		if r != nil && r.Blocked {
+			now = r.Reason
+		}
+		return fmt.Errorf("dialog changed, review again: the pane now shows %s", now)
+	}
+	if v.Dialog == nil {
+		return nil
+	}
+	d := r.Dialog
+	if d == nil || d.Kind != v.Dialog.Kind || d.Question != v.Dialog.Question {
+		question := ""
+		if d != nil {
+			question = d.Question
+		}
+		return fmt.Errorf("dialog changed, review again: the question is now %q", question)
+	}
+	if !optionKey(v, a) {
+		return nil
+	}
+	options := 0
+	for _, fresh := range r.Actions {
+		if optionKey(model.Verdict{Dialog: d}, fresh) {
+			options++
+		}
+	}
+	n := int(a.Keys[0] - '0')
+	if n > options {
+		return fmt.Errorf("dialog changed, review again: it offers %d options, not %d", options, n)
+	}
+	if n <= len(v.Dialog.Options) && n <= len(d.Options) && d.Options[n-1].Label != v.Dialog.Options[n-1].Label {
+		return fmt.Errorf("dialog changed, review again: option %d is now %q", n, d.Options[n-1].Label)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package supervisor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// NudgeOutcome is what an action sent to a blocked pane achieved, judged
+// from the pane's next scans.
+type NudgeOutcome string
+
+const (
+	OutcomeUnblocked    NudgeOutcome = "unblocked"     // working again, or moved on to another question
+	OutcomeStillBlocked NudgeOutcome = "still_blocked" // same question outcomeScans scans later, or sent another action
+	OutcomeFailed       NudgeOutcome = "failed"        // pane closed or could not be evaluated
+)
+
+// outcomeScans is how many scans after an action a pane may take to move
+// on before it counts as still blocked. Agents can take a few seconds to
+// react, and the scan running while the keys were sent still shows the old
+// state.
+const outcomeScans = 3
+
+// blockedState identifies the question a blocked pane is waiting on, so a
+// pane that went straight to the next dialog is told apart from one that
+// ignored the keys.
+func blockedState(v model.Verdict) string {
+	return v.Reason + "\n" + v.WaitingFor
+}
+
+// pendingNudge is an action sent to a pane whose outcome is not known yet.
+type pendingNudge struct {
+	task  nudgeTask
+	auto  bool
+	sent  time.Time
+	scans int // scans seen since it was sent
+}
+
+// resolvedNudge is an action whose outcome is known.
+type resolvedNudge struct {
+	pendingNudge
+	outcome NudgeOutcome
+}
+
+// historyEntry describes r for the history log.
+func (r resolvedNudge) historyEntry(now time.Time) HistoryEntry {
+	return HistoryEntry{Time: now, Target: r.task.target, Agent: r.task.agent, Action: r.task.label,
+		Keys: r.task.keys, Risk: r.task.risk, Auto: r.auto, Outcome: string(r.outcome)}
+}
+
+// outcomeTracker matches the actions sent to panes with the panes' next
+// verdicts. It is only touched from Update, so it needs no locking.
+type outcomeTracker struct {
+	pending map[string]pendingNudge // by target
+}
+
+// track starts following tasks sent at now. An action still pending for a
+// pane is resolved as still blocked: another one was needed.
+func (o *outcomeTracker) track(tasks []nudgeTask, auto bool, now time.Time) []resolvedNudge {
+	if o.pending == nil {
+		o.pending = make(map[string]pendingNudge)
+	}
+	var resolved []resolvedNudge
+	for _, t := range tasks {
+		if t.typed {
+			continue
+		}
+		if prev, ok := o.pending[t.target]; ok {
+			resolved = append(resolved, resolvedNudge{prev, OutcomeStillBlocked})
+		}
+		o.pending[t.target] = pendingNudge{task: t, auto: auto, sent: now}
+	}
+	return resolved
+}
+
+// observe judges the pending actions against a scan's verdicts and returns
+// those whose outcome is known, sorted by target.
+func (o *outcomeTracker) observe(verdicts []model.Verdict) []resolvedNudge {
+	if len(o.pending) == 0 {
+		return nil
+	}
+	current := make(map[string]model.Verdict, len(verdicts))
+	for _, v := range verdicts {
+		current[v.Target] = v
+	}
+	var resolved []resolvedNudge
+	for target, p := range o.pending {
+		p.scans++
+		v, ok := current[target]
+		var outcome NudgeOutcome
+		switch {
+		case !ok || v.Agent == "error" || v.Agent == "not_an_agent":
+			outcome = OutcomeFailed
+		case !v.Blocked || blockedState(v) != p.task.state:
+			outcome = OutcomeUnblocked
+		case p.scans >= outcomeScans:
+			outcome = OutcomeStillBlocked
+		default:
+			o.pending[target] = p
+			continue
+		}
+		delete(o.pending, target)
+		resolved = append(resolved, resolvedNudge{p, outcome})
+	}
+	sort.Slice(resolved, func(i, j int) bool { return resolved[i].task.target < resolved[j].task.target })
+	return resolved
+}
+
+// selectFollowUps selects the pane of each action the user sent that was
+// answered with a follow-up dialog (model.Action.FollowUp), so its second
+// step can be answered next, e.g. the scope OpenCode asks for after "allow
+// always". Actions sent by auto-nudge or remembered answers never move the
+// cursor.
+func (m *tuiModel) selectFollowUps(resolved []resolvedNudge) {
+	for _, r := range resolved {
+		if !r.task.followUp || r.auto || r.task.remembered || r.outcome != OutcomeUnblocked {
+			continue
+		}
+		for vi, v := range m.verdicts {
+			if v.Target != r.task.target || !v.Blocked {
+				continue
+			}
+			m.selectVerdict(vi)
+			m.message = strings.TrimPrefix(m.message+" · "+
+				fmt.Sprintf("%s asks a follow-up to '%s': %s", v.Target, r.task.label, v.Reason), " · ")
+		}
+	}
+}
+
+// outcomeKey identifies an action for the success rates: the agent and the
+// action's label.
+type outcomeKey struct {
+	agent string
+	label string
+}
+
+// outcomeCount counts the outcomes of one action.
+type outcomeCount struct {
+	outcomeKey
+	unblocked    int
+	stillBlocked int
+	failed       int
+	auto         int // of which sent by auto-nudge
+}
+
+func (c outcomeCount) total() int {
+	return c.unblocked + c.stillBlocked + c.failed
+}
+
+// successRate is the share of the action's outcomes that unblocked the pane.
+func (c outcomeCount) successRate() float64 {
+	if c.total() == 0 {
+		return 0
+	}
+	return float64(c.unblocked) / float64(c.total())
+}
+
+// recordOutcomes adds resolved actions to the per-action success rates.
+func (s *sessionStats) recordOutcomes(resolved []resolvedNudge) {
+	for _, r := range resolved {
+		if s.outcomes == nil {
+			s.outcomes = make(map[outcomeKey]*outcomeCount)
+		}
+		key := outcomeKey{agent: r.task.agent, label: r.task.label}
+		c, ok := s.outcomes[key]
+		if !ok {
+			c = &outcomeCount{outcomeKey: key}
+			s.outcomes[key] = c
+		}
+		switch r.outcome {
+		case OutcomeUnblocked:
+			c.unblocked++
+		case OutcomeStillBlocked:
+			c.stillBlocked++
+		case OutcomeFailed:
+			c.failed++
+		}
+		if r.auto {
+			c.auto++
+		}
+	}
+}
+
+// outcomeCounts returns the per-action counts, most sent first.
+func (s *sessionStats) outcomeCounts() []outcomeCount {
+	counts := make([]outcomeCount, 0, len(s.outcomes))
+	for _, c := range s.outcomes {
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].total() != counts[j].total() {
+			return counts[i].total() > counts[j].total()
+		}
+		if counts[i].agent != counts[j].agent {
+			return counts[i].agent < counts[j].agent
+		}
+		return counts[i].label < counts[j].label
+	})
+	return counts
+}
+
+// viewOutcomes renders the per-action success rates on the dashboard, to
+// tell which actions are worth auto-nudging.
+func (m *tuiModel) viewOutcomes(b *strings.Builder) {
+	counts := m.stats.outcomeCounts()
+	if len(counts) == 0 {
+		return
+	}
+	b.WriteString(m.s.header.Render(fmt.Sprintf("  %-14s %-24s %6s %9s %6s %6s", "agent", "action", "sent", "unblocked", "stuck", "auto")))
+	b.WriteString("\n")
+	for _, c := range counts {
+		rate := fmt.Sprintf("%8.0f%%", 100*c.successRate())
+		switch {
+		case c.successRate() < 0.5:
+			rate = m.s.err.Render(rate)
+		case c.successRate() < 0.8:
+			rate = m.s.blocked.Render(rate)
+		}
+		b.WriteString(fmt.Sprintf("  %s %s %6d %s %6d %6d\n", padRight(truncate(c.agent, 14), 14), padRight(truncate(c.label, 24), 24),
+			c.total(), rate, c.stillBlocked+c.failed, c.auto))
+	}
+	b.WriteString("\n")
+}
+
+// resolveOutcomes records resolved actions in the session stats and the
+// log, reports the ones that left their pane blocked on the status line,
+// and returns a tea.Cmd that adds them to the history.
+func (m *tuiModel) resolveOutcomes(resolved []resolvedNudge) tea.Cmd {
+	if len(resolved) == 0 {
+		return nil
+	}
+	m.stats.recordOutcomes(resolved)
+	var stuck []string
+	for _, r := range resolved {
+		m.store.Note(r.task.target, TimelineEntry{Time: time.Now(), Kind: "outcome", Action: r.task.label, Auto: r.auto, Outcome: string(r.outcome)})
+		m.logger().Info("nudge outcome", "target", r.task.target, "action", r.task.label,
+			"auto", r.auto, "outcome", r.outcome, "after", time.Since(r.sent).Round(time.Second))
+		if r.outcome == OutcomeStillBlocked {
+			stuck = append(stuck, fmt.Sprintf("%s still blocked after '%s'", r.task.target, r.task.label))
+		}
+	}
+	if len(stuck) > 0 {
+		m.message = strings.TrimPrefix(m.message+" · "+strings.Join(stuck, " | "), " · ")
+	}
+	if m.history == nil {
+		return nil
+	}
+	history, now := m.history, time.Now()
+	return func() tea.Msg {
+		for _, r := range resolved {
+			if err := history.Record(r.historyEntry(now)); err != nil {
+				return nudgeResultMsg{messages: []string{err.Error()}}
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestOutcomeTracker_JudgesNextScans(t *testing.T) {
+	dialog := model.Verdict{Target: "a:0.0", Agent: "claude_code", Blocked: true, Reason: "permission dialog", WaitingFor: "Run npm test?"}
+	other, gone := dialog, dialog
+	other.Target, gone.Target = "b:0.0", "c:0.0"
+	task := func(v model.Verdict) nudgeTask {
+		return nudgeTask{target: v.Target, agent: v.Agent, keys: "1", label: "allow once", state: blockedState(v)}
+	}
+
+	var o outcomeTracker
+	o.track([]nudgeTask{task(dialog), task(other), task(gone)}, true, time.Now())
+
+	// a:0.0 still shows the dialog; b:0.0 moved on to the next question;
+	// c:0.0 is gone.
+	other.WaitingFor = "Edit main.go?"
+	got := o.observe([]model.Verdict{dialog, other})
+	if len(got) != 2 || got[0].task.target != "b:0.0" || got[0].outcome != OutcomeUnblocked ||
+		got[1].task.target != "c:0.0" || got[1].outcome != OutcomeFailed {
+		t.Fatalf("first scan resolved %+v", got)
+	}
+	for range outcomeScans - 2 {
+		if got := o.observe([]model.Verdict{dialog}); len(got) != 0 {
+			t.Fatalf("resolved %+v before %d scans", got, outcomeScans)
+		}
+	}
+	got = o.observe([]model.Verdict{dialog})
+	if len(got) != 1 || got[0].outcome != OutcomeStillBlocked || !got[0].auto {
+		t.Fatalf("expected a:0.0 still blocked, got %+v", got)
+	}
+
+	// Sending again before the outcome is known means the first did not do it.
+	o.track([]nudgeTask{task(dialog)}, false, time.Now())
+	if got := o.track([]nudgeTask{task(dialog)}, false, time.Now()); len(got) != 1 || got[0].outcome != OutcomeStillBlocked {
+		t.Errorf("resending resolved %+v", got)
+	}
+	if got := o.observe([]model.Verdict{{Target: "a:0.0", Agent: "claude_code"}}); len(got) != 1 || got[0].outcome != OutcomeUnblocked {
+		t.Errorf("active pane resolved %+v", got)
+	}
+}
+
+func TestOutcomes_HistoryAndDashboard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	m := newTestModel(simpleVerdict())
+	m.history = NewHistory(path, nil)
+
+	v := m.verdicts[0]
+	sent := nudgeTask{target: v.Target, agent: v.Agent, keys: "Enter", label: "allow once", risk: "medium", state: blockedState(v)}
+	_, cmd := m.Update(nudgeResultMsg{messages: []string{"sent"}, sent: 1, nudges: []nudgeTask{sent}})
+	if cmd != nil {
+		t.Error("nothing is resolved when an action is sent")
+	}
+
+	active := v
+	active.Blocked = false
+	m.Update(scanResultMsg{result: &ScanResult{Verdicts: []model.Verdict{active}}})
+	counts := m.stats.outcomeCounts()
+	if len(counts) != 1 || counts[0].unblocked != 1 || counts[0].successRate() != 1 {
+		t.Fatalf("counts = %+v", counts)
+	}
+	if resolved := m.resolveOutcomes([]resolvedNudge{{pendingNudge{task: sent}, OutcomeStillBlocked}}); resolved == nil {
+		t.Fatal("expected the outcome recorded in the history")
+	} else {
+		resolved()
+	}
+	if !strings.Contains(m.message, "test:0.0 still blocked after 'allow once'") {
+		t.Errorf("message = %q", m.message)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e HistoryEntry
+	if err := json.Unmarshal([]byte(strings.SplitN(string(data), "\n", 2)[0]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Target != "test:0.0" || e.Action != "allow once" || e.Outcome != string(OutcomeStillBlocked) {
+		t.Errorf("history entry = %+v", e)
+	}
+
+	view := m.viewDashboard()
+	if !strings.Contains(view, "allow once") || !strings.Contains(view, "50%") {
+		t.Errorf("dashboard does not show the success rate:\n%s", view)
+	}
+}
+
+func TestSelectFollowUps(t *testing.T) {
+	var sent []string
+	m := triageTestModel(&sent)
+	m.selectVerdict(3)
+	dialog := m.verdicts[0]
+	allowAlways := nudgeTask{target: dialog.Target, agent: dialog.Agent, keys: "Down Enter", label: "allow always",
+		state: blockedState(dialog), followUp: true}
+
+	// The auto-nudged action leaves the cursor where it is.
+	m.verdicts[0].Reason = "allow always — waiting for a scope"
+	m.selectFollowUps([]resolvedNudge{{pendingNudge{task: allowAlways, auto: true}, OutcomeUnblocked}})
+	if m.selectedPaneIndex() != 3 {
+		t.Fatalf("auto-nudge moved the cursor to %d", m.selectedPaneIndex())
+	}
+
+	m.selectFollowUps([]resolvedNudge{{pendingNudge{task: allowAlways}, OutcomeUnblocked}})
+	if m.selectedPaneIndex() != 0 || !strings.Contains(m.message, "a:0.0 asks a follow-up to 'allow always'") {
+		t.Errorf("cursor at %d, message %q", m.selectedPaneIndex(), m.message)
+	}
+}
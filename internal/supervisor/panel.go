@@ -0,0 +1,311 @@
+package supervisor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// panelLayout places the action panel relative to the pane list.
+type panelLayout int
+
+const (
+	layoutBottom panelLayout = iota // list on top, action panel below
+	layoutRight                     // list on the left, action panel on the right
+)
+
+// parseLayout maps the layout setting to a panelLayout; anything but
+// "right" is the default bottom layout.
+func parseLayout(name string) panelLayout {
+	if name == "right" {
+		return layoutRight
+	}
+	return layoutBottom
+}
+
+func (l panelLayout) String() string {
+	if l == layoutRight {
+		return "right"
+	}
+	return "bottom"
+}
+
+// Action panel sizing. The right layout needs minRightLayoutWidth columns
+// (more with the cluster sidebar) and falls back to bottom when narrower.
+const (
+	minPanelWidth       = 36
+	minRightLayoutWidth = 100
+)
+
+// effectiveLayout returns the layout that fits the current terminal.
+func (m *tuiModel) effectiveLayout(sidebarWidth int) panelLayout {
+	if m.layout == layoutRight && m.width-sidebarWidth >= minRightLayoutWidth {
+		return layoutRight
+	}
+	return layoutBottom
+}
+
+// rightPanelWidth is the action panel width in the right layout.
+func (m *tuiModel) rightPanelWidth() int {
+	return max(m.width*2/5, minPanelWidth)
+}
+
+// renderActionPanel renders the selected pane's details and actions as at
+// most height lines, each at most width columns. actionRow is the index of
+// the line showing the first action, or -1 when there are none.
+func (m *tuiModel) renderActionPanel(v *model.Verdict, width, height int) (lines []string, actionRow int) {
+	actionRow = -1
+	if v == nil || height <= 0 {
+		return nil, actionRow
+	}
+	withMacros := m.withMacros(*v)
+	v = &withMacros
+	order := m.actionOrder(*v)
+	inner := max(width-2, 10)
+
+	name, detail := v.Target, nonEmpty(v.Agent, v.Model)
+	if label := m.labels.Pane(v.Target); label != "" {
+		name, detail = label, append([]string{v.Target}, detail...)
+	}
+	title := m.s.title.Render(truncate(name, inner))
+	if len(detail) > 0 {
+		title += m.s.dim.Render(truncate(" · "+strings.Join(detail, " · "), max(inner-runewidth.StringWidth(name), 0)))
+	}
+	lines = append(lines, title)
+	if note := m.labels.Note(v.Target, v.PID); note != "" {
+		lines = append(lines, m.s.info.Render(truncate(m.glyphs().note+" "+note, inner)))
+	}
+
+	reason := truncate(withIdleState(strings.Join(strings.Fields(v.Reason), " "), *v), inner)
+	switch {
+	case v.Agent == "error":
+		lines = append(lines, m.s.err.Render(reason))
+	case v.Blocked:
+		lines = append(lines, m.s.blocked.Render(reason))
+	default:
+		lines = append(lines, m.s.active.Render(reason))
+	}
+
+	// Command safety warnings go above the question so they are seen
+	// before approving.
+	if v.Blocked && v.Dialog != nil {
+		for _, w := range v.Dialog.Warnings {
+			if len(lines) < height-len(order) {
+				lines = append(lines, m.s.err.Render(truncate(m.glyphs().warning+" "+w, inner)))
+			}
+		}
+	}
+
+	// WaitingFor gets what is left after the actions, then the output
+	// above the dialog (what the agent did before blocking) what is left
+	// after WaitingFor, its last lines first.
+	room := height - len(lines) - len(order)
+	if !v.Blocked || len(v.Actions) == 0 {
+		room--
+	}
+	var waiting []string
+	for _, line := range strings.Split(v.WaitingFor, "\n") {
+		if line = strings.TrimSpace(line); line != "" && len(waiting) < room {
+			waiting = append(waiting, line)
+		}
+	}
+	activity := v.Activity
+	if !v.Blocked {
+		activity = nil
+	}
+	activity = activity[len(activity)-min(len(activity), max(room-len(waiting), 0)):]
+	for _, line := range activity {
+		lines = append(lines, m.s.dim.Render(truncate(m.glyphs().activity+" "+line, inner)))
+	}
+	for _, line := range waiting {
+		lines = append(lines, m.s.dim.Render("  "+truncate(line, inner-2)))
+	}
+
+	if !v.Blocked || len(v.Actions) == 0 {
+		if len(lines) < height {
+			lines = append(lines, m.s.dim.Render("  no actions"))
+		}
+		return lines, actionRow
+	}
+	actionRow = len(lines)
+	for _, i := range order {
+		if len(lines) == height {
+			break
+		}
+		if i < 0 {
+			rule := strings.Repeat(m.glyphs().rule, 2)
+			lines = append(lines, m.s.dim.Render(truncate("  "+rule+" high risk "+rule, inner)))
+			continue
+		}
+		a := v.Actions[i]
+		marker := " "
+		if i == v.Recommended {
+			marker = m.glyphs().recommended
+		}
+		label := truncate(a.Label, max(inner-12, 5))
+		lines = append(lines, fmt.Sprintf("%s %d. %s %s", marker, i+1, m.riskLabel(a.Risk), label))
+	}
+	if last := m.labels.LastContinuation(v.Target); last != "" && idleAtPrompt(v) && len(lines) < height {
+		last = strings.Join(strings.Fields(last), " ")
+		lines = append(lines, m.s.dim.Render(truncate(fmt.Sprintf("  .  resend %q", last), inner)))
+	}
+	return lines, actionRow
+}
+
+// actionOrder returns the order the panel lists v's actions in, as indices
+// into v.Actions (action_order): the dialog's order, safest first ("risk"),
+// or the dialog's order with high-risk actions last, below a separator
+// (-1, "grouped"). Actions keep their number either way, so 1-9 send the
+// dialog option of that number.
+func (m *tuiModel) actionOrder(v model.Verdict) []int {
+	order := make([]int, len(v.Actions))
+	for i := range order {
+		order[i] = i
+	}
+	switch m.actionOrderMode {
+	case "risk":
+		sort.SliceStable(order, func(a, b int) bool {
+			return sortRisk(v.Actions[order[a]].Risk) < sortRisk(v.Actions[order[b]].Risk)
+		})
+	case "grouped":
+		var safe, high []int
+		for _, i := range order {
+			if v.Actions[i].Risk == "high" {
+				high = append(high, i)
+			} else {
+				safe = append(safe, i)
+			}
+		}
+		if len(safe) > 0 && len(high) > 0 {
+			order = append(append(safe, -1), high...)
+		}
+	}
+	return order
+}
+
+// sortRisk orders risk levels for sorting, an unknown risk as medium.
+func sortRisk(risk string) int {
+	if r := riskOrdinal(risk); r > 0 {
+		return r
+	}
+	return riskOrdinal("medium")
+}
+
+// riskLabel renders a risk level as low/med/HIGH in its risk color.
+func (m *tuiModel) riskLabel(risk string) string {
+	switch risk {
+	case "low":
+		return m.s.active.Render("[low] ")
+	case "medium":
+		return m.s.blocked.Render("[med] ")
+	case "high":
+		return m.s.err.Render("[HIGH]")
+	}
+	return m.s.dim.Render(fmt.Sprintf("[%-4s]", risk))
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// actionAt returns the selected pane's action shown on panel line row, or
+// false when row is not an action line.
+func (m *tuiModel) actionAt(row int) (model.Verdict, model.Action, bool) {
+	v := m.selectedVerdict()
+	if v == nil || !v.Blocked || m.panelActionRow < 0 {
+		return model.Verdict{}, model.Action{}, false
+	}
+	withMacros := m.withMacros(*v)
+	v = &withMacros
+	order := m.actionOrder(*v)
+	line := row - m.panelActionRow
+	if line < 0 || line >= len(order) || order[line] < 0 {
+		return model.Verdict{}, model.Action{}, false
+	}
+	return *v, v.Actions[order[line]], true
+}
+
+// inActionPanel reports whether the screen cell (x, y) lies in the action
+// panel, and the panel line it is on.
+func (m *tuiModel) inActionPanel(x, y int) (int, bool) {
+	if m.actionPanelX > 0 {
+		// Right layout: the panel starts on the first list line (screen row 1).
+		return y - 1, x >= m.actionPanelX
+	}
+	if m.actionPanelY > 0 {
+		// Bottom layout: actionPanelY is the separator above the panel.
+		return y - m.actionPanelY - 1, y >= m.actionPanelY
+	}
+	return 0, false
+}
+
+// sendActionCmd sends one of a pane's actions in the background and records
+// it in the history. High-risk actions first ask for a typed confirmation
+// when confirm_high_risk is set; the command is then returned by the
+// confirmation instead. An action opening a text field (Action.Text) opens
+// the text input, and is sent with the text.
+func (m *tuiModel) sendActionCmd(target string, a model.Action) tea.Cmd {
+	if a.Text {
+		// The keys open a text field; they are sent with the typed text.
+		m.textInput = &textInput{target: target, after: &a}
+		return nil
+	}
+	task := nudgeTask{target: target, keys: a.Keys, raw: a.Raw, label: a.Label, risk: a.Risk, followUp: a.FollowUp}
+	var answer *lastAnswer
+	for _, v := range m.verdicts {
+		if v.Target == target {
+			task.agent, task.state = v.Agent, blockedState(v)
+			if optionKey(v, a) {
+				task.check = &v
+			}
+			if rememberable(v) {
+				answer = &lastAnswer{verdict: v, action: a}
+			}
+		}
+	}
+	send := func(reason string) tea.Cmd {
+		m.invalidateCache(target)
+		history, nudger, scanner, ctx := m.history, m.nudger, m.scanner, m.ctx
+		return func() tea.Msg {
+			if task.check != nil {
+				if err := scanner.checkDialog(ctx, *task.check, a); err != nil {
+					return nudgeResultMsg{messages: []string{fmt.Sprintf("%s: %v; nothing sent", target, err)}, changed: true}
+				}
+			}
+			if err := nudger.NudgePane(target, a.Keys, a.Raw); err != nil {
+				return nudgeResultMsg{messages: []string{fmt.Sprintf("%s: %v", target, err)}}
+			}
+			messages := []string{fmt.Sprintf("sent '%s' (%s) to %s", a.Keys, a.Label, target)}
+			if err := history.Record(task.historyEntry(time.Now(), reason, false)); err != nil {
+				messages = append(messages, err.Error())
+			}
+			return nudgeResultMsg{messages: messages, sent: 1, answer: answer, nudges: []nudgeTask{task}}
+		}
+	}
+	if m.needsConfirm(a) {
+		m.confirm = &confirmInput{action: a, targets: []string{target}, send: send}
+		return nil
+	}
+	return send("")
+}
+
+// invalidateCache drops target's cached verdict so the next scan
+// re-evaluates it after keys were sent.
+func (m *tuiModel) invalidateCache(target string) {
+	if m.scanner != nil && m.scanner.Cache != nil {
+		m.scanner.Cache.Invalidate(target)
+		m.scanner.Metrics.RecordCacheInvalidation(m.ctx)
+		m.logger().Info("cache entry invalidated", "target", target)
+	}
+}
@@ -0,0 +1,214 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestParseLayout(t *testing.T) {
+	if got := parseLayout("right"); got != layoutRight {
+		t.Errorf("parseLayout(right) = %v", got)
+	}
+	for _, name := range []string{"", "bottom"} {
+		if got := parseLayout(name); got != layoutBottom {
+			t.Errorf("parseLayout(%q) = %v, want bottom", name, got)
+		}
+	}
+}
+
+func TestActionPanel_BottomLayout(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	view := m.View()
+	if !strings.Contains(view, "1. [med]  allow once") || !strings.Contains(view, "2. [low]  dismiss") {
+		t.Fatalf("expected numbered actions in view:\n%s", view)
+	}
+	if m.actionPanelY == 0 || m.actionPanelX != 0 {
+		t.Fatalf("expected bottom panel, got actionPanelY=%d actionPanelX=%d", m.actionPanelY, m.actionPanelX)
+	}
+	lines := strings.Split(view, "\n")
+	if !strings.Contains(lines[m.actionPanelY], "─") {
+		t.Errorf("expected separator at row %d, got %q", m.actionPanelY, lines[m.actionPanelY])
+	}
+	if row := m.actionPanelY + 1 + m.panelActionRow; !strings.Contains(lines[row], "allow once") {
+		t.Errorf("expected first action at row %d, got %q", row, lines[row])
+	}
+}
+
+func TestActionPanel_RightLayout(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.layout = layoutRight
+	view := m.View()
+	if m.actionPanelX == 0 || m.actionPanelY != 0 {
+		t.Fatalf("expected right panel, got actionPanelY=%d actionPanelX=%d", m.actionPanelY, m.actionPanelX)
+	}
+	lines := strings.Split(view, "\n")
+	// The panel starts on the first list line, next to the session header.
+	if !strings.Contains(lines[1], "test:0.0") || !strings.Contains(lines[1], "│") {
+		t.Errorf("expected panel title next to the list, got %q", lines[1])
+	}
+	if row := 1 + m.panelActionRow; !strings.Contains(lines[row], "allow once") {
+		t.Errorf("expected first action at row %d, got %q", row, lines[row])
+	}
+}
+
+func TestActionPanel_RightLayoutFallsBackWhenNarrow(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.layout = layoutRight
+	m.width = 80
+	_ = m.View()
+	if m.actionPanelX != 0 || m.actionPanelY == 0 {
+		t.Errorf("expected bottom fallback, got actionPanelY=%d actionPanelX=%d", m.actionPanelY, m.actionPanelX)
+	}
+}
+
+func TestActionPanel_ToggleLayout(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if m.layout != layoutRight || m.message != "Layout: right" {
+		t.Errorf("expected right layout, got %v (%q)", m.layout, m.message)
+	}
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if m.layout != layoutBottom {
+		t.Errorf("expected bottom layout, got %v", m.layout)
+	}
+}
+
+func TestActionPanel_MouseHitTesting(t *testing.T) {
+	for _, layout := range []panelLayout{layoutBottom, layoutRight} {
+		t.Run(layout.String(), func(t *testing.T) {
+			m := newTestModel(simpleVerdict())
+			m.layout = layout
+			_ = m.View()
+			cursor := m.cursor
+
+			var x, y int
+			if layout == layoutRight {
+				x, y = m.actionPanelX+4, 1+m.panelActionRow+1
+			} else {
+				x, y = 4, m.actionPanelY+1+m.panelActionRow+1
+			}
+			row, ok := m.inActionPanel(x, y)
+			if !ok {
+				t.Fatalf("(%d,%d) not in panel", x, y)
+			}
+			v, a, ok := m.actionAt(row)
+			if !ok || v.Target != "test:0.0" || a.Label != "dismiss" {
+				t.Fatalf("actionAt(%d) = %q, %q, %v; want dismiss", row, v.Target, a.Label, ok)
+			}
+
+			// Hovering over the panel keeps the selection.
+			_, _ = m.handleMouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionMotion})
+			if m.cursor != cursor {
+				t.Errorf("hover moved cursor to %d", m.cursor)
+			}
+			_, cmd := m.handleMouse(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+			if cmd == nil {
+				t.Error("expected click on an action to send it")
+			}
+
+			// The list itself is not part of the panel.
+			if _, ok := m.inActionPanel(2, 2); ok {
+				t.Error("list row reported as panel")
+			}
+		})
+	}
+}
+
+func TestActionPanel_ActionOrder(t *testing.T) {
+	v := blockedVerdict("test:0.0", "test", "run command")
+	v.Actions = []model.Action{
+		{Keys: "y", Label: "run it", Risk: "high"},
+		{Keys: "a", Label: "always allow", Risk: "medium"},
+		{Keys: "n", Label: "deny", Risk: "low"},
+	}
+	m := newTestModel(v)
+
+	panelOrder := func() string {
+		lines, row := m.renderActionPanel(&v, 80, 20)
+		var order []string
+		for _, line := range lines[row:] {
+			order = append(order, strings.Join(strings.Fields(line), " "))
+		}
+		return strings.Join(order, " | ")
+	}
+	for mode, want := range map[string]string{
+		"":        "★ 1. [HIGH] run it | 2. [med] always allow | 3. [low] deny",
+		"risk":    "3. [low] deny | 2. [med] always allow | ★ 1. [HIGH] run it",
+		"grouped": "2. [med] always allow | 3. [low] deny | ── high risk ── | ★ 1. [HIGH] run it",
+	} {
+		m.actionOrderMode = mode
+		if got := panelOrder(); got != want {
+			t.Errorf("action_order %q:\n got %s\nwant %s", mode, got, want)
+		}
+	}
+
+	// Clicks hit the action listed on the line, not the separator.
+	m.actionOrderMode = "grouped"
+	m.View()
+	if _, a, ok := m.actionAt(m.panelActionRow); !ok || a.Label != "always allow" {
+		t.Errorf("first line: %q, %v", a.Label, ok)
+	}
+	if _, _, ok := m.actionAt(m.panelActionRow + 2); ok {
+		t.Error("the separator is not an action")
+	}
+	if _, a, _ := m.actionAt(m.panelActionRow + 3); a.Label != "run it" {
+		t.Errorf("last line: %q", a.Label)
+	}
+}
+
+func TestActionPanel_NumberKeysSendActions(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	if _, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")}); cmd == nil {
+		t.Error("expected 2 to send the second action")
+	}
+	if _, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}); cmd != nil {
+		t.Error("expected no command for a missing action")
+	}
+
+	v := simpleVerdict()
+	v.Blocked = false
+	m = newTestModel(v)
+	m.filter = filterAgents
+	m.rebuildGroups()
+	m.clampCursorToPane()
+	if _, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")}); cmd != nil {
+		t.Error("expected no action for an active pane")
+	}
+}
+
+func TestActionPanel_ShowsCommandWarnings(t *testing.T) {
+	v := simpleVerdict()
+	v.WaitingFor = "$ rm -rf build"
+	v.Dialog = &model.Dialog{Kind: model.DialogPermission, Command: "rm -rf build", Warnings: []string{"rm -rf: recursive forced delete"}}
+	m := newTestModel(v)
+	if view := m.View(); !strings.Contains(view, "⚠ rm -rf: recursive forced delete") {
+		t.Errorf("expected warning in the action panel:\n%s", view)
+	}
+}
+
+func TestActionPanel_ShowsActivityAboveWaitingFor(t *testing.T) {
+	v := simpleVerdict()
+	v.WaitingFor = "$ go test ./..."
+	v.Activity = []string{"⏺ Edited internal/auth/token.go", "⏺ Now running the tests"}
+	m := newTestModel(v)
+	lines, _ := m.renderActionPanel(&v, 80, 10)
+	panel := strings.Join(lines, "\n")
+	edited, running, waiting := strings.Index(panel, "↳ ⏺ Edited"), strings.Index(panel, "↳ ⏺ Now running"), strings.Index(panel, "$ go test")
+	if edited < 0 || running < edited || waiting < running {
+		t.Errorf("expected the activity above WaitingFor:\n%s", panel)
+	}
+
+	// Short of room, WaitingFor and the last activity line stay.
+	lines, _ = m.renderActionPanel(&v, 80, 6)
+	panel = strings.Join(lines, "\n")
+	if strings.Contains(panel, "Edited") || !strings.Contains(panel, "Now running") || !strings.Contains(panel, "$ go test") {
+		t.Errorf("expected only the last activity line:\n%s", panel)
+	}
+	lines, _ = m.renderActionPanel(&v, 80, 5)
+	if panel = strings.Join(lines, "\n"); strings.Contains(panel, "↳") {
+		t.Errorf("expected no activity without room:\n%s", panel)
+	}
+}
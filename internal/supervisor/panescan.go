@@ -0,0 +1,87 @@
+package supervisor
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// paneScanMsg carries the verdict of one pane evaluated on its own
+// (rescan <target> from the tmux hooks); nil when the pane is gone.
+type paneScanMsg struct {
+	target  string
+	verdict *model.Verdict
+	err     error
+}
+
+// scanPaneCmd evaluates target afresh in the background, without waiting
+// for or disturbing a full scan.
+func (m *tuiModel) scanPaneCmd(target string) tea.Cmd {
+	m.invalidateCache(target)
+	scanner, ctx := m.scanner, m.ctx
+	return func() tea.Msg {
+		v, err := scanner.ScanPane(ctx, target)
+		return paneScanMsg{target: target, verdict: v, err: err}
+	}
+}
+
+// applyPaneScan puts a pane's fresh verdict in the list. A full scan in
+// flight may have captured the pane before it changed, so the verdict is
+// also kept to be merged into that scan's result (mergePaneScans).
+func (m *tuiModel) applyPaneScan(msg paneScanMsg) tea.Cmd {
+	if msg.err != nil {
+		m.logger().Warn("pane rescan failed", "target", msg.target, "err", msg.err)
+		return nil
+	}
+	if msg.verdict == nil {
+		// Closed or filtered out: the next full scan drops it.
+		return nil
+	}
+	if m.scanning {
+		if m.paneScans == nil {
+			m.paneScans = make(map[string]model.Verdict)
+		}
+		m.paneScans[msg.target] = *msg.verdict
+	}
+	prevKey := m.selectedItemKey()
+	m.verdicts = withPaneVerdict(m.verdicts, *msg.verdict)
+	m.rebuildGroups()
+	m.restoreCursorByKey(prevKey)
+	transitions := m.store.Apply(m.verdicts, time.Now())
+	if summary := summarizeTransitions(transitions); summary != "" {
+		m.message = summary
+	}
+	return tea.Batch(m.announceCmd(transitions), m.transitionCmd(transitions), m.badgeCmd())
+}
+
+// mergePaneScans puts the verdicts of panes rescanned on their own during
+// a full scan into its result, unless the scan evaluated them later.
+func (m *tuiModel) mergePaneScans() {
+	for _, v := range m.paneScans {
+		i := slices.IndexFunc(m.verdicts, func(s model.Verdict) bool { return s.Target == v.Target })
+		if i < 0 || m.verdicts[i].EvaluatedAt.Before(v.EvaluatedAt) {
+			m.verdicts = withPaneVerdict(m.verdicts, v)
+		}
+	}
+	m.paneScans = nil
+}
+
+// withPaneVerdict replaces the verdict of v's pane, or inserts v in pane
+// order (paneLess) for a pane not listed yet.
+func withPaneVerdict(verdicts []model.Verdict, v model.Verdict) []model.Verdict {
+	if i := slices.IndexFunc(verdicts, func(s model.Verdict) bool { return s.Target == v.Target }); i >= 0 {
+		verdicts = slices.Clone(verdicts)
+		verdicts[i] = v
+		return verdicts
+	}
+	i := len(verdicts)
+	for j, s := range verdicts {
+		if paneLess(v, s) {
+			i = j
+			break
+		}
+	}
+	return slices.Insert(slices.Clone(verdicts), i, v)
+}
@@ -0,0 +1,63 @@
+package supervisor
+
+import (
+	"fmt"
+)
+
+// pinnedGroup is the name of the list section holding pinned panes, above
+// the groups. tmux does not allow ":" in session names, and directory and
+// repository groups are paths, so it never collides with a group.
+const pinnedGroup = ":pinned"
+
+// Pinned reports whether the pane at target is pinned with p.
+func (l *Labels) Pinned(target string) bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Pins[target]
+}
+
+// SetPinned pins or unpins the pane at target.
+func (l *Labels) SetPinned(target string, pinned bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if pinned {
+		l.Pins[target] = true
+	} else {
+		delete(l.Pins, target)
+	}
+}
+
+// togglePin pins or unpins the selected pane and persists the change.
+// Pinned panes are listed in their own section at the top, whatever the
+// grouping and filters.
+func (m *tuiModel) togglePin() {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return
+	}
+	if m.labels == nil {
+		m.message = "Pins are unavailable (see the startup warning)"
+		return
+	}
+	item := m.items[m.cursor]
+	if item.kind != itemPane {
+		m.message = "Only panes can be pinned"
+		return
+	}
+	target := m.verdicts[item.paneIdx].Target
+	pinned := !m.labels.Pinned(target)
+	m.labels.SetPinned(target, pinned)
+	m.rebuildGroups()
+	m.restoreCursorByKey(target)
+	if err := m.labels.Save(); err != nil {
+		m.message = err.Error()
+		return
+	}
+	if pinned {
+		m.message = fmt.Sprintf("Pinned %s", target)
+	} else {
+		m.message = fmt.Sprintf("Unpinned %s", target)
+	}
+}
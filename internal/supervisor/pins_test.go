@@ -0,0 +1,83 @@
+package supervisor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestLabels_PinsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "labels.json")
+	l, _ := LoadLabels(path, nil)
+	l.SetPinned("dev:0.3", true)
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	l, err := LoadLabels(path, nil)
+	if err != nil {
+		t.Fatalf("LoadLabels: %v", err)
+	}
+	if !l.Pinned("dev:0.3") || l.Pinned("dev:0.1") {
+		t.Errorf("pins = %v", l.Pins)
+	}
+	l.SetPinned("dev:0.3", false)
+	if len(l.Pins) != 0 {
+		t.Errorf("expected the pin removed, got %v", l.Pins)
+	}
+	var nilLabels *Labels
+	if nilLabels.Pinned("dev:0.3") {
+		t.Error("nil labels should have no pins")
+	}
+}
+
+func TestPin_KeyListsPaneAtTop(t *testing.T) {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "alpha:0.0", Session: "alpha", Agent: "opencode", Blocked: true},
+			{Target: "zeta:0.0", Session: "zeta", Agent: "opencode"},
+		},
+		expanded:        map[string]bool{},
+		manualCollapsed: make(map[string]bool),
+		width:           120,
+		height:          40,
+	}
+	labels, _ := LoadLabels(filepath.Join(t.TempDir(), "labels.json"), nil)
+	m.labels = labels
+	m.filter = filterAll
+	m.rebuildGroups()
+	m.restoreCursorByKey("zeta:0.0")
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if !labels.Pinned("zeta:0.0") || m.message != "Pinned zeta:0.0" {
+		t.Fatalf("pinned=%v message=%q", labels.Pinned("zeta:0.0"), m.message)
+	}
+	// The pane moved to the pinned section and the cursor followed it.
+	want := []string{"session:" + pinnedGroup, "zeta:0.0", "session:alpha", "alpha:0.0"}
+	if got := itemKeys(m); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+	if got := m.selectedItemKey(); got != "zeta:0.0" {
+		t.Errorf("cursor on %s", got)
+	}
+	if view := m.View(); !strings.Contains(view, "pinned") || !strings.Contains(view, "zeta:0.0") {
+		t.Errorf("expected the pinned section with the full target:\n%s", view)
+	}
+
+	// A pinned pane stays listed when the filter hides it.
+	m.filter = filterBlocked
+	m.rebuildGroups()
+	if got := itemKeys(m); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+
+	// p again unpins it.
+	m.restoreCursorByKey("zeta:0.0")
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if labels.Pinned("zeta:0.0") || m.items[0].session == pinnedGroup {
+		t.Errorf("expected zeta:0.0 unpinned, items = %v", itemKeys(m))
+	}
+}
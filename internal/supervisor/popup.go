@@ -0,0 +1,210 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// PopupArgs returns the tmux arguments that open command in a popup sized
+// for the quick view (pane-patrol popup). It closes when command exits.
+func PopupArgs(f mux.TmuxFeatures, command string) []string {
+	args := []string{"display-popup", "-E", "-w", "80%", "-h", "50%"}
+	if f.PopupTitle {
+		args = append(args, "-T", " pane-patrol ")
+	}
+	return append(args, command)
+}
+
+// Popup is the one-shot quick view of blocked panes shown in a tmux popup:
+// pick a pane, then jump to it or send one of its actions, and the popup
+// closes. Unlike the supervisor it does not refresh.
+type Popup struct {
+	Verdicts        []model.Verdict // blocked panes, in the order shown
+	Labels          *Labels         // friendly pane names; nil shows targets
+	History         *History        // records the action sent; nil records nothing
+	Audit           *AuditLog       // audits the action sent; nil audits nothing
+	ConfirmHighRisk bool            // require "yes" or a reason typed before a high-risk action
+}
+
+// Run shows the popup and then jumps or sends what was picked, once the
+// terminal is restored.
+func (p *Popup) Run() error {
+	m := &popupModel{popup: p}
+	if _, err := tea.NewProgram(m).Run(); err != nil {
+		return err
+	}
+	switch {
+	case m.jump != "":
+		if errMsg := jumpToPane(m.jump); errMsg != "" {
+			return fmt.Errorf("%s", errMsg)
+		}
+	case m.send != nil:
+		return AnswerPane(m.popup.Verdicts[m.cursor], Answer{Action: m.send, Reason: m.reason}, p.History, p.Audit, "popup")
+	}
+	return nil
+}
+
+// popupModel is the Bubble Tea model of the popup. Picking sets jump or
+// send and quits; Popup.Run carries it out.
+type popupModel struct {
+	popup   *Popup
+	cursor  int
+	confirm *confirmInput // high-risk action awaiting a typed reason (confirm_high_risk)
+	message string
+
+	jump   string
+	send   *model.Action
+	reason string
+}
+
+func (m *popupModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *popupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.confirm != nil {
+		return m.updateConfirm(key)
+	}
+	verdicts := m.popup.Verdicts
+	switch key.String() {
+	case "q", "esc", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		m.message = ""
+	case "down", "j":
+		if m.cursor < len(verdicts)-1 {
+			m.cursor++
+		}
+		m.message = ""
+	case "enter":
+		if len(verdicts) == 0 {
+			return m, tea.Quit
+		}
+		m.jump = verdicts[m.cursor].Target
+		return m, tea.Quit
+	case "y":
+		if len(verdicts) == 0 {
+			return m, nil
+		}
+		if v := verdicts[m.cursor]; v.Recommended < 0 {
+			m.message = fmt.Sprintf("%s has no recommended action", v.Target)
+			return m, nil
+		}
+		return m.pick(verdicts[m.cursor].Recommended + 1)
+	default:
+		if k := key.String(); len(k) == 1 && k[0] >= '1' && k[0] <= '9' && len(verdicts) > 0 {
+			return m.pick(int(k[0] - '0'))
+		}
+	}
+	return m, nil
+}
+
+// pick sends the selected pane's nth action (1-based), once "yes" or a
+// reason is typed when it is high risk and confirm_high_risk is set, as in
+// the supervisor.
+func (m *popupModel) pick(n int) (tea.Model, tea.Cmd) {
+	v := m.popup.Verdicts[m.cursor]
+	if n < 1 || n > len(v.Actions) {
+		m.message = fmt.Sprintf("%s has no action %d", v.Target, n)
+		return m, nil
+	}
+	a := v.Actions[n-1]
+	if m.popup.ConfirmHighRisk && a.Risk == "high" {
+		m.confirm = &confirmInput{action: a, targets: []string{v.Target}, send: func(reason string) tea.Cmd {
+			m.send, m.reason = &a, reason
+			return tea.Quit
+		}}
+		m.message = ""
+		return m, nil
+	}
+	m.send = &a
+	return m, tea.Quit
+}
+
+// updateConfirm handles keys while a high-risk action awaits its reason:
+// enter sends once something is typed, esc goes back to the list.
+func (m *popupModel) updateConfirm(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.confirm
+	switch key.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.confirm = nil
+		m.message = "Cancelled"
+	case tea.KeyEnter:
+		reason := strings.TrimSpace(string(c.input))
+		if reason == "" {
+			m.message = `Type "yes" or a reason to send this action`
+			return m, nil
+		}
+		m.confirm = nil
+		return m, c.send(reason)
+	default:
+		c.edit(key)
+	}
+	return m, nil
+}
+
+func (m *popupModel) View() string {
+	if m.jump != "" || m.send != nil {
+		return ""
+	}
+	verdicts := m.popup.Verdicts
+	var b strings.Builder
+	bold := lipgloss.NewStyle().Bold(true)
+	dim := lipgloss.NewStyle().Faint(true)
+	if len(verdicts) == 0 {
+		b.WriteString(bold.Render("No blocked panes") + "\n\n" + dim.Render("q close") + "\n")
+		return b.String()
+	}
+	if c := m.confirm; c != nil {
+		b.WriteString(bold.Render(fmt.Sprintf("High risk: %s (%s) → %s", c.action.Label, c.action.Keys, c.targets[0])) + "\n\n")
+		b.WriteString("yes or reason: " + string(c.input) + "█\n")
+		if m.message != "" {
+			b.WriteString("\n" + m.message + "\n")
+		}
+		b.WriteString("\n" + dim.Render("enter send · ctrl+u clear · esc cancel") + "\n")
+		return b.String()
+	}
+	b.WriteString(bold.Render(fmt.Sprintf("%d blocked", len(verdicts))) + "\n\n")
+	for i, v := range verdicts {
+		name := v.Target
+		if label := m.popup.Labels.Pane(v.Target); label != "" {
+			name = label
+		}
+		marker := "  "
+		if i == m.cursor {
+			marker = "> "
+		}
+		line := fmt.Sprintf("%s%s %s", marker, padRight(truncate(name, 24), 24), truncate(v.Reason, 48))
+		if i == m.cursor {
+			line = bold.Render(line)
+		}
+		b.WriteString(line + "\n")
+		if i == m.cursor && v.Recommended >= 0 && v.Recommended < len(v.Actions) {
+			a := v.Actions[v.Recommended]
+			rec := fmt.Sprintf("    recommended: [%d] %s", v.Recommended+1, a.Label)
+			if a.Risk != "" {
+				rec += " (" + a.Risk + ")"
+			}
+			b.WriteString(dim.Render(rec) + "\n")
+		}
+	}
+	if m.message != "" {
+		b.WriteString("\n" + m.message + "\n")
+	}
+	b.WriteString("\n" + dim.Render("enter jump · y send recommended · 1-9 send action · q close") + "\n")
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+func popupKey(m *popupModel, key string) tea.Cmd {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	switch key {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	}
+	_, cmd := m.Update(msg)
+	return cmd
+}
+
+func TestPopupArgs(t *testing.T) {
+	got := strings.Join(PopupArgs(mux.TmuxFeatures{Popup: true, PopupTitle: true}, "pane-patrol popup"), " ")
+	if got != "display-popup -E -w 80% -h 50% -T  pane-patrol  pane-patrol popup" {
+		t.Errorf("got %q", got)
+	}
+	if got := PopupArgs(mux.TmuxFeatures{Popup: true}, "x"); strings.Contains(strings.Join(got, " "), "-T") {
+		t.Errorf("tmux 3.2 has no popup titles: %q", got)
+	}
+}
+
+func TestPopup_PicksPaneAndAction(t *testing.T) {
+	api := blockedVerdict("api:0.0", "api", "permission required")
+	api.Actions = []model.Action{{Keys: "y", Label: "allow", Risk: "low"}, {Keys: "a", Label: "allow rm -rf", Risk: "high"}}
+	web := blockedVerdict("web:0.0", "web", "edit approval")
+	web.Recommended = -1
+	labels := &Labels{Panes: map[string]string{"web:0.0": "frontend"}}
+
+	m := &popupModel{popup: &Popup{Verdicts: []model.Verdict{api, web}, Labels: labels, ConfirmHighRisk: true}}
+	view := m.View()
+	for _, want := range []string{"2 blocked", "> api:0.0", "permission required", "recommended: [1] allow (low)", "frontend"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view is missing %q:\n%s", want, view)
+		}
+	}
+
+	// A high-risk action needs a typed reason, as in the supervisor.
+	if popupKey(m, "2") != nil || m.confirm == nil || !strings.Contains(m.View(), "yes or reason") {
+		t.Fatalf("high-risk action sent without a reason: %+v", m.send)
+	}
+	if popupKey(m, "enter") != nil || m.send != nil || !strings.Contains(m.message, "Type \"yes\"") {
+		t.Fatalf("enter without a reason: message %q", m.message)
+	}
+	for _, k := range []string{"c", "i"} {
+		popupKey(m, k)
+	}
+	if popupKey(m, "enter") == nil || m.send == nil || m.send.Label != "allow rm -rf" || m.reason != "ci" {
+		t.Fatalf("a reason should send, got %+v (reason %q)", m.send, m.reason)
+	}
+
+	m = &popupModel{popup: &Popup{Verdicts: []model.Verdict{api, web}}}
+	if popupKey(m, "y") == nil || m.send == nil || m.send.Keys != "y" {
+		t.Errorf("y should send the recommended action, got %+v", m.send)
+	}
+	m = &popupModel{popup: &Popup{Verdicts: []model.Verdict{api, web}}}
+	popupKey(m, "down")
+	if popupKey(m, "y") != nil || !strings.Contains(m.message, "no recommended action") {
+		t.Errorf("y without a recommendation: message %q", m.message)
+	}
+	if popupKey(m, "enter") == nil || m.jump != "web:0.0" {
+		t.Errorf("enter should jump to the selected pane, got %q", m.jump)
+	}
+
+	empty := &popupModel{popup: &Popup{}}
+	if !strings.Contains(empty.View(), "No blocked panes") || popupKey(empty, "enter") == nil {
+		t.Error("an empty popup says so and closes on enter")
+	}
+}
@@ -0,0 +1,114 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/config"
+)
+
+// Profile is a named set of scan settings from the config file, switchable
+// at runtime with P, e.g. to watch a different fleet of sessions.
+type Profile struct {
+	Name             string
+	Filter           string
+	ExcludeSessions  []string
+	Panes            *config.PaneFilter
+	RefreshInterval  time.Duration
+	AutoNudge        bool
+	AutoNudgeMaxRisk string
+}
+
+// profilePicker is the profile switcher (opened with P).
+type profilePicker struct {
+	cursor int
+}
+
+// handleProfileKey navigates the profiles; enter switches to one.
+func (m *tuiModel) handleProfileKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.profilePicker
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "P":
+		m.profilePicker = nil
+	case "up", "k":
+		p.cursor = max(p.cursor-1, 0)
+	case "down", "j":
+		p.cursor = min(p.cursor+1, len(m.profiles)-1)
+	case "enter":
+		m.profilePicker = nil
+		return m, m.applyProfile(m.profiles[p.cursor])
+	}
+	return m, nil
+}
+
+// applyProfile switches the scanner and auto-nudge settings to p and
+// rescans. The scanner is replaced rather than changed in place so a scan
+// in flight keeps its settings; its result is followed by a fresh scan.
+func (m *tuiModel) applyProfile(p Profile) tea.Cmd {
+	var s Scanner
+	if m.scanner != nil {
+		s = *m.scanner
+	}
+	s.Filter = p.Filter
+	s.ExcludeSessions = p.ExcludeSessions
+	s.Panes = p.Panes
+	m.scanner = &s
+	m.refreshInterval = p.RefreshInterval
+	m.autoNudge = p.AutoNudge
+	m.autoNudgeMaxRisk = p.AutoNudgeMaxRisk
+	if m.autoNudgeMaxRisk == "" {
+		m.autoNudgeMaxRisk = "low"
+	}
+	m.profile = p.Name
+	m.message = fmt.Sprintf("Profile: %s", p.Name)
+	if m.scanning {
+		m.rescanAfterScan = true
+		return nil
+	}
+	m.scanning = true
+	return m.doScan()
+}
+
+// viewProfilePicker lists the profiles with their scan settings.
+func (m *tuiModel) viewProfilePicker() string {
+	p := m.profilePicker
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Profiles"))
+	b.WriteString("\n\n")
+	for i, profile := range m.profiles {
+		marker := "  "
+		if i == p.cursor {
+			marker = m.s.selected.Render(m.glyphs().cursor)
+		}
+		name := m.s.header.Render(profile.Name)
+		if profile.Name == m.profile {
+			name += m.s.active.Render(" (current)")
+		}
+		var settings []string
+		if profile.Filter != "" {
+			settings = append(settings, "filter "+profile.Filter)
+		}
+		if len(profile.ExcludeSessions) > 0 {
+			settings = append(settings, "excluding "+strings.Join(profile.ExcludeSessions, ", "))
+		}
+		if profile.AutoNudge {
+			settings = append(settings, fmt.Sprintf("auto-nudge up to %s risk", profile.AutoNudgeMaxRisk))
+		}
+		if profile.RefreshInterval > 0 {
+			settings = append(settings, "refresh "+profile.RefreshInterval.String())
+		}
+		b.WriteString(marker + name)
+		if len(settings) > 0 {
+			b.WriteString(m.s.dim.Render(" · " + truncate(strings.Join(settings, " · "), m.width-len(profile.Name)-8)))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styleHints("  ↑↓ select  enter switch  esc close"))
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testProfiles() []Profile {
+	return []Profile{
+		{Name: "default", ExcludeSessions: []string{"supervisor"}, RefreshInterval: 5 * time.Second},
+		{Name: "ops", Filter: "^ops-", ExcludeSessions: []string{"supervisor"}, RefreshInterval: 30 * time.Second,
+			AutoNudge: true, AutoNudgeMaxRisk: "medium"},
+	}
+}
+
+func TestProfilePicker_Switch(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.scanner = &Scanner{Filter: ""}
+	m.profiles, m.profile = testProfiles(), "default"
+	original := m.scanner
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if m.profilePicker == nil || !strings.Contains(m.View(), "default (current)") {
+		t.Fatalf("expected P to open the picker:\n%s", m.View())
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.profilePicker != nil || cmd == nil || !m.scanning {
+		t.Fatal("expected enter to switch profiles and rescan")
+	}
+	if m.profile != "ops" || m.scanner.Filter != "^ops-" || m.refreshInterval != 30*time.Second ||
+		!m.autoNudge || m.autoNudgeMaxRisk != "medium" {
+		t.Errorf("ops settings not applied: profile=%q filter=%q refresh=%v auto=%v/%s",
+			m.profile, m.scanner.Filter, m.refreshInterval, m.autoNudge, m.autoNudgeMaxRisk)
+	}
+	if original.Filter != "" {
+		t.Error("expected the previous scanner to be left unchanged")
+	}
+}
+
+func TestProfilePicker_RescansAfterScanInFlight(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.profiles, m.profile = testProfiles(), "default"
+	m.scanning = true
+
+	if cmd := m.applyProfile(m.profiles[1]); cmd != nil {
+		t.Fatal("expected no second scan while one is in flight")
+	}
+	// The stale result is dropped in favor of a scan with the new settings.
+	_, cmd := m.Update(scanResultMsg{result: &ScanResult{}})
+	if cmd == nil || !m.scanning || m.rescanAfterScan {
+		t.Error("expected a rescan once the stale scan returned")
+	}
+}
+
+func TestProfilePicker_NoProfiles(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if m.profilePicker != nil || !strings.Contains(m.message, "No profiles") {
+		t.Errorf("expected a hint instead of the picker, got %q", m.message)
+	}
+}
@@ -0,0 +1,75 @@
+package supervisor
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// scanProgressBuffer is how many verdicts a scan can get ahead of the TUI.
+const scanProgressBuffer = 64
+
+// scanProgressMsg is a verdict of the scan whose progress channel is ch.
+type scanProgressMsg struct {
+	progress ScanProgress
+	ch       chan ScanProgress
+}
+
+// waitScanProgress waits for the next verdict on ch. It returns nil once
+// the scan closes ch. Verdicts of a scan the TUI no longer follows are
+// still received, so that scan is not blocked.
+func waitScanProgress(ch chan ScanProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return scanProgressMsg{progress: p, ch: ch}
+	}
+}
+
+// startScanProgress makes the list follow the scan sending to ch.
+func (m *tuiModel) startScanProgress(ch chan ScanProgress) {
+	m.scanProgress = ch
+	m.scanDone, m.scanTotal = 0, 0
+	// Streamed verdicts are written into m.verdicts, which may still be
+	// shared with the previous scan's result.
+	m.verdicts = slices.Clone(m.verdicts)
+}
+
+// applyScanProgress shows a streamed verdict right away: it replaces the
+// pane's previous verdict, or is inserted in pane order for a new pane.
+// Transitions, notifications, and auto-nudge wait for the full result.
+func (m *tuiModel) applyScanProgress(p ScanProgress) {
+	m.scanDone++
+	m.scanTotal = p.Total
+
+	key := m.selectedItemKey()
+	i := slices.IndexFunc(m.verdicts, func(v model.Verdict) bool { return v.Target == p.Verdict.Target })
+	if i >= 0 {
+		m.verdicts[i] = p.Verdict
+	} else {
+		at, _ := slices.BinarySearchFunc(m.verdicts, p.Verdict, func(a, b model.Verdict) int {
+			switch {
+			case paneLess(a, b):
+				return -1
+			case paneLess(b, a):
+				return 1
+			}
+			return 0
+		})
+		m.verdicts = slices.Insert(m.verdicts, at, p.Verdict)
+	}
+	m.rebuildGroups()
+	m.restoreCursorByKey(key)
+}
+
+// scanStatus describes the running scan for the header.
+func (m *tuiModel) scanStatus() string {
+	if m.scanTotal == 0 {
+		return "scanning..."
+	}
+	return fmt.Sprintf("scanning %d/%d...", m.scanDone, m.scanTotal)
+}
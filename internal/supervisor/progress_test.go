@@ -0,0 +1,57 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestScanProgress_UpdatesListAsPanesComplete(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.filter = filterAll
+	ch := make(chan ScanProgress, 1)
+	m.startScanProgress(ch)
+	m.scanning = true
+
+	// A new pane is listed in pane order; a known pane's verdict is replaced.
+	first := model.Verdict{Target: "a:0.0", Session: "a", Agent: "claude", Blocked: true}
+	m.Update(scanProgressMsg{progress: ScanProgress{Verdict: first, Total: 2}, ch: ch})
+	unblocked := simpleVerdict()
+	unblocked.Blocked = false
+	m.Update(scanProgressMsg{progress: ScanProgress{Verdict: unblocked, Total: 2}, ch: ch})
+
+	if len(m.verdicts) != 2 || m.verdicts[0].Target != "a:0.0" || m.verdicts[1].Blocked {
+		t.Fatalf("verdicts = %+v", m.verdicts)
+	}
+	if got := m.scanStatus(); got != "scanning 2/2..." {
+		t.Errorf("scanStatus() = %q", got)
+	}
+	if !strings.Contains(m.View(), "scanning 2/2...") {
+		t.Error("expected the scan's progress in the header")
+	}
+	// The cursor stays on the selected pane.
+	if item := m.items[m.cursor]; item.kind != itemPane || m.verdicts[item.paneIdx].Target != "test:0.0" {
+		t.Errorf("cursor moved to %+v", item)
+	}
+}
+
+func TestScanProgress_IgnoresFinishedScan(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	old := make(chan ScanProgress, 1)
+	m.startScanProgress(old)
+	m.startScanProgress(make(chan ScanProgress, 1))
+
+	stale := model.Verdict{Target: "a:0.0", Session: "a", Agent: "claude", Blocked: true}
+	_, cmd := m.Update(scanProgressMsg{progress: ScanProgress{Verdict: stale, Total: 1}, ch: old})
+	if len(m.verdicts) != 1 || m.scanDone != 0 {
+		t.Errorf("stale verdict applied: %+v", m.verdicts)
+	}
+	if cmd == nil {
+		t.Error("expected the stale scan to still be drained")
+	}
+	close(old)
+	if msg := cmd(); msg != nil {
+		t.Errorf("expected nothing once the scan closed its channel, got %T", msg)
+	}
+}
@@ -0,0 +1,72 @@
+package supervisor
+
+import (
+	"time"
+)
+
+// dndOverride is the do-not-disturb state set with z against the quiet
+// hours schedule. It holds until the schedule next changes.
+type dndOverride struct {
+	set      bool
+	on       bool // do not disturb while set
+	schedule bool // whether the schedule was quiet when it was set
+}
+
+// doNotDisturb reports whether notifications are held back at now: speech,
+// the transition command, and escalations. History and badges carry on.
+func (m *tuiModel) doNotDisturb(now time.Time) bool {
+	scheduled := m.quietHours.Quiet(now)
+	if m.dnd.set && m.dnd.schedule == scheduled {
+		return m.dnd.on
+	}
+	return scheduled
+}
+
+// expireDND drops the z override once the schedule has changed since it
+// was set, so an override never outlives the quiet hours it was meant for.
+func (m *tuiModel) expireDND(now time.Time) {
+	if m.dnd.set && m.dnd.schedule != m.quietHours.Quiet(now) {
+		m.dnd = dndOverride{}
+	}
+}
+
+// toggleDND turns do-not-disturb on or off (z) until the schedule next
+// changes, or back to the schedule when that is what it says.
+func (m *tuiModel) toggleDND(now time.Time) {
+	on, scheduled := !m.doNotDisturb(now), m.quietHours.Quiet(now)
+	if on == scheduled {
+		m.dnd = dndOverride{}
+	} else {
+		m.dnd = dndOverride{set: true, on: on, schedule: scheduled}
+	}
+	switch {
+	case on && m.dnd.set && len(m.quietHours.Schedule) > 0:
+		m.message = "Do not disturb ON through the next quiet hours (z to turn off)"
+	case on && m.dnd.set:
+		m.message = "Do not disturb ON (z to turn off)"
+	case on:
+		m.message = "Do not disturb ON (quiet hours)"
+	case m.dnd.set:
+		m.message = "Do not disturb OFF until the quiet hours end"
+	default:
+		m.message = "Do not disturb OFF"
+	}
+}
+
+// autoNudgePolicy returns whether auto-nudge sends recommended actions at
+// now and up to which risk, after quiet_hours.auto_nudge while do-not-disturb
+// is on. Remembered answers (R) are sent either way.
+func (m *tuiModel) autoNudgePolicy(now time.Time) (bool, string) {
+	if !m.autoNudge || !m.doNotDisturb(now) {
+		return m.autoNudge, m.autoNudgeMaxRisk
+	}
+	switch quiet := m.quietHours.AutoNudge; quiet {
+	case "pause":
+		return false, m.autoNudgeMaxRisk
+	case "low", "medium":
+		if riskOrdinal(quiet) < riskOrdinal(m.autoNudgeMaxRisk) {
+			return true, quiet
+		}
+	}
+	return true, m.autoNudgeMaxRisk
+}
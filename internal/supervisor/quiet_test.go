@@ -0,0 +1,97 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// nightly is quiet from 23:00 to 08:00 every day.
+func nightly(autoNudge string) config.QuietHours {
+	all := [7]bool{true, true, true, true, true, true, true}
+	return config.QuietHours{
+		Schedule:  []config.QuietPeriod{{Weekdays: all, FromMinute: 23 * 60, ToMinute: 8 * 60}},
+		AutoNudge: autoNudge,
+	}
+}
+
+func TestDoNotDisturb_ScheduleAndOverride(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.quietHours = nightly("")
+	day := time.Date(2026, 5, 6, 15, 0, 0, 0, time.Local)
+	night := time.Date(2026, 5, 6, 23, 30, 0, 0, time.Local)
+
+	if m.doNotDisturb(day) || !m.doNotDisturb(night) {
+		t.Fatal("expected do-not-disturb only at night")
+	}
+
+	// z during the day turns it on, through the night, until the morning.
+	m.toggleDND(day)
+	if !m.doNotDisturb(day) || !strings.Contains(m.message, "ON") {
+		t.Errorf("z: dnd=%v message=%q", m.doNotDisturb(day), m.message)
+	}
+	m.expireDND(night)
+	morning := night.Add(9 * time.Hour)
+	m.expireDND(morning)
+	if m.doNotDisturb(morning) {
+		t.Error("the override should end with the quiet hours it covered")
+	}
+
+	// z at night turns it off until the quiet hours end; z again follows
+	// the schedule.
+	m.toggleDND(night)
+	if m.doNotDisturb(night) || m.message != "Do not disturb OFF until the quiet hours end" {
+		t.Errorf("z at night: dnd=%v message=%q", m.doNotDisturb(night), m.message)
+	}
+	m.toggleDND(night)
+	if !m.doNotDisturb(night) || m.dnd.set {
+		t.Errorf("z again: dnd=%v override=%+v", m.doNotDisturb(night), m.dnd)
+	}
+
+	if view := m.View(); strings.Contains(view, "DND") != m.doNotDisturb(time.Now()) {
+		t.Errorf("header DND indicator does not match:\n%s", view)
+	}
+}
+
+func TestDoNotDisturb_AutoNudgePolicy(t *testing.T) {
+	night := time.Date(2026, 5, 6, 23, 30, 0, 0, time.Local)
+	m := newTestModel(simpleVerdict())
+	m.autoNudge, m.autoNudgeMaxRisk = true, "medium"
+
+	for _, tc := range []struct {
+		quiet   string
+		on      bool
+		maxRisk string
+	}{
+		{"", true, "medium"},
+		{"pause", false, "medium"},
+		{"low", true, "low"},
+		{"medium", true, "medium"},
+	} {
+		m.quietHours = nightly(tc.quiet)
+		if on, risk := m.autoNudgePolicy(night); on != tc.on || risk != tc.maxRisk {
+			t.Errorf("auto_nudge %q: got %v/%s, want %v/%s", tc.quiet, on, risk, tc.on, tc.maxRisk)
+		}
+		if on, risk := m.autoNudgePolicy(night.Add(-12 * time.Hour)); !on || risk != "medium" {
+			t.Errorf("auto_nudge %q outside quiet hours: got %v/%s", tc.quiet, on, risk)
+		}
+	}
+}
+
+func TestDoNotDisturb_HoldsBackNotifications(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.announcer = &Announcer{}
+	m.transitionHook = NewTransitionHook("exit 1")
+	m.dnd = dndOverride{set: true, on: true}
+	ts := []Transition{{Kind: BecameBlocked, Target: "test:0.0", Verdict: model.Verdict{Agent: "opencode", Blocked: true}}}
+
+	if m.announceCmd(ts) != nil {
+		t.Error("speech should be held back")
+	}
+	if msg := m.transitionCmd(ts)(); msg.(transitionResultMsg).err != nil {
+		t.Errorf("transition command should not run: %v", msg.(transitionResultMsg).err)
+	}
+}
@@ -0,0 +1,193 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// rawKey is a palette entry of the raw key mode.
+type rawKey struct {
+	keys  string // tmux key name(s)
+	label string
+}
+
+// rawKeyPalette lists the keys most often needed outside the parsed
+// actions: interrupting, scrolling, and navigating agent TUIs.
+var rawKeyPalette = []rawKey{
+	{"C-c", "interrupt"},
+	{"Escape", "escape"},
+	{"Enter", "enter"},
+	{"Up", "up / previous prompt"},
+	{"Down", "down"},
+	{"PPage", "page up"},
+	{"NPage", "page down"},
+	{"BTab", "shift-tab"},
+	{"C-d", "end of input"},
+}
+
+// rawKeyInput is the raw key mode for one pane (opened with K). It stays
+// open after sending so keys like PageUp can be repeated. ctrl+r records
+// the keys sent as a macro for the dialog shown when recording started.
+type rawKeyInput struct {
+	target string
+	input  string // free-form key names being typed, or the macro name
+
+	recording bool
+	recorded  []string      // keys sent while recording
+	dialog    model.Verdict // the pane's verdict when recording started
+	naming    bool          // input is the name of the recorded macro
+}
+
+// parseRawKeys validates space-separated tmux key names ("C-c", "Up",
+// "PPage Enter") and single characters. Anything longer that is not a key
+// name is rejected so a typo is not typed into the pane as text.
+func parseRawKeys(input string) (string, error) {
+	tokens := strings.Fields(input)
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("no keys entered")
+	}
+	for _, tok := range tokens {
+		if len([]rune(tok)) > 1 && !isControlSequence(tok) {
+			return "", fmt.Errorf("unknown key name %q", tok)
+		}
+	}
+	return strings.Join(tokens, " "), nil
+}
+
+// handleRawKeyInput handles keys in raw key mode: 1-9 send a palette entry
+// while nothing is typed, otherwise typed key names are sent with enter.
+func (m *tuiModel) handleRawKeyInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.rawKeys
+	if r.naming {
+		return m.handleMacroName(msg)
+	}
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.rawKeys = nil
+		return m, nil
+	case tea.KeyCtrlR:
+		m.toggleMacroRecording()
+		return m, nil
+	case tea.KeyBackspace:
+		if runes := []rune(r.input); len(runes) > 0 {
+			r.input = string(runes[:len(runes)-1])
+		}
+		return m, nil
+	case tea.KeyEnter:
+		keys, err := parseRawKeys(r.input)
+		if err != nil {
+			m.message = err.Error()
+			return m, nil
+		}
+		r.input = ""
+		return m, m.sendRecordedKeys(keys)
+	case tea.KeySpace:
+		r.input += " "
+		return m, nil
+	case tea.KeyRunes:
+		key := string(msg.Runes)
+		if r.input == "" && len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if i := int(key[0] - '1'); i < len(rawKeyPalette) {
+				return m, m.sendRecordedKeys(rawKeyPalette[i].keys)
+			}
+			return m, nil
+		}
+		r.input += key
+	}
+	return m, nil
+}
+
+// handleMacroName handles the name prompt after recording a macro: enter
+// saves it, esc discards the recording.
+func (m *tuiModel) handleMacroName(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.rawKeys
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		r.naming, r.recorded, r.input = false, nil, ""
+		m.message = "Macro discarded"
+	case tea.KeyBackspace:
+		if runes := []rune(r.input); len(runes) > 0 {
+			r.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyEnter:
+		name := strings.TrimSpace(r.input)
+		if name == "" {
+			m.message = "Name the macro, or esc to discard it"
+			return m, nil
+		}
+		m.saveMacro(name)
+	case tea.KeySpace:
+		r.input += " "
+	case tea.KeyRunes:
+		r.input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// sendRecordedKeys sends keys to the raw key mode's pane, adding them to
+// the macro being recorded.
+func (m *tuiModel) sendRecordedKeys(keys string) tea.Cmd {
+	r := m.rawKeys
+	if r.recording {
+		r.recorded = append(r.recorded, keys)
+	}
+	return m.sendRawKeys(r.target, keys)
+}
+
+// sendRawKeys sends keys to target as raw keystrokes (see NudgePane) and
+// invalidates its cached verdict.
+func (m *tuiModel) sendRawKeys(target, keys string) tea.Cmd {
+	m.invalidateCache(target)
+	nudger := m.nudger
+	return func() tea.Msg {
+		if err := nudger.NudgePane(target, keys, true); err != nil {
+			return nudgeResultMsg{messages: []string{fmt.Sprintf("send %s to %s failed: %v", keys, target, err)}}
+		}
+		return nudgeResultMsg{messages: []string{fmt.Sprintf("sent %s to %s", keys, target)}, sent: 1,
+			nudges: []nudgeTask{{target: target, keys: keys, raw: true, label: "raw keys", typed: true}}}
+	}
+}
+
+// viewRawKeyInput renders the key palette and the free-form key entry.
+func (m *tuiModel) viewRawKeyInput() string {
+	r := m.rawKeys
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Send keys"))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(r.target))
+	b.WriteString("\n\n")
+	for i, k := range rawKeyPalette {
+		b.WriteString(fmt.Sprintf("  %d. %-7s %s\n", i+1, k.keys, m.s.dim.Render(k.label)))
+	}
+	switch {
+	case r.naming:
+		b.WriteString(fmt.Sprintf("\n  recorded: %s\n  macro name: ", strings.Join(r.recorded, " ")))
+		b.WriteString(r.input)
+		b.WriteString("█\n\n")
+		b.WriteString(m.styleHints("  enter save  esc discard"))
+	case r.recording:
+		b.WriteString(m.s.err.Render(fmt.Sprintf("\n  recording: %s", strings.Join(r.recorded, " "))))
+		b.WriteString("\n  keys: ")
+		b.WriteString(r.input)
+		b.WriteString("█\n\n")
+		b.WriteString(m.styleHints("  1-9 send  type key names + enter  ctrl+r stop and name  esc close"))
+	default:
+		b.WriteString("\n  keys: ")
+		b.WriteString(r.input)
+		b.WriteString("█\n\n")
+		b.WriteString(m.styleHints("  1-9 send  type key names (C-c, Up, PPage, F5, y) + enter  ctrl+r record macro  esc close"))
+	}
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseRawKeys(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"C-c", "C-c", false},
+		{"  PPage   Enter ", "PPage Enter", false},
+		{"Down y", "Down y", false},
+		{"F5", "F5", false},
+		{"C-Up", "C-Up", false},
+		{"", "", true},
+		{"PgUpp", "", true},
+		{"hello", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseRawKeys(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseRawKeys(%q) = %q, %v; want %q, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRawKeyInput_TypeAndSend(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("K")})
+	if m.rawKeys == nil || m.rawKeys.target != "test:0.0" {
+		t.Fatalf("expected raw key mode for test:0.0, got %+v", m.rawKeys)
+	}
+	if !strings.Contains(m.View(), "PPage") {
+		t.Errorf("expected palette in view, got:\n%s", m.View())
+	}
+
+	for _, r := range "PPagx" {
+		_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if m.rawKeys.input != "PPage" {
+		t.Fatalf("input: got %q, want PPage", m.rawKeys.input)
+	}
+	// Digits are typed, not palette picks, once input is non-empty.
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeySpace})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if m.rawKeys.input != "PPage 1" {
+		t.Fatalf("input: got %q, want %q", m.rawKeys.input, "PPage 1")
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.rawKeys == nil || m.rawKeys.input != "" {
+		t.Errorf("enter should send, clear the input, and keep the mode open")
+	}
+}
+
+func TestRawKeyInput_PaletteAndErrors(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.rawKeys = &rawKeyInput{target: "test:0.0"}
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")}); cmd == nil {
+		t.Error("1 with empty input should send the first palette key")
+	}
+
+	m.rawKeys.input = "Pageup"
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("unknown key name must not be sent")
+	}
+	if !strings.Contains(m.message, "unknown key name") {
+		t.Errorf("message: got %q", m.message)
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.rawKeys != nil {
+		t.Error("esc should close raw key mode")
+	}
+}
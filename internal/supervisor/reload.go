@@ -0,0 +1,172 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/config"
+)
+
+// Settings are the config file settings the supervisor applies again when
+// the file changes or it receives SIGHUP, without restarting and losing
+// its state (selection, notes, statistics). Other settings, such as
+// parallel, the cache, and custom parsers, still need a restart.
+type Settings struct {
+	Profile         Profile   // scan, refresh, and auto-nudge settings of the current profile
+	Profiles        []Profile // profiles offered by P
+	ThemeName       string
+	Themes          map[string]config.ThemeColors
+	ShowWaitingFor  bool
+	ShowRecommended bool
+	Layout          string
+	ActionOrder     string
+	ClickAction     string
+	GroupBy         string
+	Snippets        []config.Snippet
+	Continuations   []config.Snippet
+	ConfirmHighRisk bool
+}
+
+// ReloadFunc loads the config again with the named profile applied.
+type ReloadFunc func(profile string) (Settings, error)
+
+// configPollInterval is how often the config file is checked for changes.
+const configPollInterval = 2 * time.Second
+
+// configCheckMsg polls the config file for changes.
+type configCheckMsg struct{}
+
+// reloadConfigMsg reloads the config, e.g. on SIGHUP.
+type reloadConfigMsg struct{}
+
+// reloadOnSIGHUP makes SIGHUP reload the config of p's TUI until the
+// returned stop function is called.
+func reloadOnSIGHUP(p *tea.Program) (stop func()) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hup:
+				p.Send(reloadConfigMsg{})
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(hup)
+		close(done)
+	}
+}
+
+// fileStamp identifies a version of a file, to notice that it changed.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}
+}
+
+// watchConfig schedules the next check of the config file, or returns nil
+// when there is none to watch.
+func (m *tuiModel) watchConfig() tea.Cmd {
+	if m.reload == nil || m.configFile == "" {
+		return nil
+	}
+	return tea.Tick(configPollInterval, func(time.Time) tea.Msg { return configCheckMsg{} })
+}
+
+// checkConfig reloads the config if its file changed since the last check.
+func (m *tuiModel) checkConfig() tea.Cmd {
+	stamp := statFile(m.configFile)
+	if stamp == m.configStamp {
+		return m.watchConfig()
+	}
+	m.configStamp = stamp
+	return tea.Batch(m.reloadConfig(), m.watchConfig())
+}
+
+// reloadConfig loads the config again for the current profile and applies
+// what changed. A config that fails to load is reported and the current
+// settings are kept.
+func (m *tuiModel) reloadConfig() tea.Cmd {
+	if m.reload == nil {
+		return nil
+	}
+	s, err := m.reload(m.profile)
+	if err != nil {
+		m.logger().Error("config reload failed", "err", err)
+		m.message = fmt.Sprintf("Config reload failed: %v", err)
+		return nil
+	}
+	return m.applySettings(s)
+}
+
+// applySettings applies the settings that differ from the ones loaded
+// before, so a setting changed at runtime (with a, v, w, g, or T) is only
+// reset when the file changes it too. Scan settings are always applied and
+// followed by a rescan.
+func (m *tuiModel) applySettings(s Settings) tea.Cmd {
+	old := m.settings
+	m.settings = s
+	m.profiles = s.Profiles
+
+	p := s.Profile
+	if p.RefreshInterval == old.Profile.RefreshInterval {
+		p.RefreshInterval = m.refreshInterval
+	}
+	if p.AutoNudge == old.Profile.AutoNudge && p.AutoNudgeMaxRisk == old.Profile.AutoNudgeMaxRisk {
+		p.AutoNudge = m.autoNudge
+		p.AutoNudgeMaxRisk = m.autoNudgeMaxRisk
+	}
+	cmd := m.applyProfile(p)
+
+	if s.ThemeName != old.ThemeName || !reflect.DeepEqual(s.Themes, old.Themes) {
+		m.themes = newThemeSet(s.Themes)
+		m.themeName = s.ThemeName
+		m.theme = m.themes.theme(m.themeName)
+		m.s = newStyles(m.theme)
+	}
+	if s.ShowWaitingFor != old.ShowWaitingFor {
+		m.showWaitingFor = s.ShowWaitingFor
+	}
+	if s.ShowRecommended != old.ShowRecommended {
+		m.showRecommended = s.ShowRecommended
+	}
+	if s.Layout != old.Layout {
+		m.layout = parseLayout(s.Layout)
+	}
+	if s.ActionOrder != old.ActionOrder {
+		m.actionOrderMode = s.ActionOrder
+	}
+	if s.ClickAction != old.ClickAction {
+		m.clickSelects = s.ClickAction == "select"
+	}
+	if s.GroupBy != old.GroupBy {
+		key := m.selectedItemKey()
+		m.groupBy = parseGroupMode(s.GroupBy)
+		m.repoRoots = nil
+		m.rebuildGroups()
+		m.restoreCursorByKey(key)
+	}
+	m.snippets = s.Snippets
+	m.continuations = s.Continuations
+	m.confirmHighRisk = s.ConfirmHighRisk
+
+	m.logger().Info("config reloaded", "profile", s.Profile.Name)
+	m.message = "Config reloaded"
+	return cmd
+}
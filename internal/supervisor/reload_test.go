@@ -0,0 +1,94 @@
+package supervisor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplySettings_KeepsRuntimeToggles(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.scanner = &Scanner{}
+	m.profile = "default"
+	m.settings = Settings{Profile: Profile{Name: "default", RefreshInterval: 5 * time.Second}, Layout: "bottom"}
+	m.refreshInterval = 5 * time.Second
+	m.autoNudge, m.autoNudgeMaxRisk = true, "low" // toggled with a
+	m.layout = layoutRight                        // toggled with v
+
+	s := Settings{
+		Profile:        Profile{Name: "default", Filter: "^api", RefreshInterval: 30 * time.Second},
+		Layout:         "bottom",
+		ShowWaitingFor: true,
+	}
+	cmd := m.applySettings(s)
+	if cmd == nil || !m.scanning {
+		t.Fatal("expected a rescan with the new scan settings")
+	}
+	if m.scanner.Filter != "^api" || m.refreshInterval != 30*time.Second || !m.showWaitingFor {
+		t.Errorf("changed settings not applied: filter=%q refresh=%v waiting=%v",
+			m.scanner.Filter, m.refreshInterval, m.showWaitingFor)
+	}
+	if !m.autoNudge || m.layout != layoutRight {
+		t.Errorf("unchanged settings reset runtime toggles: auto=%v layout=%v", m.autoNudge, m.layout)
+	}
+	if m.message != "Config reloaded" {
+		t.Errorf("message = %q", m.message)
+	}
+
+	// Changing auto_nudge in the file does override the toggle.
+	m.scanning = false
+	s.Profile.AutoNudge, s.Profile.AutoNudgeMaxRisk = false, "medium"
+	m.applySettings(s)
+	if m.autoNudge || m.autoNudgeMaxRisk != "medium" {
+		t.Errorf("auto-nudge change not applied: %v/%s", m.autoNudge, m.autoNudgeMaxRisk)
+	}
+}
+
+func TestReloadConfig_ErrorKeepsSettings(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.refreshInterval = 5 * time.Second
+	m.reload = func(string) (Settings, error) { return Settings{}, errors.New("invalid layout") }
+
+	if cmd := m.reloadConfig(); cmd != nil {
+		t.Error("expected no rescan after a failed reload")
+	}
+	if !strings.Contains(m.message, "Config reload failed: invalid layout") || m.refreshInterval != 5*time.Second {
+		t.Errorf("message=%q refresh=%v", m.message, m.refreshInterval)
+	}
+}
+
+func TestCheckConfig_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("refresh: 5s\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(simpleVerdict())
+	m.scanner = &Scanner{}
+	var profiles []string
+	m.reload = func(profile string) (Settings, error) {
+		profiles = append(profiles, profile)
+		return Settings{Profile: Profile{Name: profile}}, nil
+	}
+	m.profile = "ops"
+	m.configFile = path
+	m.configStamp = statFile(path)
+
+	if cmd := m.checkConfig(); cmd == nil || len(profiles) != 0 {
+		t.Fatalf("expected only the next check for an unchanged file, reloads: %v", profiles)
+	}
+	if err := os.WriteFile(path, []byte("refresh: 30s\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.checkConfig()
+	if len(profiles) != 1 || profiles[0] != "ops" {
+		t.Errorf("expected one reload of the current profile, got %v", profiles)
+	}
+	m.scanning = false
+	m.checkConfig()
+	if len(profiles) != 1 {
+		t.Errorf("expected no reload without a further change, got %v", profiles)
+	}
+}
@@ -3,8 +3,12 @@ package supervisor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -13,9 +17,11 @@ import (
 	"github.com/timvw/pane-patrol/internal/events"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/logging"
 	"github.com/timvw/pane-patrol/internal/model"
 	"github.com/timvw/pane-patrol/internal/mux"
 	ppotel "github.com/timvw/pane-patrol/internal/otel"
@@ -31,31 +37,164 @@ type Scanner struct {
 	EventStore      *events.Store
 	EventOnly       bool
 	Filter          string
-	ExcludeSessions []string // Session names to exclude from scanning (exact match)
-	Parallel        int
-	Verbose         bool
-	Cache           *VerdictCache
-	Metrics         *ppotel.Metrics // OTEL metric counters; nil-safe
-	SessionID       string          // Langfuse session ID — groups all scans from one supervisor run
-	SelfTarget      string          // pane target of this supervisor process (skipped during scan)
+	ExcludeSessions []string           // Session names to exclude from scanning (exact match)
+	Panes           *config.PaneFilter // Window and pane include/exclude rules; nil scans all panes
+	Parallel        int                // Panes evaluated at once (worker pool size)
+	// TmuxParallel caps concurrent tmux subprocesses (capture-pane) across
+	// the workers; 0 uses Parallel.
+	TmuxParallel int
+	Verbose      bool
+	Cache        Cache           // Reuses verdicts of unchanged panes; nil disables
+	Metrics      *ppotel.Metrics // OTEL metric counters; nil-safe
+	Tracer       trace.Tracer    // OTEL tracer for scan spans; nil uses the global tracer provider
+	SessionID    string          // Langfuse session ID — groups all scans from one supervisor run
+	SelfTarget   string          // pane target of this supervisor process (skipped during scan)
+	Log          *slog.Logger    // Debug log of scans, parser decisions, and cache lookups; nil discards
+	Git          *GitStatus      // Branch and dirty state of agent panes' directories; nil disables
 }
 
 // ScanResult contains the verdicts and metadata from a scan.
 type ScanResult struct {
 	Verdicts  []model.Verdict
 	CacheHits int
+	Phases    ScanPhases
+}
+
+// ScanPhases is the time a scan spent per phase. List is wall time; Capture
+// and Evaluate add up all panes, so with parallel workers they can exceed
+// the scan's duration.
+type ScanPhases struct {
+	List     time.Duration // listing panes and their process trees
+	Capture  time.Duration // capture-pane calls, not counting waits for a tmux slot
+	Evaluate time.Duration // cache lookups and parsing
+}
+
+// ScanProgress is a pane's verdict, reported while its scan is still
+// running so the supervisor can show it before the slowest pane is done.
+type ScanProgress struct {
+	Verdict model.Verdict
+	Total   int // panes the scan evaluates
+}
+
+// progressSink sends a scan's verdicts to a ScanStream caller; the zero
+// value sends nothing.
+type progressSink struct {
+	ch    chan<- ScanProgress
+	total int
+}
+
+func (p progressSink) send(ctx context.Context, v model.Verdict) {
+	if p.ch == nil {
+		return
+	}
+	select {
+	case p.ch <- ScanProgress{Verdict: v, Total: p.total}:
+	case <-ctx.Done():
+	}
+}
+
+// batchCaptureSize is the most panes captured by one tmux invocation.
+const batchCaptureSize = 50
+
+// scanPool bounds one scan's concurrency: the workers evaluate panes, and at
+// most cap(tmux) of them run a tmux subprocess at a time. It also adds up
+// the time spent per phase.
+type scanPool struct {
+	tmux   chan struct{}
+	tracer trace.Tracer
+
+	// captured holds the content of panes captured up front in batches;
+	// it is not written once the workers start.
+	captured map[string]string
+
+	mu     sync.Mutex
+	phases ScanPhases
+}
+
+func newScanPool(tmuxParallel int, tracer trace.Tracer) *scanPool {
+	return &scanPool{tmux: make(chan struct{}, max(tmuxParallel, 1)), tracer: tracer}
+}
+
+// prefetch captures panes in batches when the multiplexer supports it. A
+// failed batch, e.g. because a pane closed since it was listed, is left to
+// per-pane captures.
+func (pool *scanPool) prefetch(ctx context.Context, m mux.Multiplexer, panes []model.Pane) {
+	batcher, ok := m.(mux.BatchCapturer)
+	if !ok || len(panes) < 2 {
+		return
+	}
+	start := time.Now()
+	pool.captured = make(map[string]string, len(panes))
+	for chunk := range slices.Chunk(panes, batchCaptureSize) {
+		targets := make([]string, len(chunk))
+		for i, p := range chunk {
+			targets[i] = p.Target
+		}
+		_, span := pool.tracer.Start(ctx, "capture_batch", trace.WithAttributes(attribute.Int("panes", len(targets))))
+		captures, err := batcher.CapturePanes(ctx, targets)
+		if err != nil {
+			recordSpanError(span, err)
+			span.End()
+			continue
+		}
+		span.End()
+		maps.Copy(pool.captured, captures)
+	}
+	pool.phases.Capture += time.Since(start)
+}
+
+// capture returns the prefetched content of target, or runs CapturePane
+// once a tmux slot is free.
+func (pool *scanPool) capture(ctx context.Context, m mux.Multiplexer, target string) (string, error) {
+	if content, ok := pool.captured[target]; ok {
+		return content, nil
+	}
+	ctx, span := pool.tracer.Start(ctx, "capture", trace.WithAttributes(attribute.String("pane.target", target)))
+	defer span.End()
+	waitStart := time.Now()
+	select {
+	case pool.tmux <- struct{}{}:
+	case <-ctx.Done():
+		recordSpanError(span, ctx.Err())
+		return "", ctx.Err()
+	}
+	defer func() { <-pool.tmux }()
+	start := time.Now()
+	content, err := m.CapturePane(ctx, target)
+	pool.add(&pool.phases.Capture, time.Since(start))
+	span.SetAttributes(attribute.Int64("capture.wait_ms", start.Sub(waitStart).Milliseconds()))
+	if err != nil {
+		recordSpanError(span, err)
+	}
+	return content, err
+}
+
+func (pool *scanPool) add(phase *time.Duration, d time.Duration) {
+	pool.mu.Lock()
+	*phase += d
+	pool.mu.Unlock()
 }
 
 // Scan captures and evaluates all panes, returning verdicts.
 // This is the same logic as pane-patrol scan, but as a Go function call.
 func (s *Scanner) Scan(ctx context.Context) (*ScanResult, error) {
+	return s.ScanStream(ctx, nil)
+}
+
+// ScanStream is Scan, but also sends each pane's verdict to progress as
+// soon as it is known, in the order panes complete. Sends block until
+// received or ctx is done, so progress must be read until ScanStream
+// returns; a nil channel sends nothing. Panes closed mid-scan are not
+// sent, and a failed scan may have sent verdicts before failing.
+func (s *Scanner) ScanStream(ctx context.Context, progress chan<- ScanProgress) (*ScanResult, error) {
 	if s.EventOnly {
-		return s.scanFromEvents(), nil
+		return s.scanFromEvents(ctx, progress)
 	}
 
-	ctx, span := tracer.Start(ctx, "scan",
+	ctx, span := s.tracer().Start(ctx, "scan",
 		trace.WithAttributes(
 			attribute.String("filter", s.Filter),
+			attribute.String("scan.mode", "capture"),
 
 			// Langfuse trace-level attributes
 			attribute.String("langfuse.trace.name", "pane-supervisor-scan"),
@@ -64,73 +203,34 @@ func (s *Scanner) Scan(ctx context.Context) (*ScanResult, error) {
 		))
 	defer span.End()
 
-	panes, err := s.Mux.ListPanes(ctx, s.Filter)
+	panes, listTime, err := s.listPanes(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list panes: %w", err)
+		recordSpanError(span, err)
+		return nil, err
 	}
 
-	// Filter panes: skip self-target and excluded sessions.
-	// Use a fresh slice to avoid aliasing the original backing array.
-	filtered := make([]model.Pane, 0, len(panes))
-	for _, p := range panes {
-		if s.SelfTarget != "" && p.Target == s.SelfTarget {
-			continue
-		}
-		if len(s.ExcludeSessions) > 0 && config.MatchesExcludeList(p.Session, s.ExcludeSessions) {
-			continue
-		}
-		filtered = append(filtered, p)
+	// Drop cached verdicts of closed panes.
+	if s.Cache != nil {
+		s.Cache.Retain(panes)
 	}
-	panes = filtered
-
 	if len(panes) == 0 {
-		return &ScanResult{}, nil
+		return &ScanResult{Phases: ScanPhases{List: listTime}}, nil
 	}
 
-	verdicts := make([]model.Verdict, len(panes))
-	cacheHits := int64(0)
-	parallel := s.Parallel
-	if parallel < 1 {
-		parallel = 1
-	}
-	if parallel > len(panes) {
-		parallel = len(panes)
-	}
-
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, parallel)
-
-	for i, pane := range panes {
-		wg.Add(1)
-		go func(idx int, p model.Pane) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			start := time.Now()
-			v, err := s.evaluatePane(ctx, p)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "warning: pane %s: %v\n", p.Target, err)
-				s.Metrics.RecordEvaluation(ctx, "error")
-				v := model.BaseVerdict(p, start)
-				v.Agent = "error"
-				v.Reason = fmt.Sprintf("evaluation failed: %v", err)
-				v.EvalSource = model.EvalSourceError
-				verdicts[idx] = v
-				return
-			}
-			if v.EvalSource == model.EvalSourceCache {
-				atomic.AddInt64(&cacheHits, 1)
-			}
-			verdicts[idx] = *v
-		}(i, pane)
+	verdicts, cacheHits, phases, err := s.evaluateAll(ctx, panes, progressSink{ch: progress, total: len(panes)})
+	if err != nil {
+		recordSpanError(span, err)
+		return nil, err
 	}
-
-	wg.Wait()
+	phases.List = listTime
+	// Results are in pane order (paneLess) whatever the mode, as the TUI
+	// inserts streamed verdicts of new panes by it.
+	sort.SliceStable(verdicts, func(i, j int) bool { return paneLess(verdicts[i], verdicts[j]) })
 
 	result := &ScanResult{
 		Verdicts:  verdicts,
-		CacheHits: int(cacheHits),
+		CacheHits: cacheHits,
+		Phases:    phases,
 	}
 
 	// Record span attributes for the completed scan
@@ -143,36 +243,204 @@ func (s *Scanner) Scan(ctx context.Context) (*ScanResult, error) {
 	span.SetAttributes(
 		attribute.Int("panes.total", len(verdicts)),
 		attribute.Int("panes.blocked", blocked),
-		attribute.Int("cache.hits", int(cacheHits)),
+		attribute.Int("cache.hits", cacheHits),
+		attribute.Int64("phase.list_ms", phases.List.Milliseconds()),
+		attribute.Int64("phase.capture_ms", phases.Capture.Milliseconds()),
+		attribute.Int64("phase.evaluate_ms", phases.Evaluate.Milliseconds()),
 	)
+	s.logScan(result)
 
 	return result, nil
 }
 
-func (s *Scanner) scanFromEvents() *ScanResult {
-	if s.EventStore == nil || s.Mux == nil {
-		return &ScanResult{}
+// VerdictCache returns the scanner's cache when it is a *VerdictCache, for
+// the cache screen and saving it on exit, or nil.
+func (s *Scanner) VerdictCache() *VerdictCache {
+	c, _ := s.Cache.(*VerdictCache)
+	return c
+}
+
+// logScan logs a completed scan's counts and phase times.
+func (s *Scanner) logScan(result *ScanResult) {
+	blocked := 0
+	for _, v := range result.Verdicts {
+		if v.Blocked {
+			blocked++
+		}
 	}
-	panes, err := s.Mux.ListPanes(context.Background(), s.Filter)
+	s.log().Debug("scan done", "panes", len(result.Verdicts), "blocked", blocked, "cache_hits", result.CacheHits,
+		"list", result.Phases.List, "capture", result.Phases.Capture, "evaluate", result.Phases.Evaluate)
+}
+
+func (s *Scanner) log() *slog.Logger {
+	return logging.OrDiscard(s.Log)
+}
+
+func (s *Scanner) tracer() trace.Tracer {
+	if s.Tracer == nil {
+		return tracer
+	}
+	return s.Tracer
+}
+
+// recordSpanError marks span as failed with err.
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// listPanes lists the panes to scan, leaving out the supervisor's own
+// pane, excluded sessions, and excluded panes, and returns how long
+// listing took.
+func (s *Scanner) listPanes(ctx context.Context) ([]model.Pane, time.Duration, error) {
+	ctx, span := s.tracer().Start(ctx, "list_panes")
+	defer span.End()
+
+	start := time.Now()
+	panes, err := s.Mux.ListPanes(ctx, s.Filter)
 	if err != nil {
-		return &ScanResult{}
+		err = fmt.Errorf("failed to list panes: %w", err)
+		recordSpanError(span, err)
+		return nil, 0, err
 	}
+	listTime := time.Since(start)
 
+	// Use a fresh slice to avoid aliasing the original backing array.
 	filtered := make([]model.Pane, 0, len(panes))
 	for _, p := range panes {
-		if s.SelfTarget != "" && p.Target == s.SelfTarget {
+		if s.skip(p) {
 			continue
 		}
-		if len(s.ExcludeSessions) > 0 && config.MatchesExcludeList(p.Session, s.ExcludeSessions) {
+		filtered = append(filtered, p)
+	}
+	span.SetAttributes(attribute.Int("panes.listed", len(panes)), attribute.Int("panes.scanned", len(filtered)))
+	return filtered, listTime, nil
+}
+
+// evaluateAll evaluates panes with a pool of Parallel workers sharing
+// TmuxParallel tmux slots, and returns their verdicts in pane order.
+// Per-pane failures become "error" verdicts, except for panes closed since
+// they were listed, which are left out. Each verdict is also sent to
+// progress once known. If the multiplexer server went away mid-scan, the
+// error wraps mux.ErrNoServer.
+func (s *Scanner) evaluateAll(ctx context.Context, panes []model.Pane, progress progressSink) ([]model.Verdict, int, ScanPhases, error) {
+	workers := min(max(s.Parallel, 1), len(panes))
+	tmuxParallel := s.TmuxParallel
+	if tmuxParallel < 1 {
+		tmuxParallel = workers
+	}
+	pool := newScanPool(tmuxParallel, s.tracer())
+	pool.prefetch(ctx, s.Mux, panes)
+
+	verdicts := make([]model.Verdict, len(panes))
+	gone := make([]bool, len(panes))
+	var cacheHits atomic.Int64
+	var serverErr atomic.Pointer[error]
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				p := panes[idx]
+				start := time.Now()
+				v, err := s.evaluatePane(ctx, p, pool)
+				if errors.Is(err, mux.ErrPaneNotFound) {
+					s.log().Debug("pane closed mid-scan", "target", p.Target)
+					gone[idx] = true
+					continue
+				}
+				if errors.Is(err, mux.ErrNoServer) {
+					serverErr.CompareAndSwap(nil, &err)
+					continue
+				}
+				if err != nil {
+					s.log().Warn("pane evaluation failed", "target", p.Target, "err", err)
+					// In event-only mode the supervisor TUI owns the terminal.
+					if !s.EventOnly {
+						fmt.Fprintf(os.Stderr, "warning: pane %s: %v\n", p.Target, err)
+					}
+					s.Metrics.RecordEvaluation(ctx, "error")
+					v := model.BaseVerdict(p, start)
+					v.Agent = "error"
+					v.Reason = fmt.Sprintf("evaluation failed: %v", err)
+					v.EvalSource = model.EvalSourceError
+					verdicts[idx] = v
+					progress.send(ctx, v)
+					continue
+				}
+				if v.EvalSource == model.EvalSourceCache {
+					cacheHits.Add(1)
+				}
+				s.Git.Enrich(ctx, v)
+				verdicts[idx] = *v
+				progress.send(ctx, *v)
+			}
+		}()
+	}
+	for i := range panes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := serverErr.Load(); err != nil {
+		return nil, 0, pool.phases, *err
+	}
+	kept := verdicts[:0]
+	for i, v := range verdicts {
+		if gone[i] {
+			if s.Cache != nil {
+				s.Cache.Invalidate(panes[i].Target)
+			}
 			continue
 		}
-		filtered = append(filtered, p)
+		kept = append(kept, v)
+	}
+	return kept, int(cacheHits.Load()), pool.phases, nil
+}
+
+// skip reports whether p is left out of scans: the supervisor's own pane,
+// another supervisor's pane, an excluded session, or a pane the pane
+// filter rejects.
+func (s *Scanner) skip(p model.Pane) bool {
+	if s.SelfTarget != "" && p.Target == s.SelfTarget || p.Supervisor {
+		return true
+	}
+	if len(s.ExcludeSessions) > 0 && config.MatchesExcludeList(p.Session, s.ExcludeSessions) {
+		return true
+	}
+	return !s.Panes.Allows(p)
+}
+
+func (s *Scanner) scanFromEvents(ctx context.Context, progress chan<- ScanProgress) (*ScanResult, error) {
+	if s.EventStore == nil || s.Mux == nil {
+		return &ScanResult{}, nil
+	}
+	ctx, span := s.tracer().Start(ctx, "scan",
+		trace.WithAttributes(
+			attribute.String("filter", s.Filter),
+			attribute.String("scan.mode", "events"),
+			attribute.String("langfuse.trace.name", "pane-supervisor-scan"),
+			attribute.String("langfuse.session.id", s.SessionID),
+			attribute.StringSlice("langfuse.trace.tags", []string{"pane-supervisor", "scan"}),
+		))
+	defer span.End()
+
+	panes, listTime, err := s.listPanes(ctx)
+	if err != nil {
+		recordSpanError(span, err)
+		return nil, err
+	}
+	if s.Cache != nil {
+		s.Cache.Retain(panes)
 	}
-	panes = filtered
 	if len(panes) == 0 {
-		return &ScanResult{}
+		return &ScanResult{Phases: ScanPhases{List: listTime}}, nil
 	}
 
+	sink := progressSink{ch: progress, total: len(panes)}
 	now := time.Now().UTC()
 	eventsSnapshot := s.EventStore.Snapshot(now)
 	byTarget := make(map[string]events.Event, len(eventsSnapshot))
@@ -180,44 +448,97 @@ func (s *Scanner) scanFromEvents() *ScanResult {
 		byTarget[ev.Target] = ev
 	}
 
+	// Panes with a hook event are answered from it; the rest are captured
+	// and parsed by the worker pool.
 	verdicts := make([]model.Verdict, 0, len(panes))
+	var unhooked []model.Pane
 	for _, p := range panes {
 		if ev, ok := byTarget[p.Target]; ok {
-			pane := p
-			pane.Command = ev.Assistant
-			v := model.BaseVerdict(pane, now)
-			v.Agent = ev.Assistant
-			v.Blocked = events.IsAttentionState(ev.State)
-			v.Reason = eventReason(ev.State, ev.Message)
-			v.WaitingFor = ev.Message
-			v.EvalSource = model.EvalSourceEvent
+			v := s.eventVerdict(ctx, p, ev, now)
 			verdicts = append(verdicts, v)
+			sink.send(ctx, v)
 			continue
 		}
-
-		v, err := s.evaluatePane(context.Background(), p)
+		unhooked = append(unhooked, p)
+	}
+	var (
+		cacheHits int
+		phases    ScanPhases
+	)
+	if len(unhooked) > 0 {
+		var evaluated []model.Verdict
+		evaluated, cacheHits, phases, err = s.evaluateAll(ctx, unhooked, sink)
 		if err != nil {
-			vv := model.BaseVerdict(p, now)
-			vv.Agent = "error"
-			vv.Reason = fmt.Sprintf("evaluation failed: %v", err)
-			vv.EvalSource = model.EvalSourceError
-			verdicts = append(verdicts, vv)
-			continue
+			recordSpanError(span, err)
+			return nil, err
 		}
-		verdicts = append(verdicts, *v)
+		verdicts = append(verdicts, evaluated...)
 	}
+	phases.List = listTime
 
-	sort.SliceStable(verdicts, func(i, j int) bool {
-		if verdicts[i].Session == verdicts[j].Session {
-			if verdicts[i].Window == verdicts[j].Window {
-				return verdicts[i].Pane < verdicts[j].Pane
+	sort.SliceStable(verdicts, func(i, j int) bool { return paneLess(verdicts[i], verdicts[j]) })
+
+	result := &ScanResult{Verdicts: verdicts, CacheHits: cacheHits, Phases: phases}
+	span.SetAttributes(
+		attribute.Int("panes.total", len(verdicts)),
+		attribute.Int("panes.hooked", len(verdicts)-len(unhooked)),
+		attribute.Int("cache.hits", cacheHits),
+	)
+	s.logScan(result)
+	return result, nil
+}
+
+// eventVerdict is the verdict of pane p from its latest hook event.
+func (s *Scanner) eventVerdict(ctx context.Context, p model.Pane, ev events.Event, now time.Time) model.Verdict {
+	p.Command = ev.Assistant
+	v := model.BaseVerdict(p, now)
+	v.Agent = ev.Assistant
+	v.Blocked = events.IsAttentionState(ev.State)
+	v.Reason = eventReason(ev.State, ev.Message)
+	v.WaitingFor = ev.Message
+	v.EvalSource = model.EvalSourceEvent
+	s.Git.Enrich(ctx, &v)
+	return v
+}
+
+// ScanPane evaluates the pane target alone, the way a scan would: from its
+// hook event in event-only mode, otherwise from its cached verdict or a
+// fresh capture. Panes are listed for the pane's metadata, but only target
+// is captured. It returns nil when the pane is gone or left out of scans.
+func (s *Scanner) ScanPane(ctx context.Context, target string) (*model.Verdict, error) {
+	panes, _, err := s.listPanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(panes, func(p model.Pane) bool { return p.Target == target })
+	if i < 0 {
+		return nil, nil
+	}
+	if s.EventOnly && s.EventStore != nil {
+		now := time.Now().UTC()
+		for _, ev := range s.EventStore.Snapshot(now) {
+			if ev.Target == target {
+				v := s.eventVerdict(ctx, panes[i], ev, now)
+				return &v, nil
 			}
-			return verdicts[i].Window < verdicts[j].Window
 		}
-		return verdicts[i].Session < verdicts[j].Session
-	})
+	}
+	verdicts, _, _, err := s.evaluateAll(ctx, panes[i:i+1], progressSink{})
+	if err != nil || len(verdicts) == 0 {
+		return nil, err
+	}
+	return &verdicts[0], nil
+}
 
-	return &ScanResult{Verdicts: verdicts}
+// paneLess orders verdicts by session, window, and pane.
+func paneLess(a, b model.Verdict) bool {
+	if a.Session == b.Session {
+		if a.Window == b.Window {
+			return a.Pane < b.Pane
+		}
+		return a.Window < b.Window
+	}
+	return a.Session < b.Session
 }
 
 func eventReason(state, message string) string {
@@ -234,8 +555,9 @@ func eventReason(state, message string) string {
 	}
 }
 
-func (s *Scanner) evaluatePane(ctx context.Context, pane model.Pane) (*model.Verdict, error) {
-	ctx, span := tracer.Start(ctx, "evaluate_pane",
+// evaluatePane captures pane through pool and evaluates the capture.
+func (s *Scanner) evaluatePane(ctx context.Context, pane model.Pane, pool *scanPool) (*model.Verdict, error) {
+	ctx, span := s.tracer().Start(ctx, "evaluate_pane",
 		trace.WithAttributes(
 			attribute.String("pane.target", pane.Target),
 			attribute.String("pane.session", pane.Session),
@@ -250,22 +572,32 @@ func (s *Scanner) evaluatePane(ctx context.Context, pane model.Pane) (*model.Ver
 
 	start := time.Now()
 
-	capture, err := s.Mux.CapturePane(ctx, pane.Target)
+	capture, err := pool.capture(ctx, s.Mux, pane.Target)
 	if err != nil {
-		return nil, fmt.Errorf("capture failed: %w", err)
+		err = fmt.Errorf("capture failed: %w", err)
+		recordSpanError(span, err)
+		return nil, err
 	}
+	evalStart := time.Now()
+	defer func() { pool.add(&pool.phases.Evaluate, time.Since(evalStart)) }()
 
 	// Prepend process metadata for context.
 	content := model.BuildProcessHeader(pane) + capture
+	hash := hashContent(content)
 
 	// Set the pane content as the observation input for Langfuse
 	span.SetAttributes(attribute.String("langfuse.observation.input", content))
 
 	// Check cache: if content hasn't changed, reuse the previous verdict
 	if s.Cache != nil {
-		if cached, ok := s.Cache.Lookup(pane.Target, content); ok {
+		if cached, ok := s.lookupCache(ctx, pane.Target, content); ok {
 			cached.DurationMs = time.Since(start).Milliseconds()
 			cached.EvalSource = model.EvalSourceCache
+			cached.Path = pane.Path
+			cached.PID = pane.PID
+			cached.ProcessTree = pane.ProcessTree
+			cached.LastActivity = pane.LastActivity
+			cached.ContentHash = hash
 
 			// Set output for Langfuse even on cache hits
 			cachedOutput := map[string]any{
@@ -291,17 +623,23 @@ func (s *Scanner) evaluatePane(ctx context.Context, pane model.Pane) (*model.Ver
 	// --- Deterministic parser for known agents ---
 	// Try parsers — instant, free, 100% accurate for known agents.
 	if s.Parsers != nil {
-		if parsed := s.Parsers.Parse(capture, pane.ProcessTree); parsed != nil {
+		if parsed := s.parse(ctx, capture, pane.ProcessTree); parsed != nil {
 			v := model.BaseVerdict(pane, start)
 			v.Agent = parsed.Agent
+			v.Model = parsed.Model
+			v.Confidence = parsed.Confidence
 			v.Blocked = parsed.Blocked
 			v.Reason = parsed.Reason
 			v.WaitingFor = parsed.WaitingFor
+			v.Activity = parsed.Activity
+			v.Idle = parsed.Idle
 			v.Reasoning = parsed.Reasoning
 			v.Actions = parsed.Actions
 			v.Recommended = parsed.Recommended
+			v.Dialog = parsed.Dialog
 			v.Subagents = parsed.Subagents
 			v.EvalSource = model.EvalSourceParser
+			v.ContentHash = hash
 			verdict := &v
 
 			if s.Verbose {
@@ -332,10 +670,12 @@ func (s *Scanner) evaluatePane(ctx context.Context, pane model.Pane) (*model.Ver
 			)
 
 			s.Metrics.RecordEvaluation(ctx, "parser")
+			s.log().Debug("parsed", "target", pane.Target, "agent", verdict.Agent, "blocked", verdict.Blocked,
+				"reason", verdict.Reason, "confidence", verdict.Confidence, "actions", len(verdict.Actions))
 
 			// Store in cache for future scans
 			if s.Cache != nil {
-				s.Cache.Store(pane.Target, content, *verdict)
+				s.storeCache(ctx, pane.Target, content, *verdict)
 			}
 
 			return verdict, nil
@@ -348,6 +688,7 @@ func (s *Scanner) evaluatePane(ctx context.Context, pane model.Pane) (*model.Ver
 	v.Blocked = false
 	v.Reason = "not recognized by deterministic parsers"
 	v.EvalSource = model.EvalSourceParser
+	v.ContentHash = hash
 	verdict := &v
 
 	if s.Verbose {
@@ -376,11 +717,49 @@ func (s *Scanner) evaluatePane(ctx context.Context, pane model.Pane) (*model.Ver
 	)
 
 	s.Metrics.RecordEvaluation(ctx, "parser")
+	s.log().Debug("not recognized", "target", pane.Target, "command", pane.Command)
 
 	// Store in cache for future scans
 	if s.Cache != nil {
-		s.Cache.Store(pane.Target, content, *verdict)
+		s.storeCache(ctx, pane.Target, content, *verdict)
 	}
 
 	return verdict, nil
 }
+
+// lookupCache is Cache.Lookup in a span.
+func (s *Scanner) lookupCache(ctx context.Context, target, content string) (*model.Verdict, bool) {
+	_, span := s.tracer().Start(ctx, "cache.lookup", trace.WithAttributes(attribute.String("pane.target", target)))
+	defer span.End()
+	v, ok := s.Cache.Lookup(target, content)
+	span.SetAttributes(attribute.Bool("cache.hit", ok))
+	if ok {
+		s.log().Debug("cache hit", "target", target, "agent", v.Agent, "blocked", v.Blocked)
+	}
+	return v, ok
+}
+
+// storeCache is Cache.Store in a span.
+func (s *Scanner) storeCache(ctx context.Context, target, content string, v model.Verdict) {
+	if s.Cache == nil {
+		return
+	}
+	_, span := s.tracer().Start(ctx, "cache.store", trace.WithAttributes(attribute.String("pane.target", target)))
+	defer span.End()
+	s.Cache.Store(target, content, v)
+}
+
+// parse runs the deterministic parsers in a span.
+func (s *Scanner) parse(ctx context.Context, capture string, processTree []string) *parser.Result {
+	_, span := s.tracer().Start(ctx, "parse")
+	defer span.End()
+	result := s.Parsers.Parse(capture, processTree)
+	span.SetAttributes(attribute.Bool("parser.hit", result != nil))
+	if result != nil {
+		span.SetAttributes(
+			attribute.String("verdict.agent", result.Agent),
+			attribute.Bool("verdict.blocked", result.Blocked),
+		)
+	}
+	return result
+}
@@ -2,14 +2,23 @@ package supervisor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/timvw/pane-patrol/internal/config"
 	"github.com/timvw/pane-patrol/internal/events"
 	"github.com/timvw/pane-patrol/internal/model"
+	muxpkg "github.com/timvw/pane-patrol/internal/mux"
 	"github.com/timvw/pane-patrol/internal/parser"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // mockMultiplexer implements mux.Multiplexer for testing.
@@ -75,6 +84,32 @@ func TestScanner_BasicScan(t *testing.T) {
 	}
 }
 
+func TestScanner_ResultsInPaneOrder(t *testing.T) {
+	// The multiplexer's listing order is not necessarily pane order; the
+	// result is, as in event-only mode.
+	mux := &mockMultiplexer{
+		panes: []model.Pane{
+			{Target: "web:10.0", Session: "web", Window: 10, Pane: 0, Command: "bash"},
+			{Target: "web:2.0", Session: "web", Window: 2, Pane: 0, Command: "bash"},
+			{Target: "api:0.1", Session: "api", Window: 0, Pane: 1, Command: "bash"},
+			{Target: "api:0.0", Session: "api", Window: 0, Pane: 0, Command: "bash"},
+		},
+		captures: map[string]string{"web:10.0": "$", "web:2.0": "$", "api:0.1": "$", "api:0.0": "$"},
+	}
+	scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), Parallel: 4}
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	var got []string
+	for _, v := range result.Verdicts {
+		got = append(got, v.Target)
+	}
+	if want := "api:0.0 api:0.1 web:2.0 web:10.0"; strings.Join(got, " ") != want {
+		t.Errorf("order: got %v, want %s", got, want)
+	}
+}
+
 func TestScanner_ExcludeSessions(t *testing.T) {
 	mux := &mockMultiplexer{
 		panes: []model.Pane{
@@ -109,6 +144,33 @@ func TestScanner_ExcludeSessions(t *testing.T) {
 	}
 }
 
+func TestScanner_PaneFilter(t *testing.T) {
+	mux := &mockMultiplexer{
+		panes: []model.Pane{
+			{Target: "dev:0.0", Session: "dev", WindowName: "agents", PID: 1, Command: "opencode"},
+			{Target: "dev:1.0", Session: "dev", Window: 1, WindowName: "logs", PID: 2, Command: "codex"},
+			{Target: "dev:0.1", Session: "dev", Pane: 1, WindowName: "agents", PID: 3, Command: "bash"},
+		},
+		captures: map[string]string{"dev:0.0": "content", "dev:1.0": "content", "dev:0.1": "content"},
+	}
+	panes, err := config.NewPaneFilter(
+		[]config.PaneMatch{{Command: "opencode|codex|claude"}},
+		[]config.PaneMatch{{Target: "*:logs.*"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), Panes: panes, Parallel: 2}
+
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(result.Verdicts) != 1 || result.Verdicts[0].Target != "dev:0.0" {
+		t.Errorf("expected only dev:0.0, got %+v", result.Verdicts)
+	}
+}
+
 func TestScanner_SelfExclusion(t *testing.T) {
 	mux := &mockMultiplexer{
 		panes: []model.Pane{
@@ -141,6 +203,28 @@ func TestScanner_SelfExclusion(t *testing.T) {
 	}
 }
 
+func TestScanner_SkipsOtherSupervisors(t *testing.T) {
+	mux := &mockMultiplexer{
+		panes: []model.Pane{
+			{Target: "dev:0.0", Session: "dev", PID: 1, Command: "bash"},
+			{Target: "alice:0.0", Session: "alice", PID: 2, Command: "pane-patrol", Supervisor: true},
+		},
+		captures: map[string]string{
+			"dev:0.0":   "content",
+			"alice:0.0": "content",
+		},
+	}
+
+	scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), SelfTarget: "supervisor:0.0", Parallel: 5}
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(result.Verdicts) != 1 || result.Verdicts[0].Target != "dev:0.0" {
+		t.Errorf("expected only dev:0.0, got %+v", result.Verdicts)
+	}
+}
+
 func TestScanner_EmptyPanes(t *testing.T) {
 	mux := &mockMultiplexer{
 		panes:    []model.Pane{},
@@ -236,7 +320,10 @@ func TestScanner_CacheHit(t *testing.T) {
 		t.Errorf("Scan 1: got eval_source %q, want %q", result1.Verdicts[0].EvalSource, model.EvalSourceParser)
 	}
 
-	// Second scan — same content, should be cache hit
+	// Second scan — same content, should be cache hit, with the pane's
+	// fresh metadata rather than the cached one's.
+	activity := time.Now().Truncate(time.Second)
+	mux.panes[0].LastActivity = activity
 	result2, err := scanner.Scan(context.Background())
 	if err != nil {
 		t.Fatalf("Scan 2 error: %v", err)
@@ -244,6 +331,9 @@ func TestScanner_CacheHit(t *testing.T) {
 	if result2.CacheHits != 1 {
 		t.Errorf("Scan 2: got %d cache hits, want 1", result2.CacheHits)
 	}
+	if got := result2.Verdicts[0].LastActivity; !got.Equal(activity) {
+		t.Errorf("Scan 2: LastActivity %v, want %v", got, activity)
+	}
 }
 
 func TestScanner_EventOnlyModeUsesStore(t *testing.T) {
@@ -286,6 +376,49 @@ func TestScanner_EventOnlyModeUsesStore(t *testing.T) {
 	}
 }
 
+func TestScanner_ScanStreamSendsEachVerdict(t *testing.T) {
+	store := events.NewStore(5 * time.Minute)
+	store.Upsert(events.Event{Assistant: "claude", State: events.StateWaitingInput, Target: "dev:0.1", TS: time.Now().UTC()})
+	mux := &mockMultiplexer{
+		panes: []model.Pane{
+			{Target: "dev:0.0", Session: "dev", PID: 1, Command: "bash"},
+			{Target: "dev:0.1", Session: "dev", Pane: 1, PID: 2, Command: "claude"},
+			{Target: "dev:0.2", Session: "dev", Pane: 2, PID: 3, Command: "zsh"},
+		},
+		captures: map[string]string{"dev:0.0": "$ ls", "dev:0.2": "$ ls"},
+	}
+
+	for _, eventOnly := range []bool{false, true} {
+		scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), EventStore: store, EventOnly: eventOnly, Parallel: 2}
+		progress := make(chan ScanProgress)
+		var streamed []ScanProgress
+		done := make(chan struct{})
+		go func() {
+			for p := range progress {
+				streamed = append(streamed, p)
+			}
+			close(done)
+		}()
+		result, err := scanner.ScanStream(context.Background(), progress)
+		close(progress)
+		<-done
+		if err != nil {
+			t.Fatalf("eventOnly=%v: ScanStream() error: %v", eventOnly, err)
+		}
+		if len(streamed) != len(result.Verdicts) {
+			t.Fatalf("eventOnly=%v: streamed %d verdicts, result has %d", eventOnly, len(streamed), len(result.Verdicts))
+		}
+		for _, p := range streamed {
+			if p.Total != 3 {
+				t.Errorf("eventOnly=%v: %s total = %d, want 3", eventOnly, p.Verdict.Target, p.Total)
+			}
+			if p.Verdict.Target == "dev:0.1" && eventOnly && p.Verdict.EvalSource != model.EvalSourceEvent {
+				t.Errorf("expected the hooked pane's event verdict, got %q", p.Verdict.EvalSource)
+			}
+		}
+	}
+}
+
 func TestScanner_EventOnlyModeAppliesExcludeSessions(t *testing.T) {
 	store := events.NewStore(5 * time.Minute)
 	now := time.Now().UTC()
@@ -440,3 +573,211 @@ func TestScanner_CapturePaneError(t *testing.T) {
 		t.Errorf("Agent: got %q, want %q", v.Agent, "error")
 	}
 }
+
+// slowMultiplexer records how many captures run at once.
+type slowMultiplexer struct {
+	mockMultiplexer
+	mu          sync.Mutex
+	running     int
+	maxRunning  int
+	captureTime time.Duration
+}
+
+func (m *slowMultiplexer) CapturePane(ctx context.Context, target string) (string, error) {
+	m.mu.Lock()
+	m.running++
+	m.maxRunning = max(m.maxRunning, m.running)
+	m.mu.Unlock()
+	time.Sleep(m.captureTime)
+	m.mu.Lock()
+	m.running--
+	m.mu.Unlock()
+	return "$ ", nil
+}
+
+func TestScanner_TmuxParallelBoundsCaptures(t *testing.T) {
+	mux := &slowMultiplexer{captureTime: 5 * time.Millisecond}
+	for i := range 12 {
+		mux.panes = append(mux.panes, model.Pane{Target: fmt.Sprintf("dev:0.%d", i), Session: "dev", Pane: i})
+	}
+	scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), Parallel: 8, TmuxParallel: 2}
+
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(result.Verdicts) != 12 || result.Verdicts[11].Target != "dev:0.11" {
+		t.Fatalf("expected 12 verdicts in pane order, got %d", len(result.Verdicts))
+	}
+	if mux.maxRunning > 2 {
+		t.Errorf("expected at most 2 concurrent captures, got %d", mux.maxRunning)
+	}
+	if result.Phases.Capture < 12*mux.captureTime {
+		t.Errorf("expected capture time summed over panes, got %s", result.Phases.Capture)
+	}
+}
+
+// batchMultiplexer captures in batches; batches containing a target in
+// failing fail as a whole.
+type batchMultiplexer struct {
+	mockMultiplexer
+	failing      string
+	batchCalls   int
+	captureCalls atomic.Int32
+}
+
+func (m *batchMultiplexer) CapturePanes(_ context.Context, targets []string) (map[string]string, error) {
+	m.batchCalls++
+	captures := map[string]string{}
+	for _, target := range targets {
+		if target == m.failing {
+			return nil, fmt.Errorf("can't find pane: %s", target)
+		}
+		captures[target] = m.captures[target]
+	}
+	return captures, nil
+}
+
+func (m *batchMultiplexer) CapturePane(ctx context.Context, target string) (string, error) {
+	m.captureCalls.Add(1)
+	return m.mockMultiplexer.CapturePane(ctx, target)
+}
+
+func TestScanner_BatchCapture(t *testing.T) {
+	mux := &batchMultiplexer{mockMultiplexer: mockMultiplexer{captures: map[string]string{}}}
+	for i := range batchCaptureSize + 10 {
+		target := fmt.Sprintf("dev:0.%d", i)
+		mux.panes = append(mux.panes, model.Pane{Target: target, Session: "dev", Pane: i})
+		mux.captures[target] = "$ "
+	}
+	scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), Parallel: 4}
+
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(result.Verdicts) != batchCaptureSize+10 || mux.batchCalls != 2 || mux.captureCalls.Load() != 0 {
+		t.Errorf("expected 2 batch captures and no single ones, got %d verdicts, %d batches, %d single",
+			len(result.Verdicts), mux.batchCalls, mux.captureCalls.Load())
+	}
+
+	// A failing batch falls back to one capture per pane.
+	mux.batchCalls, mux.failing = 0, "dev:0.55"
+	delete(mux.captures, "dev:0.55")
+	result, err = scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if mux.captureCalls.Load() != 10 || result.Verdicts[55].Agent != "error" || result.Verdicts[54].Agent != "unknown" {
+		t.Errorf("expected the second batch captured one by one, got %d single captures", mux.captureCalls.Load())
+	}
+}
+
+// vanishingMultiplexer fails captures of the listed targets with err.
+type vanishingMultiplexer struct {
+	mockMultiplexer
+	failing map[string]error
+}
+
+func (m *vanishingMultiplexer) CapturePane(ctx context.Context, target string) (string, error) {
+	if err := m.failing[target]; err != nil {
+		return "", fmt.Errorf("capture %s: %w", target, err)
+	}
+	return m.mockMultiplexer.CapturePane(ctx, target)
+}
+
+func TestScanner_PaneClosedMidScanIsDropped(t *testing.T) {
+	mux := &vanishingMultiplexer{
+		mockMultiplexer: mockMultiplexer{
+			panes: []model.Pane{
+				{Target: "dev:0.0", Session: "dev", PID: 1, Command: "bash"},
+				{Target: "dev:0.1", Session: "dev", PID: 2, Command: "bash"},
+			},
+			captures: map[string]string{"dev:0.0": "$ ls"},
+		},
+		failing: map[string]error{"dev:0.1": muxpkg.ErrPaneNotFound},
+	}
+	cache := NewVerdictCache(time.Minute)
+	cache.Store("dev:0.1", "old", model.Verdict{Target: "dev:0.1"})
+	cache.Store("gone:0.0", "old", model.Verdict{Target: "gone:0.0"})
+
+	scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), Parallel: 2, Cache: cache}
+	result, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+	if len(result.Verdicts) != 1 || result.Verdicts[0].Target != "dev:0.0" {
+		t.Fatalf("verdicts = %+v, want only dev:0.0", result.Verdicts)
+	}
+	if n := cache.Stats().Entries; n != 1 {
+		t.Errorf("cache entries = %d, want 1 (closed panes dropped)", n)
+	}
+}
+
+func TestScanner_ServerGoneMidScanFailsScan(t *testing.T) {
+	mux := &mockMultiplexer{
+		panes:   []model.Pane{{Target: "dev:0.0", Session: "dev", PID: 1, Command: "bash"}},
+		captErr: fmt.Errorf("capture: %w", muxpkg.ErrNoServer),
+	}
+	scanner := &Scanner{Mux: mux, Parsers: parser.NewRegistry(), Parallel: 1}
+	_, err := scanner.Scan(context.Background())
+	if !errors.Is(err, muxpkg.ErrNoServer) {
+		t.Fatalf("Scan() error = %v, want ErrNoServer", err)
+	}
+}
+
+func TestScanner_TraceSpans(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	mux := &mockMultiplexer{
+		panes: []model.Pane{
+			{Target: "dev:0.0", Session: "dev", Command: "bash"},
+			{Target: "dev:0.1", Session: "dev", Command: "bash"},
+		},
+		captures: map[string]string{"dev:0.0": "$ ls", "dev:0.1": "$ ls"},
+	}
+	scanner := &Scanner{
+		Mux:      mux,
+		Parsers:  parser.NewRegistry(),
+		Parallel: 2,
+		Cache:    NewVerdictCache(time.Minute),
+		Tracer:   tp.Tracer("test"),
+	}
+	if _, err := scanner.Scan(context.Background()); err != nil {
+		t.Fatalf("Scan() error: %v", err)
+	}
+
+	spans := rec.Ended()
+	counts := map[string]int{}
+	var scanID trace.SpanID
+	for _, s := range spans {
+		counts[s.Name()]++
+		if s.Name() == "scan" {
+			scanID = s.SpanContext().SpanID()
+		}
+	}
+	want := map[string]int{"scan": 1, "list_panes": 1, "evaluate_pane": 2, "capture": 2, "cache.lookup": 2, "parse": 2, "cache.store": 2}
+	for name, n := range want {
+		if counts[name] != n {
+			t.Errorf("%s spans: got %d, want %d (all: %v)", name, counts[name], n, counts)
+		}
+	}
+	for _, s := range spans {
+		if (s.Name() == "list_panes" || s.Name() == "evaluate_pane") && s.Parent().SpanID() != scanID {
+			t.Errorf("%s is not a child of the scan span", s.Name())
+		}
+	}
+
+	// A failed listing marks the scan span as failed.
+	rec2 := tracetest.NewSpanRecorder()
+	scanner.Tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec2)).Tracer("test")
+	mux.listErr = errors.New("no server running")
+	if _, err := scanner.Scan(context.Background()); err == nil {
+		t.Fatal("expected a scan error")
+	}
+	for _, s := range rec2.Ended() {
+		if s.Status().Code != codes.Error {
+			t.Errorf("%s span status: got %v, want error", s.Name(), s.Status().Code)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package supervisor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// SpeakFunc speaks text aloud. The default implementation shells out to a
+// text-to-speech program. Tests can replace this to avoid exec.Command.
+type SpeakFunc func(text string) error
+
+// maxAnnouncements is the number of newly blocked panes announced
+// individually after a scan. Beyond this (e.g. the first scan after startup)
+// a single count is spoken instead of a long queue of sentences.
+const maxAnnouncements = 3
+
+// Announcer speaks a short sentence when a pane becomes blocked, for
+// accessibility and for users who are in the room but away from the screen.
+//
+// A pane is announced once per block: when it first reports blocked, or when
+// its reason changes while still blocked (a new dialog after the previous
+// one was answered); see VerdictStore. Non-agent prompts and errors are
+// never announced.
+type Announcer struct {
+	Speak SpeakFunc
+	// Labels name panes and sessions in announcements; nil uses session names.
+	Labels *Labels
+
+	speakMu sync.Mutex // serializes speech so sentences don't overlap
+}
+
+// NewAnnouncer returns an Announcer that speaks via command. If command is
+// empty, the first available of say (macOS), espeak-ng, and espeak is used
+// with the text as its argument. A custom command (e.g. a piper pipeline)
+// is run with sh -c and receives the text on stdin.
+func NewAnnouncer(command string) (*Announcer, error) {
+	speak, err := ttsSpeakFunc(command)
+	if err != nil {
+		return nil, err
+	}
+	return &Announcer{Speak: speak}, nil
+}
+
+// ttsPrograms lists the builtin text-to-speech programs in preference order.
+// Each accepts the text to speak as its final argument.
+var ttsPrograms = []string{"say", "espeak-ng", "espeak"}
+
+func ttsSpeakFunc(command string) (SpeakFunc, error) {
+	if command != "" {
+		return func(text string) error {
+			cmd := exec.Command("sh", "-c", command)
+			cmd.Stdin = strings.NewReader(text + "\n")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return fmt.Errorf("speech command failed: %w (output: %s)", err, strings.TrimSpace(string(out)))
+			}
+			return nil
+		}, nil
+	}
+	for _, name := range ttsPrograms {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+		return func(text string) error {
+			if out, err := exec.Command(path, text).CombinedOutput(); err != nil {
+				return fmt.Errorf("%s failed: %w (output: %s)", name, err, strings.TrimSpace(string(out)))
+			}
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("no text-to-speech program found (tried %s); set speech_command", strings.Join(ttsPrograms, ", "))
+}
+
+// Announce returns the sentences to speak for a scan's transitions: panes
+// that became blocked, or are blocked on a new dialog.
+func (a *Announcer) Announce(ts []Transition) []string {
+	var fresh []model.Verdict
+	for _, t := range ts {
+		if (t.Kind == BecameBlocked || t.Kind == ReasonChanged) && announceable(t.Verdict) {
+			fresh = append(fresh, t.Verdict)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+	if len(fresh) > maxAnnouncements {
+		return []string{fmt.Sprintf("%d panes blocked", len(fresh))}
+	}
+	texts := make([]string, 0, len(fresh))
+	for _, v := range fresh {
+		texts = append(texts, announcementText(v, a.Labels))
+	}
+	return texts
+}
+
+// SpeakAll speaks each text in order. Concurrent calls are serialized.
+// Returns the first error; remaining texts are skipped.
+func (a *Announcer) SpeakAll(texts []string) error {
+	a.speakMu.Lock()
+	defer a.speakMu.Unlock()
+	for _, text := range texts {
+		if err := a.Speak(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// announceable reports whether a verdict should produce an announcement.
+func announceable(v model.Verdict) bool {
+	return parser.IsAgentBlock(v)
+}
+
+// announcementText builds e.g. "Session api-refactor blocked: permission
+// required". A pane label replaces "Session <name>"; a session label
+// replaces the session name.
+func announcementText(v model.Verdict, labels *Labels) string {
+	subject := "Session " + v.Session
+	if label := labels.Pane(v.Target); label != "" {
+		subject = label
+	} else if label := labels.Session(v.Session); label != "" {
+		subject = "Session " + label
+	}
+	text := subject + " blocked"
+	if v.Reason != "" {
+		text = fmt.Sprintf("%s: %s", text, v.Reason)
+	}
+	if note := labels.Note(v.Target, v.PID); note != "" {
+		text += ". Note: " + note
+	}
+	return text
+}
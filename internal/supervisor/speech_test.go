@@ -0,0 +1,86 @@
+package supervisor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+func blockedVerdict(target, session, reason string) model.Verdict {
+	return model.Verdict{Target: target, Session: session, Agent: "claude_code", Blocked: true, Reason: reason}
+}
+
+func TestAnnouncer_AnnouncesNewBlocksOnce(t *testing.T) {
+	a, store := &Announcer{}, &VerdictStore{}
+
+	texts := a.Announce(store.Apply([]model.Verdict{blockedVerdict("api:0.0", "api-refactor", "permission required")}, time.Now()))
+	if len(texts) != 1 || texts[0] != "Session api-refactor blocked: permission required" {
+		t.Fatalf("first observe: got %q", texts)
+	}
+
+	// Same block on the next scan is not repeated.
+	texts = a.Announce(store.Apply([]model.Verdict{blockedVerdict("api:0.0", "api-refactor", "permission required")}, time.Now()))
+	if len(texts) != 0 {
+		t.Errorf("repeat observe: got %q, want nothing", texts)
+	}
+
+	// A different reason while still blocked is a new block.
+	texts = a.Announce(store.Apply([]model.Verdict{blockedVerdict("api:0.0", "api-refactor", "idle at prompt")}, time.Now()))
+	if len(texts) != 1 {
+		t.Errorf("changed reason: got %q, want one announcement", texts)
+	}
+}
+
+func TestAnnouncer_ReannouncesAfterUnblock(t *testing.T) {
+	a, store := &Announcer{}, &VerdictStore{}
+	blocked := blockedVerdict("api:0.0", "api", "permission required")
+	active := blocked
+	active.Blocked = false
+
+	a.Announce(store.Apply([]model.Verdict{blocked}, time.Now()))
+	a.Announce(store.Apply([]model.Verdict{active}, time.Now()))
+	if texts := a.Announce(store.Apply([]model.Verdict{blocked}, time.Now())); len(texts) != 1 {
+		t.Errorf("got %q, want one announcement after pane was unblocked", texts)
+	}
+}
+
+func TestAnnouncer_SkipsNonAgents(t *testing.T) {
+	a, store := &Announcer{}, &VerdictStore{}
+	prompt := blockedVerdict("sh:0.0", "sh", "yes/no confirmation prompt")
+	prompt.Agent = parser.AgentGenericPrompt
+	failed := blockedVerdict("x:0.0", "x", "evaluation failed")
+	failed.Agent = "error"
+
+	if texts := a.Announce(store.Apply([]model.Verdict{prompt, failed}, time.Now())); len(texts) != 0 {
+		t.Errorf("got %q, want no announcements", texts)
+	}
+}
+
+func TestAnnouncer_SummarizesManyBlocks(t *testing.T) {
+	a, store := &Announcer{}, &VerdictStore{}
+	var verdicts []model.Verdict
+	for _, s := range []string{"a", "b", "c", "d"} {
+		verdicts = append(verdicts, blockedVerdict(s+":0.0", s, "permission required"))
+	}
+	texts := a.Announce(store.Apply(verdicts, time.Now()))
+	if len(texts) != 1 || texts[0] != "4 panes blocked" {
+		t.Errorf("got %q, want single count announcement", texts)
+	}
+}
+
+func TestAnnouncer_SpeakAllStopsOnError(t *testing.T) {
+	var spoken []string
+	a := &Announcer{Speak: func(text string) error {
+		spoken = append(spoken, text)
+		return errors.New("no audio device")
+	}}
+	if err := a.SpeakAll([]string{"one", "two"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(spoken) != 1 {
+		t.Errorf("spoke %q, want only the first text", spoken)
+	}
+}
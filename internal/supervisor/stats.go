@@ -0,0 +1,243 @@
+package supervisor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// sessionStats accumulates statistics over one supervisor run for the
+// dashboard (D). It is only touched from Update, so it needs no locking.
+type sessionStats struct {
+	started time.Time
+
+	scans       int
+	scanTime    time.Duration
+	lastScan    time.Duration
+	slowestScan time.Duration
+	lastPhases  ScanPhases
+
+	evaluations int // pane verdicts received
+	cacheHits   int
+
+	nudges     int // panes that received keys (actions, text, raw keys)
+	autoNudges int // of which sent by auto-nudge
+
+	// outcomes counts what each action achieved (see outcomeTracker).
+	outcomes map[outcomeKey]*outcomeCount
+
+	// blockedSince is when each currently blocked pane was first seen
+	// blocked; finished blocks add to blockedTotal/blockedCount.
+	blockedSince map[string]time.Time
+	blockedTotal time.Duration
+	blockedCount int
+}
+
+// recordScan adds a completed scan. Panes that stopped being blocked (or
+// disappeared) since the previous scan finish their blocked period.
+func (s *sessionStats) recordScan(result *ScanResult, duration time.Duration, now time.Time) {
+	if s.started.IsZero() {
+		s.started = now
+	}
+	s.scans++
+	s.scanTime += duration
+	s.lastScan = duration
+	s.slowestScan = max(s.slowestScan, duration)
+	s.lastPhases = result.Phases
+	s.evaluations += len(result.Verdicts)
+	s.cacheHits += result.CacheHits
+
+	blocked := make(map[string]time.Time)
+	for _, v := range result.Verdicts {
+		if !parser.IsAgentBlock(v) {
+			continue
+		}
+		since, ok := s.blockedSince[v.Target]
+		if !ok {
+			since = now
+		}
+		blocked[v.Target] = since
+	}
+	for target, since := range s.blockedSince {
+		if _, still := blocked[target]; !still {
+			s.blockedTotal += now.Sub(since)
+			s.blockedCount++
+		}
+	}
+	s.blockedSince = blocked
+}
+
+// recordNudges counts panes that received keys.
+func (s *sessionStats) recordNudges(sent int, auto bool) {
+	s.nudges += sent
+	if auto {
+		s.autoNudges += sent
+	}
+}
+
+// avgBlocked is the mean duration of finished blocked periods, or 0 when
+// none finished yet.
+func (s *sessionStats) avgBlocked() time.Duration {
+	if s.blockedCount == 0 {
+		return 0
+	}
+	return s.blockedTotal / time.Duration(s.blockedCount)
+}
+
+// avgScan is the mean scan duration.
+func (s *sessionStats) avgScan() time.Duration {
+	if s.scans == 0 {
+		return 0
+	}
+	return s.scanTime / time.Duration(s.scans)
+}
+
+// cacheHitRatio is the share of pane evaluations served from the cache.
+func (s *sessionStats) cacheHitRatio() float64 {
+	if s.evaluations == 0 {
+		return 0
+	}
+	return float64(s.cacheHits) / float64(s.evaluations)
+}
+
+// agentCount is one row of the per-agent table.
+type agentCount struct {
+	agent   string
+	blocked int
+	active  int
+}
+
+// agentCounts counts blocked and active panes per agent in the latest
+// verdicts, busiest agents first. Non-agent panes are left out.
+func agentCounts(verdicts []model.Verdict) []agentCount {
+	byAgent := map[string]*agentCount{}
+	for _, v := range verdicts {
+		if v.Agent == "not_an_agent" || v.Agent == "" {
+			continue
+		}
+		c, ok := byAgent[v.Agent]
+		if !ok {
+			c = &agentCount{agent: v.Agent}
+			byAgent[v.Agent] = c
+		}
+		if v.Blocked {
+			c.blocked++
+		} else if v.Agent != "error" {
+			c.active++
+		}
+	}
+	counts := make([]agentCount, 0, len(byAgent))
+	for _, c := range byAgent {
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		ti, tj := counts[i].blocked+counts[i].active, counts[j].blocked+counts[j].active
+		if ti != tj {
+			return ti > tj
+		}
+		return counts[i].agent < counts[j].agent
+	})
+	return counts
+}
+
+// viewDashboard renders the session statistics screen.
+func (m *tuiModel) viewDashboard() string {
+	s := &m.stats
+	now := time.Now()
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Dashboard"))
+	if !s.started.IsZero() {
+		b.WriteString("  ")
+		b.WriteString(m.s.dim.Render(fmt.Sprintf("session %s, %d scans", formatDuration(now.Sub(s.started)), s.scans)))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(m.s.header.Render(fmt.Sprintf("  %-20s %8s %8s", "agent", "blocked", "active")))
+	b.WriteString("\n")
+	counts := agentCounts(m.verdicts)
+	if len(counts) == 0 {
+		b.WriteString(m.s.dim.Render("  no agent panes"))
+		b.WriteString("\n")
+	}
+	for _, c := range counts {
+		blocked := fmt.Sprintf("%8d", c.blocked)
+		if c.blocked > 0 {
+			blocked = m.s.blocked.Render(blocked)
+		}
+		b.WriteString(fmt.Sprintf("  %s %s %8d\n", padRight(truncate(c.agent, 20), 20), blocked, c.active))
+	}
+	b.WriteString("\n")
+
+	// Only finished blocks are averaged; panes still blocked are shown
+	// separately.
+	blockedNow := ""
+	if n := len(s.blockedSince); n > 0 {
+		var longest time.Duration
+		for _, since := range s.blockedSince {
+			longest = max(longest, now.Sub(since))
+		}
+		blockedNow = fmt.Sprintf(" (%d blocked now, longest %s)", n, formatDuration(longest))
+	}
+	avgBlocked := "n/a"
+	if s.blockedCount > 0 {
+		avgBlocked = fmt.Sprintf("%s over %d", formatDuration(s.avgBlocked()), s.blockedCount)
+	}
+	rows := [][2]string{
+		{"avg blocked time", avgBlocked + blockedNow},
+		{"nudges sent", fmt.Sprintf("%d (%d by auto-nudge)", s.nudges, s.autoNudges)},
+		{"cache hit ratio", fmt.Sprintf("%.0f%% (%d of %d evaluations)", 100*s.cacheHitRatio(), s.cacheHits, s.evaluations)},
+		{"scan latency", fmt.Sprintf("last %s, avg %s, max %s",
+			s.lastScan.Round(time.Millisecond), s.avgScan().Round(time.Millisecond), s.slowestScan.Round(time.Millisecond))},
+		{"last scan phases", fmt.Sprintf("list %s, capture %s, evaluate %s (summed over panes)",
+			s.lastPhases.List.Round(time.Millisecond), s.lastPhases.Capture.Round(time.Millisecond), s.lastPhases.Evaluate.Round(time.Millisecond))},
+	}
+	for _, r := range rows {
+		b.WriteString("  ")
+		b.WriteString(m.s.dim.Render(fmt.Sprintf("%-18s", r[0])))
+		b.WriteString(r[1])
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	m.viewOutcomes(&b)
+	b.WriteString(m.styleHints("  r rescan  D/esc close"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatDuration renders d as "45s", "12m", or "3h05m".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// handleDashboardKey handles keys on the dashboard: r rescans, D or esc
+// returns to the list.
+func (m *tuiModel) handleDashboardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "D":
+		m.dashboard = false
+	case "r":
+		if !m.scanning {
+			m.scanning = true
+			m.message = ""
+			return m, m.doScan()
+		}
+	}
+	return m, nil
+}
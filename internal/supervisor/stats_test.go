@@ -0,0 +1,99 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestSessionStats_BlockedDurations(t *testing.T) {
+	var s sessionStats
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	blocked := model.Verdict{Target: "a:0.0", Agent: "claude_code", Blocked: true}
+	active := model.Verdict{Target: "a:0.0", Agent: "claude_code"}
+
+	s.recordScan(&ScanResult{Verdicts: []model.Verdict{blocked}}, 20*time.Millisecond, t0)
+	s.recordScan(&ScanResult{Verdicts: []model.Verdict{blocked}, CacheHits: 1}, 40*time.Millisecond, t0.Add(30*time.Second))
+	if s.blockedCount != 0 || len(s.blockedSince) != 1 {
+		t.Fatalf("still blocked: count=%d since=%v", s.blockedCount, s.blockedSince)
+	}
+	s.recordScan(&ScanResult{Verdicts: []model.Verdict{active}, CacheHits: 1}, 30*time.Millisecond, t0.Add(time.Minute))
+	if got := s.avgBlocked(); got != time.Minute {
+		t.Errorf("avgBlocked: got %s, want 1m", got)
+	}
+	if len(s.blockedSince) != 0 {
+		t.Errorf("expected no blocked panes, got %v", s.blockedSince)
+	}
+	if got := s.avgScan(); got != 30*time.Millisecond || s.slowestScan != 40*time.Millisecond {
+		t.Errorf("scan latency: avg %s max %s", got, s.slowestScan)
+	}
+	if got := s.cacheHitRatio(); got < 0.66 || got > 0.67 {
+		t.Errorf("cacheHitRatio: got %f, want 2/3", got)
+	}
+}
+
+func TestSessionStats_DisappearedPaneEndsBlock(t *testing.T) {
+	var s sessionStats
+	t0 := time.Now()
+	s.recordScan(&ScanResult{Verdicts: []model.Verdict{{Target: "a:0.0", Agent: "codex", Blocked: true}}}, 0, t0)
+	s.recordScan(&ScanResult{}, 0, t0.Add(10*time.Second))
+	if s.blockedCount != 1 || s.avgBlocked() != 10*time.Second {
+		t.Errorf("expected one 10s block, got %d / %s", s.blockedCount, s.avgBlocked())
+	}
+}
+
+func TestAgentCounts(t *testing.T) {
+	counts := agentCounts([]model.Verdict{
+		{Agent: "codex", Blocked: true},
+		{Agent: "claude_code", Blocked: true},
+		{Agent: "claude_code"},
+		{Agent: "not_an_agent"},
+		{Agent: "error"},
+	})
+	if len(counts) != 3 {
+		t.Fatalf("got %d rows, want 3: %+v", len(counts), counts)
+	}
+	if counts[0] != (agentCount{agent: "claude_code", blocked: 1, active: 1}) {
+		t.Errorf("busiest agent first: got %+v", counts[0])
+	}
+	if counts[2] != (agentCount{agent: "error"}) {
+		t.Errorf("errors are neither blocked nor active: got %+v", counts[2])
+	}
+}
+
+func TestDashboard_ToggleAndNudgeCount(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.Update(nudgeResultMsg{messages: []string{"sent"}, sent: 1})
+	_, _ = m.Update(nudgeResultMsg{messages: []string{"auto"}, sent: 2, auto: true})
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	if !m.dashboard {
+		t.Fatal("expected D to open the dashboard")
+	}
+	view := m.View()
+	for _, want := range []string{"opencode", "3 (2 by auto-nudge)", "scan latency"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("dashboard missing %q:\n%s", want, view)
+		}
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.dashboard {
+		t.Error("expected esc to close the dashboard")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Second:               "45s",
+		12*time.Minute + 5*time.Second: "12m",
+		3*time.Hour + 5*time.Minute:    "3h05m",
+	}
+	for d, want := range tests {
+		if got := formatDuration(d); got != want {
+			t.Errorf("formatDuration(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package supervisor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/launch"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// templateEntry is one launch template offered by the picker. Invalid
+// templates are listed with their error so typos are visible.
+type templateEntry struct {
+	path string
+	tpl  *launch.Template
+	err  error
+}
+
+// loadTemplateEntries loads every template in dir.
+func loadTemplateEntries(dir string) ([]templateEntry, error) {
+	paths, err := launch.ListTemplates(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]templateEntry, 0, len(paths))
+	for _, path := range paths {
+		tpl, err := launch.LoadTemplate(path)
+		entries = append(entries, templateEntry{path: path, tpl: tpl, err: err})
+	}
+	return entries, nil
+}
+
+// handleTemplatePickerKey handles keys while the launch template picker is
+// open: navigate, enter to launch, esc to close.
+func (m *tuiModel) handleTemplatePickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.templates = nil
+	case "up", "k":
+		if m.templateCursor > 0 {
+			m.templateCursor--
+		}
+	case "down", "j":
+		if m.templateCursor < len(m.templates)-1 {
+			m.templateCursor++
+		}
+	case "enter":
+		entry := m.templates[m.templateCursor]
+		if entry.err != nil {
+			m.message = entry.err.Error()
+			return m, nil
+		}
+		m.templates = nil
+		m.message = fmt.Sprintf("Launching %s...", filepath.Base(entry.path))
+		return m, launchTemplateCmd(m, entry.tpl)
+	}
+	return m, nil
+}
+
+// launchTemplateCmd creates the template's sessions in the background.
+func launchTemplateCmd(m *tuiModel, tpl *launch.Template) tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		launched, err := launch.Launch(ctx, mux.NewTmux(), tpl)
+		return launchResultMsg{launched: launched, err: err}
+	}
+}
+
+// viewTemplatePicker lists the launch templates with their sessions.
+func (m *tuiModel) viewTemplatePicker() string {
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Launch template"))
+	b.WriteString("  ")
+	b.WriteString(m.s.dim.Render(m.templateDir))
+	b.WriteString("\n\n")
+	for i, e := range m.templates {
+		name := filepath.Base(e.path)
+		var detail string
+		if e.err != nil {
+			detail = "invalid: " + e.err.Error()
+		} else {
+			sessions := make([]string, len(e.tpl.Sessions))
+			for j, s := range e.tpl.Sessions {
+				sessions[j] = s.Name
+			}
+			detail = fmt.Sprintf("%d panes in %s", e.tpl.PaneCount(), strings.Join(sessions, ", "))
+		}
+		line := fmt.Sprintf("  %s  %s", name, truncate(detail, m.width-len(name)-6))
+		if i == m.templateCursor {
+			b.WriteString(m.s.selected.Render(line))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styleHints("  ↑↓ navigate  enter launch  esc cancel"))
+	b.WriteString("\n")
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTemplatePicker_ListsAndCancels(t *testing.T) {
+	dir := t.TempDir()
+	good := "sessions:\n  - name: api\n    panes:\n      - command: claude\n      - command: codex\n"
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(good), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("sessions: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(simpleVerdict())
+	m.templateDir = dir
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	if len(m.templates) != 2 {
+		t.Fatalf("expected 2 templates, got %+v", m.templates)
+	}
+	view := m.View()
+	if !strings.Contains(view, "2 panes in api") || !strings.Contains(view, "broken.yaml  invalid:") {
+		t.Errorf("picker view:\n%s", view)
+	}
+
+	// An invalid template is not launched.
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.templates == nil {
+		t.Error("enter on an invalid template must not launch or close the picker")
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.templates != nil {
+		t.Error("esc should close the picker")
+	}
+}
+
+func TestTemplatePicker_EmptyDir(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.templateDir = t.TempDir()
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	if m.templates != nil || !strings.Contains(m.message, "No launch templates") {
+		t.Errorf("templates=%v message=%q", m.templates, m.message)
+	}
+}
@@ -0,0 +1,350 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// maxTextInput caps free-form answers so a runaway paste cannot flood a
+// pane.
+const maxTextInput = 8192
+
+// textInput is the free-form answer being typed for one pane (opened with
+// t). It is a small multi-line editor: Enter sends, alt+enter or ctrl+j
+// starts a new line. Multi-line text is pasted into the pane (see
+// NudgePane) so the agent receives the newlines as part of one message.
+type textInput struct {
+	target string
+	text   []rune
+	cursor int // rune offset into text
+
+	// picking is true while the snippet picker (ctrl+p) is open;
+	// snippetCursor is the highlighted entry of choices, the snippets or,
+	// when opened with o, the continuations.
+	picking       bool
+	snippetCursor int
+	choices       []config.Snippet
+
+	// continuation is true when the text is a continuation prompt for an
+	// idle agent (opened with o); it is remembered for resending with ".".
+	continuation bool
+
+	// after is the action opening the pane's text field (model.Action.Text),
+	// sent before the text; nil types into the agent's input.
+	after *model.Action
+}
+
+// insert inserts s at the cursor and reports whether it had to be cut at
+// maxTextInput. Tabs become spaces and CRLF/CR become LF.
+func (ti *textInput) insert(s string) bool {
+	s = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\t", " ").Replace(s)
+	runes := []rune(s)
+	truncated := false
+	if room := maxTextInput - len(ti.text); len(runes) > room {
+		runes = runes[:max(room, 0)]
+		truncated = true
+	}
+	text := make([]rune, 0, len(ti.text)+len(runes))
+	text = append(text, ti.text[:ti.cursor]...)
+	text = append(text, runes...)
+	text = append(text, ti.text[ti.cursor:]...)
+	ti.text = text
+	ti.cursor += len(runes)
+	return truncated
+}
+
+// backspace deletes the rune before the cursor.
+func (ti *textInput) backspace() {
+	if ti.cursor == 0 {
+		return
+	}
+	ti.text = append(ti.text[:ti.cursor-1], ti.text[ti.cursor:]...)
+	ti.cursor--
+}
+
+// deleteForward deletes the rune under the cursor.
+func (ti *textInput) deleteForward() {
+	if ti.cursor < len(ti.text) {
+		ti.text = append(ti.text[:ti.cursor], ti.text[ti.cursor+1:]...)
+	}
+}
+
+// lineStart returns the offset of the first rune of the line containing pos.
+func (ti *textInput) lineStart(pos int) int {
+	for pos > 0 && ti.text[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+// lineEnd returns the offset of the newline (or end of text) ending the
+// line containing pos.
+func (ti *textInput) lineEnd(pos int) int {
+	for pos < len(ti.text) && ti.text[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+// moveLine moves the cursor to the previous (-1) or next (+1) line,
+// keeping the column where possible.
+func (ti *textInput) moveLine(dir int) {
+	start := ti.lineStart(ti.cursor)
+	col := ti.cursor - start
+	var target int
+	if dir < 0 {
+		if start == 0 {
+			return
+		}
+		target = ti.lineStart(start - 1)
+	} else {
+		end := ti.lineEnd(ti.cursor)
+		if end == len(ti.text) {
+			return
+		}
+		target = end + 1
+	}
+	ti.cursor = min(target+col, ti.lineEnd(target))
+}
+
+// handleTextInputKey handles keys while typing a free-form answer. Pasted
+// text (bracketed paste or ctrl+v from the system clipboard) is inserted
+// like typed text, newlines included.
+func (m *tuiModel) handleTextInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	ti := m.textInput
+	if ti.picking {
+		return m.handleSnippetPickerKey(msg)
+	}
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.textInput = nil
+		return m, nil
+	case tea.KeyEnter:
+		if msg.Alt {
+			m.insertText("\n")
+			return m, nil
+		}
+		text := strings.TrimSpace(string(ti.text))
+		if text == "" {
+			return m, nil
+		}
+		m.textInput = nil
+		if ti.continuation {
+			m.rememberContinuation(ti.target, text)
+		}
+		if ti.after != nil {
+			return m, m.sendAnswer(ti.target, Answer{Action: ti.after, Text: text})
+		}
+		return m, m.sendText(ti.target, text)
+	case tea.KeyCtrlJ:
+		m.insertText("\n")
+	case tea.KeyBackspace:
+		ti.backspace()
+	case tea.KeyDelete:
+		ti.deleteForward()
+	case tea.KeyLeft:
+		ti.cursor = max(ti.cursor-1, 0)
+	case tea.KeyRight:
+		ti.cursor = min(ti.cursor+1, len(ti.text))
+	case tea.KeyUp:
+		ti.moveLine(-1)
+	case tea.KeyDown:
+		ti.moveLine(1)
+	case tea.KeyHome, tea.KeyCtrlA:
+		ti.cursor = ti.lineStart(ti.cursor)
+	case tea.KeyEnd, tea.KeyCtrlE:
+		ti.cursor = ti.lineEnd(ti.cursor)
+	case tea.KeyCtrlU:
+		ti.text = nil
+		ti.cursor = 0
+	case tea.KeyCtrlV:
+		return m, pasteCmd()
+	case tea.KeyCtrlP:
+		if len(m.snippets) == 0 {
+			m.message = "No snippets configured (add snippets: to the config file)"
+			return m, nil
+		}
+		ti.picking, ti.choices, ti.snippetCursor = true, m.snippets, 0
+	case tea.KeyCtrlY:
+		for _, v := range m.verdicts {
+			if v.Target == ti.target {
+				return m, copyCmd(v)
+			}
+		}
+	case tea.KeySpace:
+		m.insertText(" ")
+	case tea.KeyRunes:
+		m.insertText(string(msg.Runes))
+	}
+	return m, nil
+}
+
+// handleSnippetPickerKey handles keys while choosing a canned answer:
+// enter or 1-9 insert the snippet at the cursor, esc returns to typing.
+func (m *tuiModel) handleSnippetPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	ti := m.textInput
+	pick := -1
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc, tea.KeyCtrlP:
+		ti.picking = false
+	case tea.KeyUp:
+		ti.snippetCursor = max(ti.snippetCursor-1, 0)
+	case tea.KeyDown:
+		ti.snippetCursor = min(ti.snippetCursor+1, len(ti.choices)-1)
+	case tea.KeyEnter:
+		pick = ti.snippetCursor
+	case tea.KeyRunes:
+		if key := msg.String(); len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if i := int(key[0] - '1'); i < len(ti.choices) {
+				pick = i
+			}
+		}
+	}
+	if pick >= 0 {
+		ti.picking = false
+		ti.snippetCursor = pick
+		m.insertText(ti.choices[pick].Text)
+	}
+	return m, nil
+}
+
+// insertText inserts typed or pasted text into the open text input.
+func (m *tuiModel) insertText(s string) {
+	if m.textInput.insert(s) {
+		m.message = fmt.Sprintf("Input is limited to %d characters", maxTextInput)
+	}
+}
+
+// sendText types text into target and presses Enter.
+func (m *tuiModel) sendText(target, text string) tea.Cmd {
+	m.invalidateCache(target)
+	nudger := m.nudger
+	return func() tea.Msg {
+		if err := nudger.NudgePane(target, text, false); err != nil {
+			return nudgeResultMsg{messages: []string{fmt.Sprintf("send to %s failed: %v", target, err)}}
+		}
+		return nudgeResultMsg{messages: []string{fmt.Sprintf("sent %d chars to %s", len([]rune(text)), target)}, sent: 1,
+			nudges: []nudgeTask{{target: target, keys: text, label: "text answer", typed: true}}}
+	}
+}
+
+// sendAnswer sends an answer's action, then types its text into the text
+// field the action opened and presses Enter.
+func (m *tuiModel) sendAnswer(target string, a Answer) tea.Cmd {
+	m.invalidateCache(target)
+	nudger := m.nudger
+	return func() tea.Msg {
+		if err := nudger.SendAnswer(target, a); err != nil {
+			return nudgeResultMsg{messages: []string{fmt.Sprintf("send to %s failed: %v", target, err)}}
+		}
+		return nudgeResultMsg{messages: []string{fmt.Sprintf("sent %s with %d chars to %s", a.Action.Label, len([]rune(a.Text)), target)}, sent: 1,
+			nudges: []nudgeTask{{target: target, keys: a.Action.Keys + " " + a.Text, label: a.Action.Label, risk: a.Action.Risk, typed: true}}}
+	}
+}
+
+// inputLines splits the input into display lines with the cursor block
+// drawn at its position, and returns the index of the cursor's line.
+func (ti *textInput) inputLines() ([]string, int) {
+	before := string(ti.text[:ti.cursor])
+	after := string(ti.text[ti.cursor:])
+	lines := strings.Split(before+"█"+after, "\n")
+	return lines, strings.Count(before, "\n")
+}
+
+// viewTextInput shows what the pane is waiting for above the input.
+func (m *tuiModel) viewTextInput() string {
+	ti := m.textInput
+	var b strings.Builder
+	title := "Answer"
+	if ti.continuation {
+		title = "Continue"
+	}
+	if ti.after != nil {
+		title = fmt.Sprintf("Answer (%s)", ti.after.Label)
+	}
+	b.WriteString(m.s.title.Render(title))
+	b.WriteString("  ")
+	b.WriteString(m.s.header.Render(ti.target))
+	b.WriteString("\n\n")
+
+	lines, cursorLine := ti.inputLines()
+	// The input gets up to half the screen; the question gets the rest.
+	inputRows := min(len(lines), max((m.height-8)/2, 1))
+	for _, v := range m.verdicts {
+		if v.Target != ti.target {
+			continue
+		}
+		question := strings.Split(copyText(v), "\n")
+		if budget := m.height - 8 - inputRows; len(question) > budget {
+			question = question[:max(budget, 1)]
+		}
+		for _, line := range question {
+			b.WriteString("  ")
+			b.WriteString(m.s.dim.Render(truncate(line, m.width-3)))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+
+	// Scroll so the cursor line stays visible.
+	first := max(cursorLine-inputRows+1, 0)
+	for i, line := range lines[first : first+inputRows] {
+		prompt := "  > "
+		if first+i > 0 {
+			prompt = "    "
+		}
+		// Scroll long lines horizontally so the cursor stays visible,
+		// counting columns: wide runes take two.
+		if width := m.width - 5; first+i == cursorLine && runewidth.StringWidth(line) > width {
+			end := strings.Index(line, "█") + len("█")
+			if over := runewidth.StringWidth(line[:end]) - width; over > 0 {
+				line = runewidth.TruncateLeft(line, over, "")
+			}
+			line = runewidth.Truncate(line, width, "")
+		} else {
+			line = truncate(line, m.width-5)
+		}
+		b.WriteString(prompt)
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	if ti.picking {
+		m.viewSnippetPicker(&b)
+	} else {
+		b.WriteString(m.styleHints("  enter send  alt+enter newline  ctrl+p snippets  ctrl+v paste  ctrl+y copy question  ctrl+u clear  esc cancel"))
+		b.WriteString("\n")
+	}
+	if m.message != "" {
+		b.WriteString(m.s.status.Render("  " + m.message))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// viewSnippetPicker lists the snippets or continuations below the input.
+func (m *tuiModel) viewSnippetPicker(b *strings.Builder) {
+	for i, sn := range m.textInput.choices {
+		line := fmt.Sprintf("  %d. %s", i+1, truncate(sn.Label(), m.width-7))
+		if i >= 9 {
+			line = "     " + truncate(sn.Label(), m.width-7)
+		}
+		if i == m.textInput.snippetCursor {
+			b.WriteString(m.s.selected.Render(line))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(m.styleHints("  ↑↓ navigate  enter/1-9 insert  esc back"))
+	b.WriteString("\n")
+}
@@ -0,0 +1,185 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestTextInput_TypePasteAndSend(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if m.textInput == nil || m.textInput.target != "test:0.0" {
+		t.Fatalf("expected text input for test:0.0, got %+v", m.textInput)
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("use")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeySpace})
+	// Bracketed paste arrives as one KeyMsg and keeps its newlines.
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("pnpm\r\nnot npm"), Paste: true})
+	_, _ = m.Update(clipboardPasteMsg{text: "!"})
+	if got := string(m.textInput.text); got != "use pnpm\nnot npm!" {
+		t.Fatalf("text: got %q", got)
+	}
+	view := m.View()
+	if !strings.Contains(view, "> use pnpm\n") || !strings.Contains(view, "    not npm!█") {
+		t.Errorf("view:\n%s", view)
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.textInput != nil {
+		t.Error("enter should send and close the input")
+	}
+}
+
+func TestTextInput_SurvivesRescans(t *testing.T) {
+	v := simpleVerdict()
+	m := newTestModel(v)
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("use pnpm")})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyLeft})
+
+	// Auto-refresh brings a new pane listed before the selected one; the
+	// half-typed answer, its cursor, and the selection stay.
+	other := blockedVerdict("api:0.0", "api", "permission required")
+	m.expanded["api"] = true
+	_, _ = m.Update(scanResultMsg{result: &ScanResult{Verdicts: []model.Verdict{other, v}}})
+	if m.textInput == nil || string(m.textInput.text) != "use pnpm" || m.textInput.cursor != 7 {
+		t.Fatalf("text input after a rescan: %+v", m.textInput)
+	}
+	if sel := m.selectedVerdict(); sel == nil || sel.Target != v.Target {
+		t.Errorf("selection after a rescan: %+v", sel)
+	}
+}
+
+func TestTextInput_Limit(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.textInput = &textInput{target: "test:0.0"}
+	m.insertText(strings.Repeat("x", maxTextInput+10))
+	if len(m.textInput.text) != maxTextInput {
+		t.Errorf("len: got %d, want %d", len(m.textInput.text), maxTextInput)
+	}
+	if !strings.Contains(m.message, "limited") {
+		t.Errorf("message: got %q", m.message)
+	}
+}
+
+func TestTextInput_EmptyEnterAndEsc(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.textInput = &textInput{target: "test:0.0"}
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.textInput == nil {
+		t.Error("enter on empty input should do nothing")
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.textInput != nil {
+		t.Error("esc should close the input")
+	}
+}
+
+func TestTextInput_MultiLineEditing(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.textInput = &textInput{target: "test:0.0"}
+	key := func(k tea.KeyMsg) { _, _ = m.handleKey(k) }
+	typeText := func(s string) { key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}) }
+
+	typeText("first line")
+	key(tea.KeyMsg{Type: tea.KeyEnter, Alt: true})
+	typeText("second")
+	key(tea.KeyMsg{Type: tea.KeyCtrlJ})
+	typeText("3rd")
+	if got := string(m.textInput.text); got != "first line\nsecond\n3rd" {
+		t.Fatalf("text: got %q", got)
+	}
+
+	// Up keeps the column (3), clamped to the shorter line when needed.
+	key(tea.KeyMsg{Type: tea.KeyUp})
+	key(tea.KeyMsg{Type: tea.KeyUp})
+	typeText("!")
+	if got := string(m.textInput.text); got != "fir!st line\nsecond\n3rd" {
+		t.Fatalf("after up/up insert: got %q", got)
+	}
+
+	key(tea.KeyMsg{Type: tea.KeyEnd})
+	key(tea.KeyMsg{Type: tea.KeyBackspace})
+	key(tea.KeyMsg{Type: tea.KeyDown})
+	key(tea.KeyMsg{Type: tea.KeyHome})
+	key(tea.KeyMsg{Type: tea.KeyDelete})
+	if got := string(m.textInput.text); got != "fir!st lin\necond\n3rd" {
+		t.Fatalf("after end/backspace/down/home/delete: got %q", got)
+	}
+
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.textInput != nil {
+		t.Error("enter should send the whole message")
+	}
+}
+
+func TestTextInput_ScrollsWideRunesByColumns(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.width = 25
+	m.textInput = &textInput{target: "test:0.0"}
+	// 30 double-width runes take 60 columns; 20 are left for the input.
+	m.insertText(strings.Repeat("日本語", 10))
+
+	inputLine := func() string {
+		for _, line := range strings.Split(m.View(), "\n") {
+			if strings.HasPrefix(line, "  > ") {
+				return strings.TrimPrefix(line, "  > ")
+			}
+		}
+		t.Fatalf("no input line in:\n%s", m.View())
+		return ""
+	}
+	line := inputLine()
+	if !strings.HasSuffix(line, "語█") || runewidth.StringWidth(line) > 20 {
+		t.Errorf("cursor at the end: got %q (%d columns)", line, runewidth.StringWidth(line))
+	}
+
+	for range 25 {
+		_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyLeft})
+	}
+	line = inputLine()
+	if !strings.HasPrefix(line, "日本語日本█") || runewidth.StringWidth(line) > 20 {
+		t.Errorf("cursor near the start: got %q (%d columns)", line, runewidth.StringWidth(line))
+	}
+}
+
+func TestTextInput_SnippetPicker(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.snippets = []config.Snippet{
+		{Name: "tests first", Text: "Proceed, but write the tests first."},
+		{Text: "Skip this and continue."},
+	}
+	m.textInput = &textInput{target: "test:0.0"}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Ok. ")})
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlP})
+	view := m.View()
+	if !m.textInput.picking || !strings.Contains(view, "1. tests first") || !strings.Contains(view, "2. Skip this and continue.") {
+		t.Fatalf("expected snippet picker, got:\n%s", view)
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.textInput.picking || string(m.textInput.text) != "Ok. Skip this and continue." {
+		t.Fatalf("expected snippet inserted at cursor, got %q (picking=%v)", string(m.textInput.text), m.textInput.picking)
+	}
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlP})
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1")})
+	if !strings.HasSuffix(string(m.textInput.text), "Proceed, but write the tests first.") {
+		t.Errorf("1 should insert the first snippet, got %q", string(m.textInput.text))
+	}
+}
+
+func TestTextInput_NoSnippets(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.textInput = &textInput{target: "test:0.0"}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if m.textInput.picking || !strings.Contains(m.message, "No snippets") {
+		t.Errorf("picking=%v message=%q", m.textInput.picking, m.message)
+	}
+}
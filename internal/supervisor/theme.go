@@ -1,6 +1,14 @@
 package supervisor
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/timvw/pane-patrol/internal/config"
+)
 
 // Theme defines all colors used by the supervisor TUI.
 // Use DarkTheme() or LightTheme() to get a pre-built theme,
@@ -71,6 +79,139 @@ func ThemeByName(name string) Theme {
 	}
 }
 
+// ThemeFromColors builds a user-defined theme: the base theme with every
+// color set in c replaced. When ActiveNumber is unset but Secondary or
+// TextMuted is set, it is recomputed from them.
+func ThemeFromColors(c config.ThemeColors) Theme {
+	t := ThemeByName(c.Base)
+	set := func(dst *lipgloss.Color, v string) {
+		if v != "" {
+			*dst = lipgloss.Color(v)
+		}
+	}
+	set(&t.Primary, c.Primary)
+	set(&t.Secondary, c.Secondary)
+	set(&t.Accent, c.Accent)
+	set(&t.Error, c.Error)
+	set(&t.Warning, c.Warning)
+	set(&t.Success, c.Success)
+	set(&t.Info, c.Info)
+	set(&t.Text, c.Text)
+	set(&t.TextMuted, c.TextMuted)
+	set(&t.BackgroundPanel, c.BackgroundPanel)
+	set(&t.BackgroundElem, c.BackgroundElem)
+	set(&t.Border, c.Border)
+	if c.ActiveNumber != "" {
+		t.ActiveNumber = lipgloss.Color(c.ActiveNumber)
+	} else if c.Secondary != "" || c.TextMuted != "" {
+		t.ActiveNumber = tint(t.TextMuted, t.Secondary, 0.6)
+	}
+	return t
+}
+
+// tint mixes base towards over by amount (0..1). ANSI color numbers cannot
+// be mixed; over is returned for them.
+func tint(base, over lipgloss.Color, amount float64) lipgloss.Color {
+	b, ok1 := parseHex(string(base))
+	o, ok2 := parseHex(string(over))
+	if !ok1 || !ok2 {
+		return over
+	}
+	var mixed [3]int
+	for i := range mixed {
+		mixed[i] = int(float64(b[i]) + (float64(o[i])-float64(b[i]))*amount + 0.5)
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", mixed[0], mixed[1], mixed[2]))
+}
+
+// parseHex parses #rgb or #rrggbb into its components.
+func parseHex(s string) ([3]int, bool) {
+	var rgb [3]int
+	if len(s) == 4 && s[0] == '#' {
+		s = "#" + string([]byte{s[1], s[1], s[2], s[2], s[3], s[3]})
+	}
+	if len(s) != 7 || s[0] != '#' {
+		return rgb, false
+	}
+	for i := range rgb {
+		v, err := strconv.ParseUint(s[1+2*i:3+2*i], 16, 8)
+		if err != nil {
+			return rgb, false
+		}
+		rgb[i] = int(v)
+	}
+	return rgb, true
+}
+
+// themeSet holds the themes the supervisor can switch between (T): the
+// builtin dark and light themes followed by the user-defined ones.
+type themeSet struct {
+	names  []string
+	custom map[string]config.ThemeColors
+}
+
+func newThemeSet(custom map[string]config.ThemeColors) themeSet {
+	names := []string{"dark", "light"}
+	var user []string
+	for name := range custom {
+		user = append(user, name)
+	}
+	sort.Strings(user)
+	return themeSet{names: append(names, user...), custom: custom}
+}
+
+// theme returns the named theme; unknown names fall back to dark.
+func (ts themeSet) theme(name string) Theme {
+	if c, ok := ts.custom[name]; ok {
+		return ThemeFromColors(c)
+	}
+	return ThemeByName(name)
+}
+
+// next returns the name of the theme after name, wrapping around.
+func (ts themeSet) next(name string) string {
+	if len(ts.names) == 0 {
+		ts = newThemeSet(nil)
+	}
+	for i, n := range ts.names {
+		if n == name {
+			return ts.names[(i+1)%len(ts.names)]
+		}
+	}
+	return ts.names[0]
+}
+
+// ParseColorProfile maps a color_profile setting to a termenv profile.
+// "auto" and "" report false: keep the profile detected from the terminal
+// (COLORTERM, TERM), which degrades hex colors to the nearest 256 or 16
+// color automatically.
+func ParseColorProfile(name string) (termenv.Profile, bool) {
+	switch name {
+	case "truecolor":
+		return termenv.TrueColor, true
+	case "256":
+		return termenv.ANSI256, true
+	case "16":
+		return termenv.ANSI, true
+	case "none":
+		return termenv.Ascii, true
+	}
+	return termenv.Ascii, false
+}
+
+// colorProfileName describes a termenv profile for status messages.
+func colorProfileName(p termenv.Profile) string {
+	switch p {
+	case termenv.TrueColor:
+		return "truecolor"
+	case termenv.ANSI256:
+		return "256 colors"
+	case termenv.ANSI:
+		return "16 colors"
+	}
+	return "no colors"
+}
+
 // styles holds all lipgloss styles derived from a Theme.
 // Constructed once from a Theme and stored in tuiModel.
 type styles struct {
@@ -80,6 +221,7 @@ type styles struct {
 	blocked  lipgloss.Style
 	active   lipgloss.Style
 	err      lipgloss.Style
+	info     lipgloss.Style
 	dim      lipgloss.Style
 	text     lipgloss.Style
 	status   lipgloss.Style
@@ -98,6 +240,7 @@ func newStyles(t Theme) styles {
 		blocked:  lipgloss.NewStyle().Foreground(t.Warning),
 		active:   lipgloss.NewStyle().Foreground(t.Success),
 		err:      lipgloss.NewStyle().Foreground(t.Error),
+		info:     lipgloss.NewStyle().Foreground(t.Info),
 		dim:      lipgloss.NewStyle().Foreground(t.TextMuted),
 		text:     lipgloss.NewStyle().Foreground(t.Text),
 		status:   lipgloss.NewStyle().Foreground(t.TextMuted),
@@ -0,0 +1,101 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/timvw/pane-patrol/internal/config"
+)
+
+func TestThemeFromColors(t *testing.T) {
+	th := ThemeFromColors(config.ThemeColors{Base: "light", Primary: "#ff0000", Border: "240"})
+	light := LightTheme()
+	if th.Primary != "#ff0000" || th.Border != "240" {
+		t.Errorf("overrides not applied: primary=%s border=%s", th.Primary, th.Border)
+	}
+	if th.Text != light.Text || th.ActiveNumber != light.ActiveNumber {
+		t.Errorf("unset colors should come from the base theme: text=%s activeNumber=%s", th.Text, th.ActiveNumber)
+	}
+
+	// ActiveNumber follows a changed Secondary.
+	th = ThemeFromColors(config.ThemeColors{Secondary: "#ffffff", TextMuted: "#000000"})
+	if th.ActiveNumber != "#999999" {
+		t.Errorf("ActiveNumber: got %s, want #999999", th.ActiveNumber)
+	}
+}
+
+func TestTint(t *testing.T) {
+	// The builtin themes document their ActiveNumber as this tint.
+	if got := tint("#808080", "#5c9cf5", 0.6); got != "#6a91c6" {
+		t.Errorf("tint: got %s, want #6a91c6", got)
+	}
+	if got := tint("#888", "#fff", 1); got != "#ffffff" {
+		t.Errorf("tint of short hex: got %s", got)
+	}
+	if got := tint("244", "#5c9cf5", 0.6); got != "#5c9cf5" {
+		t.Errorf("tint with ANSI color: got %s, want the overlay color", got)
+	}
+}
+
+func TestThemeSet_Cycle(t *testing.T) {
+	ts := newThemeSet(map[string]config.ThemeColors{
+		"zenburn":   {Primary: "#dfaf8f"},
+		"solarized": {Primary: "#b58900"},
+	})
+	want := []string{"light", "solarized", "zenburn", "dark"}
+	name := "dark"
+	for _, w := range want {
+		name = ts.next(name)
+		if name != w {
+			t.Fatalf("next: got %s, want %s", name, w)
+		}
+	}
+	if got := ts.theme("solarized").Primary; got != lipgloss.Color("#b58900") {
+		t.Errorf("theme(solarized).Primary = %s", got)
+	}
+	if got := ts.theme("missing").Primary; got != DarkTheme().Primary {
+		t.Errorf("unknown theme should fall back to dark, got %s", got)
+	}
+}
+
+func TestParseColorProfile(t *testing.T) {
+	tests := map[string]termenv.Profile{"truecolor": termenv.TrueColor, "256": termenv.ANSI256, "16": termenv.ANSI, "none": termenv.Ascii}
+	for name, want := range tests {
+		if got, ok := ParseColorProfile(name); !ok || got != want {
+			t.Errorf("ParseColorProfile(%q) = %v, %v", name, got, ok)
+		}
+	}
+	for _, name := range []string{"", "auto"} {
+		if _, ok := ParseColorProfile(name); ok {
+			t.Errorf("ParseColorProfile(%q) should keep the detected profile", name)
+		}
+	}
+}
+
+func TestListKey_T_CyclesTheme(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.themes = newThemeSet(map[string]config.ThemeColors{"mine": {Primary: "#123456"}})
+	m.themeName = "dark"
+
+	press := func() {
+		_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	}
+	press()
+	if m.themeName != "light" || m.theme.Primary != LightTheme().Primary {
+		t.Errorf("expected light theme, got %s", m.themeName)
+	}
+	press()
+	if m.themeName != "mine" || m.theme.Primary != "#123456" {
+		t.Errorf("expected user theme, got %s", m.themeName)
+	}
+	if !strings.HasPrefix(m.message, "Theme: mine (") {
+		t.Errorf("message: got %q", m.message)
+	}
+	press()
+	if m.themeName != "dark" {
+		t.Errorf("expected wrap to dark, got %s", m.themeName)
+	}
+}
@@ -0,0 +1,205 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// timelineLimit caps the entries kept per pane; older ones are dropped.
+const timelineLimit = 200
+
+// TimelineEntry is one event in a pane's timeline: a state transition, an
+// action sent to it, or that action's outcome.
+type TimelineEntry struct {
+	Time time.Time
+	// Kind is a TransitionKind, "nudge", or "outcome".
+	Kind   string
+	Reason string // the verdict reason for transitions
+	// Action is the label of the action sent, or whose outcome it is.
+	Action  string
+	Auto    bool   // sent by auto-nudge
+	Outcome string // the NudgeOutcome for outcomes
+}
+
+// state reports whether e starts a new state of the pane (blocked,
+// active, or gone), which lasts until the next one.
+func (e TimelineEntry) state() bool {
+	switch TransitionKind(e.Kind) {
+	case BecameBlocked, BecameActive, ReasonChanged, Disappeared:
+		return true
+	}
+	return false
+}
+
+// String describes e for the timeline view, e.g. "became blocked:
+// permission required" or "sent 'allow once' (auto)".
+func (e TimelineEntry) String() string {
+	switch e.Kind {
+	case "nudge":
+		s := fmt.Sprintf("sent '%s'", e.Action)
+		if e.Auto {
+			s += " (auto)"
+		}
+		return s
+	case "outcome":
+		return fmt.Sprintf("'%s': %s", e.Action, strings.ReplaceAll(e.Outcome, "_", " "))
+	}
+	if e.Reason != "" && TransitionKind(e.Kind) != Disappeared {
+		return fmt.Sprintf("%s: %s", transitionPhrase(TransitionKind(e.Kind)), e.Reason)
+	}
+	return transitionPhrase(TransitionKind(e.Kind))
+}
+
+// record appends entries to target's timeline. s.mu must be held.
+func (s *VerdictStore) record(target string, entries ...TimelineEntry) {
+	if s.timelines == nil {
+		s.timelines = make(map[string][]TimelineEntry)
+	}
+	tl := append(s.timelines[target], entries...)
+	if len(tl) > timelineLimit {
+		tl = append([]TimelineEntry(nil), tl[len(tl)-timelineLimit:]...)
+	}
+	s.timelines[target] = tl
+}
+
+// Note adds an action sent to a pane, or its outcome, to the pane's
+// timeline.
+func (s *VerdictStore) Note(target string, e TimelineEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record(target, e)
+}
+
+// Timeline returns target's timeline this session, oldest first. It
+// survives the pane disappearing, so an agent that exited can still be
+// looked into.
+func (s *VerdictStore) Timeline(target string) []TimelineEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TimelineEntry(nil), s.timelines[target]...)
+}
+
+// timelineView is the selected pane's timeline (opened with ctrl+t): its
+// transitions, the actions sent to it and their outcomes, to tell an agent
+// making progress from one thrashing between the same dialogs.
+type timelineView struct {
+	target string
+	name   string // the pane's name in the list when it was opened
+	offset int    // index of the first visible entry
+}
+
+// openTimeline opens the selected pane's timeline, scrolled to the end.
+func (m *tuiModel) openTimeline() {
+	v := m.selectedVerdict()
+	if v == nil {
+		m.message = "Select a pane to see its timeline"
+		return
+	}
+	m.timeline = &timelineView{target: v.Target, name: m.paneDisplayName(*v)}
+	m.timeline.offset = max(len(m.store.Timeline(v.Target))-m.timelineRows(), 0)
+	m.message = ""
+}
+
+// timelineRows is the number of entries the timeline view shows.
+func (m *tuiModel) timelineRows() int {
+	return max(m.height-5, 3)
+}
+
+// handleTimelineKey scrolls the timeline view.
+func (m *tuiModel) handleTimelineKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tv := m.timeline
+	rows := m.timelineRows()
+	maxOffset := max(len(m.store.Timeline(tv.target))-rows, 0)
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "ctrl+t":
+		m.timeline = nil
+	case "up", "k":
+		tv.offset = max(tv.offset-1, 0)
+	case "down", "j":
+		tv.offset = min(tv.offset+1, maxOffset)
+	case "pgup", "b":
+		tv.offset = max(tv.offset-rows, 0)
+	case "pgdown", " ":
+		tv.offset = min(tv.offset+rows, maxOffset)
+	case "home", "g":
+		tv.offset = 0
+	case "end", "G":
+		tv.offset = maxOffset
+	}
+	return m, nil
+}
+
+// viewTimeline renders the visible part of the timeline, each state with
+// how long it lasted, and a summary: how often the pane blocked, the
+// actions sent, and how many left it blocked.
+func (m *tuiModel) viewTimeline() string {
+	tv := m.timeline
+	entries := m.store.Timeline(tv.target)
+	var b strings.Builder
+	b.WriteString(m.s.title.Render("Timeline"))
+	b.WriteString("  ")
+	b.WriteString(m.s.dim.Render(tv.name))
+	b.WriteString("\n")
+
+	// Each state lasts until the next one, the last one until now.
+	lasted := make([]time.Duration, len(entries))
+	next := time.Now()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].state() {
+			lasted[i], next = next.Sub(entries[i].Time), entries[i].Time
+		}
+	}
+
+	var blocked, sent, stuck int
+	for _, e := range entries {
+		switch {
+		case TransitionKind(e.Kind) == BecameBlocked:
+			blocked++
+		case e.Kind == "nudge":
+			sent++
+		case e.Kind == "outcome" && e.Outcome == string(OutcomeStillBlocked):
+			stuck++
+		}
+	}
+
+	rows := m.timelineRows()
+	if len(entries) == 0 {
+		b.WriteString(m.s.dim.Render("  nothing happened to this pane yet"))
+		b.WriteString("\n")
+		rows--
+	}
+	end := min(tv.offset+rows, len(entries))
+	for i := tv.offset; i < end; i++ {
+		e := entries[i]
+		text := e.String()
+		if e.state() && TransitionKind(e.Kind) != Disappeared {
+			text += fmt.Sprintf(" (%s)", formatDuration(lasted[i]))
+		}
+		line := truncate(e.Time.Format("15:04:05")+"  "+text, max(m.width-4, 10))
+		switch {
+		case TransitionKind(e.Kind) == BecameBlocked, TransitionKind(e.Kind) == ReasonChanged:
+			line = m.s.blocked.Render(line)
+		case TransitionKind(e.Kind) == BecameActive:
+			line = m.s.active.Render(line)
+		case e.Outcome == string(OutcomeStillBlocked), e.Outcome == string(OutcomeFailed):
+			line = m.s.err.Render(line)
+		case e.Kind == "outcome", TransitionKind(e.Kind) == Disappeared:
+			line = m.s.dim.Render(line)
+		}
+		b.WriteString("  " + line + "\n")
+		rows--
+	}
+	b.WriteString(strings.Repeat("\n", max(rows, 0)))
+
+	b.WriteString(m.s.dim.Render(fmt.Sprintf("  blocked %d times · %d actions sent · %d left it blocked", blocked, sent, stuck)))
+	b.WriteString("\n")
+	b.WriteString(m.styleHints("  ↑↓ pgup pgdn scroll  g/G top/end  esc close  "))
+	b.WriteString(m.s.dim.Render(fmt.Sprintf("%d-%d of %d", min(tv.offset+1, len(entries)), end, len(entries))))
+	b.WriteString("\n")
+	return b.String()
+}
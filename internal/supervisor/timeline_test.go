@@ -0,0 +1,66 @@
+package supervisor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+func TestVerdictStore_Timeline(t *testing.T) {
+	m := newTestModel(blockedVerdict("api:0.0", "api", "permission required"))
+	s := &m.store
+	t0 := time.Now().Add(-10 * time.Minute)
+	blocked := blockedVerdict("api:0.0", "api", "permission required")
+	active := blocked
+	active.Blocked, active.Reason = false, "working"
+
+	s.Apply([]model.Verdict{blocked}, t0)
+	s.Note("api:0.0", TimelineEntry{Time: t0.Add(time.Minute), Kind: "nudge", Action: "allow once", Auto: true})
+	s.Apply([]model.Verdict{active}, t0.Add(2*time.Minute))
+	s.Apply([]model.Verdict{active}, t0.Add(3*time.Minute))
+	s.Apply([]model.Verdict{blocked}, t0.Add(5*time.Minute))
+	s.Note("api:0.0", TimelineEntry{Time: t0.Add(6 * time.Minute), Kind: "outcome", Action: "allow once", Outcome: string(OutcomeStillBlocked)})
+
+	var kinds []string
+	for _, e := range s.Timeline("api:0.0") {
+		kinds = append(kinds, e.Kind)
+	}
+	if got := strings.Join(kinds, " "); got != "became_blocked nudge became_active became_blocked outcome" {
+		t.Fatalf("timeline: got %s", got)
+	}
+
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m.timeline == nil {
+		t.Fatal("ctrl+t should open the timeline")
+	}
+	view := m.View()
+	for _, want := range []string{
+		"became blocked: permission required (2m)",
+		"sent 'allow once' (auto)",
+		"became active: working (3m)",
+		"'allow once': still blocked",
+		"blocked 2 times · 1 actions sent · 1 left it blocked",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the timeline:\n%s", want, view)
+		}
+	}
+	m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.timeline != nil {
+		t.Error("esc should close the timeline")
+	}
+}
+
+func TestVerdictStore_TimelineIsCapped(t *testing.T) {
+	var s VerdictStore
+	for i := range timelineLimit + 10 {
+		s.Note("api:0.0", TimelineEntry{Kind: "nudge", Action: string(rune('a' + i%26))})
+	}
+	tl := s.Timeline("api:0.0")
+	if len(tl) != timelineLimit || tl[len(tl)-1].Action != string(rune('a'+(timelineLimit+9)%26)) {
+		t.Errorf("expected the last %d entries, got %d", timelineLimit, len(tl))
+	}
+}
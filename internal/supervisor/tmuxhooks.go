@@ -0,0 +1,85 @@
+package supervisor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RescanHooks are the tmux hooks that make the supervisor rescan a pane as
+// soon as it may have changed: focused, split off, or created with its
+// window or session. pane-focus-in needs focus-events on.
+var RescanHooks = []string{"pane-focus-in", "after-split-window", "after-new-window", "session-created"}
+
+// rescanHookMarker identifies the hook commands pane-patrol installed, so
+// uninstalling leaves the user's own hooks alone.
+const rescanHookMarker = "control rescan"
+
+// TmuxRunner runs tmux with args and returns its output.
+type TmuxRunner func(args ...string) ([]byte, error)
+
+// rescanHookCommand is the command the hooks run: exe asks the supervisor
+// on its control socket to rescan the hook's pane, in the background and
+// silently, so tmux is not held up or cluttered when no supervisor runs.
+func rescanHookCommand(exe string) string {
+	shell := "'" + strings.ReplaceAll(exe, "'", `'\''`) + "' " + rescanHookMarker +
+		" '#{session_name}:#{window_index}.#{pane_index}' >/dev/null 2>&1 || true"
+	return "run-shell -b " + quoteTmux(shell)
+}
+
+// quoteTmux quotes s as one argument of a tmux command.
+func quoteTmux(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(s) + `"`
+}
+
+// InstallRescanHooks appends the rescan command to each of RescanHooks,
+// globally, after removing the ones installed before.
+func InstallRescanHooks(run TmuxRunner, exe string) error {
+	if err := UninstallRescanHooks(run); err != nil {
+		return err
+	}
+	command := rescanHookCommand(exe)
+	for _, hook := range RescanHooks {
+		if out, err := run("set-hook", "-ga", hook, command); err != nil {
+			return fmt.Errorf("tmux set-hook %s: %w (%s)", hook, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// hookLineRe matches a line of tmux show-hooks: "pane-focus-in[0] command".
+var hookLineRe = regexp.MustCompile(`^(\S+)\[(\d+)\]\s+(.*)$`)
+
+// InstalledRescanHooks returns the hooks ("pane-focus-in[1]") running the
+// rescan command, as listed by tmux show-hooks for sessions (-g) and
+// windows (-gw, where pane-focus-in lives).
+func InstalledRescanHooks(run TmuxRunner) ([]string, error) {
+	var hooks []string
+	for _, scope := range []string{"-g", "-gw"} {
+		out, err := run("show-hooks", scope)
+		if err != nil {
+			return nil, fmt.Errorf("tmux show-hooks %s: %w (%s)", scope, err, strings.TrimSpace(string(out)))
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			match := hookLineRe.FindStringSubmatch(strings.TrimSpace(line))
+			if match != nil && strings.Contains(match[3], rescanHookMarker) {
+				hooks = append(hooks, match[1]+"["+match[2]+"]")
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// UninstallRescanHooks removes the rescan command from the global hooks.
+func UninstallRescanHooks(run TmuxRunner) error {
+	hooks, err := InstalledRescanHooks(run)
+	if err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if out, err := run("set-hook", "-gu", hook); err != nil {
+			return fmt.Errorf("tmux set-hook -u %s: %w (%s)", hook, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// fakeHooks is a tmux server's global hooks for the hook installer.
+type fakeHooks struct {
+	hooks map[string][]string // hook -> commands by index, "" when unset
+}
+
+func (f *fakeHooks) run(args ...string) ([]byte, error) {
+	switch {
+	case args[0] == "show-hooks":
+		var b strings.Builder
+		for hook, commands := range f.hooks {
+			if (hook == "pane-focus-in") != (args[1] == "-gw") {
+				continue
+			}
+			for i, c := range commands {
+				if c != "" {
+					fmt.Fprintf(&b, "%s[%d] %s\n", hook, i, c)
+				}
+			}
+		}
+		return []byte(b.String()), nil
+	case args[0] == "set-hook" && args[1] == "-ga":
+		f.hooks[args[2]] = append(f.hooks[args[2]], args[3])
+		return nil, nil
+	case args[0] == "set-hook" && args[1] == "-gu":
+		var hook string
+		var i int
+		if _, err := fmt.Sscanf(strings.NewReplacer("[", " ", "]", "").Replace(args[2]), "%s %d", &hook, &i); err != nil {
+			return nil, err
+		}
+		f.hooks[hook][i] = ""
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected tmux %v", args)
+}
+
+func TestRescanHooks_InstallAndUninstall(t *testing.T) {
+	f := &fakeHooks{hooks: map[string][]string{"session-created": {"display-message hi"}}}
+	for range 2 {
+		if err := InstallRescanHooks(f.run, "/opt/pane patrol/pane-patrol"); err != nil {
+			t.Fatalf("install: %v", err)
+		}
+	}
+	installed, err := InstalledRescanHooks(f.run)
+	if err != nil || len(installed) != len(RescanHooks) {
+		t.Fatalf("installing twice should leave one hook each, got %v (%v)", installed, err)
+	}
+	commands := f.hooks["after-split-window"]
+	command := commands[len(commands)-1]
+	want := `run-shell -b "'/opt/pane patrol/pane-patrol' control rescan '#{session_name}:#{window_index}.#{pane_index}' >/dev/null 2>&1 || true"`
+	if command != want {
+		t.Errorf("hook command:\n got %s\nwant %s", command, want)
+	}
+
+	if err := UninstallRescanHooks(f.run); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if installed, _ := InstalledRescanHooks(f.run); len(installed) != 0 {
+		t.Errorf("left behind: %v", installed)
+	}
+	if f.hooks["session-created"][0] != "display-message hi" {
+		t.Errorf("the user's own hook was touched: %v", f.hooks["session-created"])
+	}
+}
+
+func TestRunControl_RescanPane(t *testing.T) {
+	tmux := &fakeTmux{}
+	p := tmux.addPane("app:0.0", "opencode", "working", map[string]string{
+		"working": opencodeWorking,
+		"idle":    "\n  Previous conversation output...\n\n  > \n",
+	})
+	e := newE2E(t, tmux)
+	stale := e.verdict("app:0.0")
+
+	// A hook rescans its pane on its own, while a full scan is in flight.
+	p.state = "idle"
+	e.m.scanning = true
+	answer, cmd := e.m.runControl("rescan app:0.0")
+	if answer != "ok" || cmd == nil {
+		t.Fatalf("rescan app:0.0: %q", answer)
+	}
+	e.run(cmd)
+	if v := e.verdict("app:0.0"); v.Reason != "idle at prompt" {
+		t.Fatalf("pane not rescanned: %s", v.Reason)
+	}
+
+	// Full rescans during the scan are coalesced into one after it, and
+	// the scan's result is applied, not thrown away, without undoing the
+	// pane's newer verdict.
+	for range 3 {
+		if answer, cmd := e.m.runControl("rescan"); cmd != nil || !strings.Contains(answer, "queued") {
+			t.Fatalf("rescan during a scan: %q", answer)
+		}
+	}
+	other := blockedVerdict("api:0.0", "api", "permission required")
+	_, cmd = e.m.Update(scanResultMsg{result: &ScanResult{Verdicts: []model.Verdict{other, stale}}})
+	if e.m.scanCount != 2 || len(e.m.verdicts) != 2 {
+		t.Fatalf("scan result not applied: %d scans, %+v", e.m.scanCount, e.m.verdicts)
+	}
+	if v := e.verdict("app:0.0"); v.Reason != "idle at prompt" {
+		t.Errorf("the scan in flight undid the pane's rescan: %s", v.Reason)
+	}
+	if cmd == nil || !e.m.scanning || e.m.scanAgain {
+		t.Errorf("queued rescan not started (scanning %v, again %v)", e.m.scanning, e.m.scanAgain)
+	}
+}
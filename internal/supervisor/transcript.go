@@ -0,0 +1,95 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// defaultPager opens transcripts when $PAGER is not set.
+const defaultPager = "less"
+
+// transcriptMsg reports a transcript written with H.
+type transcriptMsg struct {
+	target string
+	path   string
+	err    error
+}
+
+// pagerDoneMsg is sent when the pager showing a transcript exits.
+type pagerDoneMsg struct {
+	err error
+}
+
+// WriteTranscript writes the full content of pane target to the export
+// directory dir (see ExportVerdicts) as
+// pane-patrol-transcript-<target>-<timestamp>.txt, only readable by the
+// owner, and returns its path.
+func WriteTranscript(dir, target, content string, now time.Time) (string, error) {
+	dir, err := makeExportDir(dir)
+	if err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("pane-patrol-transcript-%s-%s.txt", fileSafe(target), now.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// fileSafe replaces the characters of a pane target that do not belong in
+// a file name, e.g. "api/dev:0.1" becomes "api-dev-0.1".
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '-'
+	}, s)
+}
+
+// transcriptCmd captures target's entire scrollback, not just the screen
+// the parser saw, and writes it to the export directory.
+func transcriptCmd(ctx context.Context, dir, target string) tea.Cmd {
+	return func() tea.Msg {
+		content, err := mux.NewTmux().CapturePaneTranscript(ctx, target)
+		if err != nil {
+			return transcriptMsg{target: target, err: err}
+		}
+		path, err := WriteTranscript(dir, target, content, time.Now())
+		return transcriptMsg{target: target, path: path, err: err}
+	}
+}
+
+// transcriptPager returns the command transcripts are opened with.
+func transcriptPager() string {
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return pager
+	}
+	return defaultPager
+}
+
+// handleTranscript reports a written transcript and opens it in the pager.
+func (m *tuiModel) handleTranscript(msg transcriptMsg) tea.Cmd {
+	if msg.err != nil {
+		m.logger().Warn("transcript failed", "target", msg.target, "err", msg.err)
+		m.message = fmt.Sprintf("Transcript of %s failed: %v", msg.target, msg.err)
+		return nil
+	}
+	m.message = fmt.Sprintf("Transcript of %s written to %s", msg.target, msg.path)
+	args := strings.Fields(m.pager)
+	if len(args) == 0 {
+		return nil
+	}
+	pager := exec.Command(args[0], append(args[1:], msg.path)...)
+	return tea.ExecProcess(pager, func(err error) tea.Msg { return pagerDoneMsg{err: err} })
+}
@@ -0,0 +1,53 @@
+package supervisor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteTranscript(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "exports")
+	now := time.Date(2026, 3, 1, 14, 5, 9, 0, time.UTC)
+	path, err := WriteTranscript(dir, "api/dev:0.1", "line 1\nline 2\n", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "pane-patrol-transcript-api-dev-0.1-20260301-140509.txt"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "line 1\nline 2\n" {
+		t.Errorf("content = %q, %v", data, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected a file only the owner can read, got %v", info.Mode())
+	}
+}
+
+func TestHandleTranscript(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+
+	if cmd := m.handleTranscript(transcriptMsg{target: "test:0.0", err: errors.New("can't find pane")}); cmd != nil {
+		t.Error("expected no pager for a failed transcript")
+	}
+	if !strings.Contains(m.message, "Transcript of test:0.0 failed: can't find pane") {
+		t.Errorf("message = %q", m.message)
+	}
+
+	// Without a pager the transcript is only written.
+	if cmd := m.handleTranscript(transcriptMsg{target: "test:0.0", path: "/tmp/t.txt"}); cmd != nil {
+		t.Error("expected no pager")
+	}
+	if m.message != "Transcript of test:0.0 written to /tmp/t.txt" {
+		t.Errorf("message = %q", m.message)
+	}
+
+	m.pager = "less -R"
+	if cmd := m.handleTranscript(transcriptMsg{target: "test:0.0", path: "/tmp/t.txt"}); cmd == nil {
+		t.Error("expected the transcript to be opened in the pager")
+	}
+}
@@ -0,0 +1,201 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// hookTimeout bounds one run of transition_command or an escalation
+// command, so a hung command does not pile up behind every scan.
+const hookTimeout = 30 * time.Second
+
+// TransitionKind is the way a pane's state changed between two scans.
+type TransitionKind string
+
+const (
+	BecameBlocked TransitionKind = "became_blocked" // new pane that is blocked, or an active pane that blocked
+	BecameActive  TransitionKind = "became_active"  // blocked pane that is working again
+	ReasonChanged TransitionKind = "reason_changed" // still blocked, on a different dialog
+	Disappeared   TransitionKind = "disappeared"    // pane closed, or no longer an agent
+)
+
+// Transition is one pane's state change. Verdict is the pane's latest
+// verdict, or its last one for Disappeared; PrevReason is the reason it was
+// blocked on before, if any.
+type Transition struct {
+	Kind       TransitionKind `json:"kind"`
+	Time       time.Time      `json:"time"`
+	Target     string         `json:"target"`
+	PrevReason string         `json:"prev_reason,omitempty"`
+	Verdict    model.Verdict  `json:"verdict"`
+}
+
+// String describes t for the status line, e.g. "api:0.1 (feature/auth)
+// became blocked: permission required".
+func (t Transition) String() string {
+	pane := t.Target
+	if t.Verdict.GitBranch != "" {
+		pane += " (" + truncate(t.Verdict.GitBranch, maxBranchLen) + ")"
+	}
+	switch t.Kind {
+	case BecameBlocked, ReasonChanged:
+		return fmt.Sprintf("%s %s: %s", pane, transitionPhrase(t.Kind), t.Verdict.Reason)
+	default:
+		return fmt.Sprintf("%s %s", pane, transitionPhrase(t.Kind))
+	}
+}
+
+func transitionPhrase(k TransitionKind) string {
+	return strings.ReplaceAll(string(k), "_", " ")
+}
+
+// VerdictStore holds the latest verdict per agent pane and turns each scan
+// into the transitions since the previous one, so the status line, speech,
+// history, and the transition hook all react to the same events. Panes
+// without an agent (shells, evaluation errors) are not tracked. It also
+// keeps each blocked pane's escalation state (see Escalate) and each pane's
+// timeline (see Timeline). The zero value is ready to use; it is safe for
+// concurrent use.
+type VerdictStore struct {
+	mu          sync.Mutex
+	panes       map[string]model.Verdict
+	escalations map[string]*escalationState // by target, for blocked panes
+	timelines   map[string][]TimelineEntry  // by target
+}
+
+// Apply records verdicts as the current state and returns the transitions
+// from the previous call, sorted by target. On the first call every blocked
+// pane is BecameBlocked.
+func (s *VerdictStore) Apply(verdicts []model.Verdict, now time.Time) []Transition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make(map[string]model.Verdict, len(verdicts))
+	var ts []Transition
+	for _, v := range verdicts {
+		if !tracked(v) {
+			continue
+		}
+		current[v.Target] = v
+		prev, seen := s.panes[v.Target]
+		switch {
+		case v.Blocked && (!seen || !prev.Blocked):
+			ts = append(ts, Transition{Kind: BecameBlocked, Time: now, Target: v.Target, Verdict: v})
+		case v.Blocked && prev.Reason != v.Reason:
+			ts = append(ts, Transition{Kind: ReasonChanged, Time: now, Target: v.Target, PrevReason: prev.Reason, Verdict: v})
+		case !v.Blocked && seen && prev.Blocked:
+			ts = append(ts, Transition{Kind: BecameActive, Time: now, Target: v.Target, PrevReason: prev.Reason, Verdict: v})
+		}
+	}
+	for target, prev := range s.panes {
+		if _, ok := current[target]; !ok {
+			t := Transition{Kind: Disappeared, Time: now, Target: target, Verdict: prev}
+			if prev.Blocked {
+				t.PrevReason = prev.Reason
+			}
+			ts = append(ts, t)
+		}
+	}
+	s.panes = current
+	s.trackBlocked(ts, now)
+	for _, t := range ts {
+		s.record(t.Target, TimelineEntry{Time: now, Kind: string(t.Kind), Reason: t.Verdict.Reason})
+	}
+
+	sort.Slice(ts, func(i, j int) bool { return ts[i].Target < ts[j].Target })
+	return ts
+}
+
+// tracked reports whether the store follows v's pane: agents only, not
+// shell prompts (see parser.IsAgentBlock), which never notify or escalate.
+func tracked(v model.Verdict) bool {
+	switch v.Agent {
+	case "", "unknown", "error", "not_an_agent", parser.AgentGenericPrompt:
+		return false
+	}
+	return true
+}
+
+// summarizeTransitions describes a scan's transitions in one status line:
+// the transition itself when there is one, otherwise counts per kind.
+func summarizeTransitions(ts []Transition) string {
+	switch len(ts) {
+	case 0:
+		return ""
+	case 1:
+		return ts[0].String()
+	}
+	counts := make(map[TransitionKind]int)
+	for _, t := range ts {
+		counts[t.Kind]++
+	}
+	var parts []string
+	for _, k := range []TransitionKind{BecameBlocked, ReasonChanged, BecameActive, Disappeared} {
+		if counts[k] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[k], transitionPhrase(k)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// historyEntry describes t for the history log.
+func (t Transition) historyEntry() HistoryEntry {
+	return HistoryEntry{
+		Time:    t.Time,
+		Target:  t.Target,
+		Agent:   t.Verdict.Agent,
+		Event:   string(t.Kind),
+		Summary: t.Verdict.Reason,
+	}
+}
+
+// TransitionHook runs a shell command for every transition, e.g. to send a
+// desktop notification or post to a chat. The command receives the
+// transition as JSON on stdin and its kind and target in PANE_PATROL_EVENT
+// and PANE_PATROL_TARGET. A nil *TransitionHook runs nothing.
+type TransitionHook struct {
+	Command string
+}
+
+// NewTransitionHook returns a hook running command, or nil if it is empty.
+func NewTransitionHook(command string) *TransitionHook {
+	if command == "" {
+		return nil
+	}
+	return &TransitionHook{Command: command}
+}
+
+// Run runs the command once per transition, in order, each for at most
+// hookTimeout. All transitions are attempted; the first error is returned.
+func (h *TransitionHook) Run(ctx context.Context, ts []Transition) error {
+	if h == nil {
+		return nil
+	}
+	var first error
+	for _, t := range ts {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		cmdCtx, cancel := context.WithTimeout(ctx, hookTimeout)
+		cmd := exec.CommandContext(cmdCtx, "sh", "-c", h.Command)
+		cmd.Stdin = strings.NewReader(string(data) + "\n")
+		cmd.Env = append(os.Environ(), "PANE_PATROL_EVENT="+string(t.Kind), "PANE_PATROL_TARGET="+t.Target)
+		out, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil && first == nil {
+			first = fmt.Errorf("transition command failed for %s: %w (output: %s)", t.Target, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return first
+}
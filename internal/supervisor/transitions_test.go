@@ -0,0 +1,109 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+func kinds(ts []Transition) []string {
+	var out []string
+	for _, t := range ts {
+		out = append(out, t.Target+"="+string(t.Kind))
+	}
+	return out
+}
+
+func TestVerdictStore_Transitions(t *testing.T) {
+	var store VerdictStore
+	now := time.Now()
+	blocked := blockedVerdict("api:0.0", "api", "permission required")
+	active := model.Verdict{Target: "web:0.0", Session: "web", Agent: "codex"}
+	shell := model.Verdict{Target: "sh:0.0", Session: "sh", Agent: "not_an_agent"}
+	// A shell waiting on a password is not an agent block: no hook runs and
+	// no escalation starts.
+	sudo := model.Verdict{Target: "sh:0.1", Session: "sh", Pane: 1, Agent: parser.AgentGenericPrompt, Blocked: true, Reason: "password prompt"}
+
+	if got := kinds(store.Apply([]model.Verdict{blocked, active, shell, sudo}, now)); strings.Join(got, " ") != "api:0.0=became_blocked" {
+		t.Fatalf("first scan: %v", got)
+	}
+	if got := store.Apply([]model.Verdict{blocked, active}, now); len(got) != 0 {
+		t.Fatalf("unchanged scan: %v", kinds(got))
+	}
+
+	changed := blocked
+	changed.Reason = "edit approval"
+	webBlocked := active
+	webBlocked.Blocked = true
+	ts := store.Apply([]model.Verdict{changed, webBlocked}, now)
+	if got := strings.Join(kinds(ts), " "); got != "api:0.0=reason_changed web:0.0=became_blocked" {
+		t.Fatalf("second scan: %s", got)
+	}
+	if ts[0].PrevReason != "permission required" || ts[0].Verdict.Reason != "edit approval" {
+		t.Errorf("reason_changed: %+v", ts[0])
+	}
+
+	ts = store.Apply([]model.Verdict{active}, now)
+	if got := strings.Join(kinds(ts), " "); got != "api:0.0=disappeared web:0.0=became_active" {
+		t.Fatalf("third scan: %s", got)
+	}
+	if summary := summarizeTransitions(ts); summary != "1 became active, 1 disappeared" {
+		t.Errorf("summary: %q", summary)
+	}
+}
+
+func TestTransitionHook_ReceivesJSONAndEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "events")
+	hook := NewTransitionHook(`{ printf '%s %s ' "$PANE_PATROL_EVENT" "$PANE_PATROL_TARGET"; cat; } >> ` + out)
+	ts := []Transition{{Kind: BecameBlocked, Target: "api:0.0", Verdict: blockedVerdict("api:0.0", "api", "permission required")}}
+	if err := hook.Run(context.Background(), ts); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix, payload, _ := strings.Cut(string(data), " {")
+	if prefix != "became_blocked api:0.0" {
+		t.Errorf("env: got %q", prefix)
+	}
+	var got Transition
+	if err := json.Unmarshal([]byte("{"+payload), &got); err != nil || got.Verdict.Reason != "permission required" {
+		t.Errorf("stdin: %q (%v)", payload, err)
+	}
+
+	if NewTransitionHook("") != nil {
+		t.Error("empty command should disable the hook")
+	}
+	if err := (*TransitionHook)(nil).Run(context.Background(), ts); err != nil {
+		t.Errorf("nil hook: %v", err)
+	}
+}
+
+func TestTransitionCmd_RecordsHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	m := newTestModel(simpleVerdict())
+	m.history = NewHistory(path, nil)
+	ts := m.store.Apply(m.verdicts, time.Now())
+	if msg := m.transitionCmd(ts)(); msg.(transitionResultMsg).err != nil {
+		t.Fatal(msg)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e HistoryEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Event != "became_blocked" || e.Target != "test:0.0" || e.Action != "" {
+		t.Errorf("history entry: %+v", e)
+	}
+}
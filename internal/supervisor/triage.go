@@ -0,0 +1,123 @@
+package supervisor
+
+import (
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// listedVerdicts returns the indices of the verdicts listed, in list
+// order, including those of collapsed sessions and windows.
+func (m *tuiModel) listedVerdicts() []int {
+	var listed []int
+	for _, g := range m.groups {
+		if len(g.windows) == 0 {
+			listed = append(listed, g.verdicts...)
+			continue
+		}
+		for _, w := range g.windows {
+			listed = append(listed, w.verdicts...)
+		}
+	}
+	return listed
+}
+
+// blockedAfter returns the index of the blocked agent (see
+// parser.IsAgentBlock) listed after (dir 1) or before (dir -1) the verdict
+// at index from, wrapping around; from -1 starts at the top or bottom of
+// the list. Shell prompts are passed over, so ctrl+a never answers a
+// password or [y/N] prompt. It returns -1 when no other pane is blocked.
+func (m *tuiModel) blockedAfter(from, dir int) int {
+	listed := m.listedVerdicts()
+	pos := -1
+	for i, vi := range listed {
+		if vi == from {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 && dir < 0 {
+		pos = len(listed)
+	}
+	n := len(listed)
+	for step := 1; step <= n; step++ {
+		i := ((pos+dir*step)%n + n) % n
+		if vi := listed[i]; vi != from && parser.IsAgentBlock(m.verdicts[vi]) {
+			return vi
+		}
+	}
+	return -1
+}
+
+// selectVerdict moves the cursor to the verdict at index vi, expanding its
+// session and window when they are collapsed.
+func (m *tuiModel) selectVerdict(vi int) {
+	v := m.verdicts[vi]
+	for _, g := range m.groups {
+		if !slices.Contains(g.verdicts, vi) {
+			continue
+		}
+		m.expanded[g.name] = true
+		delete(m.manualCollapsed, g.name)
+		if len(g.windows) > 0 {
+			key := windowKey(g.name, v.Window)
+			m.expanded[key] = true
+			delete(m.manualCollapsed, key)
+		}
+	}
+	m.rebuildItems()
+	m.restoreCursorByKey(v.Target)
+}
+
+// selectedPaneIndex returns the verdict index of the selected pane, or -1
+// when a header or nothing is selected.
+func (m *tuiModel) selectedPaneIndex() int {
+	if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].kind != itemPane {
+		return -1
+	}
+	return m.items[m.cursor].paneIdx
+}
+
+// jumpBlocked selects the next (], dir 1) or previous ([, dir -1)
+// blocked pane in the list.
+func (m *tuiModel) jumpBlocked(dir int) {
+	from := m.selectedPaneIndex()
+	vi := m.blockedAfter(from, dir)
+	if vi < 0 {
+		if from >= 0 && parser.IsAgentBlock(m.verdicts[from]) {
+			m.message = "No other blocked pane"
+		} else {
+			m.message = "No blocked panes"
+		}
+		return
+	}
+	m.selectVerdict(vi)
+}
+
+// approveAndAdvance sends the recommended action to the selected pane, or
+// to the next blocked pane when the selected one is not blocked, and moves
+// on to the blocked pane after it (ctrl+a), so a queue of approvals can be
+// worked through with one key. High-risk actions are still confirmed when
+// confirm_high_risk is set.
+func (m *tuiModel) approveAndAdvance() tea.Cmd {
+	target := m.selectedPaneIndex()
+	if target < 0 || !parser.IsAgentBlock(m.verdicts[target]) {
+		if target = m.blockedAfter(target, 1); target < 0 {
+			m.message = "No blocked panes"
+			return nil
+		}
+	}
+	v := m.verdicts[target]
+	if next := m.blockedAfter(target, 1); next >= 0 {
+		m.selectVerdict(next)
+	} else {
+		m.selectVerdict(target)
+	}
+	if len(v.Actions) == 0 || v.Recommended < 0 || v.Recommended >= len(v.Actions) {
+		m.message = fmt.Sprintf("%s has no recommended action; skipped", v.Target)
+		return nil
+	}
+	return m.sendActionCmd(v.Target, v.Actions[v.Recommended])
+}
@@ -0,0 +1,120 @@
+package supervisor
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+func triageTestModel(sent *[]string) *tuiModel {
+	approve := []model.Action{{Keys: "y", Label: "approve", Risk: "low"}}
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "a:0.0", Session: "a", Agent: "opencode", Blocked: true, Actions: approve},
+			{Target: "a:0.1", Session: "a", Pane: 1, Agent: "opencode"},
+			{Target: "b:0.0", Session: "b", Agent: "opencode", Blocked: true, Actions: approve},
+			{Target: "c:0.0", Session: "c", Agent: "opencode", Blocked: true, Actions: approve},
+		},
+		filter:          filterAll,
+		scanner:         &Scanner{},
+		expanded:        map[string]bool{},
+		manualCollapsed: make(map[string]bool),
+		width:           120,
+		height:          40,
+		nudger: &Nudger{SendKeys: func(target, _, _ string) error {
+			*sent = append(*sent, target)
+			return nil
+		}, Sleep: func(time.Duration) {}},
+	}
+	m.rebuildGroups()
+	return m
+}
+
+func TestJumpBlocked_WrapsAndExpands(t *testing.T) {
+	m := triageTestModel(new([]string))
+	m.restoreCursorByKey("a:0.1")
+
+	// b is collapsed by hand; ] still gets there and expands it.
+	m.expanded["b"] = false
+	m.manualCollapsed["b"] = true
+	m.rebuildItems()
+	m.restoreCursorByKey("a:0.1")
+
+	key := func(k string) { m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}) }
+	key("]")
+	if got := m.selectedItemKey(); got != "b:0.0" || !m.expanded["b"] {
+		t.Errorf("] moved to %s (b expanded=%v), want b:0.0", got, m.expanded["b"])
+	}
+	key("]")
+	key("]")
+	if got := m.selectedItemKey(); got != "a:0.0" {
+		t.Errorf("] should wrap around to a:0.0, got %s", got)
+	}
+	key("[")
+	if got := m.selectedItemKey(); got != "c:0.0" {
+		t.Errorf("[ should wrap around to c:0.0, got %s", got)
+	}
+
+	for i := range m.verdicts {
+		m.verdicts[i].Blocked = false
+	}
+	key("]")
+	if m.message != "No blocked panes" {
+		t.Errorf("message = %q", m.message)
+	}
+}
+
+func TestApproveAndAdvance(t *testing.T) {
+	var sent []string
+	m := triageTestModel(&sent)
+	m.restoreCursorByKey("a:0.1")
+
+	// From a pane that is not blocked, the next blocked pane is approved.
+	for _, want := range []string{"b:0.0", "c:0.0", "a:0.0"} {
+		_, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyCtrlA})
+		if cmd == nil {
+			t.Fatalf("expected keys sent to %s", want)
+		}
+		cmd()
+		if got := sent[len(sent)-1]; got != want {
+			t.Errorf("approved %s, want %s", got, want)
+		}
+	}
+	if !slices.Equal(slices.Compact(sent), []string{"b:0.0", "c:0.0", "a:0.0"}) {
+		t.Errorf("sent to %v", sent)
+	}
+	if got := m.selectedItemKey(); got != "b:0.0" {
+		t.Errorf("cursor on %s, want the next blocked pane b:0.0", got)
+	}
+
+	// A shell prompt is neither approved nor stopped at.
+	m.verdicts[1] = model.Verdict{Target: "a:0.1", Session: "a", Pane: 1, Agent: parser.AgentGenericPrompt, Blocked: true,
+		Actions: []model.Action{{Keys: "n Enter", Label: "decline", Risk: "low"}}, Recommended: 0}
+	m.restoreCursorByKey("a:0.1")
+	_, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyCtrlA})
+	if cmd == nil {
+		t.Fatal("expected keys sent to b:0.0")
+	}
+	cmd()
+	if got := sent[len(sent)-1]; got != "b:0.0" {
+		t.Errorf("approved %s, want b:0.0", got)
+	}
+	m.restoreCursorByKey("a:0.0")
+	m.jumpBlocked(1)
+	if got := m.selectedItemKey(); got != "b:0.0" {
+		t.Errorf("] moved to %s, want b:0.0 past the shell prompt", got)
+	}
+
+	// A pane without a recommended action is skipped.
+	m.verdicts[2].Actions = nil
+	if _, cmd := m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyCtrlA}); cmd != nil {
+		t.Error("expected nothing sent")
+	}
+	if m.message != "b:0.0 has no recommended action; skipped" || m.selectedItemKey() != "c:0.0" {
+		t.Errorf("message = %q, cursor on %s", m.message, m.selectedItemKey())
+	}
+}
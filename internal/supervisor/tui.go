@@ -2,14 +2,24 @@ package supervisor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os/exec"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/timvw/pane-patrol/internal/config"
+	"github.com/timvw/pane-patrol/internal/launch"
 	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+	"github.com/timvw/pane-patrol/internal/parser"
+	"github.com/timvw/pane-patrol/internal/summary"
 )
 
 // Styles are stored in tuiModel.s (built from the configurable Theme).
@@ -22,6 +32,7 @@ const (
 	filterBlocked displayFilter = iota // only blocked agents
 	filterAgents                       // all agent panes (blocked + active)
 	filterAll                          // everything including non-agents
+	filterIdle                         // agents idle at their prompt for idle_after
 )
 
 func (f displayFilter) String() string {
@@ -32,27 +43,37 @@ func (f displayFilter) String() string {
 		return "agents"
 	case filterAll:
 		return "all"
+	case filterIdle:
+		return "idle"
 	default:
 		return "?"
 	}
 }
 
-func (f displayFilter) next() displayFilter {
-	return (f + 1) % 3
+// next returns the filter after f; idle is only offered when idle_after
+// is set.
+func (f displayFilter) next(idle bool) displayFilter {
+	if f == filterAll && !idle {
+		return filterBlocked
+	}
+	return (f + 1) % 4
 }
 
 // listItem represents a row in the grouped verdict list.
-// It is either a session header or an individual pane.
+// It is either a session header, a window header, or an individual pane.
 type listItem struct {
-	kind    itemKind
-	session string
-	paneIdx int // index into verdicts slice (only for itemPane)
+	kind     itemKind
+	session  string
+	window   int  // window index (only for itemWindow)
+	paneIdx  int  // index into verdicts slice (only for itemPane)
+	inWindow bool // pane listed under a window header
 }
 
 type itemKind int
 
 const (
 	itemSession itemKind = iota
+	itemWindow
 	itemPane
 )
 
@@ -62,34 +83,118 @@ type sessionGroup struct {
 	verdicts []int // indices into the flat verdicts slice
 	blocked  int
 	active   int
+	windows  []windowGroup // set when the panes span several windows (see splitWindows)
+}
+
+// add adds verdict v, at index i of the verdicts slice, to g.
+func (g *sessionGroup) add(i int, v model.Verdict) {
+	g.verdicts = append(g.verdicts, i)
+	if parser.IsAgentBlock(v) {
+		g.blocked++
+	}
+	if v.Agent != "error" && v.Agent != "not_an_agent" && !v.Blocked {
+		g.active++
+	}
 }
 
 // messages
 type scanResultMsg struct {
-	result *ScanResult
-	err    error
+	result   *ScanResult
+	err      error
+	duration time.Duration // wall time of the scan
 }
 
 type tickMsg struct{}
 
+// speechResultMsg is sent when async speech announcements complete.
+type speechResultMsg struct {
+	err error
+}
+
+// badgeResultMsg is sent when tmux badges have been updated.
+type badgeResultMsg struct {
+	err error
+}
+
+// launchResultMsg is sent when a template launch completes.
+type launchResultMsg struct {
+	launched []launch.Launched
+	err      error
+}
+
+// transitionResultMsg reports logging transitions and running the
+// transition hook.
+type transitionResultMsg struct {
+	err error
+}
+
 // nudgeResultMsg is sent when async auto-nudge completes.
 type nudgeResultMsg struct {
 	messages []string // status messages describing what was sent
+	sent     int      // panes that received keys, for the session stats
+	auto     bool     // sent by auto-nudge
+	// answer is the action sent by hand to a rememberable dialog, offered
+	// for remembering with R once it was sent.
+	answer *lastAnswer
+	// nudges are what was sent, for the audit log; the outcomes of
+	// actions are then tracked.
+	nudges []nudgeTask
+	// changed is set when a dialog changed before the keys were sent; the
+	// panes are rescanned to show it.
+	changed bool
 }
 
 // TUI runs the interactive supervisor.
 type TUI struct {
 	Scanner          *Scanner
-	RefreshInterval  time.Duration // 0 disables auto-refresh
-	AutoNudge        bool          // Enable automatic nudging of blocked panes
-	AutoNudgeMaxRisk string        // Maximum risk level to auto-nudge: "low", "medium", "high"
-	ThemeName        string        // "dark" (default) or "light"
+	RefreshInterval  time.Duration                 // 0 disables auto-refresh
+	AutoNudge        bool                          // Enable automatic nudging of blocked panes
+	AutoNudgeMaxRisk string                        // Maximum risk level to auto-nudge: "low", "medium", "high"
+	AutoNudgeViewed  bool                          // Also auto-nudge panes shown in an attached tmux client, e.g. when nobody types into them
+	Instance         *Instance                     // Coordinates auto-nudge with other supervisors on the tmux server; nil nudges regardless
+	ThemeName        string                        // "dark" (default), "light", or a name from Themes
+	Themes           map[string]config.ThemeColors // User-defined themes, switchable with T
+	ColorProfile     string                        // "truecolor", "256", "16", "none"; "" or "auto" detects from the terminal
+	Announcer        *Announcer                    // Speaks newly blocked panes; nil disables speech
+	ShowWaitingFor   bool                          // Show the first line of WaitingFor under blocked pane rows
+	ShowRecommended  bool                          // Show the recommended action after the reason of blocked pane rows
+	TemplateDir      string                        // Directory of launch templates offered by L; "" uses launch.DefaultDir
+	Snippets         []config.Snippet              // Canned answers offered by ctrl+p in the text input
+	Continuations    []config.Snippet              // Prompts offered by o for agents idle at their prompt
+	IdleAfter        time.Duration                 // Agents idle at their prompt this long are reported and shown by the idle filter; 0 disables
+	Tasks            *TaskSource                   // Supplies the next task for an idle agent (O); nil disables it
+	Layout           string                        // Action panel placement: "bottom" (default) or "right"
+	ActionOrder      string                        // Panel action order: "dialog" (default), "risk", or "grouped"
+	ClickAction      string                        // Single click on a pane: "jump" (default) or "select"
+	GroupBy          string                        // List grouping: "session" (default), "directory", or "repo"
+	Labels           *Labels                       // Friendly pane/session names edited with n; nil disables renaming
+	SyncPaneTitles   bool                          // Also set pane labels as tmux pane titles
+	ExportDir        string                        // Directory for exports written with e; "" is the current directory
+	ConfirmHighRisk  bool                          // Require typing "yes" or a reason before sending a high-risk action
+	History          *History                      // Log of actions sent to panes; nil disables it
+	AnswerMemory     *AnswerMemory                 // Remembered answers to recurring dialogs (R, M); nil disables them
+	Badges           *Badges                       // Marks blocked panes in tmux; nil disables badges
+	TransitionHook   *TransitionHook               // Runs a command per pane state transition; nil disables it
+	Escalator        *Escalator                    // Notifies again about panes that stay blocked; nil disables it
+	Audit            *AuditLog                     // Tamper-evident log of verdicts and actions; nil disables it
+	QuietHours       config.QuietHours             // Do-not-disturb schedule for notifications and auto-nudge
+	Profiles         []Profile                     // Config profiles switchable with P; empty disables switching
+	Profile          string                        // Name of the profile the settings above come from
+	Accessible       bool                          // Screen-reader friendly rendering: ASCII state words, no box drawing or colors
+	Inline           int                           // Render in this many lines below the prompt instead of the alternate screen; 0 uses the alternate screen
+	Reload           ReloadFunc                    // Loads the config again on SIGHUP or when ConfigFile changes; nil disables reloading
+	ConfigFile       string                        // Config file watched for changes; "" reloads on SIGHUP only
+	Control          *ControlSocket                // Takes commands from scripts and tmux key bindings; nil disables it
+	Log              *slog.Logger                  // Debug log of scans, nudges, and errors; nil discards
+	LogFile          string                        // File Log writes to, shown by E; "" disables the log view
 }
 
 // model implements tea.Model
 type tuiModel struct {
-	theme Theme
-	s     styles // derived from theme
+	theme     Theme
+	s         styles // derived from theme
+	themeName string
+	themes    themeSet // themes cycled with T
 
 	scanner         *Scanner
 	ctx             context.Context
@@ -98,7 +203,13 @@ type tuiModel struct {
 	cursor          int
 
 	// display filter
-	filter displayFilter
+	filter      displayFilter
+	modelFilter string // only show panes using this model; "" shows all
+
+	// groupBy groups the list by session, directory, or repository (cycle
+	// with g); repoRoots memoizes repository roots by directory.
+	groupBy   groupMode
+	repoRoots map[string]string
 
 	// grouped list
 	groups          []sessionGroup
@@ -109,6 +220,110 @@ type tuiModel struct {
 	// layout (computed in viewVerdictList, used for mouse hit testing)
 	listStart int // scroll offset for list (for mouse hit testing)
 
+	// clickSelects makes a single click on a pane only select it; a double
+	// click or alt/ctrl-click jumps. lastClick is the previous click, to
+	// detect double clicks.
+	clickSelects bool
+	lastClick    paneClick
+
+	// layout places the action panel below (default) or right of the list
+	// (toggle with v). actionPanelY is the screen row of the separator above
+	// the panel in the bottom layout, actionPanelX the panel's first column
+	// in the right layout (0 when not in use); panelActionRow is the panel
+	// line of the first action listed, or -1. actionOrderMode is
+	// action_order (see actionOrder).
+	layout          panelLayout
+	actionPanelY    int
+	actionPanelX    int
+	panelActionRow  int
+	actionOrderMode string
+
+	// showWaitingFor adds a dimmed WaitingFor preview line under blocked
+	// pane rows (toggle with w). Rows then span one or two screen lines.
+	showWaitingFor bool
+
+	// showRecommended appends the recommended action's number, label, and
+	// risk to blocked pane rows (toggle with s), so 1-9 can be pressed
+	// without reading the action panel.
+	showRecommended bool
+
+	// showClusters shows a sidebar grouping visible blocked panes by what
+	// they are waiting on (toggle with c).
+	showClusters bool
+
+	// answering is the group of identical dialogs being answered at once
+	// (opened with A), nil otherwise. answerChoice is the picked action
+	// index awaiting y/n confirmation, or -1 while choosing.
+	answering    *answerGroup
+	answerChoice int
+
+	// templates is the launch template picker (opened with L), nil when
+	// closed; templateCursor is the highlighted entry.
+	templateDir    string
+	templates      []templateEntry
+	templateCursor int
+
+	// control is the interrupt/kill/restart view for one pane (opened
+	// with X), nil when closed.
+	control *paneControl
+
+	// rawKeys is the raw key mode for one pane (opened with K), nil when
+	// closed.
+	rawKeys *rawKeyInput
+
+	// textInput is the free-form answer being typed (opened with t), nil
+	// when closed. snippets are the canned answers it offers, and
+	// continuations the prompts it offers for idle agents (o).
+	textInput     *textInput
+	snippets      []config.Snippet
+	continuations []config.Snippet
+	// idleAfter is how long an agent idles at its prompt before it is
+	// reported and shown by the idle filter (0: never); tasks supplies
+	// their next task (O).
+	idleAfter time.Duration
+	tasks     *TaskSource
+
+	// diff is the edit review viewer (opened with d), nil when closed.
+	diff *diffView
+
+	// attach forwards keys to one pane while showing it live (opened with
+	// i), nil when detached. attachSeq numbers attaches so captures of an
+	// earlier one are dropped.
+	attach    *attachView
+	attachSeq int
+
+	// labels are the friendly pane and session names; rename is the label
+	// being typed (opened with n), nil when closed. syncPaneTitles also
+	// sets pane labels as tmux pane titles.
+	labels         *Labels
+	rename         *renameInput
+	syncPaneTitles bool
+
+	// note is the pane note being typed (opened with N), nil when closed.
+	// Notes are stored with the labels.
+	note *noteInput
+
+	// exportDir is where e writes the current verdicts and H pane
+	// transcripts; pager opens transcripts ("" only writes them).
+	exportDir string
+	pager     string
+
+	// confirm is the typed confirmation of a high-risk action, nil when
+	// closed; only used when confirmHighRisk is set. history logs every
+	// action sent (nil disables it).
+	confirmHighRisk bool
+	confirm         *confirmInput
+	history         *History
+	audit           *AuditLog
+
+	// inline is the number of lines of the inline mode, or 0 for the
+	// full-screen list (see viewInline).
+	inline int
+
+	// accessible renders states as words and avoids symbols and box
+	// drawing, for screen readers and dumb terminals (see glyphs).
+	accessible bool
+
 	// dimensions
 	width  int
 	height int
@@ -118,16 +333,113 @@ type tuiModel struct {
 	message   string
 	scanCount int
 
+	// scanProgress receives the running scan's verdicts as panes complete
+	// (see progress.go); scanDone of scanTotal panes have been received.
+	scanProgress chan ScanProgress
+	scanDone     int
+	scanTotal    int
+
 	// auto-nudge
 	autoNudge        bool   // whether auto-nudge is enabled (toggleable at runtime)
 	autoNudgeMaxRisk string // maximum risk: "low", "medium", "high"
-
-	// cumulative stats
+	// viewedPanes lists the panes shown in attached tmux clients, which
+	// auto-nudge leaves alone so it does not race someone typing into
+	// them; nil nudges them too.
+	viewedPanes func(context.Context) ([]string, error)
+	// instance holds the auto-nudge lease, so only one of several
+	// supervisors answers a dialog; nil when there is nothing to share.
+	instance *Instance
+
+	// remembered answers (nil when disabled). lastAnswer is the latest
+	// answer sent by hand that R offers to remember; remember is the scope
+	// prompt and answersScreen the management screen (M), nil when closed.
+	answerMemory  *AnswerMemory
+	lastAnswer    *lastAnswer
+	remember      *lastAnswer
+	answersScreen *answersScreen
+
+	// speech announcements (nil when disabled)
+	announcer *Announcer
+
+	// tmux badges on blocked panes (nil when disabled)
+	badges *Badges
+
+	// nudger sends keys to panes; nil uses tmux. Tests replace it.
+	nudger *Nudger
+
+	// store turns each scan into pane state transitions, which feed the
+	// status line, speech, history, and transitionHook (nil when disabled).
+	store          VerdictStore
+	transitionHook *TransitionHook
+	escalator      *Escalator
+
+	// quietHours is the do-not-disturb schedule; dnd overrides it (z).
+	quietHours config.QuietHours
+	dnd        dndOverride
+
+	// cumulative stats; outcomes follows the actions sent until the
+	// panes' next scans show whether they worked.
 	totalCacheHits int
+	stats          sessionStats
+	outcomes       outcomeTracker
+
+	// dashboard shows the session stats screen (toggle with D).
+	dashboard bool
+
+	// cacheScreen is the verdict cache screen (C), nil when closed.
+	cacheScreen *cacheScreen
+
+	// log is the debug log, logFile the file it writes to, and logView the
+	// log's tail (E), nil when closed.
+	log     *slog.Logger
+	logFile string
+	logView *logView
+	// timeline is the selected pane's timeline view (ctrl+t), nil when
+	// closed.
+	timeline *timelineView
+
+	// serverDown counts consecutive scans that found no tmux server; while
+	// non-zero a banner replaces the pane list and scans are retried with
+	// backoff.
+	serverDown int
+
+	// profiles are the config profiles offered by the picker (P), nil when
+	// closed; profile is the current one. rescanAfterScan rescans once the
+	// scan in flight returns, whose settings are stale.
+	profiles        []Profile
+	profile         string
+	profilePicker   *profilePicker
+	rescanAfterScan bool
+
+	// scanAgain starts another scan once the one in flight was applied;
+	// rescan requests arriving during a scan are coalesced into it.
+	// paneScans are panes rescanned on their own (rescan <target>) during
+	// the scan in flight, merged into its result.
+	scanAgain bool
+	paneScans map[string]model.Verdict
+
+	// reload loads the config file again; settings are the ones loaded
+	// last, and configStamp the version of configFile they came from.
+	reload      ReloadFunc
+	settings    Settings
+	configFile  string
+	configStamp fileStamp
 }
 
 func (t *TUI) Run(ctx context.Context) error {
-	theme := ThemeByName(t.ThemeName)
+	colorProfile := t.ColorProfile
+	if t.Accessible && (colorProfile == "" || colorProfile == "auto") {
+		colorProfile = "none"
+	}
+	if p, ok := ParseColorProfile(colorProfile); ok {
+		lipgloss.SetColorProfile(p)
+	}
+	themes := newThemeSet(t.Themes)
+	themeName := t.ThemeName
+	if themeName == "" {
+		themeName = "dark"
+	}
+	theme := themes.theme(themeName)
 	s := newStyles(theme)
 
 	maxRisk := t.AutoNudgeMaxRisk
@@ -138,6 +450,8 @@ func (t *TUI) Run(ctx context.Context) error {
 	m := &tuiModel{
 		theme:            theme,
 		s:                s,
+		themeName:        themeName,
+		themes:           themes,
 		scanner:          t.Scanner,
 		ctx:              ctx,
 		refreshInterval:  t.RefreshInterval,
@@ -145,15 +459,127 @@ func (t *TUI) Run(ctx context.Context) error {
 		manualCollapsed:  make(map[string]bool),
 		autoNudge:        t.AutoNudge,
 		autoNudgeMaxRisk: maxRisk,
+		announcer:        t.Announcer,
+		badges:           t.Badges,
+		transitionHook:   t.TransitionHook,
+		escalator:        t.Escalator,
+		quietHours:       t.QuietHours,
+		showWaitingFor:   t.ShowWaitingFor,
+		showRecommended:  t.ShowRecommended,
+		templateDir:      t.TemplateDir,
+		snippets:         t.Snippets,
+		continuations:    t.Continuations,
+		idleAfter:        t.IdleAfter,
+		tasks:            t.Tasks,
+		layout:           parseLayout(t.Layout),
+		actionOrderMode:  t.ActionOrder,
+		clickSelects:     t.ClickAction == "select",
+		groupBy:          parseGroupMode(t.GroupBy),
+		labels:           t.Labels,
+		syncPaneTitles:   t.SyncPaneTitles,
+		exportDir:        t.ExportDir,
+		pager:            transcriptPager(),
+		confirmHighRisk:  t.ConfirmHighRisk,
+		history:          t.History,
+		audit:            t.Audit,
+		answerMemory:     t.AnswerMemory,
+		profiles:         t.Profiles,
+		profile:          t.Profile,
+		accessible:       t.Accessible,
+		inline:           t.Inline,
+		reload:           t.Reload,
+		log:              t.Log,
+		logFile:          t.LogFile,
+		configFile:       t.ConfigFile,
+		configStamp:      statFile(t.ConfigFile),
+		settings: Settings{
+			Profile: Profile{
+				Name:             t.Profile,
+				RefreshInterval:  t.RefreshInterval,
+				AutoNudge:        t.AutoNudge,
+				AutoNudgeMaxRisk: t.AutoNudgeMaxRisk,
+			},
+			Profiles:        t.Profiles,
+			ThemeName:       t.ThemeName,
+			Themes:          t.Themes,
+			ShowWaitingFor:  t.ShowWaitingFor,
+			ShowRecommended: t.ShowRecommended,
+			Layout:          t.Layout,
+			ActionOrder:     t.ActionOrder,
+			ClickAction:     t.ClickAction,
+			GroupBy:         t.GroupBy,
+			Snippets:        t.Snippets,
+			Continuations:   t.Continuations,
+			ConfirmHighRisk: t.ConfirmHighRisk,
+		},
+	}
+	if m.templateDir == "" {
+		m.templateDir = launch.DefaultDir()
+	}
+	if !t.AutoNudgeViewed {
+		m.viewedPanes = mux.NewTmux().ViewedPanes
+	}
+	m.instance = t.Instance
+	if t.Log != nil {
+		m.nudger = DefaultNudger()
+		m.nudger.Log = t.Log
+	}
+	// Inline mode shares the terminal with the shell, whose mouse events
+	// have coordinates outside the program's lines.
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if t.Inline > 0 {
+		opts = nil
+	}
+	// Cancelling ctx stops the TUI too; SIGINT and SIGTERM are handled by
+	// the program, which restores the terminal before Run returns.
+	p := tea.NewProgram(m, append(opts, tea.WithContext(ctx))...)
+	if t.Reload != nil {
+		defer reloadOnSIGHUP(p)()
+	}
+	if t.Control != nil {
+		// Scripts are a convenience; run without them if the socket is taken.
+		if stop, err := t.Control.Listen(p.Send); err != nil {
+			m.logger().Warn("control socket disabled", "err", err)
+			m.message = err.Error()
+		} else {
+			defer stop()
+		}
 	}
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err := p.Run()
+	if errors.Is(err, tea.ErrInterrupted) || (errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil) {
+		// A signal or a cancelled ctx is a regular way to stop.
+		err = nil
+	}
 	return err
 }
 
 func (m *tuiModel) Init() tea.Cmd {
 	m.scanning = true
-	return m.doScan()
+	return tea.Batch(m.doScan(), m.watchConfig())
+}
+
+// maxServerRetryDelay caps the backoff between scans while the tmux server
+// is down.
+const maxServerRetryDelay = 30 * time.Second
+
+// serverRetryDelay is the wait before the next scan after attempt failed
+// scans: 1s, 2s, 4s, ... up to maxServerRetryDelay.
+func serverRetryDelay(attempt int) time.Duration {
+	if attempt > 5 {
+		return maxServerRetryDelay
+	}
+	return min(time.Second<<max(attempt-1, 0), maxServerRetryDelay)
+}
+
+// serverLost handles a scan that found no tmux server: the verdicts of the
+// old server's panes are dropped and the scan is retried with backoff,
+// whether or not auto-refresh is on.
+func (m *tuiModel) serverLost() tea.Cmd {
+	m.serverDown++
+	m.verdicts = nil
+	m.rebuildGroups()
+	m.message = ""
+	return tea.Tick(serverRetryDelay(m.serverDown), func(time.Time) tea.Msg { return tickMsg{} })
 }
 
 // scheduleTick returns a tea.Cmd that sends a tickMsg after the refresh interval.
@@ -167,51 +593,68 @@ func (m *tuiModel) scheduleTick() tea.Cmd {
 	})
 }
 
+// doScan scans in the background, streaming verdicts to the list as panes
+// complete, and sends a scanResultMsg with the full result when done.
 func (m *tuiModel) doScan() tea.Cmd {
 	scanner := m.scanner
 	ctx := m.ctx
-	return func() tea.Msg {
-		result, err := scanner.Scan(ctx)
-		return scanResultMsg{result: result, err: err}
-	}
+	progress := make(chan ScanProgress, scanProgressBuffer)
+	m.startScanProgress(progress)
+	return tea.Batch(waitScanProgress(progress), func() tea.Msg {
+		start := time.Now()
+		result, err := scanner.ScanStream(ctx, progress)
+		close(progress)
+		return scanResultMsg{result: result, err: err, duration: time.Since(start)}
+	})
 }
 
-// rebuildGroups groups verdicts by session and rebuilds the visible items list.
+// rebuildGroups groups verdicts by session (or directory or repository, see
+// groupBy) and rebuilds the visible items list.
 // The display filter controls which panes are included:
 //   - filterBlocked: only agent panes that are blocked
 //   - filterAgents: all agent panes (blocked + active), excluding non-agents
 //   - filterAll: everything including non-agent panes
+//   - filterIdle: agent panes idle at their prompt for idle_after
 func (m *tuiModel) rebuildGroups() {
-	seen := map[string]int{} // session -> index in groups
+	seen := map[string]int{} // group name -> index in groups
 	m.groups = nil
+	pinned := sessionGroup{name: pinnedGroup}
 	for i, v := range m.verdicts {
-		// Apply display filter
+		// Pinned panes are listed above the groups, whatever the filters.
+		if m.labels.Pinned(v.Target) {
+			pinned.add(i, v)
+			continue
+		}
+		if m.modelFilter != "" && v.Model != m.modelFilter {
+			continue
+		}
+		// Apply display filter. Generic shell prompts are low-confidence
+		// verdicts for non-agent panes, so they only appear in "all".
 		switch m.filter {
 		case filterBlocked:
-			if !v.Blocked || v.Agent == "not_an_agent" || v.Agent == "error" {
+			if !parser.IsAgentBlock(v) {
 				continue
 			}
 		case filterAgents:
-			if v.Agent == "not_an_agent" {
+			if v.Agent == "not_an_agent" || v.Agent == parser.AgentGenericPrompt {
 				continue
 			}
 		case filterAll:
 			// show everything
+		case filterIdle:
+			if !m.idleTooLong(v) {
+				continue
+			}
 		}
 
-		idx, ok := seen[v.Session]
+		key := m.groupKey(v)
+		idx, ok := seen[key]
 		if !ok {
 			idx = len(m.groups)
-			seen[v.Session] = idx
-			m.groups = append(m.groups, sessionGroup{name: v.Session})
-		}
-		m.groups[idx].verdicts = append(m.groups[idx].verdicts, i)
-		if v.Blocked {
-			m.groups[idx].blocked++
-		}
-		if v.Agent != "error" && v.Agent != "not_an_agent" && !v.Blocked {
-			m.groups[idx].active++
+			seen[key] = idx
+			m.groups = append(m.groups, sessionGroup{name: key})
 		}
+		m.groups[idx].add(i, v)
 	}
 
 	// Sort groups alphabetically for a stable, predictable order.
@@ -219,88 +662,172 @@ func (m *tuiModel) rebuildGroups() {
 	sort.SliceStable(m.groups, func(i, j int) bool {
 		return m.groups[i].name < m.groups[j].name
 	})
+	for gi := range m.groups {
+		m.splitWindows(&m.groups[gi])
+	}
+	if len(pinned.verdicts) > 0 {
+		m.groups = append([]sessionGroup{pinned}, m.groups...)
+	}
 
 	// Auto-expand policy by filter:
 	// - blocked: sessions with blocked panes and single-pane sessions
 	// - agents: sessions with any agent panes and single-pane sessions
 	// - all: all sessions
+	// Windows within a session follow the same policy.
 	// Respect manual collapses: if the user explicitly collapsed a session,
 	// don't auto-expand it until the user re-expands it manually.
+	// The pinned section is always expanded.
 	for _, g := range m.groups {
-		if m.manualCollapsed[g.name] {
-			continue
-		}
-		autoExpand := false
-		switch m.filter {
-		case filterBlocked:
-			autoExpand = len(g.verdicts) == 1 || g.blocked > 0
-		case filterAgents:
-			autoExpand = len(g.verdicts) == 1 || (g.blocked+g.active) > 0
-		case filterAll:
-			autoExpand = true
-		}
-		if autoExpand {
+		if g.name == pinnedGroup && !m.manualCollapsed[g.name] {
 			m.expanded[g.name] = true
 		}
+		m.autoExpand(g.name, len(g.verdicts), g.blocked, g.active)
+		for _, w := range g.windows {
+			m.autoExpand(windowKey(g.name, w.window), len(w.verdicts), w.blocked, w.active)
+		}
 	}
 
 	m.rebuildItems()
 }
 
+// autoExpand expands the session or window key with the given pane counts
+// according to the auto-expand policy, unless it was collapsed manually.
+func (m *tuiModel) autoExpand(key string, panes, blocked, active int) {
+	if m.manualCollapsed[key] {
+		return
+	}
+	autoExpand := false
+	switch m.filter {
+	case filterBlocked:
+		autoExpand = panes == 1 || blocked > 0
+	case filterAgents:
+		autoExpand = panes == 1 || (blocked+active) > 0
+	case filterAll, filterIdle:
+		autoExpand = true
+	}
+	if autoExpand {
+		m.expanded[key] = true
+	}
+}
+
+// knownModels returns the distinct non-empty models across all verdicts,
+// sorted alphabetically.
+func (m *tuiModel) knownModels() []string {
+	seen := map[string]bool{}
+	var models []string
+	for _, v := range m.verdicts {
+		if v.Model != "" && !seen[v.Model] {
+			seen[v.Model] = true
+			models = append(models, v.Model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
+
+// nextModelFilter returns the model filter after the current one in the
+// cycle: all -> model1 -> model2 -> ... -> all.
+func (m *tuiModel) nextModelFilter() string {
+	models := m.knownModels()
+	if m.modelFilter == "" {
+		if len(models) == 0 {
+			return ""
+		}
+		return models[0]
+	}
+	for i, name := range models {
+		if name == m.modelFilter && i+1 < len(models) {
+			return models[i+1]
+		}
+	}
+	return ""
+}
+
 // rebuildItems builds the flat visible items list from groups + expanded state.
 func (m *tuiModel) rebuildItems() {
 	m.items = nil
 	for _, g := range m.groups {
 		m.items = append(m.items, listItem{kind: itemSession, session: g.name})
-		if m.expanded[g.name] {
+		if !m.expanded[g.name] {
+			continue
+		}
+		if len(g.windows) == 0 {
 			for _, vi := range g.verdicts {
 				m.items = append(m.items, listItem{kind: itemPane, session: g.name, paneIdx: vi})
 			}
+			continue
+		}
+		for _, w := range g.windows {
+			m.items = append(m.items, listItem{kind: itemWindow, session: g.name, window: w.window})
+			if m.expanded[windowKey(g.name, w.window)] {
+				for _, vi := range w.verdicts {
+					m.items = append(m.items, listItem{kind: itemPane, session: g.name, window: w.window, paneIdx: vi, inWindow: true})
+				}
+			}
 		}
 	}
 }
 
 // selectedVerdict returns the verdict for the currently selected item.
-// For session headers: the first blocked pane, or the first pane.
+// For session and window headers: the first blocked pane, or the first pane.
 // For pane items: that pane's verdict.
 func (m *tuiModel) selectedVerdict() *model.Verdict {
 	if m.cursor < 0 || m.cursor >= len(m.items) {
 		return nil
 	}
 	item := m.items[m.cursor]
-	if item.kind == itemPane {
+	switch item.kind {
+	case itemPane:
 		return &m.verdicts[item.paneIdx]
+	case itemWindow:
+		if w := m.findWindow(item); w != nil {
+			return m.bestVerdict(w.verdicts)
+		}
+		return nil
 	}
 	// Session header: find best pane
 	for _, g := range m.groups {
 		if g.name == item.session {
-			// Prefer first blocked pane
-			for _, vi := range g.verdicts {
-				if m.verdicts[vi].Blocked {
-					return &m.verdicts[vi]
-				}
-			}
-			// Otherwise first pane
-			if len(g.verdicts) > 0 {
-				return &m.verdicts[g.verdicts[0]]
-			}
+			return m.bestVerdict(g.verdicts)
+		}
+	}
+	return nil
+}
+
+// bestVerdict returns the first blocked verdict of indices, or the first.
+func (m *tuiModel) bestVerdict(indices []int) *model.Verdict {
+	// Prefer first blocked pane
+	for _, vi := range indices {
+		if m.verdicts[vi].Blocked {
+			return &m.verdicts[vi]
 		}
 	}
+	// Otherwise first pane
+	if len(indices) > 0 {
+		return &m.verdicts[indices[0]]
+	}
 	return nil
 }
 
 // selectedItemKey returns a stable identifier for the currently selected item.
 // For pane items: the pane's Target (e.g. "session:window.pane").
-// For session headers: the session name prefixed with "session:" to avoid
-// collisions with pane targets.
+// For session and window headers: the session name or window key prefixed
+// with "session:" or "window:" to avoid collisions with pane targets.
 // Returns "" if nothing is selected.
 func (m *tuiModel) selectedItemKey() string {
 	if m.cursor < 0 || m.cursor >= len(m.items) {
 		return ""
 	}
-	item := m.items[m.cursor]
-	if item.kind == itemPane {
+	return m.itemKey(m.items[m.cursor])
+}
+
+// itemKey returns the stable identifier of item (see selectedItemKey).
+func (m *tuiModel) itemKey(item listItem) string {
+	switch item.kind {
+	case itemPane:
 		return m.verdicts[item.paneIdx].Target
+	case itemWindow:
+		return "window:" + item.expandKey()
 	}
 	return "session:" + item.session
 }
@@ -314,11 +841,7 @@ func (m *tuiModel) restoreCursorByKey(key string) {
 		return
 	}
 	for i, item := range m.items {
-		if item.kind == itemPane && m.verdicts[item.paneIdx].Target == key {
-			m.cursor = i
-			return
-		}
-		if item.kind == itemSession && "session:"+item.session == key {
+		if m.itemKey(item) == key {
 			m.cursor = i
 			return
 		}
@@ -328,16 +851,42 @@ func (m *tuiModel) restoreCursorByKey(key string) {
 }
 
 // clampCursorToPane clamps cursor to valid range and advances past session
-// headers so the cursor lands on a pane.
+// and window headers so the cursor lands on a pane.
 func (m *tuiModel) clampCursorToPane() {
 	if m.cursor >= len(m.items) {
 		m.cursor = 0
 	}
-	for m.cursor < len(m.items)-1 && m.items[m.cursor].kind == itemSession {
+	for m.cursor < len(m.items)-1 && m.items[m.cursor].kind != itemPane {
 		m.cursor++
 	}
 }
 
+// skippedByCursor reports whether up and down move past item: session
+// headers and the headers of expanded windows, whose panes are listed
+// below them. A collapsed window stops the cursor, so it can be expanded.
+func (m *tuiModel) skippedByCursor(item listItem) bool {
+	switch item.kind {
+	case itemSession:
+		return true
+	case itemWindow:
+		return m.expanded[item.expandKey()]
+	}
+	return false
+}
+
+// toggleExpanded expands or collapses the session or window header item,
+// remembering a manual collapse.
+func (m *tuiModel) toggleExpanded(item listItem) {
+	key := item.expandKey()
+	m.expanded[key] = !m.expanded[key]
+	if m.expanded[key] {
+		delete(m.manualCollapsed, key)
+	} else {
+		m.manualCollapsed[key] = true
+	}
+	m.rebuildItems()
+}
+
 func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -351,9 +900,36 @@ func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case scanProgressMsg:
+		if msg.ch == m.scanProgress && !m.rescanAfterScan {
+			m.applyScanProgress(msg.progress)
+		}
+		return m, waitScanProgress(msg.ch)
+
 	case scanResultMsg:
 		m.scanning = false
+		m.scanProgress = nil
+		if m.rescanAfterScan {
+			m.rescanAfterScan = false
+			m.scanning = true
+			return m, m.doScan()
+		}
+		if errors.Is(msg.err, mux.ErrNoServer) {
+			if m.serverDown == 0 {
+				m.logger().Warn("tmux server not running", "err", msg.err)
+			}
+			return m, m.serverLost()
+		}
+		back := m.serverDown > 0 && msg.err == nil
+		if back {
+			m.logger().Info("tmux server is back")
+		}
+		m.serverDown = 0
+		var transitions []Transition
+		var escalations []Escalation
+		var outcomeCmd tea.Cmd
 		if msg.err != nil {
+			m.logger().Error("scan failed", "err", msg.err)
 			m.message = fmt.Sprintf("Scan error: %v", msg.err)
 		} else if msg.result != nil {
 			// Preserve cursor position across rebuild: save the selected
@@ -361,28 +937,189 @@ func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			prevKey := m.selectedItemKey()
 
 			m.verdicts = msg.result.Verdicts
+			m.mergePaneScans()
 			m.scanCount++
 			m.totalCacheHits += msg.result.CacheHits
+			m.stats.recordScan(msg.result, msg.duration, time.Now())
 
 			m.rebuildGroups()
 			m.restoreCursorByKey(prevKey)
+			transitions = m.store.Apply(m.verdicts, time.Now())
+			for _, t := range transitions {
+				m.logger().Info("transition", "kind", t.Kind, "target", t.Target, "agent", t.Verdict.Agent, "reason", t.Verdict.Reason)
+			}
+			if summary := summarizeTransitions(transitions); summary != "" {
+				m.message = summary
+			}
+			m.reportIdle(time.Now())
+			m.expireDND(time.Now())
+			if !m.doNotDisturb(time.Now()) {
+				escalations = m.store.Escalate(m.escalator, time.Now())
+			}
+			for _, e := range escalations {
+				m.message = strings.TrimPrefix(m.message+" · "+e.String(), " · ")
+			}
+			if back {
+				m.message = strings.TrimSuffix("tmux server is back · "+summarizeTransitions(transitions), " · ")
+			}
+			resolved := m.outcomes.observe(m.verdicts)
+			m.selectFollowUps(resolved)
+			outcomeCmd = tea.Batch(m.auditCmd(scanAuditRecords(m.verdicts, time.Now())),
+				m.resolveOutcomes(resolved))
+		}
+		if m.logView != nil && m.logView.follow {
+			m.readLog()
 		}
-		// Schedule next auto-refresh and auto-nudge (both async).
+		// Schedule next auto-refresh and auto-nudge (both async). A rescan
+		// requested during the scan replaces the wait for the next refresh.
 		var cmds []tea.Cmd
-		if cmd := m.scheduleTick(); cmd != nil {
+		if m.scanAgain {
+			m.scanAgain = false
+			m.scanning = true
+			cmds = append(cmds, m.doScan())
+		} else if cmd := m.scheduleTick(); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
 		if cmd := m.autoNudgeCmd(); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+		if msg.err == nil {
+			if cmd := m.announceCmd(transitions); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			if cmd := m.transitionCmd(transitions); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			if cmd := m.escalationCmd(escalations); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			if cmd := m.badgeCmd(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+			if outcomeCmd != nil {
+				cmds = append(cmds, outcomeCmd)
+			}
+		}
 		return m, tea.Batch(cmds...)
 
+	case speechResultMsg:
+		if msg.err != nil {
+			m.logger().Warn("speech failed", "err", msg.err)
+			m.message = fmt.Sprintf("Speech error: %v", msg.err)
+		}
+		return m, nil
+
+	case transitionResultMsg:
+		if msg.err != nil {
+			m.logger().Warn("transition command failed", "err", msg.err)
+			m.message = msg.err.Error()
+		}
+		return m, nil
+
+	case badgeResultMsg:
+		if msg.err != nil {
+			m.logger().Warn("tmux badge update failed", "err", msg.err)
+			m.message = fmt.Sprintf("tmux badge error: %v", msg.err)
+		}
+		return m, nil
+
+	case launchResultMsg:
+		if msg.err != nil {
+			m.logger().Error("launch failed", "launched", len(msg.launched), "err", msg.err)
+			m.message = fmt.Sprintf("Launch failed after %d panes: %v", len(msg.launched), msg.err)
+		} else {
+			m.message = fmt.Sprintf("Launched %d panes", len(msg.launched))
+		}
+		if len(msg.launched) == 0 || m.scanning {
+			return m, nil
+		}
+		m.scanning = true
+		return m, m.doScan()
+
+	case clipboardPasteMsg:
+		if msg.err != nil {
+			m.message = msg.err.Error()
+		} else if m.textInput != nil {
+			m.insertText(msg.text)
+		}
+		return m, nil
+
+	case diffCaptureMsg:
+		if m.diff == nil || m.diff.target != msg.target {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.diff.loading = false
+			m.message = fmt.Sprintf("Capture failed: %v", msg.err)
+			return m, nil
+		}
+		var actions int
+		if v := m.verdictByTarget(msg.target); v != nil {
+			actions = len(v.Actions)
+		}
+		m.diff.applyCapture(msg, m.diffRows(actions))
+		return m, nil
+
+	case attachCaptureMsg, attachTickMsg, attachSentMsg:
+		return m, m.updateAttach(msg)
+
+	case paneControlMsg:
+		m.control = &msg.control
+		m.message = ""
+		return m, nil
+
 	case nudgeResultMsg:
+		m.stats.recordNudges(msg.sent, msg.auto)
+		for _, t := range msg.nudges {
+			m.store.Note(t.target, TimelineEntry{Time: time.Now(), Kind: "nudge", Action: t.label, Auto: msg.auto})
+		}
 		if len(msg.messages) > 0 {
 			m.message = strings.Join(msg.messages, " | ")
 		}
+		if msg.answer != nil && msg.sent > 0 && m.answerMemory != nil {
+			m.lastAnswer = msg.answer
+			m.message += " · R to always answer this"
+		}
+		var rescan tea.Cmd
+		if msg.changed {
+			if m.scanning {
+				m.rescanAfterScan = true
+			} else {
+				m.scanning = true
+				rescan = m.doScan()
+			}
+		}
+		return m, tea.Batch(m.auditCmd(actionAuditRecords(msg.nudges, msg.auto, time.Now())),
+			m.resolveOutcomes(m.outcomes.track(msg.nudges, msg.auto, time.Now())), rescan)
+
+	case nextTaskMsg:
+		m.handleNextTask(msg)
+		return m, nil
+
+	case transcriptMsg:
+		return m, m.handleTranscript(msg)
+
+	case pagerDoneMsg:
+		if msg.err != nil {
+			m.logger().Warn("pager failed", "err", msg.err)
+			m.message = fmt.Sprintf("Pager failed: %v", msg.err)
+		}
 		return m, nil
 
+	case configCheckMsg:
+		return m, m.checkConfig()
+
+	case reloadConfigMsg:
+		return m, m.reloadConfig()
+
+	case controlMsg:
+		answer, cmd := m.runControl(msg.command)
+		msg.reply <- answer
+		return m, cmd
+
+	case paneScanMsg:
+		return m, m.applyPaneScan(msg)
+
 	case tickMsg:
 		if m.scanning {
 			return m, m.scheduleTick()
@@ -395,14 +1132,76 @@ func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *tuiModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirm != nil {
+		return m.handleConfirmKey(msg)
+	}
+	if m.answering != nil {
+		return m.handleAnswerGroupKey(msg)
+	}
+	if m.templates != nil {
+		return m.handleTemplatePickerKey(msg)
+	}
+	if m.control != nil {
+		return m.handlePaneControlKey(msg)
+	}
+	if m.rawKeys != nil {
+		return m.handleRawKeyInput(msg)
+	}
+	if m.textInput != nil {
+		return m.handleTextInputKey(msg)
+	}
+	if m.diff != nil {
+		return m.handleDiffViewKey(msg)
+	}
+	if m.rename != nil {
+		return m.handleRenameKey(msg)
+	}
+	if m.note != nil {
+		return m.handleNoteKey(msg)
+	}
+	if m.attach != nil {
+		return m.handleAttachKey(msg)
+	}
+	if m.remember != nil {
+		return m.handleRememberKey(msg)
+	}
+	if m.answersScreen != nil {
+		return m.handleAnswersScreenKey(msg)
+	}
+	if m.profilePicker != nil {
+		return m.handleProfileKey(msg)
+	}
+	if m.cacheScreen != nil {
+		return m.handleCacheScreenKey(msg)
+	}
+	if m.logView != nil {
+		return m.handleLogViewKey(msg)
+	}
+	if m.timeline != nil {
+		return m.handleTimelineKey(msg)
+	}
+	if m.dashboard {
+		return m.handleDashboardKey(msg)
+	}
 	return m.handleVerdictListKey(msg)
 }
 
 func (m *tuiModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	// The action panel: hovering keeps the selection, clicking an action
+	// sends it to the selected pane.
+	if row, ok := m.inActionPanel(msg.X, msg.Y); ok {
+		if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return m, nil
+		}
+		if v, a, ok := m.actionAt(row); ok {
+			return m, m.sendActionCmd(v.Target, a)
+		}
+		return m, nil
+	}
+
 	// Hover: move cursor to hovered item.
 	if msg.Action == tea.MouseActionMotion {
-		idx := msg.Y - 1 + m.listStart
-		if idx >= 0 && idx < len(m.items) {
+		if idx := m.itemAtRow(msg.Y - 1); idx >= 0 {
 			m.cursor = idx
 		}
 		return m, nil
@@ -413,34 +1212,60 @@ func (m *tuiModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Click in the list panel: header line is row 0, items start at row 1
-	clickedIdx := msg.Y - 1 + m.listStart // offset for header line + scroll
-	if clickedIdx < 0 || clickedIdx >= len(m.items) {
+	clickedIdx := m.itemAtRow(msg.Y - 1)
+	if clickedIdx < 0 {
 		return m, nil
 	}
 
 	m.cursor = clickedIdx
 	item := m.items[clickedIdx]
 	if item.kind == itemPane {
+		if !m.clickJumps(clickedIdx, msg, time.Now()) {
+			return m, nil
+		}
 		// Navigate tmux to this pane
 		if errMsg := jumpToPane(m.verdicts[item.paneIdx].Target); errMsg != "" {
 			m.message = errMsg
 		}
 	} else {
-		// Session header: toggle expand/collapse
-		m.expanded[item.session] = !m.expanded[item.session]
-		if m.expanded[item.session] {
-			delete(m.manualCollapsed, item.session)
-		} else {
-			m.manualCollapsed[item.session] = true
-		}
-		m.rebuildItems()
-		if m.expanded[item.session] && m.cursor+1 < len(m.items) {
+		// Session or window header: toggle expand/collapse
+		m.toggleExpanded(item)
+		if m.expanded[item.expandKey()] && m.cursor+1 < len(m.items) {
 			m.cursor++
 		}
 	}
 	return m, nil
 }
 
+// doubleClickInterval is the longest time between the two clicks of a
+// double click.
+const doubleClickInterval = 400 * time.Millisecond
+
+// paneClick is a click on a list item.
+type paneClick struct {
+	idx int
+	at  time.Time
+}
+
+// clickJumps records a click on list item idx and reports whether it jumps
+// to the pane: always with click_action "jump", otherwise only for a double
+// click or an alt/ctrl-click.
+func (m *tuiModel) clickJumps(idx int, msg tea.MouseMsg, now time.Time) bool {
+	if !m.clickSelects {
+		return true
+	}
+	prev := m.lastClick
+	m.lastClick = paneClick{idx: idx, at: now}
+	if msg.Alt || msg.Ctrl {
+		return true
+	}
+	if prev.idx == idx && !prev.at.IsZero() && now.Sub(prev.at) <= doubleClickInterval {
+		m.lastClick = paneClick{} // a third click starts over
+		return true
+	}
+	return false
+}
+
 func (m *tuiModel) handleVerdictListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q", "ctrl+c":
@@ -450,7 +1275,7 @@ func (m *tuiModel) handleVerdictListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.items) > 0 && m.cursor > 0 {
 			m.cursor--
 			// Skip session headers — only panes are actionable
-			for m.cursor > 0 && m.items[m.cursor].kind == itemSession {
+			for m.cursor > 0 && m.skippedByCursor(m.items[m.cursor]) {
 				m.cursor--
 			}
 		}
@@ -459,7 +1284,7 @@ func (m *tuiModel) handleVerdictListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.items) > 0 && m.cursor < len(m.items)-1 {
 			m.cursor++
 			// Skip session headers — only panes are actionable
-			for m.cursor < len(m.items)-1 && m.items[m.cursor].kind == itemSession {
+			for m.cursor < len(m.items)-1 && m.skippedByCursor(m.items[m.cursor]) {
 				m.cursor++
 			}
 		}
@@ -469,16 +1294,10 @@ func (m *tuiModel) handleVerdictListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		item := m.items[m.cursor]
-		if item.kind == itemSession {
+		if item.kind != itemPane {
 			// Toggle expand/collapse
-			m.expanded[item.session] = !m.expanded[item.session]
-			if m.expanded[item.session] {
-				delete(m.manualCollapsed, item.session)
-			} else {
-				m.manualCollapsed[item.session] = true
-			}
-			m.rebuildItems()
-			if m.expanded[item.session] && m.cursor+1 < len(m.items) {
+			m.toggleExpanded(item)
+			if m.expanded[item.expandKey()] && m.cursor+1 < len(m.items) {
 				m.cursor++
 			}
 			return m, nil
@@ -494,11 +1313,11 @@ func (m *tuiModel) handleVerdictListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		item := m.items[m.cursor]
-		if item.kind == itemSession {
-			// Expand session and move to first pane
-			if !m.expanded[item.session] {
-				m.expanded[item.session] = true
-				delete(m.manualCollapsed, item.session)
+		if item.kind != itemPane {
+			// Expand session or window and move to its first row
+			if key := item.expandKey(); !m.expanded[key] {
+				m.expanded[key] = true
+				delete(m.manualCollapsed, key)
 				m.rebuildItems()
 			}
 			if m.cursor+1 < len(m.items) {
@@ -508,26 +1327,31 @@ func (m *tuiModel) handleVerdictListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "left", "h":
-		// Collapse: if on a pane, jump to its session header
-		// If on a session, collapse it
+		// Collapse: if on a pane, jump to its window or session header
+		// If on a session or expanded window, collapse it; from a
+		// collapsed window, jump to its session header
 		if m.cursor < 0 || m.cursor >= len(m.items) {
 			return m, nil
 		}
 		item := m.items[m.cursor]
-		if item.kind == itemPane {
-			// Find the session header above
+		if item.kind == itemPane || (item.kind == itemWindow && !m.expanded[item.expandKey()]) {
+			// Find the header above
 			for i := m.cursor - 1; i >= 0; i-- {
-				if m.items[i].kind == itemSession && m.items[i].session == item.session {
+				h := m.items[i]
+				if h.session != item.session || h.kind == itemPane {
+					continue
+				}
+				if h.kind == itemSession || (item.kind == itemPane && item.inWindow && h.window == item.window) {
 					m.cursor = i
 					break
 				}
 			}
 			return m, nil
 		}
-		// On session header: collapse
-		if m.expanded[item.session] {
-			m.expanded[item.session] = false
-			m.manualCollapsed[item.session] = true
+		// On session or window header: collapse
+		if key := item.expandKey(); m.expanded[key] {
+			m.expanded[key] = false
+			m.manualCollapsed[key] = true
 			m.rebuildItems()
 			if m.cursor >= len(m.items) {
 				m.cursor = len(m.items) - 1
@@ -536,53 +1360,633 @@ func (m *tuiModel) handleVerdictListKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "a":
-		// Toggle auto-nudge
-		m.autoNudge = !m.autoNudge
-		if m.autoNudge {
-			m.message = fmt.Sprintf("Auto-nudge ON (max risk: %s)", m.autoNudgeMaxRisk)
-		} else {
-			m.message = "Auto-nudge OFF"
-		}
+		m.toggleAutoNudge()
 		return m, nil
 
 	case "f":
-		// Cycle display filter: blocked -> agents -> all -> blocked
-		m.filter = m.filter.next()
+		// Cycle display filter: blocked -> agents -> all (-> idle) -> blocked
+		m.filter = m.filter.next(m.idleAfter > 0)
 		m.message = fmt.Sprintf("Filter: %s", m.filter)
 		m.rebuildGroups()
 		m.cursor = 0
 		m.clampCursorToPane()
 		return m, nil
 
-	case "r":
-		// Rescan
-		m.scanning = true
-		m.message = ""
-		return m, m.doScan()
-	}
+	case "g":
+		// Cycle grouping: session -> directory -> repo -> session
+		key := m.selectedItemKey()
+		m.groupBy = m.groupBy.next()
+		m.repoRoots = nil
+		m.message = fmt.Sprintf("Group: %s", m.groupBy)
+		m.rebuildGroups()
+		m.restoreCursorByKey(key)
+		return m, nil
 
-	return m, nil
-}
+	case "m":
+		// Cycle model filter: all -> each model seen in verdicts -> all
+		m.modelFilter = m.nextModelFilter()
+		if m.modelFilter == "" {
+			m.message = "Model: all"
+		} else {
+			m.message = fmt.Sprintf("Model: %s", m.modelFilter)
+		}
+		m.rebuildGroups()
+		m.cursor = 0
+		m.clampCursorToPane()
+		return m, nil
 
-func (m *tuiModel) View() string {
-	if m.width == 0 {
-		return "Loading..."
-	}
+	case "w":
+		// Toggle WaitingFor preview lines under blocked panes
+		m.showWaitingFor = !m.showWaitingFor
+		if m.showWaitingFor {
+			m.message = "WaitingFor preview ON"
+		} else {
+			m.message = "WaitingFor preview OFF"
+		}
+		return m, nil
 
-	return m.viewVerdictList()
-}
+	case "s":
+		// Toggle the recommended action after blocked pane rows
+		m.showRecommended = !m.showRecommended
+		if m.showRecommended {
+			m.message = "Recommended actions ON"
+		} else {
+			m.message = "Recommended actions OFF"
+		}
+		return m, nil
 
-func (m *tuiModel) viewVerdictList() string {
-	var b strings.Builder
+	case "c":
+		// Toggle blocked-reason cluster sidebar
+		m.showClusters = !m.showClusters
+		if m.showClusters && m.width < minClusterLayoutWidth {
+			m.message = fmt.Sprintf("Clusters need a terminal at least %d columns wide", minClusterLayoutWidth)
+		} else if m.showClusters {
+			m.message = "Clusters ON"
+		} else {
+			m.message = "Clusters OFF"
+		}
+		return m, nil
 
-	// Header: title + keybindings + token usage
-	b.WriteString(m.s.title.Render("Pane Supervisor"))
-	b.WriteString("  ")
-	autoLabel := "a=auto:OFF"
-	if m.autoNudge {
-		autoLabel = fmt.Sprintf("a=auto:ON(%s)", m.autoNudgeMaxRisk)
-	}
+	case "A":
+		// Answer every pane showing the same dialog as the selected one
+		v := m.selectedVerdict()
+		if v == nil {
+			return m, nil
+		}
+		g, ok := answerGroupFor(m.visibleVerdicts(), v.Target)
+		if !ok {
+			m.message = "Selected pane has no dialog to answer"
+			return m, nil
+		}
+		if len(g.targets) < 2 {
+			m.message = "No other pane is waiting on the same dialog"
+			return m, nil
+		}
+		m.answering = &g
+		m.answerChoice = -1
+		m.message = ""
+		return m, nil
+
+	case "L":
+		// Open the launch template picker
+		entries, err := loadTemplateEntries(m.templateDir)
+		if err != nil {
+			m.message = fmt.Sprintf("Templates: %v", err)
+			return m, nil
+		}
+		if len(entries) == 0 {
+			m.message = fmt.Sprintf("No launch templates in %s", m.templateDir)
+			return m, nil
+		}
+		m.templates = entries
+		m.templateCursor = 0
+		m.message = ""
+		return m, nil
+
+	case "X":
+		// Interrupt, kill, or restart the selected pane's agent
+		if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].kind != itemPane {
+			return m, nil
+		}
+		return m, loadPaneControlCmd(m.ctx, m.verdicts[m.items[m.cursor].paneIdx])
+
+	case "t":
+		// Type a free-form answer for the selected pane
+		if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].kind != itemPane {
+			return m, nil
+		}
+		m.textInput = &textInput{target: m.verdicts[m.items[m.cursor].paneIdx].Target}
+		m.message = ""
+		return m, nil
+
+	case "y":
+		// Copy the selected pane's question to the system clipboard
+		v := m.selectedVerdict()
+		if v == nil {
+			return m, nil
+		}
+		return m, copyCmd(*v)
+
+	case "d":
+		// Review the selected pane's edit approval in a scrollable diff viewer
+		v := m.selectedVerdict()
+		if v == nil {
+			return m, nil
+		}
+		if !isEditApproval(*v) {
+			m.message = "Selected pane has no edit approval to review"
+			return m, nil
+		}
+		m.diff = &diffView{target: v.Target, loading: true}
+		m.message = ""
+		return m, captureDiffCmd(m.ctx, v.Target, diffHistoryStep)
+
+	case "K":
+		// Send arbitrary keys (C-c, Up, PageUp, ...) to the selected pane
+		if m.cursor < 0 || m.cursor >= len(m.items) || m.items[m.cursor].kind != itemPane {
+			return m, nil
+		}
+		m.rawKeys = &rawKeyInput{target: m.verdicts[m.items[m.cursor].paneIdx].Target}
+		m.message = ""
+		return m, nil
+
+	case "R":
+		// Remember the last answer sent by hand for its dialog
+		switch {
+		case m.answerMemory == nil:
+			m.message = "Remembered answers are unavailable (see the startup warning)"
+		case m.lastAnswer == nil:
+			m.message = "Answer a dialog first, then press R to remember the answer"
+		default:
+			m.remember = m.lastAnswer
+			m.message = ""
+		}
+		return m, nil
+
+	case "M":
+		// Review and delete remembered answers
+		if m.answerMemory == nil {
+			m.message = "Remembered answers are unavailable (see the startup warning)"
+			return m, nil
+		}
+		m.answersScreen = &answersScreen{}
+		m.message = ""
+		return m, nil
+
+	case "C":
+		// Inspect the verdict cache and flush bad entries
+		if m.scanner == nil || m.scanner.VerdictCache() == nil {
+			m.message = "The verdict cache is off (set cache_file to enable it)"
+			return m, nil
+		}
+		screen := &cacheScreen{}
+		if v := m.selectedVerdict(); v != nil {
+			for i, e := range m.scanner.VerdictCache().List() {
+				if e.Target == v.Target {
+					screen.cursor = i
+				}
+			}
+		}
+		m.cacheScreen = screen
+		m.message = ""
+		return m, nil
+
+	case "E":
+		// Read the supervisor log
+		if m.logFile == "" {
+			m.message = "The supervisor log is off (log_file: off)"
+			return m, nil
+		}
+		m.openLogView()
+		return m, nil
+
+	case "P":
+		// Switch to another profile from the config file
+		if len(m.profiles) < 2 {
+			m.message = "No profiles configured (see profiles in the README)"
+			return m, nil
+		}
+		picker := &profilePicker{}
+		for i, p := range m.profiles {
+			if p.Name == m.profile {
+				picker.cursor = i
+			}
+		}
+		m.profilePicker = picker
+		m.message = ""
+		return m, nil
+
+	case "i":
+		// Interact with the selected pane from inside the supervisor
+		v := m.selectedVerdict()
+		if v == nil {
+			return m, nil
+		}
+		m.attachSeq++
+		m.attach = &attachView{target: v.Target, seq: m.attachSeq}
+		m.message = ""
+		return m, captureAttachCmd(m.ctx, v.Target, m.attachSeq)
+
+	case "v":
+		// Move the action panel between below and right of the list
+		if m.layout == layoutBottom {
+			m.layout = layoutRight
+		} else {
+			m.layout = layoutBottom
+		}
+		if m.layout == layoutRight && m.width < minRightLayoutWidth {
+			m.message = fmt.Sprintf("Layout: right (needs a terminal at least %d columns wide)", minRightLayoutWidth)
+		} else {
+			m.message = fmt.Sprintf("Layout: %s", m.layout)
+		}
+		return m, nil
+
+	case "p":
+		// Pin or unpin the selected pane
+		m.togglePin()
+		return m, nil
+
+	case "z":
+		// Toggle do-not-disturb against the quiet hours schedule
+		m.toggleDND(time.Now())
+		return m, nil
+
+	case "O":
+		// Ask task_command for the selected idle pane's next task
+		return m, m.nextTaskCmd()
+
+	case "o":
+		// Pick a continuation prompt for the selected idle pane
+		m.openContinuation()
+		return m, nil
+
+	case ".":
+		// Resend the selected idle pane its last continuation
+		return m, m.resendContinuation()
+
+	case "n":
+		// Label the selected pane or session
+		if m.cursor < 0 || m.cursor >= len(m.items) {
+			return m, nil
+		}
+		if m.labels == nil {
+			m.message = "Labels are unavailable (see the startup warning)"
+			return m, nil
+		}
+		item := m.items[m.cursor]
+		if item.kind == itemWindow {
+			m.message = "Only sessions and panes can be labeled"
+			return m, nil
+		}
+		if item.kind == itemSession && (m.groupBy != groupBySession || item.session == pinnedGroup) {
+			m.message = "Only sessions and panes can be labeled (group by session with g)"
+			return m, nil
+		}
+		if item.kind == itemSession {
+			m.rename = &renameInput{key: item.session, session: true, input: []rune(m.labels.Session(item.session))}
+		} else {
+			target := m.verdicts[item.paneIdx].Target
+			m.rename = &renameInput{key: target, input: []rune(m.labels.Pane(target))}
+		}
+		m.message = ""
+		return m, nil
+
+	case "N":
+		// Attach a note to the selected pane
+		v := m.selectedVerdict()
+		if v == nil {
+			return m, nil
+		}
+		if m.labels == nil {
+			m.message = "Notes are unavailable (see the startup warning)"
+			return m, nil
+		}
+		m.note = &noteInput{target: v.Target, pid: v.PID, input: []rune(m.labels.Note(v.Target, v.PID))}
+		m.message = ""
+		return m, nil
+
+	case "e":
+		// Export the current verdicts to JSON and Markdown files
+		return m, m.exportCmd()
+
+	case "H":
+		// Write the selected pane's full scrollback to a file and page it
+		v := m.selectedVerdict()
+		if v == nil {
+			return m, nil
+		}
+		m.message = fmt.Sprintf("Capturing the transcript of %s...", v.Target)
+		return m, transcriptCmd(m.ctx, m.exportDir, v.Target)
+
+	case "ctrl+t":
+		// Show the selected pane's timeline this session
+		m.openTimeline()
+		return m, nil
+
+	case "D":
+		// Show the session statistics dashboard
+		m.dashboard = true
+		m.message = ""
+		return m, nil
+
+	case "T":
+		// Cycle color themes: dark -> light -> user-defined themes
+		m.themeName = m.themes.next(m.themeName)
+		m.theme = m.themes.theme(m.themeName)
+		m.s = newStyles(m.theme)
+		m.message = fmt.Sprintf("Theme: %s (%s)", m.themeName, colorProfileName(lipgloss.ColorProfile()))
+		return m, nil
+
+	case "]":
+		// Jump to the next blocked pane
+		m.jumpBlocked(1)
+		return m, nil
+
+	case "[":
+		// Jump to the previous blocked pane
+		m.jumpBlocked(-1)
+		return m, nil
+
+	case "ctrl+a":
+		// Send the recommended action and advance to the next blocked pane
+		return m, m.approveAndAdvance()
+
+	case "r":
+		// Rescan
+		m.scanning = true
+		m.message = ""
+		return m, m.doScan()
+
+	default:
+		// 1-9 send the selected pane's Nth action (as shown in the panel)
+		if key := msg.String(); len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			selected := m.selectedVerdict()
+			if selected == nil || !selected.Blocked {
+				return m, nil
+			}
+			v := m.withMacros(*selected)
+			if i := int(key[0] - '1'); i < len(v.Actions) {
+				return m, m.sendActionCmd(v.Target, v.Actions[i])
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// handleAnswerGroupKey handles keys in the answer-group view: a number picks
+// an action, then y sends it to every member that still shows the dialog.
+func (m *tuiModel) handleAnswerGroupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q":
+		m.answering = nil
+		return m, nil
+	}
+
+	g := m.answering
+	if m.answerChoice < 0 {
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if i := int(key[0] - '1'); i < len(g.actions) {
+				m.answerChoice = i
+			}
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "y", "enter":
+		action := g.actions[m.answerChoice]
+		tasks, skipped := g.broadcastTasks(m.verdicts, m.answerChoice)
+		m.answering = nil
+		if len(tasks) == 0 {
+			m.message = "No pane still shows this dialog; nothing sent"
+			return m, nil
+		}
+		send := func(reason string) tea.Cmd {
+			for _, t := range tasks {
+				m.invalidateCache(t.target)
+			}
+			return m.broadcastCmd(tasks, skipped, reason)
+		}
+		// Confirm if any member would get a high-risk answer.
+		action.Risk = highestRisk(tasks)
+		if m.needsConfirm(action) {
+			targets := make([]string, len(tasks))
+			for i, t := range tasks {
+				targets[i] = t.target
+			}
+			m.confirm = &confirmInput{action: action, targets: targets, send: send}
+			return m, nil
+		}
+		return m, send("")
+	case "n":
+		m.answerChoice = -1
+	}
+	return m, nil
+}
+
+// broadcastCmd sends the same action to every task's pane, records it in the
+// history with reason, and reports one summary message.
+func (m *tuiModel) broadcastCmd(tasks []nudgeTask, skipped []string, reason string) tea.Cmd {
+	history, nudger, scanner, ctx := m.history, m.nudger, m.scanner, m.ctx
+	return func() tea.Msg {
+		var failed []string
+		var nudges []nudgeTask
+		changed := false
+		for _, t := range tasks {
+			if t.check != nil {
+				if err := scanner.checkDialog(ctx, *t.check, model.Action{Keys: t.keys, Raw: t.raw}); err != nil {
+					skipped = append(skipped, t.target)
+					changed = true
+					continue
+				}
+			}
+			if err := nudger.NudgePane(t.target, t.keys, t.raw); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", t.target, err))
+				continue
+			}
+			nudges = append(nudges, t)
+			if err := history.Record(t.historyEntry(time.Now(), reason, false)); err != nil {
+				failed = append(failed, err.Error())
+			}
+		}
+		msg := fmt.Sprintf("sent '%s' (%s) to %d/%d panes", tasks[0].keys, tasks[0].label, len(nudges), len(tasks))
+		if len(skipped) > 0 {
+			msg += fmt.Sprintf(", skipped %d that changed", len(skipped))
+		}
+		messages := []string{msg}
+		messages = append(messages, failed...)
+		return nudgeResultMsg{messages: messages, sent: len(nudges), nudges: nudges, changed: changed}
+	}
+}
+
+func (m *tuiModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+
+	if m.confirm != nil {
+		return m.viewConfirm()
+	}
+	if m.answering != nil {
+		return m.viewAnswerGroup()
+	}
+	if m.templates != nil {
+		return m.viewTemplatePicker()
+	}
+	if m.control != nil {
+		return m.viewPaneControl()
+	}
+	if m.rawKeys != nil {
+		return m.viewRawKeyInput()
+	}
+	if m.textInput != nil {
+		return m.viewTextInput()
+	}
+	if m.diff != nil {
+		return m.viewDiff()
+	}
+	if m.rename != nil {
+		return m.viewRename()
+	}
+	if m.note != nil {
+		return m.viewNote()
+	}
+	if m.attach != nil {
+		return m.viewAttach()
+	}
+	if m.remember != nil {
+		return m.viewRemember()
+	}
+	if m.answersScreen != nil {
+		return m.viewAnswersScreen()
+	}
+	if m.profilePicker != nil {
+		return m.viewProfilePicker()
+	}
+	if m.cacheScreen != nil {
+		return m.viewCacheScreen()
+	}
+	if m.logView != nil {
+		return m.viewLogView()
+	}
+	if m.timeline != nil {
+		return m.viewTimeline()
+	}
+	if m.dashboard {
+		return m.viewDashboard()
+	}
+	if m.inline > 0 {
+		return m.viewInline()
+	}
+	return m.viewVerdictList()
+}
+
+// viewAnswerGroup renders the members of the answer group one under the
+// other with their dialog text, so they can be compared before answering
+// all of them at once.
+func (m *tuiModel) viewAnswerGroup() string {
+	g := m.answering
+	var b strings.Builder
+	b.WriteString(m.s.title.Render(fmt.Sprintf("Answer %d panes", len(g.targets))))
+	b.WriteString("  ")
+	b.WriteString(m.s.dim.Render(truncate(g.label, m.width-20)))
+	b.WriteString("\n\n")
+
+	byTarget := make(map[string]model.Verdict, len(m.verdicts))
+	for _, v := range m.verdicts {
+		byTarget[v.Target] = v
+	}
+
+	// Actions and prompt take len(actions)+4 lines; members share the rest.
+	budget := m.height - len(g.actions) - 7
+	perMember := 1
+	if budget >= len(g.targets)*3 {
+		perMember = 3
+	}
+	used := 0
+	for i, target := range g.targets {
+		if used+perMember > budget && i > 0 {
+			b.WriteString(m.s.dim.Render(fmt.Sprintf("  … %d more panes", len(g.targets)-i)))
+			b.WriteString("\n")
+			break
+		}
+		b.WriteString("  ")
+		b.WriteString(m.s.header.Render(target))
+		b.WriteString("\n")
+		used += perMember
+		detail := 0
+		for _, line := range strings.Split(byTarget[target].WaitingFor, "\n") {
+			if detail == perMember-1 {
+				break
+			}
+			if line = strings.Join(strings.Fields(line), " "); line == "" {
+				continue
+			}
+			b.WriteString("    ")
+			b.WriteString(m.s.dim.Render(truncate(line, m.width-5)))
+			b.WriteString("\n")
+			detail++
+		}
+	}
+	b.WriteString("\n")
+
+	for i, a := range g.actions {
+		line := fmt.Sprintf("  %d. [%s] %s", i+1, a.Risk, a.Label)
+		if i == m.answerChoice {
+			b.WriteString(m.s.selected.Render(line))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if m.answerChoice < 0 {
+		b.WriteString(m.styleHints(fmt.Sprintf("  1-%d choose action  esc cancel", len(g.actions))))
+	} else {
+		a := g.actions[m.answerChoice]
+		b.WriteString(m.s.blocked.Render(fmt.Sprintf("  Send '%s' (%s) to %d panes?", a.Keys, a.Label, len(g.targets))))
+		b.WriteString("  ")
+		b.WriteString(m.styleHints("y confirm  n back  esc cancel"))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m *tuiModel) viewVerdictList() string {
+	var b strings.Builder
+
+	// Header: title + keybindings + token usage
+	b.WriteString(m.s.title.Render("Pane Supervisor"))
+	b.WriteString("  ")
+	autoLabel := "a=auto:OFF"
+	dnd := m.doNotDisturb(time.Now())
+	if m.autoNudge {
+		autoLabel = fmt.Sprintf("a=auto:ON(%s)", m.autoNudgeMaxRisk)
+		if on, risk := m.autoNudgePolicy(time.Now()); !on {
+			autoLabel = "a=auto:PAUSED(dnd)"
+		} else if risk != m.autoNudgeMaxRisk {
+			autoLabel = fmt.Sprintf("a=auto:ON(%s, dnd)", risk)
+		}
+	}
+	if dnd {
+		b.WriteString(m.s.blocked.Render("DND"))
+		b.WriteString("  ")
+	}
 	filterLabel := fmt.Sprintf("f=%s", m.filter)
+	if m.groupBy != groupBySession {
+		filterLabel += fmt.Sprintf("  g=%s", m.groupBy)
+	}
+	if m.modelFilter != "" {
+		filterLabel += fmt.Sprintf("  m=%s", m.modelFilter)
+	}
+	if len(m.profiles) > 1 {
+		filterLabel += fmt.Sprintf("  P=%s", m.profile)
+	}
 	b.WriteString(m.styleHeaderHints(fmt.Sprintf("↑↓=nav  enter=jump  %s  %s  r=rescan  q=quit", filterLabel, autoLabel)))
 	if m.totalCacheHits > 0 {
 		b.WriteString("  ")
@@ -590,10 +1994,17 @@ func (m *tuiModel) viewVerdictList() string {
 	}
 	if m.scanning {
 		b.WriteString("  ")
-		b.WriteString(m.s.blocked.Render("scanning..."))
+		b.WriteString(m.s.blocked.Render(m.scanStatus()))
 	}
 	b.WriteString("\n")
 
+	if m.serverDown > 0 {
+		b.WriteString(m.s.err.Render(fmt.Sprintf("  %s tmux server not running — retrying in %s (attempt %d)", m.glyphs().warning,
+			serverRetryDelay(m.serverDown), m.serverDown)))
+		b.WriteString("\n")
+		return b.String()
+	}
+
 	if len(m.items) == 0 && m.scanning {
 		b.WriteString("  Scanning panes...\n")
 		return b.String()
@@ -605,19 +2016,24 @@ func (m *tuiModel) viewVerdictList() string {
 	}
 
 	// Layout: 2-column list (name | reason)
-	nameWidth := 10
-	for _, g := range m.groups {
-		if len(g.name)+6 > nameWidth {
-			nameWidth = len(g.name) + 6
-		}
+	nameWidth := m.nameColumnWidth()
+	sepWidth := len(listSeparator)
+
+	// Optional cluster sidebar on the right
+	sidebarWidth := 0
+	if m.showClusters && m.width >= minClusterLayoutWidth {
+		sidebarWidth = clusterSidebarWidth
 	}
-	nameWidth += 6 // icon + indent + cursor + padding
 
-	separator := " | "
-	sepWidth := len(separator)
+	// Action panel right of the list when it fits, else below it
+	layout := m.effectiveLayout(sidebarWidth)
+	panelWidth := 0
+	if layout == layoutRight {
+		panelWidth = m.rightPanelWidth()
+	}
 
 	// Reason gets all remaining width
-	reasonWidth := m.width - nameWidth - sepWidth
+	reasonWidth := m.width - nameWidth - sepWidth - sidebarWidth - panelWidth
 	if reasonWidth < 15 {
 		reasonWidth = 15
 	}
@@ -632,9 +2048,17 @@ func (m *tuiModel) viewVerdictList() string {
 		available = 6
 	}
 
-	listHeight := len(m.items)
-	if listHeight > available {
-		listHeight = available
+	// The selected pane's action panel. Below the list it takes up to half
+	// of the list budget plus a separator line.
+	m.actionPanelX, m.actionPanelY, m.panelActionRow = 0, 0, -1
+	var panel []string
+	if v := m.selectedVerdict(); v != nil {
+		if layout == layoutRight {
+			panel, m.panelActionRow = m.renderActionPanel(v, panelWidth, available)
+		} else {
+			panel, m.panelActionRow = m.renderActionPanel(v, m.width, available/2-1)
+			available -= len(panel) + 1
+		}
 	}
 
 	// Count totals
@@ -645,44 +2069,41 @@ func (m *tuiModel) viewVerdictList() string {
 		totalActive += g.active
 	}
 
-	// Calculate visible window for scrolling
-	maxVisible := listHeight
-	if maxVisible > len(m.items) {
-		maxVisible = len(m.items)
-	}
-
 	// Compute scroll window [start, end) that keeps cursor visible
-	start := 0
-	end := maxVisible
-	if m.cursor >= end {
-		end = m.cursor + 1
-		start = end - maxVisible
-	}
-	if start < 0 {
-		start = 0
-		end = maxVisible
-	}
+	start, end := m.scrollWindow(available)
 
 	// Store scroll offset for mouse hit testing
 	m.listStart = start
 
-	// Render list rows (2 columns: name | reason)
-	sep := m.s.header.Render(separator)
-	for i := start; i < end && i < len(m.items); i++ {
-		item := m.items[i]
-		var nameCol, reasonCol string
-
-		if item.kind == itemSession {
-			nameCol, reasonCol = m.renderSessionRow(item, i, nameWidth, reasonWidth)
-		} else {
-			nameCol, reasonCol = m.renderPaneRow(item, i, nameWidth, reasonWidth)
+	// Render list rows (2 columns: name | reason) into lines so the
+	// cluster sidebar can be placed next to them.
+	listWidth := nameWidth + sepWidth + reasonWidth
+	listLines := m.renderListRows(start, end, nameWidth, reasonWidth)
+	if layout == layoutRight && panel != nil {
+		for i := range panel {
+			panel[i] = padRight(panel[i], panelWidth-2)
 		}
-
-		b.WriteString(nameCol)
-		b.WriteString(sep)
-		b.WriteString(reasonCol)
+		m.actionPanelX = listWidth
+		listLines = strings.Split(strings.TrimSuffix(m.joinSidebar(listLines, panel, listWidth), "\n"), "\n")
+		listWidth += panelWidth
+	}
+	if sidebarWidth > 0 {
+		b.WriteString(m.joinSidebar(listLines, m.renderClusterSidebar(available, sidebarWidth), listWidth))
+	} else {
+		b.WriteString(strings.Join(listLines, "\n"))
 		b.WriteString("\n")
 	}
+	if layout == layoutBottom && panel != nil {
+		// Screen row of the separator: the header is row 0.
+		m.actionPanelY = 1 + len(listLines)
+		b.WriteString(m.s.header.Render(strings.Repeat(m.glyphs().rule, max(m.width-1, 1))))
+		b.WriteString("\n")
+		for _, line := range panel {
+			b.WriteString("  ")
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
 
 	// Summary line
 	visiblePanes := 0
@@ -712,7 +2133,206 @@ func (m *tuiModel) viewVerdictList() string {
 
 // buildHints returns a context-dependent keybinding hint line.
 func (m *tuiModel) buildHints() string {
-	return m.styleHints("  ↑↓ navigate  enter jump  →/l expand  ←/h collapse  r rescan  f filter  a auto-nudge  q quit")
+	return m.styleHints("  ↑↓ navigate  ]/[ next/prev blocked  ctrl+a approve & next  enter jump  →/l expand  ←/h collapse  t answer  o continue  O next task  . resend  d diff  y copy  e export  H transcript  r rescan  f filter  g group  m model  w preview  c clusters  s recommended  A answer all  R remember  M answers  C cache  E log  P profile  X kill/restart  K keys  i attach  L launch  D dashboard  ctrl+t timeline  n label  N note  p pin  z dnd  v layout  T theme  1-9 act  a auto-nudge  q quit")
+}
+
+// clusterSidebarWidth is the width of the cluster sidebar including its
+// left border; minClusterLayoutWidth is the narrowest terminal that fits it
+// next to a usable list.
+const (
+	clusterSidebarWidth   = 40
+	minClusterLayoutWidth = 100
+)
+
+// visibleClusters groups the blocked panes currently shown in the list
+// (after display and model filters) by what they are waiting on.
+func (m *tuiModel) visibleClusters() []summary.Cluster {
+	return summary.BuildClusters(m.visibleVerdicts())
+}
+
+// visibleVerdicts returns the verdicts of the panes currently shown in the
+// list (after display and model filters), in list order.
+func (m *tuiModel) visibleVerdicts() []model.Verdict {
+	var visible []model.Verdict
+	for _, g := range m.groups {
+		for _, vi := range g.verdicts {
+			visible = append(visible, m.verdicts[vi])
+		}
+	}
+	return visible
+}
+
+// renderClusterSidebar renders at most height lines of "count label" rows,
+// each exactly width-2 columns wide (the border is added by joinSidebar).
+func (m *tuiModel) renderClusterSidebar(height, width int) []string {
+	inner := width - 2
+	lines := []string{m.s.header.Render(padRight("Blocked on", inner))}
+	clusters := m.visibleClusters()
+	if len(clusters) == 0 {
+		lines = append(lines, m.s.dim.Render(padRight("nothing blocked", inner)))
+	}
+	for i, c := range clusters {
+		if len(lines) == height-1 && i < len(clusters)-1 {
+			lines = append(lines, m.s.dim.Render(padRight(fmt.Sprintf("… %d more", len(clusters)-i), inner)))
+			break
+		}
+		count := m.s.blocked.Render(fmt.Sprintf("%3d", c.Count))
+		lines = append(lines, count+" "+padRight(truncate(c.Label, inner-5), inner-4))
+	}
+	return lines
+}
+
+// joinSidebar places sidebar lines to the right of list lines, padding the
+// shorter column so both line up.
+func (m *tuiModel) joinSidebar(list, sidebar []string, listWidth int) string {
+	n := len(list)
+	if len(sidebar) > n {
+		n = len(sidebar)
+	}
+	border := m.s.header.Render(m.glyphs().border)
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i < len(list) {
+			b.WriteString(list[i])
+		} else {
+			b.WriteString(padRight("", listWidth))
+		}
+		b.WriteString(border)
+		if i < len(sidebar) {
+			b.WriteString(sidebar[i])
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// recommendedSuffix returns the recommended action of a blocked pane for
+// its row when enabled, e.g. " · [1] approve (med)", or "".
+func (m *tuiModel) recommendedSuffix(v model.Verdict) string {
+	if !m.showRecommended || !v.Blocked || v.Recommended < 0 || v.Recommended >= len(v.Actions) {
+		return ""
+	}
+	a := v.Actions[v.Recommended]
+	suffix := fmt.Sprintf(" · [%d] %s", v.Recommended+1, a.Label)
+	switch a.Risk {
+	case "low", "high":
+		suffix += " (" + a.Risk + ")"
+	case "medium":
+		suffix += " (med)"
+	}
+	return suffix
+}
+
+// waitingForPreview returns a one-line preview of what a blocked verdict is
+// waiting for: the structured question dialog when the parser provided one,
+// else the first non-empty line of WaitingFor, whitespace-collapsed (for
+// permission dialogs that line names the command or file, which says more
+// than the generic "Do you want to proceed?"). Returns "" when there is
+// nothing to add to the reason column (not blocked, empty, or identical to
+// the reason).
+func waitingForPreview(v model.Verdict) string {
+	if !v.Blocked {
+		return ""
+	}
+	if v.Dialog != nil && v.Dialog.Kind != model.DialogPermission {
+		if preview := dialogPreview(v.Dialog); preview != "" {
+			return preview
+		}
+	}
+	for _, line := range strings.Split(v.WaitingFor, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		if line == strings.Join(strings.Fields(v.Reason), " ") {
+			return ""
+		}
+		return line
+	}
+	return ""
+}
+
+// dialogPreview renders a dialog on one line: the question, the options
+// (with checkbox state for multi-select), and the position of the active
+// tab in a multi-question form.
+func dialogPreview(d *model.Dialog) string {
+	var parts []string
+	if len(d.Tabs) > 0 && d.ActiveTab >= 0 && d.ActiveTab < len(d.Tabs) {
+		parts = append(parts, fmt.Sprintf("[%s %d/%d]", d.Tabs[d.ActiveTab], d.ActiveTab+1, len(d.Tabs)))
+	} else if len(d.Tabs) > 0 {
+		parts = append(parts, fmt.Sprintf("[%d tabs]", len(d.Tabs)))
+	}
+	if q := strings.Join(strings.Fields(d.Question), " "); q != "" {
+		parts = append(parts, q)
+	}
+	if len(d.Options) > 0 {
+		labels := make([]string, len(d.Options))
+		for i, o := range d.Options {
+			label := o.Label
+			if d.MultiSelect {
+				box := "[ ]"
+				if o.Checked {
+					box = "[✓]"
+				}
+				label = box + " " + label
+			}
+			if o.Selected {
+				label = "❯" + label
+			}
+			labels[i] = label
+		}
+		parts = append(parts, "("+strings.Join(labels, " / ")+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// itemHeight returns the number of screen lines item i occupies: 2 for a
+// pane row with a WaitingFor preview, otherwise 1.
+func (m *tuiModel) itemHeight(i int) int {
+	if !m.showWaitingFor || i < 0 || i >= len(m.items) || m.items[i].kind != itemPane {
+		return 1
+	}
+	if waitingForPreview(m.verdicts[m.items[i].paneIdx]) == "" {
+		return 1
+	}
+	return 2
+}
+
+// scrollWindow returns the item range [start, end) that fits in budget
+// screen lines while keeping the cursor visible. When the cursor is below
+// the first page, the window scrolls so the cursor is the last visible item.
+func (m *tuiModel) scrollWindow(budget int) (int, int) {
+	end, used := 0, 0
+	for end < len(m.items) && used+m.itemHeight(end) <= budget {
+		used += m.itemHeight(end)
+		end++
+	}
+	if m.cursor < end || m.cursor >= len(m.items) {
+		return 0, end
+	}
+	start := m.cursor + 1
+	used = 0
+	for start > 0 && used+m.itemHeight(start-1) <= budget {
+		used += m.itemHeight(start - 1)
+		start--
+	}
+	return start, m.cursor + 1
+}
+
+// itemAtRow maps a screen line within the list (0 = first list line) to an
+// item index, accounting for the scroll offset and multi-line rows.
+// Returns -1 if the row is outside the list.
+func (m *tuiModel) itemAtRow(row int) int {
+	if row < 0 {
+		return -1
+	}
+	for i := m.listStart; i < len(m.items); i++ {
+		row -= m.itemHeight(i)
+		if row < 0 {
+			return i
+		}
+	}
+	return -1
 }
 
 // styleHints renders a hint string with key symbols in text color and
@@ -775,41 +2395,35 @@ func (m *tuiModel) renderSessionRow(item listItem, idx, nameWidth, reasonWidth i
 	}
 
 	// Session icon: worst status across panes
-	icon := m.s.dim.Render("·")
+	g := m.glyphs()
+	icon := m.s.dim.Render(g.idle)
 	if group != nil {
 		if group.blocked > 0 {
-			icon = m.s.blocked.Render("⚠")
+			icon = m.s.blocked.Render(g.blocked)
 		} else if group.active > 0 {
-			icon = m.s.active.Render("✓")
+			icon = m.s.active.Render(g.active)
 		}
 	}
 
 	// Expand/collapse indicator
-	arrow := "▶"
+	arrow := g.collapsed
 	if m.expanded[item.session] {
-		arrow = "▼"
+		arrow = g.expanded
 	}
 
 	// Session summary in the reason column
 	var reason string
 	if group != nil {
-		parts := []string{fmt.Sprintf("%d pane", len(group.verdicts))}
-		if len(group.verdicts) != 1 {
-			parts[0] += "s"
-		}
-		if group.blocked > 0 {
-			parts = append(parts, fmt.Sprintf("%d blocked", group.blocked))
-		}
-		reason = strings.Join(parts, ", ")
+		reason = groupSummary(len(group.verdicts), group.blocked)
 	}
 
 	var nameCol, reasonCol string
 	if idx == m.cursor {
 		nameCol = m.s.selected.Render(padRight(
-			fmt.Sprintf("  %s %s %s", arrow, sessionIcon(group), item.session), nameWidth))
+			fmt.Sprintf("%2s%s %s %s", g.pointer+" ", arrow, m.sessionIcon(group), m.groupDisplayName(item.session)), nameWidth))
 		reasonCol = m.s.selected.Render(padRight(reason, reasonWidth))
 	} else {
-		nameCol = padRight(fmt.Sprintf("  %s %s %s", arrow, icon, item.session), nameWidth)
+		nameCol = padRight(fmt.Sprintf("  %s %s %s", arrow, icon, m.groupDisplayName(item.session)), nameWidth)
 		reasonCol = m.s.dim.Render(padRight(reason, reasonWidth))
 	}
 
@@ -817,66 +2431,140 @@ func (m *tuiModel) renderSessionRow(item listItem, idx, nameWidth, reasonWidth i
 }
 
 func (m *tuiModel) renderPaneRow(item listItem, idx, nameWidth, reasonWidth int) (string, string) {
+	g := m.glyphs()
 	v := m.verdicts[item.paneIdx]
 
-	icon := m.s.active.Render("✓")
+	icon := m.s.active.Render(g.active)
 	if v.Blocked {
-		icon = m.s.blocked.Render("⚠")
+		icon = m.s.blocked.Render(g.blocked)
 	}
 	if v.Agent == "error" {
-		icon = m.s.err.Render("✗")
+		icon = m.s.err.Render(g.failed)
 	}
 	if v.Agent == "not_an_agent" {
-		icon = m.s.dim.Render("·")
+		icon = m.s.dim.Render(g.idle)
 	}
 
-	// Show pane target (e.g. ":0.1") indented under the session
-	paneLabel := fmt.Sprintf(":%d.%d", v.Window, v.Pane)
+	// Show pane target (e.g. ":0.1") and label indented under the session
+	paneLabel := m.paneDisplayName(v)
 
 	// Sanitize reason: collapse newlines/tabs to spaces and truncate.
 	// Parsers may return multi-line reasons or verbose descriptions
 	// which would break the row-based TUI layout.
 	reason := strings.Join(strings.Fields(v.Reason), " ")
+	if idle := m.idleReason(v); idle != "" {
+		reason = idle
+	}
+	reason = withIdleState(reason, v)
+	if v.Model != "" {
+		reason = v.Model + " · " + reason
+	}
+	// The recommended action is kept whole; the reason gives way to it.
+	if suffix := m.recommendedSuffix(v); suffix != "" {
+		reason = truncate(reason, max(reasonWidth-1-runewidth.StringWidth(suffix), 10)) + suffix
+	}
 	reason = truncate(reason, reasonWidth-1)
 
+	indent := paneIndent(item)
 	var nameCol, reasonCol string
 	if idx == m.cursor {
 		nameCol = m.s.selected.Render(padRight(
-			fmt.Sprintf("      %s %s", iconText(v), paneLabel), nameWidth))
+			fmt.Sprintf("%*s%s %s", len(indent), g.pointer+" ", m.iconText(v), paneLabel), nameWidth))
 		reasonCol = m.s.selected.Render(padRight(reason, reasonWidth))
 	} else {
-		nameCol = padRight(fmt.Sprintf("      %s %s", icon, paneLabel), nameWidth)
+		nameCol = padRight(fmt.Sprintf("%s%s %s", indent, icon, paneLabel), nameWidth)
 		reasonCol = padRight(reason, reasonWidth)
 	}
 
 	return nameCol, reasonCol
 }
 
+// listSeparator separates the name and reason columns of the list.
+const listSeparator = " | "
+
+// nameColumnWidth is the width of the list's name column: the longest
+// session name, or window or pane name in an expanded session, plus indent
+// and icon.
+func (m *tuiModel) nameColumnWidth() int {
+	nameWidth := 10
+	for _, g := range m.groups {
+		nameWidth = max(nameWidth, runewidth.StringWidth(m.groupDisplayName(g.name))+6)
+		if !m.expanded[g.name] {
+			continue
+		}
+		if len(g.windows) == 0 {
+			for _, vi := range g.verdicts {
+				nameWidth = max(nameWidth, runewidth.StringWidth(m.paneDisplayName(m.verdicts[vi]))+2)
+			}
+			continue
+		}
+		for _, w := range g.windows {
+			nameWidth = max(nameWidth, runewidth.StringWidth(windowDisplayName(w))+8)
+			if m.expanded[windowKey(g.name, w.window)] {
+				for _, vi := range w.verdicts {
+					nameWidth = max(nameWidth, runewidth.StringWidth(m.paneDisplayName(m.verdicts[vi]))+4)
+				}
+			}
+		}
+	}
+	return nameWidth + 6 // icon + indent + cursor + padding
+}
+
+// renderListRows renders items [start, end) as list lines (2 columns:
+// name | reason), with a WaitingFor preview line under a pane row when
+// enabled.
+func (m *tuiModel) renderListRows(start, end, nameWidth, reasonWidth int) []string {
+	var rows []string
+	sep := m.s.header.Render(listSeparator)
+	for i := start; i < end && i < len(m.items); i++ {
+		item := m.items[i]
+		var nameCol, reasonCol string
+
+		switch item.kind {
+		case itemSession:
+			nameCol, reasonCol = m.renderSessionRow(item, i, nameWidth, reasonWidth)
+		case itemWindow:
+			nameCol, reasonCol = m.renderWindowRow(item, i, nameWidth, reasonWidth)
+		default:
+			nameCol, reasonCol = m.renderPaneRow(item, i, nameWidth, reasonWidth)
+		}
+		rows = append(rows, nameCol+sep+reasonCol)
+
+		if m.itemHeight(i) > 1 {
+			preview := waitingForPreview(m.verdicts[item.paneIdx])
+			rows = append(rows, padRight("", nameWidth)+sep+m.s.dim.Render(padRight(truncate(preview, reasonWidth-1), reasonWidth)))
+		}
+	}
+	return rows
+}
+
 // sessionIcon returns an icon string for a session group.
-func sessionIcon(g *sessionGroup) string {
-	if g == nil {
-		return "·"
+func (m *tuiModel) sessionIcon(group *sessionGroup) string {
+	g := m.glyphs()
+	if group == nil {
+		return g.idle
 	}
-	if g.blocked > 0 {
-		return "⚠"
+	if group.blocked > 0 {
+		return g.blocked
 	}
-	if g.active > 0 {
-		return "✓"
+	if group.active > 0 {
+		return g.active
 	}
-	return "·"
+	return g.idle
 }
 
-func iconText(v model.Verdict) string {
+func (m *tuiModel) iconText(v model.Verdict) string {
+	g := m.glyphs()
 	if v.Blocked {
-		return "⚠"
+		return g.blocked
 	}
 	if v.Agent == "error" {
-		return "✗"
+		return g.failed
 	}
 	if v.Agent == "not_an_agent" {
-		return "·"
+		return g.idle
 	}
-	return "✓"
+	return g.active
 }
 
 // riskOrdinal maps risk levels to ordinal values for comparison.
@@ -900,19 +2588,42 @@ func riskWithinThreshold(actionRisk, maxRisk string) bool {
 
 // nudgeTask describes a single auto-nudge action to perform asynchronously.
 type nudgeTask struct {
-	target string
-	keys   string
-	raw    bool
-	label  string
+	target     string
+	agent      string
+	keys       string
+	raw        bool
+	label      string
+	risk       string
+	remembered bool   // a remembered answer (R) rather than auto-nudge
+	state      string // the pane's blockedState when the task was made
+	typed      bool   // free text or raw keys rather than an offered action
+	followUp   bool   // the action may open a follow-up dialog (model.Action.FollowUp)
+	// check is the verdict the task was made for, when the pane must still
+	// show it before the keys are sent (checkDialog): for option keys and
+	// every automatic send.
+	check *model.Verdict
+}
+
+// historyEntry describes the task for the history log.
+func (t nudgeTask) historyEntry(now time.Time, reason string, auto bool) HistoryEntry {
+	if t.remembered && reason == "" {
+		reason = "remembered answer"
+	}
+	return HistoryEntry{Time: now, Target: t.target, Agent: t.agent, Action: t.label,
+		Keys: t.keys, Risk: t.risk, Reason: reason, Auto: auto}
 }
 
-// autoNudgeCmd returns a tea.Cmd that sends the recommended action for each
-// blocked pane whose recommended action is within the configured risk
-// threshold. The actual tmux send-keys calls (which include subprocess
-// invocations and deliberate sleeps) run in a goroutine so they don't block
-// the TUI Update loop.
+// autoNudgeCmd returns a tea.Cmd that answers blocked panes showing a
+// dialog with a remembered answer and, when auto-nudge is on, sends the
+// recommended action for each other blocked pane whose recommended action
+// is within the configured risk threshold. Panes shown in an attached tmux
+// client are skipped (see viewedPanes), and nothing is sent while another
+// supervisor holds the auto-nudge lease (see Instance). The actual tmux
+// send-keys calls (which include subprocess invocations and deliberate
+// sleeps) run in a goroutine so they don't block the TUI Update loop.
 func (m *tuiModel) autoNudgeCmd() tea.Cmd {
-	if !m.autoNudge {
+	autoNudge, maxRisk := m.autoNudgePolicy(time.Now())
+	if !autoNudge && m.answerMemory == nil {
 		return nil
 	}
 
@@ -920,21 +2631,36 @@ func (m *tuiModel) autoNudgeCmd() tea.Cmd {
 	// mutate here because Update runs on a single goroutine).
 	var tasks []nudgeTask
 	for _, v := range m.verdicts {
-		if v.Agent == "not_an_agent" || v.Agent == "error" || !v.Blocked {
+		// Never answer generic shell prompts (passwords, [y/N]) by
+		// themselves.
+		if !parser.IsAgentBlock(v) {
 			continue
 		}
-		if len(v.Actions) == 0 || v.Recommended >= len(v.Actions) {
+		if a, ok := m.answerMemory.Lookup(v); ok {
+			tasks = append(tasks, nudgeTask{target: v.Target, agent: v.Agent, keys: a.Keys, raw: a.Raw,
+				label: a.Label, risk: a.Risk, remembered: true, state: blockedState(v), check: &v})
+			m.invalidateCache(v.Target)
+			continue
+		}
+		if !autoNudge {
+			continue
+		}
+		if len(v.Actions) == 0 || v.Recommended < 0 || v.Recommended >= len(v.Actions) {
 			continue
 		}
 		action := v.Actions[v.Recommended]
-		if !riskWithinThreshold(action.Risk, m.autoNudgeMaxRisk) {
+		if !riskWithinThreshold(action.Risk, maxRisk) {
 			continue
 		}
 		tasks = append(tasks, nudgeTask{
 			target: v.Target,
+			agent:  v.Agent,
 			keys:   action.Keys,
 			raw:    action.Raw,
 			label:  action.Label,
+			risk:   action.Risk,
+			state:  blockedState(v),
+			check:  &v,
 		})
 		// Invalidate cache so the next scan re-evaluates this pane
 		if m.scanner.Cache != nil {
@@ -947,17 +2673,122 @@ func (m *tuiModel) autoNudgeCmd() tea.Cmd {
 		return nil
 	}
 
+	history, nudger, viewedPanes, ctx, log := m.history, m.nudger, m.viewedPanes, m.ctx, m.logger()
+	instance, leaseTTL, scanner := m.instance, 3*m.refreshInterval, m.scanner
 	return func() tea.Msg {
+		if instance != nil {
+			ok, holder, err := instance.AcquireNudgeLease(ctx, time.Now(), leaseTTL)
+			if err != nil {
+				log.Warn("cannot take the auto-nudge lease", "err", err)
+			} else if !ok {
+				log.Info("auto-nudge left to another supervisor", "holder", holder)
+				return nudgeResultMsg{messages: []string{"auto-nudge left to " + holder}, auto: true}
+			}
+		}
 		var messages []string
+		var viewed []string
+		if viewedPanes != nil {
+			var err error
+			if viewed, err = viewedPanes(ctx); err != nil {
+				log.Warn("cannot list viewed panes", "err", err)
+			}
+		}
+		var nudges []nudgeTask
+		changed := false
 		for _, t := range tasks {
-			err := NudgePane(t.target, t.keys, t.raw)
+			if slices.Contains(viewed, t.target) {
+				log.Info("auto-nudge skipped a viewed pane", "target", t.target, "keys", t.keys)
+				messages = append(messages, fmt.Sprintf("auto-nudge skipped %s: it is in view", t.target))
+				continue
+			}
+			// Nobody reviews an automatic send, so the pane must still show
+			// what the keys were chosen for.
+			if err := scanner.checkDialog(ctx, *t.check, model.Action{Keys: t.keys, Raw: t.raw}); err != nil {
+				log.Info("auto-nudge skipped a changed pane", "target", t.target, "keys", t.keys, "err", err)
+				messages = append(messages, fmt.Sprintf("auto-nudge skipped %s: %v", t.target, err))
+				changed = true
+				continue
+			}
+			err := nudger.NudgePane(t.target, t.keys, t.raw)
 			if err != nil {
 				messages = append(messages, fmt.Sprintf("auto-nudge %s failed: %v", t.target, err))
 			} else {
-				messages = append(messages, fmt.Sprintf("auto-nudged '%s' to %s (%s)", t.keys, t.target, t.label))
+				verb := "auto-nudged"
+				if t.remembered {
+					verb = "answered remembered"
+				}
+				messages = append(messages, fmt.Sprintf("%s '%s' to %s (%s)", verb, t.keys, t.target, t.label))
+				nudges = append(nudges, t)
+				if err := history.Record(t.historyEntry(time.Now(), "", true)); err != nil {
+					messages = append(messages, err.Error())
+				}
 			}
 		}
-		return nudgeResultMsg{messages: messages}
+		return nudgeResultMsg{messages: messages, sent: len(nudges), auto: true, nudges: nudges, changed: changed}
+	}
+}
+
+// announceCmd returns a tea.Cmd that speaks announcements for panes that
+// became blocked in the latest scan. Speech runs in a goroutine because TTS
+// programs block until the sentence has been spoken.
+func (m *tuiModel) announceCmd(ts []Transition) tea.Cmd {
+	if m.announcer == nil || m.doNotDisturb(time.Now()) {
+		return nil
+	}
+	texts := m.announcer.Announce(ts)
+	if len(texts) == 0 {
+		return nil
+	}
+	announcer := m.announcer
+	return func() tea.Msg {
+		return speechResultMsg{err: announcer.SpeakAll(texts)}
+	}
+}
+
+// transitionCmd returns a tea.Cmd that logs the latest scan's transitions
+// to the history and runs the transition hook in the background.
+func (m *tuiModel) transitionCmd(ts []Transition) tea.Cmd {
+	if len(ts) == 0 || (m.history == nil && m.transitionHook == nil) {
+		return nil
+	}
+	history, hook, ctx := m.history, m.transitionHook, m.ctx
+	if m.doNotDisturb(time.Now()) {
+		hook = nil
+	}
+	return func() tea.Msg {
+		var logErr error
+		for _, t := range ts {
+			if err := history.Record(t.historyEntry()); err != nil && logErr == nil {
+				logErr = err
+			}
+		}
+		if err := hook.Run(ctx, ts); err != nil {
+			return transitionResultMsg{err: err}
+		}
+		return transitionResultMsg{err: logErr}
+	}
+}
+
+// badgeCmd returns a tea.Cmd that updates the tmux badges to the latest
+// scan in the background.
+func (m *tuiModel) badgeCmd() tea.Cmd {
+	if m.badges == nil {
+		return nil
+	}
+	badges, ctx := m.badges, m.ctx
+	verdicts := append([]model.Verdict(nil), m.verdicts...)
+	return func() tea.Msg {
+		return badgeResultMsg{err: badges.Update(ctx, verdicts)}
+	}
+}
+
+// toggleAutoNudge turns auto-nudge on or off (a).
+func (m *tuiModel) toggleAutoNudge() {
+	m.autoNudge = !m.autoNudge
+	if m.autoNudge {
+		m.message = fmt.Sprintf("Auto-nudge ON (max risk: %s)", m.autoNudgeMaxRisk)
+	} else {
+		m.message = "Auto-nudge OFF"
 	}
 }
 
@@ -973,17 +2804,18 @@ func jumpToPane(target string) string {
 	return ""
 }
 
-// truncate cuts a string to at most maxLen runes (not bytes), appending "..."
-// when truncation occurs. This is safe for multi-byte UTF-8 strings from parser output.
+// truncate cuts a string to at most maxLen terminal columns, appending
+// "..." when truncation occurs. Widths are display widths, so CJK and emoji
+// count two columns; East Asian ambiguous-width runes follow the locale
+// (go-runewidth reads it from the environment).
 func truncate(s string, maxLen int) string {
-	runes := []rune(s)
-	if len(runes) <= maxLen {
+	if runewidth.StringWidth(s) <= maxLen {
 		return s
 	}
 	if maxLen <= 3 {
-		return string(runes[:maxLen])
+		return runewidth.Truncate(s, maxLen, "")
 	}
-	return string(runes[:maxLen-3]) + "..."
+	return runewidth.Truncate(s, maxLen, "...")
 }
 
 // padRight pads a string with spaces to reach the desired visible width.
@@ -995,7 +2827,8 @@ func padRight(s string, width int) string {
 	return s + strings.Repeat(" ", width-visible)
 }
 
-// visibleLen returns the visible length of a string, ignoring ANSI escape sequences.
+// visibleLen returns the display width of a string in terminal columns,
+// ignoring ANSI escape sequences.
 func visibleLen(s string) int {
 	n := 0
 	inEscape := false
@@ -1010,7 +2843,7 @@ func visibleLen(s string) int {
 			}
 			continue
 		}
-		n++
+		n += runewidth.RuneWidth(r)
 	}
 	return n
 }
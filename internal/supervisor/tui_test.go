@@ -1,10 +1,17 @@
 package supervisor
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+	"github.com/timvw/pane-patrol/internal/parser"
 )
 
 // newTestModel creates a tuiModel with a single blocked verdict, cursor on
@@ -212,6 +219,54 @@ func TestMouse_ClickOnPaneJumps(t *testing.T) {
 	}
 }
 
+func TestMouse_ClickActionSelect(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.clickSelects = true
+	m.listStart = 0
+	msg := tea.MouseMsg{X: 5, Y: 2, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+	_, _ = m.handleMouse(msg)
+
+	if m.cursor != 1 {
+		t.Errorf("expected cursor=1 (pane item), got %d", m.cursor)
+	}
+	if m.message != "" {
+		t.Errorf("single click should only select, got message %q", m.message)
+	}
+}
+
+func TestClickJumps(t *testing.T) {
+	now := time.Now()
+	click := tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+
+	m := &tuiModel{}
+	if !m.clickJumps(1, click, now) {
+		t.Error("click_action jump: single click should jump")
+	}
+
+	m = &tuiModel{clickSelects: true}
+	if m.clickJumps(1, click, now) {
+		t.Error("first click should only select")
+	}
+	if !m.clickJumps(1, click, now.Add(200*time.Millisecond)) {
+		t.Error("second click within the interval should jump")
+	}
+	if m.clickJumps(1, click, now.Add(300*time.Millisecond)) {
+		t.Error("third click should start a new double click")
+	}
+	if m.clickJumps(1, click, now.Add(time.Second)) {
+		t.Error("slow second click should only select")
+	}
+	if m.clickJumps(2, click, now.Add(1100*time.Millisecond)) {
+		t.Error("quick click on another pane should only select")
+	}
+
+	alt := click
+	alt.Alt = true
+	if !m.clickJumps(3, alt, now.Add(5*time.Second)) {
+		t.Error("alt-click should jump")
+	}
+}
+
 func TestMouse_HoverMovesCursor(t *testing.T) {
 	m := &tuiModel{
 		verdicts: []model.Verdict{
@@ -423,3 +478,441 @@ func TestAutoExpand_AllFilterExpandsSessions(t *testing.T) {
 		t.Fatalf("expected all sessions expanded in all filter")
 	}
 }
+
+func TestModelFilter_CyclesThroughKnownModels(t *testing.T) {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "a:0.0", Session: "a", Agent: "opencode", Blocked: true, Model: "gpt-4o"},
+			{Target: "a:0.1", Session: "a", Agent: "codex", Blocked: true, Model: "gpt-5-codex"},
+			{Target: "b:0.0", Session: "b", Agent: "claude_code", Blocked: true},
+		},
+		expanded:        make(map[string]bool),
+		manualCollapsed: make(map[string]bool),
+	}
+	m.rebuildGroups()
+
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}}
+	_, _ = m.handleVerdictListKey(msg)
+	if m.modelFilter != "gpt-4o" {
+		t.Fatalf("expected model filter gpt-4o, got %q", m.modelFilter)
+	}
+	paneCount := 0
+	for _, it := range m.items {
+		if it.kind == itemPane {
+			paneCount++
+			if got := m.verdicts[it.paneIdx].Model; got != "gpt-4o" {
+				t.Errorf("expected only gpt-4o panes, got %q", got)
+			}
+		}
+	}
+	if paneCount != 1 {
+		t.Errorf("expected 1 visible pane, got %d", paneCount)
+	}
+
+	_, _ = m.handleVerdictListKey(msg)
+	if m.modelFilter != "gpt-5-codex" {
+		t.Fatalf("expected model filter gpt-5-codex, got %q", m.modelFilter)
+	}
+
+	_, _ = m.handleVerdictListKey(msg)
+	if m.modelFilter != "" {
+		t.Fatalf("expected model filter to wrap to all, got %q", m.modelFilter)
+	}
+}
+
+func TestGenericPrompt_OnlyVisibleInAllFilter(t *testing.T) {
+	verdicts := []model.Verdict{
+		{Target: "a:0.0", Session: "a", Agent: parser.AgentGenericPrompt, Blocked: true,
+			Actions: []model.Action{{Keys: "n Enter", Label: "answer no", Risk: "low", Raw: true}}},
+	}
+	for _, tt := range []struct {
+		filter displayFilter
+		want   int
+	}{
+		{filterBlocked, 0},
+		{filterAgents, 0},
+		{filterAll, 1},
+	} {
+		m := &tuiModel{
+			verdicts:        verdicts,
+			expanded:        make(map[string]bool),
+			manualCollapsed: make(map[string]bool),
+			filter:          tt.filter,
+		}
+		m.rebuildGroups()
+		panes := 0
+		for _, it := range m.items {
+			if it.kind == itemPane {
+				panes++
+			}
+		}
+		if panes != tt.want {
+			t.Errorf("filter %s: got %d panes, want %d", tt.filter, panes, tt.want)
+		}
+	}
+}
+
+func TestAutoNudge_SkipsGenericPrompt(t *testing.T) {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "a:0.0", Session: "a", Agent: parser.AgentGenericPrompt, Blocked: true,
+				Actions: []model.Action{{Keys: "n Enter", Label: "answer no", Risk: "low", Raw: true}}},
+		},
+		scanner:          &Scanner{},
+		autoNudge:        true,
+		autoNudgeMaxRisk: "high",
+	}
+	if cmd := m.autoNudgeCmd(); cmd != nil {
+		t.Error("expected no auto-nudge for generic shell prompts")
+	}
+}
+
+func TestAutoNudge_SkipsViewedPanes(t *testing.T) {
+	allow := []model.Action{{Keys: "Enter", Label: "allow once", Risk: "low", Raw: true}}
+	var sent []string
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "a:0.0", Session: "a", Agent: "opencode", Blocked: true, Actions: allow},
+			{Target: "b:0.0", Session: "b", Agent: "opencode", Blocked: true, Actions: allow},
+		},
+		scanner:          &Scanner{},
+		autoNudge:        true,
+		autoNudgeMaxRisk: "low",
+		nudger: &Nudger{SendKeys: func(target, _, _ string) error {
+			sent = append(sent, target)
+			return nil
+		}, Sleep: func(time.Duration) {}},
+		viewedPanes: func(context.Context) ([]string, error) { return []string{"b:0.0"}, nil },
+	}
+	msg := m.autoNudgeCmd()().(nudgeResultMsg)
+	if !slices.Equal(sent, []string{"a:0.0"}) || msg.sent != 1 {
+		t.Errorf("sent to %v, want only a:0.0", sent)
+	}
+	if !slices.Contains(msg.messages, "auto-nudge skipped b:0.0: it is in view") {
+		t.Errorf("messages = %q", msg.messages)
+	}
+
+	// Without the lookup (--auto-nudge-viewed) every pane is nudged.
+	sent = nil
+	m.viewedPanes = nil
+	m.autoNudgeCmd()()
+	if len(sent) != 2 {
+		t.Errorf("sent to %v, want both panes", sent)
+	}
+}
+
+func TestAutoNudge_SkipsPanesWithoutRecommendation(t *testing.T) {
+	m := &tuiModel{
+		verdicts: []model.Verdict{{Target: "a:0.0", Session: "a", Agent: "codex", Blocked: true, Reason: "idle at prompt",
+			Actions: []model.Action{{Keys: "Enter", Label: "continue", Risk: "low", Raw: true}}, Recommended: -1}},
+		scanner:          &Scanner{},
+		autoNudge:        true,
+		autoNudgeMaxRisk: "low",
+	}
+	if cmd := m.autoNudgeCmd(); cmd != nil {
+		t.Error("expected nothing to send when idle_actions recommends nothing")
+	}
+}
+
+// --- WaitingFor preview rows ---
+
+func previewTestModel() *tuiModel {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "a:0.0", Session: "a", Agent: "opencode", Blocked: true,
+				Reason: "permission dialog", WaitingFor: "\n  Edit  src/main.go\n  extra"},
+			{Target: "b:0.0", Session: "b", Agent: "claude_code", Blocked: true,
+				Reason: "idle at prompt", WaitingFor: "idle at prompt"},
+		},
+		expanded:        map[string]bool{"a": true, "b": true},
+		manualCollapsed: make(map[string]bool),
+		width:           120,
+		height:          40,
+		showWaitingFor:  true,
+	}
+	m.rebuildGroups()
+	return m
+}
+
+func TestWaitingForPreview_FirstLineOnly(t *testing.T) {
+	m := previewTestModel()
+	if got := waitingForPreview(m.verdicts[0]); got != "Edit src/main.go" {
+		t.Errorf("preview: got %q, want %q", got, "Edit src/main.go")
+	}
+	// Identical to the reason: nothing to add.
+	if got := waitingForPreview(m.verdicts[1]); got != "" {
+		t.Errorf("preview: got %q, want empty", got)
+	}
+}
+
+func TestWaitingForPreview_StructuredDialog(t *testing.T) {
+	v := model.Verdict{
+		Blocked:    true,
+		Reason:     "question dialog waiting for answer",
+		WaitingFor: "Which checks?\n1. [✓] lint\n2. [ ] test",
+		Dialog: &model.Dialog{
+			Kind:        model.DialogQuestion,
+			Question:    "Which checks?",
+			MultiSelect: true,
+			Options: []model.DialogOption{
+				{Label: "lint", Checked: true, Selected: true},
+				{Label: "test"},
+			},
+			Tabs:      []string{"Checks", "Confirm"},
+			ActiveTab: -1,
+		},
+	}
+	want := "[2 tabs] Which checks? (❯[✓] lint / [ ] test)"
+	if got := waitingForPreview(v); got != want {
+		t.Errorf("preview: got %q, want %q", got, want)
+	}
+
+	v.Dialog = &model.Dialog{Kind: model.DialogConfirm, Question: "Review", Tabs: []string{"Checks", "Confirm"}, ActiveTab: 1}
+	if got := waitingForPreview(v); got != "[Confirm 2/2] Review" {
+		t.Errorf("confirm preview: got %q", got)
+	}
+
+	// Codex numbers the questions of a multi-question overlay.
+	v.Dialog = &model.Dialog{Kind: model.DialogQuestion, Question: "Which test runner?",
+		Options: []model.DialogOption{{Label: "Jest", Selected: true}, {Label: "Vitest"}},
+		Tabs:    []string{"Q1", "Q2", "Q3"}, ActiveTab: 1}
+	if got := waitingForPreview(v); got != "[Q2 2/3] Which test runner? (❯Jest / Vitest)" {
+		t.Errorf("codex preview: got %q", got)
+	}
+
+	// Permission dialogs keep the WaitingFor detail (command or file).
+	v.WaitingFor = "Bash — npm test"
+	v.Dialog = &model.Dialog{Kind: model.DialogPermission, Question: "Do you want to proceed?", ActiveTab: -1}
+	if got := waitingForPreview(v); got != "Bash — npm test" {
+		t.Errorf("permission preview: got %q", got)
+	}
+}
+
+func TestWaitingForPreview_MouseAccountsForRowHeight(t *testing.T) {
+	m := previewTestModel()
+	// items: [sess-a=0, pane-a=1 (2 lines), sess-b=2, pane-b=3]
+	// list rows: 0=sess-a, 1-2=pane-a, 3=sess-b, 4=pane-b; Y = 1+row
+	msg := tea.MouseMsg{X: 5, Y: 5, Action: tea.MouseActionMotion}
+	_, _ = m.handleMouse(msg)
+	if m.cursor != 3 {
+		t.Errorf("expected cursor=3 (pane-b), got %d", m.cursor)
+	}
+
+	msg = tea.MouseMsg{X: 5, Y: 3, Action: tea.MouseActionMotion}
+	_, _ = m.handleMouse(msg)
+	if m.cursor != 1 {
+		t.Errorf("expected cursor=1 (preview line of pane-a), got %d", m.cursor)
+	}
+}
+
+func TestWaitingForPreview_ToggleAndRender(t *testing.T) {
+	m := previewTestModel()
+	if !strings.Contains(m.View(), "Edit src/main.go") {
+		t.Error("expected preview line in view")
+	}
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if m.showWaitingFor {
+		t.Fatal("expected w to toggle preview off")
+	}
+	if strings.Contains(m.View(), "Edit src/main.go") {
+		t.Error("expected no preview line after toggling off")
+	}
+}
+
+// --- Recommended action in rows ---
+
+func TestRecommendedSuffix_ToggleAndRender(t *testing.T) {
+	v := simpleVerdict()
+	v.Recommended = 1
+	m := newTestModel(v)
+	if got := m.recommendedSuffix(v); got != "" {
+		t.Errorf("expected no suffix while off, got %q", got)
+	}
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if !m.showRecommended {
+		t.Fatal("expected s to toggle recommended actions on")
+	}
+	if got := m.recommendedSuffix(v); got != " · [2] dismiss (low)" {
+		t.Errorf("suffix = %q", got)
+	}
+	v.Recommended = 0
+	if got := m.recommendedSuffix(v); got != " · [1] allow once (med)" {
+		t.Errorf("suffix = %q", got)
+	}
+
+	// The suffix survives a reason too long for the column.
+	m.verdicts[0].Reason = strings.Repeat("very long reason ", 20)
+	if !strings.Contains(m.View(), "[2] dismiss (low)") {
+		t.Errorf("expected the recommended action in the row:\n%s", m.View())
+	}
+	m.verdicts[0].Blocked = false
+	if got := m.recommendedSuffix(m.verdicts[0]); got != "" {
+		t.Errorf("expected no suffix for an unblocked pane, got %q", got)
+	}
+}
+
+func TestScrollWindow_FitsMultiLineRows(t *testing.T) {
+	m := previewTestModel()
+	m.cursor = 3
+	// Budget of 4 lines: pane-a takes 2, so sess-a..sess-b fill it and the
+	// window must scroll to keep pane-b visible.
+	start, end := m.scrollWindow(4)
+	if end != 4 {
+		t.Errorf("end: got %d, want 4", end)
+	}
+	lines := 0
+	for i := start; i < end; i++ {
+		lines += m.itemHeight(i)
+	}
+	if lines > 4 {
+		t.Errorf("window [%d,%d) uses %d lines, want <= 4", start, end, lines)
+	}
+}
+
+// --- Cluster sidebar ---
+
+func TestClusterSidebar_ToggleAndRender(t *testing.T) {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "a:0.0", Session: "a", Agent: "claude_code", Blocked: true, Reason: "idle at prompt"},
+			{Target: "b:0.0", Session: "b", Agent: "claude_code", Blocked: true, Reason: "idle at prompt"},
+		},
+		expanded:        map[string]bool{},
+		manualCollapsed: make(map[string]bool),
+		width:           140,
+		height:          40,
+	}
+	m.rebuildGroups()
+
+	_, _ = m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if !m.showClusters {
+		t.Fatal("expected c to enable the cluster sidebar")
+	}
+	view := m.View()
+	if !strings.Contains(view, "Blocked on") || !strings.Contains(view, "  2 idle at prompt") {
+		t.Errorf("expected cluster sidebar with count in view:\n%s", view)
+	}
+}
+
+func TestAnswerGroup_PickAndConfirm(t *testing.T) {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			approvalVerdict("a:0.0", "npm install"),
+			approvalVerdict("b:0.0", "npm install"),
+		},
+		expanded:        map[string]bool{"a": true, "b": true},
+		manualCollapsed: make(map[string]bool),
+		width:           120,
+		height:          40,
+	}
+	m.rebuildGroups()
+	m.cursor = 1 // pane a:0.0
+
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if m.answering == nil || len(m.answering.targets) != 2 {
+		t.Fatalf("expected answer group of 2, got %+v", m.answering)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Answer 2 panes") || !strings.Contains(view, "b:0.0") {
+		t.Errorf("expected comparison view listing members, got:\n%s", view)
+	}
+
+	// y before choosing an action does nothing.
+	if _, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd != nil {
+		t.Error("y without a chosen action must not send")
+	}
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	if m.answerChoice != 1 {
+		t.Fatalf("answerChoice: got %d, want 1", m.answerChoice)
+	}
+	if !strings.Contains(m.View(), "Send 'Escape' (cancel) to 2 panes?") {
+		t.Error("expected confirmation prompt")
+	}
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Error("expected broadcast command after confirmation")
+	}
+	if m.answering != nil {
+		t.Error("expected answer view to close after confirmation")
+	}
+}
+
+func TestAnswerGroup_SinglePaneRefused(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	_, _ = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if m.answering != nil {
+		t.Error("a dialog shown by one pane should not open the answer view")
+	}
+	if !strings.Contains(m.message, "No other pane") {
+		t.Errorf("message: got %q", m.message)
+	}
+}
+
+func TestAnswerGroup_EscCancels(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	g := answerGroup{label: "x", actions: simpleVerdict().Actions, targets: []string{"test:0.0", "other:0.0"}}
+	m.answering = &g
+	m.answerChoice = 0
+	_, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.answering != nil || cmd != nil {
+		t.Error("esc should close the answer view without sending")
+	}
+}
+
+func TestServerDown_BannerAndRetry(t *testing.T) {
+	m := newTestModel(simpleVerdict())
+	m.scanning = true
+	_, cmd := m.Update(scanResultMsg{err: fmt.Errorf("list panes: %w", mux.ErrNoServer)})
+	if cmd == nil {
+		t.Fatal("expected a retry to be scheduled while the server is down")
+	}
+	if m.serverDown != 1 || len(m.verdicts) != 0 {
+		t.Fatalf("serverDown = %d, verdicts = %d; want 1 and 0", m.serverDown, len(m.verdicts))
+	}
+	if view := m.viewVerdictList(); !strings.Contains(view, "tmux server not running") {
+		t.Errorf("expected server-down banner, got:\n%s", view)
+	}
+
+	m.scanning = true
+	_, _ = m.Update(scanResultMsg{result: &ScanResult{Verdicts: []model.Verdict{simpleVerdict()}}})
+	if m.serverDown != 0 || !strings.HasPrefix(m.message, "tmux server is back") {
+		t.Errorf("serverDown = %d, message = %q after recovery", m.serverDown, m.message)
+	}
+}
+
+func TestServerRetryDelay_Backoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 5: 16 * time.Second, 6: 30 * time.Second, 40: 30 * time.Second} {
+		if got := serverRetryDelay(attempt); got != want {
+			t.Errorf("serverRetryDelay(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func TestWidths_DoubleWidthRunes(t *testing.T) {
+	if got := visibleLen("\x1b[1m日本\x1b[0m go"); got != 7 {
+		t.Errorf("visibleLen: got %d, want 7", got)
+	}
+	if got := truncate("エージェント待ち", 9); got != "エージ..." {
+		t.Errorf("truncate: got %q", got)
+	}
+	if got := padRight("日本", 6); got != "日本  " {
+		t.Errorf("padRight: got %q", got)
+	}
+
+	// Reasons line up in one column whatever the width of the names.
+	m := newTestModel(blockedVerdict("api:0.0", "api", "permission dialog"))
+	m.verdicts = append(m.verdicts, blockedVerdict("日本語:0.0", "日本語", "question dialog"))
+	m.expanded["日本語"] = true
+	m.rebuildGroups()
+	columns := map[string]int{}
+	for _, line := range strings.Split(m.View(), "\n") {
+		for _, reason := range []string{"permission dialog", "question dialog"} {
+			if _, seen := columns[reason]; !seen && strings.Contains(line, reason) {
+				columns[reason] = visibleLen(line[:strings.Index(line, reason)])
+			}
+		}
+	}
+	if len(columns) != 2 || columns["permission dialog"] != columns["question dialog"] {
+		t.Errorf("reason columns: %v\n%s", columns, m.View())
+	}
+}
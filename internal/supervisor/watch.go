@@ -0,0 +1,158 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/mux"
+)
+
+// defaultWatchInterval is the scan interval of Watch when auto-refresh is
+// off: unlike the TUI, the watch mode has no key to rescan.
+const defaultWatchInterval = 5 * time.Second
+
+// Watch is the plain, line-oriented alternative to the TUI (--plain): it
+// scans every interval and writes one line per pane state transition, with
+// the state spelled out, for screen readers, dumb terminals, and logs. It
+// returns nil when ctx is done, cancelling the scan in progress, or once
+// stop is closed, after finishing the scan in progress and writing its
+// transitions. A nil stop never closes. With jsonLines, each transition is
+// written as a JSON object instead, as transition_command receives it.
+func Watch(ctx context.Context, stop <-chan struct{}, s *Scanner, w io.Writer, interval time.Duration, labels *Labels, jsonLines bool) error {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	wt := &watcher{scanner: s, out: w, labels: labels, jsonLines: jsonLines}
+	for {
+		wt.scan(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stop:
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watcher is the state of Watch between scans.
+type watcher struct {
+	scanner *Scanner
+	out     io.Writer
+	labels  *Labels
+	// jsonLines writes transitions and scan errors as JSON Lines.
+	jsonLines bool
+	store     VerdictStore
+	scans     int
+	lastErr   string // last scan error written, to write each one once
+}
+
+// scan runs one scan and writes its transitions.
+func (wt *watcher) scan(ctx context.Context, now time.Time) {
+	result, err := wt.scanner.Scan(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		msg := "scan error: " + err.Error()
+		if errors.Is(err, mux.ErrNoServer) {
+			msg = "tmux server not running; retrying"
+		}
+		if msg != wt.lastErr {
+			if wt.jsonLines {
+				wt.writeJSON(watchError{Kind: "scan_error", Time: now, Error: msg})
+			} else {
+				wt.printf(now, "%s", msg)
+			}
+			wt.lastErr = msg
+		}
+		return
+	}
+	ts := wt.store.Apply(result.Verdicts, now)
+	if wt.jsonLines {
+		wt.lastErr = ""
+		for _, t := range ts {
+			wt.writeJSON(t)
+		}
+		return
+	}
+	if wt.lastErr != "" {
+		wt.printf(now, "scanning again")
+		wt.lastErr = ""
+	}
+
+	if wt.scans == 0 {
+		agents, blocked := 0, 0
+		for _, v := range result.Verdicts {
+			if tracked(v) {
+				agents++
+				if v.Blocked {
+					blocked++
+				}
+			}
+		}
+		wt.printf(now, "watching %d agent panes, %d blocked", agents, blocked)
+	}
+	wt.scans++
+	for _, t := range ts {
+		wt.printf(now, "%s", plainTransition(t, wt.labels))
+	}
+}
+
+// watchError is a scan error in the JSON Lines output, written once until
+// a scan succeeds again.
+type watchError struct {
+	Kind  string    `json:"kind"`
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+func (wt *watcher) writeJSON(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	wt.out.Write(append(data, '\n'))
+}
+
+func (wt *watcher) printf(now time.Time, format string, args ...any) {
+	fmt.Fprintf(wt.out, "%s %s\n", now.Format("15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// plainTransition describes t in words, e.g. "BLOCKED api:0.1 (claude_code):
+// permission required. Actions: 1 allow once, low risk; 2 deny, low risk."
+func plainTransition(t Transition, labels *Labels) string {
+	v := t.Verdict
+	subject := t.Target
+	if label := labels.Pane(t.Target); label != "" {
+		subject += " " + label
+	}
+	subject += " (" + v.Agent + ")"
+	switch t.Kind {
+	case BecameActive:
+		return "ACTIVE " + subject
+	case Disappeared:
+		return "GONE " + subject
+	}
+	line := "BLOCKED " + subject
+	if reason := strings.Join(strings.Fields(v.Reason), " "); reason != "" {
+		line += ": " + reason
+	}
+	line += "."
+	if len(v.Actions) > 0 {
+		actions := make([]string, len(v.Actions))
+		for i, a := range v.Actions {
+			actions[i] = fmt.Sprintf("%d %s", i+1, a.Label)
+			if a.Risk != "" {
+				actions[i] += ", " + a.Risk + " risk"
+			}
+		}
+		line += " Actions: " + strings.Join(actions, "; ") + "."
+	}
+	return line
+}
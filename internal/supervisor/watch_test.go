@@ -0,0 +1,122 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+func TestWatcher_WritesTransitionsInWords(t *testing.T) {
+	tmux := &fakeTmux{}
+	p := tmux.addPane("api:0.0", "opencode", "idle", map[string]string{
+		"idle":    "\n  Previous conversation output...\n\n  > \n",
+		"working": opencodeWorking,
+	})
+	var out bytes.Buffer
+	wt := &watcher{scanner: &Scanner{Mux: tmux, Parsers: parser.NewRegistry(), Parallel: 1}, out: &out}
+	now := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+
+	wt.scan(context.Background(), now)
+	p.state = "working"
+	wt.scan(context.Background(), now)
+	wt.scan(context.Background(), now) // nothing changed
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), out.String())
+	}
+	if lines[0] != "09:30:00 watching 1 agent panes, 1 blocked" {
+		t.Errorf("line 1: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "09:30:00 BLOCKED api:0.0 (opencode): ") || !strings.Contains(lines[1], "Actions: 1 ") {
+		t.Errorf("line 2: %q", lines[1])
+	}
+	if lines[2] != "09:30:00 ACTIVE api:0.0 (opencode)" {
+		t.Errorf("line 3: %q", lines[2])
+	}
+}
+
+func TestWatcher_WritesTransitionsAsJSONLines(t *testing.T) {
+	tmux := &fakeTmux{}
+	p := tmux.addPane("api:0.0", "opencode", "idle", map[string]string{
+		"idle":    "\n  Previous conversation output...\n\n  > \n",
+		"working": opencodeWorking,
+	})
+	var out bytes.Buffer
+	wt := &watcher{scanner: &Scanner{Mux: tmux, Parsers: parser.NewRegistry(), Parallel: 1}, out: &out, jsonLines: true}
+	now := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+
+	wt.scan(context.Background(), now)
+	wt.scan(context.Background(), now) // nothing changed
+	p.state = "working"
+	wt.scan(context.Background(), now)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per change, got %d:\n%s", len(lines), out.String())
+	}
+	for i, want := range []TransitionKind{BecameBlocked, BecameActive} {
+		var got Transition
+		if err := json.Unmarshal([]byte(lines[i]), &got); err != nil || got.Kind != want || got.Target != "api:0.0" {
+			t.Errorf("line %d: %q (%v)", i+1, lines[i], err)
+		}
+	}
+
+	out.Reset()
+	wt.scanner.Mux = &mockMultiplexer{listErr: fmt.Errorf("list: %w", mux.ErrNoServer)}
+	wt.scan(context.Background(), now)
+	wt.scan(context.Background(), now)
+	if strings.Count(out.String(), `"kind":"scan_error"`) != 1 {
+		t.Errorf("expected the scan error once:\n%s", out.String())
+	}
+}
+
+func TestWatcher_ReportsServerDownOnce(t *testing.T) {
+	var out bytes.Buffer
+	scanner := &Scanner{Mux: &mockMultiplexer{listErr: fmt.Errorf("list: %w", mux.ErrNoServer)}, Parsers: parser.NewRegistry(), Parallel: 1}
+	wt := &watcher{scanner: scanner, out: &out}
+	now := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	wt.scan(context.Background(), now)
+	wt.scan(context.Background(), now)
+	if got := strings.Count(out.String(), "tmux server not running"); got != 1 {
+		t.Errorf("server down reported %d times:\n%s", got, out.String())
+	}
+}
+
+func TestWatch_StopFinishesScanInProgress(t *testing.T) {
+	tmux := &fakeTmux{}
+	tmux.addPane("api:0.0", "opencode", "idle", map[string]string{"idle": "\n  Previous conversation output...\n\n  > \n"})
+	scanner := &Scanner{Mux: tmux, Parsers: parser.NewRegistry(), Parallel: 1}
+	stop := make(chan struct{})
+	close(stop) // e.g. SIGTERM with --drain, before the first scan is done
+
+	var out bytes.Buffer
+	done := make(chan error)
+	go func() { done <- Watch(context.Background(), stop, scanner, &out, time.Hour, nil, false) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after stop was closed")
+	}
+	if !strings.Contains(out.String(), "BLOCKED api:0.0") {
+		t.Errorf("expected the scan in progress to be written:\n%s", out.String())
+	}
+}
+
+func TestPlainTransition_Gone(t *testing.T) {
+	tr := Transition{Kind: Disappeared, Target: "api:0.0", Verdict: model.Verdict{Agent: "codex"}}
+	if got := plainTransition(tr, nil); got != "GONE api:0.0 (codex)" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,136 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timvw/pane-patrol/internal/parser"
+)
+
+// windowGroup holds the verdicts of one window of a session, listed under
+// its own header when the session's panes span several windows.
+type windowGroup struct {
+	window   int
+	name     string
+	verdicts []int // indices into the flat verdicts slice
+	blocked  int
+	active   int
+}
+
+// windowKey is a window's key in expanded and manualCollapsed. tmux does
+// not allow ":" in session names, so it never collides with a session.
+func windowKey(session string, window int) string {
+	return fmt.Sprintf("%s:%d", session, window)
+}
+
+// expandKey returns the key of a session or window header in expanded and
+// manualCollapsed.
+func (it listItem) expandKey() string {
+	if it.kind == itemWindow {
+		return windowKey(it.session, it.window)
+	}
+	return it.session
+}
+
+// splitWindows groups g's verdicts by window when grouping by session and
+// they span more than one window; a single window adds no level.
+func (m *tuiModel) splitWindows(g *sessionGroup) {
+	g.windows = nil
+	if m.groupBy != groupBySession {
+		return
+	}
+	seen := map[int]int{} // window index -> index in g.windows
+	for _, vi := range g.verdicts {
+		v := m.verdicts[vi]
+		idx, ok := seen[v.Window]
+		if !ok {
+			idx = len(g.windows)
+			seen[v.Window] = idx
+			g.windows = append(g.windows, windowGroup{window: v.Window, name: v.WindowName})
+		}
+		w := &g.windows[idx]
+		w.verdicts = append(w.verdicts, vi)
+		if parser.IsAgentBlock(v) {
+			w.blocked++
+		}
+		if v.Agent != "error" && v.Agent != "not_an_agent" && !v.Blocked {
+			w.active++
+		}
+	}
+	if len(g.windows) < 2 {
+		g.windows = nil
+	}
+}
+
+// findWindow returns the window group of a window header item, or nil.
+func (m *tuiModel) findWindow(item listItem) *windowGroup {
+	for gi := range m.groups {
+		if m.groups[gi].name != item.session {
+			continue
+		}
+		for wi := range m.groups[gi].windows {
+			if w := &m.groups[gi].windows[wi]; w.window == item.window {
+				return w
+			}
+		}
+	}
+	return nil
+}
+
+// windowDisplayName returns a window header's name, e.g. ":1 editor".
+func windowDisplayName(w windowGroup) string {
+	if w.name == "" {
+		return fmt.Sprintf(":%d", w.window)
+	}
+	return fmt.Sprintf(":%d %s", w.window, w.name)
+}
+
+// groupSummary describes a session or window in the reason column, e.g.
+// "3 panes, 1 blocked".
+func groupSummary(panes, blocked int) string {
+	s := fmt.Sprintf("%d pane", panes)
+	if panes != 1 {
+		s += "s"
+	}
+	if blocked > 0 {
+		s += fmt.Sprintf(", %d blocked", blocked)
+	}
+	return s
+}
+
+func (m *tuiModel) renderWindowRow(item listItem, idx, nameWidth, reasonWidth int) (string, string) {
+	w := m.findWindow(item)
+	if w == nil {
+		return padRight("", nameWidth), padRight("", reasonWidth)
+	}
+
+	g := m.glyphs()
+	iconText, icon := g.idle, m.s.dim.Render(g.idle)
+	switch {
+	case w.blocked > 0:
+		iconText, icon = g.blocked, m.s.blocked.Render(g.blocked)
+	case w.active > 0:
+		iconText, icon = g.active, m.s.active.Render(g.active)
+	}
+	arrow := g.collapsed
+	if m.expanded[item.expandKey()] {
+		arrow = g.expanded
+	}
+	reason := groupSummary(len(w.verdicts), w.blocked)
+
+	if idx == m.cursor {
+		return m.s.selected.Render(padRight(fmt.Sprintf("%4s%s %s %s", g.pointer+" ", arrow, iconText, windowDisplayName(*w)), nameWidth)),
+			m.s.selected.Render(padRight(reason, reasonWidth))
+	}
+	return padRight(fmt.Sprintf("    %s %s %s", arrow, icon, windowDisplayName(*w)), nameWidth),
+		m.s.dim.Render(padRight(reason, reasonWidth))
+}
+
+// paneIndent returns the indent of a pane row: panes listed under a window
+// header are indented one level further.
+func paneIndent(item listItem) string {
+	if item.inWindow {
+		return strings.Repeat(" ", 8)
+	}
+	return strings.Repeat(" ", 6)
+}
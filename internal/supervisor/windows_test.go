@@ -0,0 +1,130 @@
+package supervisor
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/timvw/pane-patrol/internal/model"
+)
+
+// windowsTestModel returns a model with session "dev" spread over two
+// windows (window 1 with a blocked pane) and single-window session "ops".
+func windowsTestModel() *tuiModel {
+	m := &tuiModel{
+		verdicts: []model.Verdict{
+			{Target: "dev:0.0", Session: "dev", Window: 0, WindowName: "editor", Agent: "opencode"},
+			{Target: "dev:0.1", Session: "dev", Window: 0, Pane: 1, WindowName: "editor", Agent: "opencode"},
+			{Target: "dev:1.0", Session: "dev", Window: 1, WindowName: "tests", Agent: "opencode", Blocked: true},
+			{Target: "ops:0.0", Session: "ops", Agent: "opencode", Blocked: true},
+			{Target: "ops:0.1", Session: "ops", Pane: 1, Agent: "opencode"},
+		},
+		filter:          filterAgents,
+		expanded:        map[string]bool{},
+		manualCollapsed: make(map[string]bool),
+		width:           120,
+		height:          40,
+	}
+	m.rebuildGroups()
+	return m
+}
+
+// itemKeys lists the items as keys (see itemKey).
+func itemKeys(m *tuiModel) []string {
+	var keys []string
+	for _, item := range m.items {
+		keys = append(keys, m.itemKey(item))
+	}
+	return keys
+}
+
+func TestWindows_GroupedUnderSessions(t *testing.T) {
+	m := windowsTestModel()
+	want := []string{
+		"session:dev", "window:dev:0", "dev:0.0", "dev:0.1", "window:dev:1", "dev:1.0",
+		"session:ops", "ops:0.0", "ops:0.1",
+	}
+	if got := itemKeys(m); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+
+	// A session whose listed panes are all in one window has no window level.
+	m.filter = filterBlocked
+	m.rebuildGroups()
+	want = []string{"session:dev", "dev:1.0", "session:ops", "ops:0.0"}
+	if got := itemKeys(m); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+
+	// Grouping by directory lists panes without windows.
+	m.groupBy = groupByDirectory
+	m.rebuildGroups()
+	for _, item := range m.items {
+		if item.kind == itemWindow {
+			t.Fatalf("unexpected window header when grouping by directory: %+v", item)
+		}
+	}
+}
+
+func TestWindows_CollapseAndNavigate(t *testing.T) {
+	m := windowsTestModel()
+	m.restoreCursorByKey("dev:0.1")
+
+	// Left goes to the window header, then collapses it.
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyLeft})
+	if got := m.selectedItemKey(); got != "window:dev:0" {
+		t.Fatalf("cursor on %s, want the window header", got)
+	}
+	if v := m.selectedVerdict(); v == nil || v.Target != "dev:0.0" {
+		t.Errorf("selected verdict = %+v, want the window's first pane", v)
+	}
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyLeft})
+	if m.expanded["dev:0"] || !m.manualCollapsed["dev:0"] {
+		t.Fatal("expected the window to be collapsed")
+	}
+	if slices.Contains(itemKeys(m), "dev:0.0") {
+		t.Error("expected the collapsed window's panes to be hidden")
+	}
+	// A manual collapse survives the next scan.
+	m.rebuildGroups()
+	if m.expanded["dev:0"] {
+		t.Error("expected the manual collapse to be kept")
+	}
+
+	// Up and down stop on the collapsed window but skip expanded headers.
+	m.restoreCursorByKey("dev:1.0")
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.selectedItemKey(); got != "window:dev:0" {
+		t.Errorf("up moved to %s, want the collapsed window", got)
+	}
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.selectedItemKey(); got != "dev:1.0" {
+		t.Errorf("down moved to %s, want dev:1.0", got)
+	}
+
+	// Right expands the window again and moves to its first pane.
+	m.restoreCursorByKey("window:dev:0")
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyRight})
+	if got := m.selectedItemKey(); !m.expanded["dev:0"] || got != "dev:0.0" {
+		t.Errorf("expanded=%v cursor on %s", m.expanded["dev:0"], got)
+	}
+
+	// Left from a collapsed window goes to its session header.
+	m.restoreCursorByKey("window:dev:1")
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyLeft})
+	m.handleVerdictListKey(tea.KeyMsg{Type: tea.KeyLeft})
+	if got := m.selectedItemKey(); got != "session:dev" {
+		t.Errorf("cursor on %s, want the session header", got)
+	}
+}
+
+func TestWindows_Render(t *testing.T) {
+	m := windowsTestModel()
+	view := m.View()
+	for _, want := range []string{":0 editor", "2 panes", ":1 tests", "1 pane, 1 blocked"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the list:\n%s", want, view)
+		}
+	}
+}
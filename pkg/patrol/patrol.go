@@ -0,0 +1,169 @@
+// Package patrol is pane-patrol as a library: scan terminal multiplexer
+// panes for AI coding agents, classify each one with the deterministic
+// parsers (blocked on a dialog, idle, working), and send the keys that
+// answer it.
+//
+// It is the stable API of pane-patrol: within a major version, the names in
+// this package are only added to, never changed or removed. The types it
+// re-exports, such as Verdict and Action, follow the same rule for their
+// existing fields. Everything under internal/ is free to change in any
+// release; the pane-patrol CLI and supervisor TUI are built on the same
+// internals.
+//
+// A minimal orchestration loop:
+//
+//	scanner := &patrol.Scanner{Mux: patrol.NewTmux(), Registry: patrol.NewRegistry()}
+//	verdicts, err := scanner.Scan(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	nudger := patrol.NewNudger()
+//	for _, v := range verdicts {
+//		// Recommended is -1, or 0 with no Actions, when there is nothing to send.
+//		if !v.Blocked || v.Recommended < 0 || v.Recommended >= len(v.Actions) {
+//			continue
+//		}
+//		if a := v.Actions[v.Recommended]; a.Risk == "low" {
+//			err = errors.Join(err, nudger.Send(v.Target, a))
+//		}
+//	}
+package patrol
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/timvw/pane-patrol/internal/model"
+	"github.com/timvw/pane-patrol/internal/mux"
+	"github.com/timvw/pane-patrol/internal/parser"
+	"github.com/timvw/pane-patrol/internal/supervisor"
+)
+
+// Verdicts and what they contain.
+type (
+	// Pane is a multiplexer pane, as listed by a Multiplexer.
+	Pane = model.Pane
+	// Verdict is the classification of one pane.
+	Verdict = model.Verdict
+	// Action is a way to unblock a pane: the keys to send and their risk.
+	Action = model.Action
+	// Dialog is the structured form of the dialog a pane is blocked on.
+	Dialog = model.Dialog
+	// DialogOption is one option of a Dialog.
+	DialogOption = model.DialogOption
+	// SubagentInfo is a subagent task visible in a pane.
+	SubagentInfo = model.SubagentInfo
+)
+
+// How a Verdict was produced (Verdict.EvalSource).
+const (
+	EvalSourceParser = model.EvalSourceParser
+	EvalSourceCache  = model.EvalSourceCache
+	EvalSourceError  = model.EvalSourceError
+	EvalSourceEvent  = model.EvalSourceEvent
+)
+
+// Multiplexer lists and captures panes. Implement it to scan something
+// other than tmux.
+type Multiplexer = mux.Multiplexer
+
+// NewTmux returns the tmux multiplexer.
+func NewTmux() Multiplexer {
+	return mux.NewTmux()
+}
+
+// Parsers.
+type (
+	// Registry runs the agent parsers over a pane's content and keeps the
+	// result with the strongest evidence.
+	Registry = parser.Registry
+	// AgentParser recognizes one agent's TUI. Implement it to add an agent.
+	AgentParser = parser.AgentParser
+	// ParseResult is what an AgentParser reports for a pane it recognizes.
+	ParseResult = parser.Result
+)
+
+// Evidence levels for ParseResult.Confidence, strongest first.
+const (
+	ConfidenceProcess = parser.ConfidenceProcess
+	ConfidenceMarker  = parser.ConfidenceMarker
+	ConfidenceShared  = parser.ConfidenceShared
+	ConfidenceGeneric = parser.ConfidenceGeneric
+)
+
+// NewRegistry returns a registry with the builtin parsers (OpenCode, Codex,
+// Claude Code), then custom, then the generic prompt parser.
+func NewRegistry(custom ...AgentParser) *Registry {
+	return parser.NewRegistry(custom...)
+}
+
+// Cache reuses the verdicts of panes whose content has not changed since
+// they were evaluated. Implement it to share verdicts between processes.
+type Cache = supervisor.Cache
+
+// NewCache returns an in-memory cache whose verdicts expire after ttl.
+func NewCache(ttl time.Duration) Cache {
+	return supervisor.NewVerdictCache(ttl)
+}
+
+// Scanner lists the panes of a multiplexer and classifies each one. Only
+// Mux is required.
+type Scanner struct {
+	Mux             Multiplexer
+	Registry        *Registry    // nil uses NewRegistry()
+	Filter          string       // regular expression on session names; "" scans all sessions
+	ExcludeSessions []string     // session names or globs to skip
+	Parallel        int          // panes evaluated at once; 0 uses 10
+	Cache           Cache        // nil evaluates every pane on every scan
+	Log             *slog.Logger // debug log of scans and parser decisions; nil discards
+}
+
+// Scan returns the verdict of every pane, sorted by session, window, and
+// pane.
+func (s *Scanner) Scan(ctx context.Context) ([]Verdict, error) {
+	registry := s.Registry
+	if registry == nil {
+		registry = NewRegistry()
+	}
+	parallel := s.Parallel
+	if parallel <= 0 {
+		parallel = 10
+	}
+	scanner := &supervisor.Scanner{
+		Mux:             s.Mux,
+		Parsers:         registry,
+		Filter:          s.Filter,
+		ExcludeSessions: s.ExcludeSessions,
+		Parallel:        parallel,
+		Cache:           s.Cache,
+		Log:             s.Log,
+	}
+	result, err := scanner.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return result.Verdicts, nil
+}
+
+// Nudger sends keys and text to tmux panes the way the supervisor does:
+// raw keypresses for agents' TUIs, literal text followed by Enter for
+// prompts, and multi-line text through a paste buffer.
+type Nudger struct {
+	n *supervisor.Nudger
+}
+
+// NewNudger returns a nudger for tmux.
+func NewNudger() *Nudger {
+	return &Nudger{n: supervisor.DefaultNudger()}
+}
+
+// Send sends a's keys to the pane at target.
+func (n *Nudger) Send(target string, a Action) error {
+	return n.n.NudgePane(target, a.Keys, a.Raw)
+}
+
+// SendText types text at the pane's prompt and submits it.
+func (n *Nudger) SendText(target, text string) error {
+	return n.n.NudgePane(target, text, false)
+}
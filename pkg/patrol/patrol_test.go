@@ -0,0 +1,74 @@
+package patrol_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timvw/pane-patrol/pkg/patrol"
+)
+
+// fakeMux serves fixed pane captures, as a library user's own multiplexer
+// would.
+type fakeMux struct {
+	panes    []patrol.Pane
+	captures map[string]string
+}
+
+func (f *fakeMux) Name() string { return "fake" }
+
+func (f *fakeMux) ListPanes(context.Context, string) ([]patrol.Pane, error) {
+	return f.panes, nil
+}
+
+func (f *fakeMux) CapturePane(_ context.Context, target string) (string, error) {
+	return f.captures[target], nil
+}
+
+// deployBot recognizes a custom agent.
+type deployBot struct{}
+
+func (deployBot) Name() string { return "deploybot" }
+
+func (deployBot) Parse(content string, _ []string) *patrol.ParseResult {
+	if !strings.Contains(content, "deploy to production?") {
+		return nil
+	}
+	return &patrol.ParseResult{Agent: "deploybot", Blocked: true, Reason: "deploy confirmation",
+		Actions: []patrol.Action{{Keys: "n", Label: "cancel", Risk: "low"}}, Confidence: patrol.ConfidenceMarker}
+}
+
+func TestScanner_ScansWithTheBuiltinAndCustomParsers(t *testing.T) {
+	mux := &fakeMux{
+		panes: []patrol.Pane{
+			{Target: "dev:0.0", Session: "dev", Command: "claude", ProcessTree: []string{"claude"}},
+			{Target: "ops:0.0", Session: "ops", Command: "bash"},
+		},
+		captures: map[string]string{
+			"dev:0.0": "  Claude needs your permission to use Read\n\n  Read file: /etc/hosts\n\n  Do you want to proceed?\n  ❯ 1. Yes  2. No\n",
+			"ops:0.0": "deploy to production? [y/N]",
+		},
+	}
+	scanner := &patrol.Scanner{Mux: mux, Registry: patrol.NewRegistry(deployBot{}), Cache: patrol.NewCache(time.Minute)}
+
+	verdicts, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verdicts) != 2 {
+		t.Fatalf("got %d verdicts", len(verdicts))
+	}
+	claude, bot := verdicts[0], verdicts[1]
+	if claude.Agent != "claude_code" || !claude.Blocked || len(claude.Actions) == 0 || claude.EvalSource != patrol.EvalSourceParser {
+		t.Errorf("claude pane: %+v", claude)
+	}
+	if bot.Agent != "deploybot" || bot.Reason != "deploy confirmation" {
+		t.Errorf("custom parser: %+v", bot)
+	}
+
+	verdicts, err = scanner.Scan(context.Background())
+	if err != nil || verdicts[0].EvalSource != patrol.EvalSourceCache {
+		t.Errorf("unchanged panes should come from the cache: %+v (%v)", verdicts, err)
+	}
+}